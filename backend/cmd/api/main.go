@@ -18,6 +18,7 @@ import (
 	"github.com/s7r8/reviewapp/internal/di"
 	"github.com/s7r8/reviewapp/internal/infrastructure/auth"
 	"github.com/s7r8/reviewapp/internal/infrastructure/config"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
 	"github.com/s7r8/reviewapp/internal/infrastructure/persistence/postgres"
 	"github.com/s7r8/reviewapp/internal/interfaces/http/handler"
 	httpmiddleware "github.com/s7r8/reviewapp/internal/interfaces/http/middleware"
@@ -39,13 +40,21 @@ func main() {
 	fmt.Printf("✅ Auth0 configured (domain: %s)\n", cfg.Auth.Domain)
 
 	// デバッグ: APIキーの確認
-	if cfg.LLM.ClaudeAPIKey == "" {
-		log.Println("⚠️  WARNING: CLAUDE_API_KEY is not set!")
-	} else {
-		log.Printf("✅ Claude API Key loaded (length: %d)\n", len(cfg.LLM.ClaudeAPIKey))
+	fmt.Printf("✅ LLM provider: %s\n", cfg.LLM.Provider)
+	switch cfg.LLM.Provider {
+	case "anthropic", "claude":
+		if cfg.LLM.ClaudeAPIKey == "" {
+			log.Println("⚠️  WARNING: CLAUDE_API_KEY is not set!")
+		} else {
+			log.Printf("✅ Claude API Key loaded (length: %d)\n", len(cfg.LLM.ClaudeAPIKey))
+		}
+	case "ollama", "openai_compatible":
+		log.Printf("✅ Local LLM endpoint: %s (model: %s)\n", cfg.LLM.LocalBaseURL, cfg.LLM.LocalModel)
 	}
 
-	if cfg.LLM.OpenAIAPIKey == "" {
+	if cfg.Embedding.Provider == "hashing" {
+		log.Printf("✅ Embedding provider: hashing (offline, %d dimensions)\n", cfg.LLM.EmbeddingDim)
+	} else if cfg.LLM.OpenAIAPIKey == "" {
 		log.Println("⚠️  WARNING: OPENAI_API_KEY is not set!")
 	} else {
 		log.Printf("✅ OpenAI API Key loaded (length: %d, starts with: %s)\n",
@@ -85,33 +94,129 @@ func main() {
 	fmt.Println("✅ Auth middleware initialized")
 
 	// 5. Wire で依存関係を自動解決
-	knowledgeHandler, err := di.InitializeKnowledgeHandler(db.DB, cfg)
+	// 外部API（LLM / Embedding）のサーキットブレーカーはプロバイダ単位で全ハンドラーに共有する
+	breakers := external.NewBreakerRegistry(cfg.LLM.BreakerFailureThreshold, cfg.LLM.BreakerOpenTimeout)
+	if cfg.LLM.CassetteMode != string(external.CassetteModeOff) {
+		fmt.Printf("⚠️  LLM cassette mode: %s (dir: %s)\n", cfg.LLM.CassetteMode, cfg.LLM.CassetteDir)
+	}
+
+	// Embeddingのキャッシュもナレッジ登録とレビューで共有する
+	embeddingCache, err := di.ProvideEmbeddingCache(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize embedding cache: %v", err)
+	}
+	fmt.Printf("✅ Embedding cache: %s (batch window: %s, batch size: %d)\n",
+		cfg.Embedding.Cache, cfg.Embedding.BatchWindow, cfg.Embedding.BatchSize)
+
+	knowledgeHandler, err := di.InitializeKnowledgeHandler(db.DB, cfg, breakers, embeddingCache)
 	if err != nil {
 		log.Fatalf("Failed to initialize knowledge handler: %v", err)
 	}
 
-	reviewHandler, err := di.InitializeReviewHandler(db.DB, cfg)
+	// 非同期のレビューのワーカー（ジョブはDBに保存するため、停止前に受け付けたジョブも起動後に実行する）
+	reviewJobUsecase, err := di.InitializeReviewJobUseCase(db.DB, cfg, breakers, embeddingCache)
+	if err != nil {
+		log.Fatalf("Failed to initialize review job usecase: %v", err)
+	}
+	reviewJobUsecase.Start()
+	fmt.Printf("✅ Review jobs: %d workers, timeout %s\n", cfg.ReviewJob.Workers, cfg.ReviewJob.Timeout)
+
+	reviewHandler, err := di.InitializeReviewHandler(db.DB, cfg, breakers, embeddingCache, reviewJobUsecase)
 	if err != nil {
 		log.Fatalf("Failed to initialize review handler: %v", err)
 	}
 
+	reviewEnsembleHandler, err := di.InitializeReviewEnsembleHandler(db.DB, cfg, breakers, embeddingCache)
+	if err != nil {
+		log.Fatalf("Failed to initialize review ensemble handler: %v", err)
+	}
+	fmt.Printf("✅ Ensemble models: %v\n", cfg.LLM.EnsembleModels)
+
+	reviewSessionHandler, err := di.InitializeReviewSessionHandler(db.DB, cfg, breakers, embeddingCache)
+	if err != nil {
+		log.Fatalf("Failed to initialize review session handler: %v", err)
+	}
+	fmt.Printf("✅ Review session: max %d files, concurrency %d\n", cfg.ReviewSession.MaxFiles, cfg.ReviewSession.Concurrency)
+
+	reviewConversationHandler, err := di.InitializeReviewConversationHandler(db.DB, cfg, breakers, embeddingCache)
+	if err != nil {
+		log.Fatalf("Failed to initialize review conversation handler: %v", err)
+	}
+	fmt.Printf("✅ Review conversation: enabled=%t, max %d messages\n", cfg.Features.ConversationMode, cfg.Conversation.MaxMessages)
+
+	reviewRerunHandler, err := di.InitializeReviewRerunHandler(db.DB, cfg, breakers, embeddingCache, reviewJobUsecase)
+	if err != nil {
+		log.Fatalf("Failed to initialize review rerun handler: %v", err)
+	}
+
+	reviewApplyHandler, err := di.InitializeReviewApplyHandler(db.DB, cfg, breakers, embeddingCache, reviewJobUsecase)
+	if err != nil {
+		log.Fatalf("Failed to initialize review apply handler: %v", err)
+	}
+
+	reviewTrashHandler, err := di.InitializeReviewTrashHandler(db.DB, cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize review trash handler: %v", err)
+	}
+
+	// 保持期間を過ぎたゴミ箱のレビューの完全削除（REVIEW_PURGE_INTERVAL=0 の場合は行わない）
+	reviewPurgeUsecase, err := di.InitializeReviewPurgeUseCase(db.DB, cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize review purge usecase: %v", err)
+	}
+	reviewPurgeUsecase.Start()
+	fmt.Printf("✅ Review trash: retention %s, purge interval %s\n", cfg.Trash.Retention, cfg.Trash.PurgeInterval)
+
 	dashboardHandler, err := di.InitializeDashboardHandler(db.DB)
 	if err != nil {
 		log.Fatalf("Failed to initialize dashboard handler: %v", err)
 	}
 
+	promptTemplateHandler, err := di.InitializePromptTemplateHandler(db.DB)
+	if err != nil {
+		log.Fatalf("Failed to initialize prompt template handler: %v", err)
+	}
+
+	usageHandler, err := di.InitializeUsageHandler(db.DB)
+	if err != nil {
+		log.Fatalf("Failed to initialize usage handler: %v", err)
+	}
+
+	quotaHandler, err := di.InitializeQuotaHandler(db.DB, cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize quota handler: %v", err)
+	}
+	fmt.Printf("✅ Quota: %d reviews/day, %d tokens/month (0 = unlimited, redis: %t)\n",
+		cfg.Quota.ReviewsPerDay, cfg.Quota.TokensPerMonth, cfg.Quota.UseRedis)
+
+	userPreferencesHandler, err := di.InitializeUserPreferencesHandler(db.DB, cfg, breakers)
+	if err != nil {
+		log.Fatalf("Failed to initialize user preferences handler: %v", err)
+	}
+
+	// 管理者API（ADMIN_AUTH0_SUBS に登録されたユーザーのみ）
+	adminMiddleware := httpmiddleware.NewAdminMiddleware(cfg.Auth.AdminSubs)
+	if len(cfg.Auth.AdminSubs) == 0 {
+		log.Println("⚠️  WARNING: ADMIN_AUTH0_SUBS is not set! Admin endpoints are disabled.")
+	}
+
 	// 6. Echoサーバー初期化
 	e := echo.New()
 
+	// エラーメッセージをリクエストのロケール（Accept-Language・ユーザー設定・リクエストの locale）の言語で返す
+	e.JSONSerializer = httpmiddleware.LocalizedJSONSerializer{}
+
 	// グローバルミドルウェア
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
+	e.Use(httpmiddleware.Locale)
 
 	// CORS設定（開発環境用）
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"http://localhost:5173", "http://localhost:3000"},
-		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		AllowOrigins:  []string{"http://localhost:5173", "http://localhost:3000"},
+		AllowMethods:  []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
+		AllowHeaders:  []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		ExposeHeaders: []string{echo.HeaderRetryAfter},
 	}))
 
 	// ヘルスチェック（認証不要）
@@ -121,16 +226,18 @@ func main() {
 		defer cancel()
 
 		if err := db.HealthCheck(ctx); err != nil {
-			return c.JSON(http.StatusServiceUnavailable, map[string]string{
-				"status":   "error",
-				"database": "disconnected",
+			return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{
+				"status":           "error",
+				"database":         "disconnected",
+				"circuit_breakers": breakers.Statuses(),
 			})
 		}
 
-		return c.JSON(http.StatusOK, map[string]string{
-			"status":   "ok",
-			"service":  "ReviewApp API",
-			"database": "connected",
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"status":           "ok",
+			"service":          "ReviewApp API",
+			"database":         "connected",
+			"circuit_breakers": breakers.Statuses(),
 		})
 	})
 
@@ -172,6 +279,10 @@ func main() {
 	// ユーザー同期エンドポイント（初回ログイン時に呼ばれる）
 	protected.POST("/auth/sync", authHandler.SyncUser)
 
+	// ユーザー設定エンドポイント（認証必須）
+	protected.GET("/users/me/preferences", userPreferencesHandler.GetPreferences)    // US-002: レビュー設定取得
+	protected.PUT("/users/me/preferences", userPreferencesHandler.UpdatePreferences) // US-003: レビュー設定更新
+
 	// ナレッジエンドポイント（認証必須）
 	protected.POST("/knowledge", knowledgeHandler.CreateKnowledge)       // KN-001: ナレッジ作成
 	protected.GET("/knowledge", knowledgeHandler.ListKnowledge)          // KN-002: ナレッジ一覧取得
@@ -183,10 +294,55 @@ func main() {
 	protected.GET("/reviews", reviewHandler.ListReviews)                 // RV-002: レビュー履歴一覧取得
 	protected.GET("/reviews/:id", reviewHandler.GetReviewByID)           // RV-003: レビュー詳細取得 ★ 追加
 	protected.PUT("/reviews/:id/feedback", reviewHandler.UpdateFeedback) // RV-004: フィードバック更新
+	protected.POST("/reviews/stream", reviewHandler.ReviewCodeStream)    // RV-005: コードレビュー実行（ストリーミング）
+	protected.POST("/reviews/:id/retry", reviewHandler.RetryReview)      // RV-015: 失敗した非同期のレビューの再実行
+
+	// 複数モデルによるレビューエンドポイント（認証必須）
+	protected.POST("/reviews/ensemble", reviewEnsembleHandler.ReviewEnsemble)           // RV-006: 複数モデルでレビュー実行
+	protected.GET("/reviews/ensembles/:id", reviewEnsembleHandler.GetEnsemble)          // RV-007: 複数モデルのレビュー取得
+	protected.GET("/reviews/ensemble/models", reviewEnsembleHandler.ListEnsembleModels) // RV-008: 比較に使えるモデル一覧
+
+	// 複数ファイルのレビューエンドポイント（認証必須）
+	protected.POST("/reviews/sessions", reviewSessionHandler.ReviewSession)       // RV-009: 複数ファイルのレビュー実行
+	protected.GET("/reviews/sessions/:id", reviewSessionHandler.GetReviewSession) // RV-010: セッションのレビュー取得
+	protected.GET("/reviews/sessions", reviewSessionHandler.ListReviewSessions)   // RV-011: セッション履歴一覧取得
+
+	// レビューについての会話エンドポイント（認証必須。FEATURE_CONVERSATION_MODE=false の場合は403）
+	protected.POST("/reviews/:id/messages", reviewConversationHandler.SendReviewMessage)                                // RV-012: レビューについてのメッセージ送信
+	protected.GET("/reviews/:id/messages", reviewConversationHandler.ListReviewMessages)                                // RV-013: レビューの会話取得
+	protected.POST("/reviews/:id/messages/:message_id/knowledge", reviewConversationHandler.CreateKnowledgeFromMessage) // RV-014: 会話からナレッジ作成
+
+	// 再レビューと結果の比較エンドポイント（認証必須）
+	protected.POST("/reviews/:id/rerun", reviewRerunHandler.RerunReview)        // RV-016: 現在のナレッジ・プロンプト・モデルで再レビュー
+	protected.GET("/reviews/:id/diff/:otherId", reviewRerunHandler.DiffReviews) // RV-017: 2つのレビューの改善点の比較
+
+	// 改善点の適用エンドポイント（認証必須）
+	protected.POST("/reviews/:id/apply", reviewApplyHandler.ApplyImprovements) // RV-022: 選択した改善点を適用したコードと unified diff
+
+	// レビューの削除・ゴミ箱エンドポイント（認証必須）
+	protected.DELETE("/reviews/:id", reviewTrashHandler.DeleteReview)        // RV-018: レビューの削除（ゴミ箱に移動）
+	protected.DELETE("/reviews", reviewTrashHandler.BulkDeleteReviews)       // RV-019: 条件に合致するレビューの一括削除
+	protected.GET("/reviews/trash", reviewTrashHandler.ListTrash)            // RV-020: ゴミ箱のレビュー一覧
+	protected.POST("/reviews/:id/restore", reviewTrashHandler.RestoreReview) // RV-021: ゴミ箱のレビューの復元
 
 	// ダッシュボードエンドポイント（認証必須）
 	protected.GET("/dashboard/stats", dashboardHandler.GetStats) // DS-001: ダッシュボード統計取得
 
+	// 利用上限エンドポイント（認証必須）
+	protected.GET("/quota", quotaHandler.GetMyQuota) // QT-001: 自分の利用上限と残りを取得
+
+	// 使用量・コスト集計エンドポイント（認証 + 管理者権限必須。全ユーザー分を集計するため）
+	protected.GET("/usage", usageHandler.GetUsage, adminMiddleware.RequireAdmin) // UG-001: 使用量・コスト集計
+
+	// 管理者エンドポイント（認証 + 管理者権限必須）
+	admin := protected.Group("/admin", adminMiddleware.RequireAdmin)
+	admin.POST("/prompt-templates", promptTemplateHandler.CreatePromptTemplate)                // PT-001: プロンプトテンプレート作成
+	admin.GET("/prompt-templates", promptTemplateHandler.ListPromptTemplates)                  // PT-002: プロンプトテンプレート一覧取得
+	admin.POST("/prompt-templates/preview", promptTemplateHandler.PreviewPromptTemplate)       // PT-003: プロンプトテンプレートのプレビュー
+	admin.POST("/prompt-templates/:id/activate", promptTemplateHandler.ActivatePromptTemplate) // PT-004: プロンプトテンプレートの有効化
+	admin.GET("/quotas/:user_id", quotaHandler.GetUserQuota)                                   // QT-002: ユーザーの利用上限と残りを取得
+	admin.PUT("/quotas/:user_id", quotaHandler.UpdateUserQuota)                                // QT-003: ユーザー個別の利用上限を設定
+
 	// 8. サーバー起動（グレースフルシャットダウン対応）
 	go func() {
 		addr := "127.0.0.1:" + cfg.Server.Port
@@ -215,5 +371,14 @@ func main() {
 		e.Logger.Fatal(err)
 	}
 
+	// 実行中のレビューの完了を待つ（期限を過ぎたレビューは中断して実行待ちに戻す）
+	if err := reviewJobUsecase.Shutdown(ctx); err != nil {
+		fmt.Printf("⚠️  Review jobs interrupted: %v\n", err)
+	}
+
+	if err := reviewPurgeUsecase.Shutdown(ctx); err != nil {
+		fmt.Printf("⚠️  Review purge interrupted: %v\n", err)
+	}
+
 	fmt.Println("✅ Server stopped gracefully")
 }
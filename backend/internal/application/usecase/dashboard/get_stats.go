@@ -13,16 +13,19 @@ import (
 type GetStatsUseCase struct {
 	reviewRepo    repository.ReviewRepository
 	knowledgeRepo repository.KnowledgeRepository
+	sessionRepo   repository.ReviewSessionRepository
 }
 
 // NewGetStatsUseCase - コンストラクタ
 func NewGetStatsUseCase(
 	reviewRepo repository.ReviewRepository,
 	knowledgeRepo repository.KnowledgeRepository,
+	sessionRepo repository.ReviewSessionRepository,
 ) *GetStatsUseCase {
 	return &GetStatsUseCase{
 		reviewRepo:    reviewRepo,
 		knowledgeRepo: knowledgeRepo,
+		sessionRepo:   sessionRepo,
 	}
 }
 
@@ -36,12 +39,26 @@ type DashboardStats struct {
 
 // RecentReviewItem - 最近のレビューアイテム
 type RecentReviewItem struct {
-	ID                string `json:"id"`
-	FileName          string `json:"file_name"`
-	Language          string `json:"language"`
-	CreatedAt         string `json:"created_at"`
-	ImprovementsCount int    `json:"improvements_count"`
-	Status            string `json:"status"`
+	ID                string  `json:"id"`
+	FileName          string  `json:"file_name"`
+	FilePath          string  `json:"file_path,omitempty"`
+	SessionID         *string `json:"session_id,omitempty"` // 複数ファイルのレビューセッションのレビューの場合
+	Language          string  `json:"language"`
+	CreatedAt         string  `json:"created_at"`
+	ImprovementsCount int     `json:"improvements_count"`
+	Status            string  `json:"status"`
+}
+
+// RecentSessionItem - 最近の複数ファイルのレビューセッション
+type RecentSessionItem struct {
+	ID                string                  `json:"id"`
+	Title             string                  `json:"title"`
+	Summary           string                  `json:"summary"`
+	FileCount         int                     `json:"file_count"`
+	ImprovementsCount int                     `json:"improvements_count"`
+	Improvements      model.ImprovementCounts `json:"improvements"` // 重要度ごとの内訳
+	CreatedAt         string                  `json:"created_at"`
+	Status            string                  `json:"status"`
 }
 
 // SkillAnalysis - スキル分析（カテゴリ別のナレッジ割合）
@@ -57,9 +74,10 @@ type SkillAnalysis struct {
 
 // DashboardStatsResponse - レスポンス全体
 type DashboardStatsResponse struct {
-	Stats          DashboardStats     `json:"stats"`
-	RecentReviews  []RecentReviewItem `json:"recent_reviews"`
-	SkillAnalysis  SkillAnalysis      `json:"skill_analysis"`
+	Stats          DashboardStats      `json:"stats"`
+	RecentReviews  []RecentReviewItem  `json:"recent_reviews"`
+	RecentSessions []RecentSessionItem `json:"recent_sessions"`
+	SkillAnalysis  SkillAnalysis       `json:"skill_analysis"`
 }
 
 // Execute - UseCase実行
@@ -97,7 +115,14 @@ func (u *GetStatsUseCase) Execute(ctx context.Context, userID string) (*Dashboar
 	}
 	recentReviews := convertToRecentReviewItems(recentReviewsData)
 
-	// 6. カテゴリ別ナレッジ数を取得
+	// 6. 最近の複数ファイルのレビューセッションを取得（最大5件）
+	recentSessionsData, err := u.sessionRepo.ListByUserID(ctx, userID, 5, 0)
+	if err != nil {
+		recentSessionsData = []*model.ReviewSession{}
+	}
+	recentSessions := convertToRecentSessionItems(recentSessionsData)
+
+	// 7. カテゴリ別ナレッジ数を取得
 	categoryCounts, err := u.knowledgeRepo.CountByCategory(ctx, userID)
 	if err != nil {
 		categoryCounts = make(map[string]int)
@@ -112,8 +137,9 @@ func (u *GetStatsUseCase) Execute(ctx context.Context, userID string) (*Dashboar
 			ConsistencyScore: consistencyScore,
 			WeeklyReviews:    weeklyReviews,
 		},
-		RecentReviews: recentReviews,
-		SkillAnalysis: skillAnalysis,
+		RecentReviews:  recentReviews,
+		RecentSessions: recentSessions,
+		SkillAnalysis:  skillAnalysis,
 	}
 
 	return response, nil
@@ -190,8 +216,11 @@ func convertToRecentReviewItems(reviews []*model.Review) []RecentReviewItem {
 	items := make([]RecentReviewItem, 0, len(reviews))
 	
 	for _, review := range reviews {
-		// ファイル名を決定（CodeからファイルっぽいものをパースするかUntitledにする）
-		fileName := "Untitled"
+		// ファイル名を決定（ファイルのパスを指定せずにレビューした場合はUntitledにする）
+		fileName := review.FileName()
+		if fileName == "" {
+			fileName = "Untitled"
+		}
 		
 		// 改善点の数をカウント
 		improvementsCount := 0
@@ -199,21 +228,43 @@ func convertToRecentReviewItems(reviews []*model.Review) []RecentReviewItem {
 			improvementsCount = len(review.StructuredResult.Improvements)
 		}
 		
-		// ステータスを決定（改善点の数に基づく）
-		status := "success"
-		if improvementsCount > 0 {
-			status = "warning"
-		}
-		
 		items = append(items, RecentReviewItem{
 			ID:                review.ID,
 			FileName:          fileName,
+			FilePath:          review.FilePath,
+			SessionID:         review.SessionID,
 			Language:          review.Language,
 			CreatedAt:         review.CreatedAt.Format(time.RFC3339),
 			ImprovementsCount: improvementsCount,
-			Status:            status,
+			Status:            reviewStatus(improvementsCount),
 		})
 	}
 	
 	return items
 }
+
+// convertToRecentSessionItems - ReviewSession配列をRecentSessionItem配列に変換
+func convertToRecentSessionItems(sessions []*model.ReviewSession) []RecentSessionItem {
+	items := make([]RecentSessionItem, 0, len(sessions))
+	for _, session := range sessions {
+		items = append(items, RecentSessionItem{
+			ID:                session.ID,
+			Title:             session.Title,
+			Summary:           session.Summary,
+			FileCount:         session.FileCount,
+			ImprovementsCount: session.Improvements.Total,
+			Improvements:      session.Improvements,
+			CreatedAt:         session.CreatedAt.Format(time.RFC3339),
+			Status:            reviewStatus(session.Improvements.Total),
+		})
+	}
+	return items
+}
+
+// reviewStatus - ステータスを決定（改善点の数に基づく）
+func reviewStatus(improvementsCount int) string {
+	if improvementsCount > 0 {
+		return "warning"
+	}
+	return "success"
+}
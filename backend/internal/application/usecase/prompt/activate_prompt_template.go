@@ -0,0 +1,48 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+)
+
+// ActivatePromptTemplateUseCase - プロンプトテンプレート有効化のユースケース
+type ActivatePromptTemplateUseCase struct {
+	promptTemplateRepo repository.PromptTemplateRepository
+}
+
+// NewActivatePromptTemplateUseCase - コンストラクタ
+func NewActivatePromptTemplateUseCase(promptTemplateRepo repository.PromptTemplateRepository) *ActivatePromptTemplateUseCase {
+	return &ActivatePromptTemplateUseCase{
+		promptTemplateRepo: promptTemplateRepo,
+	}
+}
+
+// ActivatePromptTemplateInput - 入力
+type ActivatePromptTemplateInput struct {
+	TemplateID string
+}
+
+// ActivatePromptTemplateOutput - 出力
+type ActivatePromptTemplateOutput struct {
+	Template *model.PromptTemplate
+}
+
+// Execute - 指定したバージョンを有効化（以降のレビューで使用される）
+// 過去バージョンを指定すればロールバックになる
+func (uc *ActivatePromptTemplateUseCase) Execute(ctx context.Context, input ActivatePromptTemplateInput) (*ActivatePromptTemplateOutput, error) {
+	if input.TemplateID == "" {
+		return nil, fmt.Errorf("テンプレートIDは必須です")
+	}
+
+	template, err := uc.promptTemplateRepo.Activate(ctx, input.TemplateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to activate prompt template: %w", err)
+	}
+
+	return &ActivatePromptTemplateOutput{
+		Template: template,
+	}, nil
+}
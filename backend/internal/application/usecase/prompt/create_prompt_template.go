@@ -0,0 +1,71 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+)
+
+// CreatePromptTemplateUseCase - プロンプトテンプレート作成のユースケース
+type CreatePromptTemplateUseCase struct {
+	promptTemplateRepo repository.PromptTemplateRepository
+	promptRenderer     service.PromptRenderer
+}
+
+// NewCreatePromptTemplateUseCase - コンストラクタ
+func NewCreatePromptTemplateUseCase(
+	promptTemplateRepo repository.PromptTemplateRepository,
+	promptRenderer service.PromptRenderer,
+) *CreatePromptTemplateUseCase {
+	return &CreatePromptTemplateUseCase{
+		promptTemplateRepo: promptTemplateRepo,
+		promptRenderer:     promptRenderer,
+	}
+}
+
+// CreatePromptTemplateInput - 入力
+type CreatePromptTemplateInput struct {
+	UserID      string
+	Name        string // 省略時は review_system
+	Content     string
+	Description string
+}
+
+// CreatePromptTemplateOutput - 出力
+type CreatePromptTemplateOutput struct {
+	Template *model.PromptTemplate
+}
+
+// Execute - テンプレートを新しいバージョンとして作成（有効化は別途行う）
+func (uc *CreatePromptTemplateUseCase) Execute(ctx context.Context, input CreatePromptTemplateInput) (*CreatePromptTemplateOutput, error) {
+	// 1. エンティティ生成（ドメインバリデーション）
+	name := input.Name
+	if name == "" {
+		name = model.PromptTemplateReviewSystem
+	}
+	var createdBy *string
+	if input.UserID != "" {
+		createdBy = &input.UserID
+	}
+	template, err := model.NewPromptTemplate(name, input.Content, input.Description, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 構文・差し込み項目の検証（壊れたテンプレートを有効化できないようにする）
+	if err := uc.promptRenderer.Validate(template.Content); err != nil {
+		return nil, err
+	}
+
+	// 3. 保存（バージョンはリポジトリで採番）
+	if err := uc.promptTemplateRepo.Create(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to create prompt template: %w", err)
+	}
+
+	return &CreatePromptTemplateOutput{
+		Template: template,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+)
+
+// ListPromptTemplatesUseCase - プロンプトテンプレート一覧のユースケース
+type ListPromptTemplatesUseCase struct {
+	promptTemplateRepo repository.PromptTemplateRepository
+}
+
+// NewListPromptTemplatesUseCase - コンストラクタ
+func NewListPromptTemplatesUseCase(promptTemplateRepo repository.PromptTemplateRepository) *ListPromptTemplatesUseCase {
+	return &ListPromptTemplatesUseCase{
+		promptTemplateRepo: promptTemplateRepo,
+	}
+}
+
+// ListPromptTemplatesInput - 入力
+type ListPromptTemplatesInput struct {
+	Name string // 省略時は review_system
+}
+
+// ListPromptTemplatesOutput - 出力
+type ListPromptTemplatesOutput struct {
+	Templates []*model.PromptTemplate
+}
+
+// Execute - 全バージョンを新しい順に取得
+func (uc *ListPromptTemplatesUseCase) Execute(ctx context.Context, input ListPromptTemplatesInput) (*ListPromptTemplatesOutput, error) {
+	name := input.Name
+	if name == "" {
+		name = model.PromptTemplateReviewSystem
+	}
+
+	templates, err := uc.promptTemplateRepo.ListByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt templates: %w", err)
+	}
+
+	return &ListPromptTemplatesOutput{
+		Templates: templates,
+	}, nil
+}
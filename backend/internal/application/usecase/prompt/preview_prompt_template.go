@@ -0,0 +1,121 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+)
+
+// PreviewPromptTemplateUseCase - プロンプトテンプレートのプレビューのユースケース
+type PreviewPromptTemplateUseCase struct {
+	promptTemplateRepo repository.PromptTemplateRepository
+	knowledgeRepo      repository.KnowledgeRepository
+	reviewService      *service.ReviewService
+	promptRenderer     service.PromptRenderer
+}
+
+// NewPreviewPromptTemplateUseCase - コンストラクタ
+func NewPreviewPromptTemplateUseCase(
+	promptTemplateRepo repository.PromptTemplateRepository,
+	knowledgeRepo repository.KnowledgeRepository,
+	reviewService *service.ReviewService,
+	promptRenderer service.PromptRenderer,
+) *PreviewPromptTemplateUseCase {
+	return &PreviewPromptTemplateUseCase{
+		promptTemplateRepo: promptTemplateRepo,
+		knowledgeRepo:      knowledgeRepo,
+		reviewService:      reviewService,
+		promptRenderer:     promptRenderer,
+	}
+}
+
+// PreviewPromptTemplateInput - 入力
+// TemplateID と Content のどちらも省略した場合は、現在有効なテンプレートをプレビューする
+type PreviewPromptTemplateInput struct {
+	UserID     string // このユーザーのナレッジを差し込む
+	TemplateID string // 保存済みのバージョンをプレビュー
+	Content    string // 未保存のテンプレート本文をプレビュー
+	Language   string
+	Context    string
+	Locale     string // 描画する言語（ja / en。空の場合は model.DefaultLocale）
+}
+
+// PreviewPromptTemplateOutput - 出力
+type PreviewPromptTemplateOutput struct {
+	Template     *model.PromptTemplate
+	Rendered     string
+	KnowledgeIDs []string
+}
+
+// Execute - テンプレートを描画して返す（LLMは呼び出さない）
+func (uc *PreviewPromptTemplateUseCase) Execute(ctx context.Context, input PreviewPromptTemplateInput) (*PreviewPromptTemplateOutput, error) {
+	// 1. プレビュー対象のテンプレートを決定
+	template, err := uc.resolveTemplate(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. レビュー時と同じ選定ロジックでナレッジを取得
+	knowledges, err := uc.knowledgeRepo.FindByUserID(ctx, input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find knowledge: %w", err)
+	}
+	_, usedKnowledges := uc.reviewService.BuildPromptFromKnowledge(knowledges, input.Locale)
+
+	// 3. 描画
+	rendered, err := uc.promptRenderer.Render(template, service.PromptData{
+		Language:  input.Language,
+		Context:   input.Context,
+		Knowledge: usedKnowledges,
+		Locale:    input.Locale,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	knowledgeIDs := make([]string, len(usedKnowledges))
+	for i, k := range usedKnowledges {
+		knowledgeIDs[i] = k.ID
+	}
+
+	return &PreviewPromptTemplateOutput{
+		Template:     template,
+		Rendered:     rendered,
+		KnowledgeIDs: knowledgeIDs,
+	}, nil
+}
+
+// resolveTemplate - 入力からプレビュー対象のテンプレートを取得
+func (uc *PreviewPromptTemplateUseCase) resolveTemplate(ctx context.Context, input PreviewPromptTemplateInput) (*model.PromptTemplate, error) {
+	if input.Content != "" {
+		// 未保存の本文は構文・差し込み項目を先に検証する
+		if err := uc.promptRenderer.Validate(input.Content); err != nil {
+			return nil, err
+		}
+		return &model.PromptTemplate{
+			Name:    model.PromptTemplateReviewSystem,
+			Content: input.Content,
+		}, nil
+	}
+
+	if input.TemplateID != "" {
+		template, err := uc.promptTemplateRepo.FindByID(ctx, input.TemplateID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find prompt template: %w", err)
+		}
+		return template, nil
+	}
+
+	template, err := uc.promptTemplateRepo.FindActive(ctx, model.PromptTemplateReviewSystem)
+	if errors.Is(err, model.ErrPromptTemplateNotFound) {
+		return service.DefaultReviewPromptTemplate(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find prompt template: %w", err)
+	}
+	return template, nil
+}
@@ -0,0 +1,133 @@
+package prompt_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/application/usecase/prompt"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+	"github.com/s7r8/reviewapp/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePromptTemplateUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         prompt.CreatePromptTemplateInput
+		expectedError error
+	}{
+		{
+			name:  "正常に作成（名前省略時は review_system）",
+			input: prompt.CreatePromptTemplateInput{UserID: "admin-id", Content: "{{.language}} のコードをレビューしてください"},
+		},
+		{
+			name:          "本文が空",
+			input:         prompt.CreatePromptTemplateInput{Content: "  "},
+			expectedError: model.ErrPromptTemplateContentRequired,
+		},
+		{
+			name:          "未知のテンプレート名",
+			input:         prompt.CreatePromptTemplateInput{Name: "unknown", Content: "x"},
+			expectedError: model.ErrPromptTemplateNameInvalid,
+		},
+		{
+			name:          "構文エラー",
+			input:         prompt.CreatePromptTemplateInput{Content: "{{if .has_knowledge}}"},
+			expectedError: service.ErrInvalidPromptTemplate,
+		},
+		{
+			name:          "未定義の差し込み項目",
+			input:         prompt.CreatePromptTemplateInput{Content: "{{.user_name}}"},
+			expectedError: service.ErrInvalidPromptTemplate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := testutil.NewMockPromptTemplateRepository()
+			uc := prompt.NewCreatePromptTemplateUseCase(repo, service.NewTemplatePromptRenderer())
+
+			output, err := uc.Execute(context.Background(), tt.input)
+
+			if tt.expectedError != nil {
+				assert.True(t, errors.Is(err, tt.expectedError), "unexpected error: %v", err)
+				assert.Nil(t, output)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, model.PromptTemplateReviewSystem, output.Template.Name)
+			assert.Equal(t, 1, output.Template.Version)
+			assert.False(t, output.Template.IsActive)
+			assert.Equal(t, "admin-id", *output.Template.CreatedBy)
+		})
+	}
+}
+
+func TestActivatePromptTemplateUseCase_Execute(t *testing.T) {
+	repo := testutil.NewMockPromptTemplateRepository()
+	createUC := prompt.NewCreatePromptTemplateUseCase(repo, service.NewTemplatePromptRenderer())
+	activateUC := prompt.NewActivatePromptTemplateUseCase(repo)
+
+	v1, err := createUC.Execute(context.Background(), prompt.CreatePromptTemplateInput{Content: "v1"})
+	require.NoError(t, err)
+	v2, err := createUC.Execute(context.Background(), prompt.CreatePromptTemplateInput{Content: "v2"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, v2.Template.Version)
+
+	// v2 → v1（ロールバック）の順に有効化
+	_, err = activateUC.Execute(context.Background(), prompt.ActivatePromptTemplateInput{TemplateID: v2.Template.ID})
+	require.NoError(t, err)
+	output, err := activateUC.Execute(context.Background(), prompt.ActivatePromptTemplateInput{TemplateID: v1.Template.ID})
+	require.NoError(t, err)
+
+	assert.True(t, output.Template.IsActive)
+	assert.False(t, v2.Template.IsActive)
+
+	// 存在しないID
+	_, err = activateUC.Execute(context.Background(), prompt.ActivatePromptTemplateInput{TemplateID: "unknown"})
+	assert.True(t, errors.Is(err, model.ErrPromptTemplateNotFound))
+}
+
+func TestPreviewPromptTemplateUseCase_Execute(t *testing.T) {
+	knowledgeRepo := testutil.NewMockKnowledgeRepository()
+	knowledgeRepo.SetKnowledges([]*model.Knowledge{
+		{ID: "k1", UserID: "admin-id", Title: "エラーは必ずラップする", Content: "文脈を付ける", Category: model.CategoryErrorHandling, Priority: 5},
+	})
+	repo := testutil.NewMockPromptTemplateRepository()
+	uc := prompt.NewPreviewPromptTemplateUseCase(repo, knowledgeRepo, service.NewReviewService(), service.NewTemplatePromptRenderer())
+
+	t.Run("有効なテンプレートがなければ組み込みテンプレートを描画", func(t *testing.T) {
+		output, err := uc.Execute(context.Background(), prompt.PreviewPromptTemplateInput{UserID: "admin-id", Language: "go"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, output.Template.Version)
+		assert.Contains(t, output.Rendered, "### [エラーハンドリング] エラーは必ずラップする")
+		assert.Equal(t, []string{"k1"}, output.KnowledgeIDs)
+	})
+
+	t.Run("未保存の本文を描画", func(t *testing.T) {
+		output, err := uc.Execute(context.Background(), prompt.PreviewPromptTemplateInput{
+			UserID:   "admin-id",
+			Content:  "{{.language}}:{{range .knowledge}}{{.title}}{{end}}",
+			Language: "rust",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "rust:エラーは必ずラップする", output.Rendered)
+	})
+
+	t.Run("不正な本文はエラー", func(t *testing.T) {
+		_, err := uc.Execute(context.Background(), prompt.PreviewPromptTemplateInput{UserID: "admin-id", Content: "{{end}}"})
+
+		assert.True(t, errors.Is(err, service.ErrInvalidPromptTemplate))
+	})
+
+	t.Run("存在しないテンプレートID", func(t *testing.T) {
+		_, err := uc.Execute(context.Background(), prompt.PreviewPromptTemplateInput{UserID: "admin-id", TemplateID: "unknown"})
+
+		assert.True(t, errors.Is(err, model.ErrPromptTemplateNotFound))
+	})
+}
@@ -0,0 +1,64 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+)
+
+// GetQuotaUseCase - 利用上限と残りを取得するユースケース
+type GetQuotaUseCase struct {
+	quotaService *service.QuotaService
+	quotaRepo    repository.QuotaRepository
+}
+
+// NewGetQuotaUseCase - コンストラクタ
+func NewGetQuotaUseCase(quotaService *service.QuotaService, quotaRepo repository.QuotaRepository) *GetQuotaUseCase {
+	return &GetQuotaUseCase{
+		quotaService: quotaService,
+		quotaRepo:    quotaRepo,
+	}
+}
+
+// GetQuotaInput - 入力
+type GetQuotaInput struct {
+	UserID string
+}
+
+// GetQuotaOutput - 出力
+type GetQuotaOutput struct {
+	UserID   string             `json:"user_id"`
+	Status   *model.QuotaStatus `json:"status"`
+	Defaults model.QuotaPolicy  `json:"defaults"` // グローバルの上限
+	Override *model.UserQuota   `json:"override"` // ユーザー個別の上限（未設定の場合は null）
+}
+
+// Execute - 上限・当日と当月の消費量・残りを取得
+func (uc *GetQuotaUseCase) Execute(ctx context.Context, input GetQuotaInput) (*GetQuotaOutput, error) {
+	if input.UserID == "" {
+		return nil, fmt.Errorf("ユーザーIDは必須です")
+	}
+
+	override, err := uc.quotaRepo.FindByUserID(ctx, input.UserID)
+	if errors.Is(err, model.ErrUserQuotaNotFound) {
+		override = nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to find user quota: %w", err)
+	}
+
+	status, err := uc.quotaService.Status(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetQuotaOutput{
+		UserID:   input.UserID,
+		Status:   status,
+		Defaults: uc.quotaService.Defaults(),
+		Override: override,
+	}, nil
+}
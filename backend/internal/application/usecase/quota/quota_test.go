@@ -0,0 +1,71 @@
+package quota_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/application/usecase/quota"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+	"github.com/s7r8/reviewapp/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestUserQuota_OverridesGlobalPolicy(t *testing.T) {
+	ctx := context.Background()
+	quotaRepo := testutil.NewMockQuotaRepository()
+	userRepo := testutil.NewMockUserRepository()
+	user := model.NewUser("auth0|user-1", "user@example.com", "User")
+	user.ID = "user-1"
+	userRepo.SetUser(user)
+
+	counter := testutil.NewMockQuotaCounter()
+	counter.SetUsage("user-1", model.QuotaUsage{ReviewsToday: 4, TokensThisMonth: 30000})
+
+	quotaService := service.NewQuotaService(quotaRepo, counter, model.QuotaPolicy{ReviewsPerDay: 10, TokensPerMonth: 100000})
+	getQuota := quota.NewGetQuotaUseCase(quotaService, quotaRepo)
+	updateQuota := quota.NewUpdateUserQuotaUseCase(quotaRepo, userRepo)
+
+	t.Run("個別の設定がない場合はグローバルの上限", func(t *testing.T) {
+		output, err := getQuota.Execute(ctx, quota.GetQuotaInput{UserID: "user-1"})
+
+		require.NoError(t, err)
+		assert.Nil(t, output.Override)
+		assert.Equal(t, 6, *output.Status.RemainingReviewsDay)
+		assert.Equal(t, 70000, *output.Status.RemainingTokensMonth)
+	})
+
+	t.Run("個別の設定は指定した項目だけ上書きする（0は無制限）", func(t *testing.T) {
+		_, err := updateQuota.Execute(ctx, quota.UpdateUserQuotaInput{UserID: "user-1", ReviewsPerDay: intPtr(0)})
+		require.NoError(t, err)
+
+		output, err := getQuota.Execute(ctx, quota.GetQuotaInput{UserID: "user-1"})
+
+		require.NoError(t, err)
+		assert.Equal(t, model.QuotaPolicy{ReviewsPerDay: 0, TokensPerMonth: 100000}, output.Status.Policy)
+		assert.Nil(t, output.Status.RemainingReviewsDay)
+		assert.Equal(t, 70000, *output.Status.RemainingTokensMonth)
+	})
+
+	t.Run("すべて null にすると個別の設定を削除する", func(t *testing.T) {
+		output, err := updateQuota.Execute(ctx, quota.UpdateUserQuotaInput{UserID: "user-1"})
+		require.NoError(t, err)
+		assert.Nil(t, output.Quota)
+
+		_, err = quotaRepo.FindByUserID(ctx, "user-1")
+		assert.ErrorIs(t, err, model.ErrUserQuotaNotFound)
+	})
+
+	t.Run("バリデーションエラー", func(t *testing.T) {
+		_, err := updateQuota.Execute(ctx, quota.UpdateUserQuotaInput{UserID: "user-1", TokensPerMonth: intPtr(-1)})
+		assert.ErrorIs(t, err, model.ErrQuotaInvalid)
+
+		_, err = updateQuota.Execute(ctx, quota.UpdateUserQuotaInput{UserID: "unknown", ReviewsPerDay: intPtr(5)})
+		assert.ErrorIs(t, err, model.ErrUserNotFound)
+	})
+}
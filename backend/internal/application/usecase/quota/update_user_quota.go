@@ -0,0 +1,73 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+)
+
+// UpdateUserQuotaUseCase - ユーザー個別の利用上限を設定するユースケース（管理者用）
+type UpdateUserQuotaUseCase struct {
+	quotaRepo repository.QuotaRepository
+	userRepo  repository.UserRepository
+}
+
+// NewUpdateUserQuotaUseCase - コンストラクタ
+func NewUpdateUserQuotaUseCase(quotaRepo repository.QuotaRepository, userRepo repository.UserRepository) *UpdateUserQuotaUseCase {
+	return &UpdateUserQuotaUseCase{
+		quotaRepo: quotaRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// UpdateUserQuotaInput - 入力（nil の項目はグローバル設定を使う、0は無制限）
+type UpdateUserQuotaInput struct {
+	UserID         string
+	ReviewsPerDay  *int
+	TokensPerMonth *int
+}
+
+// UpdateUserQuotaOutput - 出力（すべて nil にした場合は個別の設定を削除し、Quota は nil）
+type UpdateUserQuotaOutput struct {
+	Quota *model.UserQuota
+}
+
+// Execute - ユーザー個別の上限を作成・更新（すべて nil の場合は削除）
+func (uc *UpdateUserQuotaUseCase) Execute(ctx context.Context, input UpdateUserQuotaInput) (*UpdateUserQuotaOutput, error) {
+	quota := &model.UserQuota{
+		UserID:         input.UserID,
+		ReviewsPerDay:  input.ReviewsPerDay,
+		TokensPerMonth: input.TokensPerMonth,
+		UpdatedAt:      time.Now(),
+	}
+	if err := quota.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 1. ユーザーの存在確認
+	if _, err := uc.userRepo.FindByID(ctx, input.UserID); err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	// 2. すべてグローバル設定に戻す場合は削除
+	if quota.IsEmpty() {
+		if err := uc.quotaRepo.Delete(ctx, input.UserID); err != nil {
+			return nil, fmt.Errorf("failed to delete user quota: %w", err)
+		}
+		return &UpdateUserQuotaOutput{}, nil
+	}
+
+	// 3. 作成・更新
+	if err := uc.quotaRepo.Upsert(ctx, quota); err != nil {
+		return nil, fmt.Errorf("failed to update user quota: %w", err)
+	}
+
+	return &UpdateUserQuotaOutput{Quota: quota}, nil
+}
@@ -0,0 +1,158 @@
+package review
+
+import (
+	"context"
+	"sort"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+)
+
+// appliedCodePath - ファイルのパスがないレビューの unified diff のヘッダーに使うパス
+const appliedCodePath = "code"
+
+// ApplyImprovementsUseCase - レビューの改善点の修正後のコードを、レビューしたコードに適用するユースケース
+// 適用後のコード全体と元のコードとの unified diff を返し、適用後のコードを新しいレビュー（フォローアップ）としてレビューできる
+type ApplyImprovementsUseCase struct {
+	reviewCode *ReviewCodeUseCase
+	jobs       *ReviewJobUseCase
+}
+
+// NewApplyImprovementsUseCase - コンストラクタ
+// jobs: 適用後のコードを非同期でレビューする場合に使う
+func NewApplyImprovementsUseCase(reviewCode *ReviewCodeUseCase, jobs *ReviewJobUseCase) *ApplyImprovementsUseCase {
+	return &ApplyImprovementsUseCase{
+		reviewCode: reviewCode,
+		jobs:       jobs,
+	}
+}
+
+// ApplyImprovementsInput - 入力
+type ApplyImprovementsInput struct {
+	ReviewID string
+	UserID   string
+	// Improvements - 適用する改善点の番号（レビュー結果の improvements の0始まりの番号。重複は無視する）
+	Improvements []int
+	// Save - 適用後のコードを、元のレビューと同じ言語・説明・ファイルのパスでレビューする
+	Save bool
+	// Async - 適用後のコードのレビューを非同期のレビューとして受け付ける（Save の場合のみ）
+	Async bool
+}
+
+// ApplyImprovementsOutput - 出力
+type ApplyImprovementsOutput struct {
+	Review  *model.Review // 適用元のレビュー
+	Applied []int         // 適用した改善点の番号（昇順）
+	// NotApplied - 選択したが適用できなかった改善点と理由（番号の昇順）
+	NotApplied []model.ImprovementNotApplied
+	Code       string // 適用後のコード全体
+	Diff       string // 適用元のレビューのコードとの unified diff
+	// Formatted - go/format で整形したか（Go のコードで構文エラーがない場合）
+	Formatted bool
+	// SyntaxErrors - 適用後のコードの構文エラー（Go のみ検査する）
+	SyntaxErrors []string
+	// FollowUp - 適用後のコードのレビュー（Save の場合）
+	FollowUp *model.Review
+}
+
+// Execute - 選択した改善点を適用したコードと unified diff を作成し、指定された場合はレビューする
+func (uc *ApplyImprovementsUseCase) Execute(ctx context.Context, input ApplyImprovementsInput) (*ApplyImprovementsOutput, error) {
+	// 1. 適用元のレビューを取得（他のユーザーのレビューは見つからない扱い。完了したファイル全体のレビューのみ）
+	original, err := findOwnReview(ctx, uc.reviewCode.reviewRepo, input.UserID, input.ReviewID)
+	if err != nil {
+		return nil, err
+	}
+	if !original.IsCompleted() {
+		return nil, model.ErrReviewNotCompleted
+	}
+	if original.IsDiff() {
+		return nil, model.ErrApplyDiffReview
+	}
+
+	// 2. 改善点を適用
+	indices := uniqueSortedInts(input.Improvements)
+	if len(indices) == 0 {
+		return nil, model.ErrApplyNoImprovements
+	}
+	var improvements []model.Improvement
+	if original.StructuredResult != nil {
+		improvements = original.StructuredResult.Improvements
+	}
+	code, notApplied, err := service.ApplyImprovements(original.Code, improvements, indices)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Go のコードは構文を検査して整形する
+	output := &ApplyImprovementsOutput{
+		Review:     original,
+		Applied:    appliedIndices(indices, notApplied),
+		NotApplied: notApplied,
+		Code:       code,
+	}
+	if service.IsGoLanguage(original.Language) {
+		output.Code, output.SyntaxErrors = service.FormatGoSource(code)
+		output.Formatted = len(output.SyntaxErrors) == 0
+	}
+
+	// 4. 元のコードとの unified diff
+	path := original.FilePath
+	if path == "" {
+		path = appliedCodePath
+	}
+	output.Diff = service.UnifiedDiff(path, original.Code, output.Code)
+
+	if !input.Save {
+		return output, nil
+	}
+
+	// 5. 適用後のコードをフォローアップのレビューとしてレビュー（構文エラーがある場合はレビューしない）
+	if len(output.SyntaxErrors) > 0 {
+		return nil, model.ErrAppliedCodeInvalid
+	}
+	followUp := inputFromReview(original)
+	followUp.Code = output.Code
+	followUp.FollowUpOf = original.ID
+	if input.Async {
+		output.FollowUp, err = uc.jobs.Submit(ctx, followUp)
+		if err != nil {
+			return nil, err
+		}
+		return output, nil
+	}
+	reviewed, err := uc.reviewCode.Execute(ctx, followUp)
+	if err != nil {
+		return nil, err
+	}
+	output.FollowUp = reviewed.Review
+	return output, nil
+}
+
+// appliedIndices - 選択した改善点の番号のうち、適用できなかったものを除いた番号
+func appliedIndices(indices []int, notApplied []model.ImprovementNotApplied) []int {
+	skipped := make(map[int]bool, len(notApplied))
+	for _, n := range notApplied {
+		skipped[n.Index] = true
+	}
+	applied := make([]int, 0, len(indices))
+	for _, index := range indices {
+		if !skipped[index] {
+			applied = append(applied, index)
+		}
+	}
+	return applied
+}
+
+// uniqueSortedInts - 重複を除いて昇順に並べた番号
+func uniqueSortedInts(values []int) []int {
+	seen := make(map[int]bool, len(values))
+	unique := make([]int, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	sort.Ints(unique)
+	return unique
+}
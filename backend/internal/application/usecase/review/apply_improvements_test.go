@@ -0,0 +1,178 @@
+package review_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+	"github.com/s7r8/reviewapp/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyImprovementsUseCase_Execute(t *testing.T) {
+	newUseCase := func(repo *testutil.MockReviewRepository, client *testutil.MockClaudeClient) *review.ApplyImprovementsUseCase {
+		reviewCode := review.NewReviewCodeUseCase(
+			repo,
+			testutil.NewMockKnowledgeRepository(),
+			service.NewReviewService(),
+			client,
+			testutil.NewMockEmbeddingClient(),
+			testutil.NewMockPromptTemplateRepository(),
+			service.NewTemplatePromptRenderer(),
+			service.NewPriceTable(service.DefaultPriceCurrency, service.DefaultModelPrices()),
+			nil,
+			review.ChunkingOptions{},
+			review.CacheOptions{},
+			review.PreferenceOptions{},
+		)
+		return review.NewApplyImprovementsUseCase(reviewCode, review.NewReviewJobUseCase(reviewCode, review.ReviewJobUseCaseOptions{}))
+	}
+	newOriginal := func(t *testing.T, repo *testutil.MockReviewRepository, language string, improvements ...model.Improvement) *model.Review {
+		original := model.NewReview("test-user-id", "func main() {\n\tdata, _ := load()\n\tuse(data)\n}", language, "CLIの入口")
+		original.SetFile("cmd/main.go")
+		original.SetReviewResult("result", &model.StructuredReviewResult{Summary: "summary", Improvements: improvements}, nil, "mock", "mock-model", 100)
+		require.NoError(t, repo.Create(context.Background(), original))
+		return original
+	}
+	handleErr := model.Improvement{
+		Title:      "エラーを処理する",
+		StartLine:  2,
+		EndLine:    2,
+		CodeBefore: "data, _ := load()",
+		CodeAfter:  "data, err := load()\nif err != nil {\nlog.Fatal(err)\n}",
+	}
+
+	t.Run("改善点を適用したコードを整形し、unified diff を返す", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		client := testutil.NewMockClaudeClient()
+		uc := newUseCase(repo, client)
+		original := newOriginal(t, repo, "Go", handleErr)
+
+		output, err := uc.Execute(context.Background(), review.ApplyImprovementsInput{
+			ReviewID: original.ID, UserID: "test-user-id", Improvements: []int{0, 0},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []int{0}, output.Applied)
+		assert.True(t, output.Formatted)
+		assert.Empty(t, output.SyntaxErrors)
+		assert.Equal(t, "func main() {\n\tdata, err := load()\n\tif err != nil {\n\t\tlog.Fatal(err)\n\t}\n\tuse(data)\n}", output.Code)
+		assert.Contains(t, output.Diff, "--- a/cmd/main.go\n+++ b/cmd/main.go\n")
+		assert.Contains(t, output.Diff, "-\tdata, _ := load()\n+\tdata, err := load()\n")
+		assert.Nil(t, output.FollowUp)
+		assert.Empty(t, client.Inputs())
+	})
+
+	t.Run("保存する場合は適用後のコードを元のレビューを記録してレビューする", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		client := testutil.NewMockClaudeClient()
+		uc := newUseCase(repo, client)
+		original := newOriginal(t, repo, "Go", handleErr)
+
+		output, err := uc.Execute(context.Background(), review.ApplyImprovementsInput{
+			ReviewID: original.ID, UserID: "test-user-id", Improvements: []int{0}, Save: true,
+		})
+		require.NoError(t, err)
+
+		require.NotNil(t, output.FollowUp)
+		require.NotNil(t, output.FollowUp.FollowUpOfReviewID)
+		assert.Equal(t, original.ID, *output.FollowUp.FollowUpOfReviewID)
+		assert.Equal(t, output.Code, output.FollowUp.Code)
+		assert.Equal(t, "cmd/main.go", output.FollowUp.FilePath)
+		require.Len(t, client.Inputs(), 1)
+		assert.Equal(t, output.Code, client.Inputs()[0].Code)
+		assert.Equal(t, "CLIの入口", client.Inputs()[0].Context)
+
+		// 非同期の場合は実行待ちのジョブを受け付ける
+		output, err = uc.Execute(context.Background(), review.ApplyImprovementsInput{
+			ReviewID: original.ID, UserID: "test-user-id", Improvements: []int{0}, Save: true, Async: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, model.ReviewStatusPending, output.FollowUp.Status)
+		require.NotNil(t, output.FollowUp.FollowUpOfReviewID)
+		assert.Len(t, client.Inputs(), 1)
+	})
+
+	t.Run("構文エラーがあるコードは返すが、保存しない", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		client := testutil.NewMockClaudeClient()
+		uc := newUseCase(repo, client)
+		broken := handleErr
+		broken.CodeAfter = "data, err := load(\nif err != nil {"
+		original := newOriginal(t, repo, "Go", broken)
+
+		output, err := uc.Execute(context.Background(), review.ApplyImprovementsInput{
+			ReviewID: original.ID, UserID: "test-user-id", Improvements: []int{0},
+		})
+		require.NoError(t, err)
+		assert.False(t, output.Formatted)
+		assert.NotEmpty(t, output.SyntaxErrors)
+
+		_, err = uc.Execute(context.Background(), review.ApplyImprovementsInput{
+			ReviewID: original.ID, UserID: "test-user-id", Improvements: []int{0}, Save: true,
+		})
+		assert.ErrorIs(t, err, model.ErrAppliedCodeInvalid)
+		assert.Empty(t, client.Inputs())
+	})
+
+	t.Run("code_before を特定できない改善点は行範囲で適用し、行範囲もない改善点は理由を返す", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		uc := newUseCase(repo, testutil.NewMockClaudeClient())
+		byLine := model.Improvement{Title: "照合で code_before を空にした", StartLine: 3, EndLine: 3, CodeAfter: "use(data, nil)"}
+		unknown := model.Improvement{Title: "位置が分からない", CodeAfter: "log.Println(data)"}
+		original := newOriginal(t, repo, "Go", handleErr, byLine, unknown)
+
+		output, err := uc.Execute(context.Background(), review.ApplyImprovementsInput{
+			ReviewID: original.ID, UserID: "test-user-id", Improvements: []int{2, 1, 0},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []int{0, 1}, output.Applied)
+		assert.Equal(t, []model.ImprovementNotApplied{{Index: 2, Reason: model.ErrImprovementNotApplicable}}, output.NotApplied)
+		assert.Equal(t, "func main() {\n\tdata, err := load()\n\tif err != nil {\n\t\tlog.Fatal(err)\n\t}\n\tuse(data, nil)\n}", output.Code)
+
+		_, err = uc.Execute(context.Background(), review.ApplyImprovementsInput{
+			ReviewID: original.ID, UserID: "test-user-id", Improvements: []int{2},
+		})
+		assert.ErrorIs(t, err, model.ErrImprovementNotApplicable)
+	})
+
+	t.Run("Go 以外のコードは整形しない", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		uc := newUseCase(repo, testutil.NewMockClaudeClient())
+		original := newOriginal(t, repo, "Python", handleErr)
+
+		output, err := uc.Execute(context.Background(), review.ApplyImprovementsInput{
+			ReviewID: original.ID, UserID: "test-user-id", Improvements: []int{0},
+		})
+		require.NoError(t, err)
+		assert.False(t, output.Formatted)
+		assert.Contains(t, output.Code, "\tif err != nil {\n\tlog.Fatal(err)\n\t}")
+	})
+
+	t.Run("他のユーザー・完了していない・差分のレビュー、改善点の指定なしは適用できない", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		uc := newUseCase(repo, testutil.NewMockClaudeClient())
+		original := newOriginal(t, repo, "Go", handleErr)
+		pending := model.NewReviewJob("test-user-id", "func main() {}", "Go", "", model.ReviewJobOptions{})
+		require.NoError(t, repo.Create(context.Background(), pending))
+		diffReview := model.NewReview("test-user-id", "--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-a\n+b\n", "Go", "")
+		diffReview.SetDiff()
+		diffReview.SetReviewResult("result", &model.StructuredReviewResult{Improvements: []model.Improvement{handleErr}}, nil, "mock", "mock-model", 100)
+		require.NoError(t, repo.Create(context.Background(), diffReview))
+
+		_, err := uc.Execute(context.Background(), review.ApplyImprovementsInput{ReviewID: original.ID, UserID: "other-user-id", Improvements: []int{0}})
+		assert.ErrorIs(t, err, model.ErrReviewNotFound)
+		_, err = uc.Execute(context.Background(), review.ApplyImprovementsInput{ReviewID: pending.ID, UserID: "test-user-id", Improvements: []int{0}})
+		assert.ErrorIs(t, err, model.ErrReviewNotCompleted)
+		_, err = uc.Execute(context.Background(), review.ApplyImprovementsInput{ReviewID: diffReview.ID, UserID: "test-user-id", Improvements: []int{0}})
+		assert.ErrorIs(t, err, model.ErrApplyDiffReview)
+		_, err = uc.Execute(context.Background(), review.ApplyImprovementsInput{ReviewID: original.ID, UserID: "test-user-id"})
+		assert.ErrorIs(t, err, model.ErrApplyNoImprovements)
+		_, err = uc.Execute(context.Background(), review.ApplyImprovementsInput{ReviewID: original.ID, UserID: "test-user-id", Improvements: []int{1}})
+		assert.ErrorIs(t, err, model.ErrApplyImprovementIndex)
+	})
+}
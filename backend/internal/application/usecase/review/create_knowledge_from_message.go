@@ -0,0 +1,115 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
+)
+
+// CreateKnowledgeFromMessageUseCase - レビューについての会話からナレッジを作成するユースケース
+// LLMが提案したナレッジを元に、ユーザーが指定した項目で上書きして登録する（source_type=conversation）
+type CreateKnowledgeFromMessageUseCase struct {
+	messageRepo     repository.ReviewMessageRepository
+	knowledgeRepo   repository.KnowledgeRepository
+	embeddingClient external.EmbeddingClientInterface
+	enabled         bool
+}
+
+// NewCreateKnowledgeFromMessageUseCase - コンストラクタ
+func NewCreateKnowledgeFromMessageUseCase(
+	messageRepo repository.ReviewMessageRepository,
+	knowledgeRepo repository.KnowledgeRepository,
+	embeddingClient external.EmbeddingClientInterface,
+	options ConversationOptions,
+) *CreateKnowledgeFromMessageUseCase {
+	return &CreateKnowledgeFromMessageUseCase{
+		messageRepo:     messageRepo,
+		knowledgeRepo:   knowledgeRepo,
+		embeddingClient: embeddingClient,
+		enabled:         options.Enabled,
+	}
+}
+
+// CreateKnowledgeFromMessageInput - 入力
+// Title / Content / Category / Priority は空・0の場合にLLMの提案を使う（提案がないメッセージでは必須）
+type CreateKnowledgeFromMessageInput struct {
+	ReviewID  string
+	MessageID string
+	UserID    string
+	Title     string
+	Content   string
+	Category  string
+	Priority  int
+}
+
+// Execute - メッセージからナレッジを作成（1つのメッセージから作成できるのは1件まで）
+func (uc *CreateKnowledgeFromMessageUseCase) Execute(ctx context.Context, input CreateKnowledgeFromMessageInput) (*model.Knowledge, error) {
+	if !uc.enabled {
+		return nil, model.ErrConversationDisabled
+	}
+
+	// 1. メッセージを取得（自分のレビューのメッセージのみ）
+	message, err := uc.messageRepo.FindByID(ctx, input.UserID, input.ReviewID, input.MessageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.KnowledgeID != nil {
+		return nil, model.ErrReviewMessageKnowledgeExists
+	}
+
+	// 2. LLMの提案に指定された項目を上書きしてナレッジを生成
+	var suggestion model.KnowledgeSuggestion
+	if message.SuggestedKnowledge != nil {
+		suggestion = *message.SuggestedKnowledge
+	}
+	if input.Title != "" {
+		suggestion.Title = input.Title
+	}
+	if input.Content != "" {
+		suggestion.Content = input.Content
+	}
+	if input.Category != "" {
+		suggestion.Category = input.Category
+	}
+	if input.Priority != 0 {
+		suggestion.Priority = input.Priority
+	}
+
+	knowledge, err := model.NewKnowledgeFromConversation(
+		input.UserID,
+		message.ID,
+		suggestion.Title,
+		suggestion.Content,
+		suggestion.Category,
+		suggestion.Priority,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Embeddingベクトルを生成して保存
+	embedding, err := uc.embeddingClient.GenerateEmbedding(ctx, knowledge.Title+"\n\n"+knowledge.Content)
+	if err != nil {
+		log.Printf("Warning: failed to generate embedding for knowledge: %v", err)
+	} else {
+		knowledge.SetEmbedding(embedding)
+	}
+
+	if err := uc.knowledgeRepo.Create(ctx, knowledge); err != nil {
+		return nil, fmt.Errorf("failed to create knowledge: %w", err)
+	}
+
+	// 4. 作成したナレッジをメッセージに記録（同時に作成された場合は後から作成した方を削除する）
+	if err := uc.messageRepo.SetKnowledgeID(ctx, message.ID, knowledge.ID); err != nil {
+		if deleteErr := uc.knowledgeRepo.Delete(ctx, knowledge.ID); deleteErr != nil {
+			log.Printf("Warning: failed to delete duplicated knowledge %s: %v", knowledge.ID, deleteErr)
+		}
+		return nil, err
+	}
+
+	return knowledge, nil
+}
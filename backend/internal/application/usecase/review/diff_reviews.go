@@ -0,0 +1,90 @@
+package review
+
+import (
+	"context"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+)
+
+// DiffReviewsUseCase - 2つのレビューの結果を比較するユースケース
+// 再レビューの前後で、参照したナレッジと改善点がどう変わったかを返す
+type DiffReviewsUseCase struct {
+	reviewRepo repository.ReviewRepository
+}
+
+// NewDiffReviewsUseCase - コンストラクタ
+func NewDiffReviewsUseCase(reviewRepo repository.ReviewRepository) *DiffReviewsUseCase {
+	return &DiffReviewsUseCase{
+		reviewRepo: reviewRepo,
+	}
+}
+
+// DiffReviewsInput - 入力
+type DiffReviewsInput struct {
+	ReviewID      string // 基準のレビュー
+	OtherReviewID string // 比較するレビュー
+	UserID        string
+}
+
+// DiffReviewsOutput - 出力
+type DiffReviewsOutput struct {
+	Base             *model.Review
+	Other            *model.Review
+	KnowledgeAdded   []string // 比較するレビューでのみ参照したナレッジ
+	KnowledgeRemoved []string // 基準のレビューでのみ参照したナレッジ
+	Improvements     *model.ReviewResultDiff
+}
+
+// Execute - 2つのレビューを取得して比較する（自分の完了したレビューのみ）
+func (uc *DiffReviewsUseCase) Execute(ctx context.Context, input DiffReviewsInput) (*DiffReviewsOutput, error) {
+	// 1. レビューを取得（他のユーザーのレビューは見つからない扱い）
+	base, err := uc.findComparableReview(ctx, input.UserID, input.ReviewID)
+	if err != nil {
+		return nil, err
+	}
+	other, err := uc.findComparableReview(ctx, input.UserID, input.OtherReviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 参照したナレッジと改善点を比較
+	return &DiffReviewsOutput{
+		Base:             base,
+		Other:            other,
+		KnowledgeAdded:   subtractIDs(other.ReferencedKnowledge, base.ReferencedKnowledge),
+		KnowledgeRemoved: subtractIDs(base.ReferencedKnowledge, other.ReferencedKnowledge),
+		Improvements:     service.DiffReviewResults(base.StructuredResult, other.StructuredResult),
+	}, nil
+}
+
+// findComparableReview - 比較できるレビュー（完了し、構造化されたレビュー結果がある）を取得する
+func (uc *DiffReviewsUseCase) findComparableReview(ctx context.Context, userID, reviewID string) (*model.Review, error) {
+	review, err := findOwnReview(ctx, uc.reviewRepo, userID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if !review.IsCompleted() {
+		return nil, model.ErrReviewNotCompleted
+	}
+	if review.StructuredResult == nil {
+		return nil, model.ErrReviewNoStructuredResult
+	}
+	return review, nil
+}
+
+// subtractIDs - ids のうち exclude にないものを順に返す
+func subtractIDs(ids, exclude []string) []string {
+	excluded := make(map[string]bool, len(exclude))
+	for _, id := range exclude {
+		excluded[id] = true
+	}
+	result := []string{}
+	for _, id := range ids {
+		if !excluded[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
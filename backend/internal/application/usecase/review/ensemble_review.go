@@ -0,0 +1,272 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
+	"github.com/s7r8/reviewapp/internal/infrastructure/parser"
+)
+
+// EnsembleReviewUseCase - 同じコードを複数のモデルで同時にレビューするユースケース
+// ナレッジの検索・プロンプトの生成は ReviewCodeUseCase と共通で1回だけ行い、
+// モデルごとの結果をそれぞれ1件のレビューとして、同じ ensemble_id で保存する
+type EnsembleReviewUseCase struct {
+	reviewCode  *ReviewCodeUseCase
+	providers   []external.LLMProvider
+	concurrency int
+}
+
+// EnsembleOptions - 複数モデルのレビュー設定
+type EnsembleOptions struct {
+	Providers   []external.LLMProvider // 比較に使えるモデル（LLM_ENSEMBLE_MODELS）
+	Concurrency int                    // 並行してレビューするモデル数（0以下はモデル数）
+}
+
+// NewEnsembleReviewUseCase - コンストラクタ
+func NewEnsembleReviewUseCase(reviewCode *ReviewCodeUseCase, options EnsembleOptions) *EnsembleReviewUseCase {
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = len(options.Providers)
+	}
+	return &EnsembleReviewUseCase{
+		reviewCode:  reviewCode,
+		providers:   options.Providers,
+		concurrency: concurrency,
+	}
+}
+
+// EnsembleReviewInput - 入力
+type EnsembleReviewInput struct {
+	ReviewCodeInput
+	Models []string // "provider:model" の形式（空の場合は設定されたすべてのモデル）
+	Mode   string   // compare / merge（空の場合は compare）
+}
+
+// EnsembleReviewOutput - 出力
+type EnsembleReviewOutput struct {
+	EnsembleID string
+	Mode       string
+	Reviews    []*model.Review   // モデルごとのレビュー（指定順）
+	Merged     *model.Review     // 統合したレビュー（merge の場合のみ）
+	Failures   []EnsembleFailure // レビューに失敗したモデル
+}
+
+// EnsembleFailure - レビューに失敗したモデル
+type EnsembleFailure struct {
+	Model string
+	Error string
+}
+
+// Models - 比較に使えるモデル（"provider:model"）
+func (uc *EnsembleReviewUseCase) Models() []string {
+	models := make([]string, len(uc.providers))
+	for i, p := range uc.providers {
+		models[i] = providerKey(p)
+	}
+	return models
+}
+
+// Execute - 複数のモデルでレビューを実行
+// 一部のモデルが失敗しても、1つ以上成功すれば成功したモデルの結果を返す
+func (uc *EnsembleReviewUseCase) Execute(ctx context.Context, input EnsembleReviewInput) (*EnsembleReviewOutput, error) {
+	// 0. バリデーション（差分の場合は言語とファイルのパスを差分から補う）
+	reviewInput, err := uc.reviewCode.prepareDiff(input.ReviewCodeInput)
+	if err != nil {
+		return nil, err
+	}
+	input.ReviewCodeInput = reviewInput
+	if err := uc.reviewCode.validate(input.ReviewCodeInput); err != nil {
+		return nil, err
+	}
+	if input.Mode == "" {
+		input.Mode = model.EnsembleModeCompare
+	}
+	if !model.IsValidEnsembleMode(input.Mode) {
+		return nil, model.ErrEnsembleModeInvalid
+	}
+	providers, err := uc.selectProviders(input.Models)
+	if err != nil {
+		return nil, err
+	}
+
+	// LLMを呼び出す前に利用上限を確認
+	if err := uc.reviewCode.CheckQuota(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+
+	// Embeddingの消費トークン数をコスト計算のために集計する
+	ctx, embeddingUsage := external.WithEmbeddingUsageRecorder(ctx)
+
+	// 1. コードを分割し、関連ナレッジを取得（全モデル共通）
+	chunks, knowledges, err := uc.reviewCode.splitAndRetrieve(ctx, input.ReviewCodeInput)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. プロンプト生成（全モデル共通）。モデルは比較対象を使い、ユーザー設定は生成パラメータ・出力言語・観点のみ反映する
+	prefs := uc.reviewCode.loadPreferences(ctx, input.UserID)
+	input.Locale = resolveLocale(input.Locale, prefs)
+	knowledgePrompt, usedKnowledges := uc.reviewCode.reviewService.BuildPromptFromKnowledge(knowledges, input.Locale)
+	promptTemplate, reviewInstructions, err := uc.reviewCode.renderReviewInstructions(ctx, input.ReviewCodeInput, usedKnowledges, prefs)
+	if err != nil {
+		return nil, err
+	}
+	llmInput := newLLMInput(input.ReviewCodeInput, knowledgePrompt, reviewInstructions, prefs)
+
+	// 3. モデルごとに並行してレビュー（キャッシュは使わない）
+	results, errs := uc.generateReviews(ctx, providers, llmInput, chunks)
+
+	// 4. モデルごとのレビューを作成（Embeddingのトークン数は最初のレビュー、統合する場合は統合したレビューに含める）
+	output := &EnsembleReviewOutput{
+		EnsembleID: uuid.New().String(),
+		Mode:       input.Mode,
+	}
+	var firstErr error
+	var modelNames []string
+	for i, p := range providers {
+		if errs[i] != nil {
+			log.Printf("Warning: ensemble review failed for %s: %v", providerKey(p), errs[i])
+			output.Failures = append(output.Failures, EnsembleFailure{Model: providerKey(p), Error: errs[i].Error()})
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			continue
+		}
+
+		var usage *external.EmbeddingUsageRecorder
+		if input.Mode == model.EnsembleModeCompare && len(output.Reviews) == 0 {
+			usage = embeddingUsage
+		}
+		cacheKey := reviewCacheKey(input.ReviewCodeInput, usedKnowledges, promptTemplate, knowledgePrompt+reviewInstructions, p, prefs)
+		review := uc.reviewCode.newReview(input.ReviewCodeInput, results[i], p, usedKnowledges, promptTemplate, cacheKey, usage)
+		review.SetEnsemble(output.EnsembleID)
+		output.Reviews = append(output.Reviews, review)
+		modelNames = append(modelNames, providerKey(p))
+	}
+	if len(output.Reviews) == 0 {
+		return nil, fmt.Errorf("failed to review code: %w", firstErr)
+	}
+
+	// 5. 統合したレビューを作成
+	saved := output.Reviews
+	if input.Mode == model.EnsembleModeMerge {
+		output.Merged = uc.mergeReviews(input.ReviewCodeInput, output.Reviews, modelNames, usedKnowledges, promptTemplate, embeddingUsage)
+		output.Merged.SetEnsemble(output.EnsembleID)
+		saved = append(append([]*model.Review{}, output.Reviews...), output.Merged)
+	}
+
+	// 6. まとめて保存して、ナレッジの使用カウントと利用上限の消費を記録（統合したレビューはレビュー数に数えない）
+	if err := uc.reviewCode.saveReviews(ctx, saved, usedKnowledges); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// selectProviders - 指定されたモデルのプロバイダを選ぶ（未指定の場合はすべて）
+func (uc *EnsembleReviewUseCase) selectProviders(models []string) ([]external.LLMProvider, error) {
+	providers := uc.providers
+	if len(models) > 0 {
+		byKey := map[string]external.LLMProvider{}
+		for _, p := range uc.providers {
+			byKey[providerKey(p)] = p
+		}
+
+		providers = nil
+		seen := map[string]bool{}
+		for _, m := range models {
+			m = strings.TrimSpace(m)
+			p, ok := byKey[m]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", model.ErrEnsembleModelNotFound, m)
+			}
+			if seen[m] {
+				continue
+			}
+			seen[m] = true
+			providers = append(providers, p)
+		}
+	}
+
+	if len(providers) < 2 {
+		return nil, model.ErrEnsembleTooFewModels
+	}
+	return providers, nil
+}
+
+// generateReviews - 同時実行数を制限してモデルごとにレビューを生成（失敗したモデルは errs に記録）
+func (uc *EnsembleReviewUseCase) generateReviews(ctx context.Context, providers []external.LLMProvider, input external.ReviewCodeInput, chunks []service.CodeChunk) ([]*external.ReviewCodeOutput, []error) {
+	results := make([]*external.ReviewCodeOutput, len(providers))
+	errs := make([]error, len(providers))
+	sem := make(chan struct{}, uc.concurrency)
+	var wg sync.WaitGroup
+	for i, p := range providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+
+			results[i], errs[i] = uc.reviewCode.generate(ctx, p, input, chunks, nil)
+		}()
+	}
+	wg.Wait()
+
+	return results, errs
+}
+
+// mergeReviews - モデルごとのレビュー結果を1件のレビューにまとめる（modelNames は reviews と同じ順の "provider:model"）
+// LLMは呼び出さないため、トークン数はナレッジ検索のEmbeddingの分のみ（LLMの分はモデルごとのレビューに記録済み）
+func (uc *EnsembleReviewUseCase) mergeReviews(input ReviewCodeInput, reviews []*model.Review, modelNames []string, usedKnowledges []*model.Knowledge, promptTemplate *model.PromptTemplate, embeddingUsage *external.EmbeddingUsageRecorder) *model.Review {
+	modelReviews := make([]service.ModelReview, len(reviews))
+	resultSource := reviews[0].ResultSource
+	for i, r := range reviews {
+		modelReviews[i] = service.ModelReview{Model: modelNames[i], Result: r.StructuredResult}
+		// 1モデルでもマークダウンからのパースにフォールバックした場合はそれを記録
+		if r.ResultSource == model.ResultSourceMarkdown {
+			resultSource = model.ResultSourceMarkdown
+		}
+	}
+	structured := service.MergeModelReviews(modelReviews, input.Locale)
+
+	merged := model.NewReview(input.UserID, reviewedCode(input), input.Language, input.Context)
+	merged.SetLocale(input.Locale)
+	setReviewFile(merged, input)
+	merged.SetStaticAnalysis(analyzeInput(input))
+	merged.SetReviewResult(
+		parser.RenderReviewMarkdown(structured, input.Language, input.Locale),
+		structured,
+		extractKnowledgeIDs(usedKnowledges),
+		model.EnsembleProvider,
+		strings.Join(modelNames, ","),
+		0,
+	)
+	merged.SetResultSource(resultSource)
+	merged.SetPromptTemplate(promptTemplate)
+
+	usage := model.TokenUsage{EmbeddingTokens: embeddingUsage.Tokens()}
+	merged.SetUsage(
+		usage,
+		uc.reviewCode.priceTable.Cost("", embeddingUsage.Model(), usage),
+		uc.reviewCode.priceTable.Currency(),
+	)
+
+	return merged
+}
+
+// providerKey - モデルの識別子（"provider:model"）
+func providerKey(p external.LLMProvider) string {
+	return p.Name() + ":" + p.Model()
+}
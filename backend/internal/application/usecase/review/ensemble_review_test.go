@@ -0,0 +1,215 @@
+package review_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
+	"github.com/s7r8/reviewapp/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// namedLLMProvider - プロバイダ名・モデル名を指定できるスタブ
+type namedLLMProvider struct {
+	*testutil.MockClaudeClient
+	name  string
+	model string
+}
+
+func (p *namedLLMProvider) Name() string  { return p.name }
+func (p *namedLLMProvider) Model() string { return p.model }
+
+func newNamedLLMProvider(name, modelName string, improvements ...model.Improvement) *namedLLMProvider {
+	client := testutil.NewMockClaudeClient()
+	client.SetResponse(&external.ReviewCodeOutput{
+		Structured: &model.StructuredReviewResult{
+			Summary:      modelName + "の総合評価",
+			GoodPoints:   []string{"命名が明確"},
+			Improvements: improvements,
+		},
+		ResultSource: model.ResultSourceToolUse,
+		TokensUsed:   150,
+		Usage:        model.TokenUsage{InputTokens: 100, OutputTokens: 50},
+		Model:        modelName,
+	})
+	return &namedLLMProvider{MockClaudeClient: client, name: name, model: modelName}
+}
+
+func TestEnsembleReviewUseCase_Execute(t *testing.T) {
+	newQuotaUseCase := func(repo *testutil.MockReviewRepository, quota *service.QuotaService, providers ...external.LLMProvider) *review.EnsembleReviewUseCase {
+		embeddingClient := testutil.NewMockEmbeddingClient()
+		embeddingClient.SetTokensPerText(20)
+		reviewCode := review.NewReviewCodeUseCase(
+			repo,
+			testutil.NewMockKnowledgeRepository(),
+			service.NewReviewService(),
+			testutil.NewMockClaudeClient(),
+			embeddingClient,
+			testutil.NewMockPromptTemplateRepository(),
+			service.NewTemplatePromptRenderer(),
+			service.NewPriceTable(service.DefaultPriceCurrency, service.DefaultModelPrices()),
+			quota,
+			review.ChunkingOptions{},
+			review.CacheOptions{},
+			review.PreferenceOptions{},
+		)
+		return review.NewEnsembleReviewUseCase(reviewCode, review.EnsembleOptions{Providers: providers})
+	}
+	newUseCase := func(repo *testutil.MockReviewRepository, providers ...external.LLMProvider) *review.EnsembleReviewUseCase {
+		return newQuotaUseCase(repo, nil, providers...)
+	}
+	input := review.ReviewCodeInput{UserID: "test-user-id", Code: "func test() {}", Language: "go"}
+	errorHandling := model.Improvement{Title: "エラーを握りつぶしている", Description: "エラーを返す", Severity: "medium"}
+
+	t.Run("compare: モデルごとのレビューを同じ ensemble_id で保存する", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		uc := newUseCase(repo,
+			newNamedLLMProvider("anthropic", "claude-3-5-haiku-latest", errorHandling),
+			newNamedLLMProvider("ollama", "qwen2.5-coder:7b", errorHandling),
+		)
+
+		output, err := uc.Execute(context.Background(), review.EnsembleReviewInput{ReviewCodeInput: input})
+		require.NoError(t, err)
+
+		assert.Equal(t, model.EnsembleModeCompare, output.Mode)
+		assert.Nil(t, output.Merged)
+		assert.Empty(t, output.Failures)
+		require.Len(t, output.Reviews, 2)
+		assert.Equal(t, "anthropic", output.Reviews[0].LLMProvider)
+		assert.Equal(t, "claude-3-5-haiku-latest", output.Reviews[0].LLMModel)
+		assert.Equal(t, "ollama", output.Reviews[1].LLMProvider)
+		assert.Equal(t, "qwen2.5-coder:7b", output.Reviews[1].LLMModel)
+
+		// Embeddingのトークン数は最初のレビューにのみ含める
+		assert.Greater(t, output.Reviews[0].Usage.EmbeddingTokens, 0)
+		assert.Equal(t, 0, output.Reviews[1].Usage.EmbeddingTokens)
+
+		saved, err := repo.FindByEnsembleID(context.Background(), "test-user-id", output.EnsembleID)
+		require.NoError(t, err)
+		assert.Len(t, saved, 2)
+	})
+
+	t.Run("merge: 複数のモデルが一致した改善点を記録した統合レビューも保存する", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		uc := newUseCase(repo,
+			newNamedLLMProvider("anthropic", "claude-3-5-haiku-latest", errorHandling),
+			newNamedLLMProvider("ollama", "qwen2.5-coder:7b", errorHandling, model.Improvement{Title: "関数が長い", Description: "分割する", Severity: "low"}),
+		)
+
+		output, err := uc.Execute(context.Background(), review.EnsembleReviewInput{ReviewCodeInput: input, Mode: model.EnsembleModeMerge})
+		require.NoError(t, err)
+
+		require.NotNil(t, output.Merged)
+		assert.Equal(t, model.EnsembleProvider, output.Merged.LLMProvider)
+		assert.Equal(t, "anthropic:claude-3-5-haiku-latest,ollama:qwen2.5-coder:7b", output.Merged.LLMModel)
+		assert.Equal(t, output.EnsembleID, *output.Merged.EnsembleID)
+		assert.Equal(t, 0, output.Merged.Usage.InputTokens)
+		assert.Greater(t, output.Merged.Usage.EmbeddingTokens, 0)
+
+		require.Len(t, output.Merged.StructuredResult.Improvements, 2)
+		assert.True(t, output.Merged.StructuredResult.Improvements[0].Consensus)
+		assert.False(t, output.Merged.StructuredResult.Improvements[1].Consensus)
+		assert.Contains(t, output.Merged.ReviewResult, "指摘したモデル: anthropic:claude-3-5-haiku-latest, ollama:qwen2.5-coder:7b")
+
+		saved, err := repo.FindByEnsembleID(context.Background(), "test-user-id", output.EnsembleID)
+		require.NoError(t, err)
+		assert.Len(t, saved, 3)
+	})
+
+	t.Run("merge: 統合したレビューは利用上限のレビュー数に数えず、トークン数のみ記録する", func(t *testing.T) {
+		counter := testutil.NewMockQuotaCounter()
+		quota := service.NewQuotaService(testutil.NewMockQuotaRepository(), counter, model.QuotaPolicy{})
+		uc := newQuotaUseCase(testutil.NewMockReviewRepository(), quota,
+			newNamedLLMProvider("anthropic", "claude-3-5-haiku-latest", errorHandling),
+			newNamedLLMProvider("ollama", "qwen2.5-coder:7b", errorHandling),
+		)
+
+		output, err := uc.Execute(context.Background(), review.EnsembleReviewInput{ReviewCodeInput: input, Mode: model.EnsembleModeMerge})
+		require.NoError(t, err)
+
+		usage, err := counter.Usage(context.Background(), "test-user-id", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, 2, usage.ReviewsToday)
+		expectedTokens := output.Merged.Usage.Total()
+		for _, r := range output.Reviews {
+			expectedTokens += r.Usage.Total()
+		}
+		assert.Equal(t, expectedTokens, usage.TokensThisMonth)
+	})
+
+	t.Run("一部のモデルが失敗しても成功したモデルの結果を返す", func(t *testing.T) {
+		failing := newNamedLLMProvider("ollama", "qwen2.5-coder:7b")
+		failing.SetError(errors.New("connection refused"))
+		uc := newUseCase(testutil.NewMockReviewRepository(),
+			newNamedLLMProvider("anthropic", "claude-3-5-haiku-latest", errorHandling),
+			failing,
+		)
+
+		output, err := uc.Execute(context.Background(), review.EnsembleReviewInput{ReviewCodeInput: input})
+		require.NoError(t, err)
+
+		require.Len(t, output.Reviews, 1)
+		require.Len(t, output.Failures, 1)
+		assert.Equal(t, "ollama:qwen2.5-coder:7b", output.Failures[0].Model)
+	})
+
+	t.Run("すべてのモデルが失敗した場合はエラー", func(t *testing.T) {
+		a := newNamedLLMProvider("anthropic", "claude-3-5-haiku-latest")
+		a.SetError(errors.New("api error"))
+		b := newNamedLLMProvider("ollama", "qwen2.5-coder:7b")
+		b.SetError(errors.New("connection refused"))
+		uc := newUseCase(testutil.NewMockReviewRepository(), a, b)
+
+		_, err := uc.Execute(context.Background(), review.EnsembleReviewInput{ReviewCodeInput: input})
+		assert.Error(t, err)
+	})
+
+	t.Run("モデルの指定が不正な場合はエラー", func(t *testing.T) {
+		uc := newUseCase(testutil.NewMockReviewRepository(),
+			newNamedLLMProvider("anthropic", "claude-3-5-haiku-latest"),
+			newNamedLLMProvider("ollama", "qwen2.5-coder:7b"),
+		)
+
+		_, err := uc.Execute(context.Background(), review.EnsembleReviewInput{ReviewCodeInput: input, Models: []string{"openai:gpt-4o"}})
+		assert.ErrorIs(t, err, model.ErrEnsembleModelNotFound)
+
+		_, err = uc.Execute(context.Background(), review.EnsembleReviewInput{ReviewCodeInput: input, Models: []string{"ollama:qwen2.5-coder:7b"}})
+		assert.ErrorIs(t, err, model.ErrEnsembleTooFewModels)
+
+		_, err = uc.Execute(context.Background(), review.EnsembleReviewInput{ReviewCodeInput: input, Mode: "vote"})
+		assert.ErrorIs(t, err, model.ErrEnsembleModeInvalid)
+	})
+}
+
+func TestGetEnsembleReviewUseCase_Execute(t *testing.T) {
+	repo := testutil.NewMockReviewRepository()
+	ensembleID := "ensemble-1"
+	for _, provider := range []string{"anthropic", "ollama", model.EnsembleProvider} {
+		r := model.NewReview("test-user-id", "func test() {}", "go", "")
+		r.LLMProvider = provider
+		r.SetEnsemble(ensembleID)
+		require.NoError(t, repo.Create(context.Background(), r))
+	}
+	uc := review.NewGetEnsembleReviewUseCase(repo)
+
+	t.Run("モデルごとのレビューと統合したレビューを返す", func(t *testing.T) {
+		output, err := uc.Execute(context.Background(), review.GetEnsembleReviewInput{EnsembleID: ensembleID, UserID: "test-user-id"})
+		require.NoError(t, err)
+
+		assert.Equal(t, model.EnsembleModeMerge, output.Mode)
+		assert.Len(t, output.Reviews, 2)
+		require.NotNil(t, output.Merged)
+		assert.Equal(t, model.EnsembleProvider, output.Merged.LLMProvider)
+	})
+
+	t.Run("他のユーザーのレビューは取得できない", func(t *testing.T) {
+		_, err := uc.Execute(context.Background(), review.GetEnsembleReviewInput{EnsembleID: ensembleID, UserID: "other-user-id"})
+		assert.ErrorIs(t, err, model.ErrEnsembleNotFound)
+	})
+}
@@ -0,0 +1,63 @@
+package review
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+)
+
+// GetEnsembleReviewUseCase - 複数モデルのレビュー取得のユースケース
+type GetEnsembleReviewUseCase struct {
+	reviewRepo repository.ReviewRepository
+}
+
+// NewGetEnsembleReviewUseCase - コンストラクタ
+func NewGetEnsembleReviewUseCase(
+	reviewRepo repository.ReviewRepository,
+) *GetEnsembleReviewUseCase {
+	return &GetEnsembleReviewUseCase{
+		reviewRepo: reviewRepo,
+	}
+}
+
+// GetEnsembleReviewInput - 入力
+type GetEnsembleReviewInput struct {
+	EnsembleID string
+	UserID     string
+}
+
+// Execute - 同じ ensemble_id のレビューを取得（自分のレビューのみ）
+// 失敗したモデルは保存していないため、Failures は常に空
+func (uc *GetEnsembleReviewUseCase) Execute(ctx context.Context, input GetEnsembleReviewInput) (*EnsembleReviewOutput, error) {
+	// 0. バリデーション
+	if input.EnsembleID == "" {
+		return nil, fmt.Errorf("ensemble ID is required")
+	}
+	if input.UserID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+
+	// 1. レビューを取得（他のユーザーのレビューは見つからない扱い）
+	reviews, err := uc.reviewRepo.FindByEnsembleID(ctx, input.UserID, input.EnsembleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. モデルごとのレビューと統合したレビューに分ける
+	output := &EnsembleReviewOutput{
+		EnsembleID: input.EnsembleID,
+		Mode:       model.EnsembleModeCompare,
+	}
+	for _, r := range reviews {
+		if r.IsMergedEnsemble() {
+			output.Mode = model.EnsembleModeMerge
+			output.Merged = r
+			continue
+		}
+		output.Reviews = append(output.Reviews, r)
+	}
+
+	return output, nil
+}
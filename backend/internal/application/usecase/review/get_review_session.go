@@ -0,0 +1,60 @@
+package review
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+)
+
+// GetReviewSessionUseCase - 複数ファイルのレビューセッション取得のユースケース
+type GetReviewSessionUseCase struct {
+	reviewRepo  repository.ReviewRepository
+	sessionRepo repository.ReviewSessionRepository
+}
+
+// NewGetReviewSessionUseCase - コンストラクタ
+func NewGetReviewSessionUseCase(
+	reviewRepo repository.ReviewRepository,
+	sessionRepo repository.ReviewSessionRepository,
+) *GetReviewSessionUseCase {
+	return &GetReviewSessionUseCase{
+		reviewRepo:  reviewRepo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+// GetReviewSessionInput - 入力
+type GetReviewSessionInput struct {
+	SessionID string
+	UserID    string
+}
+
+// Execute - セッションとファイルごとのレビューを取得（自分のセッションのみ）
+// 失敗したファイルは保存していないため、Failures は常に空
+func (uc *GetReviewSessionUseCase) Execute(ctx context.Context, input GetReviewSessionInput) (*ReviewSessionOutput, error) {
+	// 0. バリデーション
+	if input.SessionID == "" {
+		return nil, fmt.Errorf("session ID is required")
+	}
+	if input.UserID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+
+	// 1. セッションを取得（他のユーザーのセッションは見つからない扱い）
+	session, err := uc.sessionRepo.FindByID(ctx, input.UserID, input.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. ファイルごとのレビューを取得
+	reviews, err := uc.reviewRepo.FindBySessionID(ctx, input.UserID, input.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReviewSessionOutput{
+		Session: session,
+		Reviews: reviews,
+	}, nil
+}
@@ -28,6 +28,10 @@ func (m *MockReviewRepositoryForGet) Create(ctx context.Context, review *model.R
 	return nil
 }
 
+func (m *MockReviewRepositoryForGet) CreateAll(ctx context.Context, reviews []*model.Review) error {
+	return nil
+}
+
 func (m *MockReviewRepositoryForGet) Update(ctx context.Context, review *model.Review) error {
 	return nil
 }
@@ -68,6 +72,66 @@ func (m *MockReviewRepositoryForGet) GetAverageFeedbackScore(ctx context.Context
 	return 0.0, nil
 }
 
+func (m *MockReviewRepositoryForGet) AggregateUsage(ctx context.Context, filter model.UsageFilter) ([]*model.UsageSummary, error) {
+	return nil, nil
+}
+
+func (m *MockReviewRepositoryForGet) FindCachedReview(ctx context.Context, userID, cacheKey string, since time.Time) (*model.Review, error) {
+	return nil, model.ErrReviewCacheMiss
+}
+
+func (m *MockReviewRepositoryForGet) FindByEnsembleID(ctx context.Context, userID, ensembleID string) ([]*model.Review, error) {
+	return nil, model.ErrEnsembleNotFound
+}
+
+func (m *MockReviewRepositoryForGet) FindBySessionID(ctx context.Context, userID, sessionID string) ([]*model.Review, error) {
+	return []*model.Review{}, nil
+}
+
+func (m *MockReviewRepositoryForGet) ClaimNextJob(ctx context.Context) (*model.Review, error) {
+	return nil, model.ErrNoPendingReviewJob
+}
+
+func (m *MockReviewRepositoryForGet) UpdateJobStatus(ctx context.Context, review *model.Review, from string) error {
+	return nil
+}
+
+func (m *MockReviewRepositoryForGet) CompleteJob(ctx context.Context, review *model.Review) error {
+	return nil
+}
+
+func (m *MockReviewRepositoryForGet) RequeueStaleJobs(ctx context.Context, startedBefore time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m *MockReviewRepositoryForGet) SoftDelete(ctx context.Context, id string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockReviewRepositoryForGet) SoftDeleteWithFilters(ctx context.Context, userID string, filters map[string]interface{}) (int, []*model.Review, error) {
+	return 0, nil, nil
+}
+
+func (m *MockReviewRepositoryForGet) FindDeletedByID(ctx context.Context, id string) (*model.Review, error) {
+	return nil, model.ErrReviewNotFound
+}
+
+func (m *MockReviewRepositoryForGet) ListDeleted(ctx context.Context, userID string, limit, offset int) ([]*model.Review, error) {
+	return nil, nil
+}
+
+func (m *MockReviewRepositoryForGet) CountDeleted(ctx context.Context, userID string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockReviewRepositoryForGet) Restore(ctx context.Context, id string) error {
+	return nil
+}
+
+func (m *MockReviewRepositoryForGet) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	return 0, nil
+}
+
 // TestGetReviewUseCase_Execute - 正常系テスト
 func TestGetReviewUseCase_Execute(t *testing.T) {
 	// Arrange
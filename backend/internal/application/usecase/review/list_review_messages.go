@@ -0,0 +1,48 @@
+package review
+
+import (
+	"context"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+)
+
+// ListReviewMessagesUseCase - レビューについての会話の取得のユースケース
+type ListReviewMessagesUseCase struct {
+	reviewRepo  repository.ReviewRepository
+	messageRepo repository.ReviewMessageRepository
+	enabled     bool
+}
+
+// NewListReviewMessagesUseCase - コンストラクタ
+func NewListReviewMessagesUseCase(
+	reviewRepo repository.ReviewRepository,
+	messageRepo repository.ReviewMessageRepository,
+	options ConversationOptions,
+) *ListReviewMessagesUseCase {
+	return &ListReviewMessagesUseCase{
+		reviewRepo:  reviewRepo,
+		messageRepo: messageRepo,
+		enabled:     options.Enabled,
+	}
+}
+
+// ListReviewMessagesInput - 入力
+type ListReviewMessagesInput struct {
+	ReviewID string
+	UserID   string
+}
+
+// Execute - レビューの会話を古い順に取得（自分のレビューのみ）
+func (uc *ListReviewMessagesUseCase) Execute(ctx context.Context, input ListReviewMessagesInput) ([]*model.ReviewMessage, error) {
+	if !uc.enabled {
+		return nil, model.ErrConversationDisabled
+	}
+
+	// 他のユーザーのレビューは見つからない扱い
+	if _, err := findOwnReview(ctx, uc.reviewRepo, input.UserID, input.ReviewID); err != nil {
+		return nil, err
+	}
+
+	return uc.messageRepo.ListByReviewID(ctx, input.UserID, input.ReviewID)
+}
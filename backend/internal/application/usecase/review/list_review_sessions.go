@@ -0,0 +1,71 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+)
+
+// ListReviewSessionsUseCase - 複数ファイルのレビューセッション履歴一覧取得UseCase
+type ListReviewSessionsUseCase struct {
+	sessionRepo repository.ReviewSessionRepository
+}
+
+// NewListReviewSessionsUseCase - コンストラクタ
+func NewListReviewSessionsUseCase(sessionRepo repository.ReviewSessionRepository) *ListReviewSessionsUseCase {
+	return &ListReviewSessionsUseCase{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// ListReviewSessionsInput - 入力
+type ListReviewSessionsInput struct {
+	UserID   string
+	Page     int
+	PageSize int
+}
+
+// ListReviewSessionsOutput - 出力
+type ListReviewSessionsOutput struct {
+	Items      []*model.ReviewSession
+	Total      int
+	Page       int
+	PageSize   int
+	TotalPages int
+}
+
+// Execute - 実行（新しい順）
+func (u *ListReviewSessionsUseCase) Execute(ctx context.Context, input ListReviewSessionsInput) (*ListReviewSessionsOutput, error) {
+	// デフォルト値の設定（ListReviewsUseCase と同じ）
+	if input.Page <= 0 {
+		input.Page = 1
+	}
+	if input.PageSize <= 0 {
+		input.PageSize = 10
+	}
+	if input.PageSize > 100 {
+		input.PageSize = 100
+	}
+
+	offset := (input.Page - 1) * input.PageSize
+	sessions, err := u.sessionRepo.ListByUserID(ctx, input.UserID, input.PageSize, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review sessions: %w", err)
+	}
+
+	total, err := u.sessionRepo.CountByUserID(ctx, input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count review sessions: %w", err)
+	}
+
+	return &ListReviewSessionsOutput{
+		Items:      sessions,
+		Total:      total,
+		Page:       input.Page,
+		PageSize:   input.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(input.PageSize))),
+	}, nil
+}
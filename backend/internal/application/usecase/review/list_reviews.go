@@ -24,15 +24,18 @@ func NewListReviewsUseCase(reviewRepo repository.ReviewRepository) *ListReviewsU
 
 // ListReviewsInput - 入力
 type ListReviewsInput struct {
-	UserID    string
-	Page      int
-	PageSize  int
-	Language  string
-	Status    string
-	SortBy    string
-	SortOrder string
-	DateFrom  *time.Time
-	DateTo    *time.Time
+	UserID     string
+	Page       int
+	PageSize   int
+	Language   string
+	Status     string
+	SessionID  string // 複数ファイルのレビューセッションのレビューのみ（オプショナル）
+	RerunOf    string // 指定したレビューの再レビューのみ（オプショナル）
+	FollowUpOf string // 指定したレビューの改善点を適用したコードのレビューのみ（オプショナル）
+	SortBy     string
+	SortOrder  string
+	DateFrom   *time.Time
+	DateTo     *time.Time
 }
 
 // ListReviewsOutput - 出力
@@ -76,6 +79,15 @@ func (u *ListReviewsUseCase) Execute(ctx context.Context, input ListReviewsInput
 	if input.Status != "" {
 		filters["status"] = input.Status
 	}
+	if input.SessionID != "" {
+		filters["session_id"] = input.SessionID
+	}
+	if input.RerunOf != "" {
+		filters["rerun_of"] = input.RerunOf
+	}
+	if input.FollowUpOf != "" {
+		filters["follow_up_of"] = input.FollowUpOf
+	}
 	if input.DateFrom != nil {
 		filters["date_from"] = *input.DateFrom
 	}
@@ -133,8 +145,7 @@ func (u *ListReviewsUseCase) validateInput(input ListReviewsInput) error {
 
 	// ステータスのバリデーション
 	if input.Status != "" {
-		validStatuses := []string{"success", "warning", "error", "pending"}
-		if !stringInSlice(input.Status, validStatuses) {
+		if !stringInSlice(input.Status, model.ValidReviewStatuses) {
 			return fmt.Errorf("無効なステータスです: %s", input.Status)
 		}
 	}
@@ -0,0 +1,107 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+)
+
+// ReviewPurgeUseCase - ゴミ箱に移動してから保持期間を過ぎたレビューを定期的に完全削除するユースケース
+// コード・レビュー結果などの内容と参照ナレッジの記録・会話を消し（コードに含まれていた秘密情報などをDBに残さない）、
+// 利用上限・使用量の集計のために使用量の列だけを残す
+type ReviewPurgeUseCase struct {
+	reviewRepo repository.ReviewRepository
+	retention  time.Duration
+	interval   time.Duration
+	now        func() time.Time
+
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// NewReviewPurgeUseCase - コンストラクタ
+func NewReviewPurgeUseCase(reviewRepo repository.ReviewRepository, options TrashOptions) *ReviewPurgeUseCase {
+	if options.Retention <= 0 {
+		options.Retention = defaultReviewTrashRetention
+	}
+	return &ReviewPurgeUseCase{
+		reviewRepo: reviewRepo,
+		retention:  options.Retention,
+		interval:   options.PurgeInterval,
+		now:        time.Now,
+		stop:       make(chan struct{}),
+	}
+}
+
+// Purge - 保持期間を過ぎたゴミ箱のレビューを完全削除し、件数を返す
+func (uc *ReviewPurgeUseCase) Purge(ctx context.Context) (int, error) {
+	count, err := uc.reviewRepo.PurgeDeleted(ctx, uc.now().Add(-uc.retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted reviews: %w", err)
+	}
+	return count, nil
+}
+
+// Start - 定期的な完全削除を開始する（間隔が0以下の場合・2回目以降の呼び出しは何もしない）
+func (uc *ReviewPurgeUseCase) Start() {
+	if uc.interval <= 0 {
+		return
+	}
+	uc.startOnce.Do(func() {
+		uc.wg.Add(1)
+		go uc.loop()
+	})
+}
+
+// Shutdown - 定期的な完全削除を停止し、実行中の完全削除の完了を待つ
+func (uc *ReviewPurgeUseCase) Shutdown(ctx context.Context) error {
+	uc.stopOnce.Do(func() { close(uc.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		uc.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// loop - 起動時と一定間隔ごとに完全削除する
+func (uc *ReviewPurgeUseCase) loop() {
+	defer uc.wg.Done()
+	uc.purge()
+
+	ticker := time.NewTicker(uc.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-uc.stop:
+			return
+		case <-ticker.C:
+			uc.purge()
+		}
+	}
+}
+
+// purge - 完全削除して結果をログに残す（失敗しても次の間隔で再試行する）
+func (uc *ReviewPurgeUseCase) purge() {
+	count, err := uc.Purge(context.Background())
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("Purged %d deleted reviews", count)
+	}
+}
@@ -0,0 +1,106 @@
+package review
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// RerunReviewUseCase - 過去のレビューと同じコードを、現在のナレッジ・プロンプト・モデルでレビューし直すユースケース
+// 新しいレビューに元のレビューを記録し、ナレッジやプロンプトの変更でレビュー結果が変わったかを比較できるようにする
+type RerunReviewUseCase struct {
+	reviewCode *ReviewCodeUseCase
+	jobs       *ReviewJobUseCase
+}
+
+// NewRerunReviewUseCase - コンストラクタ
+// jobs: 非同期で再レビューする場合に使う
+func NewRerunReviewUseCase(reviewCode *ReviewCodeUseCase, jobs *ReviewJobUseCase) *RerunReviewUseCase {
+	return &RerunReviewUseCase{
+		reviewCode: reviewCode,
+		jobs:       jobs,
+	}
+}
+
+// RerunReviewInput - 入力
+type RerunReviewInput struct {
+	ReviewID string
+	UserID   string
+	// Context - 追加の説明（空の場合は元のレビューの説明）
+	Context string
+	// Locale - レビュー結果の言語（空の場合は上書きした出力言語、それもなければ元のレビューの言語）
+	Locale string
+	// Overrides - ユーザー設定の代わりに使うプロバイダ・モデル・生成パラメータ・観点（省略した項目はユーザー設定）
+	Overrides *model.UserPreferences
+	// Async - 非同期のレビューとして受け付ける（結果は GET /api/v1/reviews/:id で取得する）
+	Async bool
+}
+
+// Execute - 元のレビューと同じ入力でレビューし直す（結果のキャッシュは使わない）
+func (uc *RerunReviewUseCase) Execute(ctx context.Context, input RerunReviewInput) (*model.Review, error) {
+	// 1. 元のレビューを取得（他のユーザーのレビューは見つからない扱い。完了したレビューのみ）
+	original, err := findOwnReview(ctx, uc.reviewCode.reviewRepo, input.UserID, input.ReviewID)
+	if err != nil {
+		return nil, err
+	}
+	if !original.IsCompleted() {
+		return nil, model.ErrReviewNotCompleted
+	}
+
+	// 2. 上書きする設定を検証
+	if err := uc.validateOverrides(ctx, input.UserID, input.Overrides); err != nil {
+		return nil, err
+	}
+
+	// 3. 元のレビューの入力を作成
+	rerun := inputFromReview(original)
+	rerun.ForceRefresh = true
+	rerun.RerunOf = original.ID
+	rerun.Overrides = input.Overrides
+	if input.Context != "" {
+		rerun.Context = input.Context
+	}
+	switch {
+	case input.Locale != "":
+		rerun.Locale = input.Locale
+	case input.Overrides != nil && input.Overrides.OutputLanguage != "":
+		rerun.Locale = input.Overrides.OutputLanguage
+	}
+
+	// 4. レビュー
+	if input.Async {
+		return uc.jobs.Submit(ctx, rerun)
+	}
+	output, err := uc.reviewCode.Execute(ctx, rerun)
+	if err != nil {
+		return nil, err
+	}
+	return output.Review, nil
+}
+
+// validateOverrides - 上書きする設定の値の範囲と、プロバイダ・モデルが使えるかを検証する
+// ユーザー設定のプロバイダは使えない場合に既定のプロバイダでレビューするが、
+// 再レビューでは指定したモデルで比較できないため、使えない場合はエラーにする
+func (uc *RerunReviewUseCase) validateOverrides(ctx context.Context, userID string, overrides *model.UserPreferences) error {
+	if overrides == nil {
+		return nil
+	}
+	overrides.Normalize()
+	if err := overrides.Validate(); err != nil {
+		return err
+	}
+	if !overrides.HasModelOverride() || uc.reviewCode.preferences.Providers == nil {
+		return nil
+	}
+
+	prefs := uc.reviewCode.loadPreferences(ctx, userID).WithOverrides(overrides)
+	providerName := prefs.LLMProvider
+	if providerName == "" {
+		providerName = uc.reviewCode.llmProvider.Name()
+	}
+	if _, err := uc.reviewCode.preferences.Providers(providerName, prefs.LLMModel); err != nil {
+		return fmt.Errorf("%w: %s", model.ErrPreferenceProviderUnavailable, providerName)
+	}
+	return nil
+}
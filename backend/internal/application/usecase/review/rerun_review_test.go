@@ -0,0 +1,188 @@
+package review_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
+	"github.com/s7r8/reviewapp/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRerunReviewUseCase_Execute(t *testing.T) {
+	newUseCase := func(repo *testutil.MockReviewRepository, llm external.LLMProvider, providers external.LLMProviderFactory) *review.RerunReviewUseCase {
+		reviewCode := review.NewReviewCodeUseCase(
+			repo,
+			testutil.NewMockKnowledgeRepository(),
+			service.NewReviewService(),
+			llm,
+			testutil.NewMockEmbeddingClient(),
+			testutil.NewMockPromptTemplateRepository(),
+			service.NewTemplatePromptRenderer(),
+			service.NewPriceTable(service.DefaultPriceCurrency, service.DefaultModelPrices()),
+			nil,
+			review.ChunkingOptions{},
+			review.CacheOptions{},
+			review.PreferenceOptions{Providers: providers},
+		)
+		return review.NewRerunReviewUseCase(reviewCode, review.NewReviewJobUseCase(reviewCode, review.ReviewJobUseCaseOptions{}))
+	}
+	newOriginal := func(t *testing.T, repo *testutil.MockReviewRepository) *model.Review {
+		original := model.NewReview("test-user-id", "func main() {}", "Go", "CLIの入口")
+		original.SetLocale(model.LocaleEnglish)
+		original.SetFile("cmd/main.go")
+		original.SetReviewResult("old result", &model.StructuredReviewResult{Summary: "old"}, []string{"k-1"}, "mock", "mock-model", 100)
+		require.NoError(t, repo.Create(context.Background(), original))
+		return original
+	}
+
+	t.Run("元のレビューと同じ入力でレビューし直し、元のレビューを記録する", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		client := testutil.NewMockClaudeClient()
+		uc := newUseCase(repo, client, nil)
+		original := newOriginal(t, repo)
+
+		rerun, err := uc.Execute(context.Background(), review.RerunReviewInput{ReviewID: original.ID, UserID: "test-user-id"})
+		require.NoError(t, err)
+
+		assert.NotEqual(t, original.ID, rerun.ID)
+		require.NotNil(t, rerun.RerunOfReviewID)
+		assert.Equal(t, original.ID, *rerun.RerunOfReviewID)
+		assert.Equal(t, "cmd/main.go", rerun.FilePath)
+		assert.Equal(t, model.LocaleEnglish, rerun.Locale)
+		assert.False(t, rerun.CacheHit)
+		require.Len(t, client.Inputs(), 1)
+		assert.Equal(t, "func main() {}", client.Inputs()[0].Code)
+		assert.Equal(t, "CLIの入口", client.Inputs()[0].Context)
+	})
+
+	t.Run("上書きしたプロバイダ・モデル・生成パラメータでレビューする", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		defaultLLM := testutil.NewMockClaudeClient()
+		preferred := newNamedLLMProvider("openai", "gpt-4o")
+		var requested []string
+		uc := newUseCase(repo, defaultLLM, func(provider, modelName string) (external.LLMProvider, error) {
+			requested = append(requested, provider+":"+modelName)
+			return preferred, nil
+		})
+		original := newOriginal(t, repo)
+		temperature := 0.3
+
+		rerun, err := uc.Execute(context.Background(), review.RerunReviewInput{
+			ReviewID:  original.ID,
+			UserID:    "test-user-id",
+			Overrides: &model.UserPreferences{LLMProvider: " openai ", LLMModel: "gpt-4o", Temperature: &temperature},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "openai", rerun.LLMProvider)
+		assert.Empty(t, defaultLLM.Inputs())
+		require.Len(t, preferred.Inputs(), 1)
+		assert.Equal(t, 0.3, *preferred.Inputs()[0].Temperature)
+		assert.Contains(t, requested, "openai:gpt-4o")
+	})
+
+	t.Run("上書きしたプロバイダを使えない・設定が不正な場合はレビューしない", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		client := testutil.NewMockClaudeClient()
+		uc := newUseCase(repo, client, func(provider, modelName string) (external.LLMProvider, error) {
+			return nil, errors.New("unsupported LLM provider")
+		})
+		original := newOriginal(t, repo)
+		temperature := 1.5
+
+		_, err := uc.Execute(context.Background(), review.RerunReviewInput{
+			ReviewID: original.ID, UserID: "test-user-id", Overrides: &model.UserPreferences{LLMProvider: "unknown"},
+		})
+		assert.ErrorIs(t, err, model.ErrPreferenceProviderUnavailable)
+		_, err = uc.Execute(context.Background(), review.RerunReviewInput{
+			ReviewID: original.ID, UserID: "test-user-id", Overrides: &model.UserPreferences{Temperature: &temperature},
+		})
+		assert.ErrorIs(t, err, model.ErrPreferenceTemperatureInvalid)
+		assert.Empty(t, client.Inputs())
+	})
+
+	t.Run("非同期の場合は元のレビューを記録したジョブを受け付ける", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		client := testutil.NewMockClaudeClient()
+		uc := newUseCase(repo, client, nil)
+		original := newOriginal(t, repo)
+
+		job, err := uc.Execute(context.Background(), review.RerunReviewInput{ReviewID: original.ID, UserID: "test-user-id", Async: true})
+		require.NoError(t, err)
+
+		assert.Equal(t, model.ReviewStatusPending, job.Status)
+		require.NotNil(t, job.RerunOfReviewID)
+		assert.Equal(t, original.ID, *job.RerunOfReviewID)
+		require.NotNil(t, job.JobOptions)
+		assert.True(t, job.JobOptions.ForceRefresh)
+		assert.Empty(t, client.Inputs())
+	})
+
+	t.Run("他のユーザーのレビュー・完了していないレビューはレビューし直せない", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		uc := newUseCase(repo, testutil.NewMockClaudeClient(), nil)
+		original := newOriginal(t, repo)
+		pending := model.NewReviewJob("test-user-id", "func main() {}", "Go", "", model.ReviewJobOptions{})
+		require.NoError(t, repo.Create(context.Background(), pending))
+
+		_, err := uc.Execute(context.Background(), review.RerunReviewInput{ReviewID: original.ID, UserID: "other-user-id"})
+		assert.ErrorIs(t, err, model.ErrReviewNotFound)
+		_, err = uc.Execute(context.Background(), review.RerunReviewInput{ReviewID: pending.ID, UserID: "test-user-id"})
+		assert.ErrorIs(t, err, model.ErrReviewNotCompleted)
+	})
+}
+
+func TestDiffReviewsUseCase_Execute(t *testing.T) {
+	newReview := func(t *testing.T, repo *testutil.MockReviewRepository, knowledgeIDs []string, improvements ...model.Improvement) *model.Review {
+		r := model.NewReview("test-user-id", "func main() {}", "Go", "")
+		r.SetReviewResult("result", &model.StructuredReviewResult{Summary: "summary", Improvements: improvements}, knowledgeIDs, "mock", "mock-model", 100)
+		require.NoError(t, repo.Create(context.Background(), r))
+		return r
+	}
+
+	t.Run("参照したナレッジと改善点の変化を返す", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		base := newReview(t, repo, []string{"k-1", "k-2"},
+			model.Improvement{Title: "エラーを握りつぶしている", Description: "返す", Severity: "medium"},
+			model.Improvement{Title: "マジックナンバー", Description: "定数にする", Severity: "low"},
+		)
+		other := newReview(t, repo, []string{"k-2", "k-3"},
+			model.Improvement{Title: "エラーを握りつぶしている", Description: "返す", Severity: "high"},
+		)
+
+		output, err := review.NewDiffReviewsUseCase(repo).Execute(context.Background(), review.DiffReviewsInput{
+			ReviewID: base.ID, OtherReviewID: other.ID, UserID: "test-user-id",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, base.ID, output.Base.ID)
+		assert.Equal(t, other.ID, output.Other.ID)
+		assert.Equal(t, []string{"k-3"}, output.KnowledgeAdded)
+		assert.Equal(t, []string{"k-1"}, output.KnowledgeRemoved)
+		assert.Empty(t, output.Improvements.Added)
+		require.Len(t, output.Improvements.Removed, 1)
+		assert.Equal(t, "マジックナンバー", output.Improvements.Removed[0].Title)
+		require.Len(t, output.Improvements.Persisting, 1)
+		assert.True(t, output.Improvements.Persisting[0].SeverityChanged)
+	})
+
+	t.Run("他のユーザーのレビュー・構造化されたレビュー結果がないレビューは比較できない", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		base := newReview(t, repo, nil)
+		unstructured := model.NewReview("test-user-id", "func main() {}", "Go", "")
+		unstructured.SetReviewResult("result", nil, nil, "mock", "mock-model", 100)
+		require.NoError(t, repo.Create(context.Background(), unstructured))
+		uc := review.NewDiffReviewsUseCase(repo)
+
+		_, err := uc.Execute(context.Background(), review.DiffReviewsInput{ReviewID: base.ID, OtherReviewID: base.ID, UserID: "other-user-id"})
+		assert.ErrorIs(t, err, model.ErrReviewNotFound)
+		_, err = uc.Execute(context.Background(), review.DiffReviewsInput{ReviewID: base.ID, OtherReviewID: unstructured.ID, UserID: "test-user-id"})
+		assert.ErrorIs(t, err, model.ErrReviewNoStructuredResult)
+	})
+}
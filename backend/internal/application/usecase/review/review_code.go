@@ -2,8 +2,11 @@ package review
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
+	"time"
 
 	"github.com/s7r8/reviewapp/internal/domain/model"
 	"github.com/s7r8/reviewapp/internal/domain/repository"
@@ -14,27 +17,85 @@ import (
 
 // ReviewCodeUseCase - コードレビューのユースケース
 type ReviewCodeUseCase struct {
-	reviewRepo      repository.ReviewRepository
-	knowledgeRepo   repository.KnowledgeRepository
-	reviewService   *service.ReviewService
-	claudeClient    external.ClaudeClientInterface
-	embeddingClient external.EmbeddingClientInterface
+	reviewRepo         repository.ReviewRepository
+	knowledgeRepo      repository.KnowledgeRepository
+	reviewService      *service.ReviewService
+	llmProvider        external.LLMProvider
+	embeddingClient    external.EmbeddingClientInterface
+	promptTemplateRepo repository.PromptTemplateRepository
+	promptRenderer     service.PromptRenderer
+	priceTable         *service.PriceTable
+	quotaService       *service.QuotaService
+	chunking           ChunkingOptions
+	cache              CacheOptions
+	preferences        PreferenceOptions
+	now                func() time.Time
 }
 
+// PreferenceOptions - ユーザー設定（users.preferences）をレビューに反映する設定
+type PreferenceOptions struct {
+	Users     repository.UserRepository   // nil の場合はユーザー設定を使わない
+	Providers external.LLMProviderFactory // 設定されたプロバイダ・モデルのクライアントを生成（nil の場合は既定のプロバイダのみ）
+}
+
+// CacheOptions - レビュー結果のキャッシュ設定
+type CacheOptions struct {
+	TTL time.Duration // 同じ入力のレビュー結果を再利用する期間（0以下はキャッシュしない）
+}
+
+// ChunkingOptions - 大きなファイルを分割してレビューする設定
+type ChunkingOptions struct {
+	MaxTokens    int // 1回のレビュー・Embeddingに含めるコードの最大トークン数（0以下は既定値）
+	Concurrency  int // 並行してレビューするチャンク数（0以下は既定値）
+	MaxDiffHunks int // 差分のレビューでレビューする最大ハンク数（0以下は既定値）
+}
+
+// 分割レビューの既定値（MaxTokens は Embedding API の入力上限 8191 トークンに収まる値にする）
+const (
+	defaultChunkMaxTokens   = 6000
+	defaultChunkConcurrency = 4
+	defaultMaxDiffHunks     = 50
+)
+
 // NewReviewCodeUsecase - コンストラクタ
 func NewReviewCodeUseCase(
 	reviewRepo repository.ReviewRepository,
 	knowledgeRepo repository.KnowledgeRepository,
 	reviewService *service.ReviewService,
-	claudeClient external.ClaudeClientInterface,
+	llmProvider external.LLMProvider,
 	embeddingClient external.EmbeddingClientInterface,
+	promptTemplateRepo repository.PromptTemplateRepository,
+	promptRenderer service.PromptRenderer,
+	priceTable *service.PriceTable,
+	quotaService *service.QuotaService,
+	chunking ChunkingOptions,
+	cache CacheOptions,
+	preferences PreferenceOptions,
 ) *ReviewCodeUseCase {
+	if chunking.MaxTokens <= 0 {
+		chunking.MaxTokens = defaultChunkMaxTokens
+	}
+	if chunking.Concurrency <= 0 {
+		chunking.Concurrency = defaultChunkConcurrency
+	}
+	if chunking.MaxDiffHunks <= 0 {
+		chunking.MaxDiffHunks = defaultMaxDiffHunks
+	}
+
 	return &ReviewCodeUseCase{
-		reviewRepo:      reviewRepo,
-		knowledgeRepo:   knowledgeRepo,
-		reviewService:   reviewService,
-		claudeClient:    claudeClient,
-		embeddingClient: embeddingClient,
+		reviewRepo:         reviewRepo,
+		knowledgeRepo:      knowledgeRepo,
+		reviewService:      reviewService,
+		llmProvider:        llmProvider,
+		embeddingClient:    embeddingClient,
+		promptTemplateRepo: promptTemplateRepo,
+		promptRenderer:     promptRenderer,
+		priceTable:         priceTable,
+		quotaService:       quotaService,
+		chunking:           chunking,
+		cache:              cache,
+		preferences:        preferences,
+		now:                time.Now,
 	}
 }
 
@@ -44,6 +105,35 @@ type ReviewCodeInput struct {
 	Code     string
 	Language string
 	Context  string // オプショナル
+	// ForceRefresh - キャッシュがあっても使わずにLLMでレビューし直す
+	ForceRefresh bool
+	// Locale - プロンプト・レビュー結果の見出し・カテゴリ名の言語（ja / en）
+	// 空の場合はユーザー設定の出力言語、それもなければ model.DefaultLocale
+	Locale string
+	// FilePath - レビューするファイルのパス（オプショナル。履歴・ダッシュボードの表示に使う）
+	FilePath string
+	// SessionID - 複数ファイルをまとめてレビューする場合のセッション（ReviewSessionUseCase が設定する）
+	SessionID string
+	// Diff - unified diff（Code の代わりに指定する）。追加・変更したハンクのみをレビューし、
+	// 改善点に対象のファイルと変更後の行範囲を設定する
+	Diff string
+	// ContextFiles - 差分のレビューで参照する変更後のファイル全体（オプショナル）
+	ContextFiles []ContextFile
+	// Job - 非同期のレビューを実行する場合のジョブ（ReviewJobUseCase が設定する）
+	// レビュー結果はジョブのIDで保存し、ジョブを完了にする
+	Job *model.Review
+	// RerunOf - 再レビューの元のレビュー（RerunReviewUseCase が設定する）
+	RerunOf string
+	// Overrides - ユーザー設定の代わりに使うプロバイダ・モデル・生成パラメータ（再レビューのみ）
+	Overrides *model.UserPreferences
+	// FollowUpOf - 改善点を適用したコードのレビューの場合、適用元のレビュー（ApplyImprovementsUseCase が設定する）
+	FollowUpOf string
+}
+
+// ContextFile - 差分のレビューで参照するファイル
+type ContextFile struct {
+	Path string // 差分の変更後のパスと同じパス
+	Code string // 変更後のファイル全体
 }
 
 // ReviewCodeOutput - 出力
@@ -53,20 +143,124 @@ type ReviewCodeOutput struct {
 
 // Execute - コードレビューを実行
 func (uc *ReviewCodeUseCase) Execute(ctx context.Context, input ReviewCodeInput) (*ReviewCodeOutput, error) {
-	// 1. コードからEmbeddingを生成
-	embeddingText := fmt.Sprintf("Language: %s\n\n%s", input.Language, input.Code)
-	if input.Context != "" {
-		embeddingText += fmt.Sprintf("\n\nContext: %s", input.Context)
+	return uc.execute(ctx, input, nil)
+}
+
+// ExecuteStream - コードレビューを実行し、生成中のテキストをonDeltaに逐次渡す
+// プロバイダがストリーミングに対応していない場合、またはコードを分割してレビューした場合は、生成完了後に全文を1回で渡す
+func (uc *ReviewCodeUseCase) ExecuteStream(ctx context.Context, input ReviewCodeInput, onDelta func(text string)) (*ReviewCodeOutput, error) {
+	if onDelta == nil {
+		onDelta = func(string) {}
+	}
+	return uc.execute(ctx, input, onDelta)
+}
+
+// CheckQuota - 利用上限に達していれば *model.QuotaExceededError を返す（quotaService が nil の場合は無制限）
+func (uc *ReviewCodeUseCase) CheckQuota(ctx context.Context, userID string) error {
+	if uc.quotaService == nil {
+		return nil
+	}
+	return uc.quotaService.Check(ctx, userID)
+}
+
+// reserveQuota - 受け付けた非同期のレビュー1件分を利用上限のレビュー数に加算（失敗してもレビューは受け付ける）
+func (uc *ReviewCodeUseCase) reserveQuota(ctx context.Context, job *model.Review) {
+	if uc.quotaService == nil {
+		return
+	}
+	if err := uc.quotaService.Reserve(ctx, job); err != nil {
+		log.Printf("Warning: failed to reserve quota for review job %s: %v", job.ID, err)
+	}
+}
+
+// releaseQuota - 中止した非同期のレビュー1件分を利用上限のレビュー数から戻す
+func (uc *ReviewCodeUseCase) releaseQuota(ctx context.Context, job *model.Review) {
+	if uc.quotaService == nil {
+		return
+	}
+	if err := uc.quotaService.Release(ctx, job); err != nil {
+		log.Printf("Warning: failed to release quota of review job %s: %v", job.ID, err)
+	}
+}
+
+// execute - ナレッジを取得してレビューを実行
+func (uc *ReviewCodeUseCase) execute(ctx context.Context, input ReviewCodeInput, onDelta func(text string)) (*ReviewCodeOutput, error) {
+	// 0. バリデーション（差分の場合は言語とファイルのパスを差分から補う）
+	input, err := uc.prepareDiff(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.validate(input); err != nil {
+		return nil, err
+	}
+
+	// LLMを呼び出す前に利用上限を確認
+	// 非同期のレビューは受け付け・再実行の時点で確認し、レビュー数に加算済み（ここで確認すると自分自身で上限に達するため確認しない）
+	if input.Job == nil {
+		if err := uc.CheckQuota(ctx, input.UserID); err != nil {
+			return nil, err
+		}
 	}
 
-	embedding, err := uc.embeddingClient.GenerateEmbedding(ctx, embeddingText)
+	// Embeddingの消費トークン数をコスト計算のために集計する
+	ctx, embeddingUsage := external.WithEmbeddingUsageRecorder(ctx)
+
+	// 1. コードを分割し、関連ナレッジを取得
+	chunks, knowledges, err := uc.splitAndRetrieve(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. ユーザー設定のモデル・生成パラメータ・出力言語でレビュー
+	prefs := uc.loadPreferences(ctx, input.UserID).WithOverrides(input.Overrides)
+	input.Locale = resolveLocale(input.Locale, prefs)
+	return uc.reviewWithKnowledge(ctx, input, chunks, knowledges, prefs, embeddingUsage, onDelta)
+}
+
+// splitAndRetrieve - トークン数の上限を超えるコードを分割し、関連ナレッジを取得する
+// 差分の場合は追加・変更したハンクごとに分割し、ナレッジはハンクの変更後のコードで検索する
+func (uc *ReviewCodeUseCase) splitAndRetrieve(ctx context.Context, input ReviewCodeInput) ([]service.CodeChunk, []*model.Knowledge, error) {
+	// 1. トークン数の上限を超えるコードは関数・クラスの境界で分割
+	var chunks []service.CodeChunk
+	if input.Diff != "" {
+		var err error
+		if chunks, err = uc.diffChunks(input); err != nil {
+			return nil, nil, err
+		}
+		log.Printf("Reviewing %d changed hunks of the diff", len(chunks))
+	} else {
+		chunks = service.SplitCode(input.Code, input.Language, uc.chunking.MaxTokens)
+		if len(chunks) > 1 {
+			log.Printf("Splitting code into %d chunks for review (max %d tokens per chunk)", len(chunks), uc.chunking.MaxTokens)
+		}
+	}
+
+	// 2. 関連ナレッジを取得（RAG: Retrieval）
+	var knowledges []*model.Knowledge
+	var err error
+	if len(chunks) > 1 || input.Diff != "" {
+		knowledges, err = uc.retrieveKnowledgeForChunks(ctx, input, chunks)
+	} else {
+		knowledges, err = uc.retrieveKnowledge(ctx, input)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return chunks, knowledges, nil
+}
+
+// retrieveKnowledge - ベクトル類似度検索で関連ナレッジを取得
+func (uc *ReviewCodeUseCase) retrieveKnowledge(ctx context.Context, input ReviewCodeInput) ([]*model.Knowledge, error) {
+	// 1. コードからEmbeddingを生成
+	embedding, err := uc.embeddingClient.GenerateEmbedding(ctx, buildEmbeddingText(input.Language, input.Code, input.Context))
 	if err != nil {
 		// Embeddingエラーの場合、全ナレッジ取得にフォールバック
 		log.Printf("Warning: failed to generate embedding, falling back to all knowledge: %v", err)
-		return uc.executeWithAllKnowledge(ctx, input)
+		return uc.findAllKnowledge(ctx, input)
 	}
 
-	// 2. ベクトル類似度検索で関連ナレッジを取得（RAG: Retrieval）
+	// 2. ベクトル類似度検索で関連ナレッジを取得
 	knowledges, err := uc.knowledgeRepo.SearchBySimilarity(
 		ctx,
 		input.UserID,
@@ -85,51 +279,90 @@ func (uc *ReviewCodeUseCase) Execute(ctx context.Context, input ReviewCodeInput)
 		log.Printf("Found %d relevant knowledge items for review", len(knowledges))
 	}
 
-	// 3. プロンプト生成（RAG: Augmented）
-	knowledgePrompt, usedKnowledges := uc.reviewService.BuildPromptFromKnowledge(knowledges)
+	return knowledges, nil
+}
 
-	// 4. LLMでレビュー生成（RAG: Generation）
-	reviewResult, err := uc.claudeClient.ReviewCode(ctx, external.ReviewCodeInput{
-		Code:            input.Code,
-		Language:        input.Language,
-		Context:         input.Context,
-		KnowledgePrompt: knowledgePrompt,
-	})
+// retrieveKnowledgeForChunks - チャンクごとにベクトル類似度検索を行い、結果を合わせる
+// Embedding API の入力上限を超えないよう、ファイル全体ではなくチャンク単位でEmbeddingを生成する
+func (uc *ReviewCodeUseCase) retrieveKnowledgeForChunks(ctx context.Context, input ReviewCodeInput, chunks []service.CodeChunk) ([]*model.Knowledge, error) {
+	// 1. チャンクごとのEmbeddingをまとめて生成
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		code := chunk.Code
+		if chunk.NewCode != "" {
+			code = chunk.NewCode
+		}
+		texts[i] = buildEmbeddingText(input.Language, code, input.Context)
+	}
+
+	embeddings, err := uc.embeddingClient.GenerateEmbeddings(ctx, texts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to review code: %w", err)
+		// Embeddingエラーの場合、全ナレッジ取得にフォールバック
+		log.Printf("Warning: failed to generate embeddings for chunks, falling back to all knowledge: %v", err)
+		return uc.findAllKnowledge(ctx, input)
 	}
 
-	// 5. マークダウンを構造化データに変換
-	structuredResult := parser.ParseReviewMarkdown(reviewResult.ReviewResult)
+	// 2. チャンクごとに検索し、同じナレッジは類似度が最も高いものを残す
+	byID := map[string]*model.Knowledge{}
+	var knowledges []*model.Knowledge
+	for _, embedding := range embeddings {
+		found, err := uc.knowledgeRepo.SearchBySimilarity(ctx, input.UserID, embedding, 10, 0.35)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search knowledge by similarity: %w", err)
+		}
+		for _, k := range found {
+			existing, ok := byID[k.ID]
+			if !ok {
+				byID[k.ID] = k
+				knowledges = append(knowledges, k)
+				continue
+			}
+			if k.RelevanceScore != nil && (existing.RelevanceScore == nil || *k.RelevanceScore > *existing.RelevanceScore) {
+				existing.RelevanceScore = k.RelevanceScore
+			}
+		}
+	}
 
-	// 6. レビューエンティティを作成
-	review := model.NewReview(
-		input.UserID,
-		input.Code,
-		input.Language,
-		input.Context,
-	)
+	log.Printf("Found %d relevant knowledge items across %d chunks", len(knowledges), len(chunks))
 
-	// 7. レビュー結果を設定（実際に使用したナレッジIDのみ記録）
-	knowledgeIDs := extractKnowledgeIDs(usedKnowledges)
-	review.SetReviewResult(
-		reviewResult.ReviewResult,
-		structuredResult,
-		knowledgeIDs,
-		"claude",                     // LLM Provider
-		"claude-3-5-sonnet-20241022", // LLM Model (TODO: configから取得)
-		reviewResult.TokensUsed,
-	)
+	return knowledges, nil
+}
 
-	// 8. レビュー結果を保存
-	if err := uc.reviewRepo.Create(ctx, review); err != nil {
-		return nil, fmt.Errorf("failed to save review: %w", err)
+// buildEmbeddingText - Embedding生成用のテキストを組み立てる
+func buildEmbeddingText(language, code, userContext string) string {
+	text := fmt.Sprintf("Language: %s\n\n%s", language, code)
+	if userContext != "" {
+		text += fmt.Sprintf("\n\nContext: %s", userContext)
 	}
+	return text
+}
 
-	// 9. ナレッジの使用カウントを更新（実際に使用したナレッジのみ）
-	if err := uc.updateKnowledgeUsage(ctx, usedKnowledges); err != nil {
-		// 更新失敗してもレビュー結果は返す
-		log.Printf("Warning: failed to update knowledge usage: %v", err)
+// findAllKnowledge - Embedding生成失敗時のフォールバック処理
+func (uc *ReviewCodeUseCase) findAllKnowledge(ctx context.Context, input ReviewCodeInput) ([]*model.Knowledge, error) {
+	// 全ナレッジを取得
+	knowledges, err := uc.knowledgeRepo.FindByUserID(ctx, input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find knowledge: %w", err)
+	}
+	return knowledges, nil
+}
+
+// reviewWithKnowledge - 取得したナレッジを使ってレビューを生成し、保存する
+func (uc *ReviewCodeUseCase) reviewWithKnowledge(ctx context.Context, input ReviewCodeInput, chunks []service.CodeChunk, knowledges []*model.Knowledge, prefs *model.UserPreferences, embeddingUsage *external.EmbeddingUsageRecorder, onDelta func(text string)) (*ReviewCodeOutput, error) {
+	// 1. レビュー結果を生成（キャッシュがあれば再利用）
+	review, usedKnowledges, err := uc.buildReview(ctx, input, chunks, knowledges, prefs, embeddingUsage, onDelta)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 保存して、ナレッジの使用カウントと利用上限の消費を記録
+	if err := uc.saveReview(ctx, review, usedKnowledges); err != nil {
+		return nil, err
+	}
+
+	// キャッシュを再利用した場合はLLMが生成していないため、保存後に全文を1回で渡す
+	if review.CacheHit && onDelta != nil {
+		onDelta(review.ReviewResult)
 	}
 
 	return &ReviewCodeOutput{
@@ -137,74 +370,549 @@ func (uc *ReviewCodeUseCase) Execute(ctx context.Context, input ReviewCodeInput)
 	}, nil
 }
 
-// updateKnowledgeUsage - ナレッジの使用カウントと最終使用日時を更新
-func (uc *ReviewCodeUseCase) updateKnowledgeUsage(ctx context.Context, knowledges []*model.Knowledge) error {
-	for _, k := range knowledges {
-		k.IncrementUsage()
-		if err := uc.knowledgeRepo.Update(ctx, k); err != nil {
-			return fmt.Errorf("failed to update knowledge %s: %w", k.ID, err)
-		}
+// buildReview - プロンプトを生成してレビューし、保存前のレビューエンティティと実際に使用したナレッジを返す
+func (uc *ReviewCodeUseCase) buildReview(ctx context.Context, input ReviewCodeInput, chunks []service.CodeChunk, knowledges []*model.Knowledge, prefs *model.UserPreferences, embeddingUsage *external.EmbeddingUsageRecorder, onDelta func(text string)) (*model.Review, []*model.Knowledge, error) {
+	// 1. プロンプト生成（RAG: Augmented）
+	knowledgePrompt, usedKnowledges := uc.reviewService.BuildPromptFromKnowledge(knowledges, input.Locale)
+	promptTemplate, reviewInstructions, err := uc.renderReviewInstructions(ctx, input, usedKnowledges, prefs)
+	if err != nil {
+		return nil, nil, err
 	}
-	return nil
-}
+	provider := uc.selectProvider(prefs)
 
-// executeWithAllKnowledge - Embedding生成失敗時のフォールバック処理
-func (uc *ReviewCodeUseCase) executeWithAllKnowledge(ctx context.Context, input ReviewCodeInput) (*ReviewCodeOutput, error) {
-	// 全ナレッジを取得
-	knowledges, err := uc.knowledgeRepo.FindByUserID(ctx, input.UserID)
+	// 2. 同じ入力・ナレッジ・プロンプト・モデルのレビュー結果があれば再利用する
+	cacheKey := reviewCacheKey(input, usedKnowledges, promptTemplate, knowledgePrompt+reviewInstructions, provider, prefs)
+	cached, err := uc.findCachedReview(ctx, input, cacheKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find knowledge: %w", err)
+		return nil, nil, err
+	}
+	if cached != nil {
+		return uc.reuseCachedReview(input, cached, embeddingUsage), usedKnowledges, nil
 	}
 
-	// プロンプト生成
-	knowledgePrompt, usedKnowledges := uc.reviewService.BuildPromptFromKnowledge(knowledges)
-
-	// LLMでレビュー生成
-	reviewResult, err := uc.claudeClient.ReviewCode(ctx, external.ReviewCodeInput{
-		Code:            input.Code,
-		Language:        input.Language,
-		Context:         input.Context,
-		KnowledgePrompt: knowledgePrompt,
-	})
+	// 3. LLMでレビュー生成（RAG: Generation）。分割した場合はチャンクごとにレビューしてまとめる
+	llmInput := newLLMInput(input, knowledgePrompt, reviewInstructions, prefs)
+	reviewResult, err := uc.generate(ctx, provider, llmInput, chunks, onDelta)
 	if err != nil {
-		return nil, fmt.Errorf("failed to review code: %w", err)
+		return nil, nil, fmt.Errorf("failed to review code: %w", err)
 	}
 
-	// マークダウンを構造化データに変換
-	structuredResult := parser.ParseReviewMarkdown(reviewResult.ReviewResult)
+	// 4. レビューエンティティを作成（トークン数の内訳はEmbeddingを含む）
+	return uc.newReview(input, reviewResult, provider, usedKnowledges, promptTemplate, cacheKey, embeddingUsage), usedKnowledges, nil
+}
+
+// newReview - LLMの出力からレビューエンティティを作成
+// embeddingUsage が nil の場合、Embeddingのトークン数は含めない（複数モデルのレビューで重複して数えないため）
+func (uc *ReviewCodeUseCase) newReview(input ReviewCodeInput, reviewResult *external.ReviewCodeOutput, provider external.LLMProvider, usedKnowledges []*model.Knowledge, promptTemplate *model.PromptTemplate, cacheKey string, embeddingUsage *external.EmbeddingUsageRecorder) *model.Review {
+	// 1. 構造化データとマークダウンを揃える
+	reviewResult = completeReviewResult(reviewResult, input.Language, input.Locale)
 
-	// レビューエンティティを作成
 	review := model.NewReview(
 		input.UserID,
-		input.Code,
+		reviewedCode(input),
 		input.Language,
 		input.Context,
 	)
+	review.SetLocale(input.Locale)
+	setReviewFile(review, input)
+	review.SetStaticAnalysis(analyzeInput(input))
 
-	// レビュー結果を設定
+	// 2. レビュー結果を設定（実際に使用したナレッジIDと、実際に応答したプロバイダ・モデルを記録）
+	providerName := reviewResult.Provider
+	if providerName == "" {
+		providerName = provider.Name()
+	}
 	knowledgeIDs := extractKnowledgeIDs(usedKnowledges)
 	review.SetReviewResult(
 		reviewResult.ReviewResult,
-		structuredResult,
+		reviewResult.Structured,
 		knowledgeIDs,
-		"claude",
-		"claude-3-5-sonnet-20241022",
+		providerName,
+		reviewResult.Model,
 		reviewResult.TokensUsed,
 	)
+	review.SetResultSource(reviewResult.ResultSource)
+	review.SetPromptTemplate(promptTemplate)
+	review.SetCacheKey(cacheKey)
+
+	// 3. トークン数の内訳とコストを記録
+	usage := reviewResult.Usage
+	embeddingModel := ""
+	if embeddingUsage != nil {
+		usage.EmbeddingTokens = embeddingUsage.Tokens()
+		embeddingModel = embeddingUsage.Model()
+	}
+	review.SetUsage(
+		usage,
+		uc.priceTable.Cost(reviewResult.Model, embeddingModel, usage),
+		uc.priceTable.Currency(),
+	)
+
+	return review
+}
+
+// reviewCacheKey - 入力・ナレッジ・プロンプト・モデル・ユーザー設定からレビュー結果のキャッシュキーを生成
+// prompt は LLM に渡すナレッジのプロンプトとレビュー方針（類似度など描画した値の違いを区別するため）
+func reviewCacheKey(input ReviewCodeInput, usedKnowledges []*model.Knowledge, promptTemplate *model.PromptTemplate, prompt string, provider external.LLMProvider, prefs *model.UserPreferences) string {
+	return service.ReviewCacheKey(service.ReviewCacheKeyInput{
+		Code:           input.Code,
+		Diff:           input.Diff,
+		ContextFiles:   contextFileMap(input.ContextFiles),
+		Language:       input.Language,
+		Context:        input.Context,
+		Knowledge:      usedKnowledges,
+		PromptTemplate: promptTemplate,
+		Prompt:         prompt,
+		Provider:       provider.Name(),
+		Model:          provider.Model(),
+		Preferences:    prefs,
+		Locale:         input.Locale,
+	})
+}
+
+// loadPreferences - ユーザー設定を読み込む（読み込めない場合は設定なしとしてレビューを続ける）
+func (uc *ReviewCodeUseCase) loadPreferences(ctx context.Context, userID string) *model.UserPreferences {
+	if uc.preferences.Users == nil {
+		return &model.UserPreferences{}
+	}
+
+	user, err := uc.preferences.Users.FindByID(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, model.ErrUserNotFound) {
+			log.Printf("Warning: failed to find user preferences: %v", err)
+		}
+		return &model.UserPreferences{}
+	}
+	prefs, err := user.GetPreferences()
+	if err != nil {
+		log.Printf("Warning: ignoring invalid preferences of user %s: %v", userID, err)
+		return &model.UserPreferences{}
+	}
+	return prefs
+}
+
+// resolveLocale - リクエストのロケール → ユーザー設定の出力言語 → 既定のロケールの順に決める
+func resolveLocale(locale string, prefs *model.UserPreferences) string {
+	if prefs == nil {
+		return model.ResolveLocale(locale)
+	}
+	return model.ResolveLocale(locale, prefs.OutputLanguage)
+}
+
+// selectProvider - ユーザー設定のプロバイダ・モデルを返す（指定がない・生成できない場合は既定のプロバイダ）
+func (uc *ReviewCodeUseCase) selectProvider(prefs *model.UserPreferences) external.LLMProvider {
+	if !prefs.HasModelOverride() || uc.preferences.Providers == nil {
+		return uc.llmProvider
+	}
+
+	// モデルのみ指定した場合は既定のプロバイダで使う
+	providerName := prefs.LLMProvider
+	if providerName == "" {
+		providerName = uc.llmProvider.Name()
+	}
+	provider, err := uc.preferences.Providers(providerName, prefs.LLMModel)
+	if err != nil {
+		log.Printf("Warning: preferred LLM provider %s is unavailable, using default: %v", providerName, err)
+		return uc.llmProvider
+	}
+	return provider
+}
 
-	// レビュー結果を保存
-	if err := uc.reviewRepo.Create(ctx, review); err != nil {
-		return nil, fmt.Errorf("failed to save review: %w", err)
+// newLLMInput - LLMへの入力を作成（ユーザー設定の生成パラメータを含める）
+func newLLMInput(input ReviewCodeInput, knowledgePrompt, reviewInstructions string, prefs *model.UserPreferences) external.ReviewCodeInput {
+	llmInput := external.ReviewCodeInput{
+		Code:               input.Code,
+		Language:           input.Language,
+		Context:            input.Context,
+		KnowledgePrompt:    knowledgePrompt,
+		ReviewInstructions: reviewInstructions,
+		Locale:             input.Locale,
+		StaticAnalysis:     analyzeInput(input),
+	}
+	if prefs != nil {
+		llmInput.Temperature = prefs.Temperature
+		if prefs.MaxTokens != nil {
+			llmInput.MaxTokens = *prefs.MaxTokens
+		}
 	}
+	return llmInput
+}
+
+// analyzeInput - Go のコードのレビューの場合、LLM を呼び出す前に静的解析する（それ以外は nil）
+// 差分はファイル全体のコードではないため解析しない。解析は軽く結果は同じため、LLM への入力とレビューの作成でそれぞれ計算する
+func analyzeInput(input ReviewCodeInput) *model.StaticAnalysis {
+	if input.Diff != "" || !service.IsGoLanguage(input.Language) {
+		return nil
+	}
+	return service.AnalyzeGoSource(input.Code)
+}
 
-	// ナレッジの使用カウントを更新
+// findCachedReview - キャッシュの有効期間内に同じキーで生成したレビューを取得（ない場合は nil）
+func (uc *ReviewCodeUseCase) findCachedReview(ctx context.Context, input ReviewCodeInput, cacheKey string) (*model.Review, error) {
+	if uc.cache.TTL <= 0 || input.ForceRefresh {
+		return nil, nil
+	}
+
+	cached, err := uc.reviewRepo.FindCachedReview(ctx, input.UserID, cacheKey, uc.now().Add(-uc.cache.TTL))
+	if errors.Is(err, model.ErrReviewCacheMiss) {
+		return nil, nil
+	}
+	if err != nil {
+		// キャッシュを引けなくてもレビューは続ける
+		log.Printf("Warning: failed to find cached review: %v", err)
+		return nil, nil
+	}
+	return cached, nil
+}
+
+// reuseCachedReview - 過去のレビュー結果を再利用した新しいレビューを作成する
+// LLMは呼び出さないため、トークン数とコストはナレッジ検索のEmbeddingの分のみ
+func (uc *ReviewCodeUseCase) reuseCachedReview(input ReviewCodeInput, cached *model.Review, embeddingUsage *external.EmbeddingUsageRecorder) *model.Review {
+	log.Printf("Reusing review result from %s (cache key %s)", cached.ID, cached.CacheKey)
+
+	review := model.NewReview(input.UserID, reviewedCode(input), input.Language, input.Context)
+	review.SetLocale(input.Locale)
+	setReviewFile(review, input)
+	review.SetStaticAnalysis(analyzeInput(input))
+	review.ReuseResult(cached)
+	if review.ReviewResult == "" && review.StructuredResult != nil {
+		review.ReviewResult = parser.RenderReviewMarkdown(review.StructuredResult, input.Language, review.Locale)
+	}
+
+	usage := model.TokenUsage{EmbeddingTokens: embeddingUsage.Tokens()}
+	review.SetUsage(
+		usage,
+		uc.priceTable.Cost("", embeddingUsage.Model(), usage),
+		uc.priceTable.Currency(),
+	)
+
+	return review
+}
+
+// reviewedCode - レビューに保存する入力（差分の場合は差分そのもの）
+func reviewedCode(input ReviewCodeInput) string {
+	if input.Diff != "" {
+		return input.Diff
+	}
+	return input.Code
+}
+
+// setReviewFile - 入力の形式・ファイルのパス・セッション・非同期のジョブ・再レビューの元のレビューをレビューに記録する
+func setReviewFile(review *model.Review, input ReviewCodeInput) {
+	if input.Job != nil {
+		review.TakeOverJob(input.Job)
+	}
+	if input.Diff != "" {
+		review.SetDiff()
+	}
+	if input.FilePath != "" {
+		review.SetFile(input.FilePath)
+	}
+	if input.SessionID != "" {
+		review.SetSession(input.SessionID)
+	}
+	if input.RerunOf != "" {
+		review.SetRerunOf(input.RerunOf)
+	}
+	if input.FollowUpOf != "" {
+		review.SetFollowUpOf(input.FollowUpOf)
+	}
+}
+
+// saveReview - レビューを保存し、ナレッジの使用カウントと利用上限の消費を記録
+func (uc *ReviewCodeUseCase) saveReview(ctx context.Context, review *model.Review, usedKnowledges []*model.Knowledge) error {
+	return uc.saveReviews(ctx, []*model.Review{review}, usedKnowledges)
+}
+
+// saveReviews - 同じ入力・ナレッジを使った複数のレビューを保存する
+// 複数のレビュー（複数モデルのレビュー）は1つのトランザクションで保存し、一部のみ保存された状態を残さない
+// ナレッジの使用カウントは1回分、利用上限の消費はレビューごとに記録する
+func (uc *ReviewCodeUseCase) saveReviews(ctx context.Context, reviews []*model.Review, usedKnowledges []*model.Knowledge) error {
+	// 1. プロンプトインジェクションの疑いを記録し、レビュー結果を保存
+	markPromptInjection(reviews, usedKnowledges)
+	if len(reviews) == 1 {
+		if err := uc.persistReview(ctx, reviews[0]); err != nil {
+			return fmt.Errorf("failed to save review: %w", err)
+		}
+	} else if err := uc.reviewRepo.CreateAll(ctx, reviews); err != nil {
+		return fmt.Errorf("failed to save reviews: %w", err)
+	}
+
+	// 2. ナレッジの使用カウントを更新（実際に使用したナレッジのみ）
 	if err := uc.updateKnowledgeUsage(ctx, usedKnowledges); err != nil {
+		// 更新失敗してもレビュー結果は返す
 		log.Printf("Warning: failed to update knowledge usage: %v", err)
 	}
 
-	return &ReviewCodeOutput{
-		Review: review,
-	}, nil
+	// 3. 利用上限の消費を記録（非同期のレビューのレビュー数は受け付け時に加算済みのため、トークン数のみ）
+	if uc.quotaService != nil {
+		for _, review := range reviews {
+			var err error
+			if review.Async {
+				err = uc.quotaService.RecordJob(ctx, review)
+			} else {
+				err = uc.quotaService.Record(ctx, review)
+			}
+			if err != nil {
+				log.Printf("Warning: failed to record quota usage: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// persistReview - レビューを保存する（非同期のレビューは作成済みのジョブを完了にする）
+func (uc *ReviewCodeUseCase) persistReview(ctx context.Context, review *model.Review) error {
+	if review.Async {
+		return uc.reviewRepo.CompleteJob(ctx, review)
+	}
+	return uc.reviewRepo.Create(ctx, review)
+}
+
+// markPromptInjection - 入力とナレッジからプロンプトインジェクションの疑いを検出してレビューに記録する
+// 検出は推定のため、レビューは中断しない
+func markPromptInjection(reviews []*model.Review, usedKnowledges []*model.Knowledge) {
+	if len(reviews) == 0 {
+		return
+	}
+	signals := service.DetectPromptInjection(service.PromptInjectionInput{
+		Code:      reviews[0].Code,
+		Context:   reviews[0].Context,
+		Knowledge: usedKnowledges,
+	})
+	if len(signals) == 0 {
+		return
+	}
+
+	log.Printf("Warning: suspected prompt injection in review input of user %s (%d signals)", reviews[0].UserID, len(signals))
+	for _, review := range reviews {
+		review.SetPromptInjectionSignals(signals)
+	}
+}
+
+// renderReviewInstructions - 有効なプロンプトテンプレートでレビュー方針を生成し、ユーザー設定の出力言語・観点を付け足す
+// 有効なテンプレートが登録されていない場合は組み込みテンプレートを使う
+func (uc *ReviewCodeUseCase) renderReviewInstructions(ctx context.Context, input ReviewCodeInput, knowledges []*model.Knowledge, prefs *model.UserPreferences) (*model.PromptTemplate, string, error) {
+	promptTemplate, err := uc.promptTemplateRepo.FindActive(ctx, model.PromptTemplateReviewSystem)
+	if errors.Is(err, model.ErrPromptTemplateNotFound) {
+		promptTemplate = service.DefaultReviewPromptTemplate()
+	} else if err != nil {
+		return nil, "", fmt.Errorf("failed to find prompt template: %w", err)
+	}
+
+	instructions, err := uc.promptRenderer.Render(promptTemplate, service.PromptData{
+		Language:  input.Language,
+		Context:   input.Context,
+		Knowledge: knowledges,
+		Locale:    input.Locale,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to render prompt template (version %d): %w", promptTemplate.Version, err)
+	}
+
+	return promptTemplate, service.AppendPreferenceInstructions(instructions, prefs, input.Locale), nil
+}
+
+// generate - 分割した場合・差分の場合はチャンクごとにレビューしてまとめ、それ以外は1回でレビューする
+// 改善点の行範囲はレビューしたコードと照合する
+func (uc *ReviewCodeUseCase) generate(ctx context.Context, provider external.LLMProvider, input external.ReviewCodeInput, chunks []service.CodeChunk, onDelta func(text string)) (*external.ReviewCodeOutput, error) {
+	if len(chunks) > 1 || chunks[0].FilePath != "" {
+		return uc.generateChunkedReview(ctx, provider, input, chunks, onDelta)
+	}
+	result, err := uc.generateReview(ctx, provider, input, onDelta)
+	if err != nil {
+		return nil, err
+	}
+	located := *result
+	located.Structured = service.LocateImprovements(result.Structured, input.Code, 1, 0)
+	return completeReviewResult(&located, input.Language, input.Locale), nil
+}
+
+// generateReview - LLMでレビューを生成（onDeltaが指定されていればストリーミング）
+func (uc *ReviewCodeUseCase) generateReview(ctx context.Context, provider external.LLMProvider, input external.ReviewCodeInput, onDelta func(text string)) (*external.ReviewCodeOutput, error) {
+	if onDelta == nil {
+		return provider.ReviewCode(ctx, input)
+	}
+
+	if streaming, ok := provider.(external.StreamingLLMProvider); ok {
+		return streaming.ReviewCodeStream(ctx, input, onDelta)
+	}
+
+	// ストリーミング非対応のプロバイダは、完了後に全文をまとめて渡す
+	result, err := provider.ReviewCode(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	result = completeReviewResult(result, input.Language, input.Locale)
+	onDelta(result.ReviewResult)
+	return result, nil
+}
+
+// generateChunkedReview - チャンクを並行してレビューし、結果を1つのレビューにまとめる（map-reduce）
+// ストリーミングの場合は、まとめた結果を完了後に1回で渡す
+func (uc *ReviewCodeUseCase) generateChunkedReview(ctx context.Context, provider external.LLMProvider, input external.ReviewCodeInput, chunks []service.CodeChunk, onDelta func(text string)) (*external.ReviewCodeOutput, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// 1. map: 同時実行数を制限してチャンクごとにレビュー（1つでも失敗したら残りを中断）
+	results := make([]*external.ReviewCodeOutput, len(chunks))
+	errs := make([]error, len(chunks))
+	sem := make(chan struct{}, uc.chunking.Concurrency)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+
+			chunkInput := input
+			chunkInput.Code = chunk.Code
+			if chunk.FilePath != "" {
+				chunkInput.Context = buildDiffChunkContext(input.Context, chunk, len(chunks), input.Locale)
+			} else {
+				chunkInput.Context = buildChunkContext(input.Context, chunk, len(chunks), input.Locale)
+				chunkInput.StaticAnalysis = input.StaticAnalysis.WithinLines(chunk.StartLine, chunk.EndLine)
+			}
+
+			result, err := provider.ReviewCode(ctx, chunkInput)
+			if err != nil {
+				errs[i] = fmt.Errorf("chunk %d/%d (lines %d-%d): %w", i+1, len(chunks), chunk.StartLine, chunk.EndLine, err)
+				cancel()
+				return
+			}
+			results[i] = completeReviewResult(result, input.Language, input.Locale)
+			results[i].Structured = locateChunkImprovements(results[i].Structured, chunk)
+		}()
+	}
+	wg.Wait()
+
+	// 中断による context.Canceled より、最初に失敗した原因のエラーを優先して返す
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. reduce: 改善点の重複を除いて番号を振り直し、トークン数を合算
+	merged := &external.ReviewCodeOutput{
+		ResultSource: results[0].ResultSource,
+		Provider:     results[0].Provider,
+		Model:        results[0].Model,
+	}
+	chunkReviews := make([]service.ChunkReview, len(chunks))
+	for i, result := range results {
+		chunkReviews[i] = service.ChunkReview{Chunk: chunks[i], Result: result.Structured}
+		merged.TokensUsed += result.TokensUsed
+		merged.Usage = merged.Usage.Add(result.Usage)
+		// 1チャンクでもマークダウンからのパースにフォールバックした場合はそれを記録
+		if result.ResultSource == model.ResultSourceMarkdown {
+			merged.ResultSource = model.ResultSourceMarkdown
+		}
+	}
+	merged.Structured = service.MergeChunkReviews(chunkReviews, input.Locale)
+	merged.ReviewResult = parser.RenderReviewMarkdown(merged.Structured, input.Language, input.Locale)
+
+	if onDelta != nil {
+		onDelta(merged.ReviewResult)
+	}
+
+	return merged, nil
+}
+
+// locateChunkImprovements - チャンクのレビュー結果の改善点の行範囲を元のファイルの行番号にして照合する
+// 差分の場合、LLM は変更後のファイルの行番号で答え、行範囲を特定できなかった改善点はハンクの追加した行の範囲にする
+func locateChunkImprovements(result *model.StructuredReviewResult, chunk service.CodeChunk) *model.StructuredReviewResult {
+	if chunk.FilePath != "" {
+		located := service.LocateImprovements(result, chunk.NewCode, chunk.NewStartLine, 0)
+		return service.AnchorImprovements(located, chunk)
+	}
+	return service.LocateImprovements(result, chunk.Code, chunk.StartLine, chunk.StartLine-1)
+}
+
+// buildChunkContext - チャンクのレビューであることと元ファイルでの位置をコンテキストに付け足す
+func buildChunkContext(userContext string, chunk service.CodeChunk, total int, locale string) string {
+	format := "大きなファイルを分割してレビューしています（%d/%d、元ファイルの%d〜%d行目）。このチャンクに定義が見当たらない識別子は、ファイルの他の部分で定義されているものとして扱ってください。改善点の行番号は、このチャンクの1行目を1として数えてください。"
+	if locale == model.LocaleEnglish {
+		format = "This is one part of a large file split for review (%d/%d, lines %d-%d of the original file). Treat identifiers not defined in this chunk as defined elsewhere in the file. Count the line numbers of improvements from the first line of this chunk as 1."
+	}
+	note := fmt.Sprintf(format, chunk.Index+1, total, chunk.StartLine, chunk.EndLine)
+	if userContext == "" {
+		return note
+	}
+	return userContext + "\n\n" + note
+}
+
+// buildDiffChunkContext - 差分のハンクの読み方と、ファイル・行範囲・変更後のファイルをコンテキストに付け足す
+func buildDiffChunkContext(userContext string, chunk service.CodeChunk, total int, locale string) string {
+	format := "変更差分のハンクをレビューしています（%d/%d、%s の%d〜%d行目）。各行の先頭は + が追加、- が削除、空白が変更のない行で、数字は変更後のファイルの行番号です。追加・変更した行（+）についてのみ指摘し、変更のない行の問題は指摘しないでください。改善点の行番号には変更後のファイルの行番号を使い、code_before には記号と行番号を除いた行を抜き出してください。"
+	fileFormat := "\n変更後のファイル（全体、または大きい場合は宣言の一覧）:\n%s"
+	if locale == model.LocaleEnglish {
+		format = "This is a hunk of a diff under review (%d/%d, %s lines %d-%d). Each line starts with + (added), - (deleted) or a space (unchanged), followed by its line number in the new file. Only report issues in added or modified lines (+), not in unchanged lines. Use the line numbers of the new file for improvements, and copy code_before without the markers and line numbers."
+		fileFormat = "\nThe new version of the file (in full, or its declarations if it is large):\n%s"
+	}
+	note := fmt.Sprintf(format, chunk.Index+1, total, chunk.FilePath, chunk.StartLine, chunk.EndLine)
+	if chunk.FileContext != "" {
+		note += fmt.Sprintf(fileFormat, chunk.FileContext)
+	}
+	if userContext == "" {
+		return note
+	}
+	return userContext + "\n\n" + note
+}
+
+// completeReviewResult - LLMの出力から構造化データとマークダウンの両方を揃える
+// 構造化出力が得られなかった場合のみ、マークダウンを正規表現でパースする（見出しは locale の言語）
+func completeReviewResult(result *external.ReviewCodeOutput, language, locale string) *external.ReviewCodeOutput {
+	completed := *result
+	if completed.Structured == nil {
+		completed.Structured = parser.ParseReviewMarkdown(completed.ReviewResult, locale)
+		completed.ResultSource = model.ResultSourceMarkdown
+		return &completed
+	}
+	if completed.ReviewResult == "" {
+		completed.ReviewResult = parser.RenderReviewMarkdown(completed.Structured, language, locale)
+	}
+	return &completed
+}
+
+// updateKnowledgeUsage - ナレッジの使用カウントと最終使用日時を更新
+func (uc *ReviewCodeUseCase) updateKnowledgeUsage(ctx context.Context, knowledges []*model.Knowledge) error {
+	for _, k := range knowledges {
+		k.IncrementUsage()
+		if err := uc.knowledgeRepo.Update(ctx, k); err != nil {
+			return fmt.Errorf("failed to update knowledge %s: %w", k.ID, err)
+		}
+	}
+	return nil
+}
+
+// validate - バリデーション
+func (uc *ReviewCodeUseCase) validate(input ReviewCodeInput) error {
+	if input.UserID == "" {
+		return fmt.Errorf("ユーザーIDは必須です")
+	}
+	if input.Code == "" && input.Diff == "" {
+		return fmt.Errorf("コードは必須です")
+	}
+	if input.Language == "" {
+		return fmt.Errorf("プログラミング言語は必須です")
+	}
+	if input.Locale != "" && model.NormalizeLocale(input.Locale) == "" {
+		return model.ErrLocaleInvalid
+	}
+	return nil
 }
 
 // extractKnowledgeIDs - ナレッジIDのリストを抽出
@@ -215,3 +923,62 @@ func extractKnowledgeIDs(knowledges []*model.Knowledge) []string {
 	}
 	return ids
 }
+
+// prepareDiff - 差分を検証し、言語の指定がなければ変更したファイルの拡張子から、
+// ファイルのパスの指定がなければ変更したファイルが1つの場合にそのパスを補う（差分でない場合はそのまま返す）
+func (uc *ReviewCodeUseCase) prepareDiff(input ReviewCodeInput) (ReviewCodeInput, error) {
+	if input.Diff == "" {
+		return input, nil
+	}
+	if input.Code != "" {
+		return input, model.ErrDiffWithCode
+	}
+
+	chunks, err := uc.diffChunks(input)
+	if err != nil {
+		return input, err
+	}
+
+	paths := map[string]bool{}
+	for _, chunk := range chunks {
+		if input.Language == "" {
+			input.Language = model.LanguageFromPath(chunk.FilePath)
+		}
+		paths[chunk.FilePath] = true
+	}
+	if input.Language == "" {
+		return input, model.ErrDiffLanguage
+	}
+	if input.FilePath == "" && len(paths) == 1 {
+		input.FilePath = chunks[0].FilePath
+	}
+	return input, nil
+}
+
+// diffChunks - 差分を解析して、追加・変更したハンクのチャンクを返す
+func (uc *ReviewCodeUseCase) diffChunks(input ReviewCodeInput) ([]service.CodeChunk, error) {
+	files, err := service.ParseUnifiedDiff(input.Diff)
+	if err != nil {
+		return nil, err
+	}
+	chunks := service.DiffChunks(files, contextFileMap(input.ContextFiles), input.Language, uc.chunking.MaxTokens)
+	if len(chunks) == 0 {
+		return nil, model.ErrDiffNoChanges
+	}
+	if len(chunks) > uc.chunking.MaxDiffHunks {
+		return nil, fmt.Errorf("%w: %d", model.ErrDiffTooManyHunks, uc.chunking.MaxDiffHunks)
+	}
+	return chunks, nil
+}
+
+// contextFileMap - 差分のレビューで参照するファイルをパス → コードにする（ない場合は nil）
+func contextFileMap(files []ContextFile) map[string]string {
+	if len(files) == 0 {
+		return nil
+	}
+	byPath := make(map[string]string, len(files))
+	for _, f := range files {
+		byPath[model.NormalizeFilePath(f.Path)] = f.Code
+	}
+	return byPath
+}
@@ -0,0 +1,209 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
+)
+
+// ReviewConversationUseCase - レビューについての会話のユースケース
+// ユーザーの質問・反論に、レビューしたコード・レビュー結果・参照したナレッジを踏まえてLLMが返答し、
+// 質問と返答をメッセージとして保存する（LLMのプロバイダ・利用上限・料金表はレビューと共通）
+type ReviewConversationUseCase struct {
+	reviewCode      *ReviewCodeUseCase
+	messageRepo     repository.ReviewMessageRepository
+	enabled         bool
+	maxMessages     int
+	historyMessages int
+}
+
+// ConversationOptions - レビューについての会話の設定
+type ConversationOptions struct {
+	Enabled         bool // 会話を使えるか（FEATURE_CONVERSATION_MODE）
+	MaxMessages     int  // 1つのレビューで保存できる最大メッセージ数（質問と返答の合計。0以下は既定値）
+	HistoryMessages int  // LLMに渡す直近の会話のメッセージ数（0以下は既定値）
+}
+
+// 会話の既定値
+const (
+	defaultConversationMaxMessages     = 50
+	defaultConversationHistoryMessages = 20
+)
+
+// NewReviewConversationUseCase - コンストラクタ
+func NewReviewConversationUseCase(reviewCode *ReviewCodeUseCase, messageRepo repository.ReviewMessageRepository, options ConversationOptions) *ReviewConversationUseCase {
+	options = options.withDefaults()
+	return &ReviewConversationUseCase{
+		reviewCode:      reviewCode,
+		messageRepo:     messageRepo,
+		enabled:         options.Enabled,
+		maxMessages:     options.MaxMessages,
+		historyMessages: options.HistoryMessages,
+	}
+}
+
+// withDefaults - 未設定の値を既定値にする
+func (o ConversationOptions) withDefaults() ConversationOptions {
+	if o.MaxMessages <= 0 {
+		o.MaxMessages = defaultConversationMaxMessages
+	}
+	if o.HistoryMessages <= 0 {
+		o.HistoryMessages = defaultConversationHistoryMessages
+	}
+	return o
+}
+
+// SendReviewMessageInput - 入力
+type SendReviewMessageInput struct {
+	ReviewID string
+	UserID   string
+	Content  string
+	// Locale - 返答の言語（ja / en。空の場合はレビューの言語）
+	Locale string
+}
+
+// SendReviewMessageOutput - 出力
+type SendReviewMessageOutput struct {
+	Question *model.ReviewMessage
+	Reply    *model.ReviewMessage
+}
+
+// Execute - レビューについてのメッセージを送信し、LLMの返答とあわせて保存する
+func (uc *ReviewConversationUseCase) Execute(ctx context.Context, input SendReviewMessageInput) (*SendReviewMessageOutput, error) {
+	// 0. バリデーション
+	if !uc.enabled {
+		return nil, model.ErrConversationDisabled
+	}
+	question, err := model.NewReviewMessage(input.ReviewID, input.UserID, input.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. レビューとこれまでの会話を取得（他のユーザーのレビューは見つからない扱い）
+	review, err := findOwnReview(ctx, uc.reviewCode.reviewRepo, input.UserID, input.ReviewID)
+	if err != nil {
+		return nil, err
+	}
+	if !review.IsCompleted() {
+		// 実行待ち・実行中・失敗したレビューにはレビュー結果がない
+		return nil, model.ErrReviewNotCompleted
+	}
+	history, err := uc.messageRepo.ListByReviewID(ctx, input.UserID, input.ReviewID)
+	if err != nil {
+		return nil, err
+	}
+	if len(history)+2 > uc.maxMessages {
+		return nil, model.ErrReviewMessageLimit
+	}
+
+	// LLMを呼び出す前に利用上限を確認
+	if err := uc.reviewCode.CheckQuota(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+
+	// 2. ユーザー設定のプロバイダで返答を生成
+	prefs := uc.reviewCode.loadPreferences(ctx, input.UserID)
+	provider, ok := uc.reviewCode.selectProvider(prefs).(external.ConversationalLLMProvider)
+	if !ok {
+		return nil, fmt.Errorf("LLM provider does not support conversation")
+	}
+
+	locale := input.Locale
+	if locale == "" {
+		locale = review.Locale
+	}
+	locale = resolveLocale(locale, prefs)
+
+	converseInput := external.ConverseInput{
+		Code:            review.Code,
+		Language:        review.Language,
+		Context:         review.Context,
+		Review:          review.ReviewResult,
+		KnowledgePrompt: uc.referencedKnowledgePrompt(ctx, review, locale),
+		Messages:        append(recentConversation(history, uc.historyMessages), external.ConversationMessage{Role: question.Role, Content: question.Content}),
+		Temperature:     prefs.Temperature,
+		Locale:          locale,
+	}
+	if prefs.MaxTokens != nil {
+		converseInput.MaxTokens = *prefs.MaxTokens
+	}
+
+	output, err := provider.Converse(ctx, converseInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate reply: %w", err)
+	}
+
+	// 3. 質問と返答を保存
+	reply := model.NewAssistantMessage(input.ReviewID, input.UserID, output.Reply, output.Knowledge)
+	reply.SetUsage(
+		output.Provider,
+		output.Model,
+		output.TokensUsed,
+		output.Usage,
+		uc.reviewCode.priceTable.Cost(output.Model, "", output.Usage),
+		uc.reviewCode.priceTable.Currency(),
+	)
+	if err := uc.messageRepo.CreateExchange(ctx, question, reply); err != nil {
+		return nil, fmt.Errorf("failed to save review messages: %w", err)
+	}
+
+	return &SendReviewMessageOutput{
+		Question: question,
+		Reply:    reply,
+	}, nil
+}
+
+// referencedKnowledgePrompt - レビューで参照したナレッジのプロンプト（削除・無効化したナレッジは除く）
+func (uc *ReviewConversationUseCase) referencedKnowledgePrompt(ctx context.Context, review *model.Review, locale string) string {
+	knowledges := make([]*model.Knowledge, 0, len(review.ReferencedKnowledge))
+	for _, id := range review.ReferencedKnowledge {
+		knowledge, err := uc.reviewCode.knowledgeRepo.FindByID(ctx, id)
+		if err != nil {
+			log.Printf("Warning: failed to find referenced knowledge %s: %v", id, err)
+			continue
+		}
+		if knowledge.UserID != review.UserID || !knowledge.IsActive {
+			continue
+		}
+		knowledges = append(knowledges, knowledge)
+	}
+
+	prompt, _ := uc.reviewCode.reviewService.BuildPromptFromKnowledge(knowledges, locale)
+	return prompt
+}
+
+// recentConversation - LLMに渡す直近の会話（ユーザーのメッセージから始まるように切り詰める）
+func recentConversation(history []*model.ReviewMessage, limit int) []external.ConversationMessage {
+	if len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	for len(history) > 0 && !history[0].IsUser() {
+		history = history[1:]
+	}
+
+	messages := make([]external.ConversationMessage, 0, len(history)+1)
+	for _, m := range history {
+		messages = append(messages, external.ConversationMessage{Role: m.Role, Content: m.Content})
+	}
+	return messages
+}
+
+// findOwnReview - ユーザーのレビューを取得（他のユーザーのレビューは model.ErrReviewNotFound）
+func findOwnReview(ctx context.Context, reviewRepo repository.ReviewRepository, userID, reviewID string) (*model.Review, error) {
+	review, err := reviewRepo.FindByID(ctx, reviewID)
+	if errors.Is(err, model.ErrReviewNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find review: %w", err)
+	}
+	if review.UserID != userID {
+		return nil, fmt.Errorf("%w: %s", model.ErrReviewNotFound, reviewID)
+	}
+	return review, nil
+}
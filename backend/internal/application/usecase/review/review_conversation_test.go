@@ -0,0 +1,204 @@
+package review_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
+	"github.com/s7r8/reviewapp/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewConversationUseCase_Execute(t *testing.T) {
+	type fixture struct {
+		repo          *testutil.MockReviewRepository
+		knowledgeRepo *testutil.MockKnowledgeRepository
+		messageRepo   *testutil.MockReviewMessageRepository
+		client        *testutil.MockClaudeClient
+		uc            *review.ReviewConversationUseCase
+		review        *model.Review
+	}
+	setup := func(t *testing.T, options review.ConversationOptions) *fixture {
+		f := &fixture{
+			repo:          testutil.NewMockReviewRepository(),
+			knowledgeRepo: testutil.NewMockKnowledgeRepository(),
+			messageRepo:   testutil.NewMockReviewMessageRepository(),
+			client:        testutil.NewMockClaudeClient(),
+		}
+		reviewCode := review.NewReviewCodeUseCase(
+			f.repo,
+			f.knowledgeRepo,
+			service.NewReviewService(),
+			f.client,
+			testutil.NewMockEmbeddingClient(),
+			testutil.NewMockPromptTemplateRepository(),
+			service.NewTemplatePromptRenderer(),
+			service.NewPriceTable(service.DefaultPriceCurrency, service.DefaultModelPrices()),
+			nil,
+			review.ChunkingOptions{},
+			review.CacheOptions{},
+			review.PreferenceOptions{},
+		)
+		f.uc = review.NewReviewConversationUseCase(reviewCode, f.messageRepo, options)
+
+		knowledge, err := model.NewKnowledge("test-user-id", "エラーは必ずラップする", "fmt.Errorf の %w でラップする", model.CategoryErrorHandling, 5)
+		require.NoError(t, err)
+		f.knowledgeRepo.SetKnowledges([]*model.Knowledge{knowledge})
+
+		f.review = model.NewReview("test-user-id", "func Find() error {\n\treturn err\n}", "Go", "")
+		f.review.SetLocale(model.LocaleJapanese)
+		f.review.SetReviewResult("### 1. エラーをラップする", nil, []string{knowledge.ID}, "anthropic", "claude-3-5-haiku-latest", 100)
+		require.NoError(t, f.repo.Create(context.Background(), f.review))
+		return f
+	}
+
+	t.Run("コード・レビュー結果・参照したナレッジを踏まえて返答し、質問と返答を保存する", func(t *testing.T) {
+		f := setup(t, review.ConversationOptions{Enabled: true})
+		f.client.SetConverseFunc(func(input external.ConverseInput) (*external.ConverseOutput, error) {
+			return &external.ConverseOutput{
+				Reply:      "呼び出し元でエラーの原因を区別できるようにするためです。",
+				Knowledge:  &model.KnowledgeSuggestion{Title: "エラーの原因を残す", Content: "ラップして原因を残す", Category: model.CategoryErrorHandling, Priority: 4},
+				TokensUsed: 300,
+				Usage:      model.TokenUsage{InputTokens: 250, OutputTokens: 50},
+				Provider:   "anthropic",
+				Model:      "claude-3-5-haiku-latest",
+			}, nil
+		})
+
+		output, err := f.uc.Execute(context.Background(), review.SendReviewMessageInput{
+			ReviewID: f.review.ID,
+			UserID:   "test-user-id",
+			Content:  "  なぜラップする必要があるのですか？ ",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, model.ReviewMessageRoleUser, output.Question.Role)
+		assert.Equal(t, "なぜラップする必要があるのですか？", output.Question.Content)
+		assert.Equal(t, model.ReviewMessageRoleAssistant, output.Reply.Role)
+		assert.Equal(t, "呼び出し元でエラーの原因を区別できるようにするためです。", output.Reply.Content)
+		require.NotNil(t, output.Reply.SuggestedKnowledge)
+		assert.Equal(t, 300, output.Reply.TokensUsed)
+		assert.Greater(t, output.Reply.Cost, 0.0)
+
+		inputs := f.client.ConverseInputs()
+		require.Len(t, inputs, 1)
+		assert.Equal(t, f.review.Code, inputs[0].Code)
+		assert.Equal(t, "### 1. エラーをラップする", inputs[0].Review)
+		assert.Contains(t, inputs[0].KnowledgePrompt, "エラーは必ずラップする")
+		assert.Equal(t, model.LocaleJapanese, inputs[0].Locale)
+		assert.Equal(t, []external.ConversationMessage{{Role: model.ReviewMessageRoleUser, Content: "なぜラップする必要があるのですか？"}}, inputs[0].Messages)
+
+		saved, err := f.messageRepo.ListByReviewID(context.Background(), "test-user-id", f.review.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []*model.ReviewMessage{output.Question, output.Reply}, saved)
+	})
+
+	t.Run("直近の会話を user から始まるように切り詰めて渡す", func(t *testing.T) {
+		f := setup(t, review.ConversationOptions{Enabled: true, HistoryMessages: 3})
+		for _, content := range []string{"1つ目の質問", "2つ目の質問"} {
+			_, err := f.uc.Execute(context.Background(), review.SendReviewMessageInput{ReviewID: f.review.ID, UserID: "test-user-id", Content: content})
+			require.NoError(t, err)
+		}
+
+		_, err := f.uc.Execute(context.Background(), review.SendReviewMessageInput{ReviewID: f.review.ID, UserID: "test-user-id", Content: "3つ目の質問"})
+		require.NoError(t, err)
+
+		// 履歴4件のうち直近3件は assistant から始まるため、2つ目の質問以降を渡す
+		inputs := f.client.ConverseInputs()
+		require.Len(t, inputs, 3)
+		messages := inputs[2].Messages
+		require.Len(t, messages, 3)
+		assert.Equal(t, "2つ目の質問", messages[0].Content)
+		assert.Equal(t, model.ReviewMessageRoleAssistant, messages[1].Role)
+		assert.Equal(t, "3つ目の質問", messages[2].Content)
+	})
+
+	t.Run("メッセージ数が上限を超える場合はLLMを呼び出さない", func(t *testing.T) {
+		f := setup(t, review.ConversationOptions{Enabled: true, MaxMessages: 2})
+		_, err := f.uc.Execute(context.Background(), review.SendReviewMessageInput{ReviewID: f.review.ID, UserID: "test-user-id", Content: "質問"})
+		require.NoError(t, err)
+
+		_, err = f.uc.Execute(context.Background(), review.SendReviewMessageInput{ReviewID: f.review.ID, UserID: "test-user-id", Content: "もう1つ質問"})
+
+		assert.ErrorIs(t, err, model.ErrReviewMessageLimit)
+		assert.Len(t, f.client.ConverseInputs(), 1)
+	})
+
+	t.Run("エラー", func(t *testing.T) {
+		f := setup(t, review.ConversationOptions{Enabled: true})
+
+		_, err := f.uc.Execute(context.Background(), review.SendReviewMessageInput{ReviewID: f.review.ID, UserID: "test-user-id", Content: "  "})
+		assert.ErrorIs(t, err, model.ErrReviewMessageRequired)
+
+		// 他のユーザーのレビューは見つからない扱い
+		_, err = f.uc.Execute(context.Background(), review.SendReviewMessageInput{ReviewID: f.review.ID, UserID: "other-user-id", Content: "質問"})
+		assert.ErrorIs(t, err, model.ErrReviewNotFound)
+
+		// 非同期のレビューが完了するまではレビュー結果がない
+		job := model.NewReviewJob("test-user-id", "func main() {}", "Go", "", model.ReviewJobOptions{})
+		require.NoError(t, f.repo.Create(context.Background(), job))
+		_, err = f.uc.Execute(context.Background(), review.SendReviewMessageInput{ReviewID: job.ID, UserID: "test-user-id", Content: "質問"})
+		assert.ErrorIs(t, err, model.ErrReviewNotCompleted)
+
+		disabled := setup(t, review.ConversationOptions{Enabled: false})
+		_, err = disabled.uc.Execute(context.Background(), review.SendReviewMessageInput{ReviewID: disabled.review.ID, UserID: "test-user-id", Content: "質問"})
+		assert.ErrorIs(t, err, model.ErrConversationDisabled)
+
+		assert.Empty(t, f.client.ConverseInputs())
+	})
+}
+
+func TestCreateKnowledgeFromMessageUseCase_Execute(t *testing.T) {
+	setup := func(t *testing.T, suggestion *model.KnowledgeSuggestion) (*review.CreateKnowledgeFromMessageUseCase, *testutil.MockKnowledgeRepository, *model.ReviewMessage) {
+		messageRepo := testutil.NewMockReviewMessageRepository()
+		knowledgeRepo := testutil.NewMockKnowledgeRepository()
+		question, err := model.NewReviewMessage("review-id", "test-user-id", "うちのチームでは errors.Join を使います")
+		require.NoError(t, err)
+		reply := model.NewAssistantMessage("review-id", "test-user-id", "承知しました。", suggestion)
+		require.NoError(t, messageRepo.CreateExchange(context.Background(), question, reply))
+
+		uc := review.NewCreateKnowledgeFromMessageUseCase(messageRepo, knowledgeRepo, testutil.NewMockEmbeddingClient(), review.ConversationOptions{Enabled: true})
+		return uc, knowledgeRepo, reply
+	}
+
+	t.Run("LLMの提案を指定した項目で上書きして source_type=conversation のナレッジを作成する", func(t *testing.T) {
+		uc, knowledgeRepo, reply := setup(t, &model.KnowledgeSuggestion{
+			Title: "複数のエラーは errors.Join でまとめる", Content: "ループ内のエラーは errors.Join でまとめて返す", Category: model.CategoryErrorHandling, Priority: 3,
+		})
+
+		knowledge, err := uc.Execute(context.Background(), review.CreateKnowledgeFromMessageInput{
+			ReviewID: "review-id", MessageID: reply.ID, UserID: "test-user-id", Priority: 5,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "複数のエラーは errors.Join でまとめる", knowledge.Title)
+		assert.Equal(t, 5, knowledge.Priority)
+		assert.Equal(t, model.SourceTypeConversation, knowledge.SourceType)
+		require.NotNil(t, knowledge.SourceID)
+		assert.Equal(t, reply.ID, *knowledge.SourceID)
+		require.NotNil(t, reply.KnowledgeID)
+		assert.Equal(t, knowledge.ID, *reply.KnowledgeID)
+		saved, err := knowledgeRepo.FindByID(context.Background(), knowledge.ID)
+		require.NoError(t, err)
+		assert.NotEmpty(t, saved.Embedding)
+
+		// 1つのメッセージから作成できるのは1件まで
+		_, err = uc.Execute(context.Background(), review.CreateKnowledgeFromMessageInput{ReviewID: "review-id", MessageID: reply.ID, UserID: "test-user-id"})
+		assert.ErrorIs(t, err, model.ErrReviewMessageKnowledgeExists)
+	})
+
+	t.Run("提案がないメッセージでは項目の指定が必要", func(t *testing.T) {
+		uc, _, reply := setup(t, nil)
+
+		_, err := uc.Execute(context.Background(), review.CreateKnowledgeFromMessageInput{ReviewID: "review-id", MessageID: reply.ID, UserID: "test-user-id"})
+		assert.ErrorIs(t, err, model.ErrTitleRequired)
+
+		// 他のユーザーのメッセージは見つからない扱い
+		_, err = uc.Execute(context.Background(), review.CreateKnowledgeFromMessageInput{ReviewID: "review-id", MessageID: reply.ID, UserID: "other-user-id", Title: "t", Content: "c", Category: model.CategoryOther, Priority: 1})
+		assert.ErrorIs(t, err, model.ErrReviewMessageNotFound)
+	})
+}
@@ -0,0 +1,349 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
+)
+
+// ReviewJobUseCase - レビューを非同期のジョブとして受け付け、ワーカーで実行するユースケース
+// ジョブは reviews テーブルに status=pending で保存し、ワーカーがDBから古い順に取得して実行する
+// （キューをDBに持つため、サーバーを再起動しても受け付けたジョブは失われない）
+// 実行は ReviewCodeUseCase と同じ手順で行い、結果は受け付けたレビューのIDで保存する
+type ReviewJobUseCase struct {
+	reviewCode   *ReviewCodeUseCase
+	workers      int
+	timeout      time.Duration
+	pollInterval time.Duration
+
+	wake      chan struct{} // 実行待ちのジョブができたことをワーカーに通知する
+	stop      chan struct{} // ワーカーを停止する
+	interrupt context.Context
+	cancel    context.CancelFunc // 停止の期限を過ぎても実行中のジョブを中断する
+	wg        sync.WaitGroup
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// ReviewJobUseCaseOptions - 非同期のレビューのワーカーの設定
+type ReviewJobUseCaseOptions struct {
+	Workers      int           // 並行して実行するジョブ数（0以下は既定値）
+	Timeout      time.Duration // 1回の実行の制限時間（0以下は既定値）
+	PollInterval time.Duration // 他のサーバーが受け付けたジョブ・止まったジョブを確認する間隔（0以下は既定値）
+}
+
+// 非同期のレビューの既定値
+const (
+	defaultReviewJobWorkers      = 4
+	defaultReviewJobTimeout      = 10 * time.Minute
+	defaultReviewJobPollInterval = 5 * time.Second
+)
+
+// reviewJobStaleGrace - 実行の制限時間を過ぎてから、止まったジョブとみなすまでの猶予
+// 制限時間を過ぎたジョブの失敗の記録と、サーバー間の時刻のずれを待つ
+const reviewJobStaleGrace = time.Minute
+
+// NewReviewJobUseCase - コンストラクタ
+func NewReviewJobUseCase(reviewCode *ReviewCodeUseCase, options ReviewJobUseCaseOptions) *ReviewJobUseCase {
+	if options.Workers <= 0 {
+		options.Workers = defaultReviewJobWorkers
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaultReviewJobTimeout
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaultReviewJobPollInterval
+	}
+	interrupt, cancel := context.WithCancel(context.Background())
+	return &ReviewJobUseCase{
+		reviewCode:   reviewCode,
+		workers:      options.Workers,
+		timeout:      options.Timeout,
+		pollInterval: options.PollInterval,
+		wake:         make(chan struct{}, options.Workers),
+		stop:         make(chan struct{}),
+		interrupt:    interrupt,
+		cancel:       cancel,
+	}
+}
+
+// RetryReviewInput - 入力
+type RetryReviewInput struct {
+	ReviewID string
+	UserID   string
+}
+
+// Submit - レビューを検証して実行待ちのジョブとして保存する（レビュー結果は GET /api/v1/reviews/:id で取得する）
+// 入力の検証・利用上限の確認・出力言語の決定は受け付け時に行い、実行時は受け付けた内容でレビューする
+// 利用上限のレビュー数は受け付け時に1件加算し、失敗・削除で中止した場合に戻す
+func (uc *ReviewJobUseCase) Submit(ctx context.Context, input ReviewCodeInput) (*model.Review, error) {
+	// 1. バリデーション（差分の場合は言語とファイルのパスを差分から補う）
+	input, err := uc.reviewCode.prepareDiff(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.reviewCode.validate(input); err != nil {
+		return nil, err
+	}
+	if err := uc.reviewCode.CheckQuota(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+
+	// 2. 実行待ちのジョブを作成
+	prefs := uc.reviewCode.loadPreferences(ctx, input.UserID).WithOverrides(input.Overrides)
+	job := model.NewReviewJob(input.UserID, reviewedCode(input), input.Language, input.Context, model.ReviewJobOptions{
+		ForceRefresh: input.ForceRefresh,
+		ContextFiles: contextFileMap(input.ContextFiles),
+		Overrides:    input.Overrides,
+	})
+	job.SetLocale(resolveLocale(input.Locale, prefs))
+	setReviewFile(job, input)
+	uc.reviewCode.reserveQuota(ctx, job)
+	if err := uc.reviewCode.reviewRepo.Create(ctx, job); err != nil {
+		uc.reviewCode.releaseQuota(ctx, job)
+		return nil, fmt.Errorf("failed to save review job: %w", err)
+	}
+
+	// 3. ワーカーに通知
+	uc.notify()
+	return job, nil
+}
+
+// Retry - 失敗したレビューを実行待ちに戻す（自分のレビューのみ）
+func (uc *ReviewJobUseCase) Retry(ctx context.Context, input RetryReviewInput) (*model.Review, error) {
+	// 1. レビューを取得（他のユーザーのレビューは見つからない扱い）
+	job, err := findOwnReview(ctx, uc.reviewCode.reviewRepo, input.UserID, input.ReviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 失敗したレビューのみ、利用上限を確認して実行待ちに戻す（受け付けと同じくレビュー数を1件加算する）
+	if err := job.Retry(); err != nil {
+		return nil, err
+	}
+	if err := uc.reviewCode.CheckQuota(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+	uc.reviewCode.reserveQuota(ctx, job)
+	if err := uc.reviewCode.reviewRepo.UpdateJobStatus(ctx, job, model.ReviewStatusFailed); err != nil {
+		uc.reviewCode.releaseQuota(ctx, job)
+		if errors.Is(err, model.ErrReviewJobLost) {
+			// 同時に再実行・削除された
+			return nil, model.ErrReviewJobNotRetryable
+		}
+		return nil, fmt.Errorf("failed to retry review job: %w", err)
+	}
+
+	// 3. ワーカーに通知
+	uc.notify()
+	return job, nil
+}
+
+// Start - ワーカーを起動する（2回目以降の呼び出しは何もしない）
+// 制限時間を過ぎても実行中のままのジョブは、実行していたサーバーが停止したものとして実行待ちに戻す
+func (uc *ReviewJobUseCase) Start() {
+	uc.startOnce.Do(func() {
+		uc.requeueStaleJobs()
+
+		uc.wg.Add(uc.workers + 1)
+		for i := 0; i < uc.workers; i++ {
+			go uc.work()
+		}
+		go uc.poll()
+
+		// 停止前に受け付けたジョブを実行する
+		uc.notify()
+	})
+}
+
+// Shutdown - 新しいジョブの取得をやめ、実行中のジョブの完了を待つ
+// ctx の期限を過ぎた場合は実行中のジョブを中断し、実行待ちに戻す
+func (uc *ReviewJobUseCase) Shutdown(ctx context.Context) error {
+	uc.stopOnce.Do(func() { close(uc.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		uc.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		uc.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// notify - 待機中のワーカーを1つ起こす（全員が実行中の場合は、実行を終えたワーカーが取得する）
+func (uc *ReviewJobUseCase) notify() {
+	select {
+	case uc.wake <- struct{}{}:
+	default:
+	}
+}
+
+// work - 実行待ちのジョブがなくなるまで実行し、通知を待つ
+func (uc *ReviewJobUseCase) work() {
+	defer uc.wg.Done()
+	for {
+		for uc.runNext() {
+		}
+		select {
+		case <-uc.stop:
+			return
+		case <-uc.wake:
+		}
+	}
+}
+
+// poll - 定期的に止まったジョブを実行待ちに戻し、ワーカーを起こす
+func (uc *ReviewJobUseCase) poll() {
+	defer uc.wg.Done()
+	ticker := time.NewTicker(uc.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-uc.stop:
+			return
+		case <-ticker.C:
+			uc.requeueStaleJobs()
+			uc.notify()
+		}
+	}
+}
+
+// requeueStaleJobs - 制限時間と猶予（確認の間隔 + reviewJobStaleGrace）を過ぎても実行中のままのジョブを実行待ちに戻す
+// 実行中のワーカーは制限時間で中断するため、猶予を過ぎて実行中のままのジョブは実行していたサーバーが停止している
+func (uc *ReviewJobUseCase) requeueStaleJobs() {
+	staleAfter := uc.timeout + uc.pollInterval + reviewJobStaleGrace
+	count, err := uc.reviewCode.reviewRepo.RequeueStaleJobs(context.Background(), uc.reviewCode.now().Add(-staleAfter))
+	if err != nil {
+		log.Printf("Warning: failed to requeue stale review jobs: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("Requeued %d stale review jobs", count)
+	}
+}
+
+// runNext - 実行待ちのジョブを1つ取得して実行する（取得できなかった場合は false）
+func (uc *ReviewJobUseCase) runNext() bool {
+	select {
+	case <-uc.stop:
+		return false
+	default:
+	}
+
+	job, err := uc.reviewCode.reviewRepo.ClaimNextJob(context.Background())
+	if errors.Is(err, model.ErrNoPendingReviewJob) {
+		return false
+	}
+	if err != nil {
+		log.Printf("Warning: failed to claim review job: %v", err)
+		return false
+	}
+
+	// 他にも実行待ちのジョブがあれば、待機中のワーカーに並行して実行させる
+	uc.notify()
+	uc.run(job)
+	return true
+}
+
+// run - ジョブを制限時間内で実行し、失敗した場合は理由を記録する
+func (uc *ReviewJobUseCase) run(job *model.Review) {
+	ctx, cancel := context.WithTimeout(uc.interrupt, uc.timeout)
+	defer cancel()
+
+	_, err := uc.reviewCode.Execute(ctx, jobInput(job))
+	if err == nil {
+		return
+	}
+	if errors.Is(err, model.ErrReviewJobLost) {
+		// 止まったとみなされて他のワーカーが取得し直した・削除された場合は、結果も失敗も記録しない
+		log.Printf("Review job %s is no longer owned by this worker (attempt %d)", job.ID, job.Attempts)
+		return
+	}
+
+	if uc.interrupt.Err() != nil {
+		// サーバーの停止で中断した場合は、次に起動したワーカーで実行し直す
+		log.Printf("Review job %s interrupted by shutdown", job.ID)
+		job.Requeue()
+	} else {
+		log.Printf("Review job %s failed (attempt %d): %v", job.ID, job.Attempts, err)
+		job.Fail(jobErrorMessage(err))
+	}
+	if err := uc.reviewCode.reviewRepo.UpdateJobStatus(context.Background(), job, model.ReviewStatusRunning); err != nil {
+		log.Printf("Warning: failed to update review job %s: %v", job.ID, err)
+		return
+	}
+	if job.Status == model.ReviewStatusFailed {
+		uc.reviewCode.releaseQuota(context.Background(), job)
+	}
+}
+
+// jobInput - 受け付けた内容からレビューの入力を作る
+func jobInput(job *model.Review) ReviewCodeInput {
+	input := inputFromReview(job)
+	input.Job = job
+	if job.SessionID != nil {
+		input.SessionID = *job.SessionID
+	}
+	if job.RerunOfReviewID != nil {
+		input.RerunOf = *job.RerunOfReviewID
+	}
+	if job.JobOptions != nil {
+		input.ForceRefresh = job.JobOptions.ForceRefresh
+		input.Overrides = job.JobOptions.Overrides
+	}
+	return input
+}
+
+// inputFromReview - レビューした内容（コード・言語・ロケール・参照したファイル）からレビューの入力を作る
+func inputFromReview(r *model.Review) ReviewCodeInput {
+	input := ReviewCodeInput{
+		UserID:   r.UserID,
+		Language: r.Language,
+		Context:  r.Context,
+		Locale:   r.Locale,
+		FilePath: r.FilePath,
+	}
+	if r.IsDiff() {
+		input.Diff = r.Code
+	} else {
+		input.Code = r.Code
+	}
+	if r.JobOptions != nil {
+		for path, code := range r.JobOptions.ContextFiles {
+			input.ContextFiles = append(input.ContextFiles, ContextFile{Path: path, Code: code})
+		}
+	}
+	return input
+}
+
+// jobErrorMessage - 失敗した理由としてユーザーに返すメッセージ（内部のエラーの詳細は含めない）
+func jobErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, model.ErrQuotaExceeded):
+		return model.ErrQuotaExceeded.Error()
+	case errors.Is(err, model.ErrDiffInvalid):
+		return model.ErrDiffInvalid.Error()
+	case errors.Is(err, model.ErrDiffNoChanges):
+		return model.ErrDiffNoChanges.Error()
+	case errors.Is(err, model.ErrDiffTooManyHunks):
+		return model.ErrDiffTooManyHunks.Error()
+	case external.IsUnavailable(err):
+		return "AI APIが一時的に利用できません"
+	case errors.Is(err, context.DeadlineExceeded):
+		return model.ErrReviewJobTimeout.Error()
+	}
+	return model.ErrReviewJobFailed.Error()
+}
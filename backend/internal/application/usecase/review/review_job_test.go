@@ -0,0 +1,265 @@
+package review_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+	"github.com/s7r8/reviewapp/internal/infrastructure/config"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
+	"github.com/s7r8/reviewapp/internal/infrastructure/persistence/redis"
+	"github.com/s7r8/reviewapp/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewJobUseCase(t *testing.T) {
+	newQuotaUseCase := func(repo *testutil.MockReviewRepository, client *testutil.MockClaudeClient, quota *service.QuotaService) *review.ReviewJobUseCase {
+		reviewCode := review.NewReviewCodeUseCase(
+			repo,
+			testutil.NewMockKnowledgeRepository(),
+			service.NewReviewService(),
+			client,
+			testutil.NewMockEmbeddingClient(),
+			testutil.NewMockPromptTemplateRepository(),
+			service.NewTemplatePromptRenderer(),
+			service.NewPriceTable(service.DefaultPriceCurrency, service.DefaultModelPrices()),
+			quota,
+			review.ChunkingOptions{},
+			review.CacheOptions{},
+			review.PreferenceOptions{},
+		)
+		return review.NewReviewJobUseCase(reviewCode, review.ReviewJobUseCaseOptions{Workers: 2, PollInterval: 10 * time.Millisecond})
+	}
+	newUseCase := func(repo *testutil.MockReviewRepository, client *testutil.MockClaudeClient) *review.ReviewJobUseCase {
+		return newQuotaUseCase(repo, client, nil)
+	}
+	waitForStatus := func(t *testing.T, repo *testutil.MockReviewRepository, id, status string) *model.Review {
+		var found *model.Review
+		require.Eventually(t, func() bool {
+			r, err := repo.FindByID(context.Background(), id)
+			require.NoError(t, err)
+			found = r
+			return r.Status == status
+		}, 2*time.Second, 5*time.Millisecond)
+		return found
+	}
+	shutdown := func(t *testing.T, uc *review.ReviewJobUseCase) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		require.NoError(t, uc.Shutdown(ctx))
+	}
+
+	t.Run("受け付けたレビューを実行待ちで保存し、ワーカーが同じIDで完了にする", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		client := testutil.NewMockClaudeClient()
+		uc := newUseCase(repo, client)
+
+		job, err := uc.Submit(context.Background(), review.ReviewCodeInput{
+			UserID:   "test-user-id",
+			Code:     "func main() {}",
+			Language: "Go",
+			FilePath: "cmd/main.go",
+			Locale:   "en",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, model.ReviewStatusPending, job.Status)
+		assert.True(t, job.Async)
+		assert.Nil(t, job.CompletedAt)
+		assert.Empty(t, client.Inputs())
+
+		uc.Start()
+		defer shutdown(t, uc)
+
+		completed := waitForStatus(t, repo, job.ID, model.ReviewStatusCompleted)
+		assert.Equal(t, "Mock review result", completed.ReviewResult)
+		assert.Equal(t, "cmd/main.go", completed.FilePath)
+		assert.Equal(t, model.LocaleEnglish, completed.Locale)
+		assert.Equal(t, 1, completed.Attempts)
+		assert.NotNil(t, completed.StartedAt)
+		assert.NotNil(t, completed.CompletedAt)
+		assert.Equal(t, job.CreatedAt, completed.CreatedAt)
+		assert.Equal(t, 1, len(client.Inputs()))
+	})
+
+	t.Run("失敗したレビューは理由を残し、再実行すると実行待ちに戻る", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		client := testutil.NewMockClaudeClient()
+		client.SetError(&external.APIError{Provider: "anthropic", StatusCode: 529, Message: "overloaded"})
+		uc := newUseCase(repo, client)
+
+		job, err := uc.Submit(context.Background(), review.ReviewCodeInput{UserID: "test-user-id", Code: "func main() {}", Language: "Go"})
+		require.NoError(t, err)
+		uc.Start()
+
+		failed := waitForStatus(t, repo, job.ID, model.ReviewStatusFailed)
+		shutdown(t, uc)
+		assert.Equal(t, "AI APIが一時的に利用できません", failed.ErrorMessage)
+		assert.Equal(t, 1, failed.Attempts)
+		assert.NotNil(t, failed.CompletedAt)
+
+		// 他のユーザーのレビューは見つからない扱い
+		retry := newUseCase(repo, testutil.NewMockClaudeClient())
+		_, err = retry.Retry(context.Background(), review.RetryReviewInput{ReviewID: job.ID, UserID: "other-user-id"})
+		assert.ErrorIs(t, err, model.ErrReviewNotFound)
+
+		retried, err := retry.Retry(context.Background(), review.RetryReviewInput{ReviewID: job.ID, UserID: "test-user-id"})
+		require.NoError(t, err)
+		assert.Equal(t, model.ReviewStatusPending, retried.Status)
+		assert.Empty(t, retried.ErrorMessage)
+
+		retry.Start()
+		defer shutdown(t, retry)
+		completed := waitForStatus(t, repo, job.ID, model.ReviewStatusCompleted)
+		assert.Equal(t, 2, completed.Attempts)
+		assert.Empty(t, completed.ErrorMessage)
+
+		// 完了したレビューは再実行できない
+		_, err = retry.Retry(context.Background(), review.RetryReviewInput{ReviewID: job.ID, UserID: "test-user-id"})
+		assert.ErrorIs(t, err, model.ErrReviewJobNotRetryable)
+	})
+
+	t.Run("1日の上限の最後の1件として受け付けたレビューは、ワーカーで利用上限を確認せずに完了する", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		counter := testutil.NewMockQuotaCounter()
+		quota := service.NewQuotaService(testutil.NewMockQuotaRepository(), counter, model.QuotaPolicy{ReviewsPerDay: 1})
+		uc := newQuotaUseCase(repo, testutil.NewMockClaudeClient(), quota)
+
+		job, err := uc.Submit(context.Background(), review.ReviewCodeInput{UserID: "test-user-id", Code: "func main() {}", Language: "Go"})
+		require.NoError(t, err)
+		// 実行待ちのレビューも受け付けた時点で当日のレビュー数に数える
+		usage, err := counter.Usage(context.Background(), "test-user-id", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, 1, usage.ReviewsToday)
+
+		uc.Start()
+		defer shutdown(t, uc)
+		completed := waitForStatus(t, repo, job.ID, model.ReviewStatusCompleted)
+		assert.Empty(t, completed.ErrorMessage)
+
+		// 完了時はトークン数のみ加算し、レビュー数は二重に数えない
+		usage, err = counter.Usage(context.Background(), "test-user-id", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, model.QuotaUsage{ReviewsToday: 1, TokensThisMonth: completed.Usage.Total()}, usage)
+
+		// 上限に達した後は受け付けない
+		_, err = uc.Submit(context.Background(), review.ReviewCodeInput{UserID: "test-user-id", Code: "func main() {}", Language: "Go"})
+		assert.ErrorIs(t, err, model.ErrQuotaExceeded)
+	})
+
+	t.Run("Redisのカウンターでも実行待ちのレビューを上限に数え、失敗したレビューの分は戻す", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		client, err := redis.NewClient(&config.RedisConfig{URL: "redis://" + server.Addr()})
+		require.NoError(t, err)
+		defer client.Close()
+		counter := redis.NewQuotaCounter(client, testutil.NewMockQuotaCounter())
+		quota := service.NewQuotaService(testutil.NewMockQuotaRepository(), counter, model.QuotaPolicy{ReviewsPerDay: 2})
+		llm := testutil.NewMockClaudeClient()
+		llm.SetError(errors.New("LLM API error"))
+		uc := newQuotaUseCase(testutil.NewMockReviewRepository(), llm, quota)
+		submit := func() (*model.Review, error) {
+			return uc.Submit(context.Background(), review.ReviewCodeInput{UserID: "test-user-id", Code: "func main() {}", Language: "Go"})
+		}
+
+		// ワーカーが実行する前でも、上限（2件）を超える受け付けは拒否する
+		_, err = submit()
+		require.NoError(t, err)
+		_, err = submit()
+		require.NoError(t, err)
+		_, err = submit()
+		assert.ErrorIs(t, err, model.ErrQuotaExceeded)
+
+		// 失敗したレビューは数えないため、再び受け付ける
+		uc.Start()
+		defer shutdown(t, uc)
+		require.Eventually(t, func() bool {
+			usage, err := counter.Usage(context.Background(), "test-user-id", time.Now())
+			return err == nil && usage.ReviewsToday == 0
+		}, 2*time.Second, 5*time.Millisecond)
+		_, err = submit()
+		assert.NoError(t, err)
+	})
+
+	t.Run("実行中に他のワーカーが取得し直したジョブには、結果も失敗も保存しない", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		client := testutil.NewMockClaudeClient()
+		uc := newUseCase(repo, client)
+
+		job, err := uc.Submit(context.Background(), review.ReviewCodeInput{UserID: "test-user-id", Code: "func main() {}", Language: "Go"})
+		require.NoError(t, err)
+		// レビュー中に、止まったとみなされて他のサーバーが取得し直す
+		client.SetReviewFunc(func(input external.ReviewCodeInput) (*external.ReviewCodeOutput, error) {
+			_, err := repo.RequeueStaleJobs(context.Background(), time.Now().Add(time.Hour))
+			require.NoError(t, err)
+			_, err = repo.ClaimNextJob(context.Background())
+			require.NoError(t, err)
+			return &external.ReviewCodeOutput{ReviewResult: "stale result"}, nil
+		})
+
+		uc.Start()
+		require.Eventually(t, func() bool { return len(client.Inputs()) == 1 }, 2*time.Second, 5*time.Millisecond)
+		shutdown(t, uc)
+
+		running, err := repo.FindByID(context.Background(), job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ReviewStatusRunning, running.Status)
+		assert.Equal(t, 2, running.Attempts)
+		assert.Empty(t, running.ReviewResult)
+		assert.Empty(t, running.ErrorMessage)
+	})
+
+	t.Run("削除したレビューは実行せず、実行中に削除したレビューの結果は保存しない", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		client := testutil.NewMockClaudeClient()
+		counter := testutil.NewMockQuotaCounter()
+		quota := service.NewQuotaService(testutil.NewMockQuotaRepository(), counter, model.QuotaPolicy{})
+		uc := newQuotaUseCase(repo, client, quota)
+		trash := review.NewReviewTrashUseCase(repo, quota, review.TrashOptions{})
+
+		deleted, err := uc.Submit(context.Background(), review.ReviewCodeInput{UserID: "test-user-id", Code: "func deleted() {}", Language: "Go"})
+		require.NoError(t, err)
+		running, err := uc.Submit(context.Background(), review.ReviewCodeInput{UserID: "test-user-id", Code: "func running() {}", Language: "Go"})
+		require.NoError(t, err)
+		require.NoError(t, trash.Delete(context.Background(), review.DeleteReviewInput{ReviewID: deleted.ID, UserID: "test-user-id"}))
+		client.SetReviewFunc(func(input external.ReviewCodeInput) (*external.ReviewCodeOutput, error) {
+			require.NoError(t, trash.Delete(context.Background(), review.DeleteReviewInput{ReviewID: running.ID, UserID: "test-user-id"}))
+			return &external.ReviewCodeOutput{ReviewResult: "deleted result"}, nil
+		})
+
+		uc.Start()
+		require.Eventually(t, func() bool { return len(client.Inputs()) == 1 }, 2*time.Second, 5*time.Millisecond)
+		shutdown(t, uc)
+
+		assert.Contains(t, client.LastInput().Code, "func running() {}")
+		for _, id := range []string{deleted.ID, running.ID} {
+			trashed, err := repo.FindDeletedByID(context.Background(), id)
+			require.NoError(t, err)
+			assert.Equal(t, model.ReviewStatusFailed, trashed.Status)
+			assert.Equal(t, model.ErrReviewJobCancelled.Error(), trashed.ErrorMessage)
+			assert.Empty(t, trashed.ReviewResult)
+		}
+		// 中止したレビューの受け付けは利用上限から戻す
+		usage, err := counter.Usage(context.Background(), "test-user-id", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, 0, usage.ReviewsToday)
+	})
+
+	t.Run("入力が不正な場合は受け付けない", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		uc := newUseCase(repo, testutil.NewMockClaudeClient())
+
+		_, err := uc.Submit(context.Background(), review.ReviewCodeInput{UserID: "test-user-id", Code: "x", Diff: "diff", Language: "Go"})
+		assert.ErrorIs(t, err, model.ErrDiffWithCode)
+		_, err = uc.Submit(context.Background(), review.ReviewCodeInput{UserID: "test-user-id", Language: "Go"})
+		assert.Error(t, err)
+
+		reviews, err := repo.FindByUserID(context.Background(), "test-user-id", 10)
+		require.NoError(t, err)
+		assert.Empty(t, reviews)
+	})
+}
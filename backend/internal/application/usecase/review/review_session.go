@@ -0,0 +1,305 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
+)
+
+// ReviewSessionUseCase - 複数のファイルをまとめてレビューするユースケース
+// ファイルごとに ReviewCodeUseCase と同じ手順（ナレッジ検索・キャッシュ・分割レビュー）でレビューし、
+// 他のファイルのパスと宣言をコンテキストに加えて、ファイル間の整合性もレビューさせる
+// 結果はファイルごとに1件のレビューとして、親のセッションに紐付けて保存する
+type ReviewSessionUseCase struct {
+	reviewCode  *ReviewCodeUseCase
+	sessionRepo repository.ReviewSessionRepository
+	maxFiles    int
+	concurrency int
+}
+
+// ReviewSessionOptions - 複数ファイルのレビュー設定
+type ReviewSessionOptions struct {
+	MaxFiles    int // 1回のセッションでレビューできる最大ファイル数（0以下は既定値）
+	Concurrency int // ファイルを並行してレビューする数（0以下は既定値）
+}
+
+// 複数ファイルのレビューの既定値
+const (
+	defaultSessionMaxFiles    = 20
+	defaultSessionConcurrency = 4
+	// 他のファイルの宣言としてコンテキストに含める最大行数（ファイルごと）
+	sessionOutlineMaxLines = 15
+	// 既定のタイトルに含めるファイル数
+	sessionTitleMaxFiles = 3
+)
+
+// NewReviewSessionUseCase - コンストラクタ
+func NewReviewSessionUseCase(reviewCode *ReviewCodeUseCase, sessionRepo repository.ReviewSessionRepository, options ReviewSessionOptions) *ReviewSessionUseCase {
+	if options.MaxFiles <= 0 {
+		options.MaxFiles = defaultSessionMaxFiles
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = defaultSessionConcurrency
+	}
+	return &ReviewSessionUseCase{
+		reviewCode:  reviewCode,
+		sessionRepo: sessionRepo,
+		maxFiles:    options.MaxFiles,
+		concurrency: options.Concurrency,
+	}
+}
+
+// ReviewSessionInput - 入力
+type ReviewSessionInput struct {
+	UserID   string
+	Title    string // 省略時はファイルのパスから作る
+	Files    []ReviewSessionFile
+	Language string // ファイルごとの指定も拡張子からの推定もできない場合の言語（オプショナル）
+	Context  string // 全ファイル共通のコンテキスト（オプショナル）
+	// ForceRefresh - キャッシュがあっても使わずにLLMでレビューし直す
+	ForceRefresh bool
+	// Locale - プロンプト・レビュー結果・セッションの総合評価の言語（ja / en）
+	Locale string
+}
+
+// ReviewSessionFile - レビューするファイル
+type ReviewSessionFile struct {
+	Path     string // リポジトリのルートからの相対パス（セッション内で一意）
+	Code     string
+	Language string // 省略時は拡張子から推定
+}
+
+// ReviewSessionOutput - 出力
+type ReviewSessionOutput struct {
+	Session  *model.ReviewSession
+	Reviews  []*model.Review        // ファイルごとのレビュー（指定順）
+	Failures []ReviewSessionFailure // レビューに失敗したファイル
+}
+
+// ReviewSessionFailure - レビューに失敗したファイル
+type ReviewSessionFailure struct {
+	FilePath string
+	Error    string
+}
+
+// MaxFiles - 1回のセッションでレビューできる最大ファイル数
+func (uc *ReviewSessionUseCase) MaxFiles() int {
+	return uc.maxFiles
+}
+
+// Execute - 複数のファイルをレビューしてセッションとして保存
+// 一部のファイルが失敗しても、1つ以上成功すれば成功したファイルの結果を返す
+func (uc *ReviewSessionUseCase) Execute(ctx context.Context, input ReviewSessionInput) (*ReviewSessionOutput, error) {
+	// 0. バリデーション（パスの正規化と言語の決定を含む）
+	files, err := uc.validate(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// LLMを呼び出す前に利用上限を確認
+	if err := uc.reviewCode.CheckQuota(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+
+	// 1. ユーザー設定とロケールは全ファイル共通
+	prefs := uc.reviewCode.loadPreferences(ctx, input.UserID)
+	locale := resolveLocale(input.Locale, prefs)
+	title := input.Title
+	if strings.TrimSpace(title) == "" {
+		title = defaultSessionTitle(files)
+	}
+	session := model.NewReviewSession(input.UserID, strings.TrimSpace(title), locale)
+
+	// 2. ファイルごとに並行してレビュー（保存は全ファイルのレビューが終わってから）
+	results := uc.reviewFiles(ctx, input, files, session.ID, locale, prefs)
+
+	output := &ReviewSessionOutput{Session: session}
+	var firstErr error
+	var usedKnowledges [][]*model.Knowledge
+	var summaries []service.SessionFileReview
+	for i, r := range results {
+		if r.err != nil {
+			log.Printf("Warning: session review failed for %s: %v", files[i].Path, r.err)
+			output.Failures = append(output.Failures, ReviewSessionFailure{FilePath: files[i].Path, Error: r.err.Error()})
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			continue
+		}
+		output.Reviews = append(output.Reviews, r.review)
+		usedKnowledges = append(usedKnowledges, r.knowledges)
+		summaries = append(summaries, service.SessionFileReview{FilePath: files[i].Path, Result: r.review.StructuredResult})
+	}
+	if len(output.Reviews) == 0 {
+		return nil, firstErr
+	}
+
+	// 3. セッションの総合評価と改善点の数をまとめ、セッション → ファイルごとのレビューの順に保存
+	session.SetResult(service.SummarizeSession(summaries, locale), output.Reviews)
+	if err := uc.sessionRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to save review session: %w", err)
+	}
+	for i, review := range output.Reviews {
+		if err := uc.reviewCode.saveReviews(ctx, []*model.Review{review}, usedKnowledges[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return output, nil
+}
+
+// validate - バリデーション。パスを正規化し、言語を決めたファイルを返す
+func (uc *ReviewSessionUseCase) validate(input ReviewSessionInput) ([]ReviewSessionFile, error) {
+	if input.UserID == "" {
+		return nil, fmt.Errorf("ユーザーIDは必須です")
+	}
+	if input.Locale != "" && model.NormalizeLocale(input.Locale) == "" {
+		return nil, model.ErrLocaleInvalid
+	}
+	if len(input.Files) == 0 {
+		return nil, model.ErrReviewSessionFilesRequired
+	}
+	if len(input.Files) > uc.maxFiles {
+		return nil, fmt.Errorf("%w: %d", model.ErrReviewSessionTooManyFiles, uc.maxFiles)
+	}
+
+	files := make([]ReviewSessionFile, len(input.Files))
+	seen := map[string]bool{}
+	for i, f := range input.Files {
+		path := model.NormalizeFilePath(f.Path)
+		if path == "" {
+			return nil, model.ErrReviewSessionFilePathRequired
+		}
+		if seen[path] {
+			return nil, fmt.Errorf("%w: %s", model.ErrReviewSessionFilePathDuplicate, path)
+		}
+		seen[path] = true
+		if strings.TrimSpace(f.Code) == "" {
+			return nil, fmt.Errorf("%w: %s", model.ErrReviewSessionFileCodeRequired, path)
+		}
+
+		// 言語: ファイルごとの指定 → 拡張子からの推定 → セッション共通の指定
+		language := f.Language
+		if language == "" {
+			language = model.LanguageFromPath(path)
+		}
+		if language == "" {
+			language = input.Language
+		}
+		if language == "" {
+			return nil, fmt.Errorf("%w: %s", model.ErrReviewSessionFileLanguageRequired, path)
+		}
+
+		files[i] = ReviewSessionFile{Path: path, Code: f.Code, Language: language}
+	}
+	return files, nil
+}
+
+// fileReviewResult - ファイルごとのレビュー結果（保存前）
+type fileReviewResult struct {
+	review     *model.Review
+	knowledges []*model.Knowledge // 実際に使用したナレッジ
+	err        error
+}
+
+// reviewFiles - 同時実行数を制限してファイルごとにレビューを生成（失敗したファイルは err に記録）
+func (uc *ReviewSessionUseCase) reviewFiles(ctx context.Context, input ReviewSessionInput, files []ReviewSessionFile, sessionID, locale string, prefs *model.UserPreferences) []fileReviewResult {
+	results := make([]fileReviewResult, len(files))
+	sem := make(chan struct{}, uc.concurrency)
+	var wg sync.WaitGroup
+	for i, f := range files {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i].err = ctx.Err()
+				return
+			}
+
+			fileInput := ReviewCodeInput{
+				UserID:       input.UserID,
+				Code:         f.Code,
+				Language:     f.Language,
+				Context:      input.Context,
+				ForceRefresh: input.ForceRefresh,
+				Locale:       locale,
+				FilePath:     f.Path,
+				SessionID:    sessionID,
+			}
+			results[i].review, results[i].knowledges, results[i].err = uc.reviewFile(ctx, fileInput, buildSessionContext(input.Context, files, i, locale), prefs)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// reviewFile - 1ファイル分のレビューを生成（保存はしない）
+// ナレッジはファイルのコードとユーザーのコンテキストで検索し、他のファイルの情報は LLM へのコンテキストにのみ加える
+func (uc *ReviewSessionUseCase) reviewFile(ctx context.Context, input ReviewCodeInput, sessionContext string, prefs *model.UserPreferences) (*model.Review, []*model.Knowledge, error) {
+	// Embeddingの消費トークン数はファイルごとのレビューに記録する
+	ctx, embeddingUsage := external.WithEmbeddingUsageRecorder(ctx)
+
+	chunks, knowledges, err := uc.reviewCode.splitAndRetrieve(ctx, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	input.Context = sessionContext
+	return uc.reviewCode.buildReview(ctx, input, chunks, knowledges, prefs, embeddingUsage, nil)
+}
+
+// buildSessionContext - 同時にレビューしている他のファイルのパスと宣言をコンテキストに付け足す（1ファイルのみの場合は付け足さない）
+func buildSessionContext(userContext string, files []ReviewSessionFile, index int, locale string) string {
+	if len(files) < 2 {
+		return userContext
+	}
+
+	format := "複数のファイルをまとめてレビューしています（%d/%d: %s）。他のファイルで定義されている識別子は未定義として指摘せず、ファイル間で呼び出し方・型・エラー処理の整合性が取れていない箇所があれば指摘してください。\n同時にレビューしている他のファイル:\n%s"
+	if locale == model.LocaleEnglish {
+		format = "This file is part of a multi-file review (%d/%d: %s). Do not report identifiers defined in the other files as undefined, but point out inconsistencies between files in calls, types and error handling.\nOther files in this review:\n%s"
+	}
+
+	var others strings.Builder
+	for i, f := range files {
+		if i == index {
+			continue
+		}
+		fmt.Fprintf(&others, "- %s (%s)\n", f.Path, f.Language)
+		for _, decl := range service.OutlineCode(f.Code, f.Language, sessionOutlineMaxLines) {
+			fmt.Fprintf(&others, "    %s\n", decl)
+		}
+	}
+
+	note := fmt.Sprintf(format, index+1, len(files), files[index].Path, strings.TrimRight(others.String(), "\n"))
+	if userContext == "" {
+		return note
+	}
+	return userContext + "\n\n" + note
+}
+
+// defaultSessionTitle - タイトルが指定されなかった場合のタイトル（先頭のファイルのパスと残りのファイル数）
+func defaultSessionTitle(files []ReviewSessionFile) string {
+	paths := make([]string, 0, sessionTitleMaxFiles)
+	for i, f := range files {
+		if i == sessionTitleMaxFiles {
+			break
+		}
+		paths = append(paths, f.Path)
+	}
+	title := strings.Join(paths, ", ")
+	if rest := len(files) - len(paths); rest > 0 {
+		title += fmt.Sprintf(" +%d", rest)
+	}
+	return title
+}
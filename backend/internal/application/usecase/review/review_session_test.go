@@ -0,0 +1,189 @@
+package review_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
+	"github.com/s7r8/reviewapp/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewSessionUseCase_Execute(t *testing.T) {
+	newUseCase := func(repo *testutil.MockReviewRepository, sessionRepo *testutil.MockReviewSessionRepository, client *testutil.MockClaudeClient) *review.ReviewSessionUseCase {
+		reviewCode := review.NewReviewCodeUseCase(
+			repo,
+			testutil.NewMockKnowledgeRepository(),
+			service.NewReviewService(),
+			client,
+			testutil.NewMockEmbeddingClient(),
+			testutil.NewMockPromptTemplateRepository(),
+			service.NewTemplatePromptRenderer(),
+			service.NewPriceTable(service.DefaultPriceCurrency, service.DefaultModelPrices()),
+			nil,
+			review.ChunkingOptions{},
+			review.CacheOptions{},
+			review.PreferenceOptions{},
+		)
+		return review.NewReviewSessionUseCase(reviewCode, sessionRepo, review.ReviewSessionOptions{MaxFiles: 3})
+	}
+	newClient := func(improvements ...model.Improvement) *testutil.MockClaudeClient {
+		client := testutil.NewMockClaudeClient()
+		client.SetResponse(&external.ReviewCodeOutput{
+			Structured: &model.StructuredReviewResult{
+				Summary:      "読みやすいコードです",
+				GoodPoints:   []string{"命名が明確"},
+				Improvements: improvements,
+			},
+			ResultSource: model.ResultSourceToolUse,
+			TokensUsed:   150,
+			Usage:        model.TokenUsage{InputTokens: 100, OutputTokens: 50},
+		})
+		return client
+	}
+	files := []review.ReviewSessionFile{
+		{Path: "./internal/user/service.go", Code: "func (s *Service) Find(id string) (*User, error) {\n\treturn s.repo.Find(id)\n}"},
+		{Path: "internal\\user\\repository.go", Code: "type Repository interface {\n\tFind(id string) (*User, error)\n}"},
+	}
+	errorHandling := model.Improvement{Title: "エラーを握りつぶしている", Description: "エラーを返す", Severity: "high"}
+
+	t.Run("ファイルごとのレビューを session_id と file_path 付きで保存する", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		sessionRepo := testutil.NewMockReviewSessionRepository()
+		uc := newUseCase(repo, sessionRepo, newClient(errorHandling))
+
+		output, err := uc.Execute(context.Background(), review.ReviewSessionInput{UserID: "test-user-id", Files: files})
+		require.NoError(t, err)
+
+		assert.Empty(t, output.Failures)
+		require.Len(t, output.Reviews, 2)
+		assert.Equal(t, "internal/user/service.go", output.Reviews[0].FilePath)
+		assert.Equal(t, "internal/user/repository.go", output.Reviews[1].FilePath)
+		for _, r := range output.Reviews {
+			assert.Equal(t, "Go", r.Language)
+			require.NotNil(t, r.SessionID)
+			assert.Equal(t, output.Session.ID, *r.SessionID)
+		}
+
+		assert.Equal(t, "internal/user/service.go, internal/user/repository.go", output.Session.Title)
+		assert.Equal(t, 2, output.Session.FileCount)
+		assert.Equal(t, model.ImprovementCounts{Total: 2, High: 2}, output.Session.Improvements)
+		assert.Contains(t, output.Session.Summary, "internal/user/service.go")
+
+		saved, err := repo.FindBySessionID(context.Background(), "test-user-id", output.Session.ID)
+		require.NoError(t, err)
+		assert.Len(t, saved, 2)
+		_, err = sessionRepo.FindByID(context.Background(), "test-user-id", output.Session.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("他のファイルのパスと宣言をコンテキストに加える", func(t *testing.T) {
+		client := newClient()
+		uc := newUseCase(testutil.NewMockReviewRepository(), testutil.NewMockReviewSessionRepository(), client)
+
+		_, err := uc.Execute(context.Background(), review.ReviewSessionInput{UserID: "test-user-id", Files: files, Context: "ユーザー検索の実装"})
+		require.NoError(t, err)
+
+		inputs := client.Inputs()
+		require.Len(t, inputs, 2)
+		for _, in := range inputs {
+			assert.True(t, strings.HasPrefix(in.Context, "ユーザー検索の実装"))
+			if strings.Contains(in.Code, "type Repository interface") {
+				assert.Contains(t, in.Context, "- internal/user/service.go (Go)")
+				assert.Contains(t, in.Context, "func (s *Service) Find(id string) (*User, error)")
+			} else {
+				assert.Contains(t, in.Context, "- internal/user/repository.go (Go)")
+				assert.Contains(t, in.Context, "type Repository interface")
+			}
+		}
+	})
+
+	t.Run("一部のファイルが失敗しても成功したファイルの結果を保存する", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		client := newClient(errorHandling)
+		client.SetReviewFunc(func(input external.ReviewCodeInput) (*external.ReviewCodeOutput, error) {
+			if strings.Contains(input.Code, "interface") {
+				return nil, errors.New("api error")
+			}
+			return &external.ReviewCodeOutput{
+				Structured:   &model.StructuredReviewResult{Summary: "読みやすいコードです", Improvements: []model.Improvement{errorHandling}},
+				ResultSource: model.ResultSourceToolUse,
+			}, nil
+		})
+		uc := newUseCase(repo, testutil.NewMockReviewSessionRepository(), client)
+
+		output, err := uc.Execute(context.Background(), review.ReviewSessionInput{UserID: "test-user-id", Files: files})
+		require.NoError(t, err)
+
+		require.Len(t, output.Reviews, 1)
+		require.Len(t, output.Failures, 1)
+		assert.Equal(t, "internal/user/repository.go", output.Failures[0].FilePath)
+		assert.Equal(t, 1, output.Session.FileCount)
+
+		saved, err := repo.FindBySessionID(context.Background(), "test-user-id", output.Session.ID)
+		require.NoError(t, err)
+		assert.Len(t, saved, 1)
+	})
+
+	t.Run("すべてのファイルが失敗した場合はセッションを保存しない", func(t *testing.T) {
+		client := newClient()
+		client.SetError(errors.New("api error"))
+		sessionRepo := testutil.NewMockReviewSessionRepository()
+		uc := newUseCase(testutil.NewMockReviewRepository(), sessionRepo, client)
+
+		_, err := uc.Execute(context.Background(), review.ReviewSessionInput{UserID: "test-user-id", Files: files})
+		assert.Error(t, err)
+
+		count, err := sessionRepo.CountByUserID(context.Background(), "test-user-id")
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("ファイルの指定が不正な場合はエラー", func(t *testing.T) {
+		uc := newUseCase(testutil.NewMockReviewRepository(), testutil.NewMockReviewSessionRepository(), newClient())
+		execute := func(files ...review.ReviewSessionFile) error {
+			_, err := uc.Execute(context.Background(), review.ReviewSessionInput{UserID: "test-user-id", Files: files})
+			return err
+		}
+
+		assert.ErrorIs(t, execute(), model.ErrReviewSessionFilesRequired)
+		assert.ErrorIs(t, execute(files[0], files[1], files[0], files[1]), model.ErrReviewSessionTooManyFiles)
+		assert.ErrorIs(t, execute(review.ReviewSessionFile{Path: " ", Code: "x"}), model.ErrReviewSessionFilePathRequired)
+		assert.ErrorIs(t, execute(files[0], review.ReviewSessionFile{Path: "internal/user/service.go", Code: "x"}), model.ErrReviewSessionFilePathDuplicate)
+		assert.ErrorIs(t, execute(review.ReviewSessionFile{Path: "main.go", Code: "  "}), model.ErrReviewSessionFileCodeRequired)
+		assert.ErrorIs(t, execute(review.ReviewSessionFile{Path: "Makefile", Code: "all:"}), model.ErrReviewSessionFileLanguageRequired)
+	})
+}
+
+func TestGetReviewSessionUseCase_Execute(t *testing.T) {
+	repo := testutil.NewMockReviewRepository()
+	sessionRepo := testutil.NewMockReviewSessionRepository()
+	session := model.NewReviewSession("test-user-id", "internal/user", "ja")
+	require.NoError(t, sessionRepo.Create(context.Background(), session))
+	for _, path := range []string{"internal/user/service.go", "internal/user/repository.go"} {
+		r := model.NewReview("test-user-id", "package user", "Go", "")
+		r.SetFile(path)
+		r.SetSession(session.ID)
+		require.NoError(t, repo.Create(context.Background(), r))
+	}
+	uc := review.NewGetReviewSessionUseCase(repo, sessionRepo)
+
+	t.Run("セッションとファイルごとのレビューを返す", func(t *testing.T) {
+		output, err := uc.Execute(context.Background(), review.GetReviewSessionInput{SessionID: session.ID, UserID: "test-user-id"})
+		require.NoError(t, err)
+
+		assert.Equal(t, session.ID, output.Session.ID)
+		assert.Len(t, output.Reviews, 2)
+	})
+
+	t.Run("他のユーザーのセッションは取得できない", func(t *testing.T) {
+		_, err := uc.Execute(context.Background(), review.GetReviewSessionInput{SessionID: session.ID, UserID: "other-user-id"})
+		assert.ErrorIs(t, err, model.ErrReviewSessionNotFound)
+	})
+}
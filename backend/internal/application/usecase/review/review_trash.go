@@ -0,0 +1,244 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+)
+
+// ReviewTrashUseCase - レビューの削除（ゴミ箱への移動）・ゴミ箱の一覧・復元のユースケース
+// 削除したレビューは保持期間のあいだゴミ箱に残し、ReviewPurgeUseCase が保持期間を過ぎたものを完全削除する
+type ReviewTrashUseCase struct {
+	reviewRepo   repository.ReviewRepository
+	quotaService *service.QuotaService
+	retention    time.Duration
+}
+
+// 削除したレビューを残す期間の既定値
+const defaultReviewTrashRetention = 31 * 24 * time.Hour
+
+// TrashOptions - 削除したレビュー（ゴミ箱）の設定
+type TrashOptions struct {
+	Retention     time.Duration // ゴミ箱に残す期間（0以下は既定値）
+	PurgeInterval time.Duration // 保持期間を過ぎたレビューを完全削除する間隔（0以下は定期的に完全削除しない）
+}
+
+// NewReviewTrashUseCase - コンストラクタ
+// options.Retention はゴミ箱の一覧の、完全削除する予定日時の計算に使う
+// quotaService: 削除で中止した非同期のレビューの受け付けを利用上限から戻す（nil の場合は戻さない）
+func NewReviewTrashUseCase(reviewRepo repository.ReviewRepository, quotaService *service.QuotaService, options TrashOptions) *ReviewTrashUseCase {
+	if options.Retention <= 0 {
+		options.Retention = defaultReviewTrashRetention
+	}
+	return &ReviewTrashUseCase{
+		reviewRepo:   reviewRepo,
+		quotaService: quotaService,
+		retention:    options.Retention,
+	}
+}
+
+// DeleteReviewInput - 入力
+type DeleteReviewInput struct {
+	ReviewID string
+	UserID   string // 権限チェック用
+}
+
+// BulkDeleteReviewsInput - 入力（条件は GET /api/v1/reviews と同じ。1つ以上必須）
+type BulkDeleteReviewsInput struct {
+	UserID     string
+	Language   string
+	Status     string
+	SessionID  string
+	RerunOf    string
+	FollowUpOf string
+	DateFrom   *time.Time
+	DateTo     *time.Time
+}
+
+// ListTrashInput - 入力
+type ListTrashInput struct {
+	UserID   string
+	Page     int
+	PageSize int
+}
+
+// ListTrashOutput - 出力
+type ListTrashOutput struct {
+	Items      []*model.Review
+	Total      int
+	Page       int
+	PageSize   int
+	TotalPages int
+	Retention  time.Duration // 削除日時からこの期間を過ぎたレビューは完全削除する
+}
+
+// RestoreReviewInput - 入力
+type RestoreReviewInput struct {
+	ReviewID string
+	UserID   string // 権限チェック用
+}
+
+// Delete - レビューをゴミ箱に移動する（自分のレビューのみ）
+func (uc *ReviewTrashUseCase) Delete(ctx context.Context, input DeleteReviewInput) error {
+	// 1. レビューの存在確認
+	review, err := uc.reviewRepo.FindByID(ctx, input.ReviewID)
+	if errors.Is(err, model.ErrReviewNotFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find review: %w", err)
+	}
+
+	// 2. 権限チェック
+	if review.UserID != input.UserID {
+		return model.ErrReviewDeleteForbidden
+	}
+
+	// 3. ゴミ箱に移動（実行待ち・実行中の非同期のレビューは中止し、受け付けを利用上限から戻す）
+	cancelled, err := uc.reviewRepo.SoftDelete(ctx, input.ReviewID)
+	if err != nil {
+		if errors.Is(err, model.ErrReviewNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to delete review: %w", err)
+	}
+	if cancelled {
+		uc.releaseQuota(ctx, []*model.Review{review})
+	}
+	return nil
+}
+
+// BulkDelete - 条件に合致する自分のレビューをゴミ箱に移動し、件数を返す
+func (uc *ReviewTrashUseCase) BulkDelete(ctx context.Context, input BulkDeleteReviewsInput) (int, error) {
+	// 1. バリデーション（条件なしの全件削除は受け付けない）
+	filters, err := bulkDeleteFilters(input)
+	if err != nil {
+		return 0, err
+	}
+
+	// 2. ゴミ箱に移動（実行待ち・実行中の非同期のレビューは中止し、受け付けを利用上限から戻す）
+	count, cancelled, err := uc.reviewRepo.SoftDeleteWithFilters(ctx, input.UserID, filters)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete reviews: %w", err)
+	}
+	uc.releaseQuota(ctx, cancelled)
+	return count, nil
+}
+
+// releaseQuota - 中止した非同期のレビューの受け付けを利用上限のレビュー数から戻す（失敗してもレビューの削除は完了とする）
+func (uc *ReviewTrashUseCase) releaseQuota(ctx context.Context, cancelled []*model.Review) {
+	if uc.quotaService == nil {
+		return
+	}
+	for _, job := range cancelled {
+		if err := uc.quotaService.Release(ctx, job); err != nil {
+			log.Printf("Warning: failed to release quota of cancelled review job %s: %v", job.ID, err)
+		}
+	}
+}
+
+// ListTrash - 自分のゴミ箱のレビューを削除日時の新しい順に取得
+func (uc *ReviewTrashUseCase) ListTrash(ctx context.Context, input ListTrashInput) (*ListTrashOutput, error) {
+	// デフォルト値の設定（GET /api/v1/reviews と同じ）
+	if input.Page <= 0 {
+		input.Page = 1
+	}
+	if input.PageSize <= 0 {
+		input.PageSize = 10
+	}
+	if input.PageSize > 100 {
+		input.PageSize = 100
+	}
+
+	reviews, err := uc.reviewRepo.ListDeleted(ctx, input.UserID, input.PageSize, (input.Page-1)*input.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted reviews: %w", err)
+	}
+	total, err := uc.reviewRepo.CountDeleted(ctx, input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count deleted reviews: %w", err)
+	}
+
+	return &ListTrashOutput{
+		Items:      reviews,
+		Total:      total,
+		Page:       input.Page,
+		PageSize:   input.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(input.PageSize))),
+		Retention:  uc.retention,
+	}, nil
+}
+
+// Restore - ゴミ箱のレビューを元に戻す（自分のレビューのみ）
+func (uc *ReviewTrashUseCase) Restore(ctx context.Context, input RestoreReviewInput) (*model.Review, error) {
+	// 1. ゴミ箱のレビューの存在確認
+	deleted, err := uc.reviewRepo.FindDeletedByID(ctx, input.ReviewID)
+	if errors.Is(err, model.ErrReviewNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deleted review: %w", err)
+	}
+
+	// 2. 権限チェック
+	if deleted.UserID != input.UserID {
+		return nil, model.ErrReviewRestoreForbidden
+	}
+
+	// 3. 元に戻して、レビューを取得し直す
+	if err := uc.reviewRepo.Restore(ctx, input.ReviewID); err != nil {
+		if errors.Is(err, model.ErrReviewNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to restore review: %w", err)
+	}
+	review, err := uc.reviewRepo.FindByID(ctx, input.ReviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find restored review: %w", err)
+	}
+	return review, nil
+}
+
+// bulkDeleteFilters - 一括削除の条件を検証し、リポジトリのフィルターに変換する
+func bulkDeleteFilters(input BulkDeleteReviewsInput) (map[string]interface{}, error) {
+	if input.Status != "" && !stringInSlice(input.Status, model.ValidReviewStatuses) {
+		return nil, fmt.Errorf("%w: 無効なステータスです: %s", model.ErrReviewFilterInvalid, input.Status)
+	}
+	if input.DateFrom != nil && input.DateTo != nil && input.DateFrom.After(*input.DateTo) {
+		return nil, fmt.Errorf("%w: 開始日は終了日より前である必要があります", model.ErrReviewFilterInvalid)
+	}
+
+	filters := make(map[string]interface{})
+	if input.Language != "" {
+		filters["language"] = input.Language
+	}
+	if input.Status != "" {
+		filters["status"] = input.Status
+	}
+	if input.SessionID != "" {
+		filters["session_id"] = input.SessionID
+	}
+	if input.RerunOf != "" {
+		filters["rerun_of"] = input.RerunOf
+	}
+	if input.FollowUpOf != "" {
+		filters["follow_up_of"] = input.FollowUpOf
+	}
+	if input.DateFrom != nil {
+		filters["date_from"] = *input.DateFrom
+	}
+	if input.DateTo != nil {
+		filters["date_to"] = *input.DateTo
+	}
+	if len(filters) == 0 {
+		return nil, model.ErrReviewFilterRequired
+	}
+	return filters, nil
+}
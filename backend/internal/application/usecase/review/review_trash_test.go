@@ -0,0 +1,134 @@
+package review_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewTrashUseCase(t *testing.T) {
+	newReview := func(t *testing.T, repo *testutil.MockReviewRepository, userID, language string) *model.Review {
+		r := model.NewReview(userID, "func main() {}", language, "")
+		r.SetReviewResult("result", &model.StructuredReviewResult{Summary: "summary"}, nil, "mock", "mock-model", 100)
+		require.NoError(t, repo.Create(context.Background(), r))
+		return r
+	}
+
+	t.Run("削除したレビューは取得できず、ゴミ箱から復元できる", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		uc := review.NewReviewTrashUseCase(repo, nil, review.TrashOptions{Retention: 24 * time.Hour})
+		r := newReview(t, repo, "test-user-id", "Go")
+
+		require.NoError(t, uc.Delete(context.Background(), review.DeleteReviewInput{ReviewID: r.ID, UserID: "test-user-id"}))
+		_, err := repo.FindByID(context.Background(), r.ID)
+		assert.ErrorIs(t, err, model.ErrReviewNotFound)
+
+		trash, err := uc.ListTrash(context.Background(), review.ListTrashInput{UserID: "test-user-id"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, trash.Total)
+		require.Len(t, trash.Items, 1)
+		assert.Equal(t, r.ID, trash.Items[0].ID)
+		assert.Equal(t, trash.Items[0].DeletedAt.Add(24*time.Hour), *trash.Items[0].PurgeAt(trash.Retention))
+
+		restored, err := uc.Restore(context.Background(), review.RestoreReviewInput{ReviewID: r.ID, UserID: "test-user-id"})
+		require.NoError(t, err)
+		assert.Equal(t, r.ID, restored.ID)
+		assert.Nil(t, restored.DeletedAt)
+
+		// 削除済みのレビュー・存在しないレビューは削除できない
+		err = uc.Delete(context.Background(), review.DeleteReviewInput{ReviewID: "missing", UserID: "test-user-id"})
+		assert.ErrorIs(t, err, model.ErrReviewNotFound)
+		_, err = uc.Restore(context.Background(), review.RestoreReviewInput{ReviewID: r.ID, UserID: "test-user-id"})
+		assert.ErrorIs(t, err, model.ErrReviewNotFound)
+	})
+
+	t.Run("他のユーザーのレビューは削除・復元できない", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		uc := review.NewReviewTrashUseCase(repo, nil, review.TrashOptions{})
+		r := newReview(t, repo, "test-user-id", "Go")
+
+		err := uc.Delete(context.Background(), review.DeleteReviewInput{ReviewID: r.ID, UserID: "other-user-id"})
+		assert.ErrorIs(t, err, model.ErrReviewDeleteForbidden)
+
+		require.NoError(t, uc.Delete(context.Background(), review.DeleteReviewInput{ReviewID: r.ID, UserID: "test-user-id"}))
+		_, err = uc.Restore(context.Background(), review.RestoreReviewInput{ReviewID: r.ID, UserID: "other-user-id"})
+		assert.ErrorIs(t, err, model.ErrReviewRestoreForbidden)
+
+		trash, err := uc.ListTrash(context.Background(), review.ListTrashInput{UserID: "other-user-id"})
+		require.NoError(t, err)
+		assert.Empty(t, trash.Items)
+	})
+
+	t.Run("条件に合致する自分のレビューのみ一括削除し、条件なしは受け付けない", func(t *testing.T) {
+		repo := testutil.NewMockReviewRepository()
+		uc := review.NewReviewTrashUseCase(repo, nil, review.TrashOptions{})
+		goReview := newReview(t, repo, "test-user-id", "Go")
+		newReview(t, repo, "test-user-id", "Python")
+		newReview(t, repo, "other-user-id", "Go")
+
+		count, err := uc.BulkDelete(context.Background(), review.BulkDeleteReviewsInput{UserID: "test-user-id", Language: "Go"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		_, err = repo.FindByID(context.Background(), goReview.ID)
+		assert.ErrorIs(t, err, model.ErrReviewNotFound)
+		remaining, err := repo.CountWithFilters(context.Background(), "test-user-id", nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, remaining)
+
+		_, err = uc.BulkDelete(context.Background(), review.BulkDeleteReviewsInput{UserID: "test-user-id"})
+		assert.ErrorIs(t, err, model.ErrReviewFilterRequired)
+		_, err = uc.BulkDelete(context.Background(), review.BulkDeleteReviewsInput{UserID: "test-user-id", Status: "unknown"})
+		assert.ErrorIs(t, err, model.ErrReviewFilterInvalid)
+	})
+}
+
+func TestReviewPurgeUseCase_Purge(t *testing.T) {
+	repo := testutil.NewMockReviewRepository()
+	deletedAt := func(ago time.Duration) *model.Review {
+		r := model.NewReview("test-user-id", "func main() {}", "Go", "")
+		r.SetUsage(model.TokenUsage{InputTokens: 100, OutputTokens: 50}, 0.01, "USD")
+		at := time.Now().Add(-ago)
+		r.DeletedAt = &at
+		require.NoError(t, repo.Create(context.Background(), r))
+		return r
+	}
+	expired := deletedAt(48 * time.Hour)
+	kept := deletedAt(time.Hour)
+	active := model.NewReview("test-user-id", "func main() {}", "Go", "")
+	active.SetUsage(model.TokenUsage{InputTokens: 100, OutputTokens: 50}, 0.01, "USD")
+	require.NoError(t, repo.Create(context.Background(), active))
+
+	uc := review.NewReviewPurgeUseCase(repo, review.TrashOptions{Retention: 24 * time.Hour})
+	count, err := uc.Purge(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, count)
+	_, err = repo.FindDeletedByID(context.Background(), expired.ID)
+	assert.ErrorIs(t, err, model.ErrReviewNotFound)
+	_, err = repo.FindDeletedByID(context.Background(), kept.ID)
+	assert.NoError(t, err)
+	_, err = repo.FindByID(context.Background(), active.ID)
+	assert.NoError(t, err)
+
+	// 内容を消したレビューも使用量の集計に含める（利用上限が戻らない）
+	summaries, err := repo.AggregateUsage(context.Background(), model.UsageFilter{
+		From:   time.Now().Add(-time.Hour),
+		To:     time.Now().Add(time.Hour),
+		UserID: "test-user-id",
+	})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 3, summaries[0].ReviewCount)
+	assert.Equal(t, 300, summaries[0].Usage.InputTokens)
+
+	// 2回目は対象がない
+	count, err = uc.Purge(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
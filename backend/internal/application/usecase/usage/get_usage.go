@@ -0,0 +1,143 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+)
+
+// 集計期間の既定値と上限（日数）
+const (
+	defaultUsageDays = 30
+	maxUsageDays     = 366
+)
+
+// GetUsageUseCase - トークン使用量・コスト集計のユースケース
+type GetUsageUseCase struct {
+	reviewRepo repository.ReviewRepository
+}
+
+// NewGetUsageUseCase - コンストラクタ
+func NewGetUsageUseCase(reviewRepo repository.ReviewRepository) *GetUsageUseCase {
+	return &GetUsageUseCase{
+		reviewRepo: reviewRepo,
+	}
+}
+
+// GetUsageInput - 入力
+type GetUsageInput struct {
+	From    string   // YYYY-MM-DD（UTC・この日を含む）。省略時は To の29日前
+	To      string   // YYYY-MM-DD（UTC・この日を含む）。省略時は今日
+	UserID  string   // 省略時は全ユーザー
+	GroupBy []string // day, model, user の組み合わせ。省略時は day, model, user すべて
+}
+
+// GetUsageOutput - 出力
+type GetUsageOutput struct {
+	From    string                `json:"from"`
+	To      string                `json:"to"`
+	GroupBy []string              `json:"group_by"`
+	Rows    []*model.UsageSummary `json:"rows"`
+	Totals  []*model.UsageSummary `json:"totals"` // 通貨ごとの合計
+}
+
+// Execute - 期間内の使用量とコストを集計
+func (uc *GetUsageUseCase) Execute(ctx context.Context, input GetUsageInput) (*GetUsageOutput, error) {
+	// 1. 集計期間を決定
+	from, to, err := parseUsageRange(input.From, input.To, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 集計単位を検証（重複は除く）
+	groupBy := input.GroupBy
+	if len(groupBy) == 0 {
+		groupBy = []string{model.UsageGroupDay, model.UsageGroupModel, model.UsageGroupUser}
+	}
+	seen := map[string]bool{}
+	var groups []string
+	for _, group := range groupBy {
+		switch group {
+		case model.UsageGroupDay, model.UsageGroupModel, model.UsageGroupUser:
+		default:
+			return nil, fmt.Errorf("%w: %s", model.ErrUsageGroupInvalid, group)
+		}
+		if !seen[group] {
+			seen[group] = true
+			groups = append(groups, group)
+		}
+	}
+
+	// 3. 集計（to はその日を含むため翌日0時より前）
+	rows, err := uc.reviewRepo.AggregateUsage(ctx, model.UsageFilter{
+		From:    from,
+		To:      to.AddDate(0, 0, 1),
+		UserID:  input.UserID,
+		GroupBy: groups,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate usage: %w", err)
+	}
+
+	return &GetUsageOutput{
+		From:    from.Format(time.DateOnly),
+		To:      to.Format(time.DateOnly),
+		GroupBy: groups,
+		Rows:    rows,
+		Totals:  sumByCurrency(rows),
+	}, nil
+}
+
+// parseUsageRange - 集計期間をパース（省略時は直近30日）
+func parseUsageRange(fromStr, toStr string, now time.Time) (time.Time, time.Time, error) {
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if toStr != "" {
+		parsed, err := time.Parse(time.DateOnly, toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: to は YYYY-MM-DD 形式で指定してください", model.ErrUsageRangeInvalid)
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -(defaultUsageDays - 1))
+	if fromStr != "" {
+		parsed, err := time.Parse(time.DateOnly, fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: from は YYYY-MM-DD 形式で指定してください", model.ErrUsageRangeInvalid)
+		}
+		from = parsed
+	}
+
+	if to.Before(from) || to.Sub(from) >= maxUsageDays*24*time.Hour {
+		return time.Time{}, time.Time{}, model.ErrUsageRangeInvalid
+	}
+
+	return from, to, nil
+}
+
+// sumByCurrency - 集計行を通貨ごとに合計
+func sumByCurrency(rows []*model.UsageSummary) []*model.UsageSummary {
+	totals := []*model.UsageSummary{}
+	byCurrency := map[string]*model.UsageSummary{}
+	for _, row := range rows {
+		total, ok := byCurrency[row.Currency]
+		if !ok {
+			total = &model.UsageSummary{Currency: row.Currency}
+			byCurrency[row.Currency] = total
+			totals = append(totals, total)
+		}
+		total.ReviewCount += row.ReviewCount
+		total.Usage = total.Usage.Add(row.Usage)
+		total.Cost += row.Cost
+	}
+
+	// 浮動小数点の誤差を除くため、行と同じ小数点以下6桁に丸める
+	for _, total := range totals {
+		total.Cost = math.Round(total.Cost*1e6) / 1e6
+	}
+	return totals
+}
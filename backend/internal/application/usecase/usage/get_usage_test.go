@@ -0,0 +1,98 @@
+package usage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/application/usecase/usage"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUsageReview - 使用量とコストを設定したレビューを生成
+func newUsageReview(userID, llmModel string, createdAt time.Time, usage model.TokenUsage, cost float64) *model.Review {
+	r := model.NewReview(userID, "code", "go", "")
+	r.LLMProvider = "anthropic"
+	r.LLMModel = llmModel
+	r.SetUsage(usage, cost, "USD")
+	r.CreatedAt = createdAt
+	return r
+}
+
+func TestGetUsageUseCase_Execute(t *testing.T) {
+	reviewRepo := testutil.NewMockReviewRepository()
+	day1 := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 1, 11, 23, 59, 0, 0, time.UTC)
+	for _, r := range []*model.Review{
+		newUsageReview("user-a", "claude-3-5-haiku-20241022", day1, model.TokenUsage{InputTokens: 1000, OutputTokens: 200, EmbeddingTokens: 50}, 0.0016),
+		newUsageReview("user-a", "claude-3-5-haiku-20241022", day1.Add(time.Hour), model.TokenUsage{InputTokens: 500, OutputTokens: 100}, 0.0008),
+		newUsageReview("user-b", "claude-3-5-haiku-20241022", day2, model.TokenUsage{InputTokens: 2000, OutputTokens: 400}, 0.0032),
+		// 1/12 の分（期間を 1/11 までにした場合は集計に含まれない）
+		newUsageReview("user-b", "claude-3-5-haiku-20241022", day2.Add(time.Hour), model.TokenUsage{InputTokens: 9999}, 9.9),
+	} {
+		require.NoError(t, reviewRepo.Create(context.Background(), r))
+	}
+	uc := usage.NewGetUsageUseCase(reviewRepo)
+
+	t.Run("ユーザー別に集計し、通貨ごとの合計を返す", func(t *testing.T) {
+		output, err := uc.Execute(context.Background(), usage.GetUsageInput{
+			From:    "2025-01-10",
+			To:      "2025-01-11",
+			GroupBy: []string{"user", "user"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "2025-01-10", output.From)
+		assert.Equal(t, "2025-01-11", output.To)
+		assert.Equal(t, []string{"user"}, output.GroupBy)
+		require.Len(t, output.Rows, 2)
+		assert.Equal(t, "user-a", output.Rows[0].UserID)
+		assert.Equal(t, 2, output.Rows[0].ReviewCount)
+		assert.Equal(t, 1500, output.Rows[0].Usage.InputTokens)
+		assert.Equal(t, 50, output.Rows[0].Usage.EmbeddingTokens)
+
+		require.Len(t, output.Totals, 1)
+		assert.Equal(t, "USD", output.Totals[0].Currency)
+		assert.Equal(t, 3, output.Totals[0].ReviewCount)
+		assert.Equal(t, 0.0056, output.Totals[0].Cost)
+	})
+
+	t.Run("ユーザーを指定して日別に集計", func(t *testing.T) {
+		output, err := uc.Execute(context.Background(), usage.GetUsageInput{
+			From:    "2025-01-01",
+			To:      "2025-01-31",
+			UserID:  "user-b",
+			GroupBy: []string{"day"},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, output.Rows, 2)
+		assert.Equal(t, "2025-01-11", output.Rows[0].Day)
+		assert.Equal(t, "2025-01-12", output.Rows[1].Day)
+	})
+
+	t.Run("group_by 省略時は日・モデル・ユーザーで集計", func(t *testing.T) {
+		output, err := uc.Execute(context.Background(), usage.GetUsageInput{From: "2025-01-10", To: "2025-01-10"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"day", "model", "user"}, output.GroupBy)
+		require.Len(t, output.Rows, 1)
+		assert.Equal(t, "claude-3-5-haiku-20241022", output.Rows[0].LLMModel)
+	})
+
+	t.Run("バリデーションエラー", func(t *testing.T) {
+		for _, input := range []usage.GetUsageInput{
+			{GroupBy: []string{"language"}},
+			{From: "2025/01/01"},
+			{From: "2025-01-31", To: "2025-01-01"},
+			{From: "2024-01-01", To: "2025-01-31"},
+		} {
+			_, err := uc.Execute(context.Background(), input)
+			assert.True(t, errors.Is(err, model.ErrUsageGroupInvalid) || errors.Is(err, model.ErrUsageRangeInvalid), "%+v: %v", input, err)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
+)
+
+// GetPreferencesUseCase - ユーザーのレビュー設定を取得するユースケース
+type GetPreferencesUseCase struct {
+	userRepo    repository.UserRepository
+	llmProvider external.LLMProvider
+}
+
+// NewGetPreferencesUseCase - コンストラクタ
+// llmProvider: 設定を省略した場合に使う既定のプロバイダ（LLM_PROVIDER）
+func NewGetPreferencesUseCase(userRepo repository.UserRepository, llmProvider external.LLMProvider) *GetPreferencesUseCase {
+	return &GetPreferencesUseCase{
+		userRepo:    userRepo,
+		llmProvider: llmProvider,
+	}
+}
+
+// GetPreferencesInput - 入力
+type GetPreferencesInput struct {
+	UserID string
+}
+
+// GetPreferencesOutput - 出力
+type GetPreferencesOutput struct {
+	Preferences *model.UserPreferences `json:"preferences"`
+	Defaults    PreferenceDefaults     `json:"defaults"` // 設定を省略した項目に使う値
+}
+
+// PreferenceDefaults - サーバーの既定のプロバイダ・モデル
+type PreferenceDefaults struct {
+	LLMProvider string `json:"llm_provider"`
+	LLMModel    string `json:"llm_model"`
+}
+
+// Execute - 保存されたレビュー設定を取得
+func (uc *GetPreferencesUseCase) Execute(ctx context.Context, input GetPreferencesInput) (*GetPreferencesOutput, error) {
+	user, err := uc.userRepo.FindByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	prefs, err := user.GetPreferences()
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetPreferencesOutput{
+		Preferences: prefs,
+		Defaults: PreferenceDefaults{
+			LLMProvider: uc.llmProvider.Name(),
+			LLMModel:    uc.llmProvider.Model(),
+		},
+	}, nil
+}
@@ -0,0 +1,90 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
+	"github.com/s7r8/reviewapp/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPreferencesTestUser(t *testing.T) *testutil.MockUserRepository {
+	t.Helper()
+	repo := testutil.NewMockUserRepository()
+	user := model.NewUser("auth0|123456789", "test@example.com", "Test User")
+	user.ID = "test-user-id"
+	repo.SetUser(user)
+	return repo
+}
+
+func TestUpdatePreferencesUseCase_Execute(t *testing.T) {
+	providers := func(provider, modelName string) (external.LLMProvider, error) {
+		if provider != external.ProviderOllama {
+			return nil, errors.New("unsupported LLM provider")
+		}
+		return testutil.NewMockClaudeClient(), nil
+	}
+	temperature := 0.3
+	maxTokens := 1024
+
+	t.Run("検証して保存し、取得できる", func(t *testing.T) {
+		repo := newPreferencesTestUser(t)
+		uc := NewUpdatePreferencesUseCase(repo, providers)
+
+		output, err := uc.Execute(context.Background(), UpdatePreferencesInput{
+			UserID: "test-user-id",
+			Preferences: model.UserPreferences{
+				LLMProvider:    "ollama",
+				LLMModel:       "qwen2.5-coder:7b",
+				Temperature:    &temperature,
+				MaxTokens:      &maxTokens,
+				OutputLanguage: "en",
+				ReviewFocus:    []string{"security", " security", "testing"},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"security", "testing"}, output.Preferences.ReviewFocus)
+
+		got, err := NewGetPreferencesUseCase(repo, testutil.NewMockClaudeClient()).Execute(context.Background(), GetPreferencesInput{UserID: "test-user-id"})
+		require.NoError(t, err)
+		assert.Equal(t, output.Preferences, got.Preferences)
+		assert.Equal(t, PreferenceDefaults{LLMProvider: "mock", LLMModel: "mock-model"}, got.Defaults)
+	})
+
+	t.Run("値が不正な場合はエラー", func(t *testing.T) {
+		uc := NewUpdatePreferencesUseCase(newPreferencesTestUser(t), providers)
+		tooHot := 1.5
+		zero := 0
+
+		cases := map[error]model.UserPreferences{
+			model.ErrPreferenceTemperatureInvalid:    {Temperature: &tooHot},
+			model.ErrPreferenceMaxTokensInvalid:      {MaxTokens: &zero},
+			model.ErrPreferenceOutputLanguageInvalid: {OutputLanguage: "fr"},
+			model.ErrPreferenceReviewFocusInvalid:    {ReviewFocus: []string{"style"}},
+			model.ErrPreferenceProviderUnavailable:   {LLMProvider: "gemini"},
+		}
+		for want, prefs := range cases {
+			_, err := uc.Execute(context.Background(), UpdatePreferencesInput{UserID: "test-user-id", Preferences: prefs})
+			assert.ErrorIs(t, err, want)
+		}
+	})
+
+	t.Run("ユーザーが存在しない場合はエラー", func(t *testing.T) {
+		uc := NewUpdatePreferencesUseCase(testutil.NewMockUserRepository(), providers)
+
+		_, err := uc.Execute(context.Background(), UpdatePreferencesInput{UserID: "unknown"})
+		assert.ErrorIs(t, err, model.ErrUserNotFound)
+	})
+}
+
+func TestGetPreferencesUseCase_Execute_Empty(t *testing.T) {
+	uc := NewGetPreferencesUseCase(newPreferencesTestUser(t), testutil.NewMockClaudeClient())
+
+	output, err := uc.Execute(context.Background(), GetPreferencesInput{UserID: "test-user-id"})
+	require.NoError(t, err)
+	assert.Equal(t, &model.UserPreferences{}, output.Preferences)
+}
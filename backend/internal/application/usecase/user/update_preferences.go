@@ -0,0 +1,73 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
+)
+
+// UpdatePreferencesUseCase - ユーザーのレビュー設定を更新するユースケース
+type UpdatePreferencesUseCase struct {
+	userRepo  repository.UserRepository
+	providers external.LLMProviderFactory
+}
+
+// NewUpdatePreferencesUseCase - コンストラクタ
+// providers: 指定されたプロバイダ・モデルが使えるかの確認に使う
+func NewUpdatePreferencesUseCase(userRepo repository.UserRepository, providers external.LLMProviderFactory) *UpdatePreferencesUseCase {
+	return &UpdatePreferencesUseCase{
+		userRepo:  userRepo,
+		providers: providers,
+	}
+}
+
+// UpdatePreferencesInput - 入力（設定全体を置き換える。省略した項目はサーバーの設定を使う）
+type UpdatePreferencesInput struct {
+	UserID      string
+	Preferences model.UserPreferences
+}
+
+// UpdatePreferencesOutput - 出力
+type UpdatePreferencesOutput struct {
+	Preferences *model.UserPreferences `json:"preferences"`
+}
+
+// Execute - レビュー設定を検証して保存
+func (uc *UpdatePreferencesUseCase) Execute(ctx context.Context, input UpdatePreferencesInput) (*UpdatePreferencesOutput, error) {
+	// 1. バリデーション
+	prefs := input.Preferences
+	prefs.Normalize()
+	if err := prefs.Validate(); err != nil {
+		return nil, err
+	}
+	if prefs.LLMProvider != "" && uc.providers != nil {
+		if _, err := uc.providers(prefs.LLMProvider, prefs.LLMModel); err != nil {
+			return nil, fmt.Errorf("%w: %s", model.ErrPreferenceProviderUnavailable, prefs.LLMProvider)
+		}
+	}
+
+	// 2. ユーザーを取得
+	user, err := uc.userRepo.FindByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	// 3. 保存
+	if err := user.SetPreferences(&prefs); err != nil {
+		return nil, err
+	}
+	user.UpdatedAt = time.Now()
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user preferences: %w", err)
+	}
+
+	return &UpdatePreferencesOutput{Preferences: &prefs}, nil
+}
@@ -5,28 +5,39 @@ package di
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/google/wire"
 	"github.com/s7r8/reviewapp/internal/application/usecase/dashboard"
 	"github.com/s7r8/reviewapp/internal/application/usecase/knowledge"
+	"github.com/s7r8/reviewapp/internal/application/usecase/prompt"
+	"github.com/s7r8/reviewapp/internal/application/usecase/quota"
 	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/application/usecase/usage"
+	"github.com/s7r8/reviewapp/internal/application/usecase/user"
+	"github.com/s7r8/reviewapp/internal/domain/model"
 	"github.com/s7r8/reviewapp/internal/domain/repository"
 	"github.com/s7r8/reviewapp/internal/domain/service"
 	"github.com/s7r8/reviewapp/internal/infrastructure/config"
 	"github.com/s7r8/reviewapp/internal/infrastructure/external"
 	"github.com/s7r8/reviewapp/internal/infrastructure/persistence/postgres"
+	"github.com/s7r8/reviewapp/internal/infrastructure/persistence/redis"
 	"github.com/s7r8/reviewapp/internal/interfaces/http/handler"
 )
 
 // InitializeKnowledgeHandler - KnowledgeHandlerを初期化（Wireが自動生成）
-func InitializeKnowledgeHandler(db *sql.DB, cfg *config.Config) (*handler.KnowledgeHandler, error) {
+func InitializeKnowledgeHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache) (*handler.KnowledgeHandler, error) {
 	wire.Build(
 		// Repository
 		postgres.NewKnowledgeRepository,
 		wire.Bind(new(repository.KnowledgeRepository), new(*postgres.KnowledgeRepository)),
 		// External
-		ProvideOpenAIClient,
-		wire.Bind(new(external.EmbeddingClientInterface), new(*external.OpenAIClient)),
+		ProvideEmbeddingClient,
 		// UseCase
 		knowledge.NewCreateKnowledgeUseCase,
 		knowledge.NewUpdateKnowledgeUseCase,
@@ -40,25 +51,40 @@ func InitializeKnowledgeHandler(db *sql.DB, cfg *config.Config) (*handler.Knowle
 }
 
 // InitializeReviewHandler - ReviewHandlerを初期化（Wireが自動生成）
-func InitializeReviewHandler(db *sql.DB, cfg *config.Config) (*handler.ReviewHandler, error) {
+// 非同期のレビューのワーカーは main で起動・停止するため、ReviewJobUseCase は引数で受け取る
+func InitializeReviewHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache, reviewJobUsecase *review.ReviewJobUseCase) (*handler.ReviewHandler, error) {
 	wire.Build(
 		// Repository
 		postgres.NewKnowledgeRepository,
 		wire.Bind(new(repository.KnowledgeRepository), new(*postgres.KnowledgeRepository)),
 		postgres.NewReviewRepository,
 		wire.Bind(new(repository.ReviewRepository), new(*postgres.ReviewRepository)),
+		postgres.NewPromptTemplateRepository,
+		wire.Bind(new(repository.PromptTemplateRepository), new(*postgres.PromptTemplateRepository)),
+		postgres.NewQuotaRepository,
+		wire.Bind(new(repository.QuotaRepository), new(*postgres.QuotaRepository)),
+		ProvideQuotaCounter,
+		postgres.NewUserRepository,
 
 		// Service
 		service.NewReviewService,
+		service.NewTemplatePromptRenderer,
+		wire.Bind(new(service.PromptRenderer), new(*service.TemplatePromptRenderer)),
+		ProvideQuotaPolicy,
+		service.NewQuotaService,
 
 		// External
-		ProvideClaudeClient,
-		wire.Bind(new(external.ClaudeClientInterface), new(*external.ClaudeClient)),
+		ProvideLLMProvider,
+		ProvideLLMProviderFactory,
 
-		ProvideOpenAIClient,
-		wire.Bind(new(external.EmbeddingClientInterface), new(*external.OpenAIClient)),
+		ProvideEmbeddingClient,
+
+		ProvidePriceTable,
 
 		// UseCase
+		ProvideReviewChunkingOptions,
+		ProvideReviewCacheOptions,
+		ProvideReviewPreferenceOptions,
 		review.NewReviewCodeUseCase,
 		review.NewUpdateFeedbackUseCase,
 		review.NewListReviewsUseCase,
@@ -70,6 +96,316 @@ func InitializeReviewHandler(db *sql.DB, cfg *config.Config) (*handler.ReviewHan
 	return nil, nil
 }
 
+// InitializeReviewJobUseCase - 非同期のレビューのユースケースを初期化（Wireが自動生成）
+func InitializeReviewJobUseCase(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache) (*review.ReviewJobUseCase, error) {
+	wire.Build(
+		// Repository
+		postgres.NewKnowledgeRepository,
+		wire.Bind(new(repository.KnowledgeRepository), new(*postgres.KnowledgeRepository)),
+		postgres.NewReviewRepository,
+		wire.Bind(new(repository.ReviewRepository), new(*postgres.ReviewRepository)),
+		postgres.NewPromptTemplateRepository,
+		wire.Bind(new(repository.PromptTemplateRepository), new(*postgres.PromptTemplateRepository)),
+		postgres.NewQuotaRepository,
+		wire.Bind(new(repository.QuotaRepository), new(*postgres.QuotaRepository)),
+		ProvideQuotaCounter,
+		postgres.NewUserRepository,
+
+		// Service
+		service.NewReviewService,
+		service.NewTemplatePromptRenderer,
+		wire.Bind(new(service.PromptRenderer), new(*service.TemplatePromptRenderer)),
+		ProvideQuotaPolicy,
+		service.NewQuotaService,
+
+		// External
+		ProvideLLMProvider,
+		ProvideLLMProviderFactory,
+
+		ProvideEmbeddingClient,
+
+		ProvidePriceTable,
+
+		// UseCase
+		ProvideReviewChunkingOptions,
+		ProvideReviewCacheOptions,
+		ProvideReviewPreferenceOptions,
+		ProvideReviewJobOptions,
+		review.NewReviewCodeUseCase,
+		review.NewReviewJobUseCase,
+	)
+	return nil, nil
+}
+
+// InitializeReviewEnsembleHandler - ReviewEnsembleHandlerを初期化（Wireが自動生成）
+func InitializeReviewEnsembleHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache) (*handler.ReviewEnsembleHandler, error) {
+	wire.Build(
+		// Repository
+		postgres.NewKnowledgeRepository,
+		wire.Bind(new(repository.KnowledgeRepository), new(*postgres.KnowledgeRepository)),
+		postgres.NewReviewRepository,
+		wire.Bind(new(repository.ReviewRepository), new(*postgres.ReviewRepository)),
+		postgres.NewPromptTemplateRepository,
+		wire.Bind(new(repository.PromptTemplateRepository), new(*postgres.PromptTemplateRepository)),
+		postgres.NewQuotaRepository,
+		wire.Bind(new(repository.QuotaRepository), new(*postgres.QuotaRepository)),
+		ProvideQuotaCounter,
+		postgres.NewUserRepository,
+
+		// Service
+		service.NewReviewService,
+		service.NewTemplatePromptRenderer,
+		wire.Bind(new(service.PromptRenderer), new(*service.TemplatePromptRenderer)),
+		ProvideQuotaPolicy,
+		service.NewQuotaService,
+
+		// External
+		ProvideLLMProvider,
+		ProvideLLMProviderFactory,
+
+		ProvideEmbeddingClient,
+
+		ProvidePriceTable,
+
+		// UseCase
+		ProvideReviewChunkingOptions,
+		ProvideReviewCacheOptions,
+		ProvideReviewPreferenceOptions,
+		ProvideReviewEnsembleOptions,
+		review.NewReviewCodeUseCase,
+		review.NewEnsembleReviewUseCase,
+		review.NewGetEnsembleReviewUseCase,
+
+		// Handler
+		handler.NewReviewEnsembleHandler,
+	)
+	return nil, nil
+}
+
+// InitializeReviewSessionHandler - ReviewSessionHandlerを初期化（Wireが自動生成）
+func InitializeReviewSessionHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache) (*handler.ReviewSessionHandler, error) {
+	wire.Build(
+		// Repository
+		postgres.NewKnowledgeRepository,
+		wire.Bind(new(repository.KnowledgeRepository), new(*postgres.KnowledgeRepository)),
+		postgres.NewReviewRepository,
+		wire.Bind(new(repository.ReviewRepository), new(*postgres.ReviewRepository)),
+		postgres.NewReviewSessionRepository,
+		wire.Bind(new(repository.ReviewSessionRepository), new(*postgres.ReviewSessionRepository)),
+		postgres.NewPromptTemplateRepository,
+		wire.Bind(new(repository.PromptTemplateRepository), new(*postgres.PromptTemplateRepository)),
+		postgres.NewQuotaRepository,
+		wire.Bind(new(repository.QuotaRepository), new(*postgres.QuotaRepository)),
+		ProvideQuotaCounter,
+		postgres.NewUserRepository,
+
+		// Service
+		service.NewReviewService,
+		service.NewTemplatePromptRenderer,
+		wire.Bind(new(service.PromptRenderer), new(*service.TemplatePromptRenderer)),
+		ProvideQuotaPolicy,
+		service.NewQuotaService,
+
+		// External
+		ProvideLLMProvider,
+		ProvideLLMProviderFactory,
+
+		ProvideEmbeddingClient,
+
+		ProvidePriceTable,
+
+		// UseCase
+		ProvideReviewChunkingOptions,
+		ProvideReviewCacheOptions,
+		ProvideReviewPreferenceOptions,
+		ProvideReviewSessionOptions,
+		review.NewReviewCodeUseCase,
+		review.NewReviewSessionUseCase,
+		review.NewGetReviewSessionUseCase,
+		review.NewListReviewSessionsUseCase,
+
+		// Handler
+		handler.NewReviewSessionHandler,
+	)
+	return nil, nil
+}
+
+// InitializeReviewConversationHandler - ReviewConversationHandlerを初期化（Wireが自動生成）
+func InitializeReviewConversationHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache) (*handler.ReviewConversationHandler, error) {
+	wire.Build(
+		// Repository
+		postgres.NewKnowledgeRepository,
+		wire.Bind(new(repository.KnowledgeRepository), new(*postgres.KnowledgeRepository)),
+		postgres.NewReviewRepository,
+		wire.Bind(new(repository.ReviewRepository), new(*postgres.ReviewRepository)),
+		postgres.NewReviewMessageRepository,
+		wire.Bind(new(repository.ReviewMessageRepository), new(*postgres.ReviewMessageRepository)),
+		postgres.NewPromptTemplateRepository,
+		wire.Bind(new(repository.PromptTemplateRepository), new(*postgres.PromptTemplateRepository)),
+		postgres.NewQuotaRepository,
+		wire.Bind(new(repository.QuotaRepository), new(*postgres.QuotaRepository)),
+		ProvideQuotaCounter,
+		postgres.NewUserRepository,
+
+		// Service
+		service.NewReviewService,
+		service.NewTemplatePromptRenderer,
+		wire.Bind(new(service.PromptRenderer), new(*service.TemplatePromptRenderer)),
+		ProvideQuotaPolicy,
+		service.NewQuotaService,
+
+		// External
+		ProvideLLMProvider,
+		ProvideLLMProviderFactory,
+
+		ProvideEmbeddingClient,
+
+		ProvidePriceTable,
+
+		// UseCase
+		ProvideReviewChunkingOptions,
+		ProvideReviewCacheOptions,
+		ProvideReviewPreferenceOptions,
+		ProvideConversationOptions,
+		review.NewReviewCodeUseCase,
+		review.NewReviewConversationUseCase,
+		review.NewListReviewMessagesUseCase,
+		review.NewCreateKnowledgeFromMessageUseCase,
+
+		// Handler
+		handler.NewReviewConversationHandler,
+	)
+	return nil, nil
+}
+
+// InitializeReviewRerunHandler - ReviewRerunHandlerを初期化（Wireが自動生成）
+// 非同期の再レビューはワーカーに実行させるため、main で起動した ReviewJobUseCase を引数で受け取る
+func InitializeReviewRerunHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache, reviewJobUsecase *review.ReviewJobUseCase) (*handler.ReviewRerunHandler, error) {
+	wire.Build(
+		// Repository
+		postgres.NewKnowledgeRepository,
+		wire.Bind(new(repository.KnowledgeRepository), new(*postgres.KnowledgeRepository)),
+		postgres.NewReviewRepository,
+		wire.Bind(new(repository.ReviewRepository), new(*postgres.ReviewRepository)),
+		postgres.NewPromptTemplateRepository,
+		wire.Bind(new(repository.PromptTemplateRepository), new(*postgres.PromptTemplateRepository)),
+		postgres.NewQuotaRepository,
+		wire.Bind(new(repository.QuotaRepository), new(*postgres.QuotaRepository)),
+		ProvideQuotaCounter,
+		postgres.NewUserRepository,
+
+		// Service
+		service.NewReviewService,
+		service.NewTemplatePromptRenderer,
+		wire.Bind(new(service.PromptRenderer), new(*service.TemplatePromptRenderer)),
+		ProvideQuotaPolicy,
+		service.NewQuotaService,
+
+		// External
+		ProvideLLMProvider,
+		ProvideLLMProviderFactory,
+
+		ProvideEmbeddingClient,
+
+		ProvidePriceTable,
+
+		// UseCase
+		ProvideReviewChunkingOptions,
+		ProvideReviewCacheOptions,
+		ProvideReviewPreferenceOptions,
+		review.NewReviewCodeUseCase,
+		review.NewRerunReviewUseCase,
+		review.NewDiffReviewsUseCase,
+
+		// Handler
+		handler.NewReviewRerunHandler,
+	)
+	return nil, nil
+}
+
+// InitializeReviewApplyHandler - ReviewApplyHandlerを初期化（Wireが自動生成）
+// 適用後のコードの非同期のレビューはワーカーに実行させるため、main で起動した ReviewJobUseCase を引数で受け取る
+func InitializeReviewApplyHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache, reviewJobUsecase *review.ReviewJobUseCase) (*handler.ReviewApplyHandler, error) {
+	wire.Build(
+		// Repository
+		postgres.NewKnowledgeRepository,
+		wire.Bind(new(repository.KnowledgeRepository), new(*postgres.KnowledgeRepository)),
+		postgres.NewReviewRepository,
+		wire.Bind(new(repository.ReviewRepository), new(*postgres.ReviewRepository)),
+		postgres.NewPromptTemplateRepository,
+		wire.Bind(new(repository.PromptTemplateRepository), new(*postgres.PromptTemplateRepository)),
+		postgres.NewQuotaRepository,
+		wire.Bind(new(repository.QuotaRepository), new(*postgres.QuotaRepository)),
+		ProvideQuotaCounter,
+		postgres.NewUserRepository,
+
+		// Service
+		service.NewReviewService,
+		service.NewTemplatePromptRenderer,
+		wire.Bind(new(service.PromptRenderer), new(*service.TemplatePromptRenderer)),
+		ProvideQuotaPolicy,
+		service.NewQuotaService,
+
+		// External
+		ProvideLLMProvider,
+		ProvideLLMProviderFactory,
+
+		ProvideEmbeddingClient,
+
+		ProvidePriceTable,
+
+		// UseCase
+		ProvideReviewChunkingOptions,
+		ProvideReviewCacheOptions,
+		ProvideReviewPreferenceOptions,
+		review.NewReviewCodeUseCase,
+		review.NewApplyImprovementsUseCase,
+
+		// Handler
+		handler.NewReviewApplyHandler,
+	)
+	return nil, nil
+}
+
+// InitializeReviewTrashHandler - ReviewTrashHandlerを初期化（Wireが自動生成）
+func InitializeReviewTrashHandler(db *sql.DB, cfg *config.Config) (*handler.ReviewTrashHandler, error) {
+	wire.Build(
+		// Repository
+		postgres.NewReviewRepository,
+		wire.Bind(new(repository.ReviewRepository), new(*postgres.ReviewRepository)),
+		postgres.NewQuotaRepository,
+		wire.Bind(new(repository.QuotaRepository), new(*postgres.QuotaRepository)),
+		ProvideQuotaCounter,
+
+		// Service
+		ProvideQuotaPolicy,
+		service.NewQuotaService,
+
+		// UseCase
+		ProvideTrashOptions,
+		review.NewReviewTrashUseCase,
+
+		// Handler
+		handler.NewReviewTrashHandler,
+	)
+	return nil, nil
+}
+
+// InitializeReviewPurgeUseCase - ReviewPurgeUseCaseを初期化（Wireが自動生成）
+// 定期的な完全削除は main で起動・停止する
+func InitializeReviewPurgeUseCase(db *sql.DB, cfg *config.Config) (*review.ReviewPurgeUseCase, error) {
+	wire.Build(
+		// Repository
+		postgres.NewReviewRepository,
+		wire.Bind(new(repository.ReviewRepository), new(*postgres.ReviewRepository)),
+
+		// UseCase
+		ProvideTrashOptions,
+		review.NewReviewPurgeUseCase,
+	)
+	return nil, nil
+}
+
 // InitializeDashboardHandler - DashboardHandlerを初期化（Wireが自動生成）
 func InitializeDashboardHandler(db *sql.DB) (*handler.DashboardHandler, error) {
 	wire.Build(
@@ -78,6 +414,8 @@ func InitializeDashboardHandler(db *sql.DB) (*handler.DashboardHandler, error) {
 		wire.Bind(new(repository.ReviewRepository), new(*postgres.ReviewRepository)),
 		postgres.NewKnowledgeRepository,
 		wire.Bind(new(repository.KnowledgeRepository), new(*postgres.KnowledgeRepository)),
+		postgres.NewReviewSessionRepository,
+		wire.Bind(new(repository.ReviewSessionRepository), new(*postgres.ReviewSessionRepository)),
 
 		// UseCase
 		dashboard.NewGetStatsUseCase,
@@ -88,20 +426,363 @@ func InitializeDashboardHandler(db *sql.DB) (*handler.DashboardHandler, error) {
 	return nil, nil
 }
 
-// ProvideClaudeClient - ClaudeClientのプロバイダ
-func ProvideClaudeClient(cfg *config.Config) *external.ClaudeClient {
-	return external.NewClaudeClient(
-		cfg.LLM.ClaudeAPIKey,
-		cfg.LLM.ClaudeModel,
-		cfg.LLM.ClaudeMaxTokens,
+// InitializePromptTemplateHandler - PromptTemplateHandlerを初期化（Wireが自動生成）
+func InitializePromptTemplateHandler(db *sql.DB) (*handler.PromptTemplateHandler, error) {
+	wire.Build(
+		// Repository
+		postgres.NewPromptTemplateRepository,
+		wire.Bind(new(repository.PromptTemplateRepository), new(*postgres.PromptTemplateRepository)),
+		postgres.NewKnowledgeRepository,
+		wire.Bind(new(repository.KnowledgeRepository), new(*postgres.KnowledgeRepository)),
+
+		// Service
+		service.NewReviewService,
+		service.NewTemplatePromptRenderer,
+		wire.Bind(new(service.PromptRenderer), new(*service.TemplatePromptRenderer)),
+
+		// UseCase
+		prompt.NewCreatePromptTemplateUseCase,
+		prompt.NewListPromptTemplatesUseCase,
+		prompt.NewPreviewPromptTemplateUseCase,
+		prompt.NewActivatePromptTemplateUseCase,
+
+		// Handler
+		handler.NewPromptTemplateHandler,
+	)
+	return nil, nil
+}
+
+// InitializeUsageHandler - UsageHandlerを初期化（Wireが自動生成）
+func InitializeUsageHandler(db *sql.DB) (*handler.UsageHandler, error) {
+	wire.Build(
+		// Repository
+		postgres.NewReviewRepository,
+		wire.Bind(new(repository.ReviewRepository), new(*postgres.ReviewRepository)),
+
+		// UseCase
+		usage.NewGetUsageUseCase,
+
+		// Handler
+		handler.NewUsageHandler,
+	)
+	return nil, nil
+}
+
+// InitializeQuotaHandler - QuotaHandlerを初期化（Wireが自動生成）
+func InitializeQuotaHandler(db *sql.DB, cfg *config.Config) (*handler.QuotaHandler, error) {
+	wire.Build(
+		// Repository
+		postgres.NewQuotaRepository,
+		wire.Bind(new(repository.QuotaRepository), new(*postgres.QuotaRepository)),
+		postgres.NewUserRepository,
+		ProvideQuotaCounter,
+
+		// Service
+		ProvideQuotaPolicy,
+		service.NewQuotaService,
+
+		// UseCase
+		quota.NewGetQuotaUseCase,
+		quota.NewUpdateUserQuotaUseCase,
+
+		// Handler
+		handler.NewQuotaHandler,
+	)
+	return nil, nil
+}
+
+// InitializeUserPreferencesHandler - UserPreferencesHandlerを初期化（Wireが自動生成）
+func InitializeUserPreferencesHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry) (*handler.UserPreferencesHandler, error) {
+	wire.Build(
+		// Repository
+		postgres.NewUserRepository,
+
+		// External
+		ProvideLLMProvider,
+		ProvideLLMProviderFactory,
+
+		// UseCase
+		user.NewGetPreferencesUseCase,
+		user.NewUpdatePreferencesUseCase,
+
+		// Handler
+		handler.NewUserPreferencesHandler,
 	)
+	return nil, nil
+}
+
+// ProvideLLMProvider - 設定（LLM_PROVIDER）に応じてレビュー用のLLMプロバイダを選択
+func ProvideLLMProvider(cfg *config.Config, breakers *external.BreakerRegistry) (external.LLMProvider, error) {
+	return newLLMProvider(cfg, breakers, cfg.LLM.Provider, "")
+}
+
+// ProvideLLMProviderFactory - ユーザー設定のプロバイダ・モデルのクライアントを生成する関数のプロバイダ
+// 同じプロバイダ・モデルのクライアントは使い回す
+func ProvideLLMProviderFactory(cfg *config.Config, breakers *external.BreakerRegistry) external.LLMProviderFactory {
+	var mu sync.Mutex
+	providers := map[string]external.LLMProvider{}
+	return func(providerName, modelName string) (external.LLMProvider, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		key := providerName + ":" + modelName
+		if provider, ok := providers[key]; ok {
+			return provider, nil
+		}
+		provider, err := newLLMProvider(cfg, breakers, providerName, modelName)
+		if err != nil {
+			return nil, err
+		}
+		providers[key] = provider
+		return provider, nil
+	}
+}
+
+// ProvideReviewPreferenceOptions - ユーザー設定をレビューに反映する設定のプロバイダ
+func ProvideReviewPreferenceOptions(userRepo repository.UserRepository, providers external.LLMProviderFactory) review.PreferenceOptions {
+	return review.PreferenceOptions{
+		Users:     userRepo,
+		Providers: providers,
+	}
+}
+
+// ProvideReviewEnsembleOptions - 複数モデルによるレビュー設定のプロバイダ（LLM_ENSEMBLE_MODELS のモデルごとにプロバイダを生成）
+func ProvideReviewEnsembleOptions(cfg *config.Config, breakers *external.BreakerRegistry) (review.EnsembleOptions, error) {
+	providers := make([]external.LLMProvider, 0, len(cfg.LLM.EnsembleModels))
+	for _, entry := range cfg.LLM.EnsembleModels {
+		// "ollama:qwen2.5-coder:7b" のようにモデル名にもコロンを含むため、最初のコロンで分ける
+		providerName, modelName, _ := strings.Cut(entry, ":")
+		provider, err := newLLMProvider(cfg, breakers, providerName, modelName)
+		if err != nil {
+			return review.EnsembleOptions{}, fmt.Errorf("invalid LLM_ENSEMBLE_MODELS: %w", err)
+		}
+		providers = append(providers, provider)
+	}
+	return review.EnsembleOptions{
+		Providers:   providers,
+		Concurrency: cfg.LLM.EnsembleConcurrency,
+	}, nil
+}
+
+// ProvideReviewSessionOptions - 複数ファイルのレビュー設定のプロバイダ
+func ProvideReviewSessionOptions(cfg *config.Config) review.ReviewSessionOptions {
+	return review.ReviewSessionOptions{
+		MaxFiles:    cfg.ReviewSession.MaxFiles,
+		Concurrency: cfg.ReviewSession.Concurrency,
+	}
+}
+
+// ProvideReviewJobOptions - 非同期のレビューのワーカーの設定のプロバイダ
+func ProvideReviewJobOptions(cfg *config.Config) review.ReviewJobUseCaseOptions {
+	return review.ReviewJobUseCaseOptions{
+		Workers:      cfg.ReviewJob.Workers,
+		Timeout:      cfg.ReviewJob.Timeout,
+		PollInterval: cfg.ReviewJob.PollInterval,
+	}
+}
+
+// ProvideTrashOptions - 削除したレビュー（ゴミ箱）の設定のプロバイダ
+func ProvideTrashOptions(cfg *config.Config) review.TrashOptions {
+	return review.TrashOptions{
+		Retention:     cfg.Trash.Retention,
+		PurgeInterval: cfg.Trash.PurgeInterval,
+	}
+}
+
+// ProvideConversationOptions - レビューについての会話の設定のプロバイダ
+func ProvideConversationOptions(cfg *config.Config) review.ConversationOptions {
+	return review.ConversationOptions{
+		Enabled:         cfg.Features.ConversationMode,
+		MaxMessages:     cfg.Conversation.MaxMessages,
+		HistoryMessages: cfg.Conversation.HistoryMessages,
+	}
+}
+
+// newLLMProvider - プロバイダ名とモデル名からLLMプロバイダを生成（モデル名が空の場合は設定のモデル）
+func newLLMProvider(cfg *config.Config, breakers *external.BreakerRegistry, provider, modelName string) (external.LLMProvider, error) {
+	switch provider {
+	case external.ProviderAnthropic, "claude", "":
+		if modelName == "" {
+			modelName = cfg.LLM.ClaudeModel
+		}
+		transport, err := provideResilientTransport(cfg, breakers, external.ProviderAnthropic)
+		if err != nil {
+			return nil, err
+		}
+		return external.NewClaudeClient(
+			cfg.LLM.ClaudeAPIKey,
+			modelName,
+			cfg.LLM.ClaudeMaxTokens,
+			cfg.LLM.Timeout,
+			cfg.LLM.StreamTimeout,
+			transport,
+		), nil
+	case external.ProviderOpenAI:
+		if modelName == "" {
+			modelName = cfg.LLM.OpenAIChatModel
+		}
+		transport, err := provideResilientTransport(cfg, breakers, external.ProviderOpenAI)
+		if err != nil {
+			return nil, err
+		}
+		return external.NewOpenAIChatClient(
+			external.ProviderOpenAI,
+			cfg.LLM.OpenAIBaseURL,
+			cfg.LLM.OpenAIAPIKey,
+			modelName,
+			cfg.LLM.MaxTokens,
+			cfg.LLM.Timeout,
+			transport,
+		), nil
+	case external.ProviderOllama, external.ProviderOpenAICompatible:
+		// Ollama / llama.cpp などのセルフホストモデル（コードを外部に送信しない）
+		if modelName == "" {
+			modelName = cfg.LLM.LocalModel
+		}
+		transport, err := provideResilientTransport(cfg, breakers, provider)
+		if err != nil {
+			return nil, err
+		}
+		return external.NewOpenAIChatClient(
+			provider,
+			cfg.LLM.LocalBaseURL,
+			cfg.LLM.LocalAPIKey,
+			modelName,
+			cfg.LLM.MaxTokens,
+			cfg.LLM.Timeout,
+			transport,
+		), nil
+	default:
+		return nil, fmt.Errorf("unsupported LLM provider: %s", provider)
+	}
+}
+
+// ProvideEmbeddingClient - 設定（EMBEDDING_PROVIDER）に応じてEmbeddingクライアントを選択
+// openai の場合は、キャッシュ（embeddingCache が nil の場合は使わない）とバッチ化を行うデコレーターで包んで返す
+func ProvideEmbeddingClient(cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache) (external.EmbeddingClientInterface, error) {
+	switch cfg.Embedding.Provider {
+	case external.ProviderOpenAI, "":
+		client, err := ProvideOpenAIClient(cfg, breakers)
+		if err != nil {
+			return nil, err
+		}
+		return external.NewCachedEmbeddingClient(client, embeddingCache, cfg.LLM.OpenAIEmbedding, external.EmbeddingBatchOptions{
+			Window:  cfg.Embedding.BatchWindow,
+			MaxSize: cfg.Embedding.BatchSize,
+			Timeout: cfg.LLM.OpenAITimeout,
+		}), nil
+	case external.ProviderHashing:
+		// ネットワークを使わないため、キャッシュ・バッチ化はしない
+		return external.NewHashingEmbeddingClient(cfg.LLM.EmbeddingDim), nil
+	default:
+		return nil, fmt.Errorf("unsupported embedding provider: %s", cfg.Embedding.Provider)
+	}
 }
 
 // ProvideOpenAIClient - OpenAIClientのプロバイダ
-func ProvideOpenAIClient(cfg *config.Config) *external.OpenAIClient {
+func ProvideOpenAIClient(cfg *config.Config, breakers *external.BreakerRegistry) (*external.OpenAIClient, error) {
+	transport, err := provideResilientTransport(cfg, breakers, external.ProviderOpenAI)
+	if err != nil {
+		return nil, err
+	}
 	return external.NewOpenAIClient(
 		cfg.LLM.OpenAIAPIKey,
 		cfg.LLM.OpenAIEmbedding,
 		cfg.LLM.OpenAITimeout,
-	)
+		transport,
+	), nil
+}
+
+// ProvideEmbeddingCache - 設定（EMBEDDING_CACHE）に応じてEmbeddingのキャッシュを選択（none の場合は nil）
+// ハンドラー間で共有するため、main で1つだけ生成して各Injectorに渡す
+func ProvideEmbeddingCache(cfg *config.Config) (external.EmbeddingCache, error) {
+	switch cfg.Embedding.Cache {
+	case "memory", "":
+		return external.NewLRUEmbeddingCache(cfg.Embedding.CacheSize), nil
+	case "redis":
+		client, err := redis.NewClient(&cfg.Redis)
+		if err != nil {
+			return nil, err
+		}
+		return redis.NewEmbeddingCache(client, cfg.Embedding.CacheTTL), nil
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported embedding cache: %s", cfg.Embedding.Cache)
+	}
+}
+
+// ProvideReviewCacheOptions - レビュー結果のキャッシュ設定のプロバイダ
+func ProvideReviewCacheOptions(cfg *config.Config) review.CacheOptions {
+	return review.CacheOptions{
+		TTL: cfg.ReviewCache.TTL,
+	}
+}
+
+// ProvideReviewChunkingOptions - 大きなファイルの分割レビュー設定のプロバイダ
+func ProvideReviewChunkingOptions(cfg *config.Config) review.ChunkingOptions {
+	return review.ChunkingOptions{
+		MaxTokens:    cfg.Chunking.MaxTokens,
+		Concurrency:  cfg.Chunking.Concurrency,
+		MaxDiffHunks: cfg.Chunking.DiffMaxHunks,
+	}
+}
+
+// ProvidePriceTable - 料金表のプロバイダ（LLM_PRICE_TABLE の内容で組み込みの料金表を上書き）
+func ProvidePriceTable(cfg *config.Config) (*service.PriceTable, error) {
+	prices := service.DefaultModelPrices()
+	if cfg.Pricing.PriceTable != "" {
+		var overrides map[string]service.ModelPrice
+		if err := json.Unmarshal([]byte(cfg.Pricing.PriceTable), &overrides); err != nil {
+			return nil, fmt.Errorf("invalid LLM_PRICE_TABLE: %w", err)
+		}
+		for name, price := range overrides {
+			prices[name] = price
+		}
+	}
+	return service.NewPriceTable(cfg.Pricing.Currency, prices), nil
+}
+
+// ProvideQuotaPolicy - グローバルの利用上限のプロバイダ
+func ProvideQuotaPolicy(cfg *config.Config) model.QuotaPolicy {
+	return model.QuotaPolicy{
+		ReviewsPerDay:  cfg.Quota.ReviewsPerDay,
+		TokensPerMonth: cfg.Quota.TokensPerMonth,
+	}
+}
+
+// ProvideQuotaCounter - 消費量カウンターのプロバイダ（QUOTA_USE_REDIS=true の場合はRedisを併用）
+func ProvideQuotaCounter(db *sql.DB, cfg *config.Config) (repository.QuotaCounter, error) {
+	counter := postgres.NewQuotaCounter(db)
+	if !cfg.Quota.UseRedis {
+		return counter, nil
+	}
+	client, err := redis.NewClient(&cfg.Redis)
+	if err != nil {
+		return nil, err
+	}
+	return redis.NewQuotaCounter(client, counter), nil
+}
+
+// provideResilientTransport - プロバイダごとのサーキットブレーカーを使うトランスポートを生成
+// LLM_CASSETTE_MODE が record / replay の場合は、外部APIとのやり取りを記録・再生するトランスポートの上で再試行する
+func provideResilientTransport(cfg *config.Config, breakers *external.BreakerRegistry, provider string) (*external.ResilientTransport, error) {
+	var base http.RoundTripper
+	if mode := external.CassetteMode(cfg.LLM.CassetteMode); mode != external.CassetteModeOff && mode != "" {
+		cassette, err := external.NewCassetteTransport(nil, external.CassetteOptions{
+			Mode:             mode,
+			Path:             filepath.Join(cfg.LLM.CassetteDir, provider+".json"),
+			IgnoreBodyFields: cfg.LLM.CassetteIgnoreFields,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid LLM_CASSETTE_MODE: %w", err)
+		}
+		base = cassette
+	}
+
+	return external.NewResilientTransport(base, breakers.Get(provider), external.RetryPolicy{
+		MaxRetries: cfg.LLM.MaxRetries,
+		BaseDelay:  cfg.LLM.RetryBaseDelay,
+		MaxDelay:   cfg.LLM.RetryMaxDelay,
+	}), nil
 }
@@ -8,24 +8,40 @@ package di
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"github.com/s7r8/reviewapp/internal/application/usecase/dashboard"
 	"github.com/s7r8/reviewapp/internal/application/usecase/knowledge"
+	"github.com/s7r8/reviewapp/internal/application/usecase/prompt"
+	"github.com/s7r8/reviewapp/internal/application/usecase/quota"
 	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/application/usecase/usage"
+	"github.com/s7r8/reviewapp/internal/application/usecase/user"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
 	"github.com/s7r8/reviewapp/internal/domain/service"
 	"github.com/s7r8/reviewapp/internal/infrastructure/config"
 	"github.com/s7r8/reviewapp/internal/infrastructure/external"
 	"github.com/s7r8/reviewapp/internal/infrastructure/persistence/postgres"
+	"github.com/s7r8/reviewapp/internal/infrastructure/persistence/redis"
 	"github.com/s7r8/reviewapp/internal/interfaces/http/handler"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
 )
 
 // Injectors from wire.go:
 
 // InitializeKnowledgeHandler - KnowledgeHandlerを初期化（Wireが自動生成）
-func InitializeKnowledgeHandler(db *sql.DB, cfg *config.Config) (*handler.KnowledgeHandler, error) {
+func InitializeKnowledgeHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache) (*handler.KnowledgeHandler, error) {
 	knowledgeRepository := postgres.NewKnowledgeRepository(db)
-	openAIClient := ProvideOpenAIClient(cfg)
-	createKnowledgeUseCase := knowledge.NewCreateKnowledgeUseCase(knowledgeRepository, openAIClient)
-	updateKnowledgeUseCase := knowledge.NewUpdateKnowledgeUseCase(knowledgeRepository, openAIClient)
+	embeddingClientInterface, err := ProvideEmbeddingClient(cfg, breakers, embeddingCache)
+	if err != nil {
+		return nil, err
+	}
+	createKnowledgeUseCase := knowledge.NewCreateKnowledgeUseCase(knowledgeRepository, embeddingClientInterface)
+	updateKnowledgeUseCase := knowledge.NewUpdateKnowledgeUseCase(knowledgeRepository, embeddingClientInterface)
 	listKnowledgeUseCase := knowledge.NewListKnowledgeUseCase(knowledgeRepository)
 	deleteKnowledgeUseCase := knowledge.NewDeleteKnowledgeUseCase(knowledgeRepository)
 	knowledgeHandler := handler.NewKnowledgeHandler(createKnowledgeUseCase, updateKnowledgeUseCase, listKnowledgeUseCase, deleteKnowledgeUseCase)
@@ -33,45 +49,642 @@ func InitializeKnowledgeHandler(db *sql.DB, cfg *config.Config) (*handler.Knowle
 }
 
 // InitializeReviewHandler - ReviewHandlerを初期化（Wireが自動生成）
-func InitializeReviewHandler(db *sql.DB, cfg *config.Config) (*handler.ReviewHandler, error) {
+// 非同期のレビューのワーカーは main で起動・停止するため、ReviewJobUseCase は引数で受け取る
+func InitializeReviewHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache, reviewJobUsecase *review.ReviewJobUseCase) (*handler.ReviewHandler, error) {
 	reviewRepository := postgres.NewReviewRepository(db)
 	knowledgeRepository := postgres.NewKnowledgeRepository(db)
 	reviewService := service.NewReviewService()
-	claudeClient := ProvideClaudeClient(cfg)
-	openAIClient := ProvideOpenAIClient(cfg)
-	reviewCodeUseCase := review.NewReviewCodeUseCase(reviewRepository, knowledgeRepository, reviewService, claudeClient, openAIClient)
+	llmProvider, err := ProvideLLMProvider(cfg, breakers)
+	if err != nil {
+		return nil, err
+	}
+	embeddingClientInterface, err := ProvideEmbeddingClient(cfg, breakers, embeddingCache)
+	if err != nil {
+		return nil, err
+	}
+	promptTemplateRepository := postgres.NewPromptTemplateRepository(db)
+	templatePromptRenderer := service.NewTemplatePromptRenderer()
+	priceTable, err := ProvidePriceTable(cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaRepository := postgres.NewQuotaRepository(db)
+	quotaCounter, err := ProvideQuotaCounter(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaPolicy := ProvideQuotaPolicy(cfg)
+	quotaService := service.NewQuotaService(quotaRepository, quotaCounter, quotaPolicy)
+	chunkingOptions := ProvideReviewChunkingOptions(cfg)
+	cacheOptions := ProvideReviewCacheOptions(cfg)
+	userRepository := postgres.NewUserRepository(db)
+	llmProviderFactory := ProvideLLMProviderFactory(cfg, breakers)
+	preferenceOptions := ProvideReviewPreferenceOptions(userRepository, llmProviderFactory)
+	reviewCodeUseCase := review.NewReviewCodeUseCase(reviewRepository, knowledgeRepository, reviewService, llmProvider, embeddingClientInterface, promptTemplateRepository, templatePromptRenderer, priceTable, quotaService, chunkingOptions, cacheOptions, preferenceOptions)
 	updateFeedbackUseCase := review.NewUpdateFeedbackUseCase(reviewRepository)
 	listReviewsUseCase := review.NewListReviewsUseCase(reviewRepository)
 	getReviewUseCase := review.NewGetReviewUseCase(reviewRepository)
-	reviewHandler := handler.NewReviewHandler(reviewCodeUseCase, updateFeedbackUseCase, listReviewsUseCase, getReviewUseCase)
+	reviewHandler := handler.NewReviewHandler(reviewCodeUseCase, updateFeedbackUseCase, listReviewsUseCase, getReviewUseCase, reviewJobUsecase)
 	return reviewHandler, nil
 }
 
+// InitializeReviewJobUseCase - 非同期のレビューのユースケースを初期化（Wireが自動生成）
+func InitializeReviewJobUseCase(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache) (*review.ReviewJobUseCase, error) {
+	reviewRepository := postgres.NewReviewRepository(db)
+	knowledgeRepository := postgres.NewKnowledgeRepository(db)
+	reviewService := service.NewReviewService()
+	llmProvider, err := ProvideLLMProvider(cfg, breakers)
+	if err != nil {
+		return nil, err
+	}
+	embeddingClientInterface, err := ProvideEmbeddingClient(cfg, breakers, embeddingCache)
+	if err != nil {
+		return nil, err
+	}
+	promptTemplateRepository := postgres.NewPromptTemplateRepository(db)
+	templatePromptRenderer := service.NewTemplatePromptRenderer()
+	priceTable, err := ProvidePriceTable(cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaRepository := postgres.NewQuotaRepository(db)
+	quotaCounter, err := ProvideQuotaCounter(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaPolicy := ProvideQuotaPolicy(cfg)
+	quotaService := service.NewQuotaService(quotaRepository, quotaCounter, quotaPolicy)
+	chunkingOptions := ProvideReviewChunkingOptions(cfg)
+	cacheOptions := ProvideReviewCacheOptions(cfg)
+	userRepository := postgres.NewUserRepository(db)
+	llmProviderFactory := ProvideLLMProviderFactory(cfg, breakers)
+	preferenceOptions := ProvideReviewPreferenceOptions(userRepository, llmProviderFactory)
+	reviewCodeUseCase := review.NewReviewCodeUseCase(reviewRepository, knowledgeRepository, reviewService, llmProvider, embeddingClientInterface, promptTemplateRepository, templatePromptRenderer, priceTable, quotaService, chunkingOptions, cacheOptions, preferenceOptions)
+	reviewJobUseCaseOptions := ProvideReviewJobOptions(cfg)
+	reviewJobUseCase := review.NewReviewJobUseCase(reviewCodeUseCase, reviewJobUseCaseOptions)
+	return reviewJobUseCase, nil
+}
+
+// InitializeReviewEnsembleHandler - ReviewEnsembleHandlerを初期化（Wireが自動生成）
+func InitializeReviewEnsembleHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache) (*handler.ReviewEnsembleHandler, error) {
+	reviewRepository := postgres.NewReviewRepository(db)
+	knowledgeRepository := postgres.NewKnowledgeRepository(db)
+	reviewService := service.NewReviewService()
+	llmProvider, err := ProvideLLMProvider(cfg, breakers)
+	if err != nil {
+		return nil, err
+	}
+	embeddingClientInterface, err := ProvideEmbeddingClient(cfg, breakers, embeddingCache)
+	if err != nil {
+		return nil, err
+	}
+	promptTemplateRepository := postgres.NewPromptTemplateRepository(db)
+	templatePromptRenderer := service.NewTemplatePromptRenderer()
+	priceTable, err := ProvidePriceTable(cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaRepository := postgres.NewQuotaRepository(db)
+	quotaCounter, err := ProvideQuotaCounter(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaPolicy := ProvideQuotaPolicy(cfg)
+	quotaService := service.NewQuotaService(quotaRepository, quotaCounter, quotaPolicy)
+	chunkingOptions := ProvideReviewChunkingOptions(cfg)
+	cacheOptions := ProvideReviewCacheOptions(cfg)
+	userRepository := postgres.NewUserRepository(db)
+	llmProviderFactory := ProvideLLMProviderFactory(cfg, breakers)
+	preferenceOptions := ProvideReviewPreferenceOptions(userRepository, llmProviderFactory)
+	reviewCodeUseCase := review.NewReviewCodeUseCase(reviewRepository, knowledgeRepository, reviewService, llmProvider, embeddingClientInterface, promptTemplateRepository, templatePromptRenderer, priceTable, quotaService, chunkingOptions, cacheOptions, preferenceOptions)
+	ensembleOptions, err := ProvideReviewEnsembleOptions(cfg, breakers)
+	if err != nil {
+		return nil, err
+	}
+	ensembleReviewUseCase := review.NewEnsembleReviewUseCase(reviewCodeUseCase, ensembleOptions)
+	getEnsembleReviewUseCase := review.NewGetEnsembleReviewUseCase(reviewRepository)
+	reviewEnsembleHandler := handler.NewReviewEnsembleHandler(ensembleReviewUseCase, getEnsembleReviewUseCase)
+	return reviewEnsembleHandler, nil
+}
+
+// InitializeReviewSessionHandler - ReviewSessionHandlerを初期化（Wireが自動生成）
+func InitializeReviewSessionHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache) (*handler.ReviewSessionHandler, error) {
+	reviewRepository := postgres.NewReviewRepository(db)
+	knowledgeRepository := postgres.NewKnowledgeRepository(db)
+	reviewService := service.NewReviewService()
+	llmProvider, err := ProvideLLMProvider(cfg, breakers)
+	if err != nil {
+		return nil, err
+	}
+	embeddingClientInterface, err := ProvideEmbeddingClient(cfg, breakers, embeddingCache)
+	if err != nil {
+		return nil, err
+	}
+	promptTemplateRepository := postgres.NewPromptTemplateRepository(db)
+	templatePromptRenderer := service.NewTemplatePromptRenderer()
+	priceTable, err := ProvidePriceTable(cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaRepository := postgres.NewQuotaRepository(db)
+	quotaCounter, err := ProvideQuotaCounter(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaPolicy := ProvideQuotaPolicy(cfg)
+	quotaService := service.NewQuotaService(quotaRepository, quotaCounter, quotaPolicy)
+	chunkingOptions := ProvideReviewChunkingOptions(cfg)
+	cacheOptions := ProvideReviewCacheOptions(cfg)
+	userRepository := postgres.NewUserRepository(db)
+	llmProviderFactory := ProvideLLMProviderFactory(cfg, breakers)
+	preferenceOptions := ProvideReviewPreferenceOptions(userRepository, llmProviderFactory)
+	reviewCodeUseCase := review.NewReviewCodeUseCase(reviewRepository, knowledgeRepository, reviewService, llmProvider, embeddingClientInterface, promptTemplateRepository, templatePromptRenderer, priceTable, quotaService, chunkingOptions, cacheOptions, preferenceOptions)
+	reviewSessionRepository := postgres.NewReviewSessionRepository(db)
+	reviewSessionOptions := ProvideReviewSessionOptions(cfg)
+	reviewSessionUseCase := review.NewReviewSessionUseCase(reviewCodeUseCase, reviewSessionRepository, reviewSessionOptions)
+	getReviewSessionUseCase := review.NewGetReviewSessionUseCase(reviewRepository, reviewSessionRepository)
+	listReviewSessionsUseCase := review.NewListReviewSessionsUseCase(reviewSessionRepository)
+	reviewSessionHandler := handler.NewReviewSessionHandler(reviewSessionUseCase, getReviewSessionUseCase, listReviewSessionsUseCase)
+	return reviewSessionHandler, nil
+}
+
+// InitializeReviewConversationHandler - ReviewConversationHandlerを初期化（Wireが自動生成）
+func InitializeReviewConversationHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache) (*handler.ReviewConversationHandler, error) {
+	reviewRepository := postgres.NewReviewRepository(db)
+	knowledgeRepository := postgres.NewKnowledgeRepository(db)
+	reviewService := service.NewReviewService()
+	llmProvider, err := ProvideLLMProvider(cfg, breakers)
+	if err != nil {
+		return nil, err
+	}
+	embeddingClientInterface, err := ProvideEmbeddingClient(cfg, breakers, embeddingCache)
+	if err != nil {
+		return nil, err
+	}
+	promptTemplateRepository := postgres.NewPromptTemplateRepository(db)
+	templatePromptRenderer := service.NewTemplatePromptRenderer()
+	priceTable, err := ProvidePriceTable(cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaRepository := postgres.NewQuotaRepository(db)
+	quotaCounter, err := ProvideQuotaCounter(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaPolicy := ProvideQuotaPolicy(cfg)
+	quotaService := service.NewQuotaService(quotaRepository, quotaCounter, quotaPolicy)
+	chunkingOptions := ProvideReviewChunkingOptions(cfg)
+	cacheOptions := ProvideReviewCacheOptions(cfg)
+	userRepository := postgres.NewUserRepository(db)
+	llmProviderFactory := ProvideLLMProviderFactory(cfg, breakers)
+	preferenceOptions := ProvideReviewPreferenceOptions(userRepository, llmProviderFactory)
+	reviewCodeUseCase := review.NewReviewCodeUseCase(reviewRepository, knowledgeRepository, reviewService, llmProvider, embeddingClientInterface, promptTemplateRepository, templatePromptRenderer, priceTable, quotaService, chunkingOptions, cacheOptions, preferenceOptions)
+	reviewMessageRepository := postgres.NewReviewMessageRepository(db)
+	conversationOptions := ProvideConversationOptions(cfg)
+	reviewConversationUseCase := review.NewReviewConversationUseCase(reviewCodeUseCase, reviewMessageRepository, conversationOptions)
+	listReviewMessagesUseCase := review.NewListReviewMessagesUseCase(reviewRepository, reviewMessageRepository, conversationOptions)
+	createKnowledgeFromMessageUseCase := review.NewCreateKnowledgeFromMessageUseCase(reviewMessageRepository, knowledgeRepository, embeddingClientInterface, conversationOptions)
+	reviewConversationHandler := handler.NewReviewConversationHandler(reviewConversationUseCase, listReviewMessagesUseCase, createKnowledgeFromMessageUseCase)
+	return reviewConversationHandler, nil
+}
+
+// InitializeReviewRerunHandler - ReviewRerunHandlerを初期化（Wireが自動生成）
+// 非同期の再レビューはワーカーに実行させるため、main で起動した ReviewJobUseCase を引数で受け取る
+func InitializeReviewRerunHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache, reviewJobUsecase *review.ReviewJobUseCase) (*handler.ReviewRerunHandler, error) {
+	reviewRepository := postgres.NewReviewRepository(db)
+	knowledgeRepository := postgres.NewKnowledgeRepository(db)
+	reviewService := service.NewReviewService()
+	llmProvider, err := ProvideLLMProvider(cfg, breakers)
+	if err != nil {
+		return nil, err
+	}
+	embeddingClientInterface, err := ProvideEmbeddingClient(cfg, breakers, embeddingCache)
+	if err != nil {
+		return nil, err
+	}
+	promptTemplateRepository := postgres.NewPromptTemplateRepository(db)
+	templatePromptRenderer := service.NewTemplatePromptRenderer()
+	priceTable, err := ProvidePriceTable(cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaRepository := postgres.NewQuotaRepository(db)
+	quotaCounter, err := ProvideQuotaCounter(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaPolicy := ProvideQuotaPolicy(cfg)
+	quotaService := service.NewQuotaService(quotaRepository, quotaCounter, quotaPolicy)
+	chunkingOptions := ProvideReviewChunkingOptions(cfg)
+	cacheOptions := ProvideReviewCacheOptions(cfg)
+	userRepository := postgres.NewUserRepository(db)
+	llmProviderFactory := ProvideLLMProviderFactory(cfg, breakers)
+	preferenceOptions := ProvideReviewPreferenceOptions(userRepository, llmProviderFactory)
+	reviewCodeUseCase := review.NewReviewCodeUseCase(reviewRepository, knowledgeRepository, reviewService, llmProvider, embeddingClientInterface, promptTemplateRepository, templatePromptRenderer, priceTable, quotaService, chunkingOptions, cacheOptions, preferenceOptions)
+	rerunReviewUseCase := review.NewRerunReviewUseCase(reviewCodeUseCase, reviewJobUsecase)
+	diffReviewsUseCase := review.NewDiffReviewsUseCase(reviewRepository)
+	reviewRerunHandler := handler.NewReviewRerunHandler(rerunReviewUseCase, diffReviewsUseCase)
+	return reviewRerunHandler, nil
+}
+
+// InitializeReviewApplyHandler - ReviewApplyHandlerを初期化（Wireが自動生成）
+// 適用後のコードの非同期のレビューはワーカーに実行させるため、main で起動した ReviewJobUseCase を引数で受け取る
+func InitializeReviewApplyHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache, reviewJobUsecase *review.ReviewJobUseCase) (*handler.ReviewApplyHandler, error) {
+	reviewRepository := postgres.NewReviewRepository(db)
+	knowledgeRepository := postgres.NewKnowledgeRepository(db)
+	reviewService := service.NewReviewService()
+	llmProvider, err := ProvideLLMProvider(cfg, breakers)
+	if err != nil {
+		return nil, err
+	}
+	embeddingClientInterface, err := ProvideEmbeddingClient(cfg, breakers, embeddingCache)
+	if err != nil {
+		return nil, err
+	}
+	promptTemplateRepository := postgres.NewPromptTemplateRepository(db)
+	templatePromptRenderer := service.NewTemplatePromptRenderer()
+	priceTable, err := ProvidePriceTable(cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaRepository := postgres.NewQuotaRepository(db)
+	quotaCounter, err := ProvideQuotaCounter(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaPolicy := ProvideQuotaPolicy(cfg)
+	quotaService := service.NewQuotaService(quotaRepository, quotaCounter, quotaPolicy)
+	chunkingOptions := ProvideReviewChunkingOptions(cfg)
+	cacheOptions := ProvideReviewCacheOptions(cfg)
+	userRepository := postgres.NewUserRepository(db)
+	llmProviderFactory := ProvideLLMProviderFactory(cfg, breakers)
+	preferenceOptions := ProvideReviewPreferenceOptions(userRepository, llmProviderFactory)
+	reviewCodeUseCase := review.NewReviewCodeUseCase(reviewRepository, knowledgeRepository, reviewService, llmProvider, embeddingClientInterface, promptTemplateRepository, templatePromptRenderer, priceTable, quotaService, chunkingOptions, cacheOptions, preferenceOptions)
+	applyImprovementsUseCase := review.NewApplyImprovementsUseCase(reviewCodeUseCase, reviewJobUsecase)
+	reviewApplyHandler := handler.NewReviewApplyHandler(applyImprovementsUseCase)
+	return reviewApplyHandler, nil
+}
+
+// InitializeReviewTrashHandler - ReviewTrashHandlerを初期化（Wireが自動生成）
+func InitializeReviewTrashHandler(db *sql.DB, cfg *config.Config) (*handler.ReviewTrashHandler, error) {
+	reviewRepository := postgres.NewReviewRepository(db)
+	quotaRepository := postgres.NewQuotaRepository(db)
+	quotaCounter, err := ProvideQuotaCounter(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaPolicy := ProvideQuotaPolicy(cfg)
+	quotaService := service.NewQuotaService(quotaRepository, quotaCounter, quotaPolicy)
+	trashOptions := ProvideTrashOptions(cfg)
+	reviewTrashUseCase := review.NewReviewTrashUseCase(reviewRepository, quotaService, trashOptions)
+	reviewTrashHandler := handler.NewReviewTrashHandler(reviewTrashUseCase)
+	return reviewTrashHandler, nil
+}
+
+// InitializeReviewPurgeUseCase - ReviewPurgeUseCaseを初期化（Wireが自動生成）
+// 定期的な完全削除は main で起動・停止する
+func InitializeReviewPurgeUseCase(db *sql.DB, cfg *config.Config) (*review.ReviewPurgeUseCase, error) {
+	reviewRepository := postgres.NewReviewRepository(db)
+	trashOptions := ProvideTrashOptions(cfg)
+	reviewPurgeUseCase := review.NewReviewPurgeUseCase(reviewRepository, trashOptions)
+	return reviewPurgeUseCase, nil
+}
+
 // InitializeDashboardHandler - DashboardHandlerを初期化（Wireが自動生成）
 func InitializeDashboardHandler(db *sql.DB) (*handler.DashboardHandler, error) {
 	reviewRepository := postgres.NewReviewRepository(db)
 	knowledgeRepository := postgres.NewKnowledgeRepository(db)
-	getStatsUseCase := dashboard.NewGetStatsUseCase(reviewRepository, knowledgeRepository)
+	reviewSessionRepository := postgres.NewReviewSessionRepository(db)
+	getStatsUseCase := dashboard.NewGetStatsUseCase(reviewRepository, knowledgeRepository, reviewSessionRepository)
 	dashboardHandler := handler.NewDashboardHandler(getStatsUseCase)
 	return dashboardHandler, nil
 }
 
+// InitializePromptTemplateHandler - PromptTemplateHandlerを初期化（Wireが自動生成）
+func InitializePromptTemplateHandler(db *sql.DB) (*handler.PromptTemplateHandler, error) {
+	promptTemplateRepository := postgres.NewPromptTemplateRepository(db)
+	templatePromptRenderer := service.NewTemplatePromptRenderer()
+	createPromptTemplateUseCase := prompt.NewCreatePromptTemplateUseCase(promptTemplateRepository, templatePromptRenderer)
+	listPromptTemplatesUseCase := prompt.NewListPromptTemplatesUseCase(promptTemplateRepository)
+	knowledgeRepository := postgres.NewKnowledgeRepository(db)
+	reviewService := service.NewReviewService()
+	previewPromptTemplateUseCase := prompt.NewPreviewPromptTemplateUseCase(promptTemplateRepository, knowledgeRepository, reviewService, templatePromptRenderer)
+	activatePromptTemplateUseCase := prompt.NewActivatePromptTemplateUseCase(promptTemplateRepository)
+	promptTemplateHandler := handler.NewPromptTemplateHandler(createPromptTemplateUseCase, listPromptTemplatesUseCase, previewPromptTemplateUseCase, activatePromptTemplateUseCase)
+	return promptTemplateHandler, nil
+}
+
+// InitializeUsageHandler - UsageHandlerを初期化（Wireが自動生成）
+func InitializeUsageHandler(db *sql.DB) (*handler.UsageHandler, error) {
+	reviewRepository := postgres.NewReviewRepository(db)
+	getUsageUseCase := usage.NewGetUsageUseCase(reviewRepository)
+	usageHandler := handler.NewUsageHandler(getUsageUseCase)
+	return usageHandler, nil
+}
+
+// InitializeQuotaHandler - QuotaHandlerを初期化（Wireが自動生成）
+func InitializeQuotaHandler(db *sql.DB, cfg *config.Config) (*handler.QuotaHandler, error) {
+	quotaRepository := postgres.NewQuotaRepository(db)
+	quotaCounter, err := ProvideQuotaCounter(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaPolicy := ProvideQuotaPolicy(cfg)
+	quotaService := service.NewQuotaService(quotaRepository, quotaCounter, quotaPolicy)
+	getQuotaUseCase := quota.NewGetQuotaUseCase(quotaService, quotaRepository)
+	userRepository := postgres.NewUserRepository(db)
+	updateUserQuotaUseCase := quota.NewUpdateUserQuotaUseCase(quotaRepository, userRepository)
+	quotaHandler := handler.NewQuotaHandler(getQuotaUseCase, updateUserQuotaUseCase)
+	return quotaHandler, nil
+}
+
+// InitializeUserPreferencesHandler - UserPreferencesHandlerを初期化（Wireが自動生成）
+func InitializeUserPreferencesHandler(db *sql.DB, cfg *config.Config, breakers *external.BreakerRegistry) (*handler.UserPreferencesHandler, error) {
+	userRepository := postgres.NewUserRepository(db)
+	llmProvider, err := ProvideLLMProvider(cfg, breakers)
+	if err != nil {
+		return nil, err
+	}
+	getPreferencesUseCase := user.NewGetPreferencesUseCase(userRepository, llmProvider)
+	llmProviderFactory := ProvideLLMProviderFactory(cfg, breakers)
+	updatePreferencesUseCase := user.NewUpdatePreferencesUseCase(userRepository, llmProviderFactory)
+	userPreferencesHandler := handler.NewUserPreferencesHandler(getPreferencesUseCase, updatePreferencesUseCase)
+	return userPreferencesHandler, nil
+}
+
 // wire.go:
 
-// ProvideClaudeClient - ClaudeClientのプロバイダ
-func ProvideClaudeClient(cfg *config.Config) *external.ClaudeClient {
-	return external.NewClaudeClient(
-		cfg.LLM.ClaudeAPIKey,
-		cfg.LLM.ClaudeModel,
-		cfg.LLM.ClaudeMaxTokens,
-	)
+// ProvideLLMProvider - 設定（LLM_PROVIDER）に応じてレビュー用のLLMプロバイダを選択
+func ProvideLLMProvider(cfg *config.Config, breakers *external.BreakerRegistry) (external.LLMProvider, error) {
+	return newLLMProvider(cfg, breakers, cfg.LLM.Provider, "")
+}
+
+// ProvideLLMProviderFactory - ユーザー設定のプロバイダ・モデルのクライアントを生成する関数のプロバイダ
+// 同じプロバイダ・モデルのクライアントは使い回す
+func ProvideLLMProviderFactory(cfg *config.Config, breakers *external.BreakerRegistry) external.LLMProviderFactory {
+	var mu sync.Mutex
+	providers := map[string]external.LLMProvider{}
+	return func(providerName, modelName string) (external.LLMProvider, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		key := providerName + ":" + modelName
+		if provider, ok := providers[key]; ok {
+			return provider, nil
+		}
+		provider, err := newLLMProvider(cfg, breakers, providerName, modelName)
+		if err != nil {
+			return nil, err
+		}
+		providers[key] = provider
+		return provider, nil
+	}
+}
+
+// ProvideReviewPreferenceOptions - ユーザー設定をレビューに反映する設定のプロバイダ
+func ProvideReviewPreferenceOptions(userRepo repository.UserRepository, providers external.LLMProviderFactory) review.PreferenceOptions {
+	return review.PreferenceOptions{
+		Users:     userRepo,
+		Providers: providers,
+	}
+}
+
+// ProvideReviewEnsembleOptions - 複数モデルによるレビュー設定のプロバイダ（LLM_ENSEMBLE_MODELS のモデルごとにプロバイダを生成）
+func ProvideReviewEnsembleOptions(cfg *config.Config, breakers *external.BreakerRegistry) (review.EnsembleOptions, error) {
+	providers := make([]external.LLMProvider, 0, len(cfg.LLM.EnsembleModels))
+	for _, entry := range cfg.LLM.EnsembleModels {
+
+		providerName, modelName, _ := strings.Cut(entry, ":")
+		provider, err := newLLMProvider(cfg, breakers, providerName, modelName)
+		if err != nil {
+			return review.EnsembleOptions{}, fmt.Errorf("invalid LLM_ENSEMBLE_MODELS: %w", err)
+		}
+		providers = append(providers, provider)
+	}
+	return review.EnsembleOptions{
+		Providers:   providers,
+		Concurrency: cfg.LLM.EnsembleConcurrency,
+	}, nil
+}
+
+// ProvideReviewSessionOptions - 複数ファイルのレビュー設定のプロバイダ
+func ProvideReviewSessionOptions(cfg *config.Config) review.ReviewSessionOptions {
+	return review.ReviewSessionOptions{
+		MaxFiles:    cfg.ReviewSession.MaxFiles,
+		Concurrency: cfg.ReviewSession.Concurrency,
+	}
+}
+
+// ProvideReviewJobOptions - 非同期のレビューのワーカーの設定のプロバイダ
+func ProvideReviewJobOptions(cfg *config.Config) review.ReviewJobUseCaseOptions {
+	return review.ReviewJobUseCaseOptions{
+		Workers:      cfg.ReviewJob.Workers,
+		Timeout:      cfg.ReviewJob.Timeout,
+		PollInterval: cfg.ReviewJob.PollInterval,
+	}
+}
+
+// ProvideTrashOptions - 削除したレビュー（ゴミ箱）の設定のプロバイダ
+func ProvideTrashOptions(cfg *config.Config) review.TrashOptions {
+	return review.TrashOptions{
+		Retention:     cfg.Trash.Retention,
+		PurgeInterval: cfg.Trash.PurgeInterval,
+	}
+}
+
+// ProvideConversationOptions - レビューについての会話の設定のプロバイダ
+func ProvideConversationOptions(cfg *config.Config) review.ConversationOptions {
+	return review.ConversationOptions{
+		Enabled:         cfg.Features.ConversationMode,
+		MaxMessages:     cfg.Conversation.MaxMessages,
+		HistoryMessages: cfg.Conversation.HistoryMessages,
+	}
+}
+
+// newLLMProvider - プロバイダ名とモデル名からLLMプロバイダを生成（モデル名が空の場合は設定のモデル）
+func newLLMProvider(cfg *config.Config, breakers *external.BreakerRegistry, provider, modelName string) (external.LLMProvider, error) {
+	switch provider {
+	case external.ProviderAnthropic, "claude", "":
+		if modelName == "" {
+			modelName = cfg.LLM.ClaudeModel
+		}
+		transport, err := provideResilientTransport(cfg, breakers, external.ProviderAnthropic)
+		if err != nil {
+			return nil, err
+		}
+		return external.NewClaudeClient(
+			cfg.LLM.ClaudeAPIKey,
+			modelName,
+			cfg.LLM.ClaudeMaxTokens,
+			cfg.LLM.Timeout,
+			cfg.LLM.StreamTimeout,
+			transport,
+		), nil
+	case external.ProviderOpenAI:
+		if modelName == "" {
+			modelName = cfg.LLM.OpenAIChatModel
+		}
+		transport, err := provideResilientTransport(cfg, breakers, external.ProviderOpenAI)
+		if err != nil {
+			return nil, err
+		}
+		return external.NewOpenAIChatClient(external.ProviderOpenAI, cfg.LLM.OpenAIBaseURL,
+			cfg.LLM.OpenAIAPIKey,
+			modelName,
+			cfg.LLM.MaxTokens,
+			cfg.LLM.Timeout,
+			transport,
+		), nil
+	case external.ProviderOllama, external.ProviderOpenAICompatible:
+
+		if modelName == "" {
+			modelName = cfg.LLM.LocalModel
+		}
+		transport, err := provideResilientTransport(cfg, breakers, provider)
+		if err != nil {
+			return nil, err
+		}
+		return external.NewOpenAIChatClient(
+			provider,
+			cfg.LLM.LocalBaseURL,
+			cfg.LLM.LocalAPIKey,
+			modelName,
+			cfg.LLM.MaxTokens,
+			cfg.LLM.Timeout,
+			transport,
+		), nil
+	default:
+		return nil, fmt.Errorf("unsupported LLM provider: %s", provider)
+	}
+}
+
+// ProvideEmbeddingClient - 設定（EMBEDDING_PROVIDER）に応じてEmbeddingクライアントを選択
+// openai の場合は、キャッシュ（embeddingCache が nil の場合は使わない）とバッチ化を行うデコレーターで包んで返す
+func ProvideEmbeddingClient(cfg *config.Config, breakers *external.BreakerRegistry, embeddingCache external.EmbeddingCache) (external.EmbeddingClientInterface, error) {
+	switch cfg.Embedding.Provider {
+	case external.ProviderOpenAI, "":
+		client, err := ProvideOpenAIClient(cfg, breakers)
+		if err != nil {
+			return nil, err
+		}
+		return external.NewCachedEmbeddingClient(client, embeddingCache, cfg.LLM.OpenAIEmbedding, external.EmbeddingBatchOptions{
+			Window:  cfg.Embedding.BatchWindow,
+			MaxSize: cfg.Embedding.BatchSize,
+			Timeout: cfg.LLM.OpenAITimeout,
+		}), nil
+	case external.ProviderHashing:
+
+		return external.NewHashingEmbeddingClient(cfg.LLM.EmbeddingDim), nil
+	default:
+		return nil, fmt.Errorf("unsupported embedding provider: %s", cfg.Embedding.Provider)
+	}
 }
 
 // ProvideOpenAIClient - OpenAIClientのプロバイダ
-func ProvideOpenAIClient(cfg *config.Config) *external.OpenAIClient {
+func ProvideOpenAIClient(cfg *config.Config, breakers *external.BreakerRegistry) (*external.OpenAIClient, error) {
+	transport, err := provideResilientTransport(cfg, breakers, external.ProviderOpenAI)
+	if err != nil {
+		return nil, err
+	}
 	return external.NewOpenAIClient(
 		cfg.LLM.OpenAIAPIKey,
 		cfg.LLM.OpenAIEmbedding,
 		cfg.LLM.OpenAITimeout,
-	)
+		transport,
+	), nil
+}
+
+// ProvideEmbeddingCache - 設定（EMBEDDING_CACHE）に応じてEmbeddingのキャッシュを選択（none の場合は nil）
+// ハンドラー間で共有するため、main で1つだけ生成して各Injectorに渡す
+func ProvideEmbeddingCache(cfg *config.Config) (external.EmbeddingCache, error) {
+	switch cfg.Embedding.Cache {
+	case "memory", "":
+		return external.NewLRUEmbeddingCache(cfg.Embedding.CacheSize), nil
+	case "redis":
+		client, err := redis.NewClient(&cfg.Redis)
+		if err != nil {
+			return nil, err
+		}
+		return redis.NewEmbeddingCache(client, cfg.Embedding.CacheTTL), nil
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported embedding cache: %s", cfg.Embedding.Cache)
+	}
+}
+
+// ProvideReviewCacheOptions - レビュー結果のキャッシュ設定のプロバイダ
+func ProvideReviewCacheOptions(cfg *config.Config) review.CacheOptions {
+	return review.CacheOptions{
+		TTL: cfg.ReviewCache.TTL,
+	}
+}
+
+// ProvideReviewChunkingOptions - 大きなファイルの分割レビュー設定のプロバイダ
+func ProvideReviewChunkingOptions(cfg *config.Config) review.ChunkingOptions {
+	return review.ChunkingOptions{
+		MaxTokens:    cfg.Chunking.MaxTokens,
+		Concurrency:  cfg.Chunking.Concurrency,
+		MaxDiffHunks: cfg.Chunking.DiffMaxHunks,
+	}
+}
+
+// ProvidePriceTable - 料金表のプロバイダ（LLM_PRICE_TABLE の内容で組み込みの料金表を上書き）
+func ProvidePriceTable(cfg *config.Config) (*service.PriceTable, error) {
+	prices := service.DefaultModelPrices()
+	if cfg.Pricing.PriceTable != "" {
+		var overrides map[string]service.ModelPrice
+		if err := json.Unmarshal([]byte(cfg.Pricing.PriceTable), &overrides); err != nil {
+			return nil, fmt.Errorf("invalid LLM_PRICE_TABLE: %w", err)
+		}
+		for name, price := range overrides {
+			prices[name] = price
+		}
+	}
+	return service.NewPriceTable(cfg.Pricing.Currency, prices), nil
+}
+
+// ProvideQuotaPolicy - グローバルの利用上限のプロバイダ
+func ProvideQuotaPolicy(cfg *config.Config) model.QuotaPolicy {
+	return model.QuotaPolicy{
+		ReviewsPerDay:  cfg.Quota.ReviewsPerDay,
+		TokensPerMonth: cfg.Quota.TokensPerMonth,
+	}
+}
+
+// ProvideQuotaCounter - 消費量カウンターのプロバイダ（QUOTA_USE_REDIS=true の場合はRedisを併用）
+func ProvideQuotaCounter(db *sql.DB, cfg *config.Config) (repository.QuotaCounter, error) {
+	counter := postgres.NewQuotaCounter(db)
+	if !cfg.Quota.UseRedis {
+		return counter, nil
+	}
+	client, err := redis.NewClient(&cfg.Redis)
+	if err != nil {
+		return nil, err
+	}
+	return redis.NewQuotaCounter(client, counter), nil
+}
+
+// provideResilientTransport - プロバイダごとのサーキットブレーカーを使うトランスポートを生成
+// LLM_CASSETTE_MODE が record / replay の場合は、外部APIとのやり取りを記録・再生するトランスポートの上で再試行する
+func provideResilientTransport(cfg *config.Config, breakers *external.BreakerRegistry, provider string) (*external.ResilientTransport, error) {
+	var base http.RoundTripper
+	if mode := external.CassetteMode(cfg.LLM.CassetteMode); mode != external.CassetteModeOff && mode != "" {
+		cassette, err := external.NewCassetteTransport(nil, external.CassetteOptions{
+			Mode:             mode,
+			Path:             filepath.Join(cfg.LLM.CassetteDir, provider+".json"),
+			IgnoreBodyFields: cfg.LLM.CassetteIgnoreFields,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid LLM_CASSETTE_MODE: %w", err)
+		}
+		base = cassette
+	}
+
+	return external.NewResilientTransport(base, breakers.Get(provider), external.RetryPolicy{
+		MaxRetries: cfg.LLM.MaxRetries,
+		BaseDelay:  cfg.LLM.RetryBaseDelay,
+		MaxDelay:   cfg.LLM.RetryMaxDelay,
+	}), nil
 }
@@ -0,0 +1,30 @@
+package model
+
+import "errors"
+
+// 複数モデルのレビュー結果の返し方
+const (
+	EnsembleModeCompare = "compare" // モデルごとの結果を並べて返す
+	EnsembleModeMerge   = "merge"   // モデルごとの結果に加えて、統合した結果を返す
+)
+
+// EnsembleProvider - 複数モデルの結果を統合したレビューの llm_provider
+const EnsembleProvider = "ensemble"
+
+// 複数モデルのレビューのエラー
+var (
+	ErrEnsembleModelNotFound = errors.New("指定されたモデルは設定されていません")
+	ErrEnsembleTooFewModels  = errors.New("2つ以上のモデルを指定してください")
+	ErrEnsembleModeInvalid   = errors.New("mode は compare または merge を指定してください")
+	ErrEnsembleNotFound      = errors.New("複数モデルのレビューが見つかりません")
+)
+
+// IsMergedEnsemble - 複数モデルの結果を統合したレビューか（LLMを呼び出さずに作成する）
+func (r *Review) IsMergedEnsemble() bool {
+	return r.LLMProvider == EnsembleProvider
+}
+
+// IsValidEnsembleMode - 返し方が有効か
+func IsValidEnsembleMode(mode string) bool {
+	return mode == EnsembleModeCompare || mode == EnsembleModeMerge
+}
@@ -24,6 +24,9 @@ type Knowledge struct {
 	IsActive   bool       `json:"is_active"`
 	CreatedAt  time.Time  `json:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// RelevanceScore - ベクトル検索時の類似度（検索結果のみ。DBには保存しない）
+	RelevanceScore *float64 `json:"relevance_score,omitempty"`
 }
 
 // カテゴリの定数
@@ -127,6 +130,32 @@ func NewKnowledgeFromReview(userID, reviewID, title, content, category string, p
 	return k, nil
 }
 
+// NewKnowledgeFromConversation - レビューについての会話から生成されたナレッジ（SourceID は元のメッセージ）
+func NewKnowledgeFromConversation(userID, messageID, title, content, category string, priority int) (*Knowledge, error) {
+	now := time.Now()
+	k := &Knowledge{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		Title:      strings.TrimSpace(title),
+		Content:    strings.TrimSpace(content),
+		Category:   category,
+		Priority:   priority,
+		SourceType: SourceTypeConversation,
+		SourceID:   &messageID,
+		UsageCount: 0,
+		LastUsedAt: nil,
+		IsActive:   true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := k.Validate(); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
 // Validate - ナレッジのバリデーション
 func (k *Knowledge) Validate() error {
 	// タイトルチェック
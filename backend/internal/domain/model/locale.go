@@ -0,0 +1,81 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 対応しているロケール（プロンプト・レビュー結果の見出し・カテゴリ名・エラーメッセージの言語）
+const (
+	LocaleJapanese = "ja"
+	LocaleEnglish  = "en"
+
+	// DefaultLocale - ロケールを指定しない場合
+	DefaultLocale = LocaleJapanese
+)
+
+// ErrLocaleInvalid - 未対応のロケール
+var ErrLocaleInvalid = errors.New("locale は ja または en を指定してください")
+
+// NormalizeLocale - ロケールを対応している言語コードにする（"en-US" → "en"、未対応・空の場合は ""）
+func NormalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	switch locale {
+	case LocaleJapanese, LocaleEnglish:
+		return locale
+	}
+	return ""
+}
+
+// ResolveLocale - 候補を優先順に見て、最初に対応しているロケールを返す（なければ DefaultLocale）
+func ResolveLocale(candidates ...string) string {
+	for _, candidate := range candidates {
+		if locale := NormalizeLocale(candidate); locale != "" {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+// ParseAcceptLanguage - Accept-Language ヘッダーから、q値が最も高い対応ロケールを返す（なければ ""）
+func ParseAcceptLanguage(header string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		locale := NormalizeLocale(fields[0])
+		if locale == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			if v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+				q = v
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{locale: locale, q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	// q値が同じ場合はヘッダーの記載順
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].locale
+}
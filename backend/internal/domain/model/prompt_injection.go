@@ -0,0 +1,38 @@
+package model
+
+// プロンプトインジェクションの疑いの種類
+const (
+	InjectionKindIgnoreInstructions = "ignore_instructions" // 以前の指示を無視させる
+	InjectionKindRoleOverride       = "role_override"       // 役割を変更させる
+	InjectionKindPromptExfiltration = "prompt_exfiltration" // システムプロンプトを出力させる
+	InjectionKindOutputManipulation = "output_manipulation" // レビュー結果を指定させる（問題なしと答えさせる等）
+	InjectionKindDelimiterInjection = "delimiter_injection" // 区切りタグ・チャットテンプレートのトークンを偽装する
+	InjectionKindToolSpoofing       = "tool_spoofing"       // レビュー結果を返すツールの呼び出しを偽装する
+)
+
+// プロンプトインジェクションの疑いがある入力
+const (
+	InjectionSourceCode      = "code"
+	InjectionSourceContext   = "context"
+	InjectionSourceKnowledge = "knowledge"
+)
+
+// PromptInjectionSignal - プロンプトインジェクションの疑いがある箇所
+// 検出はパターンによる推定のため、レビューは中断せずに記録のみ行う
+type PromptInjectionSignal struct {
+	Kind     string `json:"kind"`
+	Source   string `json:"source"`
+	SourceID string `json:"source_id,omitempty"` // knowledge の場合はナレッジID
+	Line     int    `json:"line"`                // 入力内の行番号（1始まり。knowledge は1行目がタイトル）
+	Excerpt  string `json:"excerpt"`             // 該当行（長い場合は省略）
+}
+
+// SetPromptInjectionSignals - プロンプトインジェクションの疑いを記録
+func (r *Review) SetPromptInjectionSignals(signals []PromptInjectionSignal) {
+	r.PromptInjectionSignals = signals
+}
+
+// PromptInjectionSuspected - プロンプトインジェクションの疑いがあるか
+func (r *Review) PromptInjectionSuspected() bool {
+	return len(r.PromptInjectionSignals) > 0
+}
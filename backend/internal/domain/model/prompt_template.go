@@ -0,0 +1,78 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PromptTemplate - プロンプトテンプレートエンティティ（バージョン管理）
+// 同じ Name のテンプレートはバージョンごとに1行ずつ保存し、有効化できるのは1バージョンのみ
+type PromptTemplate struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Version     int        `json:"version"`
+	Content     string     `json:"content"`
+	Description string     `json:"description,omitempty"`
+	IsActive    bool       `json:"is_active"`
+	CreatedBy   *string    `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ActivatedAt *time.Time `json:"activated_at,omitempty"`
+}
+
+// テンプレート名の定数
+const (
+	PromptTemplateReviewSystem = "review_system" // コードレビューのシステムプロンプト（レビュー方針部分）
+)
+
+// プロンプトテンプレートのバリデーションエラー
+var (
+	ErrPromptTemplateNotFound        = errors.New("プロンプトテンプレートが見つかりません")
+	ErrPromptTemplateNameInvalid     = errors.New("無効なテンプレート名です")
+	ErrPromptTemplateContentRequired = errors.New("テンプレート本文は必須です")
+	ErrPromptTemplateContentTooLong  = errors.New("テンプレート本文は20000文字以内にしてください")
+)
+
+// 許可されたテンプレート名
+var validPromptTemplateNames = map[string]bool{
+	PromptTemplateReviewSystem: true,
+}
+
+// NewPromptTemplate - プロンプトテンプレートを生成（バージョンは保存時に採番）
+func NewPromptTemplate(name, content, description string, createdBy *string) (*PromptTemplate, error) {
+	t := &PromptTemplate{
+		ID:          uuid.New().String(),
+		Name:        name,
+		Content:     content,
+		Description: strings.TrimSpace(description),
+		CreatedBy:   createdBy,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Validate - テンプレートのバリデーション（構文チェックは PromptRenderer が行う）
+func (t *PromptTemplate) Validate() error {
+	if !validPromptTemplateNames[t.Name] {
+		return ErrPromptTemplateNameInvalid
+	}
+	if strings.TrimSpace(t.Content) == "" {
+		return ErrPromptTemplateContentRequired
+	}
+	if len([]rune(t.Content)) > 20000 {
+		return ErrPromptTemplateContentTooLong
+	}
+	return nil
+}
+
+// IsBuiltin - DBに保存されていない組み込みテンプレートか
+func (t *PromptTemplate) IsBuiltin() bool {
+	return t.Version == 0
+}
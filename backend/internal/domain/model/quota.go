@@ -0,0 +1,160 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 利用上限の種類
+const (
+	QuotaKindReviewsPerDay  = "reviews_per_day"  // 1日あたりのレビュー数（UTC）
+	QuotaKindTokensPerMonth = "tokens_per_month" // 1か月あたりのトークン数（UTC）
+)
+
+// 利用上限のエラー
+var (
+	ErrQuotaExceeded     = errors.New("利用上限に達しました")
+	ErrQuotaInvalid      = errors.New("上限は0以上で指定してください（0は無制限）")
+	ErrUserQuotaNotFound = errors.New("ユーザー個別の利用上限が設定されていません")
+)
+
+// QuotaPolicy - 利用上限（0は無制限）
+type QuotaPolicy struct {
+	ReviewsPerDay  int `json:"reviews_per_day"`
+	TokensPerMonth int `json:"tokens_per_month"`
+}
+
+// UserQuota - ユーザー個別の利用上限（nil の項目はグローバル設定を使う）
+type UserQuota struct {
+	UserID         string    `json:"user_id"`
+	ReviewsPerDay  *int      `json:"reviews_per_day"`
+	TokensPerMonth *int      `json:"tokens_per_month"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Validate - 上限が0以上か検証
+func (q *UserQuota) Validate() error {
+	if (q.ReviewsPerDay != nil && *q.ReviewsPerDay < 0) || (q.TokensPerMonth != nil && *q.TokensPerMonth < 0) {
+		return ErrQuotaInvalid
+	}
+	return nil
+}
+
+// IsEmpty - 個別の設定が1つもないか
+func (q *UserQuota) IsEmpty() bool {
+	return q.ReviewsPerDay == nil && q.TokensPerMonth == nil
+}
+
+// Apply - グローバル設定にユーザー個別の設定を上書きした上限を返す
+func (q *UserQuota) Apply(global QuotaPolicy) QuotaPolicy {
+	policy := global
+	if q == nil {
+		return policy
+	}
+	if q.ReviewsPerDay != nil {
+		policy.ReviewsPerDay = *q.ReviewsPerDay
+	}
+	if q.TokensPerMonth != nil {
+		policy.TokensPerMonth = *q.TokensPerMonth
+	}
+	return policy
+}
+
+// QuotaUsage - 上限の対象期間内の消費量（削除済みのレビューも含む）
+type QuotaUsage struct {
+	ReviewsToday    int `json:"reviews_today"`
+	TokensThisMonth int `json:"tokens_this_month"`
+}
+
+// QuotaStatus - 利用上限と残り
+type QuotaStatus struct {
+	Policy               QuotaPolicy `json:"policy"`
+	Usage                QuotaUsage  `json:"usage"`
+	RemainingReviewsDay  *int        `json:"remaining_reviews_today"`     // nil は無制限
+	RemainingTokensMonth *int        `json:"remaining_tokens_this_month"` // nil は無制限
+	DayResetAt           time.Time   `json:"day_reset_at"`
+	MonthResetAt         time.Time   `json:"month_reset_at"`
+}
+
+// NewQuotaStatus - 上限と消費量から残りを計算
+func NewQuotaStatus(policy QuotaPolicy, usage QuotaUsage, now time.Time) *QuotaStatus {
+	dayStart, monthStart := QuotaPeriodStarts(now)
+	return &QuotaStatus{
+		Policy:               policy,
+		Usage:                usage,
+		RemainingReviewsDay:  remaining(policy.ReviewsPerDay, usage.ReviewsToday),
+		RemainingTokensMonth: remaining(policy.TokensPerMonth, usage.TokensThisMonth),
+		DayResetAt:           dayStart.AddDate(0, 0, 1),
+		MonthResetAt:         monthStart.AddDate(0, 1, 0),
+	}
+}
+
+// Check - 上限に達していればエラーを返す
+// トークン数はレビュー前に分からないため、上限に達するまでは受け付ける（最後の1件は上限を超えうる）
+func (s *QuotaStatus) Check() error {
+	if s.RemainingReviewsDay != nil && *s.RemainingReviewsDay == 0 {
+		return &QuotaExceededError{
+			Kind:    QuotaKindReviewsPerDay,
+			Limit:   s.Policy.ReviewsPerDay,
+			Used:    s.Usage.ReviewsToday,
+			ResetAt: s.DayResetAt,
+			Status:  s,
+		}
+	}
+	if s.RemainingTokensMonth != nil && *s.RemainingTokensMonth == 0 {
+		return &QuotaExceededError{
+			Kind:    QuotaKindTokensPerMonth,
+			Limit:   s.Policy.TokensPerMonth,
+			Used:    s.Usage.TokensThisMonth,
+			ResetAt: s.MonthResetAt,
+			Status:  s,
+		}
+	}
+	return nil
+}
+
+// QuotaExceededError - 利用上限超過エラー（errors.Is(err, ErrQuotaExceeded) で判定できる）
+type QuotaExceededError struct {
+	Kind    string       `json:"kind"`
+	Limit   int          `json:"limit"`
+	Used    int          `json:"used"`
+	ResetAt time.Time    `json:"reset_at"`
+	Status  *QuotaStatus `json:"status"`
+}
+
+// Error - エラーメッセージ
+func (e *QuotaExceededError) Error() string {
+	switch e.Kind {
+	case QuotaKindReviewsPerDay:
+		return fmt.Sprintf("1日あたりのレビュー数の上限（%d件）に達しました", e.Limit)
+	case QuotaKindTokensPerMonth:
+		return fmt.Sprintf("1か月あたりのトークン数の上限（%d）に達しました", e.Limit)
+	}
+	return ErrQuotaExceeded.Error()
+}
+
+// Unwrap - errors.Is(err, ErrQuotaExceeded) を満たす
+func (e *QuotaExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// QuotaPeriodStarts - 上限の対象期間（UTCの当日0時・当月1日0時）
+func QuotaPeriodStarts(now time.Time) (dayStart, monthStart time.Time) {
+	now = now.UTC()
+	dayStart = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return dayStart, monthStart
+}
+
+// remaining - 残り（上限0は無制限のため nil）
+func remaining(limit, used int) *int {
+	if limit <= 0 {
+		return nil
+	}
+	left := limit - used
+	if left < 0 {
+		left = 0
+	}
+	return &left
+}
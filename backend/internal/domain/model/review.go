@@ -1,7 +1,9 @@
 package model
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,22 +11,83 @@ import (
 
 // Review - コードレビューエンティティ
 type Review struct {
-	ID                  string                  `json:"id"`
-	UserID              string                  `json:"user_id"`
-	Code                string                  `json:"code"`
-	Language            string                  `json:"language"`
-	Context             string                  `json:"context,omitempty"`
-	ReviewResult        string                  `json:"review_result"`                  // マークダウン（元データ）
-	StructuredResult    *StructuredReviewResult `json:"structured_result,omitempty"`    // 構造化データ
-	ReferencedKnowledge []string                `json:"referenced_knowledge"`
-	LLMProvider         string                  `json:"llm_provider"`
-	LLMModel            string                  `json:"llm_model"`
-	TokensUsed          int                     `json:"tokens_used"`
-	FeedbackScore       *int                    `json:"feedback_score,omitempty"`
-	FeedbackComment     string                  `json:"feedback_comment,omitempty"`
-	CreatedAt           time.Time               `json:"created_at"`
-	UpdatedAt           time.Time               `json:"updated_at"`
-	DeletedAt           *time.Time              `json:"deleted_at,omitempty"`
+	ID                     string                  `json:"id"`
+	UserID                 string                  `json:"user_id"`
+	Code                   string                  `json:"code"`
+	Language               string                  `json:"language"`
+	FilePath               string                  `json:"file_path,omitempty"` // レビューしたファイルのパス（指定がない場合は空）
+	InputFormat            string                  `json:"input_format"`        // Code の形式（code: ファイル全体 / diff: unified diff）
+	Locale                 string                  `json:"locale"`              // プロンプト・レビュー結果の見出しの言語（ja / en）
+	Context                string                  `json:"context,omitempty"`
+	ReviewResult           string                  `json:"review_result"`               // マークダウン（元データ）
+	StructuredResult       *StructuredReviewResult `json:"structured_result,omitempty"` // 構造化データ
+	ResultSource           string                  `json:"result_source"`               // 構造化データの生成経路
+	PromptTemplateID       *string                 `json:"prompt_template_id"`          // 使用したプロンプトテンプレート（組み込みの場合はnil）
+	PromptTemplateVersion  int                     `json:"prompt_template_version"`     // 使用したテンプレートのバージョン（組み込みは0）
+	ReferencedKnowledge    []string                `json:"referenced_knowledge"`
+	LLMProvider            string                  `json:"llm_provider"`
+	LLMModel               string                  `json:"llm_model"`
+	TokensUsed             int                     `json:"tokens_used"` // 入力 + 出力トークン数（内訳は Usage）
+	Usage                  TokenUsage              `json:"usage"`
+	Cost                   float64                 `json:"cost"`                               // 料金表から算出したコスト
+	CostCurrency           string                  `json:"cost_currency"`                      // コストの通貨（USD など）
+	CacheKey               string                  `json:"-"`                                  // 結果キャッシュのキー（入力・ナレッジ・テンプレート・モデルのハッシュ）
+	CacheHit               bool                    `json:"cache_hit"`                          // 過去のレビュー結果を再利用したか
+	CachedFromReviewID     *string                 `json:"cached_from_review_id,omitempty"`    // 再利用した元のレビュー
+	EnsembleID             *string                 `json:"ensemble_id,omitempty"`              // 複数モデルで同時にレビューした場合のグループ
+	SessionID              *string                 `json:"session_id,omitempty"`               // 複数ファイルをまとめてレビューした場合のセッション
+	PromptInjectionSignals []PromptInjectionSignal `json:"prompt_injection_signals,omitempty"` // プロンプトインジェクションの疑いがある箇所
+	Status                 string                  `json:"status"`                             // pending / running / completed / failed
+	Async                  bool                    `json:"async"`                              // 非同期のレビュー（ジョブ）として受け付けたか
+	JobOptions             *ReviewJobOptions       `json:"-"`                                  // 非同期のレビューの実行に必要な入力
+	ErrorMessage           string                  `json:"error_message,omitempty"`            // 失敗した理由
+	Attempts               int                     `json:"attempts"`                           // 実行した回数（同期的なレビューは0）
+	StartedAt              *time.Time              `json:"started_at,omitempty"`               // 最後に実行を開始した日時
+	CompletedAt            *time.Time              `json:"completed_at,omitempty"`             // 完了・失敗した日時
+	RerunOfReviewID        *string                 `json:"rerun_of_review_id,omitempty"`       // 再レビューの元のレビュー
+	FollowUpOfReviewID     *string                 `json:"follow_up_of_review_id,omitempty"`   // 改善点を適用したコードのレビューの場合、適用元のレビュー
+	StaticAnalysis         *StaticAnalysis         `json:"analysis,omitempty"`                 // LLM を呼び出す前に計算した静的解析の結果（Go のコードのみ）
+	FeedbackScore          *int                    `json:"feedback_score,omitempty"`
+	FeedbackComment        string                  `json:"feedback_comment,omitempty"`
+	CreatedAt              time.Time               `json:"created_at"`
+	UpdatedAt              time.Time               `json:"updated_at"`
+	DeletedAt              *time.Time              `json:"deleted_at,omitempty"`
+	PurgedAt               *time.Time              `json:"-"` // ゴミ箱の保持期間を過ぎて内容を消した日時（使用量の列のみ残る）
+}
+
+// ErrReviewCacheMiss - 再利用できるレビュー結果がない
+var ErrReviewCacheMiss = errors.New("review cache miss")
+
+// ErrReviewNotFound - レビューが見つからない（他のユーザーのレビューを含む）
+var ErrReviewNotFound = errors.New("レビューが見つかりません")
+
+// レビューする入力の形式
+const (
+	ReviewInputCode = "code" // ファイル全体（または一部）のコード
+	ReviewInputDiff = "diff" // unified diff（追加・変更したハンクのみレビューする）
+)
+
+// 差分のレビューのエラー
+var (
+	ErrDiffInvalid      = errors.New("差分（unified diff）の形式が不正です")
+	ErrDiffNoChanges    = errors.New("差分にレビューできる追加・変更がありません")
+	ErrDiffTooManyHunks = errors.New("差分のハンク数が上限を超えています")
+	ErrDiffWithCode     = errors.New("code と diff はどちらか一方のみ指定してください")
+	ErrDiffLanguage     = errors.New("差分のファイルのプログラミング言語を判定できません（language を指定してください）")
+)
+
+// 構造化データの生成経路
+const (
+	ResultSourceToolUse    = "tool_use"    // Claude のツール呼び出し
+	ResultSourceJSONSchema = "json_schema" // OpenAI互換APIの JSON Schema 出力
+	ResultSourceMarkdown   = "markdown"    // マークダウンを正規表現でパース（フォールバック）
+)
+
+// 改善点の重要度
+var validSeverities = map[string]bool{
+	"low":    true,
+	"medium": true,
+	"high":   true,
 }
 
 // StructuredReviewResult - 構造化されたレビュー結果
@@ -38,21 +101,92 @@ type StructuredReviewResult struct {
 type Improvement struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
+	CodeBefore  string `json:"code_before,omitempty"` // 改善前のコード（レビューしたコードから該当行を抜き出したもの）
 	CodeAfter   string `json:"code_after,omitempty"`
 	Severity    string `json:"severity"` // low, medium, high
+	// 改善点の対象の行範囲（1始まり・終了行を含む。特定できない場合は0）
+	// レビューしたコードと照合済みで、差分のレビューの場合は変更後のファイルでの行番号
+	StartLine int `json:"start_line,omitempty"`
+	EndLine   int `json:"end_line,omitempty"`
+	// 差分のレビューの場合のみ設定：改善点の対象のファイル
+	FilePath string `json:"file_path,omitempty"`
+	// 複数モデルの結果を統合した場合のみ設定
+	AgreedBy  []string `json:"agreed_by,omitempty"` // この改善点を指摘したモデル
+	Consensus bool     `json:"consensus,omitempty"` // 2つ以上のモデルが指摘したか
+}
+
+// Validate - LLMが返した構造化データを検証
+func (s *StructuredReviewResult) Validate() error {
+	if strings.TrimSpace(s.Summary) == "" {
+		return fmt.Errorf("summary is required")
+	}
+	for i, point := range s.GoodPoints {
+		if strings.TrimSpace(point) == "" {
+			return fmt.Errorf("good_points[%d] is empty", i)
+		}
+	}
+	for i, imp := range s.Improvements {
+		if strings.TrimSpace(imp.Title) == "" {
+			return fmt.Errorf("improvements[%d].title is required", i)
+		}
+		if strings.TrimSpace(imp.Description) == "" {
+			return fmt.Errorf("improvements[%d].description is required", i)
+		}
+		if !validSeverities[imp.Severity] {
+			return fmt.Errorf("improvements[%d].severity must be one of low, medium, high: %q", i, imp.Severity)
+		}
+	}
+	return nil
 }
 
 // NewReview - レビューを生成
+// 同期的なレビューは結果を生成してから作成するため、完了した状態で生成する
 func NewReview(userID, code, language, context string) *Review {
+	now := time.Now()
 	return &Review{
-		ID:        uuid.New().String(),
-		UserID:    userID,
-		Code:      code,
-		Language:  language,
-		Context:   context,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Code:        code,
+		Language:    language,
+		Context:     context,
+		Locale:      DefaultLocale,
+		InputFormat: ReviewInputCode,
+		Status:      ReviewStatusCompleted,
+		CompletedAt: &now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// SetLocale - プロンプト・レビュー結果の言語を設定（未対応の場合は既定のロケール）
+func (r *Review) SetLocale(locale string) {
+	r.Locale = ResolveLocale(locale)
+}
+
+// SetDiff - 入力が unified diff であることを記録（Code には差分をそのまま保存する）
+func (r *Review) SetDiff() {
+	r.InputFormat = ReviewInputDiff
+}
+
+// IsDiff - 入力が unified diff か
+func (r *Review) IsDiff() bool {
+	return r.InputFormat == ReviewInputDiff
+}
+
+// SetResultSource - 構造化データの生成経路を設定
+func (r *Review) SetResultSource(source string) {
+	r.ResultSource = source
+}
+
+// SetPromptTemplate - 使用したプロンプトテンプレートを記録
+func (r *Review) SetPromptTemplate(template *PromptTemplate) {
+	if template.IsBuiltin() {
+		r.PromptTemplateID = nil
+	} else {
+		id := template.ID
+		r.PromptTemplateID = &id
 	}
+	r.PromptTemplateVersion = template.Version
 }
 
 // SetReviewResult - レビュー結果を設定
@@ -66,18 +200,48 @@ func (r *Review) SetReviewResult(result string, structuredResult *StructuredRevi
 	r.UpdatedAt = time.Now()
 }
 
+// SetUsage - トークン使用量の内訳とコストを設定
+func (r *Review) SetUsage(usage TokenUsage, cost float64, currency string) {
+	r.Usage = usage
+	r.Cost = cost
+	r.CostCurrency = currency
+	r.UpdatedAt = time.Now()
+}
+
+// SetCacheKey - 結果キャッシュのキーを設定
+func (r *Review) SetCacheKey(key string) {
+	r.CacheKey = key
+}
+
+// ReuseResult - 過去のレビュー結果を再利用する（LLMを呼び出していないため TokensUsed は0）
+func (r *Review) ReuseResult(source *Review) {
+	r.SetReviewResult(source.ReviewResult, source.StructuredResult, source.ReferencedKnowledge, source.LLMProvider, source.LLMModel, 0)
+	r.ResultSource = source.ResultSource
+	r.PromptTemplateID = source.PromptTemplateID
+	r.PromptTemplateVersion = source.PromptTemplateVersion
+	r.CacheKey = source.CacheKey
+	r.CacheHit = true
+	sourceID := source.ID
+	r.CachedFromReviewID = &sourceID
+}
+
+// SetEnsemble - 複数モデルで同時にレビューしたグループを設定
+func (r *Review) SetEnsemble(ensembleID string) {
+	r.EnsembleID = &ensembleID
+}
+
 // SetFeedback - ユーザーフィードバックを設定
 func (r *Review) SetFeedback(score int, comment string) error {
 	// スコアのバリデーション
 	if score < 1 || score > 3 {
 		return fmt.Errorf("スコアは1-3の整数で指定してください")
 	}
-	
+
 	// コメントの長さチェック
 	if len(comment) > 500 {
 		return fmt.Errorf("コメントは500文字以内にしてください")
 	}
-	
+
 	r.FeedbackScore = &score
 	r.FeedbackComment = comment
 	r.UpdatedAt = time.Now()
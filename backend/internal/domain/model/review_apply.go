@@ -0,0 +1,32 @@
+package model
+
+import "errors"
+
+// 改善点の適用のエラー
+var (
+	// ErrApplyNoImprovements - 適用する改善点が指定されていない
+	ErrApplyNoImprovements = errors.New("適用する改善点を1つ以上指定してください")
+	// ErrApplyImprovementIndex - 指定した番号の改善点がレビュー結果にない
+	ErrApplyImprovementIndex = errors.New("指定した番号の改善点がありません")
+	// ErrImprovementNotApplicable - 修正前のコードがレビューしたコードになく、行範囲もない改善点は適用できない
+	ErrImprovementNotApplicable = errors.New("修正前のコードをレビューしたコードから特定できないため、この改善点は適用できません")
+	// ErrImprovementNoCodeAfter - 修正後のコードがない改善点は適用できない
+	ErrImprovementNoCodeAfter = errors.New("修正後のコードがないため、この改善点は適用できません")
+	// ErrImprovementsOverlap - 同じ行を書き換える改善点は同時に適用できない
+	ErrImprovementsOverlap = errors.New("同じ行を書き換える改善点は同時に適用できません")
+	// ErrApplyDiffReview - 差分のレビューはファイル全体のコードがないため適用できない
+	ErrApplyDiffReview = errors.New("差分のレビューには改善点を適用できません")
+	// ErrAppliedCodeInvalid - 適用後のコードに構文エラーがある場合はレビューとして保存しない
+	ErrAppliedCodeInvalid = errors.New("適用後のコードに構文エラーがあるため保存できません")
+)
+
+// ImprovementNotApplied - 選択したが適用できなかった改善点
+type ImprovementNotApplied struct {
+	Index  int   // 改善点の番号（0始まり）
+	Reason error // ErrImprovementNotApplicable または ErrImprovementNoCodeAfter
+}
+
+// SetFollowUpOf - 改善点を適用したコードのレビューに、適用元のレビューを記録する
+func (r *Review) SetFollowUpOf(reviewID string) {
+	r.FollowUpOfReviewID = &reviewID
+}
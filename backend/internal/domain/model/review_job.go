@@ -0,0 +1,87 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// レビューの状態（非同期のレビューは pending → running → completed / failed の順に遷移する）
+const (
+	ReviewStatusPending   = "pending"   // 実行待ち
+	ReviewStatusRunning   = "running"   // 実行中
+	ReviewStatusCompleted = "completed" // 完了（同期的なレビューは常に completed）
+	ReviewStatusFailed    = "failed"    // 失敗（再実行できる）
+)
+
+// ValidReviewStatuses - レビューの状態の一覧
+var ValidReviewStatuses = []string{ReviewStatusPending, ReviewStatusRunning, ReviewStatusCompleted, ReviewStatusFailed}
+
+// 非同期のレビューのエラー
+var (
+	ErrNoPendingReviewJob    = errors.New("no pending review job")
+	ErrReviewJobLost         = errors.New("review job is no longer owned") // 状態・実行回数が変わっていた（他のワーカーが取得し直した・削除された）
+	ErrReviewJobNotRetryable = errors.New("失敗したレビューのみ再実行できます")
+	ErrReviewNotCompleted    = errors.New("レビューが完了していません")
+	ErrReviewJobTimeout      = errors.New("レビューがタイムアウトしました")
+	ErrReviewJobFailed       = errors.New("レビューに失敗しました")
+	ErrReviewJobCancelled    = errors.New("レビューを削除したため中止しました")
+)
+
+// ReviewJobOptions - 非同期のレビューの実行に必要な、レビューの列にない入力
+type ReviewJobOptions struct {
+	ForceRefresh bool              `json:"force_refresh,omitempty"`
+	ContextFiles map[string]string `json:"context_files,omitempty"` // 差分のレビューで参照するファイル（パス → 変更後のファイル全体）
+	Overrides    *UserPreferences  `json:"overrides,omitempty"`     // 再レビューで上書きした設定
+}
+
+// NewReviewJob - 非同期のレビューを実行待ちの状態で生成
+func NewReviewJob(userID, code, language, context string, options ReviewJobOptions) *Review {
+	review := NewReview(userID, code, language, context)
+	review.Status = ReviewStatusPending
+	review.Async = true
+	review.JobOptions = &options
+	review.CompletedAt = nil
+	return review
+}
+
+// IsCompleted - レビュー結果があるか
+func (r *Review) IsCompleted() bool {
+	return r.Status == ReviewStatusCompleted
+}
+
+// TakeOverJob - 非同期のレビューの結果として、ジョブのID・作成日時・実行の記録を引き継ぐ
+func (r *Review) TakeOverJob(job *Review) {
+	r.ID = job.ID
+	r.Async = true
+	r.JobOptions = job.JobOptions
+	r.Attempts = job.Attempts
+	r.StartedAt = job.StartedAt
+	r.CreatedAt = job.CreatedAt
+}
+
+// Fail - 失敗した理由を記録して失敗の状態にする
+func (r *Review) Fail(message string) {
+	now := time.Now()
+	r.Status = ReviewStatusFailed
+	r.ErrorMessage = message
+	r.CompletedAt = &now
+	r.UpdatedAt = now
+}
+
+// Retry - 失敗したレビューを実行待ちに戻す（失敗した理由は消す）
+func (r *Review) Retry() error {
+	if r.Status != ReviewStatusFailed {
+		return ErrReviewJobNotRetryable
+	}
+	r.Status = ReviewStatusPending
+	r.ErrorMessage = ""
+	r.CompletedAt = nil
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// Requeue - 実行中に中断したレビューを実行待ちに戻す（サーバーの停止時など）
+func (r *Review) Requeue() {
+	r.Status = ReviewStatusPending
+	r.UpdatedAt = time.Now()
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ReviewMessage - レビューについての会話のメッセージ（ユーザーの質問とLLMの返答）
+type ReviewMessage struct {
+	ID       string `json:"id"`
+	ReviewID string `json:"review_id"`
+	UserID   string `json:"user_id"`
+	Role     string `json:"role"` // user / assistant
+	Content  string `json:"content"`
+	// SuggestedKnowledge - LLMが返答と一緒に提案したナレッジ（アシスタントのメッセージのみ。提案がない場合は nil）
+	SuggestedKnowledge *KnowledgeSuggestion `json:"suggested_knowledge,omitempty"`
+	// KnowledgeID - このメッセージから作成したナレッジ（作成していない場合は nil）
+	KnowledgeID *string `json:"knowledge_id,omitempty"`
+	// 返答を生成したLLMと使用量（アシスタントのメッセージのみ）
+	LLMProvider  string     `json:"llm_provider,omitempty"`
+	LLMModel     string     `json:"llm_model,omitempty"`
+	TokensUsed   int        `json:"tokens_used"`
+	Usage        TokenUsage `json:"usage"`
+	Cost         float64    `json:"cost"`
+	CostCurrency string     `json:"cost_currency,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// KnowledgeSuggestion - 会話からLLMが提案したナレッジの候補
+type KnowledgeSuggestion struct {
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+	Category string `json:"category"`
+	Priority int    `json:"priority"`
+}
+
+// メッセージの送信者
+const (
+	ReviewMessageRoleUser      = "user"
+	ReviewMessageRoleAssistant = "assistant"
+)
+
+// MaxReviewMessageLength - ユーザーが送信できるメッセージの最大文字数
+const MaxReviewMessageLength = 4000
+
+// レビューについての会話のエラー
+var (
+	ErrConversationDisabled         = errors.New("レビューについての会話は無効になっています")
+	ErrReviewMessageRequired        = errors.New("メッセージは必須です")
+	ErrReviewMessageTooLong         = errors.New("メッセージは4000文字以内にしてください")
+	ErrReviewMessageLimit           = errors.New("このレビューの会話のメッセージ数が上限に達しました")
+	ErrReviewMessageNotFound        = errors.New("メッセージが見つかりません")
+	ErrReviewMessageKnowledgeExists = errors.New("このメッセージからはナレッジを作成済みです")
+)
+
+// NewReviewMessage - ユーザーのメッセージを生成
+func NewReviewMessage(reviewID, userID, content string) (*ReviewMessage, error) {
+	m := &ReviewMessage{
+		ID:        uuid.New().String(),
+		ReviewID:  reviewID,
+		UserID:    userID,
+		Role:      ReviewMessageRoleUser,
+		Content:   strings.TrimSpace(content),
+		CreatedAt: time.Now(),
+	}
+
+	if m.Content == "" {
+		return nil, ErrReviewMessageRequired
+	}
+	if utf8.RuneCountInString(m.Content) > MaxReviewMessageLength {
+		return nil, ErrReviewMessageTooLong
+	}
+
+	return m, nil
+}
+
+// NewAssistantMessage - LLMの返答のメッセージを生成
+func NewAssistantMessage(reviewID, userID, content string, suggestion *KnowledgeSuggestion) *ReviewMessage {
+	return &ReviewMessage{
+		ID:                 uuid.New().String(),
+		ReviewID:           reviewID,
+		UserID:             userID,
+		Role:               ReviewMessageRoleAssistant,
+		Content:            strings.TrimSpace(content),
+		SuggestedKnowledge: suggestion,
+		CreatedAt:          time.Now(),
+	}
+}
+
+// SetUsage - 返答を生成したLLMとトークン使用量・コストを設定
+func (m *ReviewMessage) SetUsage(llmProvider, llmModel string, tokensUsed int, usage TokenUsage, cost float64, currency string) {
+	m.LLMProvider = llmProvider
+	m.LLMModel = llmModel
+	m.TokensUsed = tokensUsed
+	m.Usage = usage
+	m.Cost = cost
+	m.CostCurrency = currency
+}
+
+// IsUser - ユーザーのメッセージか
+func (m *ReviewMessage) IsUser() bool {
+	return m.Role == ReviewMessageRoleUser
+}
+
+// Validate - LLMが提案したナレッジを検証（ナレッジとして登録できる値か）
+func (s *KnowledgeSuggestion) Validate() error {
+	k := Knowledge{
+		Title:      strings.TrimSpace(s.Title),
+		Content:    strings.TrimSpace(s.Content),
+		Category:   s.Category,
+		Priority:   s.Priority,
+		SourceType: SourceTypeConversation,
+	}
+	return k.Validate()
+}
@@ -0,0 +1,57 @@
+package model
+
+import "errors"
+
+// ErrReviewNoStructuredResult - 構造化されたレビュー結果がないレビューは改善点を比較できない
+var ErrReviewNoStructuredResult = errors.New("構造化されたレビュー結果がないため比較できません")
+
+// SetRerunOf - 再レビューの元のレビューを記録する
+func (r *Review) SetRerunOf(reviewID string) {
+	r.RerunOfReviewID = &reviewID
+}
+
+// ReviewResultDiff - 2つのレビュー結果の改善点の比較（基準のレビュー → 比較するレビュー）
+type ReviewResultDiff struct {
+	Added      []Improvement           `json:"added"`      // 比較するレビューにのみある改善点
+	Removed    []Improvement           `json:"removed"`    // 基準のレビューにのみある改善点
+	Persisting []PersistingImprovement `json:"persisting"` // 両方のレビューにある改善点
+}
+
+// PersistingImprovement - 両方のレビューにある改善点の組
+type PersistingImprovement struct {
+	Base            Improvement `json:"base"`
+	Other           Improvement `json:"other"`
+	SeverityChanged bool        `json:"severity_changed"` // 重要度が変わったか
+}
+
+// WithOverrides - 設定の一部を上書きした設定を返す（overrides の空の項目は元の設定のまま）
+// プロバイダを上書きした場合は、モデルも上書きした値（空の場合はプロバイダの設定値）にする
+func (p *UserPreferences) WithOverrides(overrides *UserPreferences) *UserPreferences {
+	merged := UserPreferences{}
+	if p != nil {
+		merged = *p
+	}
+	if overrides == nil {
+		return &merged
+	}
+
+	if overrides.LLMProvider != "" {
+		merged.LLMProvider = overrides.LLMProvider
+		merged.LLMModel = overrides.LLMModel
+	} else if overrides.LLMModel != "" {
+		merged.LLMModel = overrides.LLMModel
+	}
+	if overrides.Temperature != nil {
+		merged.Temperature = overrides.Temperature
+	}
+	if overrides.MaxTokens != nil {
+		merged.MaxTokens = overrides.MaxTokens
+	}
+	if overrides.OutputLanguage != "" {
+		merged.OutputLanguage = overrides.OutputLanguage
+	}
+	if len(overrides.ReviewFocus) > 0 {
+		merged.ReviewFocus = overrides.ReviewFocus
+	}
+	return &merged
+}
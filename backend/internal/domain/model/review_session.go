@@ -0,0 +1,153 @@
+package model
+
+import (
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewSession - 複数のファイルをまとめてレビューした単位
+// ファイルごとのレビューは Review として1件ずつ保存し、SessionID で紐付ける
+type ReviewSession struct {
+	ID           string            `json:"id"`
+	UserID       string            `json:"user_id"`
+	Title        string            `json:"title"`
+	Locale       string            `json:"locale"`
+	Summary      string            `json:"summary"`      // ファイルごとの総合評価をまとめたもの
+	FileCount    int               `json:"file_count"`   // レビューしたファイル数（失敗したファイルは含めない）
+	Improvements ImprovementCounts `json:"improvements"` // 全ファイルの改善点の数
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	DeletedAt    *time.Time        `json:"deleted_at,omitempty"`
+}
+
+// ImprovementCounts - 改善点の数（重要度ごとの内訳）
+type ImprovementCounts struct {
+	Total  int `json:"total"`
+	High   int `json:"high"`
+	Medium int `json:"medium"`
+	Low    int `json:"low"`
+}
+
+// 複数ファイルのレビューのエラー
+var (
+	ErrReviewSessionFilesRequired        = errors.New("ファイルを1つ以上指定してください")
+	ErrReviewSessionTooManyFiles         = errors.New("ファイル数が上限を超えています")
+	ErrReviewSessionFilePathRequired     = errors.New("ファイルのパスは必須です")
+	ErrReviewSessionFilePathDuplicate    = errors.New("同じパスのファイルが含まれています")
+	ErrReviewSessionFileCodeRequired     = errors.New("ファイルのコードは必須です")
+	ErrReviewSessionFileLanguageRequired = errors.New("ファイルのプログラミング言語を判定できません（language を指定してください）")
+	ErrReviewSessionNotFound             = errors.New("レビューセッションが見つかりません")
+)
+
+// NewReviewSession - レビューセッションを生成
+func NewReviewSession(userID, title, locale string) *ReviewSession {
+	now := time.Now()
+	return &ReviewSession{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Title:     title,
+		Locale:    ResolveLocale(locale),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// SetResult - ファイルごとのレビューから総合評価とファイル数・改善点の数を設定
+func (s *ReviewSession) SetResult(summary string, reviews []*Review) {
+	s.Summary = summary
+	s.FileCount = len(reviews)
+	s.Improvements = ImprovementCounts{}
+	for _, r := range reviews {
+		s.Improvements.Add(CountImprovements(r.StructuredResult))
+	}
+	s.UpdatedAt = time.Now()
+}
+
+// CountImprovements - レビュー結果の改善点を重要度ごとに数える
+func CountImprovements(result *StructuredReviewResult) ImprovementCounts {
+	var counts ImprovementCounts
+	if result == nil {
+		return counts
+	}
+	for _, imp := range result.Improvements {
+		counts.Total++
+		switch imp.Severity {
+		case "high":
+			counts.High++
+		case "medium":
+			counts.Medium++
+		case "low":
+			counts.Low++
+		}
+	}
+	return counts
+}
+
+// Add - 改善点の数を足し合わせる
+func (c *ImprovementCounts) Add(other ImprovementCounts) {
+	c.Total += other.Total
+	c.High += other.High
+	c.Medium += other.Medium
+	c.Low += other.Low
+}
+
+// SetFile - レビューしたファイルのパスを設定（区切り文字は / に揃える）
+func (r *Review) SetFile(filePath string) {
+	r.FilePath = NormalizeFilePath(filePath)
+}
+
+// SetSession - 複数ファイルをまとめてレビューしたセッションを設定
+func (r *Review) SetSession(sessionID string) {
+	r.SessionID = &sessionID
+}
+
+// FileName - レビューしたファイルの名前（パスがない場合は空文字列）
+func (r *Review) FileName() string {
+	if r.FilePath == "" {
+		return ""
+	}
+	return path.Base(r.FilePath)
+}
+
+// NormalizeFilePath - ファイルのパスの区切り文字を / に揃え、先頭の ./ と前後の空白を取り除く
+func NormalizeFilePath(filePath string) string {
+	filePath = strings.TrimSpace(strings.ReplaceAll(filePath, "\\", "/"))
+	for strings.HasPrefix(filePath, "./") {
+		filePath = strings.TrimPrefix(filePath, "./")
+	}
+	return filePath
+}
+
+// 拡張子とプログラミング言語（レビュー履歴の言語フィルターと同じ名前）の対応
+var languagesByExtension = map[string]string{
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".js":    "JavaScript",
+	".jsx":   "JavaScript",
+	".mjs":   "JavaScript",
+	".cjs":   "JavaScript",
+	".py":    "Python",
+	".go":    "Go",
+	".java":  "Java",
+	".cpp":   "C++",
+	".cc":    "C++",
+	".cxx":   "C++",
+	".hpp":   "C++",
+	".h":     "C++",
+	".cs":    "C#",
+	".rb":    "Ruby",
+	".php":   "PHP",
+	".rs":    "Rust",
+	".swift": "Swift",
+	".kt":    "Kotlin",
+	".kts":   "Kotlin",
+}
+
+// LanguageFromPath - ファイルの拡張子からプログラミング言語を推定（不明な場合は空文字列）
+func LanguageFromPath(filePath string) string {
+	return languagesByExtension[strings.ToLower(path.Ext(NormalizeFilePath(filePath)))]
+}
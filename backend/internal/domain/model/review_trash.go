@@ -0,0 +1,26 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrReviewDeleteForbidden - 他のユーザーのレビューは削除できない
+	ErrReviewDeleteForbidden = errors.New("このレビューを削除する権限がありません")
+	// ErrReviewRestoreForbidden - 他のユーザーのレビューは復元できない
+	ErrReviewRestoreForbidden = errors.New("このレビューを復元する権限がありません")
+	// ErrReviewFilterRequired - 条件を指定しない一括削除は全件の削除になるため受け付けない
+	ErrReviewFilterRequired = errors.New("削除するレビューの条件を1つ以上指定してください")
+	// ErrReviewFilterInvalid - 一括削除の条件が不正
+	ErrReviewFilterInvalid = errors.New("削除するレビューの条件が不正です")
+)
+
+// PurgeAt - 削除したレビューを完全に削除する予定日時（削除していない場合は nil）
+func (r *Review) PurgeAt(retention time.Duration) *time.Time {
+	if r.DeletedAt == nil {
+		return nil
+	}
+	purgeAt := r.DeletedAt.Add(retention)
+	return &purgeAt
+}
@@ -0,0 +1,83 @@
+package model
+
+// 静的解析の指摘の種類
+const (
+	AnalysisRuleSyntaxError    = "syntax_error"    // 構文エラー
+	AnalysisRuleIgnoredError   = "ignored_error"   // 戻り値（エラーの可能性）を捨てている
+	AnalysisRuleLongFunction   = "long_function"   // 関数が長い
+	AnalysisRuleDeepNesting    = "deep_nesting"    // ネストが深い
+	AnalysisRuleHighComplexity = "high_complexity" // 循環的複雑度が高い
+)
+
+// 静的解析のしきい値（値がしきい値を超えた関数を指摘する）
+const (
+	MaxFunctionLines        = 50 // 関数の行数（ナレッジの「関数は50行以内」に揃える）
+	MaxNestingDepth         = 3  // 関数の本体からの if / for / switch / select / 関数リテラルのネストの深さ
+	MaxCyclomaticComplexity = 10 // 循環的複雑度
+)
+
+// StaticAnalysis - LLM を呼び出す前に計算した静的解析の結果（Go のコードのみ）
+// プロンプトに事実として含め、レビューの analysis として返す
+type StaticAnalysis struct {
+	Findings  []AnalysisFinding `json:"findings"`  // 指摘（行番号順）
+	Functions []FunctionMetrics `json:"functions"` // 関数ごとの計測値（構文エラーがある場合は空）
+}
+
+// AnalysisFinding - 静的解析の指摘
+type AnalysisFinding struct {
+	Rule      string `json:"rule"`
+	StartLine int    `json:"start_line"` // レビューしたコードの行番号（1始まり）
+	EndLine   int    `json:"end_line"`
+	Function  string `json:"function,omitempty"`  // 指摘した箇所を含む関数（メソッドは Type.Method）
+	Call      string `json:"call,omitempty"`      // ignored_error の場合、戻り値を捨てている呼び出し
+	Message   string `json:"message"`             // 指摘の内容（日本語）
+	Value     int    `json:"value,omitempty"`     // 計測値（行数・ネストの深さ・循環的複雑度）
+	Threshold int    `json:"threshold,omitempty"` // しきい値
+}
+
+// FunctionMetrics - 関数の計測値
+type FunctionMetrics struct {
+	Name       string `json:"name"` // メソッドは Type.Method、関数の外の文の並びは空
+	StartLine  int    `json:"start_line"`
+	EndLine    int    `json:"end_line"`
+	Lines      int    `json:"lines"`       // func の行から閉じ括弧の行までの行数
+	MaxNesting int    `json:"max_nesting"` // ネストの最大の深さ
+	Complexity int    `json:"complexity"`  // 循環的複雑度（1 + 分岐の数）
+}
+
+// HasFindings - 指摘があるか
+func (a *StaticAnalysis) HasFindings() bool {
+	return a != nil && len(a.Findings) > 0
+}
+
+// WithinLines - 開始行が start〜end 行目の指摘のみを、start 行目を1とする行番号にして返す
+// 分割したチャンクのレビューで、チャンクの範囲の指摘をチャンクの行番号でプロンプトに含めるために使う
+func (a *StaticAnalysis) WithinLines(start, end int) *StaticAnalysis {
+	if a == nil {
+		return nil
+	}
+	within := &StaticAnalysis{Findings: []AnalysisFinding{}, Functions: []FunctionMetrics{}}
+	offset := start - 1
+	for _, f := range a.Findings {
+		if f.StartLine < start || f.StartLine > end {
+			continue
+		}
+		f.StartLine -= offset
+		f.EndLine -= offset
+		within.Findings = append(within.Findings, f)
+	}
+	for _, m := range a.Functions {
+		if m.StartLine < start || m.StartLine > end {
+			continue
+		}
+		m.StartLine -= offset
+		m.EndLine -= offset
+		within.Functions = append(within.Functions, m)
+	}
+	return within
+}
+
+// SetStaticAnalysis - 静的解析の結果を記録
+func (r *Review) SetStaticAnalysis(analysis *StaticAnalysis) {
+	r.StaticAnalysis = analysis
+}
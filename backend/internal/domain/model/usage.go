@@ -0,0 +1,66 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// TokenUsage - 1回のレビューで消費したトークン数の内訳
+type TokenUsage struct {
+	InputTokens         int `json:"input_tokens"`          // 入力（キャッシュ分を除く）
+	OutputTokens        int `json:"output_tokens"`         // 出力
+	CacheCreationTokens int `json:"cache_creation_tokens"` // プロンプトキャッシュへの書き込み
+	CacheReadTokens     int `json:"cache_read_tokens"`     // プロンプトキャッシュからの読み込み
+	EmbeddingTokens     int `json:"embedding_tokens"`      // ナレッジ検索用のEmbedding
+}
+
+// Add - 使用量を合算
+func (u TokenUsage) Add(other TokenUsage) TokenUsage {
+	return TokenUsage{
+		InputTokens:         u.InputTokens + other.InputTokens,
+		OutputTokens:        u.OutputTokens + other.OutputTokens,
+		CacheCreationTokens: u.CacheCreationTokens + other.CacheCreationTokens,
+		CacheReadTokens:     u.CacheReadTokens + other.CacheReadTokens,
+		EmbeddingTokens:     u.EmbeddingTokens + other.EmbeddingTokens,
+	}
+}
+
+// Total - 全トークン数（利用上限の計算に使う）
+func (u TokenUsage) Total() int {
+	return u.InputTokens + u.OutputTokens + u.CacheCreationTokens + u.CacheReadTokens + u.EmbeddingTokens
+}
+
+// 使用量の集計単位
+const (
+	UsageGroupDay   = "day"   // 日別（UTC）
+	UsageGroupModel = "model" // プロバイダ・モデル別
+	UsageGroupUser  = "user"  // ユーザー別
+)
+
+// 使用量集計のバリデーションエラー
+var (
+	ErrUsageGroupInvalid = errors.New("group_by は day, model, user から指定してください")
+	ErrUsageRangeInvalid = errors.New("集計期間が不正です（to は from 以降、期間は366日以内）")
+)
+
+// UsageFilter - 使用量集計の条件
+type UsageFilter struct {
+	From    time.Time // この日時以降（含む）
+	To      time.Time // この日時より前（含まない）
+	UserID  string    // 空の場合は全ユーザー
+	GroupBy []string  // UsageGroup*（空の場合は通貨ごとの合計のみ）
+}
+
+// UsageSummary - 使用量・コストの集計行（GroupBy に含まれない項目は空）
+type UsageSummary struct {
+	Day         string     `json:"day,omitempty"` // YYYY-MM-DD
+	LLMProvider string     `json:"llm_provider,omitempty"`
+	LLMModel    string     `json:"llm_model,omitempty"`
+	UserID      string     `json:"user_id,omitempty"`
+	UserEmail   string     `json:"user_email,omitempty"`
+	UserName    string     `json:"user_name,omitempty"`
+	ReviewCount int        `json:"review_count"`
+	Usage       TokenUsage `json:"usage"`
+	Cost        float64    `json:"cost"`
+	Currency    string     `json:"currency"`
+}
@@ -1,6 +1,12 @@
 package model
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// ErrUserNotFound - ユーザーが見つからない
+var ErrUserNotFound = errors.New("user not found")
 
 // User - ユーザーエンティティ
 type User struct {
@@ -0,0 +1,147 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// レビュー結果の出力言語（ユーザーのロケールとしても使う）
+const (
+	OutputLanguageJapanese = LocaleJapanese
+	OutputLanguageEnglish  = LocaleEnglish
+)
+
+// レビューで重点的に確認する観点
+const (
+	ReviewFocusSecurity        = "security"
+	ReviewFocusPerformance     = "performance"
+	ReviewFocusReadability     = "readability"
+	ReviewFocusMaintainability = "maintainability"
+	ReviewFocusErrorHandling   = "error_handling"
+	ReviewFocusTesting         = "testing"
+)
+
+// reviewFocusLabels - 観点とプロンプトに含める表示名（ロケールごと）
+var reviewFocusLabels = map[string]map[string]string{
+	ReviewFocusSecurity:        {LocaleJapanese: "セキュリティ", LocaleEnglish: "security"},
+	ReviewFocusPerformance:     {LocaleJapanese: "パフォーマンス", LocaleEnglish: "performance"},
+	ReviewFocusReadability:     {LocaleJapanese: "可読性", LocaleEnglish: "readability"},
+	ReviewFocusMaintainability: {LocaleJapanese: "保守性", LocaleEnglish: "maintainability"},
+	ReviewFocusErrorHandling:   {LocaleJapanese: "エラー処理", LocaleEnglish: "error handling"},
+	ReviewFocusTesting:         {LocaleJapanese: "テストのしやすさ", LocaleEnglish: "testability"},
+}
+
+// 生成パラメータの範囲（どのプロバイダでも受け付けられる値に制限する）
+const (
+	MinPreferenceTemperature = 0.0
+	MaxPreferenceTemperature = 1.0
+	MaxPreferenceMaxTokens   = 16384
+)
+
+// ユーザー設定のエラー
+var (
+	ErrPreferenceTemperatureInvalid    = errors.New("temperature は0以上1以下で指定してください")
+	ErrPreferenceMaxTokensInvalid      = errors.New("max_tokens は1以上16384以下で指定してください")
+	ErrPreferenceOutputLanguageInvalid = errors.New("output_language は ja または en を指定してください")
+	ErrPreferenceReviewFocusInvalid    = errors.New("review_focus に未対応の観点が含まれています")
+	ErrPreferenceProviderUnavailable   = errors.New("指定されたLLMプロバイダは利用できません")
+)
+
+// UserPreferences - ユーザーごとのレビュー設定（users.preferences に保存）
+// 省略した項目はサーバーの設定（LLM_PROVIDER / CLAUDE_MODEL など）を使う
+type UserPreferences struct {
+	LLMProvider    string   `json:"llm_provider,omitempty"`    // anthropic / openai / ollama / openai_compatible
+	LLMModel       string   `json:"llm_model,omitempty"`       // 省略時はプロバイダの設定値
+	Temperature    *float64 `json:"temperature,omitempty"`     // 0〜1
+	MaxTokens      *int     `json:"max_tokens,omitempty"`      // 1回の生成の最大出力トークン数
+	OutputLanguage string   `json:"output_language,omitempty"` // ja / en
+	ReviewFocus    []string `json:"review_focus,omitempty"`    // 重点的に確認する観点
+}
+
+// ParseUserPreferences - users.preferences（JSON）を読み込む（空の場合は設定なし）
+func ParseUserPreferences(raw string) (*UserPreferences, error) {
+	prefs := &UserPreferences{}
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "null" {
+		return prefs, nil
+	}
+	if err := json.Unmarshal([]byte(raw), prefs); err != nil {
+		return nil, fmt.Errorf("failed to parse user preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// Validate - 値の範囲と選択肢を検証
+func (p *UserPreferences) Validate() error {
+	if p.Temperature != nil && (*p.Temperature < MinPreferenceTemperature || *p.Temperature > MaxPreferenceTemperature) {
+		return ErrPreferenceTemperatureInvalid
+	}
+	if p.MaxTokens != nil && (*p.MaxTokens < 1 || *p.MaxTokens > MaxPreferenceMaxTokens) {
+		return ErrPreferenceMaxTokensInvalid
+	}
+	if p.OutputLanguage != "" && p.OutputLanguage != OutputLanguageJapanese && p.OutputLanguage != OutputLanguageEnglish {
+		return ErrPreferenceOutputLanguageInvalid
+	}
+	for _, focus := range p.ReviewFocus {
+		if _, ok := reviewFocusLabels[focus]; !ok {
+			return ErrPreferenceReviewFocusInvalid
+		}
+	}
+	return nil
+}
+
+// Normalize - 前後の空白と重複した観点を取り除く
+func (p *UserPreferences) Normalize() {
+	p.LLMProvider = strings.TrimSpace(p.LLMProvider)
+	p.LLMModel = strings.TrimSpace(p.LLMModel)
+	p.OutputLanguage = strings.TrimSpace(p.OutputLanguage)
+
+	seen := map[string]bool{}
+	focus := make([]string, 0, len(p.ReviewFocus))
+	for _, f := range p.ReviewFocus {
+		f = strings.TrimSpace(f)
+		if f == "" || seen[f] {
+			continue
+		}
+		seen[f] = true
+		focus = append(focus, f)
+	}
+	p.ReviewFocus = focus
+}
+
+// HasModelOverride - プロバイダまたはモデルを指定しているか
+func (p *UserPreferences) HasModelOverride() bool {
+	return p != nil && (p.LLMProvider != "" || p.LLMModel != "")
+}
+
+// ReviewFocusLabels - 観点の表示名（指定順）
+func (p *UserPreferences) ReviewFocusLabels(locale string) []string {
+	if p == nil {
+		return nil
+	}
+	locale = ResolveLocale(locale)
+	labels := make([]string, 0, len(p.ReviewFocus))
+	for _, focus := range p.ReviewFocus {
+		if label, ok := reviewFocusLabels[focus]; ok {
+			labels = append(labels, label[locale])
+		}
+	}
+	return labels
+}
+
+// GetPreferences - 保存されたレビュー設定を読み込む
+func (u *User) GetPreferences() (*UserPreferences, error) {
+	return ParseUserPreferences(u.Preferences)
+}
+
+// SetPreferences - レビュー設定を保存用のJSONにして設定する
+func (u *User) SetPreferences(prefs *UserPreferences) error {
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user preferences: %w", err)
+	}
+	u.Preferences = string(data)
+	return nil
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// PromptTemplateRepository - プロンプトテンプレートリポジトリのインターフェース
+type PromptTemplateRepository interface {
+	// Create - テンプレートを新しいバージョンとして作成（Version は同名テンプレートの最大値+1を採番）
+	Create(ctx context.Context, template *model.PromptTemplate) error
+
+	// FindByID - IDでテンプレートを取得（見つからない場合は model.ErrPromptTemplateNotFound）
+	FindByID(ctx context.Context, id string) (*model.PromptTemplate, error)
+
+	// FindActive - 有効なバージョンを取得（見つからない場合は model.ErrPromptTemplateNotFound）
+	FindActive(ctx context.Context, name string) (*model.PromptTemplate, error)
+
+	// ListByName - 同名テンプレートの全バージョンを新しい順に取得
+	ListByName(ctx context.Context, name string) ([]*model.PromptTemplate, error)
+
+	// Activate - 指定したバージョンを有効化し、同名の他バージョンを無効化
+	Activate(ctx context.Context, id string) (*model.PromptTemplate, error)
+}
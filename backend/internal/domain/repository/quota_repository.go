@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// QuotaRepository - ユーザー個別の利用上限リポジトリのインターフェース
+type QuotaRepository interface {
+	// FindByUserID - ユーザー個別の上限を取得（設定がない場合は model.ErrUserQuotaNotFound）
+	FindByUserID(ctx context.Context, userID string) (*model.UserQuota, error)
+
+	// Upsert - ユーザー個別の上限を作成・更新
+	Upsert(ctx context.Context, quota *model.UserQuota) error
+
+	// Delete - ユーザー個別の上限を削除（グローバル設定に戻す）
+	Delete(ctx context.Context, userID string) error
+}
+
+// QuotaCounter - 利用上限の対象期間内の消費量カウンターのインターフェース
+type QuotaCounter interface {
+	// Usage - now を含む日（UTC）のレビュー数と月（UTC）のトークン数を取得
+	Usage(ctx context.Context, userID string, now time.Time) (model.QuotaUsage, error)
+
+	// Add - now を含む日のレビュー数と月のトークン数に加算
+	// reviews は非同期のレビューの受け付けの取り消し（失敗・削除）で -1 になる
+	Add(ctx context.Context, userID string, now time.Time, reviews, tokens int) error
+}
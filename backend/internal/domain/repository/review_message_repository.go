@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// ReviewMessageRepository - レビューについての会話のメッセージリポジトリのインターフェース
+type ReviewMessageRepository interface {
+	// CreateExchange - ユーザーのメッセージとLLMの返答をまとめて保存（片方だけ保存しない）
+	CreateExchange(ctx context.Context, question, reply *model.ReviewMessage) error
+
+	// FindByID - ユーザーのレビューのメッセージを取得（他のユーザーのメッセージ・なければ model.ErrReviewMessageNotFound）
+	FindByID(ctx context.Context, userID, reviewID, messageID string) (*model.ReviewMessage, error)
+
+	// ListByReviewID - レビューの会話を古い順に取得
+	ListByReviewID(ctx context.Context, userID, reviewID string) ([]*model.ReviewMessage, error)
+
+	// SetKnowledgeID - メッセージから作成したナレッジを記録（作成済みの場合は model.ErrReviewMessageKnowledgeExists）
+	SetKnowledgeID(ctx context.Context, messageID, knowledgeID string) error
+}
@@ -12,6 +12,9 @@ type ReviewRepository interface {
 	// Create - レビューを作成
 	Create(ctx context.Context, review *model.Review) error
 
+	// CreateAll - 複数のレビューを1つのトランザクションで作成（1件でも失敗した場合はすべて作成しない）
+	CreateAll(ctx context.Context, reviews []*model.Review) error
+
 	// FindByID - IDでレビューを取得
 	FindByID(ctx context.Context, id string) (*model.Review, error)
 
@@ -21,9 +24,33 @@ type ReviewRepository interface {
 	// Update - レビューを更新
 	Update(ctx context.Context, review *model.Review) error
 
-	// Delete - レビューを削除
+	// Delete - レビューを削除（物理削除）
 	Delete(ctx context.Context, id string) error
 
+	// SoftDelete - レビューをゴミ箱に移動（削除済みのレビューは model.ErrReviewNotFound）
+	// 実行待ち・実行中の非同期のレビューを中止した場合は true を返す
+	SoftDelete(ctx context.Context, id string) (bool, error)
+
+	// SoftDeleteWithFilters - フィルター条件に合致するユーザーのレビューをゴミ箱に移動し、件数を返す（フィルターは ListWithFilters と同じ）
+	// 中止した実行待ち・実行中の非同期のレビュー（ID・ユーザー・作成日時のみ）も返す
+	SoftDeleteWithFilters(ctx context.Context, userID string, filters map[string]interface{}) (int, []*model.Review, error)
+
+	// FindDeletedByID - ゴミ箱のレビューをIDで取得（なければ model.ErrReviewNotFound）
+	FindDeletedByID(ctx context.Context, id string) (*model.Review, error)
+
+	// ListDeleted - ユーザーのゴミ箱のレビューを削除日時の新しい順に取得
+	ListDeleted(ctx context.Context, userID string, limit, offset int) ([]*model.Review, error)
+
+	// CountDeleted - ユーザーのゴミ箱のレビューの総数を取得
+	CountDeleted(ctx context.Context, userID string) (int, error)
+
+	// Restore - ゴミ箱のレビューを元に戻す（ゴミ箱にない場合は model.ErrReviewNotFound）
+	Restore(ctx context.Context, id string) error
+
+	// PurgeDeleted - deletedBefore より前にゴミ箱に移動したレビューを完全削除し、件数を返す
+	// 使用量の集計のため行は残し、コード・レビュー結果などの内容・参照ナレッジの記録・会話を消す
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error)
+
 	// FindRecentByUserID - 最近のレビューを取得
 	FindRecentByUserID(ctx context.Context, userID string, limit int) ([]*model.Review, error)
 
@@ -44,4 +71,31 @@ type ReviewRepository interface {
 
 	// GetAverageFeedbackScore - フィードバックスコアの平均を取得
 	GetAverageFeedbackScore(ctx context.Context, userID string) (float64, error)
+
+	// AggregateUsage - 期間内のトークン使用量とコストを集計（コストは発生済みのため削除済みのレビューも含む。完了していない非同期のレビューは含めない）
+	AggregateUsage(ctx context.Context, filter model.UsageFilter) ([]*model.UsageSummary, error)
+
+	// FindCachedReview - キャッシュキーが一致する、since 以降に生成した最新のレビューを取得（なければ model.ErrReviewCacheMiss）
+	FindCachedReview(ctx context.Context, userID, cacheKey string, since time.Time) (*model.Review, error)
+
+	// FindByEnsembleID - 同じアンサンブルで作成したユーザーのレビューを作成順に取得（なければ model.ErrEnsembleNotFound）
+	FindByEnsembleID(ctx context.Context, userID, ensembleID string) ([]*model.Review, error)
+
+	// FindBySessionID - 同じセッションで作成したユーザーのレビューを作成順に取得（なければ空）
+	FindBySessionID(ctx context.Context, userID, sessionID string) ([]*model.Review, error)
+
+	// ClaimNextJob - 最も古い実行待ちの非同期のレビューを実行中にして取得（なければ model.ErrNoPendingReviewJob）
+	// 複数のワーカー・サーバーから同時に呼び出しても、1つのジョブは1か所にしか渡さない
+	ClaimNextJob(ctx context.Context) (*model.Review, error)
+
+	// UpdateJobStatus - 非同期のレビューの状態（status, error_message, attempts, started_at, completed_at）を更新
+	// 保存されている状態が from で実行回数（attempts）が同じ場合のみ更新し、そうでなければ model.ErrReviewJobLost を返す
+	UpdateJobStatus(ctx context.Context, review *model.Review, from string) error
+
+	// CompleteJob - 非同期のレビューにレビュー結果を保存して完了にする
+	// 実行中で実行回数（attempts）が同じ場合のみ保存し、そうでなければ model.ErrReviewJobLost を返す
+	CompleteJob(ctx context.Context, review *model.Review) error
+
+	// RequeueStaleJobs - startedBefore より前に開始して終わっていない実行中のレビューを実行待ちに戻し、件数を返す
+	RequeueStaleJobs(ctx context.Context, startedBefore time.Time) (int, error)
 }
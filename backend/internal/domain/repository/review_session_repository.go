@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// ReviewSessionRepository - 複数ファイルのレビューセッションリポジトリのインターフェース
+type ReviewSessionRepository interface {
+	// Create - セッションを作成（ファイルごとのレビューより先に作成する）
+	Create(ctx context.Context, session *model.ReviewSession) error
+
+	// FindByID - ユーザーのセッションを取得（他のユーザーのセッション・なければ model.ErrReviewSessionNotFound）
+	FindByID(ctx context.Context, userID, sessionID string) (*model.ReviewSession, error)
+
+	// ListByUserID - ユーザーのセッションを新しい順に取得
+	ListByUserID(ctx context.Context, userID string, limit, offset int) ([]*model.ReviewSession, error)
+
+	// CountByUserID - ユーザーのセッション総数を取得
+	CountByUserID(ctx context.Context, userID string) (int, error)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// ApplyImprovements - 選択した改善点の code_before を code_after に置き換えたコードを返す
+//
+//   - code_before の位置は LocateImprovements と同じ方法で探す（複数ある場合は改善点の開始行に最も近い位置）
+//   - code_before がない・見つからない場合は、LocateImprovements で照合した行範囲（start_line〜end_line）がコードの範囲内であれば置き換える
+//     （LocateImprovements は code_before がコードにない場合に code_before を空にするため）
+//   - code_after のインデントは、置き換える1行目のインデントに揃える（相対的なインデントは保つ）
+//   - 同じ行を書き換える改善点は、どちらを優先すべきか判断できないためエラーにする
+//
+// 適用できない改善点（code_after がない、code_before と行範囲のどちらからも位置を特定できない）は適用せずに理由とともに返す。
+// 選択した改善点がすべて適用できない場合はエラーにする。
+// indices は improvements の番号（0始まり。重複しないこと）
+func ApplyImprovements(code string, improvements []model.Improvement, indices []int) (string, []model.ImprovementNotApplied, error) {
+	lines := strings.Split(code, "\n")
+
+	edits := make([]lineEdit, 0, len(indices))
+	var notApplied []model.ImprovementNotApplied
+	for _, index := range indices {
+		if index < 0 || index >= len(improvements) {
+			return "", nil, fmt.Errorf("%w: %d", model.ErrApplyImprovementIndex, index)
+		}
+		imp := improvements[index]
+		if strings.TrimSpace(imp.CodeAfter) == "" {
+			notApplied = append(notApplied, model.ImprovementNotApplied{Index: index, Reason: model.ErrImprovementNoCodeAfter})
+			continue
+		}
+		start, end, ok := locateEdit(lines, imp)
+		if !ok {
+			notApplied = append(notApplied, model.ImprovementNotApplied{Index: index, Reason: model.ErrImprovementNotApplicable})
+			continue
+		}
+		edits = append(edits, lineEdit{
+			index: index,
+			start: start,
+			end:   end,
+			lines: reindentLines(imp.CodeAfter, leadingSpace(lines[start])),
+		})
+	}
+	if len(edits) == 0 && len(notApplied) > 0 {
+		return "", notApplied, fmt.Errorf("%w: %d", notApplied[0].Reason, notApplied[0].Index)
+	}
+
+	// 重なりの確認のため開始行の順に並べ、後ろから置き換える（前の改善点の行番号がずれないように）
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	for i := 1; i < len(edits); i++ {
+		if edits[i].start <= edits[i-1].end {
+			return "", nil, fmt.Errorf("%w: %d, %d", model.ErrImprovementsOverlap, edits[i-1].index, edits[i].index)
+		}
+	}
+	for i := len(edits) - 1; i >= 0; i-- {
+		e := edits[i]
+		replaced := make([]string, 0, len(lines)-(e.end-e.start+1)+len(e.lines))
+		replaced = append(replaced, lines[:e.start]...)
+		replaced = append(replaced, e.lines...)
+		replaced = append(replaced, lines[e.end+1:]...)
+		lines = replaced
+	}
+	return strings.Join(lines, "\n"), notApplied, nil
+}
+
+// locateEdit - 改善点で置き換える行（0始まり、終了行を含む）
+// code_before の位置を優先し、見つからない場合は行範囲がコードの範囲内であればその行
+func locateEdit(lines []string, imp model.Improvement) (int, int, bool) {
+	if strings.TrimSpace(imp.CodeBefore) != "" {
+		if start, end, ok := findLines(lines, imp.CodeBefore, imp.StartLine-1); ok {
+			return start, end, true
+		}
+	}
+	if imp.StartLine < 1 || imp.EndLine < imp.StartLine || imp.EndLine > len(lines) {
+		return 0, 0, false
+	}
+	return imp.StartLine - 1, imp.EndLine - 1, true
+}
+
+// lineEdit - 改善点の適用による行の置き換え（start〜end は0始まり、終了行を含む）
+type lineEdit struct {
+	index      int
+	start, end int
+	lines      []string
+}
+
+// reindentLines - snippet の共通のインデントを indent に置き換えた行（先頭と末尾の空行は除く）
+func reindentLines(snippet, indent string) []string {
+	lines := strings.Split(strings.ReplaceAll(snippet, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	common := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if n := len(leadingSpace(line)); common < 0 || n < common {
+			common = n
+		}
+	}
+
+	reindented := make([]string, len(lines))
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		reindented[i] = indent + strings.TrimRight(line[common:], " \t")
+	}
+	return reindented
+}
+
+// leadingSpace - 行頭の空白（スペース・タブ）
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
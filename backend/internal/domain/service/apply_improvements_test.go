@@ -0,0 +1,76 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyImprovements(t *testing.T) {
+	code := "func Load(path string) []byte {\n\tdata, _ := os.ReadFile(path)\n\treturn data\n}\n\nfunc Save(path string, data []byte) {\n\tos.WriteFile(path, data, 0644)\n}\n"
+	improvements := []model.Improvement{
+		{
+			Title:      "エラーを無視しない",
+			StartLine:  2,
+			EndLine:    3,
+			CodeBefore: "data, _ := os.ReadFile(path)\nreturn data",
+			CodeAfter:  "data, err := os.ReadFile(path)\nif err != nil {\n\treturn nil, err\n}\nreturn data, nil",
+		},
+		{
+			Title:      "書き込みのエラーを返す",
+			StartLine:  7,
+			EndLine:    7,
+			CodeBefore: "os.WriteFile(path, data, 0644)",
+			CodeAfter:  "    return os.WriteFile(path, data, 0o644)",
+		},
+		{Title: "修正後のコードがない", StartLine: 1, EndLine: 1, CodeBefore: "func Load(path string) []byte {"},
+		{Title: "コードにない", CodeBefore: "return nil", CodeAfter: "return data"},
+		{Title: "1つ目と重なる", StartLine: 3, EndLine: 3, CodeBefore: "return data", CodeAfter: "return data, nil"},
+		{Title: "照合で code_before を空にした", StartLine: 7, EndLine: 7, CodeAfter: "return os.WriteFile(path, data, 0o644)"},
+		{Title: "行範囲がコードの範囲外", StartLine: 8, EndLine: 12, CodeAfter: "}"},
+	}
+
+	t.Run("code_before を置き換え、インデントを置き換える行に揃える", func(t *testing.T) {
+		applied, notApplied, err := ApplyImprovements(code, improvements, []int{0, 1})
+		require.NoError(t, err)
+		assert.Empty(t, notApplied)
+
+		assert.Equal(t, "func Load(path string) []byte {\n\tdata, err := os.ReadFile(path)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\treturn data, nil\n}\n\nfunc Save(path string, data []byte) {\n\treturn os.WriteFile(path, data, 0o644)\n}\n", applied)
+	})
+
+	t.Run("code_before がない場合は照合した行範囲を置き換える", func(t *testing.T) {
+		applied, notApplied, err := ApplyImprovements(code, improvements, []int{5})
+		require.NoError(t, err)
+		assert.Empty(t, notApplied)
+
+		assert.Equal(t, "func Load(path string) []byte {\n\tdata, _ := os.ReadFile(path)\n\treturn data\n}\n\nfunc Save(path string, data []byte) {\n\treturn os.WriteFile(path, data, 0o644)\n}\n", applied)
+	})
+
+	t.Run("適用できない改善点は理由とともに返し、残りを適用する", func(t *testing.T) {
+		applied, notApplied, err := ApplyImprovements(code, improvements, []int{1, 2, 3, 6})
+		require.NoError(t, err)
+
+		assert.Equal(t, []model.ImprovementNotApplied{
+			{Index: 2, Reason: model.ErrImprovementNoCodeAfter},
+			{Index: 3, Reason: model.ErrImprovementNotApplicable},
+			{Index: 6, Reason: model.ErrImprovementNotApplicable},
+		}, notApplied)
+		assert.Contains(t, applied, "\treturn os.WriteFile(path, data, 0o644)\n")
+	})
+
+	t.Run("すべて適用できない改善点・重なる改善点・範囲外の番号はエラー", func(t *testing.T) {
+		_, _, err := ApplyImprovements(code, improvements, []int{2})
+		assert.ErrorIs(t, err, model.ErrImprovementNoCodeAfter)
+		_, notApplied, err := ApplyImprovements(code, improvements, []int{3, 6})
+		assert.ErrorIs(t, err, model.ErrImprovementNotApplicable)
+		assert.Len(t, notApplied, 2)
+		_, _, err = ApplyImprovements(code, improvements, []int{0, 4})
+		assert.ErrorIs(t, err, model.ErrImprovementsOverlap)
+		_, _, err = ApplyImprovements(code, improvements, []int{1, 5})
+		assert.ErrorIs(t, err, model.ErrImprovementsOverlap)
+		_, _, err = ApplyImprovements(code, improvements, []int{7})
+		assert.ErrorIs(t, err, model.ErrApplyImprovementIndex)
+	})
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CodeChunk - 大きなファイルを分割したレビュー単位（差分のレビューでは追加・変更したハンク）
+type CodeChunk struct {
+	Index     int    // 0始まりの連番
+	StartLine int    // 元ファイルでの開始行（1始まり）
+	EndLine   int    // 元ファイルでの終了行（1始まり・この行を含む）
+	Code      string // チャンクのコード
+	// 差分のレビューの場合のみ設定（DiffChunks）。StartLine / EndLine は変更後のファイルでの追加した行の範囲
+	FilePath string // 変更後のファイルのパス
+	Section  string // ハンクのヘッダーの関数名など
+	NewCode  string // 変更後のコード（ナレッジ検索のEmbeddingに使う）
+	// NewStartLine - NewCode の1行目の変更後のファイルでの行番号
+	NewStartLine int
+	// FileContext - 変更後のファイル全体、または宣言の一覧（ファイル全体が渡された場合のみ）
+	FileContext string
+}
+
+// EstimateTokens - テキストのトークン数を概算
+// ASCII は約4文字で1トークン、日本語などの非ASCII文字は1文字1トークンとして数える（多めに見積もる）
+func EstimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < 0x80 {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// 宣言の開始行とみなすパターン（行頭の空白を除いた文字列に適用）
+var (
+	goDeclPattern     = regexp.MustCompile(`^(func|type|var|const)\b`)
+	pythonDeclPattern = regexp.MustCompile(`^(async\s+def|def|class)\s`)
+	jsDeclPattern     = regexp.MustCompile(`^(export\s+)?(default\s+)?(async\s+)?(function\b|class\b|interface\b|enum\b|type\s+\w+.*=|(const|let|var)\s+\w+\s*=\s*(async\s*)?(\(|function\b|\w+\s*=>))`)
+	// Java / Kotlin / C# / Rust / Swift / PHP など（型・関数宣言と、修飾子付きのメソッド宣言）
+	genericDeclPattern   = regexp.MustCompile(`^((public|private|protected|internal|static|final|abstract|override|async|export|default|pub(\([a-z]+\))?|open|sealed|data|inline|virtual|unsafe|synchronized)\s+)*(class|interface|enum|struct|record|trait|impl|object|fun|func|fn|function|def|module|namespace)\b`)
+	genericMethodPattern = regexp.MustCompile(`^((public|private|protected|internal|static|final|abstract|override|async|virtual|synchronized)\s+)+[\w<>\[\],.?\s]+\s+\w+\s*\(`)
+)
+
+// isDeclarationLine - 関数・クラスなどの宣言の開始行か
+func isDeclarationLine(language, trimmed string) bool {
+	switch strings.ToLower(language) {
+	case "go", "golang":
+		return goDeclPattern.MatchString(trimmed)
+	case "python", "py":
+		return pythonDeclPattern.MatchString(trimmed)
+	case "javascript", "typescript", "js", "ts", "jsx", "tsx":
+		return jsDeclPattern.MatchString(trimmed)
+	default:
+		return genericDeclPattern.MatchString(trimmed) || genericMethodPattern.MatchString(trimmed)
+	}
+}
+
+// isLeadingCommentLine - 宣言の直前に付くコメント・アノテーション行か（宣言と同じチャンクに含める）
+func isLeadingCommentLine(trimmed string) bool {
+	for _, prefix := range []string{"//", "#", "/*", "*", "@", "--"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitCode - コードをトークン数の上限に収まるチャンクに分割
+//
+// 分割はできるだけ関数・クラスの境界で行う:
+//  1. トップレベル（インデントなし）の宣言で区切り、上限まで詰めてチャンクにする
+//  2. 1つの宣言だけで上限を超える場合は、ネストした宣言（メソッドなど）で区切る
+//  3. それでも超える場合は、空行を優先して行単位で区切る
+//
+// 上限以下のコード、または maxTokens が0以下の場合は1チャンクのまま返す
+func SplitCode(code, language string, maxTokens int) []CodeChunk {
+	lines := strings.Split(code, "\n")
+	if maxTokens <= 0 || EstimateTokens(code) <= maxTokens {
+		return []CodeChunk{{Index: 0, StartLine: 1, EndLine: len(lines), Code: code}}
+	}
+
+	s := &codeSplitter{lines: lines, language: language, maxTokens: maxTokens}
+	s.pack(s.segments(0, len(lines), true))
+	s.flush()
+
+	return s.chunks
+}
+
+// codeSplitter - SplitCode の作業状態
+type codeSplitter struct {
+	lines     []string
+	language  string
+	maxTokens int
+
+	chunks       []CodeChunk
+	currentStart int // 作成中のチャンクの開始行（0始まり）
+	currentEnd   int // 作成中のチャンクの終了行（0始まり・この行を含まない）
+}
+
+// lineRange - 行の範囲 [start, end)（0始まり）
+type lineRange struct {
+	start, end int
+}
+
+// segments - [start, end) を宣言の境界で区切る（topLevel=true の場合はインデントなしの宣言のみ）
+func (s *codeSplitter) segments(start, end int, topLevel bool) []lineRange {
+	var boundaries []int
+	for i := start; i < end; i++ {
+		line := s.lines[i]
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if topLevel && len(trimmed) != len(line) {
+			continue
+		}
+		if !isDeclarationLine(s.language, trimmed) {
+			continue
+		}
+
+		// 直前のコメント・アノテーションを宣言側に含める
+		boundary := i
+		lowest := start
+		if len(boundaries) > 0 {
+			lowest = boundaries[len(boundaries)-1] + 1
+		}
+		for boundary > lowest && isLeadingCommentLine(strings.TrimSpace(s.lines[boundary-1])) {
+			boundary--
+		}
+		if boundary > start && (len(boundaries) == 0 || boundary > boundaries[len(boundaries)-1]) {
+			boundaries = append(boundaries, boundary)
+		}
+	}
+
+	ranges := make([]lineRange, 0, len(boundaries)+1)
+	prev := start
+	for _, b := range boundaries {
+		ranges = append(ranges, lineRange{start: prev, end: b})
+		prev = b
+	}
+	return append(ranges, lineRange{start: prev, end: end})
+}
+
+// pack - 区切った範囲を上限まで詰めてチャンクにする
+func (s *codeSplitter) pack(ranges []lineRange) {
+	for _, r := range ranges {
+		if s.tokens(r.start, r.end) > s.maxTokens {
+			// 単独で上限を超える範囲は、作成中のチャンクを確定してからさらに細かく分割
+			s.flush()
+			s.splitOversized(r)
+			continue
+		}
+		if s.currentEnd > s.currentStart && s.tokens(s.currentStart, r.end) > s.maxTokens {
+			s.flush()
+		}
+		if s.currentEnd == s.currentStart {
+			s.currentStart = r.start
+		}
+		s.currentEnd = r.end
+	}
+}
+
+// splitOversized - 上限を超える範囲をネストした宣言、または行単位で分割
+func (s *codeSplitter) splitOversized(r lineRange) {
+	nested := s.segments(r.start, r.end, false)
+	if len(nested) > 1 {
+		s.pack(nested)
+		return
+	}
+
+	// 宣言で区切れない場合は行単位（できるだけ空行の位置で区切る）
+	start := r.start
+	for start < r.end {
+		end := start + 1
+		for end < r.end && s.tokens(start, end+1) <= s.maxTokens {
+			end++
+		}
+		if end < r.end {
+			for i := end - 1; i > start+(end-start)/2; i-- {
+				if strings.TrimSpace(s.lines[i]) == "" {
+					end = i + 1
+					break
+				}
+			}
+		}
+		s.currentStart, s.currentEnd = start, end
+		s.flush()
+		start = end
+	}
+}
+
+// flush - 作成中のチャンクを確定
+func (s *codeSplitter) flush() {
+	if s.currentEnd <= s.currentStart {
+		return
+	}
+	s.chunks = append(s.chunks, CodeChunk{
+		Index:     len(s.chunks),
+		StartLine: s.currentStart + 1,
+		EndLine:   s.currentEnd,
+		Code:      strings.Join(s.lines[s.currentStart:s.currentEnd], "\n"),
+	})
+	s.currentStart = s.currentEnd
+}
+
+// tokens - [start, end) の行のトークン数を概算
+func (s *codeSplitter) tokens(start, end int) int {
+	return EstimateTokens(strings.Join(s.lines[start:end], "\n"))
+}
+
+// 宣言の一覧で1行に含める最大文字数
+const outlineLineMaxRunes = 120
+
+// OutlineCode - 関数・クラスなどの宣言の行を出現順に返す（最大 maxLines 行。0以下は無制限）
+// 複数ファイルのレビューで、他のファイルに定義されている識別子を伝えるために使う
+// 宣言の本体（"{" 以降）は含めず、長い行は省略する
+func OutlineCode(code, language string, maxLines int) []string {
+	var outline []string
+	for _, line := range strings.Split(code, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || !isDeclarationLine(language, trimmed) {
+			continue
+		}
+		decl := strings.TrimSpace(strings.TrimSuffix(trimmed, "{"))
+		if runes := []rune(decl); len(runes) > outlineLineMaxRunes {
+			decl = string(runes[:outlineLineMaxRunes]) + "…"
+		}
+		outline = append(outline, decl)
+		if maxLines > 0 && len(outline) >= maxLines {
+			break
+		}
+	}
+	return outline
+}
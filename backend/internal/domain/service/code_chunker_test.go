@@ -0,0 +1,154 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, EstimateTokens(""))
+	assert.Equal(t, 1, EstimateTokens("func"))
+	assert.Equal(t, 2, EstimateTokens("func("))
+	// 非ASCII文字は1文字1トークン
+	assert.Equal(t, 3, EstimateTokens("エラー"))
+}
+
+// goFunctions - 指定した数の関数（コメント付き）を並べたGoコードを生成
+func goFunctions(n, bodyLines int) string {
+	var b strings.Builder
+	b.WriteString("package sample\n\nimport \"fmt\"\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "\n// Func%d - サンプル\nfunc Func%d() {\n", i, i)
+		for j := 0; j < bodyLines; j++ {
+			fmt.Fprintf(&b, "\tfmt.Println(\"line %d of func %d\")\n", j, i)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func TestSplitCode_UnderLimit(t *testing.T) {
+	code := goFunctions(3, 2)
+
+	chunks := SplitCode(code, "go", 10000)
+
+	require.Len(t, chunks, 1)
+	assert.Equal(t, code, chunks[0].Code)
+	assert.Equal(t, 1, chunks[0].StartLine)
+}
+
+func TestSplitCode_FunctionBoundaries(t *testing.T) {
+	code := goFunctions(40, 10)
+	lines := strings.Split(code, "\n")
+
+	chunks := SplitCode(code, "go", 500)
+
+	require.Greater(t, len(chunks), 1)
+	for i, chunk := range chunks {
+		assert.Equal(t, i, chunk.Index)
+		assert.LessOrEqual(t, EstimateTokens(chunk.Code), 500)
+		// 各チャンクは関数の直前のコメントから始まる（先頭チャンクは package から）
+		if i == 0 {
+			assert.True(t, strings.HasPrefix(chunk.Code, "package sample"))
+		} else {
+			assert.True(t, strings.HasPrefix(chunk.Code, "// Func"), "chunk %d starts with %q", i, lines[chunk.StartLine-1])
+		}
+		// 行番号とコードが一致する
+		assert.Equal(t, strings.Join(lines[chunk.StartLine-1:chunk.EndLine], "\n"), chunk.Code)
+	}
+
+	// チャンクをつなげると元のコードに戻る
+	joined := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		joined[i] = chunk.Code
+	}
+	assert.Equal(t, code, strings.Join(joined, "\n"))
+}
+
+func TestSplitCode_OversizedClassSplitsOnMethods(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("public class Service {\n")
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&b, "    @Override\n    public void method%d() {\n", i)
+		for j := 0; j < 8; j++ {
+			fmt.Fprintf(&b, "        System.out.println(\"method %d line %d\");\n", i, j)
+		}
+		b.WriteString("    }\n")
+	}
+	b.WriteString("}\n")
+	code := b.String()
+
+	chunks := SplitCode(code, "java", 400)
+
+	require.Greater(t, len(chunks), 1)
+	for i, chunk := range chunks[1:] {
+		assert.LessOrEqual(t, EstimateTokens(chunk.Code), 400)
+		// アノテーションはメソッドと同じチャンクに含まれる
+		assert.True(t, strings.HasPrefix(strings.TrimSpace(chunk.Code), "@Override"), "chunk %d: %q", i+1, chunk.Code[:40])
+	}
+}
+
+func TestSplitCode_NoDeclarationsFallsBackToLines(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(&b, "value_%d = compute(%d)\n", i, i)
+	}
+	code := b.String()
+
+	chunks := SplitCode(code, "python", 300)
+
+	require.Greater(t, len(chunks), 1)
+	total := 0
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, EstimateTokens(chunk.Code), 300)
+		total += chunk.EndLine - chunk.StartLine + 1
+	}
+	assert.Equal(t, len(strings.Split(code, "\n")), total)
+}
+
+func TestOutlineCode(t *testing.T) {
+	code := `package user
+
+import "errors"
+
+// User - ユーザー
+type User struct {
+	ID string
+}
+
+func (u *User) Validate() error {
+	if u.ID == "" {
+		return errors.New("id is required")
+	}
+	return nil
+}
+
+func NewUser(id string) *User {
+	return &User{ID: id}
+}
+`
+
+	t.Run("宣言の行を出現順に返す", func(t *testing.T) {
+		outline := OutlineCode(code, "Go", 0)
+
+		assert.Equal(t, []string{
+			"type User struct",
+			"func (u *User) Validate() error",
+			"func NewUser(id string) *User",
+		}, outline)
+	})
+
+	t.Run("最大行数で打ち切る", func(t *testing.T) {
+		outline := OutlineCode(code, "Go", 2)
+
+		assert.Len(t, outline, 2)
+	})
+
+	t.Run("宣言がない場合は空", func(t *testing.T) {
+		assert.Empty(t, OutlineCode("x = 1\ny = 2\n", "Python", 0))
+	})
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// DiffFile - unified diff の1ファイル分の変更
+type DiffFile struct {
+	OldPath string // 変更前のパス（新規ファイルの場合は空）
+	NewPath string // 変更後のパス（削除したファイルの場合は空）
+	Hunks   []DiffHunk
+	Binary  bool // バイナリファイル（ハンクなし）
+}
+
+// Path - ファイルのパス（変更後、削除したファイルは変更前）
+func (f DiffFile) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// DiffHunk - 変更のまとまり（"@@ -a,b +c,d @@" から次のハンクまで）
+type DiffHunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Section  string // "@@" の後ろの関数名など（ない場合は空）
+	Lines    []DiffLine
+}
+
+// 差分の行の種類
+const (
+	DiffLineContext = ' '
+	DiffLineAdded   = '+'
+	DiffLineDeleted = '-'
+)
+
+// DiffLine - ハンクの1行
+type DiffLine struct {
+	Kind    byte   // DiffLineContext / DiffLineAdded / DiffLineDeleted
+	Text    string // 先頭の記号を除いた内容
+	NewLine int    // 変更後のファイルでの行番号（削除した行は0）
+}
+
+// ChangedRange - 追加した行の変更後のファイルでの範囲（追加した行がない場合は 0, 0）
+func (h DiffHunk) ChangedRange() (start, end int) {
+	for _, line := range h.Lines {
+		if line.Kind != DiffLineAdded {
+			continue
+		}
+		if start == 0 {
+			start = line.NewLine
+		}
+		end = line.NewLine
+	}
+	return start, end
+}
+
+// NewStartLine - 変更後のコード（NewCode）の1行目の変更後のファイルでの行番号
+func (h DiffHunk) NewStartLine() int {
+	for _, line := range h.Lines {
+		if line.Kind != DiffLineDeleted {
+			return line.NewLine
+		}
+	}
+	return 0
+}
+
+// NewCode - 変更後のコード（前後の行と追加した行）
+func (h DiffHunk) NewCode() string {
+	var lines []string
+	for _, line := range h.Lines {
+		if line.Kind != DiffLineDeleted {
+			lines = append(lines, line.Text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var (
+	diffGitHeaderPattern  = regexp.MustCompile(`^diff --git a/(.+?) b/(.+)$`)
+	diffHunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$`)
+)
+
+// ParseUnifiedDiff - unified diff（git diff / diff -u の出力）をファイルとハンクに分解する
+// ハンクの行数がヘッダーと一致しない場合、ハンクが1つもない場合は model.ErrDiffInvalid を返す
+func ParseUnifiedDiff(diff string) ([]DiffFile, error) {
+	lines := strings.Split(strings.ReplaceAll(diff, "\r\n", "\n"), "\n")
+
+	var files []DiffFile
+	var current *DiffFile
+	startFile := func() {
+		files = append(files, DiffFile{})
+		current = &files[len(files)-1]
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			startFile()
+			if m := diffGitHeaderPattern.FindStringSubmatch(line); m != nil {
+				current.OldPath, current.NewPath = m[1], m[2]
+			}
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			// "diff --git" のない差分（diff -u）はここからファイルが始まる
+			if current == nil || len(current.Hunks) > 0 {
+				startFile()
+			}
+			current.OldPath = diffPath(strings.TrimPrefix(line, "--- "), "a/")
+			current.NewPath = diffPath(strings.TrimPrefix(lines[i+1], "+++ "), "b/")
+			i++
+		case strings.HasPrefix(line, "new file mode"):
+			if current != nil {
+				current.OldPath = ""
+			}
+		case strings.HasPrefix(line, "deleted file mode"):
+			if current != nil {
+				current.NewPath = ""
+			}
+		case strings.HasPrefix(line, "rename from "):
+			if current != nil {
+				current.OldPath = strings.TrimPrefix(line, "rename from ")
+			}
+		case strings.HasPrefix(line, "rename to "):
+			if current != nil {
+				current.NewPath = strings.TrimPrefix(line, "rename to ")
+			}
+		case strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch":
+			if current != nil {
+				current.Binary = true
+			}
+		case strings.HasPrefix(line, "@@"):
+			if current == nil {
+				return nil, fmt.Errorf("%w: hunk without file header at line %d", model.ErrDiffInvalid, i+1)
+			}
+			hunk, next, err := parseDiffHunk(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			current.Hunks = append(current.Hunks, hunk)
+			i = next - 1
+		}
+	}
+
+	hunks := 0
+	for _, f := range files {
+		hunks += len(f.Hunks)
+	}
+	if hunks == 0 {
+		return nil, fmt.Errorf("%w: no hunks found", model.ErrDiffInvalid)
+	}
+	return files, nil
+}
+
+// parseDiffHunk - lines[start] のヘッダーからハンクを読み取り、次の行の位置を返す
+func parseDiffHunk(lines []string, start int) (DiffHunk, int, error) {
+	m := diffHunkHeaderPattern.FindStringSubmatch(lines[start])
+	if m == nil {
+		return DiffHunk{}, 0, fmt.Errorf("%w: invalid hunk header at line %d", model.ErrDiffInvalid, start+1)
+	}
+	hunk := DiffHunk{
+		OldStart: atoiOr(m[1], 0),
+		OldLines: atoiOr(m[2], 1),
+		NewStart: atoiOr(m[3], 0),
+		NewLines: atoiOr(m[4], 1),
+		Section:  strings.TrimSpace(m[5]),
+	}
+
+	oldLeft, newLeft := hunk.OldLines, hunk.NewLines
+	newLine := hunk.NewStart
+	i := start + 1
+	for ; i < len(lines) && (oldLeft > 0 || newLeft > 0); i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, `\`) {
+			// "\ No newline at end of file"
+			continue
+		}
+		kind := byte(DiffLineContext)
+		text := line
+		if line != "" {
+			kind, text = line[0], line[1:]
+		}
+		switch kind {
+		case DiffLineContext:
+			hunk.Lines = append(hunk.Lines, DiffLine{Kind: kind, Text: text, NewLine: newLine})
+			newLine++
+			oldLeft--
+			newLeft--
+		case DiffLineAdded:
+			hunk.Lines = append(hunk.Lines, DiffLine{Kind: kind, Text: text, NewLine: newLine})
+			newLine++
+			newLeft--
+		case DiffLineDeleted:
+			hunk.Lines = append(hunk.Lines, DiffLine{Kind: kind, Text: text})
+			oldLeft--
+		default:
+			return DiffHunk{}, 0, fmt.Errorf("%w: unexpected line in hunk at line %d", model.ErrDiffInvalid, i+1)
+		}
+	}
+	if oldLeft > 0 || newLeft > 0 || oldLeft < 0 || newLeft < 0 {
+		return DiffHunk{}, 0, fmt.Errorf("%w: hunk at line %d does not match its header", model.ErrDiffInvalid, start+1)
+	}
+	// 末尾の "\ No newline at end of file"
+	for i < len(lines) && strings.HasPrefix(lines[i], `\`) {
+		i++
+	}
+	return hunk, i, nil
+}
+
+// diffPath - "--- a/path" / "+++ b/path" のパス（/dev/null は空）
+func diffPath(value, prefix string) string {
+	// タイムスタンプ（diff -u）を除く
+	if tab := strings.Index(value, "\t"); tab >= 0 {
+		value = value[:tab]
+	}
+	value = strings.TrimSpace(value)
+	if value == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(value, prefix)
+}
+
+// atoiOr - 数値に変換（空の場合は def）
+func atoiOr(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// 変更後のファイル全体の代わりに含める宣言の最大行数（ファイル全体がトークン数の上限を超える場合）
+const diffOutlineMaxLines = 30
+
+// DiffChunks - 追加・変更したハンクをレビュー単位（CodeChunk）にする
+//
+//   - 追加した行がないハンク（削除のみ）・削除したファイル・バイナリファイルはレビューしない
+//   - Code は変更後の行番号を付けたハンク、StartLine / EndLine は追加した行の範囲
+//   - NewCode はナレッジ検索のEmbeddingに使う変更後のコード
+//   - contextFiles（パス → 変更後のファイル全体）にあるファイルは FileContext に含める
+//     ハンクと合わせて maxTokens を超える場合は、ファイル全体の代わりに宣言の一覧にする
+//   - 1つのハンクが maxTokens を超える場合は行単位で分割する（maxTokens が0以下の場合は分割しない）
+func DiffChunks(files []DiffFile, contextFiles map[string]string, language string, maxTokens int) []CodeChunk {
+	var chunks []CodeChunk
+	for _, f := range files {
+		if f.NewPath == "" || f.Binary {
+			continue
+		}
+		path := model.NormalizeFilePath(f.NewPath)
+		fileCode, hasFile := contextFiles[path]
+		for _, hunk := range f.Hunks {
+			for _, part := range splitDiffHunk(hunk, maxTokens) {
+				start, end := part.ChangedRange()
+				if start == 0 {
+					continue
+				}
+				chunk := CodeChunk{
+					Index:        len(chunks),
+					StartLine:    start,
+					EndLine:      end,
+					Code:         RenderDiffHunk(part),
+					FilePath:     path,
+					Section:      hunk.Section,
+					NewCode:      part.NewCode(),
+					NewStartLine: part.NewStartLine(),
+				}
+				if hasFile {
+					chunk.FileContext = fileContext(fileCode, chunk.Code, language, maxTokens)
+				}
+				chunks = append(chunks, chunk)
+			}
+		}
+	}
+	return chunks
+}
+
+// fileContext - ハンクと合わせて上限に収まればファイル全体、収まらなければ宣言の一覧
+func fileContext(fileCode, hunkCode, language string, maxTokens int) string {
+	if maxTokens <= 0 || EstimateTokens(fileCode)+EstimateTokens(hunkCode) <= maxTokens {
+		return fileCode
+	}
+	return strings.Join(OutlineCode(fileCode, language, diffOutlineMaxLines), "\n")
+}
+
+// splitDiffHunk - トークン数の上限を超えるハンクを行単位で分割
+func splitDiffHunk(hunk DiffHunk, maxTokens int) []DiffHunk {
+	if maxTokens <= 0 || EstimateTokens(RenderDiffHunk(hunk)) <= maxTokens {
+		return []DiffHunk{hunk}
+	}
+
+	var parts []DiffHunk
+	part := DiffHunk{Section: hunk.Section}
+	tokens := 0
+	for _, line := range hunk.Lines {
+		lineTokens := EstimateTokens(renderDiffLine(line)) + 1
+		if len(part.Lines) > 0 && tokens+lineTokens > maxTokens {
+			parts = append(parts, part)
+			part = DiffHunk{Section: hunk.Section}
+			tokens = 0
+		}
+		part.Lines = append(part.Lines, line)
+		tokens += lineTokens
+	}
+	return append(parts, part)
+}
+
+// RenderDiffHunk - LLMに渡すハンク（各行に記号と変更後の行番号を付ける。削除した行は行番号なし）
+func RenderDiffHunk(hunk DiffHunk) string {
+	lines := make([]string, len(hunk.Lines))
+	for i, line := range hunk.Lines {
+		lines[i] = renderDiffLine(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderDiffLine - "+   12 | code" の形式
+func renderDiffLine(line DiffLine) string {
+	number := ""
+	if line.NewLine > 0 {
+		number = strconv.Itoa(line.NewLine)
+	}
+	return fmt.Sprintf("%c %5s | %s", line.Kind, number, line.Text)
+}
+
+// AnchorImprovements - 差分のチャンクのレビュー結果の改善点に、ファイルを設定したコピーを返す
+// 行範囲を特定できなかった改善点（LocateImprovements で0になったもの）はハンクの追加した行の範囲にする
+// チャンクが差分でない場合はそのまま返す
+func AnchorImprovements(result *model.StructuredReviewResult, chunk CodeChunk) *model.StructuredReviewResult {
+	if result == nil || chunk.FilePath == "" {
+		return result
+	}
+	anchored := *result
+	anchored.Improvements = make([]model.Improvement, len(result.Improvements))
+	for i, imp := range result.Improvements {
+		imp.FilePath = chunk.FilePath
+		if imp.StartLine == 0 {
+			imp.StartLine, imp.EndLine = chunk.StartLine, chunk.EndLine
+		}
+		anchored.Improvements[i] = imp
+	}
+	return &anchored
+}
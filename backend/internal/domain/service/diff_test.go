@@ -0,0 +1,129 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleDiff = `diff --git a/internal/user/service.go b/internal/user/service.go
+index 3b18e51..a9c2f04 100644
+--- a/internal/user/service.go
++++ b/internal/user/service.go
+@@ -10,6 +10,10 @@ func (s *Service) Find(id string) (*User, error) {
+ 	if id == "" {
+ 		return nil, ErrInvalidID
+ 	}
+-	return s.repo.Find(id)
++	user, err := s.repo.Find(id)
++	if err != nil {
++		return nil, err
++	}
++	return user, nil
+ }
+
+@@ -40,3 +44,2 @@ func (s *Service) Delete(id string) error {
+ 	return s.repo.Delete(id)
+-	// TODO
+ }
+diff --git a/docs/logo.png b/docs/logo.png
+Binary files a/docs/logo.png and b/docs/logo.png differ
+diff --git a/internal/user/legacy.go b/internal/user/legacy.go
+deleted file mode 100644
+--- a/internal/user/legacy.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package user
+-
+`
+
+func TestParseUnifiedDiff(t *testing.T) {
+	files, err := ParseUnifiedDiff(sampleDiff)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+
+	service := files[0]
+	assert.Equal(t, "internal/user/service.go", service.Path())
+	require.Len(t, service.Hunks, 2)
+	hunk := service.Hunks[0]
+	assert.Equal(t, 10, hunk.NewStart)
+	assert.Equal(t, 10, hunk.NewLines)
+	assert.Equal(t, "func (s *Service) Find(id string) (*User, error) {", hunk.Section)
+	start, end := hunk.ChangedRange()
+	assert.Equal(t, 13, start)
+	assert.Equal(t, 17, end)
+	assert.NotContains(t, hunk.NewCode(), "return s.repo.Find(id)")
+
+	assert.True(t, files[1].Binary)
+	assert.Equal(t, "", files[2].NewPath)
+	assert.Equal(t, "internal/user/legacy.go", files[2].Path())
+}
+
+func TestParseUnifiedDiff_Invalid(t *testing.T) {
+	_, err := ParseUnifiedDiff("func main() {}")
+	assert.ErrorIs(t, err, model.ErrDiffInvalid)
+
+	// ハンクの行数がヘッダーと一致しない
+	_, err = ParseUnifiedDiff("--- a/main.go\n+++ b/main.go\n@@ -1,3 +1,3 @@\n package main\n")
+	assert.ErrorIs(t, err, model.ErrDiffInvalid)
+}
+
+func TestDiffChunks(t *testing.T) {
+	files, err := ParseUnifiedDiff(sampleDiff)
+	require.NoError(t, err)
+	fileCode := "package user\n\nfunc (s *Service) Find(id string) (*User, error) {\n}\n"
+
+	chunks := DiffChunks(files, map[string]string{"internal/user/service.go": fileCode}, "Go", 10000)
+
+	// 削除のみのハンク・バイナリファイル・削除したファイルはレビューしない
+	require.Len(t, chunks, 1)
+	chunk := chunks[0]
+	assert.Equal(t, "internal/user/service.go", chunk.FilePath)
+	assert.Equal(t, 13, chunk.StartLine)
+	assert.Equal(t, 17, chunk.EndLine)
+	assert.Contains(t, chunk.Code, "+    13 | \tuser, err := s.repo.Find(id)")
+	assert.Contains(t, chunk.Code, "-       | \treturn s.repo.Find(id)")
+	assert.Contains(t, chunk.NewCode, "user, err := s.repo.Find(id)")
+	assert.Equal(t, fileCode, chunk.FileContext)
+}
+
+func TestDiffChunks_SplitLargeHunk(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("--- a/main.go\n+++ b/main.go\n@@ -0,0 +1,40 @@\n")
+	for i := 0; i < 40; i++ {
+		b.WriteString("+\tfmt.Println(\"a fairly long line of generated code\")\n")
+	}
+	files, err := ParseUnifiedDiff(b.String())
+	require.NoError(t, err)
+
+	chunks := DiffChunks(files, nil, "Go", 200)
+
+	require.Greater(t, len(chunks), 1)
+	assert.Equal(t, 1, chunks[0].StartLine)
+	assert.Equal(t, 40, chunks[len(chunks)-1].EndLine)
+	for i := 1; i < len(chunks); i++ {
+		assert.Equal(t, chunks[i-1].EndLine+1, chunks[i].StartLine)
+	}
+}
+
+func TestAnchorImprovements(t *testing.T) {
+	result := &model.StructuredReviewResult{
+		Summary:      "エラーを返すようになりました",
+		Improvements: []model.Improvement{{Title: "エラーをラップする", Severity: "medium"}},
+	}
+	chunk := CodeChunk{FilePath: "internal/user/service.go", StartLine: 13, EndLine: 17}
+
+	anchored := AnchorImprovements(result, chunk)
+
+	require.Len(t, anchored.Improvements, 1)
+	assert.Equal(t, "internal/user/service.go", anchored.Improvements[0].FilePath)
+	assert.Equal(t, 13, anchored.Improvements[0].StartLine)
+	assert.Equal(t, 17, anchored.Improvements[0].EndLine)
+	// 元の結果は変更しない
+	assert.Empty(t, result.Improvements[0].FilePath)
+	// 差分でないチャンクはそのまま
+	assert.Same(t, result, AnchorImprovements(result, CodeChunk{}))
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// errorReturningFuncs - 戻り値のエラーを確認せずに呼び出すと指摘する標準ライブラリの関数
+var errorReturningFuncs = map[string]bool{
+	"os.Chdir":            true,
+	"os.Chmod":            true,
+	"os.Mkdir":            true,
+	"os.MkdirAll":         true,
+	"os.Remove":           true,
+	"os.RemoveAll":        true,
+	"os.Rename":           true,
+	"os.Setenv":           true,
+	"os.Unsetenv":         true,
+	"os.WriteFile":        true,
+	"io.Copy":             true,
+	"io.WriteString":      true,
+	"json.Unmarshal":      true,
+	"xml.Unmarshal":       true,
+	"filepath.Walk":       true,
+	"filepath.WalkDir":    true,
+	"http.ListenAndServe": true,
+}
+
+// errorReturningMethods - 戻り値のエラーを確認せずに呼び出すと指摘するメソッド名
+// 型情報がないため名前で判定する。bytes.Buffer・strings.Builder の Write のようにエラーを返さない実装が多いものは含めない
+var errorReturningMethods = map[string]bool{
+	"Close":       true,
+	"Commit":      true,
+	"Decode":      true,
+	"Encode":      true,
+	"Exec":        true,
+	"ExecContext": true,
+	"Execute":     true,
+	"Flush":       true,
+	"Ping":        true,
+	"PingContext": true,
+	"Rollback":    true,
+	"Scan":        true,
+	"Shutdown":    true,
+	"Sync":        true,
+}
+
+// AnalyzeGoSource - Goのコードを go/parser・go/ast で解析し、構文エラー・捨てている戻り値・
+// しきい値を超える関数の行数・ネストの深さ・循環的複雑度を返す（LLM を呼び出す前の事実の計算）
+// パッケージ宣言のないコードの一部も FormatGoSource と同じく解析する。構文エラーがある場合は構文エラーのみ返す
+// 型情報を使わないため、捨てている戻り値がエラーかどうかは判断しない（レビューで判断する）
+func AnalyzeGoSource(src string) *model.StaticAnalysis {
+	analysis := &model.StaticAnalysis{
+		Findings:  []model.AnalysisFinding{},
+		Functions: []model.FunctionMetrics{},
+	}
+
+	source, err := parseGoSource(src)
+	if err != nil {
+		for _, e := range goSyntaxErrorList(err) {
+			analysis.Findings = append(analysis.Findings, model.AnalysisFinding{
+				Rule:      model.AnalysisRuleSyntaxError,
+				StartLine: e.Pos.Line,
+				EndLine:   e.Pos.Line,
+				Message:   "構文エラー: " + e.Msg,
+			})
+		}
+		return analysis
+	}
+
+	for _, decl := range source.file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		// 文の並びを囲んだ関数は元のコードにないため、計測せずに捨てている戻り値のみ検出する
+		name := ""
+		if !source.statements {
+			metrics := measureGoFunction(source.fset, fn)
+			analysis.Functions = append(analysis.Functions, metrics)
+			analysis.Findings = append(analysis.Findings, functionFindings(source.fset, fn, metrics)...)
+			name = metrics.Name
+		}
+		analysis.Findings = append(analysis.Findings, ignoredErrors(source.fset, fn.Body, name)...)
+	}
+
+	sort.SliceStable(analysis.Findings, func(i, j int) bool {
+		return analysis.Findings[i].StartLine < analysis.Findings[j].StartLine
+	})
+	return analysis
+}
+
+// measureGoFunction - 関数の行数・ネストの最大の深さ・循環的複雑度を計測する
+func measureGoFunction(fset *token.FileSet, fn *ast.FuncDecl) model.FunctionMetrics {
+	start := fset.Position(fn.Pos()).Line
+	end := fset.Position(fn.End()).Line
+	depth, _ := maxNesting(fn.Body)
+	return model.FunctionMetrics{
+		Name:       goFuncName(fn),
+		StartLine:  start,
+		EndLine:    end,
+		Lines:      end - start + 1,
+		MaxNesting: depth,
+		Complexity: cyclomaticComplexity(fn.Body),
+	}
+}
+
+// functionFindings - 計測値がしきい値を超える関数の指摘
+// ネストの深さは最も深いブロックの行範囲、それ以外は関数の行範囲を指す
+func functionFindings(fset *token.FileSet, fn *ast.FuncDecl, metrics model.FunctionMetrics) []model.AnalysisFinding {
+	var findings []model.AnalysisFinding
+	if metrics.Lines > model.MaxFunctionLines {
+		findings = append(findings, model.AnalysisFinding{
+			Rule:      model.AnalysisRuleLongFunction,
+			StartLine: metrics.StartLine,
+			EndLine:   metrics.EndLine,
+			Function:  metrics.Name,
+			Message:   fmt.Sprintf("関数 %s は %d 行です（しきい値 %d 行）", metrics.Name, metrics.Lines, model.MaxFunctionLines),
+			Value:     metrics.Lines,
+			Threshold: model.MaxFunctionLines,
+		})
+	}
+	if metrics.MaxNesting > model.MaxNestingDepth {
+		_, deepest := maxNesting(fn.Body)
+		findings = append(findings, model.AnalysisFinding{
+			Rule:      model.AnalysisRuleDeepNesting,
+			StartLine: fset.Position(deepest.Pos()).Line,
+			EndLine:   fset.Position(deepest.End()).Line,
+			Function:  metrics.Name,
+			Message:   fmt.Sprintf("関数 %s のネストの深さが %d です（しきい値 %d）", metrics.Name, metrics.MaxNesting, model.MaxNestingDepth),
+			Value:     metrics.MaxNesting,
+			Threshold: model.MaxNestingDepth,
+		})
+	}
+	if metrics.Complexity > model.MaxCyclomaticComplexity {
+		findings = append(findings, model.AnalysisFinding{
+			Rule:      model.AnalysisRuleHighComplexity,
+			StartLine: metrics.StartLine,
+			EndLine:   metrics.EndLine,
+			Function:  metrics.Name,
+			Message:   fmt.Sprintf("関数 %s の循環的複雑度が %d です（しきい値 %d）", metrics.Name, metrics.Complexity, model.MaxCyclomaticComplexity),
+			Value:     metrics.Complexity,
+			Threshold: model.MaxCyclomaticComplexity,
+		})
+	}
+	return findings
+}
+
+// goFuncName - 関数名（メソッドは Type.Method）
+func goFuncName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	switch t := recv.(type) {
+	case *ast.IndexExpr:
+		recv = t.X
+	case *ast.IndexListExpr:
+		recv = t.X
+	}
+	return types.ExprString(recv) + "." + fn.Name.Name
+}
+
+// maxNesting - 関数の本体からのネストの最大の深さと、最も深いブロック
+// if / for / range / switch / select / 関数リテラルを1段と数え、else if は if と同じ深さとする
+func maxNesting(body *ast.BlockStmt) (int, ast.Node) {
+	depth, maxDepth := 0, 0
+	var deepest ast.Node = body
+	elseIfs := map[*ast.IfStmt]bool{}
+	var nested []bool
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			if nested[len(nested)-1] {
+				depth--
+			}
+			nested = nested[:len(nested)-1]
+			return true
+		}
+
+		nests := false
+		switch s := n.(type) {
+		case *ast.IfStmt:
+			if elseIf, ok := s.Else.(*ast.IfStmt); ok {
+				elseIfs[elseIf] = true
+			}
+			nests = !elseIfs[s]
+		case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt, *ast.FuncLit:
+			nests = true
+		}
+		if nests {
+			depth++
+			if depth > maxDepth {
+				maxDepth, deepest = depth, n
+			}
+		}
+		nested = append(nested, nests)
+		return true
+	})
+	return maxDepth, deepest
+}
+
+// cyclomaticComplexity - 循環的複雑度（1 + if・for・range・case・select の case・&&・|| の数）
+// 関数リテラルの分岐も、それを含む関数の分岐として数える
+func cyclomaticComplexity(body *ast.BlockStmt) int {
+	complexity := 1
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if s.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if s.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if s.Op == token.LAND || s.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
+
+// ignoredErrors - 戻り値を捨てている呼び出しを検出する
+//   - 呼び出しの最後の戻り値を _ に代入している（data, _ := os.ReadFile(path) / _ = f.Close()）
+//   - エラーを返す標準ライブラリの関数・メソッドの呼び出しを文として書いている（os.Remove(path) / f.Close()）
+//
+// defer・go の呼び出しは、慣習として戻り値を捨てることが多いため対象外
+func ignoredErrors(fset *token.FileSet, body *ast.BlockStmt, function string) []model.AnalysisFinding {
+	var findings []model.AnalysisFinding
+	add := func(node ast.Node, call *ast.CallExpr, format string) {
+		callee := types.ExprString(call.Fun)
+		findings = append(findings, model.AnalysisFinding{
+			Rule:      model.AnalysisRuleIgnoredError,
+			StartLine: fset.Position(node.Pos()).Line,
+			EndLine:   fset.Position(node.End()).Line,
+			Function:  function,
+			Call:      callee,
+			Message:   fmt.Sprintf(format, callee),
+		})
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.DeferStmt, *ast.GoStmt:
+			return false
+		case *ast.AssignStmt:
+			if len(s.Rhs) != 1 {
+				return true
+			}
+			call, ok := ast.Unparen(s.Rhs[0]).(*ast.CallExpr)
+			if ok && isBlank(s.Lhs[len(s.Lhs)-1]) {
+				add(s, call, "%s の最後の戻り値を _ で捨てています")
+			}
+		case *ast.ExprStmt:
+			call, ok := ast.Unparen(s.X).(*ast.CallExpr)
+			if ok && returnsError(call) {
+				add(s, call, "%s の戻り値（エラー）を確認していません")
+			}
+		}
+		return true
+	})
+	return findings
+}
+
+// isBlank - ブランク識別子 _ か
+func isBlank(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "_"
+}
+
+// returnsError - エラーを返す標準ライブラリの関数・メソッドの呼び出しか（型情報がないため名前で判定する）
+func returnsError(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	if pkg, ok := sel.X.(*ast.Ident); ok && errorReturningFuncs[pkg.Name+"."+sel.Sel.Name] {
+		return true
+	}
+	return errorReturningMethods[sel.Sel.Name]
+}
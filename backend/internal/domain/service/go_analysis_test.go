@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeGoSource(t *testing.T) {
+	findingsOf := func(analysis *model.StaticAnalysis, rule string) []model.AnalysisFinding {
+		var findings []model.AnalysisFinding
+		for _, f := range analysis.Findings {
+			if f.Rule == rule {
+				findings = append(findings, f)
+			}
+		}
+		return findings
+	}
+
+	t.Run("捨てている戻り値を検出し、defer は対象外にする", func(t *testing.T) {
+		src := `package main
+
+func (s *Store) Load(path string) []byte {
+	data, _ := os.ReadFile(path)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	os.Remove(path)
+	_ = f.Sync()
+	var buf strings.Builder
+	buf.WriteString("ok")
+	return data
+}
+`
+		analysis := AnalyzeGoSource(src)
+
+		ignored := findingsOf(analysis, model.AnalysisRuleIgnoredError)
+		require.Len(t, ignored, 3)
+		assert.Equal(t, 4, ignored[0].StartLine)
+		assert.Equal(t, "os.ReadFile", ignored[0].Call)
+		assert.Equal(t, "Store.Load", ignored[0].Function)
+		assert.Equal(t, "os.ReadFile の最後の戻り値を _ で捨てています", ignored[0].Message)
+		assert.Equal(t, 10, ignored[1].StartLine)
+		assert.Equal(t, "os.Remove", ignored[1].Call)
+		assert.Equal(t, 11, ignored[2].StartLine)
+		assert.Equal(t, "f.Sync", ignored[2].Call)
+
+		require.Len(t, analysis.Functions, 1)
+		assert.Equal(t, model.FunctionMetrics{Name: "Store.Load", StartLine: 3, EndLine: 15, Lines: 13, MaxNesting: 1, Complexity: 2}, analysis.Functions[0])
+	})
+
+	t.Run("しきい値を超える関数の行数・ネストの深さ・循環的複雑度を指摘する", func(t *testing.T) {
+		var body strings.Builder
+		for i := 0; i < 10; i++ {
+			fmt.Fprintf(&body, "\tif v == %d || v < 0 {\n\t\tv++\n\t}\n", i)
+		}
+		for i := 0; i < 25; i++ {
+			body.WriteString("\tv++\n")
+		}
+		src := "func Long(v int) int {\n" + body.String() + "\tfor {\n\t\tswitch v {\n\t\tcase 1:\n\t\t\tif v > 0 {\n\t\t\t\tfunc() {}()\n\t\t\t}\n\t\t}\n\t}\n}\n"
+
+		analysis := AnalyzeGoSource(src)
+
+		require.Len(t, analysis.Functions, 1)
+		metrics := analysis.Functions[0]
+		assert.Equal(t, 65, metrics.Lines)
+		assert.Equal(t, 4, metrics.MaxNesting)
+		// 1 + if 10 + || 10 + for + case + if
+		assert.Equal(t, 24, metrics.Complexity)
+
+		long := findingsOf(analysis, model.AnalysisRuleLongFunction)
+		require.Len(t, long, 1)
+		assert.Equal(t, model.AnalysisFinding{
+			Rule: model.AnalysisRuleLongFunction, StartLine: 1, EndLine: 65, Function: "Long",
+			Message: "関数 Long は 65 行です（しきい値 50 行）", Value: 65, Threshold: model.MaxFunctionLines,
+		}, long[0])
+		deep := findingsOf(analysis, model.AnalysisRuleDeepNesting)
+		require.Len(t, deep, 1)
+		assert.Equal(t, 61, deep[0].StartLine)
+		assert.Equal(t, 4, deep[0].Value)
+		assert.Len(t, findingsOf(analysis, model.AnalysisRuleHighComplexity), 1)
+	})
+
+	t.Run("else if はネストを深くしない", func(t *testing.T) {
+		analysis := AnalyzeGoSource("func f(v int) {\n\tif v == 1 {\n\t} else if v == 2 {\n\t} else if v == 3 {\n\t\tfor {\n\t\t}\n\t}\n}")
+		require.Len(t, analysis.Functions, 1)
+		assert.Equal(t, 2, analysis.Functions[0].MaxNesting)
+		assert.Equal(t, 5, analysis.Functions[0].Complexity)
+	})
+
+	t.Run("文の並びは関数を計測せずに捨てている戻り値のみ検出する", func(t *testing.T) {
+		analysis := AnalyzeGoSource("n, _ := strconv.Atoi(s)\nfmt.Println(n)")
+		assert.Empty(t, analysis.Functions)
+		require.Len(t, analysis.Findings, 1)
+		assert.Equal(t, model.AnalysisRuleIgnoredError, analysis.Findings[0].Rule)
+		assert.Equal(t, 1, analysis.Findings[0].StartLine)
+		assert.Empty(t, analysis.Findings[0].Function)
+	})
+
+	t.Run("構文エラーがある場合は構文エラーのみ返す", func(t *testing.T) {
+		analysis := AnalyzeGoSource("package main\n\nfunc main() {\n\tdata, _ := load(\n}\n")
+		assert.Empty(t, analysis.Functions)
+		require.NotEmpty(t, analysis.Findings)
+		for _, f := range analysis.Findings {
+			assert.Equal(t, model.AnalysisRuleSyntaxError, f.Rule)
+			assert.Positive(t, f.StartLine)
+			assert.True(t, strings.HasPrefix(f.Message, "構文エラー: "))
+		}
+	})
+
+	t.Run("チャンクの範囲の指摘をチャンクの行番号にする", func(t *testing.T) {
+		analysis := AnalyzeGoSource("func a() {\n\t_ = f.Close()\n}\n\nfunc b() {\n\t_ = g.Close()\n}\n")
+		within := analysis.WithinLines(5, 7)
+		require.Len(t, within.Findings, 1)
+		assert.Equal(t, "g.Close", within.Findings[0].Call)
+		assert.Equal(t, 2, within.Findings[0].StartLine)
+		require.Len(t, within.Functions, 1)
+		assert.Equal(t, "b", within.Functions[0].Name)
+		assert.Equal(t, 1, within.Functions[0].StartLine)
+	})
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"strings"
+)
+
+// goSyntaxErrorLimit - 返す構文エラーの最大数
+const goSyntaxErrorLimit = 10
+
+// IsGoLanguage - レビューの言語が Go か
+func IsGoLanguage(language string) bool {
+	switch strings.ToLower(strings.TrimSpace(language)) {
+	case "go", "golang":
+		return true
+	}
+	return false
+}
+
+// FormatGoSource - Goのコードの構文を go/parser で検査し、go/format で整形したコードを返す
+// レビューには貼り付けたコードの一部（パッケージ宣言のない宣言・文の並び）もあるため、
+// go/format と同じくそれらはパッケージ・関数で囲んで検査する
+// 構文エラーがある場合は整形せずにそのまま返し、エラーの行とメッセージ（最大 goSyntaxErrorLimit 件）を返す
+func FormatGoSource(src string) (string, []string) {
+	if _, err := parseGoSource(src); err != nil {
+		return src, goSyntaxErrors(err)
+	}
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return src, goSyntaxErrors(err)
+	}
+	return string(formatted), nil
+}
+
+// goSource - 構文を解析した Go のコード
+type goSource struct {
+	fset *token.FileSet
+	file *ast.File
+	// statements - 文の並びを関数 _ で囲んで解析したか（file の唯一の関数は元のコードにない）
+	statements bool
+}
+
+// parseGoSource - ファイル・宣言の並び・文の並びのいずれかとして構文を解析する
+// 囲むコードは1行目の前に同じ行で付けるため、行番号は元のコードと同じになる
+func parseGoSource(src string) (*goSource, error) {
+	fset := token.NewFileSet()
+	if _, err := parser.ParseFile(fset, "", src, parser.PackageClauseOnly); err == nil {
+		file, err := parser.ParseFile(fset, "", src, parser.AllErrors)
+		if err != nil {
+			return nil, err
+		}
+		return &goSource{fset: fset, file: file}, nil
+	}
+
+	file, declErr := parser.ParseFile(fset, "", "package p;"+src, parser.AllErrors)
+	if declErr == nil {
+		return &goSource{fset: fset, file: file}, nil
+	}
+	file, stmtErr := parser.ParseFile(fset, "", "package p; func _() {"+src+"\n}", parser.AllErrors)
+	if stmtErr == nil {
+		return &goSource{fset: fset, file: file, statements: true}, nil
+	}
+	// 宣言として解析できない場合（関数の外に文がある）は文の並びとしてのエラーを返す
+	if strings.Contains(declErr.Error(), "expected declaration") {
+		return nil, stmtErr
+	}
+	return nil, declErr
+}
+
+// goSyntaxErrors - 構文エラーを「N行目: メッセージ」の形式にする
+func goSyntaxErrors(err error) []string {
+	list := goSyntaxErrorList(err)
+	messages := make([]string, 0, len(list))
+	for _, e := range list {
+		if e.Pos.Line == 0 {
+			messages = append(messages, e.Msg)
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("%d行目: %s", e.Pos.Line, e.Msg))
+	}
+	return messages
+}
+
+// goSyntaxErrorList - 構文エラーの一覧（最大 goSyntaxErrorLimit 件。位置のないエラーは行番号0）
+func goSyntaxErrorList(err error) []*scanner.Error {
+	var list scanner.ErrorList
+	if !errors.As(err, &list) {
+		return []*scanner.Error{{Msg: err.Error()}}
+	}
+	if len(list) > goSyntaxErrorLimit {
+		list = list[:goSyntaxErrorLimit]
+	}
+	return list
+}
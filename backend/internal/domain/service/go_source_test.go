@@ -0,0 +1,36 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatGoSource(t *testing.T) {
+	t.Run("ファイル・宣言の並び・文の並びを整形する", func(t *testing.T) {
+		formatted, errs := FormatGoSource("package main\nfunc main(){\nx:=1\n_ = x}\n")
+		assert.Empty(t, errs)
+		assert.Equal(t, "package main\n\nfunc main() {\n\tx := 1\n\t_ = x\n}\n", formatted)
+
+		formatted, errs = FormatGoSource("func add(a,b int) int {return a+b}")
+		assert.Empty(t, errs)
+		assert.Equal(t, "func add(a, b int) int { return a + b }", formatted)
+
+		formatted, errs = FormatGoSource("x:=1\nfmt.Println(x)")
+		assert.Empty(t, errs)
+		assert.Equal(t, "x := 1\nfmt.Println(x)", formatted)
+	})
+
+	t.Run("構文エラーは整形せずに行番号とメッセージを返す", func(t *testing.T) {
+		src := "package main\n\nfunc main() {\n\tif x {\n}\n"
+		formatted, errs := FormatGoSource(src)
+		assert.Equal(t, src, formatted)
+		require.NotEmpty(t, errs)
+		assert.Regexp(t, `^\d+行目: `, errs[0])
+	})
+
+	assert.True(t, IsGoLanguage("Go"))
+	assert.True(t, IsGoLanguage("golang"))
+	assert.False(t, IsGoLanguage("Python"))
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// LocateImprovements - LLMが返した改善点の行範囲をレビューしたコードと照合したコピーを返す
+//
+//   - code_before がコードにあればその位置を行範囲にする（複数ある場合は LLM の開始行に最も近い位置）
+//   - code_before がコードにない場合は code_before を空にし、LLM の行範囲をコードの範囲に収める（改善点の適用はこの行範囲を使う）
+//     開始行がコードの範囲外の場合は行範囲なし（0）、終了行が範囲を超える場合は最終行にする
+//
+// firstLine はコードの1行目の行番号（分割・差分の場合は元のファイルでの行番号）、
+// lineOffset は LLM の行番号に足す値（LLM がチャンクの1行目を1として数える場合は firstLine-1）
+func LocateImprovements(result *model.StructuredReviewResult, code string, firstLine, lineOffset int) *model.StructuredReviewResult {
+	if result == nil {
+		return nil
+	}
+	lines := strings.Split(code, "\n")
+	lastLine := firstLine + len(lines) - 1
+
+	located := *result
+	located.Improvements = make([]model.Improvement, len(result.Improvements))
+	for i, imp := range result.Improvements {
+		start, end := imp.StartLine, imp.EndLine
+		if start > 0 {
+			start += lineOffset
+		}
+		if end > 0 {
+			end += lineOffset
+		}
+
+		if from, to, ok := findLines(lines, imp.CodeBefore, start-firstLine); ok {
+			start, end = firstLine+from, firstLine+to
+		} else {
+			imp.CodeBefore = ""
+			start, end = clampLineRange(start, end, firstLine, lastLine)
+		}
+		imp.StartLine, imp.EndLine = start, end
+		located.Improvements[i] = imp
+	}
+	return &located
+}
+
+// clampLineRange - 行範囲を first〜last に収める（開始行が範囲外の場合は 0, 0）
+func clampLineRange(start, end, first, last int) (int, int) {
+	if start < first || start > last {
+		return 0, 0
+	}
+	if end < start {
+		end = start
+	}
+	if end > last {
+		end = last
+	}
+	return start, end
+}
+
+// findLines - snippet の行がコードに連続して現れる位置（0始まり、終了行を含む）
+// 行の前後の空白は無視し、複数ある場合は hint の行に最も近い位置を返す
+func findLines(lines []string, snippet string, hint int) (int, int, bool) {
+	target := trimmedLines(snippet)
+	if len(target) == 0 || len(target) > len(lines) {
+		return 0, 0, false
+	}
+
+	best, found := 0, false
+	for i := 0; i+len(target) <= len(lines); i++ {
+		if !matchLines(lines[i:i+len(target)], target) {
+			continue
+		}
+		if !found || abs(i-hint) < abs(best-hint) {
+			best, found = i, true
+		}
+	}
+	return best, best + len(target) - 1, found
+}
+
+// trimmedLines - 前後の空白を除いた行（先頭と末尾の空行は除く）
+func trimmedLines(snippet string) []string {
+	var lines []string
+	for _, line := range strings.Split(snippet, "\n") {
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// matchLines - 前後の空白を除いて行が一致するか
+func matchLines(lines, target []string) bool {
+	for i, line := range lines {
+		if strings.TrimSpace(line) != target[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocateImprovements(t *testing.T) {
+	code := "func Load(path string) []byte {\n\tdata, _ := os.ReadFile(path)\n\treturn data\n}\n\nfunc Save(path string, data []byte) {\n\tos.WriteFile(path, data, 0644)\n}"
+
+	tests := []struct {
+		name       string
+		imp        model.Improvement
+		start, end int
+		codeBefore string
+	}{
+		{
+			name:  "コードの範囲内の行範囲はそのまま",
+			imp:   model.Improvement{StartLine: 2, EndLine: 3},
+			start: 2, end: 3,
+		},
+		{
+			name:       "code_before の位置を優先する（前後の空白は無視）",
+			imp:        model.Improvement{StartLine: 6, EndLine: 6, CodeBefore: "  data, _ := os.ReadFile(path)  "},
+			start:      2,
+			end:        2,
+			codeBefore: "  data, _ := os.ReadFile(path)  ",
+		},
+		{
+			name:  "コードにない code_before は空にして行範囲で判断する",
+			imp:   model.Improvement{StartLine: 7, EndLine: 7, CodeBefore: "os.Remove(path)"},
+			start: 7, end: 7,
+		},
+		{
+			name:  "終了行が最終行を超える場合は最終行にする",
+			imp:   model.Improvement{StartLine: 6, EndLine: 20},
+			start: 6, end: 8,
+		},
+		{
+			name:  "終了行がない・開始行より前の場合は開始行にする",
+			imp:   model.Improvement{StartLine: 7, EndLine: 3},
+			start: 7, end: 7,
+		},
+		{
+			name:  "開始行がコードの範囲外の場合は行範囲なし",
+			imp:   model.Improvement{StartLine: 42, EndLine: 45},
+			start: 0, end: 0,
+		},
+		{
+			name:  "行範囲の指定がない場合は行範囲なし",
+			imp:   model.Improvement{},
+			start: 0, end: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &model.StructuredReviewResult{Improvements: []model.Improvement{tt.imp}}
+
+			located := LocateImprovements(result, code, 1, 0)
+
+			require.Len(t, located.Improvements, 1)
+			assert.Equal(t, tt.start, located.Improvements[0].StartLine)
+			assert.Equal(t, tt.end, located.Improvements[0].EndLine)
+			assert.Equal(t, tt.codeBefore, located.Improvements[0].CodeBefore)
+		})
+	}
+}
+
+func TestLocateImprovements_Chunk(t *testing.T) {
+	// 元のファイルの101行目からのチャンク。LLMはチャンクの1行目を1として数える
+	code := "func A() {\n\treturn\n}\n\nfunc B() {\n\treturn\n}"
+	result := &model.StructuredReviewResult{Improvements: []model.Improvement{
+		{StartLine: 2, EndLine: 2},
+		// 同じ行が複数ある場合は LLM の行に近い位置
+		{StartLine: 6, EndLine: 6, CodeBefore: "return"},
+		{StartLine: 9, EndLine: 9},
+	}}
+
+	located := LocateImprovements(result, code, 101, 100)
+
+	assert.Equal(t, 102, located.Improvements[0].StartLine)
+	assert.Equal(t, 106, located.Improvements[1].StartLine)
+	assert.Equal(t, 106, located.Improvements[1].EndLine)
+	assert.Equal(t, 0, located.Improvements[2].StartLine)
+	// 元の結果は変更しない
+	assert.Equal(t, 2, result.Improvements[0].StartLine)
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"math"
+	"strings"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// ModelPrice - モデルの料金（100万トークンあたり）
+// CacheWrite / CacheRead が0の場合は Input と同じ料金として計算する
+type ModelPrice struct {
+	Input      float64 `json:"input"`
+	Output     float64 `json:"output"`
+	CacheWrite float64 `json:"cache_write,omitempty"`
+	CacheRead  float64 `json:"cache_read,omitempty"`
+}
+
+// DefaultPriceCurrency - 組み込み料金表の通貨
+const DefaultPriceCurrency = "USD"
+
+// DefaultModelPrices - 組み込みの料金表（USD / 100万トークン）
+// セルフホストモデル（Ollama など）は含めないため、コストは0になる
+func DefaultModelPrices() map[string]ModelPrice {
+	return map[string]ModelPrice{
+		// Anthropic
+		"claude-3-5-haiku":  {Input: 0.80, Output: 4.00, CacheWrite: 1.00, CacheRead: 0.08},
+		"claude-3-5-sonnet": {Input: 3.00, Output: 15.00, CacheWrite: 3.75, CacheRead: 0.30},
+		"claude-3-7-sonnet": {Input: 3.00, Output: 15.00, CacheWrite: 3.75, CacheRead: 0.30},
+		"claude-sonnet-4":   {Input: 3.00, Output: 15.00, CacheWrite: 3.75, CacheRead: 0.30},
+		"claude-3-opus":     {Input: 15.00, Output: 75.00, CacheWrite: 18.75, CacheRead: 1.50},
+		"claude-opus-4":     {Input: 15.00, Output: 75.00, CacheWrite: 18.75, CacheRead: 1.50},
+		// OpenAI
+		"gpt-4o-mini": {Input: 0.15, Output: 0.60, CacheRead: 0.075},
+		"gpt-4o":      {Input: 2.50, Output: 10.00, CacheRead: 1.25},
+		// Embedding
+		"text-embedding-3-small": {Input: 0.02},
+		"text-embedding-3-large": {Input: 0.13},
+	}
+}
+
+// PriceTable - モデルごとの料金表
+type PriceTable struct {
+	currency string
+	prices   map[string]ModelPrice
+}
+
+// NewPriceTable - コンストラクタ
+func NewPriceTable(currency string, prices map[string]ModelPrice) *PriceTable {
+	if currency == "" {
+		currency = DefaultPriceCurrency
+	}
+	return &PriceTable{currency: currency, prices: prices}
+}
+
+// Currency - 料金表の通貨
+func (t *PriceTable) Currency() string {
+	return t.currency
+}
+
+// Lookup - モデルの料金を取得
+// APIが返すモデル名には日付などのサフィックスが付くため（例: claude-3-5-haiku-20241022）、
+// 完全一致がなければ最も長く前方一致するエントリを使う
+func (t *PriceTable) Lookup(modelName string) (ModelPrice, bool) {
+	if price, ok := t.prices[modelName]; ok {
+		return price, true
+	}
+
+	best := ""
+	for name := range t.prices {
+		if strings.HasPrefix(modelName, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return ModelPrice{}, false
+	}
+	return t.prices[best], true
+}
+
+// Cost - トークン使用量からコストを算出（料金表にないモデルの分は0として扱う）
+func (t *PriceTable) Cost(llmModel, embeddingModel string, usage model.TokenUsage) float64 {
+	var cost float64
+
+	if price, ok := t.Lookup(llmModel); ok {
+		cacheWrite, cacheRead := price.CacheWrite, price.CacheRead
+		if cacheWrite == 0 {
+			cacheWrite = price.Input
+		}
+		if cacheRead == 0 {
+			cacheRead = price.Input
+		}
+		cost += float64(usage.InputTokens)*price.Input +
+			float64(usage.OutputTokens)*price.Output +
+			float64(usage.CacheCreationTokens)*cacheWrite +
+			float64(usage.CacheReadTokens)*cacheRead
+	}
+
+	if price, ok := t.Lookup(embeddingModel); ok {
+		cost += float64(usage.EmbeddingTokens) * price.Input
+	}
+
+	// 100万トークンあたりの料金なので換算し、小数点以下6桁に丸める
+	return math.Round(cost) / 1e6
+}
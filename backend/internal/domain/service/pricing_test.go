@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceTable_Lookup(t *testing.T) {
+	table := NewPriceTable("", map[string]ModelPrice{
+		"claude-3-5":        {Input: 1},
+		"claude-3-5-sonnet": {Input: 3},
+	})
+
+	assert.Equal(t, DefaultPriceCurrency, table.Currency())
+
+	// 日付サフィックス付きのモデル名は最も長く前方一致するエントリを使う
+	price, ok := table.Lookup("claude-3-5-sonnet-20241022")
+	assert.True(t, ok)
+	assert.Equal(t, 3.0, price.Input)
+
+	price, ok = table.Lookup("claude-3-5-haiku-latest")
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, price.Input)
+
+	_, ok = table.Lookup("qwen2.5-coder:7b")
+	assert.False(t, ok)
+	_, ok = table.Lookup("")
+	assert.False(t, ok)
+}
+
+func TestPriceTable_Cost(t *testing.T) {
+	table := NewPriceTable("USD", map[string]ModelPrice{
+		"claude-3-5-haiku":       {Input: 0.80, Output: 4.00, CacheWrite: 1.00, CacheRead: 0.08},
+		"gpt-4o-mini":            {Input: 0.15, Output: 0.60},
+		"text-embedding-3-small": {Input: 0.02},
+	})
+
+	tests := []struct {
+		name           string
+		llmModel       string
+		embeddingModel string
+		usage          model.TokenUsage
+		expected       float64
+	}{
+		{
+			name:           "入力・出力・キャッシュ・Embeddingを合算",
+			llmModel:       "claude-3-5-haiku-20241022",
+			embeddingModel: "text-embedding-3-small",
+			usage:          model.TokenUsage{InputTokens: 1000, OutputTokens: 500, CacheCreationTokens: 2000, CacheReadTokens: 10000, EmbeddingTokens: 3000},
+			// 1000*0.80 + 500*4.00 + 2000*1.00 + 10000*0.08 + 3000*0.02 = 5660 / 1e6
+			expected: 0.00566,
+		},
+		{
+			name:     "キャッシュ料金が未設定の場合は入力と同じ料金",
+			llmModel: "gpt-4o-mini",
+			usage:    model.TokenUsage{InputTokens: 1000, CacheReadTokens: 1000},
+			expected: 0.0003,
+		},
+		{
+			name:           "料金表にないモデル（セルフホスト）は0",
+			llmModel:       "qwen2.5-coder:7b",
+			embeddingModel: "nomic-embed-text",
+			usage:          model.TokenUsage{InputTokens: 100000, OutputTokens: 100000, EmbeddingTokens: 1000},
+			expected:       0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, table.Cost(tt.llmModel, tt.embeddingModel, tt.usage), 1e-9)
+		})
+	}
+}
+
+func TestDefaultModelPrices_ConfiguredModels(t *testing.T) {
+	table := NewPriceTable(DefaultPriceCurrency, DefaultModelPrices())
+
+	// config のデフォルトモデルが料金表に含まれている
+	for _, name := range []string{"claude-3-5-haiku-latest", "gpt-4o-mini", "text-embedding-3-small"} {
+		_, ok := table.Lookup(name)
+		assert.True(t, ok, name)
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// プロンプト内でユーザーの入力を囲む区切りタグ
+// LLMには、このタグの中身をデータとして扱い、指示として従わないよう指示する
+const (
+	UntrustedCodeTag    = "untrusted_code"
+	UntrustedContextTag = "untrusted_context"
+	KnowledgeTag        = "knowledge"
+)
+
+// reservedTagPattern - 入力に含まれる区切りタグ（開始・終了、大文字小文字を問わない）
+var reservedTagPattern = regexp.MustCompile(`(?i)<(/?\s*)(` + UntrustedCodeTag + `|` + UntrustedContextTag + `|` + KnowledgeTag + `)\b`)
+
+// EscapePromptDelimiters - 入力に含まれる区切りタグを無効化する（"<" を "&lt;" に置き換える）
+// 入力の途中でタグを閉じて、後ろに指示を書き足すことを防ぐ
+func EscapePromptDelimiters(text string) string {
+	return reservedTagPattern.ReplaceAllString(text, "&lt;$1$2")
+}
+
+// WrapUntrusted - 入力を区切りタグで囲む（中身の区切りタグは無効化する）
+func WrapUntrusted(tag, text string) string {
+	return fmt.Sprintf("<%s>\n%s\n</%s>", tag, EscapePromptDelimiters(text), tag)
+}
+
+// FenceCode - コードをマークダウンのコードブロックにする
+// コード中のバッククォートの連続より長いフェンスを使い、コード内の ``` でブロックが閉じないようにする
+func FenceCode(code, language string) string {
+	longest := 0
+	run := 0
+	for _, r := range code {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+			continue
+		}
+		run = 0
+	}
+	fenceLength := 3
+	if longest >= fenceLength {
+		fenceLength = longest + 1
+	}
+	fence := strings.Repeat("`", fenceLength)
+	return fmt.Sprintf("%s%s\n%s\n%s", fence, SanitizeFenceLanguage(language), code, fence)
+}
+
+// SanitizeFenceLanguage - コードブロックの言語名として使える文字（英数字と + # . _ -）のみ残す
+// 言語名はユーザーの入力のため、改行などでプロンプトの構造を変えられないようにする
+func SanitizeFenceLanguage(language string) string {
+	var sb strings.Builder
+	for _, r := range language {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', strings.ContainsRune("+#._-", r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// SanitizePromptLine - 見出しなど1行で使う入力から改行を取り除き、区切りタグを無効化する
+func SanitizePromptLine(text string) string {
+	return EscapePromptDelimiters(strings.Join(strings.Fields(text), " "))
+}
+
+// injectionPattern - プロンプトインジェクションの疑いがある記述のパターン
+type injectionPattern struct {
+	kind    string
+	pattern *regexp.Regexp
+}
+
+// injectionPatterns - 既知の手口（英語・日本語）
+// 誤検知を抑えるため、コードのコメントとして自然な表現（"ignore errors from the previous call" "should return no issues" など）には一致しないようにする
+var injectionPatterns = []injectionPattern{
+	{model.InjectionKindIgnoreInstructions, regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override|bypass)\b.{0,40}\b(previous|prior|above|earlier|preceding|all|any|your|system|original)\b.{0,30}\b(instructions?|prompts?|rules|directions|guidelines|directives)\b`)},
+	{model.InjectionKindIgnoreInstructions, regexp.MustCompile(`(以前|これまで|上記|上|前|先|元|最初|すべて|全て)の(指示|命令|ルール|プロンプト|指令)(を|は)(すべて|全て)?(無視|忘れ|破棄)`)},
+	{model.InjectionKindRoleOverride, regexp.MustCompile(`(?i)\b(you are now (an?|the|my|in)\b|you are no longer\b|from now on,? (you|your)\b|pretend (to be|you are)\b|(act|behave|respond) as (if you (were|are)|an? (ai|assistant|language model|different|unrestricted|new))\b|developer mode\b)`)},
+	{model.InjectionKindRoleOverride, regexp.MustCompile(`あなたは(今から|これから|もう)|として振る舞って|(役割|ロール)を(変更|変え)`)},
+	{model.InjectionKindPromptExfiltration, regexp.MustCompile(`(?i)\b(reveal|print|show|output|repeat|leak|display|dump)\b.{0,30}\b(system prompt|system message|hidden prompt|initial prompt|your instructions|the instructions above)\b`)},
+	{model.InjectionKindPromptExfiltration, regexp.MustCompile(`(システムプロンプト|指示文|プロンプト)(の内容)?を(表示|出力|教え|開示)`)},
+	{model.InjectionKindOutputManipulation, regexp.MustCompile(`(?i)\b(respond|reply|answer|say|report|state|conclude)\b.{0,40}\b(no issues|no problems|no improvements|looks good|lgtm|is perfect|is secure|approved?)\b`)},
+	{model.InjectionKindOutputManipulation, regexp.MustCompile(`(?i)\b(do not|don't|never)\s+(report|mention|flag|point out)\b.{0,30}\b(issues?|problems?|vulnerabilit(y|ies)|bugs?|this)\b`)},
+	{model.InjectionKindOutputManipulation, regexp.MustCompile(`(問題(は|が)?(ない|なし|ありません)|改善点(は|が)?(ない|なし|ありません)|完璧)(と|だと)(出力|回答|報告|答え|評価|判定)|(指摘|報告)しないで`)},
+	{model.InjectionKindDelimiterInjection, regexp.MustCompile(`(?i)</?\s*(` + UntrustedCodeTag + `|` + UntrustedContextTag + `|` + KnowledgeTag + `|system|instructions?)\s*>`)},
+	{model.InjectionKindDelimiterInjection, regexp.MustCompile(`<\|(im_start|im_end|system|user|assistant|endoftext)\|>|\[/?INST\]|<</?SYS>>`)},
+	{model.InjectionKindDelimiterInjection, regexp.MustCompile(`^\W{0,4}(Human|Assistant|SYSTEM):`)},
+	{model.InjectionKindToolSpoofing, regexp.MustCompile(`\bsubmit_review\b`)},
+}
+
+// maxInjectionExcerptRunes - 記録する該当行の最大文字数
+const maxInjectionExcerptRunes = 120
+
+// PromptInjectionInput - プロンプトインジェクションを検査する入力
+type PromptInjectionInput struct {
+	Code      string
+	Context   string
+	Knowledge []*model.Knowledge // プロンプトに含めたナレッジ
+}
+
+// DetectPromptInjection - 入力からプロンプトインジェクションの疑いがある行を検出する
+// 同じ行で同じ種類の疑いは1件にまとめる。疑いがない場合は nil
+func DetectPromptInjection(input PromptInjectionInput) []model.PromptInjectionSignal {
+	var signals []model.PromptInjectionSignal
+	signals = append(signals, detectInText(model.InjectionSourceCode, "", input.Code)...)
+	signals = append(signals, detectInText(model.InjectionSourceContext, "", input.Context)...)
+	for _, k := range input.Knowledge {
+		signals = append(signals, detectInText(model.InjectionSourceKnowledge, k.ID, k.Title+"\n"+k.Content)...)
+	}
+	return signals
+}
+
+// detectInText - 1つの入力を行ごとに検査
+func detectInText(source, sourceID, text string) []model.PromptInjectionSignal {
+	if text == "" {
+		return nil
+	}
+
+	var signals []model.PromptInjectionSignal
+	for i, line := range strings.Split(text, "\n") {
+		found := map[string]bool{}
+		for _, p := range injectionPatterns {
+			if found[p.kind] || !p.pattern.MatchString(line) {
+				continue
+			}
+			found[p.kind] = true
+			signals = append(signals, model.PromptInjectionSignal{
+				Kind:     p.kind,
+				Source:   source,
+				SourceID: sourceID,
+				Line:     i + 1,
+				Excerpt:  truncateRunes(strings.TrimSpace(line), maxInjectionExcerptRunes),
+			})
+		}
+	}
+	return signals
+}
+
+// truncateRunes - 文字数で切り詰める（切り詰めた場合は末尾に … を付ける）
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "…"
+}
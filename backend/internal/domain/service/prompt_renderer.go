@@ -0,0 +1,233 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// PromptRenderer - プロンプトテンプレートを描画するインターフェース
+type PromptRenderer interface {
+	// Render - テンプレートにレビュー対象とナレッジを差し込んでプロンプトを生成
+	Render(tmpl *model.PromptTemplate, data PromptData) (string, error)
+
+	// Validate - テンプレートの構文と差し込み項目をサンプルデータで検証
+	Validate(content string) error
+}
+
+// ErrInvalidPromptTemplate - テンプレートの構文エラー・描画エラー
+var ErrInvalidPromptTemplate = errors.New("プロンプトテンプレートが不正です")
+
+// PromptData - テンプレートに差し込むデータ
+type PromptData struct {
+	Language  string
+	Context   string
+	Knowledge []*model.Knowledge // 実際にプロンプトに含めるナレッジ（優先度順）
+	Locale    string             // 省略時は model.DefaultLocale
+}
+
+// defaultReviewPromptTemplate - DBに有効なテンプレートがない場合に使う組み込みテンプレート（ロケールで切り替える）
+const defaultReviewPromptTemplate = `{{- if eq .locale "en" -}}
+You are a code reviewer.
+Review the code based on the following rules and past review decisions.
+
+## The user's coding philosophy and rules
+{{- if .has_knowledge}}
+{{range .knowledge}}
+### [{{.category_name}}] {{.title}}{{if .relevance_score}} (similarity: {{printf "%.2f" .relevance_score}}){{end}}
+<knowledge>
+{{.content}}
+</knowledge>
+{{end}}
+{{- else}}
+Review the code based on general best practices.
+{{- end}}
+
+## Review instructions
+1. Point out where the code violates the rules above
+2. Propose concrete improvements
+3. Explain why each rule matters
+4. Always point out good points as well
+
+**Important**: Give the user's philosophy and rules the highest priority.
+{{- else -}}
+あなたはコードレビュアーです。
+以下のルールと過去の判断基準に基づいてレビューしてください。
+
+## ユーザーのコーディング哲学・ルール
+{{- if .has_knowledge}}
+{{range .knowledge}}
+### [{{.category_name}}] {{.title}}{{if .relevance_score}}（類似度: {{printf "%.2f" .relevance_score}}）{{end}}
+<knowledge>
+{{.content}}
+</knowledge>
+{{end}}
+{{- else}}
+一般的なベストプラクティスに基づいてレビューしてください。
+{{- end}}
+
+## レビュー指示
+1. 上記のルールに違反している箇所を指摘
+2. 改善案を具体的に提示
+3. なぜそのルールが重要か説明
+4. 良い点も必ず指摘する
+
+**重要**: ユーザーの哲学・ルールを最優先してください。
+{{- end}}`
+
+// DefaultReviewPromptTemplate - 組み込みのレビュー用テンプレート（Version 0）
+func DefaultReviewPromptTemplate() *model.PromptTemplate {
+	return &model.PromptTemplate{
+		Name:     model.PromptTemplateReviewSystem,
+		Version:  0,
+		Content:  defaultReviewPromptTemplate,
+		IsActive: true,
+	}
+}
+
+// AppendPreferenceInstructions - 出力言語・ユーザー設定の重点的に確認する観点をレビュー方針の末尾に付け足す
+// テンプレートの内容によらず反映するため、描画後の文字列に追加する（指定がない場合はそのまま返す）
+// DBのテンプレートはロケールに合わせて書かれているとは限らないため、英語の場合は常に出力言語を指定する
+func AppendPreferenceInstructions(instructions string, prefs *model.UserPreferences, locale string) string {
+	locale = model.ResolveLocale(locale)
+
+	var lines []string
+	switch {
+	case locale == model.LocaleEnglish:
+		lines = append(lines, "- Write the review (summary, good points and improvement descriptions) in English. Use the headings exactly as specified in the output format.")
+	case prefs != nil && prefs.OutputLanguage == model.OutputLanguageJapanese:
+		lines = append(lines, "- レビュー結果の本文は日本語で記述してください。")
+	}
+	if labels := prefs.ReviewFocusLabels(locale); len(labels) > 0 {
+		if locale == model.LocaleEnglish {
+			lines = append(lines, fmt.Sprintf("- Pay particular attention to: %s", strings.Join(labels, ", ")))
+		} else {
+			lines = append(lines, fmt.Sprintf("- 特に次の観点を重点的に確認してください: %s", strings.Join(labels, "、")))
+		}
+	}
+	if len(lines) == 0 {
+		return instructions
+	}
+
+	heading := "## ユーザー設定"
+	if locale == model.LocaleEnglish {
+		heading = "## User preferences"
+	}
+	return instructions + "\n\n" + heading + "\n" + strings.Join(lines, "\n")
+}
+
+// TemplatePromptRenderer - text/template によるPromptRenderer実装
+//
+// 差し込み項目（docs/prompt-design.md の placeholder 名に合わせる）:
+//
+//	.language / .context / .has_knowledge / .locale（ja / en）
+//	.knowledge[] の各要素: .id .title .content .category .category_name .priority
+//	                      .source_type .source_id .created_at .relevance_score
+//
+// relevance_score はベクトル検索で取得したナレッジのみ値を持ち、それ以外は nil
+type TemplatePromptRenderer struct{}
+
+// NewTemplatePromptRenderer - コンストラクタ
+func NewTemplatePromptRenderer() *TemplatePromptRenderer {
+	return &TemplatePromptRenderer{}
+}
+
+// Render - テンプレートを描画
+func (r *TemplatePromptRenderer) Render(tmpl *model.PromptTemplate, data PromptData) (string, error) {
+	parsed, err := parsePromptTemplate(tmpl.Content)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := parsed.Execute(&sb, toTemplateData(data)); err != nil {
+		return "", fmt.Errorf("%w: 描画エラー: %v", ErrInvalidPromptTemplate, err)
+	}
+
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// Validate - 類似度あり・なし・ナレッジなしのサンプルで描画できるか検証
+func (r *TemplatePromptRenderer) Validate(content string) error {
+	parsed, err := parsePromptTemplate(content)
+	if err != nil {
+		return err
+	}
+
+	score := 0.82
+	sourceID := "00000000-0000-0000-0000-000000000000"
+	samples := []PromptData{
+		{
+			Language: "go",
+			Context:  "サンプル",
+			Knowledge: []*model.Knowledge{
+				{ID: "sample-1", Title: "エラーは必ずラップする", Content: "fmt.Errorf の %w で文脈を付ける", Category: model.CategoryErrorHandling, Priority: 5, SourceType: model.SourceTypeReview, SourceID: &sourceID, RelevanceScore: &score},
+				{ID: "sample-2", Title: "関数は50行以内", Content: "1つの責務のみを持つ", Category: model.CategoryCleanCode, Priority: 3, SourceType: model.SourceTypeManual},
+			},
+		},
+		{Language: "python"},
+		{Language: "go", Locale: model.LocaleEnglish, Knowledge: []*model.Knowledge{{ID: "sample-3", Title: "Wrap errors", Content: "Add context with %w", Category: model.CategoryErrorHandling, Priority: 4}}},
+	}
+
+	for _, sample := range samples {
+		if err := parsed.Execute(&strings.Builder{}, toTemplateData(sample)); err != nil {
+			return fmt.Errorf("%w: 描画エラー: %v", ErrInvalidPromptTemplate, err)
+		}
+	}
+	return nil
+}
+
+// parsePromptTemplate - テンプレートをパース（未定義の項目はエラーにする）
+func parsePromptTemplate(content string) (*template.Template, error) {
+	parsed, err := template.New("prompt").Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 構文エラー: %v", ErrInvalidPromptTemplate, err)
+	}
+	return parsed, nil
+}
+
+// toTemplateData - テンプレートに渡すデータ（キーは snake_case）に変換
+// ユーザーの入力（タイトル・内容・コンテキスト）は区切りタグを無効化して渡す
+func toTemplateData(data PromptData) map[string]interface{} {
+	locale := model.ResolveLocale(data.Locale)
+	knowledge := make([]map[string]interface{}, len(data.Knowledge))
+	for i, k := range data.Knowledge {
+		var relevanceScore interface{}
+		if k.RelevanceScore != nil {
+			relevanceScore = *k.RelevanceScore
+		}
+		sourceID := ""
+		if k.SourceID != nil {
+			sourceID = *k.SourceID
+		}
+		createdAt := ""
+		if !k.CreatedAt.IsZero() {
+			createdAt = k.CreatedAt.Format(time.DateOnly)
+		}
+
+		knowledge[i] = map[string]interface{}{
+			"id":              k.ID,
+			"title":           SanitizePromptLine(k.Title),
+			"content":         EscapePromptDelimiters(k.Content),
+			"category":        k.Category,
+			"category_name":   categoryDisplayName(k.Category, locale),
+			"priority":        k.Priority,
+			"source_type":     k.SourceType,
+			"source_id":       sourceID,
+			"created_at":      createdAt,
+			"relevance_score": relevanceScore,
+		}
+	}
+
+	return map[string]interface{}{
+		"language":      data.Language,
+		"context":       EscapePromptDelimiters(data.Context),
+		"has_knowledge": len(knowledge) > 0,
+		"locale":        locale,
+		"knowledge":     knowledge,
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplatePromptRenderer_Render_DefaultTemplate(t *testing.T) {
+	renderer := NewTemplatePromptRenderer()
+	score := 0.876
+	sourceID := "review-1"
+
+	t.Run("ナレッジを優先度順に差し込み、類似度があれば表示する", func(t *testing.T) {
+		rendered, err := renderer.Render(DefaultReviewPromptTemplate(), PromptData{
+			Language: "go",
+			Knowledge: []*model.Knowledge{
+				{Title: "エラーは必ずラップする", Content: "文脈を付ける", Category: model.CategoryErrorHandling, Priority: 5, SourceID: &sourceID, RelevanceScore: &score},
+				{Title: "関数は短く", Content: "50行以内", Category: model.CategoryCleanCode, Priority: 3},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Contains(t, rendered, "### [エラーハンドリング] エラーは必ずラップする（類似度: 0.88）\n<knowledge>\n文脈を付ける\n</knowledge>")
+		assert.Contains(t, rendered, "### [クリーンコード] 関数は短く\n<knowledge>\n50行以内\n</knowledge>")
+		assert.NotContains(t, rendered, "一般的なベストプラクティス")
+		assert.Contains(t, rendered, "## レビュー指示")
+	})
+
+	t.Run("ナレッジがない場合は一般的なベストプラクティスを指示する", func(t *testing.T) {
+		rendered, err := renderer.Render(DefaultReviewPromptTemplate(), PromptData{Language: "go"})
+
+		require.NoError(t, err)
+		assert.Contains(t, rendered, "## ユーザーのコーディング哲学・ルール\n一般的なベストプラクティスに基づいてレビューしてください。")
+	})
+}
+
+func TestTemplatePromptRenderer_Render_DefaultTemplateEnglish(t *testing.T) {
+	renderer := NewTemplatePromptRenderer()
+
+	t.Run("英語のロケールでは英語の方針とカテゴリ名にする", func(t *testing.T) {
+		rendered, err := renderer.Render(DefaultReviewPromptTemplate(), PromptData{
+			Language: "go",
+			Locale:   model.LocaleEnglish,
+			Knowledge: []*model.Knowledge{
+				{Title: "Wrap errors", Content: "Add context", Category: model.CategoryErrorHandling, Priority: 5},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(rendered, "You are a code reviewer."))
+		assert.Contains(t, rendered, "### [Error handling] Wrap errors\n<knowledge>\nAdd context\n</knowledge>")
+		assert.Contains(t, rendered, "## Review instructions")
+		assert.NotContains(t, rendered, "レビュー")
+	})
+
+	t.Run("ナレッジがない場合", func(t *testing.T) {
+		rendered, err := renderer.Render(DefaultReviewPromptTemplate(), PromptData{Language: "go", Locale: "en-US"})
+
+		require.NoError(t, err)
+		assert.Contains(t, rendered, "## The user's coding philosophy and rules\nReview the code based on general best practices.")
+	})
+}
+
+func TestTemplatePromptRenderer_Render_Placeholders(t *testing.T) {
+	renderer := NewTemplatePromptRenderer()
+	createdAt := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	sourceID := "review-1"
+
+	tmpl := &model.PromptTemplate{
+		Content: `{{.language}}|{{.context}}{{range .knowledge}}|{{.category}}:{{.priority}}:{{.source_type}}:{{.source_id}}:{{.created_at}}{{end}}`,
+	}
+
+	rendered, err := renderer.Render(tmpl, PromptData{
+		Language: "python",
+		Context:  "バッチ処理",
+		Knowledge: []*model.Knowledge{
+			{Category: model.CategoryTesting, Priority: 4, SourceType: model.SourceTypeReview, SourceID: &sourceID, CreatedAt: createdAt},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "python|バッチ処理|testing:4:review:review-1:2024-01-15", rendered)
+}
+
+func TestTemplatePromptRenderer_Validate(t *testing.T) {
+	renderer := NewTemplatePromptRenderer()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "組み込みテンプレート", content: DefaultReviewPromptTemplate().Content},
+		{name: "docs/prompt-design.md の項目名", content: `{{range .knowledge}}[{{.relevance_score}}] {{.title}} ({{.category}}){{end}}`},
+		{name: "構文エラー", content: `{{range .knowledge}}`, wantErr: true},
+		{name: "未定義の項目", content: `{{.user_name}}`, wantErr: true},
+		{name: "ナレッジの未定義の項目", content: `{{range .knowledge}}{{.score}}{{end}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := renderer.Validate(tt.content)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidPromptTemplate))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAppendPreferenceInstructions(t *testing.T) {
+	t.Run("出力言語と観点をレビュー方針の末尾に付け足す", func(t *testing.T) {
+		instructions := AppendPreferenceInstructions("レビュー方針", &model.UserPreferences{
+			OutputLanguage: model.OutputLanguageJapanese,
+			ReviewFocus:    []string{model.ReviewFocusSecurity, model.ReviewFocusPerformance},
+		}, model.LocaleJapanese)
+
+		assert.True(t, strings.HasPrefix(instructions, "レビュー方針\n\n## ユーザー設定\n"))
+		assert.Contains(t, instructions, "日本語で記述してください")
+		assert.Contains(t, instructions, "特に次の観点を重点的に確認してください: セキュリティ、パフォーマンス")
+	})
+
+	t.Run("英語の場合は英語で指示し、観点も英語にする", func(t *testing.T) {
+		instructions := AppendPreferenceInstructions("review policy", &model.UserPreferences{
+			ReviewFocus: []string{model.ReviewFocusSecurity, model.ReviewFocusErrorHandling},
+		}, model.LocaleEnglish)
+
+		assert.True(t, strings.HasPrefix(instructions, "review policy\n\n## User preferences\n"))
+		assert.Contains(t, instructions, "in English")
+		assert.Contains(t, instructions, "Pay particular attention to: security, error handling")
+	})
+
+	t.Run("英語の場合はユーザー設定がなくても出力言語を指定する", func(t *testing.T) {
+		instructions := AppendPreferenceInstructions("review policy", nil, model.LocaleEnglish)
+
+		assert.Contains(t, instructions, "## User preferences\n- Write the review")
+	})
+
+	t.Run("設定がない場合はそのまま返す", func(t *testing.T) {
+		assert.Equal(t, "レビュー方針", AppendPreferenceInstructions("レビュー方針", nil, ""))
+		assert.Equal(t, "レビュー方針", AppendPreferenceInstructions("レビュー方針", &model.UserPreferences{LLMModel: "gpt-4o"}, model.LocaleJapanese))
+	})
+}
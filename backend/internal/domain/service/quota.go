@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+)
+
+// QuotaService - 利用上限のドメインサービス
+// グローバルの上限にユーザー個別の上限を上書きし、当日・当月の消費量と比較する
+type QuotaService struct {
+	quotaRepo repository.QuotaRepository
+	counter   repository.QuotaCounter
+	defaults  model.QuotaPolicy
+	now       func() time.Time
+}
+
+// NewQuotaService - コンストラクタ
+func NewQuotaService(quotaRepo repository.QuotaRepository, counter repository.QuotaCounter, defaults model.QuotaPolicy) *QuotaService {
+	return &QuotaService{
+		quotaRepo: quotaRepo,
+		counter:   counter,
+		defaults:  defaults,
+		now:       time.Now,
+	}
+}
+
+// Defaults - グローバルの上限
+func (s *QuotaService) Defaults() model.QuotaPolicy {
+	return s.defaults
+}
+
+// Policy - ユーザーに適用される上限
+func (s *QuotaService) Policy(ctx context.Context, userID string) (model.QuotaPolicy, error) {
+	quota, err := s.quotaRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, model.ErrUserQuotaNotFound) {
+		return s.defaults, nil
+	}
+	if err != nil {
+		return model.QuotaPolicy{}, fmt.Errorf("failed to find user quota: %w", err)
+	}
+	return quota.Apply(s.defaults), nil
+}
+
+// Status - ユーザーの上限・消費量・残りを取得
+func (s *QuotaService) Status(ctx context.Context, userID string) (*model.QuotaStatus, error) {
+	policy, err := s.Policy(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.status(ctx, userID, policy)
+}
+
+// Check - 上限に達していれば *model.QuotaExceededError を返す
+func (s *QuotaService) Check(ctx context.Context, userID string) error {
+	policy, err := s.Policy(ctx, userID)
+	if err != nil {
+		return err
+	}
+	// 無制限の場合は消費量を集計しない
+	if policy.ReviewsPerDay <= 0 && policy.TokensPerMonth <= 0 {
+		return nil
+	}
+
+	status, err := s.status(ctx, userID, policy)
+	if err != nil {
+		return err
+	}
+	return status.Check()
+}
+
+// Record - 保存したレビュー1件分の消費を記録
+// 統合したレビュー（複数モデルのレビューの merge）はLLMを呼び出さないため、レビュー数に数えずトークン数のみ記録する
+func (s *QuotaService) Record(ctx context.Context, review *model.Review) error {
+	reviews := 1
+	if review.IsMergedEnsemble() {
+		reviews = 0
+	}
+	return s.counter.Add(ctx, review.UserID, s.now(), reviews, review.Usage.Total())
+}
+
+// Reserve - 受け付けた非同期のレビュー1件分を当日のレビュー数に加算（実行待ちのレビューも上限の確認に含めるため）
+// 期間は受け付けた日時（created_at）で決める（PostgreSQLの集計と同じ）
+func (s *QuotaService) Reserve(ctx context.Context, job *model.Review) error {
+	return s.counter.Add(ctx, job.UserID, job.CreatedAt, 1, 0)
+}
+
+// Release - 失敗・削除で中止した非同期のレビュー1件分を当日のレビュー数から戻す
+func (s *QuotaService) Release(ctx context.Context, job *model.Review) error {
+	return s.counter.Add(ctx, job.UserID, job.CreatedAt, -1, 0)
+}
+
+// RecordJob - 完了した非同期のレビューのトークン数のみ記録（レビュー数は受け付け時に加算済み）
+func (s *QuotaService) RecordJob(ctx context.Context, review *model.Review) error {
+	return s.counter.Add(ctx, review.UserID, review.CreatedAt, 0, review.Usage.Total())
+}
+
+// status - 消費量を取得して残りを計算
+func (s *QuotaService) status(ctx context.Context, userID string, policy model.QuotaPolicy) (*model.QuotaStatus, error) {
+	now := s.now()
+	usage, err := s.counter.Usage(ctx, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quota usage: %w", err)
+	}
+	return model.NewQuotaStatus(policy, usage, now), nil
+}
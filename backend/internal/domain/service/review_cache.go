@@ -0,0 +1,117 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// reviewCacheKeyVersion - キーの組み立て方を変えた場合に上げる（古いキャッシュを使わないため）
+const reviewCacheKeyVersion = 3
+
+// ReviewCacheKeyInput - レビュー結果のキャッシュキーの材料
+type ReviewCacheKeyInput struct {
+	Code           string
+	Diff           string            // 差分のレビューの場合の unified diff
+	ContextFiles   map[string]string // 差分のレビューで渡された変更後のファイル全体（パス → コード）
+	Language       string
+	Context        string
+	Knowledge      []*model.Knowledge // プロンプトに含めたナレッジ
+	PromptTemplate *model.PromptTemplate
+	// Prompt - LLMに渡すナレッジのプロンプトと描画したレビュー方針
+	// テンプレートに差し込んだ類似度（relevance_score）など、ナレッジの内容以外で変わる値を含めるため
+	Prompt   string
+	Provider string
+	Model    string
+	// Preferences - ユーザー設定（生成パラメータ・出力言語・観点。プロバイダとモデルは Provider / Model に含める）
+	Preferences *model.UserPreferences
+	// Locale - プロンプト・レビュー結果の言語（省略時は model.DefaultLocale）
+	Locale string
+}
+
+// reviewCacheKeyMaterial - ハッシュする値（フィールドの順序を固定するため構造体でJSONにする）
+type reviewCacheKeyMaterial struct {
+	Version         int                   `json:"v"`
+	Code            string                `json:"code"`
+	Language        string                `json:"language"`
+	Context         string                `json:"context"`
+	Knowledge       []knowledgeCacheEntry `json:"knowledge"`
+	TemplateID      string                `json:"template_id"`
+	TemplateVersion int                   `json:"template_version"`
+	PromptHash      string                `json:"prompt_hash"`
+	Provider        string                `json:"provider"`
+	Model           string                `json:"model"`
+	// ユーザー設定がない場合は出力しない（設定前に生成したキャッシュと同じキーにする）
+	Temperature    *float64 `json:"temperature,omitempty"`
+	MaxTokens      *int     `json:"max_tokens,omitempty"`
+	OutputLanguage string   `json:"output_language,omitempty"`
+	ReviewFocus    []string `json:"review_focus,omitempty"`
+	// 既定のロケールの場合は出力しない
+	Locale string `json:"locale,omitempty"`
+	// 差分のレビューの場合のみ出力する（ファイル全体のレビューは従来と同じキーにする）
+	Diff         string            `json:"diff,omitempty"`
+	ContextFiles map[string]string `json:"context_files,omitempty"`
+}
+
+// knowledgeCacheEntry - ナレッジのIDと内容のハッシュ
+// updated_at は使用回数の更新でも変わるため、バージョンとしてプロンプトに影響する内容のハッシュを使う
+type knowledgeCacheEntry struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+}
+
+// ReviewCacheKey - 同じ入力・ナレッジ・プロンプト・モデルのレビューに同じ値を返す（SHA-256の16進文字列）
+func ReviewCacheKey(input ReviewCacheKeyInput) string {
+	material := reviewCacheKeyMaterial{
+		Version:      reviewCacheKeyVersion,
+		Code:         input.Code,
+		Diff:         input.Diff,
+		ContextFiles: input.ContextFiles,
+		Language:     input.Language,
+		Context:      input.Context,
+		Knowledge:    make([]knowledgeCacheEntry, 0, len(input.Knowledge)),
+		PromptHash:   promptHash(input.Prompt),
+		Provider:     input.Provider,
+		Model:        input.Model,
+	}
+	for _, k := range input.Knowledge {
+		material.Knowledge = append(material.Knowledge, knowledgeCacheEntry{ID: k.ID, Version: knowledgeVersion(k)})
+	}
+	// 検索結果の順序が変わってもナレッジの一覧は同じ値にする（プロンプトの違いは PromptHash で区別する）
+	sort.Slice(material.Knowledge, func(i, j int) bool {
+		return material.Knowledge[i].ID < material.Knowledge[j].ID
+	})
+	if input.Preferences != nil {
+		material.Temperature = input.Preferences.Temperature
+		material.MaxTokens = input.Preferences.MaxTokens
+		material.OutputLanguage = input.Preferences.OutputLanguage
+		material.ReviewFocus = input.Preferences.ReviewFocus
+	}
+	if locale := model.ResolveLocale(input.Locale); locale != model.DefaultLocale {
+		material.Locale = locale
+	}
+	if input.PromptTemplate != nil {
+		material.TemplateID = input.PromptTemplate.ID
+		material.TemplateVersion = input.PromptTemplate.Version
+	}
+
+	data, _ := json.Marshal(material)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// promptHash - 描画したプロンプトのハッシュ
+func promptHash(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+// knowledgeVersion - プロンプトに含まれるナレッジの内容のハッシュ
+func knowledgeVersion(k *model.Knowledge) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%d", k.Title, k.Content, k.Category, k.Priority)))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewCacheKey(t *testing.T) {
+	k1 := &model.Knowledge{ID: "k1", Title: "エラー処理", Content: "エラーはラップする", Category: model.CategoryErrorHandling, Priority: 3}
+	k2 := &model.Knowledge{ID: "k2", Title: "テスト", Content: "テーブル駆動で書く", Category: model.CategoryTesting, Priority: 2}
+	base := ReviewCacheKeyInput{
+		Code:           "func main() {}",
+		Language:       "go",
+		Knowledge:      []*model.Knowledge{k1, k2},
+		PromptTemplate: &model.PromptTemplate{ID: "t1", Version: 2},
+		Prompt:         "### [エラーハンドリング] エラー処理（類似度: 0.82）",
+		Provider:       "anthropic",
+		Model:          "claude-3-5-haiku-latest",
+	}
+	key := ReviewCacheKey(base)
+	assert.Len(t, key, 64)
+
+	t.Run("ナレッジの順序や使用回数が変わっても同じキー", func(t *testing.T) {
+		used := *k1
+		used.UsageCount = 10
+		input := base
+		input.Knowledge = []*model.Knowledge{k2, &used}
+		assert.Equal(t, key, ReviewCacheKey(input))
+	})
+
+	t.Run("既定のロケールを指定した場合は指定がない場合と同じキー", func(t *testing.T) {
+		input := base
+		input.Locale = model.DefaultLocale
+		assert.Equal(t, key, ReviewCacheKey(input))
+	})
+
+	t.Run("ユーザー設定が空の場合は設定がない場合と同じキー", func(t *testing.T) {
+		input := base
+		input.Preferences = &model.UserPreferences{}
+		assert.Equal(t, key, ReviewCacheKey(input))
+	})
+
+	changes := map[string]func(in *ReviewCacheKeyInput){
+		"コード":     func(in *ReviewCacheKeyInput) { in.Code += "\n" },
+		"言語":      func(in *ReviewCacheKeyInput) { in.Language = "python" },
+		"コンテキスト":  func(in *ReviewCacheKeyInput) { in.Context = "CLI" },
+		"ナレッジの件数": func(in *ReviewCacheKeyInput) { in.Knowledge = in.Knowledge[:1] },
+		"ナレッジの内容": func(in *ReviewCacheKeyInput) {
+			edited := *k1
+			edited.Content = "変更"
+			in.Knowledge = []*model.Knowledge{&edited, k2}
+		},
+		"テンプレートのバージョン": func(in *ReviewCacheKeyInput) { in.PromptTemplate = &model.PromptTemplate{ID: "t1", Version: 3} },
+		"描画したプロンプトの類似度": func(in *ReviewCacheKeyInput) {
+			in.Prompt = "### [エラーハンドリング] エラー処理（類似度: 0.41）"
+		},
+		"モデル": func(in *ReviewCacheKeyInput) { in.Model = "claude-sonnet-4" },
+		"出力言語": func(in *ReviewCacheKeyInput) {
+			in.Preferences = &model.UserPreferences{OutputLanguage: model.OutputLanguageEnglish}
+		},
+		"ロケール": func(in *ReviewCacheKeyInput) { in.Locale = model.LocaleEnglish },
+	}
+	for name, change := range changes {
+		t.Run(name+"が変わるとキーが変わる", func(t *testing.T) {
+			input := base
+			change(&input)
+			assert.NotEqual(t, key, ReviewCacheKey(input))
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// 対象の行範囲が重なる改善点の組を優先するための加点（タイトルの類似度に加える）
+const improvementLineOverlapBonus = 0.1
+
+// improvementPair - 同じ指摘の候補（基準のレビューと比較するレビューの改善点の位置）
+type improvementPair struct {
+	base, other int
+	score       float64
+}
+
+// DiffReviewResults - 2つのレビュー結果の改善点を比較し、追加・解消・継続に分類する
+//
+//   - タイトルが似ている改善点（MergeModelReviews と同じ類似度）を同じ指摘とみなす
+//   - 対象のファイルがどちらにもある場合は、同じファイルの改善点のみを同じ指摘とみなす
+//   - 類似度が高い組から順に1対1で対応させ、対象の行範囲が重なる組を優先する
+//   - 改善点は各レビューでの出現順に並べる（継続は基準のレビューの順）
+func DiffReviewResults(base, other *model.StructuredReviewResult) *model.ReviewResultDiff {
+	diff := &model.ReviewResultDiff{
+		Added:      []model.Improvement{},
+		Removed:    []model.Improvement{},
+		Persisting: []model.PersistingImprovement{},
+	}
+	var baseImprovements, otherImprovements []model.Improvement
+	if base != nil {
+		baseImprovements = base.Improvements
+	}
+	if other != nil {
+		otherImprovements = other.Improvements
+	}
+
+	// 1. 同じ指摘の候補を類似度の高い順に並べる
+	otherTitles := make([]string, len(otherImprovements))
+	for j, imp := range otherImprovements {
+		otherTitles[j] = normalizeForDedupe(imp.Title)
+	}
+	var pairs []improvementPair
+	for i, b := range baseImprovements {
+		title := normalizeForDedupe(b.Title)
+		for j, o := range otherImprovements {
+			if b.FilePath != "" && o.FilePath != "" && b.FilePath != o.FilePath {
+				continue
+			}
+			score := 1.0
+			if title != otherTitles[j] {
+				score = diceCoefficient(title, otherTitles[j])
+			}
+			if score < improvementTitleSimilarity {
+				continue
+			}
+			if linesOverlap(b, o) {
+				score += improvementLineOverlapBonus
+			}
+			pairs = append(pairs, improvementPair{base: i, other: j, score: score})
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].score > pairs[j].score
+	})
+
+	// 2. 類似度の高い組から1対1で対応させる
+	matched := make(map[int]int, len(pairs)) // 基準のレビューの改善点 → 比較するレビューの改善点
+	otherMatched := make(map[int]bool, len(pairs))
+	for _, p := range pairs {
+		if _, ok := matched[p.base]; ok || otherMatched[p.other] {
+			continue
+		}
+		matched[p.base] = p.other
+		otherMatched[p.other] = true
+	}
+
+	// 3. 分類する
+	for i, b := range baseImprovements {
+		j, ok := matched[i]
+		if !ok {
+			diff.Removed = append(diff.Removed, b)
+			continue
+		}
+		o := otherImprovements[j]
+		diff.Persisting = append(diff.Persisting, model.PersistingImprovement{
+			Base:            b,
+			Other:           o,
+			SeverityChanged: b.Severity != o.Severity,
+		})
+	}
+	for j, o := range otherImprovements {
+		if !otherMatched[j] {
+			diff.Added = append(diff.Added, o)
+		}
+	}
+	return diff
+}
+
+// linesOverlap - 2つの改善点の対象の行範囲が重なるか（どちらかの行範囲が不明な場合は false）
+func linesOverlap(a, b model.Improvement) bool {
+	if a.StartLine <= 0 || b.StartLine <= 0 {
+		return false
+	}
+	return a.StartLine <= max(b.EndLine, b.StartLine) && b.StartLine <= max(a.EndLine, a.StartLine)
+}
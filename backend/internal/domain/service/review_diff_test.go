@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffReviewResults(t *testing.T) {
+	t.Run("改善点を追加・解消・継続に分類する", func(t *testing.T) {
+		base := &model.StructuredReviewResult{
+			Improvements: []model.Improvement{
+				{Title: "エラーを握りつぶしている", Description: "エラーを返す", Severity: "medium"},
+				{Title: "マジックナンバー", Description: "定数にする", Severity: "low"},
+			},
+		}
+		other := &model.StructuredReviewResult{
+			Improvements: []model.Improvement{
+				{Title: "SQLインジェクションの危険", Description: "プレースホルダを使う", Severity: "high"},
+				{Title: "エラーを握りつぶしています", Description: "呼び出し元に返す", Severity: "high"},
+			},
+		}
+
+		diff := DiffReviewResults(base, other)
+
+		require.Len(t, diff.Persisting, 1)
+		assert.Equal(t, "エラーを握りつぶしている", diff.Persisting[0].Base.Title)
+		assert.Equal(t, "エラーを握りつぶしています", diff.Persisting[0].Other.Title)
+		assert.True(t, diff.Persisting[0].SeverityChanged)
+		require.Len(t, diff.Removed, 1)
+		assert.Equal(t, "マジックナンバー", diff.Removed[0].Title)
+		require.Len(t, diff.Added, 1)
+		assert.Equal(t, "SQLインジェクションの危険", diff.Added[0].Title)
+	})
+
+	t.Run("同じタイトルの改善点は行範囲が重なるものと対応させ、別のファイルの改善点とは対応させない", func(t *testing.T) {
+		base := &model.StructuredReviewResult{
+			Improvements: []model.Improvement{
+				{Title: "Missing error check", Severity: "medium", FilePath: "a.go", StartLine: 10, EndLine: 12},
+				{Title: "Missing error check", Severity: "medium", FilePath: "a.go", StartLine: 40, EndLine: 41},
+				{Title: "Unused variable", Severity: "low", FilePath: "a.go", StartLine: 5, EndLine: 5},
+			},
+		}
+		other := &model.StructuredReviewResult{
+			Improvements: []model.Improvement{
+				{Title: "Missing error check", Severity: "medium", FilePath: "a.go", StartLine: 41, EndLine: 43},
+				{Title: "Unused variable", Severity: "low", FilePath: "b.go", StartLine: 5, EndLine: 5},
+			},
+		}
+
+		diff := DiffReviewResults(base, other)
+
+		require.Len(t, diff.Persisting, 1)
+		assert.Equal(t, 40, diff.Persisting[0].Base.StartLine)
+		assert.False(t, diff.Persisting[0].SeverityChanged)
+		require.Len(t, diff.Removed, 2)
+		assert.Equal(t, 10, diff.Removed[0].StartLine)
+		assert.Equal(t, "Unused variable", diff.Removed[1].Title)
+		require.Len(t, diff.Added, 1)
+		assert.Equal(t, "b.go", diff.Added[0].FilePath)
+	})
+
+	t.Run("改善点がない場合は空の分類を返す", func(t *testing.T) {
+		diff := DiffReviewResults(&model.StructuredReviewResult{}, nil)
+
+		assert.NotNil(t, diff.Added)
+		assert.Empty(t, diff.Added)
+		assert.Empty(t, diff.Removed)
+		assert.Empty(t, diff.Persisting)
+	})
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// ChunkReview - チャンクごとのレビュー結果
+type ChunkReview struct {
+	Chunk  CodeChunk
+	Result *model.StructuredReviewResult
+}
+
+// 重要度の並び順（高いものを先に出す）
+var severityRank = map[string]int{
+	"high":   0,
+	"medium": 1,
+	"low":    2,
+}
+
+// mergeTexts - まとめたレビュー結果の総合評価の文言
+type mergeTexts struct {
+	chunkHeader  string // チャンク数, チャンクごとの総合評価
+	chunkSummary string // 開始行, 終了行, 総合評価
+	modelHeader  string // モデル数, 一致した改善点の数, モデルごとの総合評価
+	// 差分のレビュー
+	diffHeader  string // ハンク数, ハンクごとの総合評価
+	diffSummary string // ファイルのパス, 開始行, 終了行, 総合評価
+	// 複数ファイルのレビューセッション
+	sessionHeader  string // ファイル数, 改善点の数, 重要度「高」の改善点の数, ファイルごとの総合評価
+	sessionSummary string // ファイルのパス, 改善点の数, 総合評価
+}
+
+var mergeTextsByLocale = map[string]mergeTexts{
+	model.LocaleJapanese: {
+		chunkHeader:  "ファイルを%d個のチャンクに分割してレビューしました。\n%s",
+		chunkSummary: "- %d〜%d行目: %s",
+		modelHeader:  "%d個のモデルのレビューを統合しました（%d件の改善点で複数のモデルが一致）。\n%s",
+
+		diffHeader:  "差分の%d個のハンクをレビューしました。\n%s",
+		diffSummary: "- %s %d〜%d行目: %s",
+
+		sessionHeader:  "%d個のファイルをレビューしました（改善点%d件、うち重要度「高」%d件）。\n%s",
+		sessionSummary: "- %s（改善点%d件）: %s",
+	},
+	model.LocaleEnglish: {
+		chunkHeader:  "The file was split into %d chunks for review.\n%s",
+		chunkSummary: "- Lines %d-%d: %s",
+		modelHeader:  "Merged the reviews of %d models (%d improvements reported by multiple models).\n%s",
+
+		diffHeader:  "Reviewed %d hunks of the diff.\n%s",
+		diffSummary: "- %s lines %d-%d: %s",
+
+		sessionHeader:  "Reviewed %d files (%d improvements, %d of high severity).\n%s",
+		sessionSummary: "- %s (%d improvements): %s",
+	},
+}
+
+// mergeTextsFor - ロケールの文言（未対応の場合は既定のロケール）
+func mergeTextsFor(locale string) mergeTexts {
+	return mergeTextsByLocale[model.ResolveLocale(locale)]
+}
+
+// MergeChunkReviews - チャンクごとのレビュー結果を1つのレビュー結果にまとめる（map-reduce の reduce）
+//
+//   - 良い点: 同じ内容を除いて出現順に並べる
+//   - 改善点: タイトルが同じものを1つにまとめ（重要度は高い方を採用）、重要度 → 出現順に並べ直す
+//     番号はマークダウン描画時にこの順序で振り直される
+//     差分のレビューで対象の行範囲が設定されている改善点は、同じファイル・行範囲のものだけをまとめる
+//   - 総合評価: チャンクごとの総合評価を行範囲付きで並べる（locale の言語で記述する）
+func MergeChunkReviews(reviews []ChunkReview, locale string) *model.StructuredReviewResult {
+	if len(reviews) == 1 {
+		return reviews[0].Result
+	}
+
+	merged := &model.StructuredReviewResult{
+		GoodPoints:   []string{},
+		Improvements: []model.Improvement{},
+	}
+
+	text := mergeTextsFor(locale)
+	seenGoodPoints := map[string]bool{}
+	improvementIndex := map[string]int{}
+	var summaries []string
+
+	for _, r := range reviews {
+		if r.Result == nil {
+			continue
+		}
+
+		for _, point := range r.Result.GoodPoints {
+			key := normalizeForDedupe(point)
+			if key == "" || seenGoodPoints[key] {
+				continue
+			}
+			seenGoodPoints[key] = true
+			merged.GoodPoints = append(merged.GoodPoints, point)
+		}
+
+		for _, imp := range r.Result.Improvements {
+			key := normalizeForDedupe(imp.Title)
+			if imp.FilePath != "" {
+				key = fmt.Sprintf("%s:%d-%d:%s", imp.FilePath, imp.StartLine, imp.EndLine, key)
+			}
+			i, ok := improvementIndex[key]
+			if !ok {
+				improvementIndex[key] = len(merged.Improvements)
+				merged.Improvements = append(merged.Improvements, imp)
+				continue
+			}
+			merged.Improvements[i] = mergeImprovement(merged.Improvements[i], imp)
+		}
+
+		if summary := strings.TrimSpace(r.Result.Summary); summary != "" {
+			if r.Chunk.FilePath != "" {
+				summaries = append(summaries, fmt.Sprintf(text.diffSummary, r.Chunk.FilePath, r.Chunk.StartLine, r.Chunk.EndLine, summary))
+			} else {
+				summaries = append(summaries, fmt.Sprintf(text.chunkSummary, r.Chunk.StartLine, r.Chunk.EndLine, summary))
+			}
+		}
+	}
+
+	sort.SliceStable(merged.Improvements, func(i, j int) bool {
+		return severityRank[merged.Improvements[i].Severity] < severityRank[merged.Improvements[j].Severity]
+	})
+
+	header := text.chunkHeader
+	if reviews[0].Chunk.FilePath != "" {
+		header = text.diffHeader
+	}
+	merged.Summary = fmt.Sprintf(header, len(reviews), strings.Join(summaries, "\n"))
+
+	return merged
+}
+
+// mergeImprovement - 重複した改善点をまとめる（重要度は高い方、説明は異なる場合のみ追記）
+func mergeImprovement(kept, dup model.Improvement) model.Improvement {
+	if severityRank[dup.Severity] < severityRank[kept.Severity] {
+		kept.Severity = dup.Severity
+	}
+	if normalizeForDedupe(dup.Description) != normalizeForDedupe(kept.Description) {
+		kept.Description = strings.TrimRight(kept.Description, "\n") + "\n" + dup.Description
+	}
+	if kept.CodeAfter == "" {
+		kept.CodeAfter = dup.CodeAfter
+	}
+	return kept
+}
+
+// normalizeForDedupe - 重複判定用に空白・記号・大文字小文字の違いを除く
+func normalizeForDedupe(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ModelReview - モデルごとのレビュー結果
+type ModelReview struct {
+	Model  string // "provider:model"
+	Result *model.StructuredReviewResult
+}
+
+// 改善点を同じ指摘とみなすタイトルの類似度（文字bigramのDice係数）
+const improvementTitleSimilarity = 0.5
+
+// MergeModelReviews - 同じコードに対する複数モデルのレビュー結果を1つにまとめる
+//
+//   - 良い点: 同じ内容を除いて出現順に並べる
+//   - 改善点: タイトルが似ているものを同じ指摘としてまとめ、指摘したモデルを AgreedBy に記録する
+//     2つ以上のモデルが指摘したもの（Consensus）→ 重要度 → 出現順に並べ直す
+//   - 総合評価: モデルごとの総合評価を並べる（locale の言語で記述する）
+func MergeModelReviews(reviews []ModelReview, locale string) *model.StructuredReviewResult {
+	merged := &model.StructuredReviewResult{
+		GoodPoints:   []string{},
+		Improvements: []model.Improvement{},
+	}
+
+	seenGoodPoints := map[string]bool{}
+	var titles []string
+	var summaries []string
+
+	for _, r := range reviews {
+		if r.Result == nil {
+			continue
+		}
+
+		for _, point := range r.Result.GoodPoints {
+			key := normalizeForDedupe(point)
+			if key == "" || seenGoodPoints[key] {
+				continue
+			}
+			seenGoodPoints[key] = true
+			merged.GoodPoints = append(merged.GoodPoints, point)
+		}
+
+		for _, imp := range r.Result.Improvements {
+			key := normalizeForDedupe(imp.Title)
+			i := findSimilarImprovement(merged.Improvements, titles, key, r.Model)
+			if i < 0 {
+				imp.AgreedBy = []string{r.Model}
+				titles = append(titles, key)
+				merged.Improvements = append(merged.Improvements, imp)
+				continue
+			}
+			merged.Improvements[i] = agreeImprovement(merged.Improvements[i], imp, r.Model)
+		}
+
+		if summary := strings.TrimSpace(r.Result.Summary); summary != "" {
+			summaries = append(summaries, fmt.Sprintf("- %s: %s", r.Model, summary))
+		}
+	}
+
+	consensus := 0
+	for i := range merged.Improvements {
+		merged.Improvements[i].Consensus = len(merged.Improvements[i].AgreedBy) > 1
+		if merged.Improvements[i].Consensus {
+			consensus++
+		}
+	}
+
+	sort.SliceStable(merged.Improvements, func(i, j int) bool {
+		a, b := merged.Improvements[i], merged.Improvements[j]
+		if len(a.AgreedBy) != len(b.AgreedBy) {
+			return len(a.AgreedBy) > len(b.AgreedBy)
+		}
+		return severityRank[a.Severity] < severityRank[b.Severity]
+	})
+
+	merged.Summary = fmt.Sprintf(mergeTextsFor(locale).modelHeader, len(reviews), consensus, strings.Join(summaries, "\n"))
+
+	return merged
+}
+
+// agreeImprovement - 別のモデルの同じ指摘をまとめる（重要度は高い方、説明は先に指摘したモデルのものを残す）
+func agreeImprovement(kept, dup model.Improvement, modelName string) model.Improvement {
+	if severityRank[dup.Severity] < severityRank[kept.Severity] {
+		kept.Severity = dup.Severity
+	}
+	if kept.CodeAfter == "" {
+		kept.CodeAfter = dup.CodeAfter
+	}
+	kept.AgreedBy = append(kept.AgreedBy, modelName)
+	return kept
+}
+
+// findSimilarImprovement - key（正規化済みのタイトル）と同じ指摘とみなせる改善点の位置を返す（ない場合は -1）
+// 同じモデルの改善点どうしは別の指摘として扱うため、modelName が指摘済みのものは対象外
+func findSimilarImprovement(improvements []model.Improvement, titles []string, key, modelName string) int {
+	best, bestScore := -1, improvementTitleSimilarity
+	for i, title := range titles {
+		if slices.Contains(improvements[i].AgreedBy, modelName) {
+			continue
+		}
+		if title == key {
+			return i
+		}
+		if score := diceCoefficient(title, key); score >= bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// diceCoefficient - 文字bigramのDice係数（0〜1）
+// モデルによって言い回しが違っても、同じ指摘のタイトルは共通の語を多く含む
+func diceCoefficient(a, b string) float64 {
+	bigramsA, bigramsB := charBigrams(a), charBigrams(b)
+	if len(bigramsA) == 0 || len(bigramsB) == 0 {
+		return 0
+	}
+
+	counts := map[string]int{}
+	for _, bigram := range bigramsA {
+		counts[bigram]++
+	}
+	shared := 0
+	for _, bigram := range bigramsB {
+		if counts[bigram] > 0 {
+			counts[bigram]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(bigramsA)+len(bigramsB))
+}
+
+// charBigrams - 隣り合う2文字の組を返す
+func charBigrams(text string) []string {
+	runes := []rune(text)
+	if len(runes) < 2 {
+		return nil
+	}
+	bigrams := make([]string, 0, len(runes)-1)
+	for i := 0; i+1 < len(runes); i++ {
+		bigrams = append(bigrams, string(runes[i:i+2]))
+	}
+	return bigrams
+}
+
+// SessionFileReview - 複数ファイルのレビューセッションのファイルごとのレビュー結果
+type SessionFileReview struct {
+	FilePath string
+	Result   *model.StructuredReviewResult
+}
+
+// SummarizeSession - ファイルごとのレビュー結果からセッション全体の総合評価を作る（locale の言語で記述する）
+// 改善点はファイルごとのレビューに残すため、まとめるのは総合評価と改善点の数のみ
+func SummarizeSession(files []SessionFileReview, locale string) string {
+	text := mergeTextsFor(locale)
+	var total model.ImprovementCounts
+	summaries := make([]string, 0, len(files))
+	for _, f := range files {
+		counts := model.CountImprovements(f.Result)
+		total.Add(counts)
+
+		summary := ""
+		if f.Result != nil {
+			summary = strings.Join(strings.Fields(f.Result.Summary), " ")
+		}
+		summaries = append(summaries, fmt.Sprintf(text.sessionSummary, f.FilePath, counts.Total, summary))
+	}
+
+	return fmt.Sprintf(text.sessionHeader, len(files), total.Total, total.High, strings.Join(summaries, "\n"))
+}
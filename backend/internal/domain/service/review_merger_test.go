@@ -0,0 +1,181 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeChunkReviews(t *testing.T) {
+	t.Run("1チャンクの場合はそのまま返す", func(t *testing.T) {
+		result := &model.StructuredReviewResult{Summary: "良好"}
+
+		merged := MergeChunkReviews([]ChunkReview{{Chunk: CodeChunk{StartLine: 1, EndLine: 10}, Result: result}}, model.DefaultLocale)
+
+		assert.Same(t, result, merged)
+	})
+
+	t.Run("改善点の重複を除き、重要度順に並べ直す", func(t *testing.T) {
+		merged := MergeChunkReviews([]ChunkReview{
+			{
+				Chunk: CodeChunk{Index: 0, StartLine: 1, EndLine: 100},
+				Result: &model.StructuredReviewResult{
+					Summary:    "前半は読みやすい",
+					GoodPoints: []string{"命名が明確", "テストしやすい構造"},
+					Improvements: []model.Improvement{
+						{Title: "マジックナンバー", Description: "定数にする", Severity: "low"},
+						{Title: "エラーを握りつぶしている", Description: "エラーを返す", Severity: "medium"},
+					},
+				},
+			},
+			{
+				Chunk: CodeChunk{Index: 1, StartLine: 101, EndLine: 180},
+				Result: &model.StructuredReviewResult{
+					Summary:    "後半は関数が長い",
+					GoodPoints: []string{"命名が明確。"},
+					Improvements: []model.Improvement{
+						{Title: "エラーを握りつぶしている ", Description: "ログだけで終わらせない", CodeAfter: "return err", Severity: "high"},
+						{Title: "関数が長い", Description: "分割する", Severity: "medium"},
+					},
+				},
+			},
+		}, model.DefaultLocale)
+
+		require.NotNil(t, merged)
+		assert.NoError(t, merged.Validate())
+		assert.Equal(t, []string{"命名が明確", "テストしやすい構造"}, merged.GoodPoints)
+
+		require.Len(t, merged.Improvements, 3)
+		assert.Equal(t, "エラーを握りつぶしている", merged.Improvements[0].Title)
+		assert.Equal(t, "high", merged.Improvements[0].Severity)
+		assert.Equal(t, "エラーを返す\nログだけで終わらせない", merged.Improvements[0].Description)
+		assert.Equal(t, "return err", merged.Improvements[0].CodeAfter)
+		assert.Equal(t, "関数が長い", merged.Improvements[1].Title)
+		assert.Equal(t, "マジックナンバー", merged.Improvements[2].Title)
+
+		assert.Contains(t, merged.Summary, "2個のチャンク")
+		assert.Contains(t, merged.Summary, "- 1〜100行目: 前半は読みやすい")
+		assert.Contains(t, merged.Summary, "- 101〜180行目: 後半は関数が長い")
+	})
+
+	t.Run("英語のロケールでは総合評価を英語でまとめる", func(t *testing.T) {
+		merged := MergeChunkReviews([]ChunkReview{
+			{Chunk: CodeChunk{StartLine: 1, EndLine: 50}, Result: &model.StructuredReviewResult{Summary: "Readable"}},
+			{Chunk: CodeChunk{StartLine: 51, EndLine: 90}, Result: &model.StructuredReviewResult{Summary: "Too long"}},
+		}, model.LocaleEnglish)
+
+		assert.Equal(t, "The file was split into 2 chunks for review.\n- Lines 1-50: Readable\n- Lines 51-90: Too long", merged.Summary)
+	})
+}
+
+func TestMergeModelReviews(t *testing.T) {
+	t.Run("複数のモデルが指摘した改善点をまとめ、一致したものを先頭に並べる", func(t *testing.T) {
+		merged := MergeModelReviews([]ModelReview{
+			{
+				Model: "anthropic:claude-3-5-haiku-latest",
+				Result: &model.StructuredReviewResult{
+					Summary:    "全体的に読みやすい",
+					GoodPoints: []string{"命名が明確"},
+					Improvements: []model.Improvement{
+						{Title: "マジックナンバー", Description: "定数にする", Severity: "low"},
+						{Title: "エラーを握りつぶしている", Description: "エラーを返す", Severity: "medium"},
+					},
+				},
+			},
+			{
+				Model: "ollama:qwen2.5-coder:7b",
+				Result: &model.StructuredReviewResult{
+					Summary:    "エラー処理に問題がある",
+					GoodPoints: []string{"命名が明確。", "関数が短い"},
+					Improvements: []model.Improvement{
+						{Title: "関数が長い", Description: "分割する", Severity: "high"},
+						{Title: "エラーを握りつぶさない", Description: "ログだけで終わらせない", CodeAfter: "return err", Severity: "high"},
+					},
+				},
+			},
+		}, model.DefaultLocale)
+
+		require.NotNil(t, merged)
+		assert.NoError(t, merged.Validate())
+		assert.Equal(t, []string{"命名が明確", "関数が短い"}, merged.GoodPoints)
+
+		require.Len(t, merged.Improvements, 3)
+		assert.Equal(t, "エラーを握りつぶしている", merged.Improvements[0].Title)
+		assert.Equal(t, "エラーを返す", merged.Improvements[0].Description)
+		assert.Equal(t, "return err", merged.Improvements[0].CodeAfter)
+		assert.Equal(t, "high", merged.Improvements[0].Severity)
+		assert.Equal(t, []string{"anthropic:claude-3-5-haiku-latest", "ollama:qwen2.5-coder:7b"}, merged.Improvements[0].AgreedBy)
+		assert.True(t, merged.Improvements[0].Consensus)
+
+		assert.Equal(t, "関数が長い", merged.Improvements[1].Title)
+		assert.Equal(t, []string{"ollama:qwen2.5-coder:7b"}, merged.Improvements[1].AgreedBy)
+		assert.False(t, merged.Improvements[1].Consensus)
+		assert.Equal(t, "マジックナンバー", merged.Improvements[2].Title)
+
+		assert.Contains(t, merged.Summary, "2個のモデル")
+		assert.Contains(t, merged.Summary, "1件の改善点で複数のモデルが一致")
+		assert.Contains(t, merged.Summary, "- ollama:qwen2.5-coder:7b: エラー処理に問題がある")
+	})
+
+	t.Run("同じモデルの似た改善点はまとめない", func(t *testing.T) {
+		merged := MergeModelReviews([]ModelReview{
+			{
+				Model: "a:model",
+				Result: &model.StructuredReviewResult{
+					Summary: "a",
+					Improvements: []model.Improvement{
+						{Title: "変数名が短い", Description: "x を count にする", Severity: "low"},
+						{Title: "変数名が短い（ループ）", Description: "i を index にする", Severity: "low"},
+					},
+				},
+			},
+			{
+				Model: "b:model",
+				Result: &model.StructuredReviewResult{
+					Summary:      "b",
+					Improvements: []model.Improvement{{Title: "変数名が短い", Description: "略さない", Severity: "low"}},
+				},
+			},
+		}, model.DefaultLocale)
+
+		require.Len(t, merged.Improvements, 2)
+		assert.Equal(t, []string{"a:model", "b:model"}, merged.Improvements[0].AgreedBy)
+		assert.Equal(t, []string{"a:model"}, merged.Improvements[1].AgreedBy)
+	})
+}
+
+func TestSummarizeSession(t *testing.T) {
+	files := []SessionFileReview{
+		{
+			FilePath: "internal/user/user.go",
+			Result: &model.StructuredReviewResult{
+				Summary: "構造体の設計は明確\nエラー処理に課題",
+				Improvements: []model.Improvement{
+					{Title: "エラーを握りつぶしている", Description: "エラーを返す", Severity: "high"},
+					{Title: "マジックナンバー", Description: "定数にする", Severity: "low"},
+				},
+			},
+		},
+		{
+			FilePath: "internal/user/handler.go",
+			Result:   &model.StructuredReviewResult{Summary: "問題なし"},
+		},
+	}
+
+	t.Run("ファイルごとの総合評価と改善点の数をまとめる", func(t *testing.T) {
+		summary := SummarizeSession(files, model.DefaultLocale)
+
+		assert.Contains(t, summary, "2個のファイルをレビューしました（改善点2件、うち重要度「高」1件）")
+		assert.Contains(t, summary, "- internal/user/user.go（改善点2件）: 構造体の設計は明確 エラー処理に課題")
+		assert.Contains(t, summary, "- internal/user/handler.go（改善点0件）: 問題なし")
+	})
+
+	t.Run("英語のロケールでは英語で記述する", func(t *testing.T) {
+		summary := SummarizeSession(files, model.LocaleEnglish)
+
+		assert.Contains(t, summary, "Reviewed 2 files (2 improvements, 1 of high severity).")
+		assert.Contains(t, summary, "- internal/user/handler.go (0 improvements): 問題なし")
+	})
+}
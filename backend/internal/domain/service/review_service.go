@@ -17,9 +17,13 @@ func NewReviewService() *ReviewService {
 }
 
 // BuildPromptFromKnowledge - ナレッジからプロンプトを生成し、実際に使用したナレッジを返す
-func (s *ReviewService) BuildPromptFromKnowledge(knowledges []*model.Knowledge) (string, []*model.Knowledge) {
+// カテゴリ名・ナレッジがない場合の文言は locale の言語にする
+func (s *ReviewService) BuildPromptFromKnowledge(knowledges []*model.Knowledge, locale string) (string, []*model.Knowledge) {
 	// ナレッジが存在しない場合
 	if len(knowledges) == 0 {
+		if model.ResolveLocale(locale) == model.LocaleEnglish {
+			return "Review the code based on general best practices.", []*model.Knowledge{}
+		}
 		return "一般的なベストプラクティスに基づいてレビューしてください。", []*model.Knowledge{}
 	}
 
@@ -46,29 +50,35 @@ func (s *ReviewService) BuildPromptFromKnowledge(knowledges []*model.Knowledge)
 		k := sortedKnowledges[i]
 		usedKnowledges[i] = k
 
-		categoryName := s.getCategoryName(k.Category)
-		sb.WriteString(fmt.Sprintf("### [%s] %s\n", categoryName, k.Title))
-		sb.WriteString(fmt.Sprintf("%s\n\n", k.Content))
+		categoryName := s.getCategoryName(k.Category, locale)
+		sb.WriteString(fmt.Sprintf("### [%s] %s\n", categoryName, SanitizePromptLine(k.Title)))
+		sb.WriteString(WrapUntrusted(KnowledgeTag, k.Content) + "\n\n")
 	}
 
 	return sb.String(), usedKnowledges
 }
 
-// getCategoryName - カテゴリIDを日本語名に変換
+// getCategoryName - カテゴリIDをロケールの表示名に変換
+func (s *ReviewService) getCategoryName(category, locale string) string {
+	return categoryDisplayName(category, locale)
+}
+
+// categoryDisplayNames - カテゴリIDとロケールごとの表示名
 // TODO DBからマッピングを取得するよう修正
-func (s *ReviewService) getCategoryName(category string) string {
-	categoryMap := map[string]string{
-		"error_handling": "エラーハンドリング",
-		"testing":        "テスト",
-		"performance":    "パフォーマンス",
-		"security":       "セキュリティ",
-		"clean_code":     "クリーンコード",
-		"architecture":   "アーキテクチャ",
-		"other":          "その他",
-	}
+var categoryDisplayNames = map[string]map[string]string{
+	"error_handling": {model.LocaleJapanese: "エラーハンドリング", model.LocaleEnglish: "Error handling"},
+	"testing":        {model.LocaleJapanese: "テスト", model.LocaleEnglish: "Testing"},
+	"performance":    {model.LocaleJapanese: "パフォーマンス", model.LocaleEnglish: "Performance"},
+	"security":       {model.LocaleJapanese: "セキュリティ", model.LocaleEnglish: "Security"},
+	"clean_code":     {model.LocaleJapanese: "クリーンコード", model.LocaleEnglish: "Clean code"},
+	"architecture":   {model.LocaleJapanese: "アーキテクチャ", model.LocaleEnglish: "Architecture"},
+	"other":          {model.LocaleJapanese: "その他", model.LocaleEnglish: "Other"},
+}
 
-	if name, ok := categoryMap[category]; ok {
-		return name
+// categoryDisplayName - カテゴリIDをロケールの表示名に変換（未知のカテゴリはIDのまま）
+func categoryDisplayName(category, locale string) string {
+	if names, ok := categoryDisplayNames[category]; ok {
+		return names[model.ResolveLocale(locale)]
 	}
 	return category
 }
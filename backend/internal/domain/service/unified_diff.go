@@ -0,0 +1,201 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// unifiedDiffContext - unified diff のハンクの前後に含める変更していない行の数
+const unifiedDiffContext = 3
+
+// UnifiedDiff - 変更前後のコードの unified diff（git diff と同じ形式。変更がない場合は空文字列）
+// path はヘッダー（--- a/path, +++ b/path）に使うファイルのパス
+func UnifiedDiff(path, before, after string) string {
+	if before == after {
+		return ""
+	}
+	a, b := splitLinesKeepEnd(before), splitLinesKeepEnd(after)
+	ops := diffLines(a, b)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%s\n+++ b/%s\n", path, path)
+	for _, hunk := range groupHunks(ops, unifiedDiffContext) {
+		writeHunk(&sb, hunk, a, b)
+	}
+	return sb.String()
+}
+
+// diffOp - 行の編集操作（aIndex / bIndex は変更前・変更後の行の番号（0始まり））
+// 追加の aIndex・削除の bIndex は、その位置までに処理した行数
+type diffOp struct {
+	kind   byte // ' ': 変更なし / '-': 削除 / '+': 追加
+	aIndex int
+	bIndex int
+}
+
+// splitLinesKeepEnd - 改行を含めて行に分割する（最終行に改行がない場合は改行なしの行になる）
+func splitLinesKeepEnd(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines - Myers のアルゴリズムで最短の編集操作の列を求める
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+
+	// 各ステップの開始時点の v（k = -d〜d）を残し、最後に逆にたどって編集操作を求める
+	var trace [][]int
+	last := 0
+search:
+	for d := 0; d <= max; d++ {
+		snapshot := make([]int, 2*d+1)
+		copy(snapshot, v[offset-d:offset+d+1])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				last = d
+				break search
+			}
+		}
+	}
+
+	var reversed []diffOp
+	x, y := n, m
+	for d := last; d > 0; d-- {
+		snapshot := trace[d]
+		at := func(k int) int { return snapshot[k+d] }
+
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, diffOp{kind: ' ', aIndex: x - 1, bIndex: y - 1})
+			x, y = x-1, y-1
+		}
+		if x == prevX {
+			reversed = append(reversed, diffOp{kind: '+', aIndex: x, bIndex: y - 1})
+		} else {
+			reversed = append(reversed, diffOp{kind: '-', aIndex: x - 1, bIndex: y})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		reversed = append(reversed, diffOp{kind: ' ', aIndex: x - 1, bIndex: y - 1})
+		x, y = x-1, y-1
+	}
+
+	ops := make([]diffOp, len(reversed))
+	for i, op := range reversed {
+		ops[len(reversed)-1-i] = op
+	}
+	return ops
+}
+
+// groupHunks - 変更の前後 context 行を含めてハンクにまとめる（間の変更していない行が 2*context 行以下なら同じハンク）
+func groupHunks(ops []diffOp, context int) [][]diffOp {
+	var hunks [][]diffOp
+	i := 0
+	for i < len(ops) {
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(ops); {
+			if ops[j].kind != ' ' {
+				end = j
+				j++
+				continue
+			}
+			k := j
+			for k < len(ops) && ops[k].kind == ' ' {
+				k++
+			}
+			if k == len(ops) || k-j > 2*context {
+				break
+			}
+			j = k
+		}
+		stop := end + context + 1
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+		hunks = append(hunks, ops[start:stop])
+		i = stop
+	}
+	return hunks
+}
+
+// writeHunk - ハンクのヘッダー（@@ -start,count +start,count @@）と行を書き出す
+func writeHunk(sb *strings.Builder, hunk []diffOp, a, b []string) {
+	oldCount, newCount := 0, 0
+	for _, op := range hunk {
+		if op.kind != '+' {
+			oldCount++
+		}
+		if op.kind != '-' {
+			newCount++
+		}
+	}
+	oldStart, newStart := hunk[0].aIndex, hunk[0].bIndex
+	if oldCount > 0 {
+		oldStart++
+	}
+	if newCount > 0 {
+		newStart++
+	}
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+
+	for _, op := range hunk {
+		line := b[op.bIndex]
+		if op.kind == '-' {
+			line = a[op.aIndex]
+		}
+		sb.WriteByte(op.kind)
+		sb.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange - ハンクのヘッダーの行範囲（1行の場合は行数を省略する）
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
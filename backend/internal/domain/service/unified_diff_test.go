@@ -0,0 +1,29 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	before := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"a\")\n\tfmt.Println(\"b\")\n\tfmt.Println(\"c\")\n\tfmt.Println(\"d\")\n\tfmt.Println(\"e\")\n\tfmt.Println(\"f\")\n\tfmt.Println(\"g\")\n\tfmt.Println(\"h\")\n}\n"
+	after := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"A\")\n\tfmt.Println(\"b\")\n\tfmt.Println(\"c\")\n\tfmt.Println(\"d\")\n\tfmt.Println(\"e\")\n\tfmt.Println(\"f\")\n\tfmt.Println(\"g\")\n\tfmt.Println(\"h\")\n\tfmt.Println(\"i\")\n}"
+
+	diff := UnifiedDiff("cmd/main.go", before, after)
+
+	assert.Equal(t, "--- a/cmd/main.go\n+++ b/cmd/main.go\n"+
+		"@@ -3,7 +3,7 @@\n import \"fmt\"\n \n func main() {\n-\tfmt.Println(\"a\")\n+\tfmt.Println(\"A\")\n \tfmt.Println(\"b\")\n \tfmt.Println(\"c\")\n \tfmt.Println(\"d\")\n"+
+		"@@ -11,4 +11,5 @@\n \tfmt.Println(\"f\")\n \tfmt.Println(\"g\")\n \tfmt.Println(\"h\")\n-}\n+\tfmt.Println(\"i\")\n+}\n\\ No newline at end of file\n", diff)
+
+	// 差分のレビュー（RV-001 の diff）にそのまま使える
+	files, err := ParseUnifiedDiff(diff)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "cmd/main.go", files[0].NewPath)
+	assert.Len(t, files[0].Hunks, 2)
+
+	assert.Empty(t, UnifiedDiff("main.go", before, before))
+	assert.Equal(t, "--- a/main.go\n+++ b/main.go\n@@ -0,0 +1,2 @@\n+a\n+b\n", UnifiedDiff("main.go", "", "a\nb\n"))
+}
@@ -4,18 +4,28 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config - アプリケーション全体の設定
 type Config struct {
-	Env      string
-	Server   ServerConfig
-	Database DatabaseConfig
-	Auth     AuthConfig
-	LLM      LLMConfig
-	Redis    RedisConfig
-	Features FeatureFlags
+	Env           string
+	Server        ServerConfig
+	Database      DatabaseConfig
+	Auth          AuthConfig
+	LLM           LLMConfig
+	Redis         RedisConfig
+	Embedding     EmbeddingConfig
+	Pricing       PricingConfig
+	Quota         QuotaConfig
+	Trash         TrashConfig
+	Chunking      ChunkingConfig
+	ReviewCache   ReviewCacheConfig
+	ReviewSession ReviewSessionConfig
+	ReviewJob     ReviewJobConfig
+	Conversation  ConversationConfig
+	Features      FeatureFlags
 }
 
 // ServerConfig - サーバー設定
@@ -44,17 +54,53 @@ type AuthConfig struct {
 	Audience     string
 	ClientID     string
 	ClientSecret string
+	AdminSubs    []string // 管理者APIを利用できるAuth0ユーザーID（sub）
 }
 
 // LLMConfig - LLM設定
 type LLMConfig struct {
+	Provider        string        // レビューに使うプロバイダ（anthropic, openai, ollama, openai_compatible）
+	Timeout         time.Duration // レビュー生成のタイムアウト
+	StreamTimeout   time.Duration // ストリーミングレビューのタイムアウト
+	MaxTokens       int           // OpenAI / ローカルモデルの最大出力トークン数
 	ClaudeAPIKey    string
 	ClaudeModel     string
 	ClaudeMaxTokens int
 	OpenAIAPIKey    string
+	OpenAIBaseURL   string
+	OpenAIChatModel string
 	OpenAIEmbedding string
 	EmbeddingDim    int
 	OpenAITimeout   time.Duration
+	LocalBaseURL    string // Ollama / llama.cpp などOpenAI互換サーバーのURL
+	LocalModel      string
+	LocalAPIKey     string
+
+	// 外部API呼び出しのリトライ・サーキットブレーカー
+	MaxRetries              int           // 429 / 5xx / 通信エラー時の最大リトライ回数
+	RetryBaseDelay          time.Duration // 指数バックオフの基準待機時間
+	RetryMaxDelay           time.Duration // バックオフの上限
+	BreakerFailureThreshold int           // サーキットを開く連続失敗回数
+	BreakerOpenTimeout      time.Duration // サーキットを開いてから再試行するまでの時間
+
+	// 外部APIとのやり取りの記録・再生（開発・テスト用）
+	CassetteMode         string   // off / record / replay
+	CassetteDir          string   // 記録ファイルの保存先（プロバイダごとに <provider>.json）
+	CassetteIgnoreFields []string // リクエストの照合で比較しないJSONのフィールド
+
+	// 複数モデルによるレビュー
+	EnsembleModels      []string // 比較に使えるモデル（"provider:model" のカンマ区切り。例: anthropic:claude-3-5-haiku-latest）
+	EnsembleConcurrency int      // 並行してレビューするモデル数（0はすべて同時）
+}
+
+// EmbeddingConfig - Embeddingの生成・キャッシュ・まとめての生成の設定
+type EmbeddingConfig struct {
+	Provider    string        // Embeddingの生成方法（openai / hashing）
+	Cache       string        // キャッシュの保存先（memory / redis / none）
+	CacheSize   int           // memory の場合の最大件数
+	CacheTTL    time.Duration // redis の場合の有効期間（0は期限なし）
+	BatchWindow time.Duration // 同時に届いた1件ずつの生成をまとめる待ち時間（0はまとめない）
+	BatchSize   int           // 1回にまとめる最大件数
 }
 
 // RedisConfig - Redis設定
@@ -64,6 +110,56 @@ type RedisConfig struct {
 	DB       int
 }
 
+// PricingConfig - コスト計算の料金表設定
+type PricingConfig struct {
+	Currency   string // 料金表の通貨
+	PriceTable string // モデルごとの料金（JSON）。組み込みの料金表に上書きする
+}
+
+// QuotaConfig - 利用上限設定（ユーザー個別の上限は user_quotas テーブルで上書き）
+type QuotaConfig struct {
+	ReviewsPerDay  int  // 1日あたりのレビュー数（0は無制限）
+	TokensPerMonth int  // 1か月あたりのトークン数（0は無制限）
+	UseRedis       bool // 消費量のカウンターにRedisを使う（接続できない場合はPostgreSQLで集計）
+}
+
+// TrashConfig - 削除したレビュー（ゴミ箱）の設定
+type TrashConfig struct {
+	Retention     time.Duration // ゴミ箱に残す期間（過ぎたレビューは内容を完全削除する。利用上限・使用量の履歴として使用量の列は行ごと残す）
+	PurgeInterval time.Duration // 保持期間を過ぎたレビューを完全削除する間隔（0は完全削除しない）
+}
+
+// ChunkingConfig - 大きなファイル・差分の分割レビューの設定
+type ChunkingConfig struct {
+	MaxTokens    int // 1回のレビュー・Embeddingに含めるコードの最大トークン数（超える場合は分割）
+	Concurrency  int // チャンクを並行してレビューする数
+	DiffMaxHunks int // 差分のレビューでレビューする最大ハンク数（超える場合はエラー）
+}
+
+// ReviewCacheConfig - レビュー結果のキャッシュの設定
+type ReviewCacheConfig struct {
+	TTL time.Duration // 同じ入力のレビュー結果を再利用する期間（0はキャッシュしない）
+}
+
+// ReviewSessionConfig - 複数ファイルのレビューセッションの設定
+type ReviewSessionConfig struct {
+	MaxFiles    int // 1回のセッションでレビューできる最大ファイル数
+	Concurrency int // ファイルを並行してレビューする数
+}
+
+// ReviewJobConfig - 非同期のレビュー（POST /api/v1/reviews?async=true）の設定
+type ReviewJobConfig struct {
+	Workers      int           // 並行して実行するレビュー数
+	Timeout      time.Duration // 1回の実行の制限時間（過ぎても実行中のままのレビューは実行待ちに戻す）
+	PollInterval time.Duration // 他のサーバーが受け付けたレビュー・止まったレビューを確認する間隔
+}
+
+// ConversationConfig - レビューについての会話の設定（Features.ConversationMode が有効な場合）
+type ConversationConfig struct {
+	MaxMessages     int // 1つのレビューで保存できる最大メッセージ数（質問と返答の合計）
+	HistoryMessages int // LLMに渡す直近の会話のメッセージ数
+}
+
 // FeatureFlags - 機能フラグ
 type FeatureFlags struct {
 	VectorSearch         bool
@@ -97,21 +193,86 @@ func Load() (*Config, error) {
 			Audience:     getEnv("AUTH0_AUDIENCE", ""),
 			ClientID:     getEnv("AUTH0_CLIENT_ID", ""),
 			ClientSecret: getEnv("AUTH0_CLIENT_SECRET", ""),
+			AdminSubs:    getEnvAsSlice("ADMIN_AUTH0_SUBS"),
 		},
 		LLM: LLMConfig{
+			Provider:        getEnv("LLM_PROVIDER", "anthropic"),
+			Timeout:         getEnvAsDuration("LLM_TIMEOUT", "30s"),
+			StreamTimeout:   getEnvAsDuration("LLM_STREAM_TIMEOUT", "5m"),
+			MaxTokens:       getEnvAsInt("LLM_MAX_TOKENS", 4096),
 			ClaudeAPIKey:    getEnv("CLAUDE_API_KEY", ""),
 			ClaudeModel:     getEnv("CLAUDE_MODEL", "claude-3-5-haiku-latest"),
 			ClaudeMaxTokens: getEnvAsInt("CLAUDE_MAX_TOKENS", 4096),
 			OpenAIAPIKey:    getEnv("OPENAI_API_KEY", ""),
+			OpenAIBaseURL:   getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
+			OpenAIChatModel: getEnv("OPENAI_CHAT_MODEL", "gpt-4o-mini"),
 			OpenAIEmbedding: getEnv("OPENAI_EMBEDDING_MODEL", "text-embedding-3-small"),
 			EmbeddingDim:    getEnvAsInt("OPENAI_EMBEDDING_DIMENSIONS", 1536),
 			OpenAITimeout:   getEnvAsDuration("OPENAI_API_TIMEOUT", "30s"),
+			LocalBaseURL:    getEnv("LLM_LOCAL_BASE_URL", "http://localhost:11434/v1"),
+			LocalModel:      getEnv("LLM_LOCAL_MODEL", "qwen2.5-coder:7b"),
+			LocalAPIKey:     getEnv("LLM_LOCAL_API_KEY", ""),
+
+			MaxRetries:              getEnvAsInt("LLM_MAX_RETRIES", 3),
+			RetryBaseDelay:          getEnvAsDuration("LLM_RETRY_BASE_DELAY", "500ms"),
+			RetryMaxDelay:           getEnvAsDuration("LLM_RETRY_MAX_DELAY", "10s"),
+			BreakerFailureThreshold: getEnvAsInt("LLM_BREAKER_FAILURE_THRESHOLD", 5),
+			BreakerOpenTimeout:      getEnvAsDuration("LLM_BREAKER_OPEN_TIMEOUT", "30s"),
+
+			CassetteMode:         getEnv("LLM_CASSETTE_MODE", "off"),
+			CassetteDir:          getEnv("LLM_CASSETTE_DIR", "testdata/cassettes"),
+			CassetteIgnoreFields: getEnvAsSlice("LLM_CASSETTE_IGNORE_FIELDS"),
+
+			EnsembleModels:      getEnvAsSlice("LLM_ENSEMBLE_MODELS"),
+			EnsembleConcurrency: getEnvAsInt("LLM_ENSEMBLE_CONCURRENCY", 0),
+		},
+		Embedding: EmbeddingConfig{
+			Provider:    getEnv("EMBEDDING_PROVIDER", "openai"),
+			Cache:       getEnv("EMBEDDING_CACHE", "memory"),
+			CacheSize:   getEnvAsInt("EMBEDDING_CACHE_SIZE", 10000),
+			CacheTTL:    getEnvAsDuration("EMBEDDING_CACHE_TTL", "720h"),
+			BatchWindow: getEnvAsDuration("EMBEDDING_BATCH_WINDOW", "10ms"),
+			BatchSize:   getEnvAsInt("EMBEDDING_BATCH_SIZE", 64),
 		},
 		Redis: RedisConfig{
 			URL:      getEnv("REDIS_URL", "redis://localhost:6379"),
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
+		Pricing: PricingConfig{
+			Currency:   getEnv("PRICING_CURRENCY", "USD"),
+			PriceTable: getEnv("LLM_PRICE_TABLE", ""),
+		},
+		Quota: QuotaConfig{
+			ReviewsPerDay:  getEnvAsInt("QUOTA_REVIEWS_PER_DAY", 0),
+			TokensPerMonth: getEnvAsInt("QUOTA_TOKENS_PER_MONTH", 0),
+			UseRedis:       getEnvAsBool("QUOTA_USE_REDIS", false),
+		},
+		Trash: TrashConfig{
+			Retention:     getEnvAsDuration("REVIEW_TRASH_RETENTION", "744h"),
+			PurgeInterval: getEnvAsDuration("REVIEW_PURGE_INTERVAL", "1h"),
+		},
+		Chunking: ChunkingConfig{
+			MaxTokens:    getEnvAsInt("REVIEW_CHUNK_MAX_TOKENS", 6000),
+			Concurrency:  getEnvAsInt("REVIEW_CHUNK_CONCURRENCY", 4),
+			DiffMaxHunks: getEnvAsInt("REVIEW_DIFF_MAX_HUNKS", 50),
+		},
+		ReviewCache: ReviewCacheConfig{
+			TTL: getEnvAsDuration("REVIEW_CACHE_TTL", "24h"),
+		},
+		ReviewSession: ReviewSessionConfig{
+			MaxFiles:    getEnvAsInt("REVIEW_SESSION_MAX_FILES", 20),
+			Concurrency: getEnvAsInt("REVIEW_SESSION_CONCURRENCY", 4),
+		},
+		ReviewJob: ReviewJobConfig{
+			Workers:      getEnvAsInt("REVIEW_JOB_WORKERS", 4),
+			Timeout:      getEnvAsDuration("REVIEW_JOB_TIMEOUT", "10m"),
+			PollInterval: getEnvAsDuration("REVIEW_JOB_POLL_INTERVAL", "5s"),
+		},
+		Conversation: ConversationConfig{
+			MaxMessages:     getEnvAsInt("REVIEW_CONVERSATION_MAX_MESSAGES", 50),
+			HistoryMessages: getEnvAsInt("REVIEW_CONVERSATION_HISTORY_MESSAGES", 20),
+		},
 		Features: FeatureFlags{
 			VectorSearch:         getEnvAsBool("FEATURE_VECTOR_SEARCH", false),
 			HybridSearch:         getEnvAsBool("FEATURE_HYBRID_SEARCH", false),
@@ -120,9 +281,22 @@ func Load() (*Config, error) {
 		},
 	}
 
+	if err := cfg.Trash.validate(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
+// validate - ゴミ箱に残す期間が0以下の場合はエラー
+// 完全削除してもレビューの行（使用量の列）は残るため、利用上限・使用量の集計のための下限は設けない
+func (c *TrashConfig) validate() error {
+	if c.Retention <= 0 {
+		return fmt.Errorf("REVIEW_TRASH_RETENTION must be positive, got %s", c.Retention)
+	}
+	return nil
+}
+
 // GetDSN - PostgreSQL接続文字列を生成
 func (c *DatabaseConfig) GetDSN() string {
 	// DATABASE_URLが設定されていればそれを使用
@@ -162,6 +336,17 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// getEnvAsSlice - カンマ区切りの環境変数をスライスに変換（空要素は除く）
+func getEnvAsSlice(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getEnvAsDuration(key string, defaultValue string) time.Duration {
 	valueStr := getEnv(key, defaultValue)
 	if duration, err := time.ParseDuration(valueStr); err == nil {
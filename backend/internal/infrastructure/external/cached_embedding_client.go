@@ -0,0 +1,237 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultEmbeddingBatchTimeout - まとめて送る1回の生成の制限時間のデフォルト
+const defaultEmbeddingBatchTimeout = 30 * time.Second
+
+// EmbeddingBatchOptions - 同時に届いた1件ずつの生成をまとめる設定
+type EmbeddingBatchOptions struct {
+	Window  time.Duration // 最初のリクエストからまとめて送るまでの待ち時間（0以下はまとめない）
+	MaxSize int           // 1回にまとめる最大件数（達したら待たずに送る）
+	Timeout time.Duration // まとめて送る1回の生成の制限時間（0以下はデフォルトの30秒）
+}
+
+// CachedEmbeddingClient - キャッシュとバッチ化を行う EmbeddingClientInterface のデコレーター
+//   - モデルとテキストのハッシュをキーにキャッシュし、同じテキストはAPIを呼び出さない
+//   - 同時に届いた GenerateEmbedding を1回の GenerateEmbeddings にまとめる
+//
+// キャッシュから返した分は使用量（RecordEmbeddingUsage）を記録しない
+type CachedEmbeddingClient struct {
+	base  EmbeddingClientInterface
+	cache EmbeddingCache
+	model string
+	batch EmbeddingBatchOptions
+
+	mu      sync.Mutex
+	pending []*pendingEmbedding
+	timer   *time.Timer
+}
+
+// pendingEmbedding - バッチ送信を待っている1件
+type pendingEmbedding struct {
+	ctx  context.Context
+	key  string
+	text string
+	done chan embeddingResult
+}
+
+// embeddingResult - バッチ送信の結果
+type embeddingResult struct {
+	embedding []float32
+	err       error
+}
+
+// NewCachedEmbeddingClient - コンストラクタ
+// model: キャッシュキーに含めるモデル名。cache が nil の場合はバッチ化のみ行う
+func NewCachedEmbeddingClient(base EmbeddingClientInterface, cache EmbeddingCache, model string, batch EmbeddingBatchOptions) *CachedEmbeddingClient {
+	if batch.MaxSize <= 0 {
+		batch.MaxSize = 1
+	}
+	if batch.Timeout <= 0 {
+		batch.Timeout = defaultEmbeddingBatchTimeout
+	}
+	return &CachedEmbeddingClient{
+		base:  base,
+		cache: cache,
+		model: model,
+		batch: batch,
+	}
+}
+
+// GenerateEmbedding - キャッシュになければ、他のリクエストとまとめて生成
+func (c *CachedEmbeddingClient) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	key := EmbeddingCacheKey(c.model, text)
+	if embedding, ok := c.getCache(ctx, key); ok {
+		return embedding, nil
+	}
+
+	if c.batch.Window <= 0 || c.batch.MaxSize <= 1 {
+		embedding, err := c.base.GenerateEmbedding(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		c.setCache(ctx, key, embedding)
+		return embedding, nil
+	}
+
+	item := &pendingEmbedding{ctx: ctx, key: key, text: text, done: make(chan embeddingResult, 1)}
+	c.enqueue(item)
+
+	select {
+	case result := <-item.done:
+		return result.embedding, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// GenerateEmbeddings - キャッシュにないテキストのみ一括で生成
+func (c *CachedEmbeddingClient) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	keys := make([]string, len(texts))
+	var missTexts []string
+	var missIndexes []int
+	for i, text := range texts {
+		keys[i] = EmbeddingCacheKey(c.model, text)
+		if embedding, ok := c.getCache(ctx, keys[i]); ok {
+			embeddings[i] = embedding
+			continue
+		}
+		missTexts = append(missTexts, text)
+		missIndexes = append(missIndexes, i)
+	}
+	if len(missTexts) == 0 {
+		return embeddings, nil
+	}
+
+	generated, err := c.base.GenerateEmbeddings(ctx, missTexts)
+	if err != nil {
+		return nil, err
+	}
+	if len(generated) != len(missTexts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(missTexts), len(generated))
+	}
+	for j, i := range missIndexes {
+		embeddings[i] = generated[j]
+		c.setCache(ctx, keys[i], generated[j])
+	}
+
+	return embeddings, nil
+}
+
+// enqueue - バッチに追加し、件数が上限に達したら送信（最初の1件で待ち時間のタイマーを開始）
+func (c *CachedEmbeddingClient) enqueue(item *pendingEmbedding) {
+	c.mu.Lock()
+	c.pending = append(c.pending, item)
+	if len(c.pending) >= c.batch.MaxSize {
+		batch := c.takePendingLocked()
+		c.mu.Unlock()
+		go c.flush(batch)
+		return
+	}
+	if c.timer == nil {
+		c.timer = time.AfterFunc(c.batch.Window, func() {
+			c.mu.Lock()
+			batch := c.takePendingLocked()
+			c.mu.Unlock()
+			c.flush(batch)
+		})
+	}
+	c.mu.Unlock()
+}
+
+// takePendingLocked - 待っている分を取り出してタイマーを止める
+func (c *CachedEmbeddingClient) takePendingLocked() []*pendingEmbedding {
+	batch := c.pending
+	c.pending = nil
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	return batch
+}
+
+// flush - まとめた分を1回の GenerateEmbeddings で生成して各リクエストに返す
+func (c *CachedEmbeddingClient) flush(batch []*pendingEmbedding) {
+	if len(batch) == 0 {
+		return
+	}
+
+	// 1. 同じテキストは1回だけ送る（キャンセル済みのリクエストは除く）
+	var texts []string
+	index := map[string]int{}
+	for _, item := range batch {
+		if item.ctx.Err() != nil {
+			continue
+		}
+		if _, ok := index[item.key]; !ok {
+			index[item.key] = len(texts)
+			texts = append(texts, item.text)
+		}
+	}
+	if len(texts) == 0 {
+		return
+	}
+
+	// 2. 1件のリクエストのキャンセルで他のリクエストを失敗させないよう、リクエストのcontextを引き継がずに
+	// 制限時間のみ付けて送る（応答しない場合に待っている全リクエストが止まらないように）
+	// 使用量はバッチ全体で集計し、テキストの長さに応じて各リクエストに振り分ける
+	ctx, cancel := context.WithTimeout(context.Background(), c.batch.Timeout)
+	defer cancel()
+	ctx, usage := WithEmbeddingUsageRecorder(ctx)
+	embeddings, err := c.base.GenerateEmbeddings(ctx, texts)
+	if err == nil && len(embeddings) != len(texts) {
+		err = fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embeddings))
+	}
+
+	// 3. 結果を返してキャッシュに保存
+	totalLength := 0
+	for _, text := range texts {
+		totalLength += len(text)
+	}
+	for _, item := range batch {
+		i, ok := index[item.key]
+		if !ok {
+			continue
+		}
+		if err != nil {
+			item.done <- embeddingResult{err: err}
+			continue
+		}
+		if totalLength > 0 {
+			RecordEmbeddingUsage(item.ctx, usage.Model(), usage.Tokens()*len(item.text)/totalLength)
+		}
+		item.done <- embeddingResult{embedding: embeddings[i]}
+	}
+	if err != nil {
+		return
+	}
+	for key, i := range index {
+		c.setCache(ctx, key, embeddings[i])
+	}
+}
+
+// getCache - キャッシュから取得（キャッシュを使わない場合は常にミス）
+func (c *CachedEmbeddingClient) getCache(ctx context.Context, key string) ([]float32, bool) {
+	if c.cache == nil {
+		return nil, false
+	}
+	return c.cache.Get(ctx, key)
+}
+
+// setCache - キャッシュに保存（キャッシュを使わない場合は何もしない）
+func (c *CachedEmbeddingClient) setCache(ctx context.Context, key string, embedding []float32) {
+	if c.cache == nil || len(embedding) == 0 {
+		return
+	}
+	c.cache.Set(ctx, key, embedding)
+}
+
+// CachedEmbeddingClientがインターフェースを実装していることを保証
+var _ EmbeddingClientInterface = (*CachedEmbeddingClient)(nil)
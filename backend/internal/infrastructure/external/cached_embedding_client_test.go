@@ -0,0 +1,222 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingEmbeddingClient - 呼び出しを記録するEmbeddingクライアントのスタブ
+// テキストの長さを値にしたベクトルを返し、1文字1トークンとして使用量を記録する
+type countingEmbeddingClient struct {
+	mu         sync.Mutex
+	singles    []string
+	batches    [][]string
+	err        error
+	batchDelay time.Duration
+	hang       bool // GenerateEmbeddings が context の終了まで応答しない
+}
+
+func (c *countingEmbeddingClient) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	c.mu.Lock()
+	c.singles = append(c.singles, text)
+	c.mu.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	RecordEmbeddingUsage(ctx, "stub-embedding", len(text))
+	return []float32{float32(len(text))}, nil
+}
+
+func (c *countingEmbeddingClient) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	c.mu.Lock()
+	c.batches = append(c.batches, append([]string(nil), texts...))
+	c.mu.Unlock()
+	time.Sleep(c.batchDelay)
+	if c.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	embeddings := make([][]float32, len(texts))
+	tokens := 0
+	for i, text := range texts {
+		embeddings[i] = []float32{float32(len(text))}
+		tokens += len(text)
+	}
+	RecordEmbeddingUsage(ctx, "stub-embedding", tokens)
+	return embeddings, nil
+}
+
+func (c *countingEmbeddingClient) calls() (singles int, batches [][]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.singles), append([][]string(nil), c.batches...)
+}
+
+func TestCachedEmbeddingClient_Cache(t *testing.T) {
+	base := &countingEmbeddingClient{}
+	client := NewCachedEmbeddingClient(base, NewLRUEmbeddingCache(10), "stub-embedding", EmbeddingBatchOptions{})
+	ctx, usage := WithEmbeddingUsageRecorder(context.Background())
+
+	// 1. 1件ずつ: 2回目はキャッシュから返し、使用量を記録しない
+	first, err := client.GenerateEmbedding(ctx, "hello")
+	require.NoError(t, err)
+	second, err := client.GenerateEmbedding(ctx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	singles, _ := base.calls()
+	assert.Equal(t, 1, singles)
+	assert.Equal(t, 5, usage.Tokens())
+
+	// 2. 一括: キャッシュにないテキストのみ生成し、入力の順序で返す
+	embeddings, err := client.GenerateEmbeddings(ctx, []string{"hi", "hello", "hey!"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{2}, {5}, {4}}, embeddings)
+	_, batches := base.calls()
+	assert.Equal(t, [][]string{{"hi", "hey!"}}, batches)
+	assert.Equal(t, 11, usage.Tokens())
+
+	// 3. モデルが異なるクライアントとはキャッシュを共有しない
+	assert.NotEqual(t, EmbeddingCacheKey("stub-embedding", "hello"), EmbeddingCacheKey("other-model", "hello"))
+}
+
+func TestCachedEmbeddingClient_Batching(t *testing.T) {
+	base := &countingEmbeddingClient{}
+	client := NewCachedEmbeddingClient(base, NewLRUEmbeddingCache(10), "stub-embedding", EmbeddingBatchOptions{
+		Window:  50 * time.Millisecond,
+		MaxSize: 10,
+	})
+
+	texts := []string{"a", "bb", "ccc", "bb"}
+	results := make([][]float32, len(texts))
+	usages := make([]*EmbeddingUsageRecorder, len(texts))
+	var wg sync.WaitGroup
+	for i, text := range texts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, usage := WithEmbeddingUsageRecorder(context.Background())
+			usages[i] = usage
+			embedding, err := client.GenerateEmbedding(ctx, text)
+			assert.NoError(t, err)
+			results[i] = embedding
+		}()
+	}
+	wg.Wait()
+
+	// 同時に届いた4件を1回の一括生成にまとめ、同じテキストは1回だけ送る
+	singles, batches := base.calls()
+	assert.Equal(t, 0, singles)
+	require.Len(t, batches, 1)
+	assert.ElementsMatch(t, []string{"a", "bb", "ccc"}, batches[0])
+	for i, text := range texts {
+		assert.Equal(t, []float32{float32(len(text))}, results[i])
+		// 使用量はテキストの長さに応じて振り分ける
+		assert.Equal(t, len(text), usages[i].Tokens())
+	}
+
+	// まとめて生成した結果もキャッシュする
+	_, err := client.GenerateEmbedding(context.Background(), "ccc")
+	require.NoError(t, err)
+	_, batches = base.calls()
+	assert.Len(t, batches, 1)
+}
+
+func TestCachedEmbeddingClient_BatchMaxSize(t *testing.T) {
+	base := &countingEmbeddingClient{}
+	client := NewCachedEmbeddingClient(base, nil, "stub-embedding", EmbeddingBatchOptions{
+		Window:  time.Minute, // 上限に達したら待たずに送る
+		MaxSize: 2,
+	})
+
+	var wg sync.WaitGroup
+	for _, text := range []string{"a", "b"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GenerateEmbedding(context.Background(), text)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	_, batches := base.calls()
+	require.Len(t, batches, 1)
+	assert.Len(t, batches[0], 2)
+}
+
+func TestCachedEmbeddingClient_BatchError(t *testing.T) {
+	base := &countingEmbeddingClient{err: errors.New("API error")}
+	cache := NewLRUEmbeddingCache(10)
+	client := NewCachedEmbeddingClient(base, cache, "stub-embedding", EmbeddingBatchOptions{
+		Window:  time.Millisecond,
+		MaxSize: 10,
+	})
+
+	_, err := client.GenerateEmbedding(context.Background(), "hello")
+
+	assert.EqualError(t, err, "API error")
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestCachedEmbeddingClient_CanceledRequestDoesNotFailBatch(t *testing.T) {
+	base := &countingEmbeddingClient{batchDelay: 50 * time.Millisecond}
+	client := NewCachedEmbeddingClient(base, nil, "stub-embedding", EmbeddingBatchOptions{
+		Window:  10 * time.Millisecond,
+		MaxSize: 10,
+	})
+
+	canceled, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		time.AfterFunc(20*time.Millisecond, cancel)
+		_, err := client.GenerateEmbedding(canceled, "a")
+		assert.ErrorIs(t, err, context.Canceled)
+	}()
+	go func() {
+		defer wg.Done()
+		embedding, err := client.GenerateEmbedding(context.Background(), "bb")
+		assert.NoError(t, err)
+		assert.Equal(t, []float32{2}, embedding)
+	}()
+	wg.Wait()
+}
+
+func TestCachedEmbeddingClient_BatchTimeout(t *testing.T) {
+	base := &countingEmbeddingClient{hang: true}
+	client := NewCachedEmbeddingClient(base, nil, "stub-embedding", EmbeddingBatchOptions{
+		Window:  10 * time.Millisecond,
+		MaxSize: 10,
+		Timeout: 50 * time.Millisecond,
+	})
+
+	// リクエストに期限がなくても、まとめて送る生成の制限時間で終わる
+	_, err := client.GenerateEmbedding(context.Background(), "a")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestLRUEmbeddingCache_Evicts(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUEmbeddingCache(2)
+	cache.Set(ctx, "a", []float32{1})
+	cache.Set(ctx, "b", []float32{2})
+	_, _ = cache.Get(ctx, "a") // a を最近使ったエントリにする
+	cache.Set(ctx, "c", []float32{3})
+
+	_, ok := cache.Get(ctx, "b")
+	assert.False(t, ok)
+	got, ok := cache.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, []float32{1}, got)
+	assert.Equal(t, 2, cache.Len())
+}
@@ -0,0 +1,330 @@
+package external
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrCassetteInteractionNotFound - replay モードで、リクエストに一致する記録がない
+var ErrCassetteInteractionNotFound = errors.New("no recorded interaction matches the request")
+
+// CassetteMode - 記録・再生の動作
+type CassetteMode string
+
+const (
+	CassetteModeOff    CassetteMode = "off"    // 何もしない（実際のAPIを呼び出す）
+	CassetteModeRecord CassetteMode = "record" // 実際のAPIを呼び出し、やり取りをファイルに記録する
+	CassetteModeReplay CassetteMode = "replay" // 記録したやり取りを返す（APIは呼び出さない）
+)
+
+// CassetteOptions - 記録・再生の設定
+type CassetteOptions struct {
+	Mode CassetteMode
+	Path string // 記録ファイル（JSON）
+
+	// IgnoreBodyFields - リクエストの照合で比較しないJSONのフィールド（"metadata.user_id" のようにドット区切りでネストを指定）
+	IgnoreBodyFields []string
+}
+
+// Cassette - 記録したやり取りの一覧（記録ファイルの形式）
+type Cassette struct {
+	Interactions []CassetteInteraction `json:"interactions"`
+}
+
+// CassetteInteraction - 1回のリクエストとレスポンス
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest - 記録したリクエスト
+// APIキーを記録しないため、リクエストヘッダーは保存しない
+type CassetteRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// CassetteResponse - 記録したレスポンス
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// CassetteTransport - 外部APIとのやり取りを記録・再生する http.RoundTripper
+// record では next で実際に呼び出してファイルに記録し、replay ではファイルの記録から応答する
+// リクエストはメソッド・URL・正規化したボディ（JSONのキー順・空白・IgnoreBodyFields を無視）で照合する
+type CassetteTransport struct {
+	next    http.RoundTripper
+	options CassetteOptions
+	file    *cassetteFile
+}
+
+// cassetteFile - 記録ファイルの内容
+// 同じファイルを使うトランスポート（Embeddingとレビューなど）で記録を共有する
+type cassetteFile struct {
+	mu       sync.Mutex
+	path     string
+	cassette *Cassette
+	used     []bool // replay で返した記録（同じリクエストが複数回ある場合は記録順に返す）
+}
+
+// cassetteFiles - プロセス内で開いた記録ファイル（キーは絶対パス）
+var cassetteFiles = struct {
+	sync.Mutex
+	files map[string]*cassetteFile
+}{files: map[string]*cassetteFile{}}
+
+// NewCassetteTransport - コンストラクタ
+// replay の場合は記録ファイルを読み込む。record の場合は新しく記録する（既存のファイルはプロセスで最初に開いたときに上書き）
+// next が nil の場合は http.DefaultTransport を使う
+func NewCassetteTransport(next http.RoundTripper, options CassetteOptions) (*CassetteTransport, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if options.Mode != CassetteModeRecord && options.Mode != CassetteModeReplay {
+		return nil, fmt.Errorf("unsupported cassette mode: %s", options.Mode)
+	}
+
+	file, err := openCassetteFile(options.Path, options.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CassetteTransport{
+		next:    next,
+		options: options,
+		file:    file,
+	}, nil
+}
+
+// openCassetteFile - 記録ファイルを開く（同じパスは同じ内容を返す）
+func openCassetteFile(path string, mode CassetteMode) (*cassetteFile, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cassette path: %w", err)
+	}
+
+	cassetteFiles.Lock()
+	defer cassetteFiles.Unlock()
+
+	if file, ok := cassetteFiles.files[abs]; ok {
+		return file, nil
+	}
+
+	cassette := &Cassette{}
+	if mode == CassetteModeReplay {
+		cassette, err = LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	file := &cassetteFile{
+		path:     path,
+		cassette: cassette,
+		used:     make([]bool, len(cassette.Interactions)),
+	}
+	cassetteFiles.files[abs] = file
+	return file, nil
+}
+
+// LoadCassette - 記録ファイルを読み込む
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// RoundTrip - record / replay の設定に応じてリクエストを処理
+func (t *CassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.options.Mode == CassetteModeReplay {
+		return t.replay(req, body)
+	}
+	return t.record(req, body)
+}
+
+// replay - 一致する記録からレスポンスを作る
+func (t *CassetteTransport) replay(req *http.Request, body []byte) (*http.Response, error) {
+	key := t.normalizeBody(body)
+
+	t.file.mu.Lock()
+	defer t.file.mu.Unlock()
+
+	// 未使用の記録を優先し、すべて使った場合は最後に一致した記録を繰り返す
+	found := -1
+	for i, interaction := range t.file.cassette.Interactions {
+		if !t.matches(interaction.Request, req, key) {
+			continue
+		}
+		found = i
+		if !t.file.used[i] {
+			break
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("%w: %s %s (cassette: %s)", ErrCassetteInteractionNotFound, req.Method, req.URL, t.file.path)
+	}
+	t.file.used[found] = true
+
+	recorded := t.file.cassette.Interactions[found].Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// record - 実際に呼び出し、やり取りを記録ファイルに追記する
+func (t *CassetteTransport) record(req *http.Request, body []byte) (*http.Response, error) {
+	// RoundTripper はリクエストを変更しないため、読み出したボディを複製したリクエストに設定して送る
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	// 呼び出し元が読めるようにレスポンスボディを保持し直す
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+
+	t.file.mu.Lock()
+	defer t.file.mu.Unlock()
+
+	t.file.cassette.Interactions = append(t.file.cassette.Interactions, CassetteInteraction{
+		Request: CassetteRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Body:   string(body),
+		},
+		Response: CassetteResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       string(respBody),
+		},
+	})
+	t.file.used = append(t.file.used, false)
+	if err := t.file.save(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// save - 記録ファイルに書き込む（書き込み途中のファイルを読まれないよう一時ファイルから置き換える）
+func (f *cassetteFile) save() error {
+	data, err := json.MarshalIndent(f.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// matches - 記録したリクエストと一致するか（key は正規化済みのボディ）
+func (t *CassetteTransport) matches(recorded CassetteRequest, req *http.Request, key string) bool {
+	return recorded.Method == req.Method &&
+		recorded.URL == req.URL.String() &&
+		t.normalizeBody([]byte(recorded.Body)) == key
+}
+
+// normalizeBody - 照合用にリクエストボディを正規化
+// JSONの場合はフィールドの順序・空白を揃え、IgnoreBodyFields を取り除く。JSON以外は前後の空白のみ除く
+func (t *CassetteTransport) normalizeBody(body []byte) string {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return ""
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return string(body)
+	}
+	for _, field := range t.options.IgnoreBodyFields {
+		deleteJSONField(value, strings.Split(field, "."))
+	}
+
+	// map はキー順で出力されるため、フィールドの順序に依存しない
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return string(body)
+	}
+	return string(normalized)
+}
+
+// deleteJSONField - ドット区切りで指定したフィールドを取り除く（配列の場合は各要素から取り除く）
+func deleteJSONField(value interface{}, path []string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			delete(v, path[0])
+			return
+		}
+		if child, ok := v[path[0]]; ok {
+			deleteJSONField(child, path[1:])
+		}
+	case []interface{}:
+		for _, item := range v {
+			deleteJSONField(item, path)
+		}
+	}
+}
+
+// readRequestBody - リクエストボディを読み出して閉じる
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,153 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCassetteTransport_RecordThenReplay(t *testing.T) {
+	server := newFailingServer(t, 0, 0, nil, `{"answer":42}`)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	// record: 実際に呼び出してファイルに記録する
+	recorder, err := NewCassetteTransport(nil, CassetteOptions{Mode: CassetteModeRecord, Path: path})
+	require.NoError(t, err)
+	resp := postJSON(t, &http.Client{Transport: recorder}, server.URL+"/v1/answer", `{"model":"m","input":"x"}`)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, `{"answer":42}`, string(body))
+	assert.Equal(t, int32(1), server.calls)
+
+	cassette, err := LoadCassette(path)
+	require.NoError(t, err)
+	require.Len(t, cassette.Interactions, 1)
+	assert.Equal(t, `{"model":"m","input":"x"}`, cassette.Interactions[0].Request.Body)
+
+	// replay: サーバーを止めても記録から応答する（JSONのフィールド順・空白は照合に影響しない）
+	url := server.URL + "/v1/answer"
+	server.Close()
+	file, err := openCassetteFile(path, CassetteModeReplay)
+	require.NoError(t, err)
+	player := &CassetteTransport{next: http.DefaultTransport, options: CassetteOptions{Mode: CassetteModeReplay, Path: path}, file: file}
+
+	resp = postJSON(t, &http.Client{Transport: player}, url, "{\n  \"input\": \"x\",\n  \"model\": \"m\"\n}")
+	body, _ = io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"answer":42}`, string(body))
+}
+
+func TestCassetteTransport_ReplayMatching(t *testing.T) {
+	newPlayer := func(t *testing.T, options CassetteOptions, interactions ...CassetteInteraction) *http.Client {
+		t.Helper()
+		options.Mode = CassetteModeReplay
+		options.Path = filepath.Join(t.TempDir(), "cassette.json")
+		file := &cassetteFile{path: options.Path, cassette: &Cassette{Interactions: interactions}, used: make([]bool, len(interactions))}
+		return &http.Client{Transport: &CassetteTransport{next: http.DefaultTransport, options: options, file: file}}
+	}
+	interaction := func(body, response string) CassetteInteraction {
+		return CassetteInteraction{
+			Request:  CassetteRequest{Method: http.MethodPost, URL: "https://api.example.com/v1/chat", Body: body},
+			Response: CassetteResponse{StatusCode: http.StatusOK, Body: response},
+		}
+	}
+	read := func(t *testing.T, resp *http.Response) string {
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	t.Run("同じリクエストが複数回ある場合は記録順に返し、使い切ったら最後の記録を繰り返す", func(t *testing.T) {
+		client := newPlayer(t, CassetteOptions{},
+			interaction(`{"input":"x"}`, "first"),
+			interaction(`{"input":"y"}`, "other"),
+			interaction(`{"input":"x"}`, "second"),
+		)
+
+		assert.Equal(t, "first", read(t, postJSON(t, client, "https://api.example.com/v1/chat", `{"input":"x"}`)))
+		assert.Equal(t, "second", read(t, postJSON(t, client, "https://api.example.com/v1/chat", `{"input":"x"}`)))
+		assert.Equal(t, "second", read(t, postJSON(t, client, "https://api.example.com/v1/chat", `{"input":"x"}`)))
+	})
+
+	t.Run("IgnoreBodyFields のフィールドは照合しない", func(t *testing.T) {
+		client := newPlayer(t, CassetteOptions{IgnoreBodyFields: []string{"metadata.request_id", "messages.id"}},
+			interaction(`{"metadata":{"request_id":"a","user":"u"},"messages":[{"id":"1","text":"x"}]}`, "ok"),
+		)
+
+		resp := postJSON(t, client, "https://api.example.com/v1/chat", `{"messages":[{"text":"x","id":"2"}],"metadata":{"user":"u","request_id":"b"}}`)
+		assert.Equal(t, "ok", read(t, resp))
+	})
+
+	t.Run("記録にないリクエストはエラーになり、再試行しない", func(t *testing.T) {
+		client := newPlayer(t, CassetteOptions{}, interaction(`{"input":"x"}`, "ok"))
+		breaker := NewCircuitBreaker("test", 5, time.Minute)
+		transport, delays := newTestTransport(breaker, 3)
+		transport.base = client.Transport
+
+		req, err := http.NewRequest(http.MethodPost, "https://api.example.com/v1/chat", nil)
+		require.NoError(t, err)
+		_, err = (&http.Client{Transport: transport}).Do(req)
+
+		assert.True(t, errors.Is(err, ErrCassetteInteractionNotFound))
+		assert.Empty(t, *delays)
+		assert.Equal(t, CircuitClosed, breaker.Status().State)
+	})
+}
+
+func TestCassetteTransport_InvalidMode(t *testing.T) {
+	_, err := NewCassetteTransport(nil, CassetteOptions{Mode: "rewind", Path: filepath.Join(t.TempDir(), "c.json")})
+	assert.Error(t, err)
+
+	_, err = NewCassetteTransport(nil, CassetteOptions{Mode: CassetteModeReplay, Path: filepath.Join(t.TempDir(), "missing.json")})
+	assert.Error(t, err)
+}
+
+// 以下は testdata/cassettes の記録を使い、実際のHTTP・パース処理をAPIなしで通す
+
+func TestOpenAIClient_GenerateEmbedding_Replay(t *testing.T) {
+	transport, err := NewCassetteTransport(nil, CassetteOptions{Mode: CassetteModeReplay, Path: "testdata/cassettes/openai.json"})
+	require.NoError(t, err)
+	client := NewOpenAIClient("test-api-key", "text-embedding-3-small", 5*time.Second, transport)
+
+	ctx, usage := WithEmbeddingUsageRecorder(context.Background())
+	embedding, err := client.GenerateEmbedding(ctx, "エラーは必ず呼び出し元に返す")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{0.0123, -0.0456, 0.0789, 0.0012}, embedding)
+	assert.Equal(t, 5, usage.Tokens())
+
+	embeddings, err := client.GenerateEmbeddings(context.Background(), []string{"関数は1つのことだけをする", "エラーは必ず呼び出し元に返す"})
+	require.NoError(t, err)
+	require.Len(t, embeddings, 2)
+	assert.Equal(t, []float32{-0.0321, 0.0654, -0.0987, 0.0021}, embeddings[1])
+}
+
+func TestClaudeClient_ReviewCode_Replay(t *testing.T) {
+	// 記録は公式のエンドポイント宛て（環境変数でSDKの接続先が変わらないようにする）
+	t.Setenv("ANTHROPIC_BASE_URL", "https://api.anthropic.com/")
+	transport, err := NewCassetteTransport(nil, CassetteOptions{Mode: CassetteModeReplay, Path: "testdata/cassettes/anthropic.json"})
+	require.NoError(t, err)
+	client := NewClaudeClient("test-api-key", "claude-3-5-haiku-latest", 4096, 5*time.Second, 5*time.Second, transport)
+
+	output, err := client.ReviewCode(context.Background(), ReviewCodeInput{
+		Code:     "func HandleError(err error) {\n    log.Println(err)\n}",
+		Language: "go",
+		Context:  "HTTPハンドラのエラー処理です。",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.ResultSourceToolUse, output.ResultSource)
+	assert.Equal(t, "claude-3-5-haiku-20241022", output.Model)
+	assert.Equal(t, 1024+187, output.TokensUsed)
+	require.Len(t, output.Structured.Improvements, 1)
+	assert.Equal(t, "エラーを握りつぶしている", output.Structured.Improvements[0].Title)
+	assert.Equal(t, "high", output.Structured.Improvements[0].Severity)
+	assert.Equal(t, 2, output.Structured.Improvements[0].StartLine)
+	assert.Equal(t, "    log.Println(err)", output.Structured.Improvements[0].CodeBefore)
+}
@@ -3,61 +3,170 @@ package external
 import (
 	"context"
 	"fmt"
+	"log"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/s7r8/reviewapp/internal/domain/model"
 )
 
 // ClaudeClient - Claude API クライアント
 type ClaudeClient struct {
-	client      anthropic.Client
-	model       string
-	maxTokens   int
-	temperature float64
+	client        anthropic.Client
+	model         string
+	maxTokens     int
+	temperature   float64
+	timeout       time.Duration
+	streamTimeout time.Duration
 }
 
 // NewClaudeClient - コンストラクタ
-func NewClaudeClient(apiKey, model string, maxTokens int) *ClaudeClient {
-	client := anthropic.NewClient(
+// transport: リトライ・サーキットブレーカー用のトランスポート（nilの場合はSDK標準のリトライを使う）
+func NewClaudeClient(apiKey, model string, maxTokens int, timeout, streamTimeout time.Duration, transport http.RoundTripper) *ClaudeClient {
+	opts := []option.RequestOption{
 		option.WithAPIKey(apiKey),
-	)
+	}
+	if transport != nil {
+		// 再試行はトランスポート側で行うため、SDKのリトライは無効化する
+		opts = append(opts,
+			option.WithHTTPClient(&http.Client{Transport: transport}),
+			option.WithMaxRetries(0),
+		)
+	}
+	client := anthropic.NewClient(opts...)
 
 	return &ClaudeClient{
-		client:      client,
-		model:       model,
-		maxTokens:   maxTokens,
-		temperature: 0.7, // デフォルト
+		client:        client,
+		model:         model,
+		maxTokens:     maxTokens,
+		temperature:   0.7, // デフォルト
+		timeout:       timeout,
+		streamTimeout: streamTimeout,
 	}
 }
 
-// ReviewCodeInput - レビュー入力
-type ReviewCodeInput struct {
-	Code            string
-	Language        string
-	Context         string
-	KnowledgePrompt string // ナレッジから生成したプロンプト
+// Name - プロバイダ名
+func (c *ClaudeClient) Name() string {
+	return ProviderAnthropic
 }
 
-// ReviewCodeOutput - レビュー結果
-type ReviewCodeOutput struct {
-	ReviewResult string
-	TokensUsed   int
+// Model - 設定されたモデル名
+func (c *ClaudeClient) Model() string {
+	return c.model
 }
 
 // ReviewCode - コードをレビュー
+// submit_review ツールの呼び出しを強制して構造化データを受け取り、
+// 検証に失敗した場合のみマークダウン出力で再生成する
 func (c *ClaudeClient) ReviewCode(ctx context.Context, input ReviewCodeInput) (*ReviewCodeOutput, error) {
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// Claude API呼び出し（ツール呼び出し）
+	params := c.buildMessageParams(buildStructuredSystemPrompt(input), input)
+	params.Tools = []anthropic.ToolUnionParam{
+		{
+			OfTool: &anthropic.ToolParam{
+				Name:        submitReviewToolName,
+				Description: anthropic.String(submitReviewDescription),
+				InputSchema: anthropic.ToolInputSchemaParam{
+					Properties:  reviewResultProperties,
+					Required:    reviewResultRequired,
+					ExtraFields: map[string]interface{}{"additionalProperties": false},
+				},
+			},
+		},
+	}
+	params.ToolChoice = anthropic.ToolChoiceParamOfTool(submitReviewToolName)
+
+	message, err := c.client.Messages.New(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Claude API: %w", err)
+	}
+
+	output := c.toReviewCodeOutput(message)
+	structured, err := extractToolUseReview(message)
+	if err == nil {
+		output.Structured = structured
+		output.ResultSource = model.ResultSourceToolUse
+		return output, nil
+	}
+
+	// フォールバック: マークダウンで再生成
+	log.Printf("Warning: invalid tool_use review from Claude, falling back to markdown: %v", err)
+	fallback, err := c.client.Messages.New(ctx, c.buildMessageParams(buildSystemPrompt(input), input))
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Claude API: %w", err)
+	}
+
+	fallbackOutput := c.toReviewCodeOutput(fallback)
+	fallbackOutput.TokensUsed += output.TokensUsed
+	fallbackOutput.Usage = fallbackOutput.Usage.Add(output.Usage)
+	return fallbackOutput, nil
+}
+
+// extractToolUseReview - submit_review ツールの入力から構造化データを取り出す
+func extractToolUseReview(message *anthropic.Message) (*model.StructuredReviewResult, error) {
+	for _, block := range message.Content {
+		if block.Type == "tool_use" && block.Name == submitReviewToolName {
+			return decodeStructuredReview(block.Input)
+		}
+	}
+	return nil, fmt.Errorf("no %s tool_use block in response", submitReviewToolName)
+}
+
+// ReviewCodeStream - Claudeのストリーミング APIでレビューし、テキスト差分をonDeltaに逐次渡す
+func (c *ClaudeClient) ReviewCodeStream(ctx context.Context, input ReviewCodeInput, onDelta func(text string)) (*ReviewCodeOutput, error) {
+	// 長いレビューでも途中で打ち切られないよう、通常より長いタイムアウトを使う
+	ctx, cancel := context.WithTimeout(ctx, c.streamTimeout)
 	defer cancel()
 
+	// テキスト差分を逐次返すため、ストリーミングはマークダウン出力で生成する
+	stream := c.client.Messages.NewStreaming(ctx, c.buildMessageParams(buildSystemPrompt(input), input))
+	defer stream.Close()
+
+	// イベントを蓄積して最終的なメッセージを組み立てる
+	message := anthropic.Message{}
+	for stream.Next() {
+		event := stream.Current()
+		if err := message.Accumulate(event); err != nil {
+			return nil, fmt.Errorf("failed to accumulate stream event: %w", err)
+		}
+
+		if delta, ok := event.AsAny().(anthropic.ContentBlockDeltaEvent); ok {
+			if text, ok := delta.Delta.AsAny().(anthropic.TextDelta); ok && onDelta != nil {
+				onDelta(text.Text)
+			}
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, fmt.Errorf("failed to stream Claude API: %w", err)
+	}
+
+	return c.toReviewCodeOutput(&message), nil
+}
+
+// buildMessageParams - Claude APIのリクエストパラメータを生成
+func (c *ClaudeClient) buildMessageParams(systemPrompt string, input ReviewCodeInput) anthropic.MessageNewParams {
 	// プロンプト生成
-	systemPrompt := c.buildSystemPrompt(input.KnowledgePrompt)
-	userPrompt := c.buildUserPrompt(input.Code, input.Language, input.Context)
+	userPrompt := buildUserPrompt(input)
+
+	// ユーザー設定があれば生成パラメータを上書き
+	maxTokens := c.maxTokens
+	if input.MaxTokens > 0 {
+		maxTokens = input.MaxTokens
+	}
+	temperature := c.temperature
+	if input.Temperature != nil {
+		temperature = *input.Temperature
+	}
 
-	// Claude API呼び出し
-	message, err := c.client.Messages.New(ctx, anthropic.MessageNewParams{
+	return anthropic.MessageNewParams{
 		Model:     anthropic.Model(c.model),
-		MaxTokens: int64(c.maxTokens),
+		MaxTokens: int64(maxTokens),
 		System: []anthropic.TextBlockParam{
 			{
 				Type: "text",
@@ -69,12 +178,12 @@ func (c *ClaudeClient) ReviewCode(ctx context.Context, input ReviewCodeInput) (*
 				anthropic.NewTextBlock(userPrompt),
 			),
 		},
-		Temperature: anthropic.Float(c.temperature),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to call Claude API: %w", err)
+		Temperature: anthropic.Float(temperature),
 	}
+}
 
+// toReviewCodeOutput - Claude APIのレスポンスをレビュー結果に変換
+func (c *ClaudeClient) toReviewCodeOutput(message *anthropic.Message) *ReviewCodeOutput {
 	// レスポンスからテキストを抽出
 	var reviewText string
 	for _, block := range message.Content {
@@ -83,83 +192,126 @@ func (c *ClaudeClient) ReviewCode(ctx context.Context, input ReviewCodeInput) (*
 		}
 	}
 
-	// トークン使用量を計算
+	// トークン使用量を計算（InputTokens はキャッシュの書き込み・読み込み分を含まない）
 	tokensUsed := int(message.Usage.InputTokens + message.Usage.OutputTokens)
 
 	return &ReviewCodeOutput{
 		ReviewResult: reviewText,
 		TokensUsed:   tokensUsed,
-	}, nil
+		Usage: model.TokenUsage{
+			InputTokens:         int(message.Usage.InputTokens),
+			OutputTokens:        int(message.Usage.OutputTokens),
+			CacheCreationTokens: int(message.Usage.CacheCreationInputTokens),
+			CacheReadTokens:     int(message.Usage.CacheReadInputTokens),
+		},
+		Provider:     ProviderAnthropic,
+		Model:        string(message.Model),
+		ResultSource: model.ResultSourceMarkdown,
+	}
 }
 
-// buildSystemPrompt - システムプロンプト生成
-func (c *ClaudeClient) buildSystemPrompt(knowledgePrompt string) string {
-	return fmt.Sprintf(`あなたはコードレビュアーです。
-以下のルールと過去の判断基準に基づいてレビューしてください。
-
-## ユーザーのコーディング哲学・ルール
-%s
-
-## レビュー指示
-1. 上記のルールに違反している箇所を指摘
-2. 改善案を具体的に提示
-3. なぜそのルールが重要か説明
-4. 良い点も必ず指摘する
-
-**重要**: ユーザーの哲学・ルールを最優先してください。
-
-## 出力フォーマット（この形式を厳密に守ること）
-
-**必ず以下の構造で出力してください:**
-
-### 良い点
-- 良い点1
-- 良い点2
-
-### 1. 改善点のタイトル
-
-- 問題点の説明
-- 理由の説明
-
-改善例：
-`+"```python"+`
-# 改善後のコード
-`+"```"+`
+// Converse - レビューについてのユーザーのメッセージに返答
+// submit_reply ツールの呼び出しを強制して返答とナレッジの提案を受け取り、
+// 検証に失敗した場合のみテキストで返答を再生成する（ナレッジの提案なし）
+func (c *ClaudeClient) Converse(ctx context.Context, input ConverseInput) (*ConverseOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
 
-### 2. 改善点のタイトル
+	params := c.buildConversationParams(buildConversationSystemPrompt(input, true), input)
+	params.Tools = []anthropic.ToolUnionParam{
+		{
+			OfTool: &anthropic.ToolParam{
+				Name:        submitReplyToolName,
+				Description: anthropic.String(submitReplyDescription),
+				InputSchema: anthropic.ToolInputSchemaParam{
+					Properties:  conversationReplyProperties,
+					Required:    conversationReplyRequired,
+					ExtraFields: map[string]interface{}{"additionalProperties": false},
+				},
+			},
+		},
+	}
+	params.ToolChoice = anthropic.ToolChoiceParamOfTool(submitReplyToolName)
 
-- 問題点の説明
-- 理由の説明
+	message, err := c.client.Messages.New(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Claude API: %w", err)
+	}
 
-改善例：
-`+"```python"+`
-# 改善後のコード
-`+"```"+`
+	output := c.toConverseOutput(message)
+	reply, err := extractToolUseReply(message)
+	if err == nil {
+		output.Reply = reply.Reply
+		output.Knowledge = reply.Knowledge
+		return output, nil
+	}
 
-### 総合評価
-総合的な評価を1-2文で記述
+	// フォールバック: テキストで再生成
+	log.Printf("Warning: invalid tool_use reply from Claude, falling back to text: %v", err)
+	fallback, err := c.client.Messages.New(ctx, c.buildConversationParams(buildConversationSystemPrompt(input, false), input))
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Claude API: %w", err)
+	}
 
-**絶対に守るべきルール:**
-1. 各セクションは必ず「### 」で始める（###の後にスペース）
-2. 改善点は「### 数字. タイトル」の形式
-3. コードブロックは`+"```言語名"+`で囲む
-4. この順序を必ず守る: 良い点 → 改善点 → 総合評価`, knowledgePrompt)
+	fallbackOutput := c.toConverseOutput(fallback)
+	fallbackOutput.TokensUsed += output.TokensUsed
+	fallbackOutput.Usage = fallbackOutput.Usage.Add(output.Usage)
+	return fallbackOutput, nil
 }
 
-// buildUserPrompt - ユーザープロンプト生成
-func (c *ClaudeClient) buildUserPrompt(code, language, context string) string {
-	prompt := fmt.Sprintf(`## レビュー対象コード
-言語: %s
-
-`, language)
+// extractToolUseReply - submit_reply ツールの入力から返答を取り出す
+func extractToolUseReply(message *anthropic.Message) (*conversationReply, error) {
+	for _, block := range message.Content {
+		if block.Type == "tool_use" && block.Name == submitReplyToolName {
+			return decodeConversationReply(block.Input)
+		}
+	}
+	return nil, fmt.Errorf("no %s tool_use block in response", submitReplyToolName)
+}
 
-	if context != "" {
-		prompt += fmt.Sprintf(`コンテキスト: %s
+// buildConversationParams - 会話のリクエストパラメータを生成（これまでの会話をそのままメッセージにする）
+func (c *ClaudeClient) buildConversationParams(systemPrompt string, input ConverseInput) anthropic.MessageNewParams {
+	messages := make([]anthropic.MessageParam, 0, len(input.Messages))
+	for _, m := range input.Messages {
+		if m.Role == model.ReviewMessageRoleAssistant {
+			messages = append(messages, anthropic.NewAssistantMessage(anthropic.NewTextBlock(m.Content)))
+		} else {
+			messages = append(messages, anthropic.NewUserMessage(anthropic.NewTextBlock(m.Content)))
+		}
+	}
 
-`, context)
+	// ユーザー設定があれば生成パラメータを上書き
+	maxTokens := c.maxTokens
+	if input.MaxTokens > 0 {
+		maxTokens = input.MaxTokens
+	}
+	temperature := c.temperature
+	if input.Temperature != nil {
+		temperature = *input.Temperature
 	}
 
-	prompt += fmt.Sprintf("```%s\n%s\n```", language, code)
+	return anthropic.MessageNewParams{
+		Model:     anthropic.Model(c.model),
+		MaxTokens: int64(maxTokens),
+		System: []anthropic.TextBlockParam{
+			{
+				Type: "text",
+				Text: systemPrompt,
+			},
+		},
+		Messages:    messages,
+		Temperature: anthropic.Float(temperature),
+	}
+}
 
-	return prompt
+// toConverseOutput - Claude APIのレスポンスを返答に変換（テキストのブロックを返答にする）
+func (c *ClaudeClient) toConverseOutput(message *anthropic.Message) *ConverseOutput {
+	output := c.toReviewCodeOutput(message)
+	return &ConverseOutput{
+		Reply:      strings.TrimSpace(output.ReviewResult),
+		TokensUsed: output.TokensUsed,
+		Usage:      output.Usage,
+		Provider:   output.Provider,
+		Model:      output.Model,
+	}
 }
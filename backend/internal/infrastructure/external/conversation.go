@@ -0,0 +1,202 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+)
+
+// ConversationalLLMProvider - レビューについての質問に答えられるLLMプロバイダ
+type ConversationalLLMProvider interface {
+	LLMProvider
+	// Converse - レビューしたコードとレビュー結果を踏まえて、会話の最後のユーザーのメッセージに返答する
+	Converse(ctx context.Context, input ConverseInput) (*ConverseOutput, error)
+}
+
+// ConverseInput - レビューについての会話の入力
+type ConverseInput struct {
+	Code            string
+	Language        string
+	Context         string
+	Review          string // レビュー結果（マークダウン）
+	KnowledgePrompt string // レビューで参照したナレッジから生成したプロンプト
+	// Messages - これまでの会話（古い順。user から始まり、最後はユーザーの新しいメッセージ）
+	Messages []ConversationMessage
+	// Temperature / MaxTokens - ユーザー設定による生成パラメータ（nil・0以下の場合はクライアントの設定値）
+	Temperature *float64
+	MaxTokens   int
+	// Locale - プロンプトと返答の言語（ja / en。空の場合は model.DefaultLocale）
+	Locale string
+}
+
+// ConversationMessage - 会話の1メッセージ
+type ConversationMessage struct {
+	Role    string // model.ReviewMessageRoleUser / model.ReviewMessageRoleAssistant
+	Content string
+}
+
+// ConverseOutput - 返答
+type ConverseOutput struct {
+	Reply      string                     // 返答（マークダウン）
+	Knowledge  *model.KnowledgeSuggestion // 会話から提案するナレッジ（提案がない・不正な場合は nil）
+	TokensUsed int                        // 入力 + 出力トークン数
+	Usage      model.TokenUsage           // トークン数の内訳
+	Provider   string                     // 実際に使用したプロバイダ
+	Model      string                     // 実際に使用したモデル（APIのレスポンス値）
+}
+
+// 各クライアントがインターフェースを実装していることを保証
+var (
+	_ ConversationalLLMProvider = (*ClaudeClient)(nil)
+	_ ConversationalLLMProvider = (*OpenAIChatClient)(nil)
+)
+
+// 会話の構造化出力のツール名 / スキーマ名
+const (
+	submitReplyToolName    = "submit_reply"
+	conversationSchemaName = "conversation_reply"
+	submitReplyDescription = "レビューについての質問への返答と、ナレッジの提案を提出する"
+)
+
+// knowledgeSuggestionSchema - ナレッジの提案（model.KnowledgeSuggestion）のJSON Schema
+var knowledgeSuggestionSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"title": map[string]interface{}{
+			"type":        "string",
+			"description": "ナレッジのタイトル（200文字以内）",
+		},
+		"content": map[string]interface{}{
+			"type":        "string",
+			"description": "今後のレビューで使うルール・判断基準",
+		},
+		"category": map[string]interface{}{
+			"type": "string",
+			"enum": []string{
+				model.CategoryErrorHandling, model.CategoryTesting, model.CategoryPerformance, model.CategorySecurity,
+				model.CategoryCleanCode, model.CategoryArchitecture, model.CategoryOther,
+			},
+		},
+		"priority": map[string]interface{}{
+			"type":        "integer",
+			"description": "重要度（1-5）",
+		},
+	},
+	"required":             []string{"title", "content", "category", "priority"},
+	"additionalProperties": false,
+}
+
+// conversationReplyProperties - 返答のプロパティ
+var conversationReplyProperties = map[string]interface{}{
+	"reply": map[string]interface{}{
+		"type":        "string",
+		"description": "ユーザーのメッセージへの返答（マークダウン）",
+	},
+	"knowledge": map[string]interface{}{
+		"anyOf": []interface{}{
+			knowledgeSuggestionSchema,
+			map[string]interface{}{"type": "null"},
+		},
+		"description": "今後のレビューに活かすべきルールがあればナレッジの提案。なければ null",
+	},
+}
+
+// conversationReplyRequired - 返答の必須プロパティ
+var conversationReplyRequired = []string{"reply", "knowledge"}
+
+// conversationReplySchema - 返答のJSON Schema（OpenAIの strict モードに準拠）
+var conversationReplySchema = map[string]interface{}{
+	"type":                 "object",
+	"properties":           conversationReplyProperties,
+	"required":             conversationReplyRequired,
+	"additionalProperties": false,
+}
+
+// conversationReply - LLMが返した返答
+type conversationReply struct {
+	Reply     string                     `json:"reply"`
+	Knowledge *model.KnowledgeSuggestion `json:"knowledge"`
+}
+
+// decodeConversationReply - LLMが返したJSONを返答に変換して検証
+// 提案されたナレッジが不正な場合は、返答は使い提案のみ捨てる
+func decodeConversationReply(data []byte) (*conversationReply, error) {
+	var reply conversationReply
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal conversation reply: %w", err)
+	}
+
+	reply.Reply = strings.TrimSpace(reply.Reply)
+	if reply.Reply == "" {
+		return nil, fmt.Errorf("reply is required")
+	}
+	if reply.Knowledge != nil {
+		reply.Knowledge.Title = strings.TrimSpace(reply.Knowledge.Title)
+		reply.Knowledge.Content = strings.TrimSpace(reply.Knowledge.Content)
+		reply.Knowledge.Category = strings.ToLower(strings.TrimSpace(reply.Knowledge.Category))
+		if err := reply.Knowledge.Validate(); err != nil {
+			reply.Knowledge = nil
+		}
+	}
+
+	return &reply, nil
+}
+
+// buildConversationSystemPrompt - レビューについての会話のシステムプロンプト
+// レビューしたコード・レビュー結果・参照したナレッジを含める。structured が true の場合はナレッジの提案の指示も含める
+func buildConversationSystemPrompt(input ConverseInput, structured bool) string {
+	english := isEnglish(input.Locale)
+
+	var b strings.Builder
+	if english {
+		b.WriteString(`You are the code reviewer who wrote the review below. The user is asking about the review or pushing back on an improvement.
+- Answer based on the code, the review and the user's rules. Explain the reasons concretely and show code when it helps
+- If the user's argument is valid, admit it and revise the improvement. If not, explain why you still recommend it
+- Keep answers concise and answer in English`)
+	} else {
+		b.WriteString(`あなたは以下のレビューを書いたコードレビュアーです。ユーザーがレビューについて質問したり、改善点に反論したりしています。
+- コード・レビュー結果・ユーザーのルールに基づいて答えてください。理由は具体的に説明し、必要に応じてコードを示してください
+- ユーザーの指摘が正しい場合は認めて改善点を見直し、そうでない場合はなぜ改善を勧めるのかを説明してください
+- 簡潔に日本語で答えてください`)
+	}
+	b.WriteString(inputHandlingRules(input.Locale))
+
+	if structured {
+		if english {
+			b.WriteString(`
+
+## Output format
+Return the answer following the given schema.
+- reply: the answer to the user's latest message (markdown)
+- knowledge: only when the conversation reveals a rule or preference of the user that should apply to future reviews (e.g. the user explains a team convention), propose it as knowledge (title, content, category, priority 1-5). Otherwise null`)
+		} else {
+			b.WriteString(`
+
+## 出力フォーマット
+返答は指定されたスキーマに従って返してください。
+- reply: ユーザーの最新のメッセージへの返答（マークダウン）
+- knowledge: 会話から今後のレビューにも適用すべきユーザーのルール・方針がわかった場合のみ（チームの規約を説明された等）、ナレッジとして提案する（title, content, category, priority 1-5）。それ以外は null`)
+		}
+	}
+
+	knowledgeHeading, codeHeading, reviewHeading, languageLabel, contextLabel := "## ユーザーのコーディング哲学・ルール", "## レビューしたコード", "## レビュー結果", "言語", "コンテキスト"
+	if english {
+		knowledgeHeading, codeHeading, reviewHeading, languageLabel, contextLabel = "## The user's coding philosophy and rules", "## Reviewed code", "## Review", "Language", "Context"
+	}
+
+	if input.KnowledgePrompt != "" {
+		fmt.Fprintf(&b, "\n\n%s\n%s", knowledgeHeading, input.KnowledgePrompt)
+	}
+	fmt.Fprintf(&b, "\n\n%s\n%s: %s\n\n", codeHeading, languageLabel, service.SanitizeFenceLanguage(input.Language))
+	if input.Context != "" {
+		fmt.Fprintf(&b, "%s:\n%s\n\n", contextLabel, service.WrapUntrusted(service.UntrustedContextTag, input.Context))
+	}
+	b.WriteString(service.WrapUntrusted(service.UntrustedCodeTag, service.FenceCode(input.Code, input.Language)))
+	fmt.Fprintf(&b, "\n\n%s\n%s", reviewHeading, input.Review)
+
+	return b.String()
+}
@@ -0,0 +1,90 @@
+package external
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// EmbeddingCache - Embeddingのキャッシュ
+// キャッシュの失敗でEmbeddingの生成を止めないため、エラーは返さない（取得できない場合はミス扱い）
+type EmbeddingCache interface {
+	// Get - キャッシュからEmbeddingを取得
+	Get(ctx context.Context, key string) ([]float32, bool)
+	// Set - Embeddingをキャッシュに保存
+	Set(ctx context.Context, key string, embedding []float32)
+}
+
+// EmbeddingCacheKey - モデルとテキストのハッシュからキャッシュキーを生成
+// モデルが変わるとベクトル空間が変わるため、モデル名をキーに含める
+func EmbeddingCacheKey(model, text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return model + ":" + hex.EncodeToString(sum[:])
+}
+
+// LRUEmbeddingCache - メモリ上のLRUキャッシュ（プロセス内でのみ共有）
+type LRUEmbeddingCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // 先頭が最近使ったエントリ
+	entries  map[string]*list.Element
+}
+
+// lruEntry - LRUキャッシュのエントリ
+type lruEntry struct {
+	key       string
+	embedding []float32
+}
+
+// NewLRUEmbeddingCache - コンストラクタ（capacity: 保持するEmbeddingの最大件数）
+func NewLRUEmbeddingCache(capacity int) *LRUEmbeddingCache {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &LRUEmbeddingCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+// Get - キャッシュからEmbeddingを取得
+func (c *LRUEmbeddingCache) Get(ctx context.Context, key string) ([]float32, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).embedding, true
+}
+
+// Set - Embeddingを保存し、上限を超えた場合は最も古いエントリを捨てる
+func (c *LRUEmbeddingCache) Set(ctx context.Context, key string, embedding []float32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEntry).embedding = embedding
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, embedding: embedding})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len - 保持しているEmbeddingの件数
+func (c *LRUEmbeddingCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,63 @@
+package external
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// APIError - 外部APIがエラーステータスを返した
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// IsUnavailable - 外部APIが一時的に利用できないことを示すエラーか判定
+// （サーキット遮断中・リトライ後も解消しなかったレート制限 / 5xx）
+func IsUnavailable(err error) bool {
+	if errors.Is(err, ErrCircuitOpen) {
+		return true
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return isUnavailableStatus(apiErr.StatusCode)
+	}
+
+	var anthropicErr *anthropic.Error
+	if errors.As(err, &anthropicErr) {
+		return isUnavailableStatus(anthropicErr.StatusCode)
+	}
+
+	return false
+}
+
+// isUnavailableStatus - 一時的な障害を示すHTTPステータスか判定
+func isUnavailableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// newAPIError - OpenAI形式のエラーレスポンスからAPIErrorを生成
+func newAPIError(provider string, statusCode int, body []byte) *APIError {
+	var errResp errorResponse
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error.Message == "" {
+		return &APIError{
+			Provider:   provider,
+			StatusCode: statusCode,
+			Message:    fmt.Sprintf("API error (status %d): %s", statusCode, string(body)),
+		}
+	}
+	return &APIError{
+		Provider:   provider,
+		StatusCode: statusCode,
+		Message:    fmt.Sprintf("%s API error: %s (type: %s)", provider, errResp.Error.Message, errResp.Error.Type),
+	}
+}
@@ -0,0 +1,175 @@
+package external
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// HashingEmbeddingClient - ネットワークを使わない決定的なEmbeddingクライアント（開発・テスト用）
+// テキストをトークンに分け、特徴量ハッシュ（feature hashing）で dim 次元のベクトルにする。
+//   - コード: 識別子を camelCase / snake_case の単語に分け、元の識別子と単語の両方を特徴量にする
+//   - 自然言語: 英数字は単語、日本語などの文字は2文字ずつ（bigram）を特徴量にする
+//   - 隣り合うトークンの組も特徴量にする
+//
+// 同じテキストは常に同じベクトルになり、語彙を共有するテキストほどコサイン類似度が高くなる。
+// 意味の近さは扱えないため、精度はAPIのEmbeddingより低い
+type HashingEmbeddingClient struct {
+	dim int
+}
+
+// NewHashingEmbeddingClient - コンストラクタ（dim: ベクトルの次元数。knowledge.embedding の次元に合わせる）
+func NewHashingEmbeddingClient(dim int) *HashingEmbeddingClient {
+	if dim <= 0 {
+		dim = 1536
+	}
+	return &HashingEmbeddingClient{dim: dim}
+}
+
+// GenerateEmbedding - テキストからEmbeddingベクトルを生成
+func (c *HashingEmbeddingClient) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	return c.embed(text), nil
+}
+
+// GenerateEmbeddings - 複数テキストから一括でEmbedding生成
+func (c *HashingEmbeddingClient) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embeddings[i] = c.embed(text)
+	}
+	return embeddings, nil
+}
+
+// embed - 特徴量の出現回数を符号付きでハッシュした次元に加算し、L2正規化する
+func (c *HashingEmbeddingClient) embed(text string) []float32 {
+	counts := map[string]int{}
+	tokens := tokenizeForHashing(text)
+	for i, token := range tokens {
+		counts[token]++
+		if i > 0 {
+			counts[tokens[i-1]+" "+token]++
+		}
+	}
+
+	vector := make([]float64, c.dim)
+	for feature, count := range counts {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		index := int(sum % uint64(c.dim))
+		// 衝突による偏りを打ち消すため、別のビットで符号を決める
+		sign := 1.0
+		if sum>>63 == 1 {
+			sign = -1.0
+		}
+		// 出現回数は対数で抑える（同じ単語の繰り返しに引きずられない）
+		vector[index] += sign * (1 + math.Log(float64(count)))
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+
+	embedding := make([]float32, c.dim)
+	if norm == 0 {
+		return embedding
+	}
+	for i, v := range vector {
+		embedding[i] = float32(v / norm)
+	}
+	return embedding
+}
+
+// tokenizeForHashing - テキストを特徴量のトークンに分ける（小文字化する）
+func tokenizeForHashing(text string) []string {
+	var tokens []string
+	var word []rune
+	var wide []rune
+
+	flushWord := func() {
+		if len(word) == 0 {
+			return
+		}
+		identifier := strings.ToLower(string(word))
+		parts := splitIdentifier(word)
+		if len(parts) > 1 {
+			tokens = append(tokens, identifier)
+		}
+		tokens = append(tokens, parts...)
+		word = word[:0]
+	}
+	flushWide := func() {
+		switch {
+		case len(wide) == 1:
+			tokens = append(tokens, string(wide))
+		case len(wide) > 1:
+			for i := 0; i+1 < len(wide); i++ {
+				tokens = append(tokens, string(wide[i:i+2]))
+			}
+		}
+		wide = wide[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'):
+			flushWide()
+			word = append(word, r)
+		case r >= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			flushWord()
+			wide = append(wide, unicode.ToLower(r))
+		default:
+			flushWord()
+			flushWide()
+		}
+	}
+	flushWord()
+	flushWide()
+
+	return tokens
+}
+
+// splitIdentifier - 識別子を camelCase / snake_case の境界で単語に分ける（小文字化する）
+// 例: parseHTTPRequest → parse, http, request / max_retry_count → max, retry, count
+func splitIdentifier(word []rune) []string {
+	var parts []string
+	start := 0
+	appendPart := func(end int) {
+		if end > start {
+			parts = append(parts, strings.ToLower(string(word[start:end])))
+		}
+	}
+
+	for i := 0; i < len(word); i++ {
+		r := word[i]
+		if r == '_' {
+			appendPart(i)
+			start = i + 1
+			continue
+		}
+		if i == start {
+			continue
+		}
+		prev := word[i-1]
+		switch {
+		// lower → Upper（parseHTTP の p|H）
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			appendPart(i)
+			start = i
+		// Upper → Upper + lower（HTTPRequest の P|R）
+		case unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(word) && unicode.IsLower(word[i+1]):
+			appendPart(i)
+			start = i
+		}
+	}
+	appendPart(len(word))
+
+	return parts
+}
+
+// HashingEmbeddingClientがインターフェースを実装していることを保証
+var _ EmbeddingClientInterface = (*HashingEmbeddingClient)(nil)
@@ -0,0 +1,75 @@
+package external
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cosine(a, b []float32) float64 {
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
+
+func TestHashingEmbeddingClient_GenerateEmbedding(t *testing.T) {
+	client := NewHashingEmbeddingClient(256)
+	ctx := context.Background()
+
+	code, err := client.GenerateEmbedding(ctx, "func parseHTTPRequest(r *http.Request) error { return fmt.Errorf(\"failed: %w\", err) }")
+	require.NoError(t, err)
+	again, err := client.GenerateEmbedding(ctx, "func parseHTTPRequest(r *http.Request) error { return fmt.Errorf(\"failed: %w\", err) }")
+	require.NoError(t, err)
+
+	t.Run("次元数が設定どおりで、L2正規化されている", func(t *testing.T) {
+		assert.Len(t, code, 256)
+		assert.InDelta(t, 1.0, math.Sqrt(cosine(code, code)), 1e-5)
+	})
+
+	t.Run("同じテキストは同じベクトル", func(t *testing.T) {
+		assert.Equal(t, code, again)
+	})
+
+	t.Run("語彙を共有するテキストほど類似度が高い", func(t *testing.T) {
+		related, _ := client.GenerateEmbedding(ctx, "エラーは fmt.Errorf の %w でラップして返す。HTTP request の parse 失敗も同様")
+		unrelated, _ := client.GenerateEmbedding(ctx, "テストはテーブル駆動で書き、サブテストに名前を付ける")
+		assert.Greater(t, cosine(code, related), cosine(code, unrelated))
+	})
+
+	t.Run("日本語は2文字ずつの特徴量で比較する", func(t *testing.T) {
+		a, _ := client.GenerateEmbedding(ctx, "エラーハンドリングの原則")
+		b, _ := client.GenerateEmbedding(ctx, "エラーハンドリングを徹底する")
+		c, _ := client.GenerateEmbedding(ctx, "命名規則を統一する")
+		assert.Greater(t, cosine(a, b), cosine(a, c))
+	})
+
+	t.Run("空のテキストはゼロベクトル", func(t *testing.T) {
+		empty, err := client.GenerateEmbedding(ctx, "  ")
+		require.NoError(t, err)
+		assert.Len(t, empty, 256)
+		assert.Zero(t, cosine(empty, empty))
+	})
+}
+
+func TestHashingEmbeddingClient_GenerateEmbeddings(t *testing.T) {
+	client := NewHashingEmbeddingClient(64)
+	ctx := context.Background()
+
+	embeddings, err := client.GenerateEmbeddings(ctx, []string{"foo", "bar"})
+	require.NoError(t, err)
+	require.Len(t, embeddings, 2)
+	single, _ := client.GenerateEmbedding(ctx, "bar")
+	assert.Equal(t, single, embeddings[1])
+}
+
+func TestTokenizeForHashing(t *testing.T) {
+	assert.Equal(t,
+		[]string{"parsehttprequest", "parse", "http", "request", "max_retry", "max", "retry", "エラ", "ラー", "処理"},
+		tokenizeForHashing("parseHTTPRequest(max_retry) エラー、処理"),
+	)
+}
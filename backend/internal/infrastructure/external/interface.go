@@ -2,15 +2,77 @@ package external
 
 import (
 	"context"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// LLMプロバイダ名
+const (
+	ProviderAnthropic        = "anthropic"
+	ProviderOpenAI           = "openai"
+	ProviderOllama           = "ollama"
+	ProviderOpenAICompatible = "openai_compatible"
 )
 
-// ClaudeClientInterface - Claude API クライアントのインターフェース
-type ClaudeClientInterface interface {
+// Embeddingプロバイダ名（openai は ProviderOpenAI）
+const (
+	ProviderHashing = "hashing" // ネットワークを使わない特徴量ハッシュ（開発・テスト用）
+)
+
+// LLMProvider - レビューを生成するLLMプロバイダのインターフェース
+type LLMProvider interface {
+	// Name - プロバイダ名を返す
+	Name() string
+	// Model - 設定されたモデル名を返す（レビュー結果のキャッシュキーに使う）
+	Model() string
+	// ReviewCode - コードをレビューする
 	ReviewCode(ctx context.Context, input ReviewCodeInput) (*ReviewCodeOutput, error)
 }
 
-// 元のClaudeClientがインターフェースを実装していることを保証
-var _ ClaudeClientInterface = (*ClaudeClient)(nil)
+// StreamingLLMProvider - レビューをトークン単位でストリーミングできるLLMプロバイダ
+type StreamingLLMProvider interface {
+	LLMProvider
+	// ReviewCodeStream - レビューを生成し、生成されたテキスト差分をonDeltaに逐次渡す
+	ReviewCodeStream(ctx context.Context, input ReviewCodeInput, onDelta func(text string)) (*ReviewCodeOutput, error)
+}
+
+// LLMProviderFactory - プロバイダ名とモデル名からLLMプロバイダを生成する（モデル名が空の場合はプロバイダの設定値）
+type LLMProviderFactory func(provider, model string) (LLMProvider, error)
+
+// ReviewCodeInput - レビュー入力
+type ReviewCodeInput struct {
+	Code            string
+	Language        string
+	Context         string
+	KnowledgePrompt string // ナレッジから生成したプロンプト
+	// ReviewInstructions - プロンプトテンプレートから生成したレビュー方針
+	// 空の場合は KnowledgePrompt を既定の方針に差し込む。出力形式の指示は各クライアントが付け足す
+	ReviewInstructions string
+	// Temperature / MaxTokens - ユーザー設定による生成パラメータ（nil・0以下の場合はクライアントの設定値）
+	Temperature *float64
+	MaxTokens   int
+	// Locale - プロンプトの言語（ja / en。空の場合は model.DefaultLocale）
+	Locale string
+	// StaticAnalysis - LLM を呼び出す前に計算した静的解析の結果（Go のコードのみ。nil の場合はプロンプトに含めない）
+	StaticAnalysis *model.StaticAnalysis
+}
+
+// ReviewCodeOutput - レビュー結果
+type ReviewCodeOutput struct {
+	ReviewResult string                        // マークダウン（構造化出力の場合は空）
+	Structured   *model.StructuredReviewResult // 検証済みの構造化データ（マークダウン出力の場合はnil）
+	ResultSource string                        // 構造化データの生成経路（model.ResultSource*）
+	TokensUsed   int                           // 入力 + 出力トークン数
+	Usage        model.TokenUsage              // トークン数の内訳（Embedding は含まない）
+	Provider     string                        // 実際に使用したプロバイダ
+	Model        string                        // 実際に使用したモデル（APIのレスポンス値）
+}
+
+// 各クライアントがインターフェースを実装していることを保証
+var (
+	_ StreamingLLMProvider = (*ClaudeClient)(nil)
+	_ LLMProvider          = (*OpenAIChatClient)(nil)
+)
 
 type EmbeddingClientInterface interface {
 	// GenerateEmbedding - テキストからEmbeddingを生成する
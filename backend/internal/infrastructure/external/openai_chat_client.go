@@ -0,0 +1,321 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// OpenAIChatClient - OpenAI Chat Completions API クライアント
+// OpenAI本家に加え、Ollama / llama.cpp などOpenAI互換APIを持つローカルサーバーにも利用する
+type OpenAIChatClient struct {
+	name        string
+	baseURL     string
+	apiKey      string
+	model       string
+	maxTokens   int
+	temperature float64
+	httpClient  *http.Client
+}
+
+// NewOpenAIChatClient - コンストラクタ
+// name: 記録用のプロバイダ名（openai, ollama, openai_compatible）
+// baseURL: APIのベースURL（例: https://api.openai.com/v1, http://localhost:11434/v1）
+// transport: リトライ・サーキットブレーカー用のトランスポート（nilの場合は http.DefaultTransport）
+func NewOpenAIChatClient(name, baseURL, apiKey, model string, maxTokens int, timeout time.Duration, transport http.RoundTripper) *OpenAIChatClient {
+	return &OpenAIChatClient{
+		name:        name,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		apiKey:      apiKey,
+		model:       model,
+		maxTokens:   maxTokens,
+		temperature: 0.7, // デフォルト
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+		},
+	}
+}
+
+// chatMessage - Chat Completions APIのメッセージ
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatCompletionRequest - Chat Completions APIのリクエスト
+type chatCompletionRequest struct {
+	Model          string              `json:"model"`
+	Messages       []chatMessage       `json:"messages"`
+	MaxTokens      int                 `json:"max_tokens,omitempty"`
+	Temperature    float64             `json:"temperature"`
+	ResponseFormat *chatResponseFormat `json:"response_format,omitempty"`
+}
+
+// chatResponseFormat - 出力形式の指定（Structured Outputs）
+type chatResponseFormat struct {
+	Type       string          `json:"type"`
+	JSONSchema *chatJSONSchema `json:"json_schema,omitempty"`
+}
+
+// chatJSONSchema - 出力を制約するJSON Schema
+type chatJSONSchema struct {
+	Name   string      `json:"name"`
+	Strict bool        `json:"strict"`
+	Schema interface{} `json:"schema"`
+}
+
+// chatCompletionResponse - Chat Completions APIのレスポンス
+type chatCompletionResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Index        int         `json:"index"`
+		Message      chatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+		// PromptTokensDetails - プロンプトキャッシュの内訳（対応しているサーバーのみ）
+		PromptTokensDetails struct {
+			CachedTokens int `json:"cached_tokens"`
+		} `json:"prompt_tokens_details"`
+	} `json:"usage"`
+}
+
+// Name - プロバイダ名
+func (c *OpenAIChatClient) Name() string {
+	return c.name
+}
+
+// Model - 設定されたモデル名
+func (c *OpenAIChatClient) Model() string {
+	return c.model
+}
+
+// ReviewCode - コードをレビュー
+// JSON Schema で構造化データを受け取り、検証に失敗した場合のみマークダウン出力で再生成する
+func (c *OpenAIChatClient) ReviewCode(ctx context.Context, input ReviewCodeInput) (*ReviewCodeOutput, error) {
+	chatResp, err := c.createChatCompletion(ctx, c.buildRequest(buildStructuredSystemPrompt(input), input, &chatResponseFormat{
+		Type: "json_schema",
+		JSONSchema: &chatJSONSchema{
+			Name:   reviewResultSchemaName,
+			Strict: true,
+			Schema: reviewResultSchema,
+		},
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	output := c.toReviewCodeOutput(chatResp)
+	structured, err := decodeStructuredReview([]byte(output.ReviewResult))
+	if err == nil {
+		output.ReviewResult = ""
+		output.Structured = structured
+		output.ResultSource = model.ResultSourceJSONSchema
+		return output, nil
+	}
+
+	// フォールバック: マークダウンで再生成
+	log.Printf("Warning: invalid json_schema review from %s, falling back to markdown: %v", c.name, err)
+	fallbackResp, err := c.createChatCompletion(ctx, c.buildRequest(buildSystemPrompt(input), input, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	fallbackOutput := c.toReviewCodeOutput(fallbackResp)
+	fallbackOutput.TokensUsed += output.TokensUsed
+	fallbackOutput.Usage = fallbackOutput.Usage.Add(output.Usage)
+	return fallbackOutput, nil
+}
+
+// buildRequest - Chat Completions APIのリクエストを生成
+func (c *OpenAIChatClient) buildRequest(systemPrompt string, input ReviewCodeInput, responseFormat *chatResponseFormat) chatCompletionRequest {
+	userPrompt := buildUserPrompt(input)
+
+	// ユーザー設定があれば生成パラメータを上書き
+	maxTokens := c.maxTokens
+	if input.MaxTokens > 0 {
+		maxTokens = input.MaxTokens
+	}
+	temperature := c.temperature
+	if input.Temperature != nil {
+		temperature = *input.Temperature
+	}
+
+	return chatCompletionRequest{
+		Model: c.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userPrompt},
+		},
+		MaxTokens:      maxTokens,
+		Temperature:    temperature,
+		ResponseFormat: responseFormat,
+	}
+}
+
+// createChatCompletion - Chat Completions APIを呼び出す
+func (c *OpenAIChatClient) createChatCompletion(ctx context.Context, reqBody chatCompletionRequest) (*chatCompletionResponse, error) {
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// HTTPリクエスト作成
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	// ローカルサーバーはAPIキー不要な場合がある
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	}
+
+	// APIリクエスト実行
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s API: %w", c.name, err)
+	}
+	defer resp.Body.Close()
+
+	// レスポンス読み取り
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// エラーチェック
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(c.name, resp.StatusCode, body)
+	}
+
+	// レスポンスパース
+	var chatResp chatCompletionResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	// データ検証
+	if len(chatResp.Choices) == 0 {
+		return nil, fmt.Errorf("no choices returned")
+	}
+
+	return &chatResp, nil
+}
+
+// toReviewCodeOutput - Chat Completions APIのレスポンスをレビュー結果に変換
+func (c *OpenAIChatClient) toReviewCodeOutput(chatResp *chatCompletionResponse) *ReviewCodeOutput {
+	// サーバーがモデル名を返さない場合は設定値を記録する
+	modelName := chatResp.Model
+	if modelName == "" {
+		modelName = c.model
+	}
+
+	// prompt_tokens はキャッシュから読み込んだ分を含むため、内訳では分けて記録する
+	cachedTokens := chatResp.Usage.PromptTokensDetails.CachedTokens
+
+	return &ReviewCodeOutput{
+		ReviewResult: chatResp.Choices[0].Message.Content,
+		TokensUsed:   chatResp.Usage.PromptTokens + chatResp.Usage.CompletionTokens,
+		Usage: model.TokenUsage{
+			InputTokens:     chatResp.Usage.PromptTokens - cachedTokens,
+			OutputTokens:    chatResp.Usage.CompletionTokens,
+			CacheReadTokens: cachedTokens,
+		},
+		ResultSource: model.ResultSourceMarkdown,
+		Provider:     c.name,
+		Model:        modelName,
+	}
+}
+
+// Converse - レビューについてのユーザーのメッセージに返答
+// JSON Schema で返答とナレッジの提案を受け取り、検証に失敗した場合のみテキストで返答を再生成する（ナレッジの提案なし）
+func (c *OpenAIChatClient) Converse(ctx context.Context, input ConverseInput) (*ConverseOutput, error) {
+	chatResp, err := c.createChatCompletion(ctx, c.buildConversationRequest(buildConversationSystemPrompt(input, true), input, &chatResponseFormat{
+		Type: "json_schema",
+		JSONSchema: &chatJSONSchema{
+			Name:   conversationSchemaName,
+			Strict: true,
+			Schema: conversationReplySchema,
+		},
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	output := c.toConverseOutput(chatResp)
+	reply, err := decodeConversationReply([]byte(output.Reply))
+	if err == nil {
+		output.Reply = reply.Reply
+		output.Knowledge = reply.Knowledge
+		return output, nil
+	}
+
+	// フォールバック: テキストで再生成
+	log.Printf("Warning: invalid json_schema reply from %s, falling back to text: %v", c.name, err)
+	fallbackResp, err := c.createChatCompletion(ctx, c.buildConversationRequest(buildConversationSystemPrompt(input, false), input, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	fallbackOutput := c.toConverseOutput(fallbackResp)
+	fallbackOutput.TokensUsed += output.TokensUsed
+	fallbackOutput.Usage = fallbackOutput.Usage.Add(output.Usage)
+	return fallbackOutput, nil
+}
+
+// buildConversationRequest - 会話のリクエストを生成（これまでの会話をそのままメッセージにする）
+func (c *OpenAIChatClient) buildConversationRequest(systemPrompt string, input ConverseInput, responseFormat *chatResponseFormat) chatCompletionRequest {
+	messages := make([]chatMessage, 0, len(input.Messages)+1)
+	messages = append(messages, chatMessage{Role: "system", Content: systemPrompt})
+	for _, m := range input.Messages {
+		role := "user"
+		if m.Role == model.ReviewMessageRoleAssistant {
+			role = "assistant"
+		}
+		messages = append(messages, chatMessage{Role: role, Content: m.Content})
+	}
+
+	// ユーザー設定があれば生成パラメータを上書き
+	maxTokens := c.maxTokens
+	if input.MaxTokens > 0 {
+		maxTokens = input.MaxTokens
+	}
+	temperature := c.temperature
+	if input.Temperature != nil {
+		temperature = *input.Temperature
+	}
+
+	return chatCompletionRequest{
+		Model:          c.model,
+		Messages:       messages,
+		MaxTokens:      maxTokens,
+		Temperature:    temperature,
+		ResponseFormat: responseFormat,
+	}
+}
+
+// toConverseOutput - Chat Completions APIのレスポンスを返答に変換
+func (c *OpenAIChatClient) toConverseOutput(chatResp *chatCompletionResponse) *ConverseOutput {
+	output := c.toReviewCodeOutput(chatResp)
+	return &ConverseOutput{
+		Reply:      strings.TrimSpace(output.ReviewResult),
+		TokensUsed: output.TokensUsed,
+		Usage:      output.Usage,
+		Provider:   output.Provider,
+		Model:      output.Model,
+	}
+}
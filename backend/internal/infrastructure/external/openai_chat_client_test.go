@@ -0,0 +1,376 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const structuredReviewJSON = `{
+	"summary": "シンプルで読みやすいコードです。",
+	"good_points": ["シンプル"],
+	"improvements": [
+		{"title": "エラーを無視している", "description": "エラーをラップして返す", "code_after": "", "severity": "HIGH"}
+	]
+}`
+
+func TestOpenAIChatClient_ReviewCode_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// リクエスト検証
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer test-api-key", r.Header.Get("Authorization"))
+
+		var reqBody chatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		assert.Equal(t, "gpt-4o-mini", reqBody.Model)
+		require.Len(t, reqBody.Messages, 2)
+		assert.Equal(t, "system", reqBody.Messages[0].Role)
+		assert.Contains(t, reqBody.Messages[0].Content, "エラーは必ずラップする")
+		assert.Equal(t, "user", reqBody.Messages[1].Role)
+		assert.Contains(t, reqBody.Messages[1].Content, "func main() {}")
+		require.NotNil(t, reqBody.ResponseFormat)
+		assert.Equal(t, "json_schema", reqBody.ResponseFormat.Type)
+		assert.Equal(t, "review_result", reqBody.ResponseFormat.JSONSchema.Name)
+		assert.True(t, reqBody.ResponseFormat.JSONSchema.Strict)
+
+		// レスポンス返却
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"model": "gpt-4o-mini-2024-07-18",
+			"choices": []map[string]interface{}{
+				{
+					"index":         0,
+					"message":       map[string]string{"role": "assistant", "content": structuredReviewJSON},
+					"finish_reason": "stop",
+				},
+			},
+			"usage": map[string]interface{}{
+				"prompt_tokens": 120, "completion_tokens": 30, "total_tokens": 150,
+				"prompt_tokens_details": map[string]int{"cached_tokens": 100},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewOpenAIChatClient(ProviderOpenAI, server.URL+"/v1/", "test-api-key", "gpt-4o-mini", 1024, 5*time.Second, nil)
+
+	output, err := client.ReviewCode(context.Background(), ReviewCodeInput{
+		Code:            "func main() {}",
+		Language:        "go",
+		KnowledgePrompt: "エラーは必ずラップする",
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, output.ReviewResult)
+	require.NotNil(t, output.Structured)
+	assert.Equal(t, "シンプルで読みやすいコードです。", output.Structured.Summary)
+	assert.Equal(t, []string{"シンプル"}, output.Structured.GoodPoints)
+	require.Len(t, output.Structured.Improvements, 1)
+	assert.Equal(t, "high", output.Structured.Improvements[0].Severity)
+	assert.Equal(t, model.ResultSourceJSONSchema, output.ResultSource)
+	assert.Equal(t, 150, output.TokensUsed)
+	// prompt_tokens のうちキャッシュから読み込んだ分は内訳を分ける
+	assert.Equal(t, model.TokenUsage{InputTokens: 20, OutputTokens: 30, CacheReadTokens: 100}, output.Usage)
+	assert.Equal(t, ProviderOpenAI, output.Provider)
+	assert.Equal(t, "gpt-4o-mini-2024-07-18", output.Model)
+}
+
+func TestOpenAIChatClient_ReviewCode_FallbackToMarkdown(t *testing.T) {
+	// スキーマに従わない応答の場合はマークダウンで再生成する
+	var requests []chatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody chatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		requests = append(requests, reqBody)
+
+		content := `{"summary":"","good_points":[],"improvements":[]}`
+		if reqBody.ResponseFormat == nil {
+			content = "### 良い点\n- シンプル"
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"role": "assistant", "content": content}},
+			},
+			"usage": map[string]int{"prompt_tokens": 100, "completion_tokens": 20},
+		})
+	}))
+	defer server.Close()
+
+	client := NewOpenAIChatClient(ProviderOpenAI, server.URL, "test-api-key", "gpt-4o-mini", 1024, 5*time.Second, nil)
+
+	output, err := client.ReviewCode(context.Background(), ReviewCodeInput{Code: "x", Language: "go"})
+
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.NotNil(t, requests[0].ResponseFormat)
+	assert.Nil(t, requests[1].ResponseFormat)
+	assert.Contains(t, requests[1].Messages[0].Content, "### 良い点")
+	assert.Equal(t, "### 良い点\n- シンプル", output.ReviewResult)
+	assert.Nil(t, output.Structured)
+	assert.Equal(t, model.ResultSourceMarkdown, output.ResultSource)
+	assert.Equal(t, 240, output.TokensUsed)
+}
+
+func TestOpenAIChatClient_ReviewCode_GenerationOverrides(t *testing.T) {
+	var requests []chatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody chatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		requests = append(requests, reqBody)
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"role": "assistant", "content": structuredReviewJSON}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewOpenAIChatClient(ProviderOpenAI, server.URL, "test-api-key", "gpt-4o-mini", 1024, 5*time.Second, nil)
+
+	// 指定がなければクライアントの設定値
+	_, err := client.ReviewCode(context.Background(), ReviewCodeInput{Code: "x", Language: "go"})
+	require.NoError(t, err)
+
+	// ユーザー設定の temperature / max_tokens で上書き
+	temperature := 0.0
+	_, err = client.ReviewCode(context.Background(), ReviewCodeInput{Code: "x", Language: "go", Temperature: &temperature, MaxTokens: 256})
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, 0.7, requests[0].Temperature)
+	assert.Equal(t, 1024, requests[0].MaxTokens)
+	assert.Equal(t, 0.0, requests[1].Temperature)
+	assert.Equal(t, 256, requests[1].MaxTokens)
+}
+
+func TestOpenAIChatClient_ReviewCode_EnglishLocale(t *testing.T) {
+	// 英語のロケールでは、プロンプトと（マークダウンの場合の）見出しを英語にする
+	var requests []chatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody chatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		requests = append(requests, reqBody)
+
+		content := `{"summary":"","good_points":[],"improvements":[]}`
+		if reqBody.ResponseFormat == nil {
+			content = "### Good points\n- Simple"
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"role": "assistant", "content": content}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewOpenAIChatClient(ProviderOpenAI, server.URL, "test-api-key", "gpt-4o-mini", 1024, 5*time.Second, nil)
+
+	_, err := client.ReviewCode(context.Background(), ReviewCodeInput{Code: "x", Language: "go", Context: "CLI", Locale: model.LocaleEnglish})
+
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Contains(t, requests[0].Messages[0].Content, "You are a code reviewer.")
+	assert.Contains(t, requests[0].Messages[0].Content, "Write every text field in English.")
+	assert.Contains(t, requests[0].Messages[1].Content, "## Code to review\nLanguage: go")
+	assert.Contains(t, requests[0].Messages[1].Content, "Context:\n<untrusted_context>")
+	assert.Contains(t, requests[1].Messages[0].Content, "### Good points")
+	assert.Contains(t, requests[1].Messages[0].Content, "### Summary")
+	assert.NotContains(t, requests[1].Messages[0].Content, "良い点")
+}
+
+func TestOpenAIChatClient_ReviewCode_StaticAnalysis(t *testing.T) {
+	// 静的解析の結果はコードの後に計算済みの事実として含める
+	var requests []chatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody chatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		requests = append(requests, reqBody)
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"role": "assistant", "content": structuredReviewJSON}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewOpenAIChatClient(ProviderOpenAI, server.URL, "test-api-key", "gpt-4o-mini", 1024, 5*time.Second, nil)
+	analysis := &model.StaticAnalysis{Findings: []model.AnalysisFinding{
+		{Rule: model.AnalysisRuleIgnoredError, StartLine: 2, EndLine: 2, Call: "os.ReadFile", Message: "os.ReadFile の最後の戻り値を _ で捨てています"},
+		{Rule: model.AnalysisRuleLongFunction, StartLine: 1, EndLine: 60, Function: "main", Message: "関数 main は 60 行です（しきい値 50 行）", Value: 60, Threshold: 50},
+	}}
+
+	_, err := client.ReviewCode(context.Background(), ReviewCodeInput{Code: "x", Language: "go", StaticAnalysis: analysis})
+	require.NoError(t, err)
+	_, err = client.ReviewCode(context.Background(), ReviewCodeInput{Code: "x", Language: "go", StaticAnalysis: analysis, Locale: model.LocaleEnglish})
+	require.NoError(t, err)
+	_, err = client.ReviewCode(context.Background(), ReviewCodeInput{Code: "x", Language: "go", StaticAnalysis: &model.StaticAnalysis{}})
+	require.NoError(t, err)
+	_, err = client.ReviewCode(context.Background(), ReviewCodeInput{Code: "x", Language: "python"})
+	require.NoError(t, err)
+
+	require.Len(t, requests, 4)
+	assert.Contains(t, requests[0].Messages[1].Content, "</untrusted_code>\n\n## 静的解析の結果\n")
+	assert.Contains(t, requests[0].Messages[1].Content, "- 2行目 [ignored_error] os.ReadFile の最後の戻り値を _ で捨てています\n")
+	assert.Contains(t, requests[0].Messages[1].Content, "- 1〜60行目 [long_function] 関数 main は 60 行です（しきい値 50 行）\n")
+	assert.Contains(t, requests[1].Messages[1].Content, "- line 2 [ignored_error] return value of os.ReadFile is discarded\n")
+	assert.Contains(t, requests[1].Messages[1].Content, "- lines 1-60 [long_function] function main is 60 lines long (threshold 50)\n")
+	assert.Contains(t, requests[2].Messages[1].Content, "- 指摘なし（")
+	assert.NotContains(t, requests[3].Messages[1].Content, "静的解析")
+}
+
+func TestOpenAIChatClient_ReviewCode_LocalServerWithoutAPIKey(t *testing.T) {
+	// Ollama / llama.cpp はAPIキー不要・モデル名を返さない場合がある
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"role": "assistant", "content": "レビュー結果"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewOpenAIChatClient(ProviderOllama, server.URL, "", "qwen2.5-coder:7b", 1024, 5*time.Second, nil)
+
+	output, err := client.ReviewCode(context.Background(), ReviewCodeInput{
+		Code:     "print('hello')",
+		Language: "python",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "レビュー結果", output.ReviewResult)
+	assert.Equal(t, ProviderOllama, output.Provider)
+	assert.Equal(t, "qwen2.5-coder:7b", output.Model)
+	assert.Equal(t, ProviderOllama, client.Name())
+}
+
+func TestOpenAIChatClient_ReviewCode_ErrorHandling(t *testing.T) {
+	tests := []struct {
+		name         string
+		statusCode   int
+		responseBody string
+		expectedMsg  string
+	}{
+		{
+			name:         "OpenAI形式のエラー",
+			statusCode:   http.StatusUnauthorized,
+			responseBody: `{"error":{"message":"Incorrect API key","type":"invalid_request_error"}}`,
+			expectedMsg:  "Incorrect API key",
+		},
+		{
+			name:         "JSON以外のエラー",
+			statusCode:   http.StatusBadGateway,
+			responseBody: "bad gateway",
+			expectedMsg:  "status 502",
+		},
+		{
+			name:         "choicesが空",
+			statusCode:   http.StatusOK,
+			responseBody: `{"choices":[]}`,
+			expectedMsg:  "no choices returned",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			client := NewOpenAIChatClient(ProviderOpenAI, server.URL, "test-api-key", "gpt-4o-mini", 1024, 5*time.Second, nil)
+
+			_, err := client.ReviewCode(context.Background(), ReviewCodeInput{Code: "x", Language: "go"})
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedMsg)
+		})
+	}
+}
+
+func TestOpenAIChatClient_Converse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody chatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+
+		// システムプロンプトにコード・レビュー結果・ナレッジ、続けてこれまでの会話
+		require.Len(t, reqBody.Messages, 4)
+		assert.Equal(t, "system", reqBody.Messages[0].Role)
+		assert.Contains(t, reqBody.Messages[0].Content, "func main() {}")
+		assert.Contains(t, reqBody.Messages[0].Content, "### 1. エラーを無視している")
+		assert.Contains(t, reqBody.Messages[0].Content, "エラーは必ずラップする")
+		assert.Equal(t, []chatMessage{
+			{Role: "user", Content: "なぜですか？"},
+			{Role: "assistant", Content: "原因を追えなくなるためです。"},
+			{Role: "user", Content: "このパッケージでは panic で落としています"},
+		}, reqBody.Messages[1:])
+		require.NotNil(t, reqBody.ResponseFormat)
+		assert.Equal(t, "conversation_reply", reqBody.ResponseFormat.JSONSchema.Name)
+
+		content := `{"reply": "それならこの指摘は不要です。", "knowledge": {"title": "main パッケージでは panic を許容する", "content": "起動時のエラーは panic でよい", "category": "Error_Handling", "priority": 3}}`
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"model": "gpt-4o-mini-2024-07-18",
+			"choices": []map[string]interface{}{
+				{"index": 0, "message": map[string]string{"role": "assistant", "content": content}, "finish_reason": "stop"},
+			},
+			"usage": map[string]interface{}{"prompt_tokens": 200, "completion_tokens": 40, "total_tokens": 240},
+		})
+	}))
+	defer server.Close()
+
+	input := ConverseInput{
+		Code:            "func main() {}",
+		Language:        "go",
+		Review:          "### 1. エラーを無視している",
+		KnowledgePrompt: "エラーは必ずラップする",
+		Messages: []ConversationMessage{
+			{Role: model.ReviewMessageRoleUser, Content: "なぜですか？"},
+			{Role: model.ReviewMessageRoleAssistant, Content: "原因を追えなくなるためです。"},
+			{Role: model.ReviewMessageRoleUser, Content: "このパッケージでは panic で落としています"},
+		},
+	}
+
+	client := NewOpenAIChatClient(ProviderOpenAI, server.URL+"/v1", "test-api-key", "gpt-4o-mini", 1024, 5*time.Second, nil)
+	output, err := client.Converse(context.Background(), input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "それならこの指摘は不要です。", output.Reply)
+	require.NotNil(t, output.Knowledge)
+	assert.Equal(t, model.CategoryErrorHandling, output.Knowledge.Category)
+	assert.Equal(t, 240, output.TokensUsed)
+	assert.Equal(t, "gpt-4o-mini-2024-07-18", output.Model)
+}
+
+func TestDecodeConversationReply(t *testing.T) {
+	// 登録できないナレッジの提案は捨て、返答は使う
+	reply, err := decodeConversationReply([]byte(`{"reply": " はい ", "knowledge": {"title": "命名", "content": "短くする", "category": "naming", "priority": 3}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "はい", reply.Reply)
+	assert.Nil(t, reply.Knowledge)
+
+	reply, err = decodeConversationReply([]byte(`{"reply": "はい", "knowledge": null}`))
+	require.NoError(t, err)
+	assert.Nil(t, reply.Knowledge)
+
+	_, err = decodeConversationReply([]byte(`{"reply": "", "knowledge": null}`))
+	assert.Error(t, err)
+}
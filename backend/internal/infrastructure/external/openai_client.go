@@ -21,13 +21,15 @@ type OpenAIClient struct {
 }
 
 // NewOpenAIClient - コンストラクタ
-func NewOpenAIClient(apiKey, model string, timeout time.Duration) *OpenAIClient {
+// transport: リトライ・サーキットブレーカー用のトランスポート（nilの場合は http.DefaultTransport）
+func NewOpenAIClient(apiKey, model string, timeout time.Duration, transport http.RoundTripper) *OpenAIClient {
 	return &OpenAIClient{
 		apiKey:  apiKey,
 		model:   model,
 		timeout: timeout,
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: transport,
 		},
 	}
 }
@@ -105,11 +107,7 @@ func (c *OpenAIClient) GenerateEmbedding(ctx context.Context, text string) ([]fl
 
 	// エラーチェック
 	if resp.StatusCode != http.StatusOK {
-		var errResp errorResponse
-		if err := json.Unmarshal(body, &errResp); err != nil {
-			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("OpenAI API error: %s (type: %s)", errResp.Error.Message, errResp.Error.Type)
+		return nil, newAPIError("OpenAI", resp.StatusCode, body)
 	}
 
 	// レスポンスパース
@@ -123,6 +121,8 @@ func (c *OpenAIClient) GenerateEmbedding(ctx context.Context, text string) ([]fl
 		return nil, fmt.Errorf("no embedding data returned")
 	}
 
+	c.recordUsage(ctx, &embResp)
+
 	return embResp.Data[0].Embedding, nil
 }
 
@@ -167,11 +167,7 @@ func (c *OpenAIClient) GenerateEmbeddings(ctx context.Context, texts []string) (
 
 	// エラーチェック
 	if resp.StatusCode != http.StatusOK {
-		var errResp errorResponse
-		if err := json.Unmarshal(body, &errResp); err != nil {
-			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("OpenAI API error: %s (type: %s)", errResp.Error.Message, errResp.Error.Type)
+		return nil, newAPIError("OpenAI", resp.StatusCode, body)
 	}
 
 	// レスポンスパース
@@ -185,6 +181,8 @@ func (c *OpenAIClient) GenerateEmbeddings(ctx context.Context, texts []string) (
 		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embResp.Data))
 	}
 
+	c.recordUsage(ctx, &embResp)
+
 	// 結果を配列に格納（インデックス順にソート）
 	embeddings := make([][]float32, len(texts))
 	for _, data := range embResp.Data {
@@ -196,3 +194,12 @@ func (c *OpenAIClient) GenerateEmbeddings(ctx context.Context, texts []string) (
 
 	return embeddings, nil
 }
+
+// recordUsage - レスポンスのトークン数をcontextの集計先に記録（コスト計算用）
+func (c *OpenAIClient) recordUsage(ctx context.Context, embResp *embeddingResponse) {
+	modelName := embResp.Model
+	if modelName == "" {
+		modelName = c.model
+	}
+	RecordEmbeddingUsage(ctx, modelName, embResp.Usage.PromptTokens)
+}
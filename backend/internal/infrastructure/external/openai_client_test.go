@@ -71,7 +71,7 @@ func TestOpenAIClient_GenerateEmbeddings_Success(t *testing.T) {
 }
 
 func TestOpenAIClient_GenerateEmbeddings_EmptyInput(t *testing.T) {
-	client := NewOpenAIClient("test-api-key", "text-embedding-3-small", 10*time.Second)
+	client := NewOpenAIClient("test-api-key", "text-embedding-3-small", 10*time.Second, nil)
 
 	ctx := context.Background()
 	texts := []string{}
@@ -83,7 +83,7 @@ func TestOpenAIClient_GenerateEmbeddings_EmptyInput(t *testing.T) {
 }
 
 func TestOpenAIClient_NewOpenAIClient(t *testing.T) {
-	client := NewOpenAIClient("test-api-key", "test-model", 5*time.Second)
+	client := NewOpenAIClient("test-api-key", "test-model", 5*time.Second, nil)
 
 	assert.NotNil(t, client)
 	assert.Equal(t, "test-api-key", client.apiKey)
@@ -0,0 +1,365 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen - サーキットブレーカーが開いているため呼び出しを遮断した
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// maxRetryAfter - Retry-Afterヘッダーで指定された待機時間の上限
+const maxRetryAfter = time.Minute
+
+// RetryPolicy - リトライ設定
+type RetryPolicy struct {
+	MaxRetries int           // 初回を除く最大リトライ回数
+	BaseDelay  time.Duration // 1回目のリトライまでの基準待機時間（以降は指数的に増加）
+	MaxDelay   time.Duration // 待機時間の上限
+}
+
+// CircuitState - サーキットブレーカーの状態
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // 正常（呼び出しを通す）
+	CircuitOpen     CircuitState = "open"      // 遮断中（呼び出しを即座に失敗させる）
+	CircuitHalfOpen CircuitState = "half_open" // 試行中（1件だけ通して回復を確認する）
+)
+
+// CircuitBreakerStatus - サーキットブレーカーの状態（/health 表示用）
+type CircuitBreakerStatus struct {
+	Name                string       `json:"name"`
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+}
+
+// CircuitBreaker - プロバイダ単位のサーキットブレーカー
+// 連続失敗が閾値に達すると open になり、openTimeout 経過後に half_open で1件だけ試行する
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	name             string
+	failureThreshold int
+	openTimeout      time.Duration
+	state            CircuitState
+	failures         int
+	openedAt         time.Time
+	probing          bool
+	now              func() time.Time
+}
+
+// NewCircuitBreaker - コンストラクタ
+func NewCircuitBreaker(name string, failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &CircuitBreaker{
+		name:             name,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		state:            CircuitClosed,
+		now:              time.Now,
+	}
+}
+
+// Allow - 呼び出してよいか判定
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return fmt.Errorf("%s: %w", b.name, ErrCircuitOpen)
+		}
+		b.state = CircuitHalfOpen
+		b.probing = true
+		return nil
+	case CircuitHalfOpen:
+		// 試行中の呼び出しが終わるまでは遮断する
+		if b.probing {
+			return fmt.Errorf("%s: %w", b.name, ErrCircuitOpen)
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// RecordSuccess - 成功を記録（closed に戻す）
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = CircuitClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// RecordFailure - 失敗を記録
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == CircuitHalfOpen || b.failures >= b.failureThreshold {
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+// release - 成否を記録せずに試行中の枠を解放
+func (b *CircuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// Status - 現在の状態を取得
+func (b *CircuitBreaker) Status() CircuitBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := CircuitBreakerStatus{
+		Name:                b.name,
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+	}
+	if b.state != CircuitClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
+
+// BreakerRegistry - プロバイダ名ごとのサーキットブレーカーを管理
+type BreakerRegistry struct {
+	mu               sync.Mutex
+	breakers         map[string]*CircuitBreaker
+	failureThreshold int
+	openTimeout      time.Duration
+}
+
+// NewBreakerRegistry - コンストラクタ
+func NewBreakerRegistry(failureThreshold int, openTimeout time.Duration) *BreakerRegistry {
+	return &BreakerRegistry{
+		breakers:         make(map[string]*CircuitBreaker),
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+	}
+}
+
+// Get - プロバイダのサーキットブレーカーを取得（なければ作成）
+func (r *BreakerRegistry) Get(name string) *CircuitBreaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	breaker, ok := r.breakers[name]
+	if !ok {
+		breaker = NewCircuitBreaker(name, r.failureThreshold, r.openTimeout)
+		r.breakers[name] = breaker
+	}
+	return breaker
+}
+
+// Statuses - 全プロバイダの状態を名前順で取得
+func (r *BreakerRegistry) Statuses() []CircuitBreakerStatus {
+	r.mu.Lock()
+	breakers := make([]*CircuitBreaker, 0, len(r.breakers))
+	for _, breaker := range r.breakers {
+		breakers = append(breakers, breaker)
+	}
+	r.mu.Unlock()
+
+	statuses := make([]CircuitBreakerStatus, len(breakers))
+	for i, breaker := range breakers {
+		statuses[i] = breaker.Status()
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// ResilientTransport - リトライとサーキットブレーカーを備えた http.RoundTripper
+// 429 / 5xx / 通信エラーを Retry-After またはジッター付き指数バックオフで再試行する
+type ResilientTransport struct {
+	base    http.RoundTripper
+	breaker *CircuitBreaker
+	policy  RetryPolicy
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+// NewResilientTransport - コンストラクタ
+// base が nil の場合は http.DefaultTransport を使う
+func NewResilientTransport(base http.RoundTripper, breaker *CircuitBreaker, policy RetryPolicy) *ResilientTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &ResilientTransport{
+		base:    base,
+		breaker: breaker,
+		policy:  policy,
+		sleep:   sleepContext,
+	}
+}
+
+// RoundTrip - リクエストを実行（必要に応じて再試行）
+func (t *ResilientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	// 再送できるようにボディを保持
+	getBody := req.GetBody
+	if req.Body != nil && req.Body != http.NoBody && getBody == nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		getBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req.Clone(ctx)
+		if getBody != nil {
+			body, err := getBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+			attemptReq.Body = body
+		}
+
+		if err := t.breaker.Allow(); err != nil {
+			if attemptReq.Body != nil {
+				attemptReq.Body.Close()
+			}
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if !shouldRetry(resp, err) {
+			if err != nil {
+				// 呼び出し元のキャンセルはプロバイダの成否として扱わない
+				t.breaker.release()
+			} else {
+				t.breaker.RecordSuccess()
+			}
+			return resp, err
+		}
+
+		// 429 はレート制限なので障害としては数えない
+		if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+			if ctx.Err() == nil {
+				t.breaker.RecordFailure()
+			} else {
+				t.breaker.release()
+			}
+		} else {
+			t.breaker.RecordSuccess()
+		}
+
+		if attempt >= t.policy.MaxRetries || ctx.Err() != nil {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt, resp)
+		if resp != nil {
+			// コネクションを再利用できるようボディを読み捨てる
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := t.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// backoff - 次の試行までの待機時間を計算
+func (t *ResilientTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	// サーバーから待機時間が指定されていればそれに従う
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header, time.Now()); ok {
+			return delay
+		}
+	}
+
+	// ジッター付き指数バックオフ（base * 2^attempt の 50%〜100%）
+	delay := t.policy.BaseDelay << attempt
+	if delay <= 0 || delay > t.policy.MaxDelay {
+		delay = t.policy.MaxDelay
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// shouldRetry - 再試行すべき応答か判定
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		// 呼び出し元のキャンセル・タイムアウトと、記録にないリクエスト（replay）は再試行しない
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) &&
+			!errors.Is(err, ErrCassetteInteractionNotFound)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// parseRetryAfter - Retry-After / retry-after-ms ヘッダーから待機時間を取得
+func parseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	var delay time.Duration
+	if ms := header.Get("retry-after-ms"); ms != "" {
+		value, err := strconv.ParseFloat(ms, 64)
+		if err != nil || value < 0 {
+			return 0, false
+		}
+		delay = time.Duration(value * float64(time.Millisecond))
+	} else if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			if seconds < 0 {
+				return 0, false
+			}
+			delay = time.Duration(seconds) * time.Second
+		} else if at, err := http.ParseTime(value); err == nil {
+			delay = at.Sub(now)
+			if delay < 0 {
+				delay = 0
+			}
+		} else {
+			return 0, false
+		}
+	} else {
+		return 0, false
+	}
+
+	if delay > maxRetryAfter {
+		delay = maxRetryAfter
+	}
+	return delay, true
+}
+
+// sleepContext - コンテキストがキャンセルされるまで待機
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,314 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingServer - 指定した回数だけエラーステータスを返し、その後は成功するスタブサーバー
+type failingServer struct {
+	*httptest.Server
+	calls  int32
+	bodies [][]byte
+}
+
+func newFailingServer(t *testing.T, failures int, failStatus int, header http.Header, successBody string) *failingServer {
+	t.Helper()
+	s := &failingServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.bodies = append(s.bodies, body)
+
+		call := int(atomic.AddInt32(&s.calls, 1))
+		if call <= failures {
+			for key, values := range header {
+				for _, v := range values {
+					w.Header().Add(key, v)
+				}
+			}
+			w.WriteHeader(failStatus)
+			w.Write([]byte(`{"error":{"message":"injected failure","type":"server_error"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(successBody))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// newTestTransport - 待機せずに待機時間だけ記録するトランスポート
+func newTestTransport(breaker *CircuitBreaker, maxRetries int) (*ResilientTransport, *[]time.Duration) {
+	delays := []time.Duration{}
+	transport := NewResilientTransport(nil, breaker, RetryPolicy{
+		MaxRetries: maxRetries,
+		BaseDelay:  100 * time.Millisecond,
+		MaxDelay:   time.Second,
+	})
+	transport.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	return transport, &delays
+}
+
+func postJSON(t *testing.T, client *http.Client, url, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestResilientTransport_RetriesServerErrors(t *testing.T) {
+	server := newFailingServer(t, 2, http.StatusServiceUnavailable, nil, `{"ok":true}`)
+	transport, delays := newTestTransport(NewCircuitBreaker("test", 5, time.Minute), 3)
+
+	resp := postJSON(t, &http.Client{Transport: transport}, server.URL, `{"input":"x"}`)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), server.calls)
+	// リトライ時もリクエストボディを再送する
+	for _, body := range server.bodies {
+		assert.Equal(t, `{"input":"x"}`, string(body))
+	}
+	// ジッター付き指数バックオフ（base * 2^attempt の 50%〜100%）
+	require.Len(t, *delays, 2)
+	assert.GreaterOrEqual(t, (*delays)[0], 50*time.Millisecond)
+	assert.LessOrEqual(t, (*delays)[0], 100*time.Millisecond)
+	assert.GreaterOrEqual(t, (*delays)[1], 100*time.Millisecond)
+	assert.LessOrEqual(t, (*delays)[1], 200*time.Millisecond)
+}
+
+func TestResilientTransport_HonoursRetryAfter(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        http.Header
+		expectedDelay time.Duration
+	}{
+		{
+			name:          "秒数指定",
+			header:        http.Header{"Retry-After": []string{"2"}},
+			expectedDelay: 2 * time.Second,
+		},
+		{
+			name:          "ミリ秒指定",
+			header:        http.Header{"Retry-After-Ms": []string{"1500"}},
+			expectedDelay: 1500 * time.Millisecond,
+		},
+		{
+			name:          "上限を超える指定は切り詰める",
+			header:        http.Header{"Retry-After": []string{"3600"}},
+			expectedDelay: maxRetryAfter,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFailingServer(t, 1, http.StatusTooManyRequests, tt.header, `{"ok":true}`)
+			transport, delays := newTestTransport(NewCircuitBreaker("test", 5, time.Minute), 3)
+
+			resp := postJSON(t, &http.Client{Transport: transport}, server.URL, `{}`)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, []time.Duration{tt.expectedDelay}, *delays)
+		})
+	}
+}
+
+func TestParseRetryAfter_HTTPDate(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	header := http.Header{"Retry-After": []string{now.Add(5 * time.Second).Format(http.TimeFormat)}}
+
+	delay, ok := parseRetryAfter(header, now)
+
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, delay)
+}
+
+func TestResilientTransport_GivesUpAfterMaxRetries(t *testing.T) {
+	server := newFailingServer(t, 10, http.StatusInternalServerError, nil, `{}`)
+	transport, _ := newTestTransport(NewCircuitBreaker("test", 10, time.Minute), 2)
+
+	resp := postJSON(t, &http.Client{Transport: transport}, server.URL, `{}`)
+
+	// 最後の応答をそのまま返す
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, int32(3), server.calls)
+}
+
+func TestResilientTransport_DoesNotRetryClientErrors(t *testing.T) {
+	server := newFailingServer(t, 1, http.StatusBadRequest, nil, `{}`)
+	transport, delays := newTestTransport(NewCircuitBreaker("test", 5, time.Minute), 3)
+
+	resp := postJSON(t, &http.Client{Transport: transport}, server.URL, `{}`)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, int32(1), server.calls)
+	assert.Empty(t, *delays)
+}
+
+func TestResilientTransport_StopsWhenContextCancelled(t *testing.T) {
+	server := newFailingServer(t, 10, http.StatusServiceUnavailable, nil, `{}`)
+	transport := NewResilientTransport(nil, NewCircuitBreaker("test", 10, time.Minute), RetryPolicy{
+		MaxRetries: 5,
+		BaseDelay:  time.Minute,
+		MaxDelay:   time.Minute,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, bytes.NewBufferString(`{}`))
+	require.NoError(t, err)
+
+	_, err = (&http.Client{Transport: transport}).Do(req)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), server.calls)
+}
+
+func TestResilientTransport_CircuitBreaker(t *testing.T) {
+	server := newFailingServer(t, 3, http.StatusBadGateway, nil, `{"ok":true}`)
+	breaker := NewCircuitBreaker(ProviderOpenAI, 3, 30*time.Second)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker.now = func() time.Time { return now }
+	transport, _ := newTestTransport(breaker, 5)
+	client := &http.Client{Transport: transport}
+
+	// 1. 連続失敗が閾値に達するとサーキットが開き、以降は呼び出さない
+	req, _ := http.NewRequest(http.MethodPost, server.URL, bytes.NewBufferString(`{}`))
+	_, err := client.Do(req)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.True(t, IsUnavailable(err))
+	assert.Equal(t, int32(3), server.calls)
+	assert.Equal(t, CircuitOpen, breaker.Status().State)
+	assert.Equal(t, 3, breaker.Status().ConsecutiveFailures)
+
+	req, _ = http.NewRequest(http.MethodPost, server.URL, bytes.NewBufferString(`{}`))
+	_, err = client.Do(req)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(3), server.calls)
+
+	// 2. openTimeout 経過後は half_open で試行し、成功すれば閉じる
+	now = now.Add(31 * time.Second)
+	resp := postJSON(t, client, server.URL, `{}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(4), server.calls)
+	assert.Equal(t, CircuitClosed, breaker.Status().State)
+	assert.Nil(t, breaker.Status().OpenedAt)
+}
+
+func TestCircuitBreaker_HalfOpenFailureReopens(t *testing.T) {
+	breaker := NewCircuitBreaker("test", 1, time.Second)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker.now = func() time.Time { return now }
+
+	require.NoError(t, breaker.Allow())
+	breaker.RecordFailure()
+	assert.Equal(t, CircuitOpen, breaker.Status().State)
+
+	now = now.Add(2 * time.Second)
+	require.NoError(t, breaker.Allow())
+	assert.Equal(t, CircuitHalfOpen, breaker.Status().State)
+	// 試行中は他の呼び出しを通さない
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+
+	breaker.RecordFailure()
+	assert.Equal(t, CircuitOpen, breaker.Status().State)
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+}
+
+func TestBreakerRegistry_Statuses(t *testing.T) {
+	registry := NewBreakerRegistry(1, time.Minute)
+	registry.Get(ProviderOpenAI)
+	registry.Get(ProviderAnthropic).RecordFailure()
+
+	// 同じ名前では同じブレーカーを返す
+	assert.Same(t, registry.Get(ProviderOpenAI), registry.Get(ProviderOpenAI))
+
+	statuses := registry.Statuses()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, ProviderAnthropic, statuses[0].Name)
+	assert.Equal(t, CircuitOpen, statuses[0].State)
+	assert.Equal(t, ProviderOpenAI, statuses[1].Name)
+	assert.Equal(t, CircuitClosed, statuses[1].State)
+}
+
+// redirectTransport - SDKのリクエストをテストサーバーに向ける
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClaudeClient_ReviewCode_RetriesThroughTransport(t *testing.T) {
+	server := newFailingServer(t, 2, 529, nil, `{
+		"id": "msg_test",
+		"type": "message",
+		"role": "assistant",
+		"model": "claude-test",
+		"content": [{
+			"type": "tool_use",
+			"id": "toolu_test",
+			"name": "submit_review",
+			"input": {"summary": "良いコードです。", "good_points": ["シンプル"], "improvements": []}
+		}],
+		"stop_reason": "tool_use",
+		"usage": {"input_tokens": 10, "output_tokens": 5}
+	}`)
+	target, _ := url.Parse(server.URL)
+
+	transport, delays := newTestTransport(NewCircuitBreaker(ProviderAnthropic, 5, time.Minute), 3)
+	transport.base = &redirectTransport{target: target}
+	client := NewClaudeClient("test-api-key", "claude-test", 1024, 5*time.Second, 5*time.Second, transport)
+
+	output, err := client.ReviewCode(context.Background(), ReviewCodeInput{Code: "x", Language: "go"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), server.calls) // SDKのリトライは無効化され、トランスポートだけが再試行する
+	assert.Len(t, *delays, 2)
+	assert.Equal(t, model.ResultSourceToolUse, output.ResultSource)
+	assert.Equal(t, "良いコードです。", output.Structured.Summary)
+	assert.Equal(t, 15, output.TokensUsed)
+}
+
+func TestClaudeClient_ReviewCode_CircuitOpenIsUnavailable(t *testing.T) {
+	breaker := NewCircuitBreaker(ProviderAnthropic, 1, time.Minute)
+	breaker.RecordFailure()
+	transport, _ := newTestTransport(breaker, 3)
+	client := NewClaudeClient("test-api-key", "claude-test", 1024, 5*time.Second, 5*time.Second, transport)
+
+	_, err := client.ReviewCode(context.Background(), ReviewCodeInput{Code: "x", Language: "go"})
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.True(t, IsUnavailable(err))
+}
+
+func TestIsUnavailable(t *testing.T) {
+	assert.True(t, IsUnavailable(&APIError{StatusCode: http.StatusTooManyRequests}))
+	assert.True(t, IsUnavailable(&APIError{StatusCode: http.StatusBadGateway}))
+	assert.False(t, IsUnavailable(&APIError{StatusCode: http.StatusUnauthorized}))
+	assert.False(t, IsUnavailable(errors.New("failed to unmarshal response")))
+}
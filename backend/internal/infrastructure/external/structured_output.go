@@ -0,0 +1,106 @@
+package external
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// 構造化出力のツール名 / スキーマ名
+const (
+	submitReviewToolName    = "submit_review"
+	reviewResultSchemaName  = "review_result"
+	submitReviewDescription = "コードレビューの結果を構造化して提出する"
+)
+
+// improvementSchema - 改善点（model.Improvement）のJSON Schema
+var improvementSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"title": map[string]interface{}{
+			"type":        "string",
+			"description": "改善点のタイトル",
+		},
+		"description": map[string]interface{}{
+			"type":        "string",
+			"description": "問題点と、なぜ改善すべきかの説明",
+		},
+		"start_line": map[string]interface{}{
+			"type":        "integer",
+			"description": "対象の開始行（レビュー対象のコードの1行目を1とする。特定できない場合は0）",
+		},
+		"end_line": map[string]interface{}{
+			"type":        "integer",
+			"description": "対象の終了行（この行を含む。特定できない場合は0）",
+		},
+		"code_before": map[string]interface{}{
+			"type":        "string",
+			"description": "対象の改善前のコード（レビュー対象のコードから該当行をそのまま抜き出す）。不要な場合は空文字",
+		},
+		"code_after": map[string]interface{}{
+			"type":        "string",
+			"description": "改善後のコード。不要な場合は空文字",
+		},
+		"severity": map[string]interface{}{
+			"type": "string",
+			"enum": []string{"low", "medium", "high"},
+		},
+	},
+	"required":             []string{"title", "description", "start_line", "end_line", "code_before", "code_after", "severity"},
+	"additionalProperties": false,
+}
+
+// reviewResultProperties - レビュー結果（model.StructuredReviewResult）のプロパティ
+var reviewResultProperties = map[string]interface{}{
+	"summary": map[string]interface{}{
+		"type":        "string",
+		"description": "総合評価（1-2文）",
+	},
+	"good_points": map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"type": "string"},
+	},
+	"improvements": map[string]interface{}{
+		"type":  "array",
+		"items": improvementSchema,
+	},
+}
+
+// reviewResultRequired - レビュー結果の必須プロパティ
+var reviewResultRequired = []string{"summary", "good_points", "improvements"}
+
+// reviewResultSchema - レビュー結果のJSON Schema（OpenAIの strict モードに準拠）
+var reviewResultSchema = map[string]interface{}{
+	"type":                 "object",
+	"properties":           reviewResultProperties,
+	"required":             reviewResultRequired,
+	"additionalProperties": false,
+}
+
+// decodeStructuredReview - LLMが返したJSONを構造化データに変換して検証
+func decodeStructuredReview(data []byte) (*model.StructuredReviewResult, error) {
+	var result model.StructuredReviewResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal structured review: %w", err)
+	}
+
+	// 表記ゆれを正規化
+	result.Summary = strings.TrimSpace(result.Summary)
+	if result.GoodPoints == nil {
+		result.GoodPoints = []string{}
+	}
+	if result.Improvements == nil {
+		result.Improvements = []model.Improvement{}
+	}
+	for i := range result.Improvements {
+		result.Improvements[i].Severity = strings.ToLower(strings.TrimSpace(result.Improvements[i].Severity))
+	}
+
+	if err := result.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid structured review: %w", err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,65 @@
+package external
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeStructuredReview(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expectedMsg string
+	}{
+		{
+			name: "正常なJSON",
+			data: structuredReviewJSON,
+		},
+		{
+			name:        "JSONではない",
+			data:        "### 良い点\n- シンプル",
+			expectedMsg: "failed to unmarshal",
+		},
+		{
+			name:        "summaryが空",
+			data:        `{"summary":" ","good_points":["a"],"improvements":[]}`,
+			expectedMsg: "summary is required",
+		},
+		{
+			name:        "改善点のタイトルが空",
+			data:        `{"summary":"s","good_points":[],"improvements":[{"title":"","description":"d","severity":"low"}]}`,
+			expectedMsg: "improvements[0].title is required",
+		},
+		{
+			name:        "不正な重要度",
+			data:        `{"summary":"s","good_points":[],"improvements":[{"title":"t","description":"d","severity":"critical"}]}`,
+			expectedMsg: "severity must be one of",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := decodeStructuredReview([]byte(tt.data))
+
+			if tt.expectedMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, result.Summary)
+			assert.Equal(t, "high", result.Improvements[0].Severity) // 大文字は正規化される
+		})
+	}
+}
+
+func TestDecodeStructuredReview_NormalizesNilSlices(t *testing.T) {
+	result, err := decodeStructuredReview([]byte(`{"summary":"問題ありません"}`))
+
+	require.NoError(t, err)
+	assert.NotNil(t, result.GoodPoints)
+	assert.NotNil(t, result.Improvements)
+}
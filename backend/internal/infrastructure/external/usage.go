@@ -0,0 +1,50 @@
+package external
+
+import (
+	"context"
+	"sync"
+)
+
+// EmbeddingUsageRecorder - 1リクエストの間に消費したEmbeddingのトークン数を集計する
+// EmbeddingClientInterface の戻り値を変えずに使用量を取り出すため、contextで受け渡す
+type EmbeddingUsageRecorder struct {
+	mu     sync.Mutex
+	tokens int
+	model  string
+}
+
+type embeddingUsageKey struct{}
+
+// WithEmbeddingUsageRecorder - Embeddingの使用量を集計するcontextを生成
+func WithEmbeddingUsageRecorder(ctx context.Context) (context.Context, *EmbeddingUsageRecorder) {
+	recorder := &EmbeddingUsageRecorder{}
+	return context.WithValue(ctx, embeddingUsageKey{}, recorder), recorder
+}
+
+// RecordEmbeddingUsage - contextに集計先があればEmbeddingの使用量を加算
+func RecordEmbeddingUsage(ctx context.Context, model string, tokens int) {
+	recorder, ok := ctx.Value(embeddingUsageKey{}).(*EmbeddingUsageRecorder)
+	if !ok {
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.tokens += tokens
+	if model != "" {
+		recorder.model = model
+	}
+}
+
+// Tokens - 集計したトークン数
+func (r *EmbeddingUsageRecorder) Tokens() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.tokens
+}
+
+// Model - 使用したEmbeddingモデル
+func (r *EmbeddingUsageRecorder) Model() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.model
+}
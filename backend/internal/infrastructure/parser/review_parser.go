@@ -7,29 +7,88 @@ import (
 	"github.com/s7r8/reviewapp/internal/domain/model"
 )
 
+// reviewVocabulary - レビュー結果のマークダウンの見出しと、読み取れなかった場合の文言（ロケールごと）
+type reviewVocabulary struct {
+	GoodPoints         string // 良い点の見出し
+	Summary            string // 総合評価の見出し
+	Example            string // 改善後のコードの前に置く行
+	AgreedBy           string // 指摘したモデル
+	Location           string // 改善点の対象のファイルと行
+	Line               string // 1行（%d）
+	Lines              string // 行範囲（%d〜%d）
+	DefaultSummary     string
+	DefaultGoodPoint   string
+	DefaultDescription string
+}
+
+// reviewVocabularies - ロケールごとの見出し（プロンプトの出力フォーマットと揃える）
+var reviewVocabularies = map[string]reviewVocabulary{
+	model.LocaleJapanese: {
+		GoodPoints:         "良い点",
+		Summary:            "総合評価",
+		Example:            "改善例：",
+		AgreedBy:           "指摘したモデル",
+		Location:           "対象",
+		Line:               "%d行目",
+		Lines:              "%d〜%d行目",
+		DefaultSummary:     "詳細は下記をご確認ください。",
+		DefaultGoodPoint:   "コードの基本的な構造は良好です",
+		DefaultDescription: "改善が推奨されます",
+	},
+	model.LocaleEnglish: {
+		GoodPoints:         "Good points",
+		Summary:            "Summary",
+		Example:            "Example:",
+		AgreedBy:           "Reported by",
+		Location:           "Location",
+		Line:               "line %d",
+		Lines:              "lines %d-%d",
+		DefaultSummary:     "See the details below.",
+		DefaultGoodPoint:   "The basic structure of the code is sound",
+		DefaultDescription: "An improvement is recommended",
+	},
+}
+
+// vocabularyFor - ロケールの見出し（未対応のロケールは既定のロケール）
+func vocabularyFor(locale string) reviewVocabulary {
+	return reviewVocabularies[model.ResolveLocale(locale)]
+}
+
+// 見出しはロケールによらず、どの言語でも読み取る（LLMが指定と異なる言語の見出しで返す場合があるため）
+const (
+	summaryHeading           = `###+?\s*(?i:総合評価|summary)`
+	summaryHeadingPattern    = summaryHeading + `\s*\n`
+	goodPointsHeadingPattern = `###+?\s*(?i:良い点|good points)\s*\n`
+)
+
+// exampleLinePattern - 改善後のコードの前に置く行（説明には含めない）
+var exampleLinePattern = regexp.MustCompile(`^(?:改善[例案]|(?i:example|suggested fix|improved code))[:：]`)
+
 // ParseReviewMarkdown - マークダウン形式のレビュー結果を構造化データに変換
-func ParseReviewMarkdown(markdown string) *model.StructuredReviewResult {
+// 見出しが見つからない場合の文言は locale の言語にする
+func ParseReviewMarkdown(markdown, locale string) *model.StructuredReviewResult {
+	vocabulary := vocabularyFor(locale)
 	result := &model.StructuredReviewResult{
 		GoodPoints:   []string{},
 		Improvements: []model.Improvement{},
 	}
 
 	// サマリーを抽出
-	result.Summary = extractSummary(markdown)
+	result.Summary = extractSummary(markdown, vocabulary)
 
 	// 良い点を抽出
-	result.GoodPoints = extractGoodPoints(markdown)
+	result.GoodPoints = extractGoodPoints(markdown, vocabulary)
 
 	// 改善点を抽出
-	result.Improvements = extractImprovements(markdown)
+	result.Improvements = extractImprovements(markdown, vocabulary)
 
 	return result
 }
 
 // extractSummary - 総合評価セクションから抽出
-func extractSummary(text string) string {
-	// ### 総合評価 または ## 総合評価
-	re := regexp.MustCompile(`###+?\s*総合評価\s*\n([\s\S]*?)(?:\n##|$)`)
+func extractSummary(text string, vocabulary reviewVocabulary) string {
+	// ### 総合評価 / ### Summary または ## 総合評価
+	re := regexp.MustCompile(summaryHeadingPattern + `([\s\S]*?)(?:\n##|$)`)
 	if match := re.FindStringSubmatch(text); len(match) > 1 {
 		lines := strings.Split(match[1], "\n")
 		for _, line := range lines {
@@ -39,15 +98,15 @@ func extractSummary(text string) string {
 			}
 		}
 	}
-	return "詳細は下記をご確認ください。"
+	return vocabulary.DefaultSummary
 }
 
 // extractGoodPoints - 良い点セクションから箇条書きを抽出
-func extractGoodPoints(text string) []string {
+func extractGoodPoints(text string, vocabulary reviewVocabulary) []string {
 	points := []string{}
 
-	// ### 良い点 または ## 良い点
-	re := regexp.MustCompile(`###+?\s*良い点\s*\n([\s\S]*?)(?:\n##|$)`)
+	// ### 良い点 / ### Good points または ## 良い点
+	re := regexp.MustCompile(goodPointsHeadingPattern + `([\s\S]*?)(?:\n##|$)`)
 	if match := re.FindStringSubmatch(text); len(match) > 1 {
 		content := match[1]
 		lines := strings.Split(content, "\n")
@@ -66,14 +125,14 @@ func extractGoodPoints(text string) []string {
 	}
 
 	if len(points) == 0 {
-		points = append(points, "コードの基本的な構造は良好です")
+		points = append(points, vocabulary.DefaultGoodPoint)
 	}
 
 	return points
 }
 
 // extractImprovements - 改善点セクションを抽出
-func extractImprovements(text string) []model.Improvement {
+func extractImprovements(text string, vocabulary reviewVocabulary) []model.Improvement {
 	improvements := []model.Improvement{}
 
 	// ### 1. タイトル または ## 1. タイトル の形式でセクションを探す
@@ -93,7 +152,7 @@ func extractImprovements(text string) []model.Improvement {
 			contentEnd = matches[i+1][0]
 		} else {
 			// 総合評価セクションを探す
-			summaryRe := regexp.MustCompile(`\n###+?\s*総合評価`)
+			summaryRe := regexp.MustCompile(`\n` + summaryHeading)
 			if loc := summaryRe.FindStringIndex(text[contentStart:]); loc != nil {
 				contentEnd = contentStart + loc[0]
 			}
@@ -102,7 +161,7 @@ func extractImprovements(text string) []model.Improvement {
 		content := text[contentStart:contentEnd]
 
 		// 説明とコードを抽出
-		description := extractDescription(content)
+		description := extractDescription(content, vocabulary)
 		codeAfter := extractCodeBlock(content)
 
 		// 重要度を判定
@@ -120,7 +179,7 @@ func extractImprovements(text string) []model.Improvement {
 }
 
 // extractDescription - 説明文を抽出（箇条書き + 通常テキスト）
-func extractDescription(content string) string {
+func extractDescription(content string, vocabulary reviewVocabulary) string {
 	lines := strings.Split(content, "\n")
 	descriptions := []string{}
 
@@ -137,8 +196,8 @@ func extractDescription(content string) string {
 			continue
 		}
 
-		// "改善例：" "改善案：" をスキップ
-		if regexp.MustCompile(`^改善[例案][:：]`).MatchString(trimmed) {
+		// "改善例：" "改善案：" "Example:" をスキップ
+		if exampleLinePattern.MatchString(trimmed) {
 			continue
 		}
 
@@ -156,7 +215,7 @@ func extractDescription(content string) string {
 	}
 
 	if len(descriptions) == 0 {
-		return "改善が推奨されます"
+		return vocabulary.DefaultDescription
 	}
 
 	return strings.Join(descriptions, "\n")
@@ -179,6 +238,7 @@ func determineSeverity(title, description string) string {
 	highKeywords := []string{
 		"重大", "脆弱性", "エラーハンドリング", "エラー処理",
 		"セキュリティ", "危険", "バグ", "クリティカル",
+		"critical", "vulnerab", "error handling", "security", "danger", "bug",
 	}
 	for _, keyword := range highKeywords {
 		if strings.Contains(text, keyword) {
@@ -191,6 +251,9 @@ func determineSeverity(title, description string) string {
 		"パフォーマンス", "効率", "最適化",
 		"クリーンコード", "保守性", "可読性",
 		"テスト", "ドキュメント",
+		"performance", "efficien", "optimiz",
+		"clean code", "maintainab", "readab",
+		"test", "documentation",
 	}
 	for _, keyword := range mediumKeywords {
 		if strings.Contains(text, keyword) {
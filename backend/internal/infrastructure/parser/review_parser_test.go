@@ -115,7 +115,7 @@ if __name__ == '__main__':
 ### 総合評価
 基本的な二分探索アルゴリズムは正しく実装されていますが、エラーハンドリング、入力検証、テストカバレッジの観点で改善の余地があります。`
 
-	result := ParseReviewMarkdown(markdown)
+	result := ParseReviewMarkdown(markdown, "ja")
 
 	// デバッグ出力
 	t.Logf("\n=== Parse Result ===")
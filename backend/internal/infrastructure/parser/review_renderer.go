@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// RenderReviewMarkdown - 構造化データをマークダウン形式のレビュー結果に変換
+// ParseReviewMarkdown が読み取れる形式（良い点 → 改善点 → 総合評価）で、見出しは locale の言語で出力する
+func RenderReviewMarkdown(result *model.StructuredReviewResult, language, locale string) string {
+	vocabulary := vocabularyFor(locale)
+	var b strings.Builder
+
+	// 良い点
+	fmt.Fprintf(&b, "### %s\n", vocabulary.GoodPoints)
+	for _, point := range result.GoodPoints {
+		fmt.Fprintf(&b, "- %s\n", point)
+	}
+
+	// 改善点
+	for i, imp := range result.Improvements {
+		fmt.Fprintf(&b, "\n### %d. %s\n\n", i+1, imp.Title)
+		for _, line := range strings.Split(imp.Description, "\n") {
+			if trimmed := strings.TrimSpace(line); trimmed != "" {
+				fmt.Fprintf(&b, "- %s\n", strings.TrimPrefix(trimmed, "- "))
+			}
+		}
+		// 対象の行（差分のレビューの場合はファイルも）を示す
+		if location := renderLocation(imp, vocabulary); location != "" {
+			fmt.Fprintf(&b, "- %s: %s\n", vocabulary.Location, location)
+		}
+		// 複数モデルの結果を統合した場合は、指摘したモデルを示す
+		if len(imp.AgreedBy) > 0 {
+			fmt.Fprintf(&b, "- %s: %s\n", vocabulary.AgreedBy, strings.Join(imp.AgreedBy, ", "))
+		}
+		if imp.CodeAfter != "" {
+			fmt.Fprintf(&b, "\n%s\n```%s\n%s\n```\n", vocabulary.Example, language, imp.CodeAfter)
+		}
+	}
+
+	// 総合評価
+	fmt.Fprintf(&b, "\n### %s\n%s\n", vocabulary.Summary, result.Summary)
+
+	return b.String()
+}
+
+// renderLocation - 改善点の対象（"path 10〜14行目"）。行もファイルもない場合は空
+func renderLocation(imp model.Improvement, vocabulary reviewVocabulary) string {
+	var lines string
+	switch {
+	case imp.StartLine == 0:
+	case imp.EndLine <= imp.StartLine:
+		lines = fmt.Sprintf(vocabulary.Line, imp.StartLine)
+	default:
+		lines = fmt.Sprintf(vocabulary.Lines, imp.StartLine, imp.EndLine)
+	}
+	return strings.TrimSpace(imp.FilePath + " " + lines)
+}
@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderReviewMarkdown_RoundTrip(t *testing.T) {
+	// 描画したマークダウンは ParseReviewMarkdown で同じ内容に戻せる
+	structured := &model.StructuredReviewResult{
+		Summary:    "全体的に読みやすいコードです。",
+		GoodPoints: []string{"関数が短い", "命名が明確"},
+		Improvements: []model.Improvement{
+			{
+				Title:       "エラーを握りつぶしている",
+				Description: "エラーが無視されている\nfmt.Errorfでラップして返す",
+				CodeAfter:   "if err != nil {\n\treturn fmt.Errorf(\"failed to load: %w\", err)\n}",
+				Severity:    "high",
+			},
+			{
+				Title:       "変数名",
+				Description: "短すぎる変数名",
+				Severity:    "low",
+			},
+		},
+	}
+
+	markdown := RenderReviewMarkdown(structured, "go", model.DefaultLocale)
+	parsed := ParseReviewMarkdown(markdown, model.DefaultLocale)
+
+	assert.Contains(t, markdown, "```go\n")
+	assert.Equal(t, structured.Summary, parsed.Summary)
+	assert.Equal(t, structured.GoodPoints, parsed.GoodPoints)
+	assert.Len(t, parsed.Improvements, 2)
+	for i, imp := range structured.Improvements {
+		assert.Equal(t, imp.Title, parsed.Improvements[i].Title)
+		assert.Equal(t, imp.Description, parsed.Improvements[i].Description)
+		assert.Equal(t, imp.CodeAfter, parsed.Improvements[i].CodeAfter)
+	}
+}
+
+func TestRenderReviewMarkdown_English(t *testing.T) {
+	// 英語の見出しで描画し、英語のロケールでパースして同じ内容に戻せる
+	structured := &model.StructuredReviewResult{
+		Summary:    "The code is easy to read.",
+		GoodPoints: []string{"Short functions"},
+		Improvements: []model.Improvement{
+			{
+				Title:       "Missing error handling",
+				Description: "Return the error with context",
+				CodeAfter:   "return fmt.Errorf(\"load: %w\", err)",
+				Severity:    "high",
+				AgreedBy:    []string{"anthropic:claude", "openai:gpt-4o"},
+			},
+		},
+	}
+
+	markdown := RenderReviewMarkdown(structured, "go", model.LocaleEnglish)
+	parsed := ParseReviewMarkdown(markdown, model.LocaleEnglish)
+
+	assert.Contains(t, markdown, "### Good points\n")
+	assert.Contains(t, markdown, "- Reported by: anthropic:claude, openai:gpt-4o\n")
+	assert.Contains(t, markdown, "\nExample:\n```go\n")
+	assert.Contains(t, markdown, "### Summary\n")
+	assert.Equal(t, structured.Summary, parsed.Summary)
+	assert.Equal(t, structured.GoodPoints, parsed.GoodPoints)
+	if assert.Len(t, parsed.Improvements, 1) {
+		assert.Equal(t, "Missing error handling", parsed.Improvements[0].Title)
+		assert.Equal(t, structured.Improvements[0].CodeAfter, parsed.Improvements[0].CodeAfter)
+		assert.Equal(t, "high", parsed.Improvements[0].Severity)
+	}
+}
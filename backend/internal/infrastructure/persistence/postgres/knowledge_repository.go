@@ -442,6 +442,7 @@ func (r *KnowledgeRepository) SearchBySimilarity(
 		if len(embeddingVec.Slice()) > 0 {
 			k.Embedding = embeddingVec.Slice()
 		}
+		k.RelevanceScore = &similarity
 
 		knowledges = append(knowledges, k)
 	}
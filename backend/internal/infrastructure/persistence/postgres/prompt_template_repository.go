@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// PromptTemplateRepository - PostgreSQL実装
+type PromptTemplateRepository struct {
+	db *sql.DB
+}
+
+// NewPromptTemplateRepository - コンストラクタ
+func NewPromptTemplateRepository(db *sql.DB) *PromptTemplateRepository {
+	return &PromptTemplateRepository{db: db}
+}
+
+// promptTemplateColumns - SELECT対象のカラム
+const promptTemplateColumns = `
+	id, name, version, content, description, is_active,
+	created_by, created_at, activated_at
+`
+
+// Create - テンプレートを新しいバージョンとして作成
+// バージョンは同名テンプレートの最大値+1（同時作成時は UNIQUE(name, version) で片方が失敗する）
+func (r *PromptTemplateRepository) Create(ctx context.Context, template *model.PromptTemplate) error {
+	query := `
+		INSERT INTO prompt_templates (
+			id, name, version, content, description, is_active, created_by, created_at
+		)
+		SELECT $1, $2, COALESCE(MAX(version), 0) + 1, $3, $4, false, $5, $6
+		FROM prompt_templates
+		WHERE name = $2
+		RETURNING version
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		template.ID,
+		template.Name,
+		template.Content,
+		template.Description,
+		template.CreatedBy,
+		template.CreatedAt,
+	).Scan(&template.Version)
+	if err != nil {
+		return fmt.Errorf("failed to create prompt template: %w", err)
+	}
+
+	template.IsActive = false
+	return nil
+}
+
+// FindByID - IDでテンプレートを取得
+func (r *PromptTemplateRepository) FindByID(ctx context.Context, id string) (*model.PromptTemplate, error) {
+	query := `SELECT ` + promptTemplateColumns + ` FROM prompt_templates WHERE id = $1`
+
+	template, err := scanPromptTemplate(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", model.ErrPromptTemplateNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find prompt template: %w", err)
+	}
+
+	return template, nil
+}
+
+// FindActive - 有効なバージョンを取得
+func (r *PromptTemplateRepository) FindActive(ctx context.Context, name string) (*model.PromptTemplate, error) {
+	query := `SELECT ` + promptTemplateColumns + ` FROM prompt_templates WHERE name = $1 AND is_active = true`
+
+	template, err := scanPromptTemplate(r.db.QueryRowContext(ctx, query, name))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", model.ErrPromptTemplateNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active prompt template: %w", err)
+	}
+
+	return template, nil
+}
+
+// ListByName - 同名テンプレートの全バージョンを新しい順に取得
+func (r *PromptTemplateRepository) ListByName(ctx context.Context, name string) ([]*model.PromptTemplate, error) {
+	query := `SELECT ` + promptTemplateColumns + ` FROM prompt_templates WHERE name = $1 ORDER BY version DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []*model.PromptTemplate{}
+	for rows.Next() {
+		template, err := scanPromptTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan prompt template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate prompt templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// Activate - 指定したバージョンを有効化し、同名の他バージョンを無効化
+func (r *PromptTemplateRepository) Activate(ctx context.Context, id string) (*model.PromptTemplate, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 対象を行ロックして名前を取得
+	var name string
+	err = tx.QueryRowContext(ctx, `SELECT name FROM prompt_templates WHERE id = $1 FOR UPDATE`, id).Scan(&name)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", model.ErrPromptTemplateNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find prompt template: %w", err)
+	}
+
+	// 部分ユニークインデックス（name WHERE is_active）に違反しないよう先に無効化する
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE prompt_templates
+		SET is_active = false
+		WHERE name = $1 AND is_active = true AND id <> $2
+	`, name, id); err != nil {
+		return nil, fmt.Errorf("failed to deactivate prompt templates: %w", err)
+	}
+
+	query := `
+		UPDATE prompt_templates
+		SET is_active = true, activated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + promptTemplateColumns
+
+	template, err := scanPromptTemplate(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to activate prompt template: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return template, nil
+}
+
+// rowScanner - *sql.Row / *sql.Rows 共通のScan
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanPromptTemplate - 1行をエンティティに変換
+func scanPromptTemplate(row rowScanner) (*model.PromptTemplate, error) {
+	template := &model.PromptTemplate{}
+	var description, createdBy sql.NullString
+	var activatedAt sql.NullTime
+
+	err := row.Scan(
+		&template.ID,
+		&template.Name,
+		&template.Version,
+		&template.Content,
+		&description,
+		&template.IsActive,
+		&createdBy,
+		&template.CreatedAt,
+		&activatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nullable フィールドの処理
+	if description.Valid {
+		template.Description = description.String
+	}
+	if createdBy.Valid {
+		template.CreatedBy = &createdBy.String
+	}
+	if activatedAt.Valid {
+		template.ActivatedAt = &activatedAt.Time
+	}
+
+	return template, nil
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// QuotaRepository - PostgreSQL実装
+type QuotaRepository struct {
+	db *sql.DB
+}
+
+// NewQuotaRepository - コンストラクタ
+func NewQuotaRepository(db *sql.DB) *QuotaRepository {
+	return &QuotaRepository{db: db}
+}
+
+// FindByUserID - ユーザー個別の上限を取得
+func (r *QuotaRepository) FindByUserID(ctx context.Context, userID string) (*model.UserQuota, error) {
+	query := `
+		SELECT user_id, reviews_per_day, tokens_per_month, updated_at
+		FROM user_quotas
+		WHERE user_id = $1
+	`
+
+	quota := &model.UserQuota{}
+	var reviewsPerDay, tokensPerMonth sql.NullInt64
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&quota.UserID,
+		&reviewsPerDay,
+		&tokensPerMonth,
+		&quota.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", model.ErrUserQuotaNotFound, userID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user quota: %w", err)
+	}
+
+	if reviewsPerDay.Valid {
+		v := int(reviewsPerDay.Int64)
+		quota.ReviewsPerDay = &v
+	}
+	if tokensPerMonth.Valid {
+		v := int(tokensPerMonth.Int64)
+		quota.TokensPerMonth = &v
+	}
+
+	return quota, nil
+}
+
+// Upsert - ユーザー個別の上限を作成・更新
+func (r *QuotaRepository) Upsert(ctx context.Context, quota *model.UserQuota) error {
+	query := `
+		INSERT INTO user_quotas (user_id, reviews_per_day, tokens_per_month, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE SET
+			reviews_per_day = EXCLUDED.reviews_per_day,
+			tokens_per_month = EXCLUDED.tokens_per_month,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query, quota.UserID, quota.ReviewsPerDay, quota.TokensPerMonth, quota.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert user quota: %w", err)
+	}
+
+	return nil
+}
+
+// Delete - ユーザー個別の上限を削除
+func (r *QuotaRepository) Delete(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM user_quotas WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user quota: %w", err)
+	}
+
+	return nil
+}
+
+// QuotaCounter - reviews テーブルから消費量を集計するカウンター
+// レビューの保存自体が消費の記録になるため Add は何もしない
+type QuotaCounter struct {
+	db *sql.DB
+}
+
+// NewQuotaCounter - コンストラクタ
+func NewQuotaCounter(db *sql.DB) *QuotaCounter {
+	return &QuotaCounter{db: db}
+}
+
+// quotaTokensExpr - レビュー1件のトークン数
+// 004 マイグレーション以前のレビューは内訳がないため tokens_used を使う
+const quotaTokensExpr = `
+	CASE WHEN input_tokens + output_tokens > 0
+		THEN input_tokens + output_tokens + cache_creation_tokens + cache_read_tokens
+		ELSE COALESCE(tokens_used, 0)
+	END + embedding_tokens
+`
+
+// Usage - 当日のレビュー数と当月のトークン数を集計（削除済みのレビューも含む）
+// 実行待ち・実行中の非同期のレビューは受け付けた時点で1件と数え、失敗したレビューは数えない（再実行の受け付けで改めて確認する）
+// 統合したレビュー（llm_provider = 'ensemble'）はLLMを呼び出さないため、レビュー数に数えずトークン数（Embedding）のみ数える
+func (c *QuotaCounter) Usage(ctx context.Context, userID string, now time.Time) (model.QuotaUsage, error) {
+	dayStart, monthStart := model.QuotaPeriodStarts(now)
+
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE created_at >= $2 AND llm_provider <> $5),
+			COALESCE(SUM(` + quotaTokensExpr + `), 0)
+		FROM reviews
+		WHERE user_id = $1 AND created_at >= $3 AND status <> $4
+	`
+
+	var usage model.QuotaUsage
+	err := c.db.QueryRowContext(ctx, query, userID, dayStart, monthStart, model.ReviewStatusFailed, model.EnsembleProvider).Scan(
+		&usage.ReviewsToday,
+		&usage.TokensThisMonth,
+	)
+	if err != nil {
+		return model.QuotaUsage{}, fmt.Errorf("failed to count quota usage: %w", err)
+	}
+
+	return usage, nil
+}
+
+// Add - 何もしない（reviews テーブルに保存済み）
+func (c *QuotaCounter) Add(ctx context.Context, userID string, now time.Time, reviews, tokens int) error {
+	return nil
+}
@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// ReviewMessageRepository - PostgreSQL実装
+type ReviewMessageRepository struct {
+	db *sql.DB
+}
+
+// NewReviewMessageRepository - コンストラクタ
+func NewReviewMessageRepository(db *sql.DB) *ReviewMessageRepository {
+	return &ReviewMessageRepository{db: db}
+}
+
+// reviewMessageColumns - SELECT する列（scanReviewMessage と同じ順）
+const reviewMessageColumns = `
+	id, review_id, user_id, role, content, suggested_knowledge, knowledge_id,
+	llm_provider, llm_model, tokens_used,
+	input_tokens, output_tokens, cache_creation_tokens, cache_read_tokens,
+	cost, cost_currency, created_at
+`
+
+// CreateExchange - ユーザーのメッセージとLLMの返答を1つのトランザクションで保存
+func (r *ReviewMessageRepository) CreateExchange(ctx context.Context, question, reply *model.ReviewMessage) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, message := range []*model.ReviewMessage{question, reply} {
+		if err := insertReviewMessage(ctx, tx, message); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// insertReviewMessage - メッセージを1件保存
+func insertReviewMessage(ctx context.Context, tx *sql.Tx, message *model.ReviewMessage) error {
+	var suggestionJSON []byte
+	if message.SuggestedKnowledge != nil {
+		data, err := json.Marshal(message.SuggestedKnowledge)
+		if err != nil {
+			return fmt.Errorf("failed to marshal suggested knowledge: %w", err)
+		}
+		suggestionJSON = data
+	}
+
+	currency := message.CostCurrency
+	if currency == "" {
+		currency = "USD"
+	}
+
+	query := `
+		INSERT INTO review_messages (
+			id, review_id, user_id, role, content, suggested_knowledge, knowledge_id,
+			llm_provider, llm_model, tokens_used,
+			input_tokens, output_tokens, cache_creation_tokens, cache_read_tokens,
+			cost, cost_currency, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+	`
+
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		message.ID,
+		message.ReviewID,
+		message.UserID,
+		message.Role,
+		message.Content,
+		suggestionJSON,
+		message.KnowledgeID,
+		message.LLMProvider,
+		message.LLMModel,
+		message.TokensUsed,
+		message.Usage.InputTokens,
+		message.Usage.OutputTokens,
+		message.Usage.CacheCreationTokens,
+		message.Usage.CacheReadTokens,
+		message.Cost,
+		currency,
+		message.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create review message: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID - ユーザーのレビューのメッセージを取得（なければ model.ErrReviewMessageNotFound）
+func (r *ReviewMessageRepository) FindByID(ctx context.Context, userID, reviewID, messageID string) (*model.ReviewMessage, error) {
+	query := `SELECT ` + reviewMessageColumns + `
+		FROM review_messages
+		WHERE id = $1 AND review_id = $2 AND user_id = $3
+	`
+
+	message, err := scanReviewMessage(r.db.QueryRowContext(ctx, query, messageID, reviewID, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", model.ErrReviewMessageNotFound, messageID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find review message: %w", err)
+	}
+
+	return message, nil
+}
+
+// ListByReviewID - レビューの会話を古い順に取得
+func (r *ReviewMessageRepository) ListByReviewID(ctx context.Context, userID, reviewID string) ([]*model.ReviewMessage, error) {
+	// 質問と返答は同じ時刻で保存することがあるため、role でも並べる（user → assistant）
+	query := `SELECT ` + reviewMessageColumns + `
+		FROM review_messages
+		WHERE review_id = $1 AND user_id = $2
+		ORDER BY created_at, role DESC, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, reviewID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []*model.ReviewMessage{}
+	for rows.Next() {
+		message, err := scanReviewMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate review messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// SetKnowledgeID - メッセージから作成したナレッジを記録（作成済みの場合は model.ErrReviewMessageKnowledgeExists）
+func (r *ReviewMessageRepository) SetKnowledgeID(ctx context.Context, messageID, knowledgeID string) error {
+	query := `
+		UPDATE review_messages
+		SET knowledge_id = $2
+		WHERE id = $1 AND knowledge_id IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, messageID, knowledgeID)
+	if err != nil {
+		return fmt.Errorf("failed to set knowledge of review message: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return model.ErrReviewMessageKnowledgeExists
+	}
+
+	return nil
+}
+
+// scanReviewMessage - 1行をメッセージに変換（*sql.Row と *sql.Rows の両方で使う）
+func scanReviewMessage(row interface{ Scan(dest ...any) error }) (*model.ReviewMessage, error) {
+	message := &model.ReviewMessage{}
+	var suggestionJSON []byte
+	var knowledgeID sql.NullString
+	err := row.Scan(
+		&message.ID,
+		&message.ReviewID,
+		&message.UserID,
+		&message.Role,
+		&message.Content,
+		&suggestionJSON,
+		&knowledgeID,
+		&message.LLMProvider,
+		&message.LLMModel,
+		&message.TokensUsed,
+		&message.Usage.InputTokens,
+		&message.Usage.OutputTokens,
+		&message.Usage.CacheCreationTokens,
+		&message.Usage.CacheReadTokens,
+		&message.Cost,
+		&message.CostCurrency,
+		&message.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(suggestionJSON) > 0 {
+		var suggestion model.KnowledgeSuggestion
+		if err := json.Unmarshal(suggestionJSON, &suggestion); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal suggested knowledge: %w", err)
+		}
+		message.SuggestedKnowledge = &suggestion
+	}
+	if knowledgeID.Valid {
+		message.KnowledgeID = &knowledgeID.String
+	}
+	return message, nil
+}
@@ -24,6 +24,11 @@ func NewReviewRepository(db *sql.DB) *ReviewRepository {
 
 // Create - レビューを作成
 func (r *ReviewRepository) Create(ctx context.Context, review *model.Review) error {
+	return r.CreateAll(ctx, []*model.Review{review})
+}
+
+// CreateAll - 複数のレビューを1つのトランザクションで作成（1件でも失敗した場合はすべて作成しない）
+func (r *ReviewRepository) CreateAll(ctx context.Context, reviews []*model.Review) error {
 	// トランザクション開始
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -31,8 +36,25 @@ func (r *ReviewRepository) Create(ctx context.Context, review *model.Review) err
 	}
 	defer tx.Rollback()
 
+	for _, review := range reviews {
+		if err := insertReview(ctx, tx, review); err != nil {
+			return err
+		}
+	}
+
+	// トランザクションコミット
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// insertReview - レビューと参照したナレッジをトランザクション内で INSERT
+func insertReview(ctx context.Context, tx *sql.Tx, review *model.Review) error {
 	// ★ StructuredResult を JSONB に変換
 	var reviewResultJSON []byte
+	var err error
 	if review.StructuredResult != nil {
 		reviewResultJSON, err = json.Marshal(review.StructuredResult)
 		if err != nil {
@@ -43,13 +65,32 @@ func (r *ReviewRepository) Create(ctx context.Context, review *model.Review) err
 		reviewResultJSON = []byte(`{"summary":"","good_points":[],"improvements":[]}`)
 	}
 
+	signalsJSON, err := marshalPromptInjectionSignals(review.PromptInjectionSignals)
+	if err != nil {
+		return err
+	}
+	jobInputJSON, err := marshalJobOptions(review.JobOptions)
+	if err != nil {
+		return err
+	}
+	analysisJSON, err := marshalStaticAnalysis(review.StaticAnalysis)
+	if err != nil {
+		return err
+	}
+
 	// 1. reviewsテーブルにINSERT
 	query := `
 		INSERT INTO reviews (
-			id, user_id, code, language, context,
-			review_result, llm_provider, llm_model, tokens_used,
-			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			id, user_id, code, language, locale, context,
+			review_result, result_source, llm_provider, llm_model, tokens_used,
+			prompt_template_id, prompt_template_version,
+			input_tokens, output_tokens, cache_creation_tokens, cache_read_tokens, embedding_tokens,
+			cost, cost_currency,
+			cache_key, cache_hit, cached_from_review_id, ensemble_id,
+			prompt_injection_signals, session_id, file_path, input_format,
+			status, is_async, job_input, error_message, attempts, started_at, completed_at,
+			rerun_of_review_id, follow_up_of_review_id, static_analysis, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40)
 	`
 
 	_, err = tx.ExecContext(
@@ -59,11 +100,40 @@ func (r *ReviewRepository) Create(ctx context.Context, review *model.Review) err
 		review.UserID,
 		review.Code,
 		review.Language,
+		localeOrDefault(review.Locale),
 		review.Context,
 		reviewResultJSON,
+		resultSourceOrDefault(review.ResultSource),
 		review.LLMProvider,
 		review.LLMModel,
 		review.TokensUsed,
+		review.PromptTemplateID,
+		review.PromptTemplateVersion,
+		review.Usage.InputTokens,
+		review.Usage.OutputTokens,
+		review.Usage.CacheCreationTokens,
+		review.Usage.CacheReadTokens,
+		review.Usage.EmbeddingTokens,
+		review.Cost,
+		costCurrencyOrDefault(review.CostCurrency),
+		nullIfEmpty(review.CacheKey),
+		review.CacheHit,
+		review.CachedFromReviewID,
+		review.EnsembleID,
+		signalsJSON,
+		review.SessionID,
+		nullIfEmpty(review.FilePath),
+		inputFormatOrDefault(review.InputFormat),
+		statusOrDefault(review.Status),
+		review.Async,
+		jobInputJSON,
+		nullIfEmpty(review.ErrorMessage),
+		review.Attempts,
+		review.StartedAt,
+		review.CompletedAt,
+		review.RerunOfReviewID,
+		review.FollowUpOfReviewID,
+		analysisJSON,
 		review.CreatedAt,
 		review.UpdatedAt,
 	)
@@ -90,11 +160,6 @@ func (r *ReviewRepository) Create(ctx context.Context, review *model.Review) err
 		}
 	}
 
-	// トランザクションコミット
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	return nil
 }
 
@@ -103,29 +168,64 @@ func (r *ReviewRepository) FindByID(ctx context.Context, id string) (*model.Revi
 	// 1. reviewsテーブルから基本情報を取得
 	query := `
 		SELECT 
-			id, user_id, code, language, context,
-			review_result, llm_provider, llm_model, tokens_used,
+			id, user_id, code, language, locale, context,
+			review_result, result_source, llm_provider, llm_model, tokens_used,
+			prompt_template_id, prompt_template_version,
+			input_tokens, output_tokens, cache_creation_tokens, cache_read_tokens, embedding_tokens,
+			cost, cost_currency,
+			cache_key, cache_hit, cached_from_review_id, ensemble_id, prompt_injection_signals,
+			session_id, file_path, input_format, rerun_of_review_id, follow_up_of_review_id, static_analysis,
+			status, is_async, job_input, error_message, attempts, started_at, completed_at,
 			feedback_score, feedback_comment, created_at, updated_at, deleted_at
 		FROM reviews
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	review := &model.Review{}
-	var context, llmProvider, llmModel, feedbackComment sql.NullString
-	var reviewResultJSON []byte
+	var context, llmProvider, llmModel, feedbackComment, promptTemplateID, cacheKey, cachedFromReviewID, ensembleID, sessionID, filePath, errorMessage, rerunOf, followUpOf sql.NullString
+	var reviewResultJSON, signalsJSON, jobInputJSON, analysisJSON []byte
 	var feedbackScore sql.NullInt32
-	var deletedAt sql.NullTime
+	var startedAt, completedAt, deletedAt sql.NullTime
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&review.ID,
 		&review.UserID,
 		&review.Code,
 		&review.Language,
+		&review.Locale,
 		&context,
 		&reviewResultJSON,
+		&review.ResultSource,
 		&llmProvider,
 		&llmModel,
 		&review.TokensUsed,
+		&promptTemplateID,
+		&review.PromptTemplateVersion,
+		&review.Usage.InputTokens,
+		&review.Usage.OutputTokens,
+		&review.Usage.CacheCreationTokens,
+		&review.Usage.CacheReadTokens,
+		&review.Usage.EmbeddingTokens,
+		&review.Cost,
+		&review.CostCurrency,
+		&cacheKey,
+		&review.CacheHit,
+		&cachedFromReviewID,
+		&ensembleID,
+		&signalsJSON,
+		&sessionID,
+		&filePath,
+		&review.InputFormat,
+		&rerunOf,
+		&followUpOf,
+		&analysisJSON,
+		&review.Status,
+		&review.Async,
+		&jobInputJSON,
+		&errorMessage,
+		&review.Attempts,
+		&startedAt,
+		&completedAt,
 		&feedbackScore,
 		&feedbackComment,
 		&review.CreatedAt,
@@ -134,7 +234,7 @@ func (r *ReviewRepository) FindByID(ctx context.Context, id string) (*model.Revi
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("review not found: %s", id)
+		return nil, fmt.Errorf("%w: %s", model.ErrReviewNotFound, id)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to find review: %w", err)
@@ -157,6 +257,36 @@ func (r *ReviewRepository) FindByID(ctx context.Context, id string) (*model.Revi
 	if feedbackComment.Valid {
 		review.FeedbackComment = feedbackComment.String
 	}
+	if promptTemplateID.Valid {
+		review.PromptTemplateID = &promptTemplateID.String
+	}
+	review.CacheKey = cacheKey.String
+	if cachedFromReviewID.Valid {
+		review.CachedFromReviewID = &cachedFromReviewID.String
+	}
+	if ensembleID.Valid {
+		review.EnsembleID = &ensembleID.String
+	}
+	if sessionID.Valid {
+		review.SessionID = &sessionID.String
+	}
+	review.FilePath = filePath.String
+	if rerunOf.Valid {
+		review.RerunOfReviewID = &rerunOf.String
+	}
+	if followUpOf.Valid {
+		review.FollowUpOfReviewID = &followUpOf.String
+	}
+	if review.PromptInjectionSignals, err = unmarshalPromptInjectionSignals(signalsJSON); err != nil {
+		return nil, err
+	}
+	if review.JobOptions, err = unmarshalJobOptions(jobInputJSON); err != nil {
+		return nil, err
+	}
+	if review.StaticAnalysis, err = unmarshalStaticAnalysis(analysisJSON); err != nil {
+		return nil, err
+	}
+	setJobState(review, errorMessage, startedAt, completedAt)
 	if deletedAt.Valid {
 		review.DeletedAt = &deletedAt.Time
 	}
@@ -204,8 +334,14 @@ func (r *ReviewRepository) FindByID(ctx context.Context, id string) (*model.Revi
 func (r *ReviewRepository) FindByUserID(ctx context.Context, userID string, limit int) ([]*model.Review, error) {
 	query := `
 		SELECT 
-			id, user_id, code, language, context,
-			review_result, llm_provider, llm_model, tokens_used,
+			id, user_id, code, language, locale, context,
+			review_result, result_source, llm_provider, llm_model, tokens_used,
+			prompt_template_id, prompt_template_version,
+			input_tokens, output_tokens, cache_creation_tokens, cache_read_tokens, embedding_tokens,
+			cost, cost_currency,
+			cache_key, cache_hit, cached_from_review_id, ensemble_id, prompt_injection_signals,
+			session_id, file_path, input_format, rerun_of_review_id, follow_up_of_review_id,
+			status, is_async, error_message, attempts, started_at, completed_at,
 			feedback_score, feedback_comment, created_at, updated_at
 		FROM reviews
 		WHERE user_id = $1 AND deleted_at IS NULL
@@ -222,20 +358,48 @@ func (r *ReviewRepository) FindByUserID(ctx context.Context, userID string, limi
 	var reviews []*model.Review
 	for rows.Next() {
 		review := &model.Review{}
-		var context, llmProvider, llmModel, feedbackComment sql.NullString
-		var reviewResultJSON []byte
+		var context, llmProvider, llmModel, feedbackComment, promptTemplateID, cacheKey, cachedFromReviewID, ensembleID, sessionID, filePath, errorMessage, rerunOf, followUpOf sql.NullString
+		var reviewResultJSON, signalsJSON []byte
 		var feedbackScore sql.NullInt32
+		var startedAt, completedAt sql.NullTime
 
 		err := rows.Scan(
 			&review.ID,
 			&review.UserID,
 			&review.Code,
 			&review.Language,
+			&review.Locale,
 			&context,
 			&reviewResultJSON,
+			&review.ResultSource,
 			&llmProvider,
 			&llmModel,
 			&review.TokensUsed,
+			&promptTemplateID,
+			&review.PromptTemplateVersion,
+			&review.Usage.InputTokens,
+			&review.Usage.OutputTokens,
+			&review.Usage.CacheCreationTokens,
+			&review.Usage.CacheReadTokens,
+			&review.Usage.EmbeddingTokens,
+			&review.Cost,
+			&review.CostCurrency,
+			&cacheKey,
+			&review.CacheHit,
+			&cachedFromReviewID,
+			&ensembleID,
+			&signalsJSON,
+			&sessionID,
+			&filePath,
+			&review.InputFormat,
+			&rerunOf,
+			&followUpOf,
+			&review.Status,
+			&review.Async,
+			&errorMessage,
+			&review.Attempts,
+			&startedAt,
+			&completedAt,
 			&feedbackScore,
 			&feedbackComment,
 			&review.CreatedAt,
@@ -262,6 +426,28 @@ func (r *ReviewRepository) FindByUserID(ctx context.Context, userID string, limi
 		if feedbackComment.Valid {
 			review.FeedbackComment = feedbackComment.String
 		}
+		if promptTemplateID.Valid {
+			review.PromptTemplateID = &promptTemplateID.String
+		}
+		review.CacheKey = cacheKey.String
+		if cachedFromReviewID.Valid {
+			review.CachedFromReviewID = &cachedFromReviewID.String
+		}
+		if ensembleID.Valid {
+			review.EnsembleID = &ensembleID.String
+		}
+		if sessionID.Valid {
+			review.SessionID = &sessionID.String
+		}
+		review.FilePath = filePath.String
+		if rerunOf.Valid {
+			review.RerunOfReviewID = &rerunOf.String
+		}
+		if followUpOf.Valid {
+			review.FollowUpOfReviewID = &followUpOf.String
+		}
+		review.PromptInjectionSignals, _ = unmarshalPromptInjectionSignals(signalsJSON)
+		setJobState(review, errorMessage, startedAt, completedAt)
 
 		// ★ JSONBから構造化データを復元
 		if len(reviewResultJSON) > 0 {
@@ -306,37 +492,8 @@ func (r *ReviewRepository) FindByUserID(ctx context.Context, userID string, limi
 // ListWithFilters - フィルター、ソート、ページネーション付きでレビュー一覧を取得
 func (r *ReviewRepository) ListWithFilters(ctx context.Context, userID string, filters map[string]interface{}, sortBy, sortOrder string, limit, offset int) ([]*model.Review, error) {
 	// WHERE句を動的に構築
-	where := "user_id = $1 AND deleted_at IS NULL"
-	params := []interface{}{userID}
-	paramIndex := 2
-
-	// 言語フィルター
-	if language, ok := filters["language"].(string); ok && language != "" {
-		where += fmt.Sprintf(" AND language = $%d", paramIndex)
-		params = append(params, language)
-		paramIndex++
-	}
-
-	// ステータスフィルター
-	if status, ok := filters["status"].(string); ok && status != "" {
-		where += fmt.Sprintf(" AND status = $%d", paramIndex)
-		params = append(params, status)
-		paramIndex++
-	}
-
-	// 期間フィルター（開始日）
-	if dateFrom, ok := filters["date_from"].(time.Time); ok && !dateFrom.IsZero() {
-		where += fmt.Sprintf(" AND created_at >= $%d", paramIndex)
-		params = append(params, dateFrom)
-		paramIndex++
-	}
-
-	// 期間フィルター（終了日）
-	if dateTo, ok := filters["date_to"].(time.Time); ok && !dateTo.IsZero() {
-		where += fmt.Sprintf(" AND created_at <= $%d", paramIndex)
-		params = append(params, dateTo)
-		paramIndex++
-	}
+	where, params := reviewFilterWhere(userID, filters)
+	paramIndex := len(params) + 1
 
 	// ORDER BY句
 	orderBy := fmt.Sprintf("%s %s", sortBy, strings.ToUpper(sortOrder))
@@ -344,8 +501,14 @@ func (r *ReviewRepository) ListWithFilters(ctx context.Context, userID string, f
 	// クエリ構築
 	query := fmt.Sprintf(`
 		SELECT 
-			id, user_id, code, language, context,
-			review_result, llm_provider, llm_model, tokens_used,
+			id, user_id, code, language, locale, context,
+			review_result, result_source, llm_provider, llm_model, tokens_used,
+			prompt_template_id, prompt_template_version,
+			input_tokens, output_tokens, cache_creation_tokens, cache_read_tokens, embedding_tokens,
+			cost, cost_currency,
+			cache_key, cache_hit, cached_from_review_id, ensemble_id, prompt_injection_signals,
+			session_id, file_path, input_format, rerun_of_review_id, follow_up_of_review_id,
+			status, is_async, error_message, attempts, started_at, completed_at,
 			feedback_score, feedback_comment, created_at, updated_at
 		FROM reviews
 		WHERE %s
@@ -364,20 +527,48 @@ func (r *ReviewRepository) ListWithFilters(ctx context.Context, userID string, f
 	var reviews []*model.Review
 	for rows.Next() {
 		review := &model.Review{}
-		var context, llmProvider, llmModel, feedbackComment sql.NullString
-		var reviewResultJSON []byte
+		var context, llmProvider, llmModel, feedbackComment, promptTemplateID, cacheKey, cachedFromReviewID, ensembleID, sessionID, filePath, errorMessage, rerunOf, followUpOf sql.NullString
+		var reviewResultJSON, signalsJSON []byte
 		var feedbackScore sql.NullInt32
+		var startedAt, completedAt sql.NullTime
 
 		err := rows.Scan(
 			&review.ID,
 			&review.UserID,
 			&review.Code,
 			&review.Language,
+			&review.Locale,
 			&context,
 			&reviewResultJSON,
+			&review.ResultSource,
 			&llmProvider,
 			&llmModel,
 			&review.TokensUsed,
+			&promptTemplateID,
+			&review.PromptTemplateVersion,
+			&review.Usage.InputTokens,
+			&review.Usage.OutputTokens,
+			&review.Usage.CacheCreationTokens,
+			&review.Usage.CacheReadTokens,
+			&review.Usage.EmbeddingTokens,
+			&review.Cost,
+			&review.CostCurrency,
+			&cacheKey,
+			&review.CacheHit,
+			&cachedFromReviewID,
+			&ensembleID,
+			&signalsJSON,
+			&sessionID,
+			&filePath,
+			&review.InputFormat,
+			&rerunOf,
+			&followUpOf,
+			&review.Status,
+			&review.Async,
+			&errorMessage,
+			&review.Attempts,
+			&startedAt,
+			&completedAt,
 			&feedbackScore,
 			&feedbackComment,
 			&review.CreatedAt,
@@ -404,6 +595,28 @@ func (r *ReviewRepository) ListWithFilters(ctx context.Context, userID string, f
 		if feedbackComment.Valid {
 			review.FeedbackComment = feedbackComment.String
 		}
+		if promptTemplateID.Valid {
+			review.PromptTemplateID = &promptTemplateID.String
+		}
+		review.CacheKey = cacheKey.String
+		if cachedFromReviewID.Valid {
+			review.CachedFromReviewID = &cachedFromReviewID.String
+		}
+		if ensembleID.Valid {
+			review.EnsembleID = &ensembleID.String
+		}
+		if sessionID.Valid {
+			review.SessionID = &sessionID.String
+		}
+		review.FilePath = filePath.String
+		if rerunOf.Valid {
+			review.RerunOfReviewID = &rerunOf.String
+		}
+		if followUpOf.Valid {
+			review.FollowUpOfReviewID = &followUpOf.String
+		}
+		review.PromptInjectionSignals, _ = unmarshalPromptInjectionSignals(signalsJSON)
+		setJobState(review, errorMessage, startedAt, completedAt)
 
 		// ★ JSONBから構造化データを復元
 		if len(reviewResultJSON) > 0 {
@@ -440,7 +653,7 @@ func (r *ReviewRepository) ListWithFilters(ctx context.Context, userID string, f
 
 		// ★ markdownから構造化データをパース
 		if review.ReviewResult != "" {
-			structured := parser.ParseReviewMarkdown(review.ReviewResult)
+			structured := parser.ParseReviewMarkdown(review.ReviewResult, review.Locale)
 			review.StructuredResult = structured
 		}
 
@@ -453,6 +666,27 @@ func (r *ReviewRepository) ListWithFilters(ctx context.Context, userID string, f
 // CountWithFilters - フィルター条件に合致するレビューの総数を取得
 func (r *ReviewRepository) CountWithFilters(ctx context.Context, userID string, filters map[string]interface{}) (int, error) {
 	// WHERE句を動的に構築
+	where, params := reviewFilterWhere(userID, filters)
+
+	// クエリ構築
+	query := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM reviews
+		WHERE %s
+	`, where)
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, params...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count reviews: %w", err)
+	}
+
+	return count, nil
+}
+
+// reviewFilterWhere - ユーザーの削除していないレビューをフィルター条件で絞り込むWHERE句とパラメータを構築
+// （一覧・件数・一括削除で共通）
+func reviewFilterWhere(userID string, filters map[string]interface{}) (string, []interface{}) {
 	where := "user_id = $1 AND deleted_at IS NULL"
 	params := []interface{}{userID}
 	paramIndex := 2
@@ -471,6 +705,27 @@ func (r *ReviewRepository) CountWithFilters(ctx context.Context, userID string,
 		paramIndex++
 	}
 
+	// セッションフィルター（複数ファイルのレビューセッションのレビューのみ）
+	if sessionID, ok := filters["session_id"].(string); ok && sessionID != "" {
+		where += fmt.Sprintf(" AND session_id = $%d", paramIndex)
+		params = append(params, sessionID)
+		paramIndex++
+	}
+
+	// 再レビューの元のレビューでのフィルター
+	if rerunOf, ok := filters["rerun_of"].(string); ok && rerunOf != "" {
+		where += fmt.Sprintf(" AND rerun_of_review_id = $%d", paramIndex)
+		params = append(params, rerunOf)
+		paramIndex++
+	}
+
+	// 改善点の適用元のレビューでのフィルター
+	if followUpOf, ok := filters["follow_up_of"].(string); ok && followUpOf != "" {
+		where += fmt.Sprintf(" AND follow_up_of_review_id = $%d", paramIndex)
+		params = append(params, followUpOf)
+		paramIndex++
+	}
+
 	// 期間フィルター（開始日）
 	if dateFrom, ok := filters["date_from"].(time.Time); ok && !dateFrom.IsZero() {
 		where += fmt.Sprintf(" AND created_at >= $%d", paramIndex)
@@ -485,20 +740,7 @@ func (r *ReviewRepository) CountWithFilters(ctx context.Context, userID string,
 		paramIndex++
 	}
 
-	// クエリ構築
-	query := fmt.Sprintf(`
-		SELECT COUNT(*)
-		FROM reviews
-		WHERE %s
-	`, where)
-
-	var count int
-	err := r.db.QueryRowContext(ctx, query, params...).Scan(&count)
-	if err != nil {
-		return 0, fmt.Errorf("failed to count reviews: %w", err)
-	}
-
-	return count, nil
+	return where, params
 }
 
 // Update - レビューを更新
@@ -518,19 +760,21 @@ func (r *ReviewRepository) Update(ctx context.Context, review *model.Review) err
 		UPDATE reviews
 		SET 
 			review_result = $1,
-			llm_provider = $2,
-			llm_model = $3,
-			tokens_used = $4,
-			feedback_score = $5,
-			feedback_comment = $6,
-			updated_at = $7
-		WHERE id = $8
+			result_source = $2,
+			llm_provider = $3,
+			llm_model = $4,
+			tokens_used = $5,
+			feedback_score = $6,
+			feedback_comment = $7,
+			updated_at = $8
+		WHERE id = $9
 	`
 
 	_, err = r.db.ExecContext(
 		ctx,
 		query,
 		reviewResultJSON, // ★ JSONB
+		resultSourceOrDefault(review.ResultSource),
 		review.LLMProvider,
 		review.LLMModel,
 		review.TokensUsed,
@@ -558,6 +802,261 @@ func (r *ReviewRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// SoftDelete - レビューをゴミ箱に移動（論理削除。deleted_at を設定する）
+func (r *ReviewRepository) SoftDelete(ctx context.Context, id string) (bool, error) {
+	query := softDeleteQuery("id = $1 AND deleted_at IS NULL", "$2")
+
+	deleted := &model.Review{}
+	var cancelled bool
+	err := r.db.QueryRowContext(ctx, query, id, model.ErrReviewJobCancelled.Error()).Scan(&deleted.ID, &deleted.UserID, &deleted.CreatedAt, &cancelled)
+	if err == sql.ErrNoRows {
+		return false, fmt.Errorf("%w: %s", model.ErrReviewNotFound, id)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to soft delete review: %w", err)
+	}
+
+	return cancelled, nil
+}
+
+// SoftDeleteWithFilters - フィルター条件に合致するユーザーのレビューをゴミ箱に移動
+func (r *ReviewRepository) SoftDeleteWithFilters(ctx context.Context, userID string, filters map[string]interface{}) (int, []*model.Review, error) {
+	where, params := reviewFilterWhere(userID, filters)
+	params = append(params, model.ErrReviewJobCancelled.Error())
+	query := softDeleteQuery(where, fmt.Sprintf("$%d", len(params)))
+
+	rows, err := r.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to soft delete reviews: %w", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	var cancelledJobs []*model.Review
+	for rows.Next() {
+		job := &model.Review{}
+		var cancelled bool
+		if err := rows.Scan(&job.ID, &job.UserID, &job.CreatedAt, &cancelled); err != nil {
+			return 0, nil, fmt.Errorf("failed to scan deleted review: %w", err)
+		}
+		count++
+		if cancelled {
+			cancelledJobs = append(cancelledJobs, job)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, nil, fmt.Errorf("failed to soft delete reviews: %w", err)
+	}
+
+	return count, cancelledJobs, nil
+}
+
+// softDeleteQuery - where に合致するレビューをゴミ箱に移動し、id / user_id / created_at と
+// 実行待ち・実行中の非同期のレビューを中止したか（更新前の status）を返すクエリ
+func softDeleteQuery(where, message string) string {
+	return `
+		WITH target AS (
+			SELECT id, status IN ('pending', 'running') AS cancelled
+			FROM reviews
+			WHERE ` + where + `
+			FOR UPDATE
+		)
+		UPDATE reviews
+		SET deleted_at = NOW(), updated_at = NOW(), ` + cancelJobOnDelete(message) + `
+		FROM target
+		WHERE reviews.id = target.id
+		RETURNING reviews.id, reviews.user_id, reviews.created_at, target.cancelled
+	`
+}
+
+// cancelJobOnDelete - 削除する実行待ち・実行中の非同期のレビューを失敗にする SET 句（message は中止した理由のパラメータ）
+// ワーカーは失敗にしたレビューを取得せず、実行中のワーカーの結果は CompleteJob で保存しない
+func cancelJobOnDelete(message string) string {
+	return `
+		error_message = CASE WHEN status IN ('pending', 'running') THEN ` + message + ` ELSE error_message END,
+		completed_at = CASE WHEN status IN ('pending', 'running') THEN NOW() ELSE completed_at END,
+		status = CASE WHEN status IN ('pending', 'running') THEN 'failed' ELSE status END`
+}
+
+// deletedReviewColumns - ゴミ箱の一覧・復元の権限チェックで使う列（参照ナレッジ・利用量は取得しない）
+const deletedReviewColumns = `
+	id, user_id, code, language, locale, context, file_path, session_id, status,
+	review_result, llm_provider, llm_model, created_at, updated_at, deleted_at
+`
+
+// scanDeletedReview - deletedReviewColumns の行をレビューに変換
+func scanDeletedReview(scan func(dest ...interface{}) error) (*model.Review, error) {
+	review := &model.Review{}
+	var context, filePath, sessionID, llmProvider, llmModel sql.NullString
+	var reviewResultJSON []byte
+	var deletedAt sql.NullTime
+
+	err := scan(
+		&review.ID,
+		&review.UserID,
+		&review.Code,
+		&review.Language,
+		&review.Locale,
+		&context,
+		&filePath,
+		&sessionID,
+		&review.Status,
+		&reviewResultJSON,
+		&llmProvider,
+		&llmModel,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+		&deletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Context = context.String
+	review.FilePath = filePath.String
+	if sessionID.Valid {
+		review.SessionID = &sessionID.String
+	}
+	review.LLMProvider = llmProvider.String
+	review.LLMModel = llmModel.String
+	if deletedAt.Valid {
+		review.DeletedAt = &deletedAt.Time
+	}
+	if len(reviewResultJSON) > 0 {
+		var structured model.StructuredReviewResult
+		if err := json.Unmarshal(reviewResultJSON, &structured); err == nil {
+			review.StructuredResult = &structured
+		}
+	}
+
+	return review, nil
+}
+
+// FindDeletedByID - ゴミ箱のレビューをIDで取得
+func (r *ReviewRepository) FindDeletedByID(ctx context.Context, id string) (*model.Review, error) {
+	query := `SELECT ` + deletedReviewColumns + `
+		FROM reviews
+		WHERE id = $1 AND deleted_at IS NOT NULL AND purged_at IS NULL
+	`
+
+	review, err := scanDeletedReview(r.db.QueryRowContext(ctx, query, id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", model.ErrReviewNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deleted review: %w", err)
+	}
+
+	return review, nil
+}
+
+// ListDeleted - ユーザーのゴミ箱のレビューを削除日時の新しい順に取得
+func (r *ReviewRepository) ListDeleted(ctx context.Context, userID string, limit, offset int) ([]*model.Review, error) {
+	query := `SELECT ` + deletedReviewColumns + `
+		FROM reviews
+		WHERE user_id = $1 AND deleted_at IS NOT NULL AND purged_at IS NULL
+		ORDER BY deleted_at DESC, id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []*model.Review{}
+	for rows.Next() {
+		review, err := scanDeletedReview(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deleted review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate deleted reviews: %w", err)
+	}
+
+	return reviews, nil
+}
+
+// CountDeleted - ユーザーのゴミ箱のレビューの総数を取得
+func (r *ReviewRepository) CountDeleted(ctx context.Context, userID string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM reviews
+		WHERE user_id = $1 AND deleted_at IS NOT NULL AND purged_at IS NULL
+	`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count deleted reviews: %w", err)
+	}
+
+	return count, nil
+}
+
+// Restore - ゴミ箱のレビューを元に戻す
+func (r *ReviewRepository) Restore(ctx context.Context, id string) error {
+	query := `
+		UPDATE reviews
+		SET deleted_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL AND purged_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore review: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %s", model.ErrReviewNotFound, id)
+	}
+
+	return nil
+}
+
+// PurgeDeleted - deletedBefore より前にゴミ箱に移動したレビューの内容を消す
+// 利用上限・使用量の集計は reviews の行から数えるため、行は削除せずコード・レビュー結果などを消して使用量の列を残す
+// 参照ナレッジの記録・会話は削除する（キャッシュ元・再レビュー元などの参照は内容を消したレビューを指したまま）
+func (r *ReviewRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	query := `
+		WITH purged AS (
+			UPDATE reviews
+			SET code = '',
+				context = NULL,
+				file_path = NULL,
+				review_result = '{"summary":"","good_points":[],"improvements":[]}',
+				feedback_comment = NULL,
+				cache_key = NULL,
+				prompt_injection_signals = '[]',
+				static_analysis = NULL,
+				job_input = NULL,
+				error_message = NULL,
+				purged_at = NOW(),
+				updated_at = NOW()
+			WHERE deleted_at IS NOT NULL AND deleted_at < $1 AND purged_at IS NULL
+			RETURNING id
+		), purged_knowledge AS (
+			DELETE FROM review_knowledge WHERE review_id IN (SELECT id FROM purged)
+		), purged_messages AS (
+			DELETE FROM review_messages WHERE review_id IN (SELECT id FROM purged)
+		)
+		SELECT COUNT(*) FROM purged
+	`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, deletedBefore).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to purge deleted reviews: %w", err)
+	}
+
+	return count, nil
+}
+
 // FindRecentByUserID - 最近のレビューを取得
 func (r *ReviewRepository) FindRecentByUserID(ctx context.Context, userID string, limit int) ([]*model.Review, error) {
 	return r.FindByUserID(ctx, userID, limit)
@@ -565,7 +1064,7 @@ func (r *ReviewRepository) FindRecentByUserID(ctx context.Context, userID string
 
 // UpdateFeedback - フィードバックを更新
 func (r *ReviewRepository) UpdateFeedback(ctx context.Context, reviewID string, score int, comment string) error {
-query := `
+	query := `
 UPDATE reviews
 SET feedback_score = $1,
 feedback_comment = $2,
@@ -573,21 +1072,21 @@ updated_at = NOW()
 WHERE id = $3 AND deleted_at IS NULL
 `
 
-result, err := r.db.ExecContext(ctx, query, score, comment, reviewID)
-if err != nil {
-return fmt.Errorf("failed to update feedback: %w", err)
-}
+	result, err := r.db.ExecContext(ctx, query, score, comment, reviewID)
+	if err != nil {
+		return fmt.Errorf("failed to update feedback: %w", err)
+	}
 
-rowsAffected, err := result.RowsAffected()
-if err != nil {
-return fmt.Errorf("failed to get rows affected: %w", err)
-}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
 
-if rowsAffected == 0 {
-return fmt.Errorf("レビューが見つかりません: %s", reviewID)
-}
+	if rowsAffected == 0 {
+		return fmt.Errorf("レビューが見つかりません: %s", reviewID)
+	}
 
-return nil
+	return nil
 }
 
 // CountByUserID - ユーザーIDでレビュー総数を取得
@@ -645,3 +1144,557 @@ func (r *ReviewRepository) GetAverageFeedbackScore(ctx context.Context, userID s
 
 	return average, nil
 }
+
+// resultSourceOrDefault - 生成経路が未設定の場合はマークダウン扱いにする
+func resultSourceOrDefault(source string) string {
+	if source == "" {
+		return model.ResultSourceMarkdown
+	}
+	return source
+}
+
+// AggregateUsage - 期間内のトークン使用量とコストを集計
+func (r *ReviewRepository) AggregateUsage(ctx context.Context, filter model.UsageFilter) ([]*model.UsageSummary, error) {
+	// 集計単位ごとの SELECT / GROUP BY 対象（通貨は常に分けて集計する）
+	var dims []string
+	groupDay, groupModel, groupUser := false, false, false
+	for _, group := range filter.GroupBy {
+		switch group {
+		case model.UsageGroupDay:
+			groupDay = true
+			dims = append(dims, "(r.created_at AT TIME ZONE 'UTC')::date")
+		case model.UsageGroupModel:
+			groupModel = true
+			dims = append(dims, "COALESCE(r.llm_provider, '')", "COALESCE(r.llm_model, '')")
+		case model.UsageGroupUser:
+			groupUser = true
+			dims = append(dims, "r.user_id", "COALESCE(u.email, '')", "COALESCE(u.name, '')")
+		default:
+			return nil, fmt.Errorf("%w: %s", model.ErrUsageGroupInvalid, group)
+		}
+	}
+	dims = append(dims, "r.cost_currency")
+
+	query := `
+		SELECT ` + strings.Join(dims, ", ") + `,
+			COUNT(*),
+			COALESCE(SUM(r.input_tokens), 0),
+			COALESCE(SUM(r.output_tokens), 0),
+			COALESCE(SUM(r.cache_creation_tokens), 0),
+			COALESCE(SUM(r.cache_read_tokens), 0),
+			COALESCE(SUM(r.embedding_tokens), 0),
+			COALESCE(SUM(r.cost), 0)
+		FROM reviews r
+		LEFT JOIN users u ON u.id = r.user_id
+		WHERE r.created_at >= $1 AND r.created_at < $2
+		  AND r.status = 'completed'
+	`
+	params := []interface{}{filter.From, filter.To}
+	if filter.UserID != "" {
+		query += " AND r.user_id = $3"
+		params = append(params, filter.UserID)
+	}
+	query += " GROUP BY " + strings.Join(dims, ", ") + " ORDER BY " + strings.Join(dims, ", ")
+
+	rows, err := r.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate usage: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := []*model.UsageSummary{}
+	for rows.Next() {
+		summary := &model.UsageSummary{}
+		var day time.Time
+
+		dest := []interface{}{}
+		if groupDay {
+			dest = append(dest, &day)
+		}
+		if groupModel {
+			dest = append(dest, &summary.LLMProvider, &summary.LLMModel)
+		}
+		if groupUser {
+			dest = append(dest, &summary.UserID, &summary.UserEmail, &summary.UserName)
+		}
+		dest = append(dest,
+			&summary.Currency,
+			&summary.ReviewCount,
+			&summary.Usage.InputTokens,
+			&summary.Usage.OutputTokens,
+			&summary.Usage.CacheCreationTokens,
+			&summary.Usage.CacheReadTokens,
+			&summary.Usage.EmbeddingTokens,
+			&summary.Cost,
+		)
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan usage: %w", err)
+		}
+		if groupDay {
+			summary.Day = day.Format(time.DateOnly)
+		}
+		summaries = append(summaries, summary)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate usage: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// FindCachedReview - キャッシュキーが一致する、since 以降に生成した最新のレビューを取得
+// キャッシュから作成したレビューは対象外（元のレビューを返す）
+func (r *ReviewRepository) FindCachedReview(ctx context.Context, userID, cacheKey string, since time.Time) (*model.Review, error) {
+	query := `
+		SELECT id
+		FROM reviews
+		WHERE user_id = $1
+		  AND cache_key = $2
+		  AND cache_hit = false
+		  AND status = 'completed'
+		  AND created_at >= $3
+		  AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var id string
+	err := r.db.QueryRowContext(ctx, query, userID, cacheKey, since).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, model.ErrReviewCacheMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find cached review: %w", err)
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// FindByEnsembleID - 同じアンサンブルで作成したレビューを作成順に取得（なければ model.ErrEnsembleNotFound）
+func (r *ReviewRepository) FindByEnsembleID(ctx context.Context, userID, ensembleID string) ([]*model.Review, error) {
+	query := `
+		SELECT id
+		FROM reviews
+		WHERE user_id = $1
+		  AND ensemble_id = $2
+		  AND deleted_at IS NULL
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, ensembleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ensemble reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan review id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate ensemble reviews: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, model.ErrEnsembleNotFound
+	}
+
+	reviews := make([]*model.Review, 0, len(ids))
+	for _, id := range ids {
+		review, err := r.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, nil
+}
+
+// FindBySessionID - 同じセッションで作成したレビューを作成順に取得（なければ空）
+func (r *ReviewRepository) FindBySessionID(ctx context.Context, userID, sessionID string) ([]*model.Review, error) {
+	query := `
+		SELECT id
+		FROM reviews
+		WHERE user_id = $1
+		  AND session_id = $2
+		  AND deleted_at IS NULL
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find session reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan review id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate session reviews: %w", err)
+	}
+
+	reviews := make([]*model.Review, 0, len(ids))
+	for _, id := range ids {
+		review, err := r.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, nil
+}
+
+// ClaimNextJob - 最も古い実行待ちの非同期のレビューを実行中にして取得
+// SKIP LOCKED で他のワーカーが取得中の行を飛ばすため、同じジョブを2か所で実行しない
+func (r *ReviewRepository) ClaimNextJob(ctx context.Context) (*model.Review, error) {
+	query := `
+		UPDATE reviews
+		SET status = 'running',
+			attempts = attempts + 1,
+			started_at = NOW(),
+			error_message = NULL,
+			completed_at = NULL,
+			updated_at = NOW()
+		WHERE id = (
+			SELECT id
+			FROM reviews
+			WHERE status = 'pending' AND deleted_at IS NULL
+			ORDER BY created_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id
+	`
+
+	var id string
+	err := r.db.QueryRowContext(ctx, query).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, model.ErrNoPendingReviewJob
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim review job: %w", err)
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// UpdateJobStatus - 非同期のレビューの状態を更新
+// 状態が from で実行回数が同じ行のみ更新する（止まったとみなして他のワーカーが取得し直したジョブを上書きしない）
+func (r *ReviewRepository) UpdateJobStatus(ctx context.Context, review *model.Review, from string) error {
+	query := `
+		UPDATE reviews
+		SET status = $1,
+			error_message = $2,
+			attempts = $3,
+			started_at = $4,
+			completed_at = $5,
+			updated_at = $6
+		WHERE id = $7 AND status = $8 AND attempts = $3 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		statusOrDefault(review.Status),
+		nullIfEmpty(review.ErrorMessage),
+		review.Attempts,
+		review.StartedAt,
+		review.CompletedAt,
+		review.UpdatedAt,
+		review.ID,
+		from,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update review job status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %s", model.ErrReviewJobLost, review.ID)
+	}
+
+	return nil
+}
+
+// CompleteJob - 非同期のレビューにレビュー結果と参照したナレッジを保存して完了にする
+// 実行中で実行回数が同じ行のみ更新する（他のワーカーが取得し直したジョブ・削除したジョブには保存しない）
+func (r *ReviewRepository) CompleteJob(ctx context.Context, review *model.Review) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var reviewResultJSON []byte
+	if review.StructuredResult != nil {
+		reviewResultJSON, err = json.Marshal(review.StructuredResult)
+		if err != nil {
+			return fmt.Errorf("failed to marshal review result: %w", err)
+		}
+	} else {
+		reviewResultJSON = []byte(`{"summary":"","good_points":[],"improvements":[]}`)
+	}
+
+	signalsJSON, err := marshalPromptInjectionSignals(review.PromptInjectionSignals)
+	if err != nil {
+		return err
+	}
+	analysisJSON, err := marshalStaticAnalysis(review.StaticAnalysis)
+	if err != nil {
+		return err
+	}
+
+	// 1. レビュー結果を保存して完了にする
+	query := `
+		UPDATE reviews
+		SET language = $1,
+			locale = $2,
+			file_path = $3,
+			review_result = $4,
+			result_source = $5,
+			llm_provider = $6,
+			llm_model = $7,
+			tokens_used = $8,
+			prompt_template_id = $9,
+			prompt_template_version = $10,
+			input_tokens = $11,
+			output_tokens = $12,
+			cache_creation_tokens = $13,
+			cache_read_tokens = $14,
+			embedding_tokens = $15,
+			cost = $16,
+			cost_currency = $17,
+			cache_key = $18,
+			cache_hit = $19,
+			cached_from_review_id = $20,
+			prompt_injection_signals = $21,
+			static_analysis = $22,
+			status = 'completed',
+			error_message = NULL,
+			completed_at = $23,
+			updated_at = $24
+		WHERE id = $25 AND status = 'running' AND attempts = $26 AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(
+		ctx,
+		query,
+		review.Language,
+		localeOrDefault(review.Locale),
+		nullIfEmpty(review.FilePath),
+		reviewResultJSON,
+		resultSourceOrDefault(review.ResultSource),
+		review.LLMProvider,
+		review.LLMModel,
+		review.TokensUsed,
+		review.PromptTemplateID,
+		review.PromptTemplateVersion,
+		review.Usage.InputTokens,
+		review.Usage.OutputTokens,
+		review.Usage.CacheCreationTokens,
+		review.Usage.CacheReadTokens,
+		review.Usage.EmbeddingTokens,
+		review.Cost,
+		costCurrencyOrDefault(review.CostCurrency),
+		nullIfEmpty(review.CacheKey),
+		review.CacheHit,
+		review.CachedFromReviewID,
+		signalsJSON,
+		analysisJSON,
+		review.CompletedAt,
+		review.UpdatedAt,
+		review.ID,
+		review.Attempts,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete review job: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %s", model.ErrReviewJobLost, review.ID)
+	}
+
+	// 2. 参照したナレッジを保存（再実行の場合は前回の分を置き換える）
+	if _, err := tx.ExecContext(ctx, `DELETE FROM review_knowledge WHERE review_id = $1`, review.ID); err != nil {
+		return fmt.Errorf("failed to delete review_knowledge: %w", err)
+	}
+	for _, knowledgeID := range review.ReferencedKnowledge {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO review_knowledge (review_id, knowledge_id, created_at)
+			VALUES ($1, $2, NOW())
+		`, review.ID, knowledgeID); err != nil {
+			return fmt.Errorf("failed to insert review_knowledge: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RequeueStaleJobs - startedBefore より前に開始して終わっていない実行中のレビューを実行待ちに戻す
+// サーバーの再起動などで実行が中断されたジョブを再実行するために使う
+func (r *ReviewRepository) RequeueStaleJobs(ctx context.Context, startedBefore time.Time) (int, error) {
+	query := `
+		UPDATE reviews
+		SET status = 'pending',
+			updated_at = NOW()
+		WHERE status = 'running'
+		  AND started_at < $1
+		  AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, startedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue stale review jobs: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// marshalPromptInjectionSignals - プロンプトインジェクションの疑いを JSONB に変換（疑いがなければ空配列）
+func marshalPromptInjectionSignals(signals []model.PromptInjectionSignal) ([]byte, error) {
+	if len(signals) == 0 {
+		return []byte("[]"), nil
+	}
+	data, err := json.Marshal(signals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal prompt injection signals: %w", err)
+	}
+	return data, nil
+}
+
+// unmarshalPromptInjectionSignals - JSONB からプロンプトインジェクションの疑いを復元（空配列は nil）
+func unmarshalPromptInjectionSignals(data []byte) ([]model.PromptInjectionSignal, error) {
+	var signals []model.PromptInjectionSignal
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(data, &signals); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal prompt injection signals: %w", err)
+	}
+	if len(signals) == 0 {
+		return nil, nil
+	}
+	return signals, nil
+}
+
+// marshalStaticAnalysis - 静的解析の結果を JSONB に変換（解析していなければ NULL）
+func marshalStaticAnalysis(analysis *model.StaticAnalysis) ([]byte, error) {
+	if analysis == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal static analysis: %w", err)
+	}
+	return data, nil
+}
+
+// unmarshalStaticAnalysis - JSONB から静的解析の結果を復元（NULL は nil）
+func unmarshalStaticAnalysis(data []byte) (*model.StaticAnalysis, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var analysis model.StaticAnalysis
+	if err := json.Unmarshal(data, &analysis); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal static analysis: %w", err)
+	}
+	return &analysis, nil
+}
+
+// nullIfEmpty - 空文字列はNULLとして保存
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// localeOrDefault - ロケールが未設定の場合は既定のロケールとして保存
+func localeOrDefault(locale string) string {
+	return model.ResolveLocale(locale)
+}
+
+// costCurrencyOrDefault - 通貨が未設定の場合はUSDとして保存
+func costCurrencyOrDefault(currency string) string {
+	if currency == "" {
+		return "USD"
+	}
+	return currency
+}
+
+// inputFormatOrDefault - 入力の形式が未設定の場合はファイル全体のコードとして保存
+func inputFormatOrDefault(format string) string {
+	if format == "" {
+		return model.ReviewInputCode
+	}
+	return format
+}
+
+// statusOrDefault - 状態が未設定の場合は完了として保存
+func statusOrDefault(status string) string {
+	if status == "" {
+		return model.ReviewStatusCompleted
+	}
+	return status
+}
+
+// marshalJobOptions - 非同期のレビューの入力を JSONB に変換（同期的なレビューは NULL）
+func marshalJobOptions(options *model.ReviewJobOptions) ([]byte, error) {
+	if options == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job input: %w", err)
+	}
+	return data, nil
+}
+
+// unmarshalJobOptions - JSONB から非同期のレビューの入力を復元（NULL は nil）
+func unmarshalJobOptions(data []byte) (*model.ReviewJobOptions, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var options model.ReviewJobOptions
+	if err := json.Unmarshal(data, &options); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job input: %w", err)
+	}
+	return &options, nil
+}
+
+// setJobState - Nullable な非同期のレビューの状態をレビューに設定
+func setJobState(review *model.Review, errorMessage sql.NullString, startedAt, completedAt sql.NullTime) {
+	review.ErrorMessage = errorMessage.String
+	if startedAt.Valid {
+		review.StartedAt = &startedAt.Time
+	}
+	if completedAt.Valid {
+		review.CompletedAt = &completedAt.Time
+	}
+}
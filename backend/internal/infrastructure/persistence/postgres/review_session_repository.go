@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// ReviewSessionRepository - PostgreSQL実装
+type ReviewSessionRepository struct {
+	db *sql.DB
+}
+
+// NewReviewSessionRepository - コンストラクタ
+func NewReviewSessionRepository(db *sql.DB) *ReviewSessionRepository {
+	return &ReviewSessionRepository{db: db}
+}
+
+// reviewSessionColumns - SELECT する列（scanReviewSession と同じ順）
+const reviewSessionColumns = `
+	id, user_id, title, locale, summary, file_count,
+	improvement_count, high_count, medium_count, low_count,
+	created_at, updated_at, deleted_at
+`
+
+// Create - セッションを作成
+func (r *ReviewSessionRepository) Create(ctx context.Context, session *model.ReviewSession) error {
+	query := `
+		INSERT INTO review_sessions (
+			id, user_id, title, locale, summary, file_count,
+			improvement_count, high_count, medium_count, low_count,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		session.ID,
+		session.UserID,
+		session.Title,
+		localeOrDefault(session.Locale),
+		session.Summary,
+		session.FileCount,
+		session.Improvements.Total,
+		session.Improvements.High,
+		session.Improvements.Medium,
+		session.Improvements.Low,
+		session.CreatedAt,
+		session.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create review session: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID - ユーザーのセッションを取得（なければ model.ErrReviewSessionNotFound）
+func (r *ReviewSessionRepository) FindByID(ctx context.Context, userID, sessionID string) (*model.ReviewSession, error) {
+	query := `SELECT ` + reviewSessionColumns + `
+		FROM review_sessions
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`
+
+	session, err := scanReviewSession(r.db.QueryRowContext(ctx, query, sessionID, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", model.ErrReviewSessionNotFound, sessionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find review session: %w", err)
+	}
+
+	return session, nil
+}
+
+// ListByUserID - ユーザーのセッションを新しい順に取得
+func (r *ReviewSessionRepository) ListByUserID(ctx context.Context, userID string, limit, offset int) ([]*model.ReviewSession, error) {
+	query := `SELECT ` + reviewSessionColumns + `
+		FROM review_sessions
+		WHERE user_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*model.ReviewSession{}
+	for rows.Next() {
+		session, err := scanReviewSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate review sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// CountByUserID - ユーザーのセッション総数を取得
+func (r *ReviewSessionRepository) CountByUserID(ctx context.Context, userID string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM review_sessions
+		WHERE user_id = $1 AND deleted_at IS NULL
+	`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count review sessions: %w", err)
+	}
+
+	return count, nil
+}
+
+// scanReviewSession - 1行をセッションに変換（*sql.Row と *sql.Rows の両方で使う）
+func scanReviewSession(row interface{ Scan(dest ...any) error }) (*model.ReviewSession, error) {
+	session := &model.ReviewSession{}
+	var deletedAt sql.NullTime
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.Title,
+		&session.Locale,
+		&session.Summary,
+		&session.FileCount,
+		&session.Improvements.Total,
+		&session.Improvements.High,
+		&session.Improvements.Medium,
+		&session.Improvements.Low,
+		&session.CreatedAt,
+		&session.UpdatedAt,
+		&deletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if deletedAt.Valid {
+		session.DeletedAt = &deletedAt.Time
+	}
+	return session, nil
+}
@@ -68,7 +68,7 @@ func (r *userRepository) FindByID(ctx context.Context, id string) (*model.User,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("user not found")
+			return nil, model.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to find user by id: %w", err)
 	}
@@ -98,7 +98,7 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*model.
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("user not found")
+			return nil, model.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to find user by email: %w", err)
 	}
@@ -128,7 +128,7 @@ func (r *userRepository) FindByAuth0UserID(ctx context.Context, auth0UserID stri
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("user not found")
+			return nil, model.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to find user by auth0_user_id: %w", err)
 	}
@@ -164,7 +164,7 @@ func (r *userRepository) Update(ctx context.Context, user *model.User) error {
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("user not found")
+		return model.ErrUserNotFound
 	}
 
 	return nil
@@ -189,7 +189,7 @@ func (r *userRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("user not found")
+		return model.ErrUserNotFound
 	}
 
 	return nil
@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/s7r8/reviewapp/internal/infrastructure/config"
+)
+
+// defaultTimeout - 接続・1コマンドあたりのタイムアウト（コンテキストの期限が短い場合はそちらを優先する）
+const defaultTimeout = 3 * time.Second
+
+// NewClient - REDIS_URL（redis://[:password@]host:port/db、rediss:// はTLS）から go-redis のクライアントを生成
+// REDIS_URL にパスワード・DB番号がない場合は REDIS_PASSWORD・REDIS_DB を使う
+// 接続はプールで管理し、最初のコマンド実行時に確立する（複数のリクエストから並行して使える）
+func NewClient(cfg *config.RedisConfig) (*goredis.Client, error) {
+	opts, err := goredis.ParseURL(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	if opts.Password == "" {
+		opts.Password = cfg.Password
+	}
+	if opts.DB == 0 {
+		opts.DB = cfg.DB
+	}
+
+	opts.DialTimeout = defaultTimeout
+	opts.ReadTimeout = defaultTimeout
+	opts.WriteTimeout = defaultTimeout
+	opts.ContextTimeoutEnabled = true
+	// 接続できない場合は再試行を待たずに呼び出し元のフォールバック（キャッシュミス・PostgreSQLの集計）に任せる
+	// 再試行は1回のみ（プールの切れた接続の張り直しなど）
+	opts.MaxRetries = 1
+	opts.DialerRetries = 1
+	// RESP2 で接続し、CLIENT SETINFO を送らない（古いRedis・互換サーバーでも使えるように）
+	opts.Protocol = 2
+	opts.DisableIdentity = true
+
+	return goredis.NewClient(opts), nil
+}
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// EmbeddingCache - RedisのEmbeddingキャッシュ（複数のAPIサーバーで共有する）
+// ベクトルは float32 のリトルエンディアンのバイト列で保存する。
+// Redisに接続できない場合はキャッシュミスとして扱い、Embeddingの生成は止めない
+type EmbeddingCache struct {
+	client *goredis.Client
+	ttl    time.Duration
+}
+
+// NewEmbeddingCache - コンストラクタ（ttl: 0以下は期限なし）
+func NewEmbeddingCache(client *goredis.Client, ttl time.Duration) *EmbeddingCache {
+	if ttl < 0 {
+		ttl = 0
+	}
+	return &EmbeddingCache{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// Get - キャッシュからEmbeddingを取得
+func (c *EmbeddingCache) Get(ctx context.Context, key string) ([]float32, bool) {
+	data, err := c.client.Get(ctx, embeddingKey(key)).Bytes()
+	if err != nil {
+		if !errors.Is(err, goredis.Nil) {
+			log.Printf("Warning: failed to get embedding from redis: %v", err)
+		}
+		return nil, false
+	}
+
+	if len(data)%4 != 0 {
+		return nil, false
+	}
+	embedding := make([]float32, len(data)/4)
+	for i := range embedding {
+		embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4 : i*4+4]))
+	}
+	return embedding, true
+}
+
+// Set - Embeddingをキャッシュに保存
+func (c *EmbeddingCache) Set(ctx context.Context, key string, embedding []float32) {
+	data := make([]byte, len(embedding)*4)
+	for i, v := range embedding {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(v))
+	}
+
+	if err := c.client.Set(ctx, embeddingKey(key), data, c.ttl).Err(); err != nil {
+		log.Printf("Warning: failed to set embedding to redis: %v", err)
+	}
+}
+
+// embeddingKey - Redisのキー
+func embeddingKey(key string) string {
+	return "embedding:" + key
+}
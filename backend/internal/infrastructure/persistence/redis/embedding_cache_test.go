@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/s7r8/reviewapp/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddingCache(t *testing.T) {
+	server, url := startFakeRedis(t)
+	client, err := NewClient(&config.RedisConfig{URL: url})
+	require.NoError(t, err)
+	defer client.Close()
+
+	cache := NewEmbeddingCache(client, time.Hour)
+	ctx := context.Background()
+
+	_, ok := cache.Get(ctx, "text-embedding-3-small:abc")
+	assert.False(t, ok)
+
+	embedding := []float32{0.1, -0.25, 3.5, 0}
+	cache.Set(ctx, "text-embedding-3-small:abc", embedding)
+
+	got, ok := cache.Get(ctx, "text-embedding-3-small:abc")
+	require.True(t, ok)
+	assert.Equal(t, embedding, got)
+	stored, err := server.Get("embedding:text-embedding-3-small:abc")
+	require.NoError(t, err)
+	assert.Len(t, stored, 16)
+	assert.Equal(t, time.Hour, server.TTL("embedding:text-embedding-3-small:abc"))
+}
+
+func TestEmbeddingCache_MissWhenRedisIsDown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	client, err := NewClient(&config.RedisConfig{URL: "redis://" + addr})
+	require.NoError(t, err)
+	defer client.Close()
+	cache := NewEmbeddingCache(client, time.Hour)
+
+	cache.Set(context.Background(), "key", []float32{1})
+	_, ok := cache.Get(context.Background(), "key")
+
+	assert.False(t, ok)
+}
@@ -0,0 +1,112 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/repository"
+)
+
+// incrIfExistsScript - キーが存在する場合のみ加算する
+// キーがない（期限切れ・Redis再起動）場合は、次の Usage で永続化先から集計し直す
+var incrIfExistsScript = goredis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return redis.call('INCRBY', KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+// QuotaCounter - Redisの消費量カウンター
+// 永続化先（PostgreSQL）の集計結果をキャッシュし、レビューの保存・非同期のレビューの受け付け・中止のたびに加算する。
+// Redisに接続できない場合は永続化先のカウンターにフォールバックする
+type QuotaCounter struct {
+	client   *goredis.Client
+	fallback repository.QuotaCounter
+}
+
+// NewQuotaCounter - コンストラクタ
+func NewQuotaCounter(client *goredis.Client, fallback repository.QuotaCounter) *QuotaCounter {
+	return &QuotaCounter{
+		client:   client,
+		fallback: fallback,
+	}
+}
+
+// Usage - 当日のレビュー数と当月のトークン数を取得
+func (c *QuotaCounter) Usage(ctx context.Context, userID string, now time.Time) (model.QuotaUsage, error) {
+	dayKey, monthKey := quotaKeys(userID, now)
+
+	values, err := c.client.MGet(ctx, dayKey, monthKey).Result()
+	if err != nil {
+		log.Printf("Warning: failed to get quota usage from redis, falling back: %v", err)
+		return c.fallback.Usage(ctx, userID, now)
+	}
+
+	if len(values) == 2 && values[0] != nil && values[1] != nil {
+		reviews, err1 := strconv.Atoi(fmt.Sprint(values[0]))
+		tokens, err2 := strconv.Atoi(fmt.Sprint(values[1]))
+		if err1 == nil && err2 == nil {
+			return model.QuotaUsage{ReviewsToday: reviews, TokensThisMonth: tokens}, nil
+		}
+	}
+
+	// キャッシュがない場合は永続化先から集計してカウンターを作る
+	usage, err := c.fallback.Usage(ctx, userID, now)
+	if err != nil {
+		return model.QuotaUsage{}, err
+	}
+	dayTTL, monthTTL := quotaTTLs(now)
+	if err := c.seed(ctx, dayKey, usage.ReviewsToday, dayTTL); err != nil {
+		log.Printf("Warning: failed to seed quota counter: %v", err)
+	}
+	if err := c.seed(ctx, monthKey, usage.TokensThisMonth, monthTTL); err != nil {
+		log.Printf("Warning: failed to seed quota counter: %v", err)
+	}
+
+	return usage, nil
+}
+
+// Add - レビュー数・トークン数を加算（0の場合は加算しない）
+func (c *QuotaCounter) Add(ctx context.Context, userID string, now time.Time, reviews, tokens int) error {
+	dayKey, monthKey := quotaKeys(userID, now)
+
+	if reviews != 0 {
+		if err := incrIfExistsScript.Run(ctx, c.client, []string{dayKey}, reviews).Err(); err != nil {
+			return fmt.Errorf("failed to increment review counter: %w", err)
+		}
+	}
+	if tokens != 0 {
+		if err := incrIfExistsScript.Run(ctx, c.client, []string{monthKey}, tokens).Err(); err != nil {
+			return fmt.Errorf("failed to increment token counter: %w", err)
+		}
+	}
+
+	return c.fallback.Add(ctx, userID, now, reviews, tokens)
+}
+
+// seed - カウンターがなければ初期値を設定（他のリクエストが先に設定済みの場合は何もしない）
+func (c *QuotaCounter) seed(ctx context.Context, key string, value int, ttl time.Duration) error {
+	return c.client.SetNX(ctx, key, value, ttl).Err()
+}
+
+// quotaKeys - 当日・当月のカウンターのキー
+func quotaKeys(userID string, now time.Time) (dayKey, monthKey string) {
+	dayStart, monthStart := model.QuotaPeriodStarts(now)
+	dayKey = fmt.Sprintf("quota:%s:reviews:%s", userID, dayStart.Format(time.DateOnly))
+	monthKey = fmt.Sprintf("quota:%s:tokens:%s", userID, monthStart.Format("2006-01"))
+	return dayKey, monthKey
+}
+
+// quotaTTLs - 期間の終わりまで（少し余裕を持たせる）
+func quotaTTLs(now time.Time) (dayTTL, monthTTL time.Duration) {
+	dayStart, monthStart := model.QuotaPeriodStarts(now)
+	dayTTL = dayStart.AddDate(0, 0, 1).Sub(now) + time.Hour
+	monthTTL = monthStart.AddDate(0, 1, 0).Sub(now) + time.Hour
+	return dayTTL, monthTTL
+}
@@ -0,0 +1,124 @@
+package redis
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startFakeRedis - テスト用のRedisサーバー（miniredis）を起動し、REDIS_URL を返す
+func startFakeRedis(t *testing.T) (*miniredis.Miniredis, string) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	return server, "redis://" + server.Addr()
+}
+
+// stubCounter - 永続化先のカウンターのスタブ
+type stubCounter struct {
+	usage model.QuotaUsage
+	calls int
+}
+
+func (s *stubCounter) Usage(ctx context.Context, userID string, now time.Time) (model.QuotaUsage, error) {
+	s.calls++
+	return s.usage, nil
+}
+
+func (s *stubCounter) Add(ctx context.Context, userID string, now time.Time, reviews, tokens int) error {
+	return nil
+}
+
+func TestQuotaCounter(t *testing.T) {
+	server, url := startFakeRedis(t)
+	client, err := NewClient(&config.RedisConfig{URL: url})
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Ping(context.Background()).Err())
+
+	fallback := &stubCounter{usage: model.QuotaUsage{ReviewsToday: 3, TokensThisMonth: 5000}}
+	counter := NewQuotaCounter(client, fallback)
+	ctx := context.Background()
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// 1. キャッシュがない場合は永続化先から集計してカウンターを作る
+	usage, err := counter.Usage(ctx, "user-1", now)
+	require.NoError(t, err)
+	assert.Equal(t, fallback.usage, usage)
+	assert.Equal(t, 1, fallback.calls)
+	assertValue(t, server, "quota:user-1:reviews:2025-01-15", "3")
+	assertValue(t, server, "quota:user-1:tokens:2025-01", "5000")
+	assert.Positive(t, server.TTL("quota:user-1:reviews:2025-01-15"))
+
+	// 2. 加算後はRedisのカウンターを使う
+	require.NoError(t, counter.Add(ctx, "user-1", now, 1, 1200))
+	usage, err = counter.Usage(ctx, "user-1", now)
+	require.NoError(t, err)
+	assert.Equal(t, model.QuotaUsage{ReviewsToday: 4, TokensThisMonth: 6200}, usage)
+	assert.Equal(t, 1, fallback.calls)
+
+	// 受け付けの取り消しはレビュー数のみ戻し、完了した非同期のレビューはトークン数のみ加算する
+	require.NoError(t, counter.Add(ctx, "user-1", now, -1, 0))
+	require.NoError(t, counter.Add(ctx, "user-1", now, 0, 300))
+	usage, err = counter.Usage(ctx, "user-1", now)
+	require.NoError(t, err)
+	assert.Equal(t, model.QuotaUsage{ReviewsToday: 3, TokensThisMonth: 6500}, usage)
+
+	// 3. 日付が変わるとカウンターがないため加算せず、次の Usage で集計し直す
+	tomorrow := now.AddDate(0, 0, 1)
+	require.NoError(t, counter.Add(ctx, "user-1", tomorrow, 1, 100))
+	assert.False(t, server.Exists("quota:user-1:reviews:2025-01-16"))
+}
+
+func assertValue(t *testing.T, server *miniredis.Miniredis, key, expected string) {
+	t.Helper()
+	value, err := server.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, expected, value)
+}
+
+func TestQuotaCounter_FallsBackWhenRedisIsDown(t *testing.T) {
+	// 接続できないポート
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	client, err := NewClient(&config.RedisConfig{URL: "redis://" + addr})
+	require.NoError(t, err)
+	defer client.Close()
+	fallback := &stubCounter{usage: model.QuotaUsage{ReviewsToday: 1}}
+
+	usage, err := NewQuotaCounter(client, fallback).Usage(context.Background(), "user-1", time.Now())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, usage.ReviewsToday)
+}
+
+func TestNewClient_ParsesURL(t *testing.T) {
+	client, err := NewClient(&config.RedisConfig{URL: "rediss://:secret@cache.example.com/2"})
+	require.NoError(t, err)
+	defer client.Close()
+	opts := client.Options()
+	assert.Equal(t, "cache.example.com:6379", opts.Addr)
+	assert.Equal(t, "secret", opts.Password)
+	assert.Equal(t, 2, opts.DB)
+	assert.NotNil(t, opts.TLSConfig)
+
+	// REDIS_URL にない場合は REDIS_PASSWORD・REDIS_DB を使う
+	client, err = NewClient(&config.RedisConfig{URL: "redis://localhost:6379", Password: "env-secret", DB: 3})
+	require.NoError(t, err)
+	defer client.Close()
+	assert.Equal(t, "env-secret", client.Options().Password)
+	assert.Equal(t, 3, client.Options().DB)
+	assert.Nil(t, client.Options().TLSConfig)
+
+	_, err = NewClient(&config.RedisConfig{URL: "http://localhost:6379"})
+	assert.ErrorContains(t, err, "scheme")
+}
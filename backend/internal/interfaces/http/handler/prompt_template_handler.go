@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/s7r8/reviewapp/internal/application/usecase/prompt"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/domain/service"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/middleware"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/response"
+)
+
+// PromptTemplateHandler - プロンプトテンプレート関連のHTTPハンドラ（管理者用）
+type PromptTemplateHandler struct {
+	createPromptTemplateUC   *prompt.CreatePromptTemplateUseCase
+	listPromptTemplatesUC    *prompt.ListPromptTemplatesUseCase
+	previewPromptTemplateUC  *prompt.PreviewPromptTemplateUseCase
+	activatePromptTemplateUC *prompt.ActivatePromptTemplateUseCase
+}
+
+// NewPromptTemplateHandler - コンストラクタ
+func NewPromptTemplateHandler(
+	createPromptTemplateUC *prompt.CreatePromptTemplateUseCase,
+	listPromptTemplatesUC *prompt.ListPromptTemplatesUseCase,
+	previewPromptTemplateUC *prompt.PreviewPromptTemplateUseCase,
+	activatePromptTemplateUC *prompt.ActivatePromptTemplateUseCase,
+) *PromptTemplateHandler {
+	return &PromptTemplateHandler{
+		createPromptTemplateUC:   createPromptTemplateUC,
+		listPromptTemplatesUC:    listPromptTemplatesUC,
+		previewPromptTemplateUC:  previewPromptTemplateUC,
+		activatePromptTemplateUC: activatePromptTemplateUC,
+	}
+}
+
+// CreatePromptTemplateRequest - テンプレート作成リクエスト
+type CreatePromptTemplateRequest struct {
+	Name        string `json:"name"` // 省略時は review_system
+	Content     string `json:"content" validate:"required"`
+	Description string `json:"description"`
+}
+
+// PreviewPromptTemplateRequest - プレビューリクエスト
+// template_id と content のどちらも省略した場合は有効なテンプレートをプレビューする
+type PreviewPromptTemplateRequest struct {
+	TemplateID string `json:"template_id"`
+	Content    string `json:"content"`
+	Language   string `json:"language"`
+	Context    string `json:"context"`
+	Locale     string `json:"locale"` // 描画する言語（ja / en。省略時はユーザー設定の出力言語、Accept-Language の順）
+}
+
+// PromptTemplateResponse - テンプレートのレスポンス
+type PromptTemplateResponse struct {
+	ID          string     `json:"id,omitempty"`
+	Name        string     `json:"name"`
+	Version     int        `json:"version"`
+	Content     string     `json:"content"`
+	Description string     `json:"description,omitempty"`
+	IsActive    bool       `json:"is_active"`
+	CreatedBy   *string    `json:"created_by,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	ActivatedAt *time.Time `json:"activated_at,omitempty"`
+}
+
+// PreviewPromptTemplateResponse - プレビューのレスポンス
+type PreviewPromptTemplateResponse struct {
+	Template     PromptTemplateResponse `json:"template"`
+	Rendered     string                 `json:"rendered"`
+	KnowledgeIDs []string               `json:"knowledge_ids"`
+}
+
+// CreatePromptTemplate - テンプレート作成エンドポイント
+// POST /api/v1/admin/prompt-templates
+func (h *PromptTemplateHandler) CreatePromptTemplate(c echo.Context) error {
+	// 1. リクエストボディをパース
+	var req CreatePromptTemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "リクエストボディが不正です",
+		})
+	}
+
+	// 2. ユーザーIDを取得（作成者として記録）
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 3. UseCase実行
+	output, err := h.createPromptTemplateUC.Execute(c.Request().Context(), prompt.CreatePromptTemplateInput{
+		UserID:      userID,
+		Name:        req.Name,
+		Content:     req.Content,
+		Description: req.Description,
+	})
+	if err != nil {
+		return promptTemplateErrorResponse(c, "CreatePromptTemplate", err)
+	}
+
+	// 4. レスポンスヘッダーに API Code を追加
+	c.Response().Header().Set("X-API-Code", "PT-001")
+
+	// 5. 成功レスポンス
+	return c.JSON(http.StatusCreated, toPromptTemplateResponse(output.Template))
+}
+
+// ListPromptTemplates - テンプレート一覧取得エンドポイント
+// GET /api/v1/admin/prompt-templates?name=review_system
+func (h *PromptTemplateHandler) ListPromptTemplates(c echo.Context) error {
+	output, err := h.listPromptTemplatesUC.Execute(c.Request().Context(), prompt.ListPromptTemplatesInput{
+		Name: c.QueryParam("name"),
+	})
+	if err != nil {
+		return promptTemplateErrorResponse(c, "ListPromptTemplates", err)
+	}
+
+	templates := make([]PromptTemplateResponse, len(output.Templates))
+	for i, t := range output.Templates {
+		templates[i] = toPromptTemplateResponse(t)
+	}
+
+	c.Response().Header().Set("X-API-Code", "PT-002")
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"templates": templates,
+	})
+}
+
+// PreviewPromptTemplate - テンプレートのプレビューエンドポイント
+// POST /api/v1/admin/prompt-templates/preview
+func (h *PromptTemplateHandler) PreviewPromptTemplate(c echo.Context) error {
+	// 1. リクエストボディをパース
+	var req PreviewPromptTemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "リクエストボディが不正です",
+		})
+	}
+
+	// 2. ロケールのバリデーション（指定したロケールはエラーメッセージにも使う）
+	middleware.SetLocale(c, req.Locale)
+	if req.Locale != "" && model.NormalizeLocale(req.Locale) == "" {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: model.ErrLocaleInvalid.Error(),
+		})
+	}
+
+	// 3. ユーザーIDを取得（このユーザーのナレッジを差し込む）
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 4. UseCase実行
+	output, err := h.previewPromptTemplateUC.Execute(c.Request().Context(), prompt.PreviewPromptTemplateInput{
+		UserID:     userID,
+		TemplateID: req.TemplateID,
+		Content:    req.Content,
+		Language:   req.Language,
+		Context:    req.Context,
+		Locale:     middleware.GetLocale(c),
+	})
+	if err != nil {
+		return promptTemplateErrorResponse(c, "PreviewPromptTemplate", err)
+	}
+
+	c.Response().Header().Set("X-API-Code", "PT-003")
+
+	return c.JSON(http.StatusOK, PreviewPromptTemplateResponse{
+		Template:     toPromptTemplateResponse(output.Template),
+		Rendered:     output.Rendered,
+		KnowledgeIDs: output.KnowledgeIDs,
+	})
+}
+
+// ActivatePromptTemplate - テンプレート有効化エンドポイント
+// POST /api/v1/admin/prompt-templates/:id/activate
+func (h *PromptTemplateHandler) ActivatePromptTemplate(c echo.Context) error {
+	output, err := h.activatePromptTemplateUC.Execute(c.Request().Context(), prompt.ActivatePromptTemplateInput{
+		TemplateID: c.Param("id"),
+	})
+	if err != nil {
+		return promptTemplateErrorResponse(c, "ActivatePromptTemplate", err)
+	}
+
+	c.Response().Header().Set("X-API-Code", "PT-004")
+
+	return c.JSON(http.StatusOK, toPromptTemplateResponse(output.Template))
+}
+
+// promptTemplateErrorResponse - エラー種別に応じたレスポンスを返す
+func promptTemplateErrorResponse(c echo.Context, operation string, err error) error {
+	switch {
+	case errors.Is(err, model.ErrPromptTemplateNotFound):
+		return c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error:   "not_found",
+			Message: "プロンプトテンプレートが見つかりません",
+		})
+	case errors.Is(err, service.ErrInvalidPromptTemplate),
+		errors.Is(err, model.ErrPromptTemplateNameInvalid),
+		errors.Is(err, model.ErrPromptTemplateContentRequired),
+		errors.Is(err, model.ErrPromptTemplateContentTooLong):
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+	default:
+		c.Logger().Errorf("%s failed: %v", operation, err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "internal_error",
+			Message: "サーバーエラーが発生しました",
+		})
+	}
+}
+
+// toPromptTemplateResponse - エンティティをレスポンスに変換（未保存・組み込みテンプレートはIDなし）
+func toPromptTemplateResponse(t *model.PromptTemplate) PromptTemplateResponse {
+	res := PromptTemplateResponse{
+		ID:          t.ID,
+		Name:        t.Name,
+		Version:     t.Version,
+		Content:     t.Content,
+		Description: t.Description,
+		IsActive:    t.IsActive,
+		CreatedBy:   t.CreatedBy,
+		ActivatedAt: t.ActivatedAt,
+	}
+	if !t.CreatedAt.IsZero() {
+		createdAt := t.CreatedAt
+		res.CreatedAt = &createdAt
+	}
+	return res
+}
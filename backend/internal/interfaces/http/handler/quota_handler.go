@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/s7r8/reviewapp/internal/application/usecase/quota"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/middleware"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/response"
+)
+
+// QuotaHandler - 利用上限ハンドラー
+type QuotaHandler struct {
+	getQuotaUseCase        *quota.GetQuotaUseCase
+	updateUserQuotaUseCase *quota.UpdateUserQuotaUseCase
+}
+
+// NewQuotaHandler - コンストラクタ
+func NewQuotaHandler(
+	getQuotaUseCase *quota.GetQuotaUseCase,
+	updateUserQuotaUseCase *quota.UpdateUserQuotaUseCase,
+) *QuotaHandler {
+	return &QuotaHandler{
+		getQuotaUseCase:        getQuotaUseCase,
+		updateUserQuotaUseCase: updateUserQuotaUseCase,
+	}
+}
+
+// UpdateUserQuotaRequest - ユーザー個別の上限の更新リクエスト
+// null（省略）の項目はグローバル設定を使う。0は無制限。すべて null の場合は個別の設定を削除する
+type UpdateUserQuotaRequest struct {
+	ReviewsPerDay  *int `json:"reviews_per_day"`
+	TokensPerMonth *int `json:"tokens_per_month"`
+}
+
+// GetMyQuota - 自分の利用上限と残りを取得
+// GET /api/v1/quota
+func (h *QuotaHandler) GetMyQuota(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	return h.getQuota(c, userID, "QT-001")
+}
+
+// GetUserQuota - 指定したユーザーの利用上限と残りを取得（管理者用）
+// GET /api/v1/admin/quotas/:user_id
+func (h *QuotaHandler) GetUserQuota(c echo.Context) error {
+	return h.getQuota(c, c.Param("user_id"), "QT-002")
+}
+
+// getQuota - 利用上限と残りを返す
+func (h *QuotaHandler) getQuota(c echo.Context, userID, apiCode string) error {
+	output, err := h.getQuotaUseCase.Execute(c.Request().Context(), quota.GetQuotaInput{UserID: userID})
+	if err != nil {
+		c.Logger().Errorf("GetQuota failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "internal_error",
+			Message: "利用上限の取得に失敗しました",
+		})
+	}
+
+	c.Response().Header().Set("X-API-Code", apiCode)
+	return c.JSON(http.StatusOK, output)
+}
+
+// UpdateUserQuota - ユーザー個別の利用上限を設定（管理者用）
+// PUT /api/v1/admin/quotas/:user_id
+func (h *QuotaHandler) UpdateUserQuota(c echo.Context) error {
+	// 1. リクエストボディをパース
+	var req UpdateUserQuotaRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request body",
+		})
+	}
+
+	// 2. UseCase実行
+	_, err := h.updateUserQuotaUseCase.Execute(c.Request().Context(), quota.UpdateUserQuotaInput{
+		UserID:         c.Param("user_id"),
+		ReviewsPerDay:  req.ReviewsPerDay,
+		TokensPerMonth: req.TokensPerMonth,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrQuotaInvalid):
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+			})
+		case errors.Is(err, model.ErrUserNotFound):
+			return c.JSON(http.StatusNotFound, response.ErrorResponse{
+				Error:   "not_found",
+				Message: "ユーザーが見つかりません",
+			})
+		}
+		c.Logger().Errorf("UpdateUserQuota failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "internal_error",
+			Message: "利用上限の更新に失敗しました",
+		})
+	}
+
+	// 3. 更新後の上限と残りを返す
+	return h.getQuota(c, c.Param("user_id"), "QT-003")
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/middleware"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/response"
+)
+
+// ReviewApplyHandler - レビューの改善点の適用のハンドラー
+type ReviewApplyHandler struct {
+	applyUsecase *review.ApplyImprovementsUseCase
+}
+
+// NewReviewApplyHandler - コンストラクタ
+func NewReviewApplyHandler(applyUsecase *review.ApplyImprovementsUseCase) *ReviewApplyHandler {
+	return &ReviewApplyHandler{
+		applyUsecase: applyUsecase,
+	}
+}
+
+// ApplyImprovementsRequest - リクエスト
+type ApplyImprovementsRequest struct {
+	Improvements []int `json:"improvements"` // 適用する改善点の番号（structured_result.improvements の0始まりの番号）
+	Save         bool  `json:"save"`         // 適用後のコードを新しいレビューとしてレビューする
+}
+
+// ApplyImprovementsResponse - レスポンス
+type ApplyImprovementsResponse struct {
+	ReviewID       string               `json:"review_id"`
+	Applied        []int                `json:"applied"`
+	NotApplied     []NotAppliedResponse `json:"not_applied"`   // 選択したが適用できなかった改善点と理由
+	Code           string               `json:"code"`          // 適用後のコード全体
+	Diff           string               `json:"diff"`          // レビューしたコードとの unified diff（変更がない場合は空）
+	Formatted      bool                 `json:"formatted"`     // go/format で整形したか（Go のみ）
+	SyntaxErrors   []string             `json:"syntax_errors"` // 適用後のコードの構文エラー（Go のみ検査する）
+	FollowUpReview *ReviewCodeResponse  `json:"follow_up_review,omitempty"`
+}
+
+// NotAppliedResponse - 適用できなかった改善点
+type NotAppliedResponse struct {
+	Index  int    `json:"index"`
+	Reason string `json:"reason"`
+}
+
+// ApplyImprovements - POST /api/v1/reviews/:id/apply
+// save=true の場合は適用後のコードをレビューして 201（?async=true の場合は実行待ちで受け付けて 202）を返す
+func (h *ReviewApplyHandler) ApplyImprovements(c echo.Context) error {
+	// 1. リクエストボディを取得
+	var req ApplyImprovementsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request body",
+		})
+	}
+
+	// 2. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 3. UseCase実行（他のユーザーのレビューは見つからない扱い）
+	async := c.QueryParam("async") == "true"
+	output, err := h.applyUsecase.Execute(c.Request().Context(), review.ApplyImprovementsInput{
+		ReviewID:     c.Param("id"),
+		UserID:       userID,
+		Improvements: req.Improvements,
+		Save:         req.Save,
+		Async:        async,
+	})
+	if err != nil {
+		var quotaErr *model.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceeded(c, quotaErr)
+		}
+		if status, errResp, ok := applyErrorResponse(err); ok {
+			return c.JSON(status, errResp)
+		}
+		c.Logger().Errorf("ApplyImprovements failed: %v", err)
+		status, errResp := reviewErrorResponse(err)
+		return c.JSON(status, errResp)
+	}
+
+	// 4. レスポンスを構築
+	resp := ApplyImprovementsResponse{
+		ReviewID:     output.Review.ID,
+		Applied:      output.Applied,
+		NotApplied:   make([]NotAppliedResponse, 0, len(output.NotApplied)),
+		Code:         output.Code,
+		Diff:         output.Diff,
+		Formatted:    output.Formatted,
+		SyntaxErrors: output.SyntaxErrors,
+	}
+	if resp.SyntaxErrors == nil {
+		resp.SyntaxErrors = []string{}
+	}
+	for _, n := range output.NotApplied {
+		resp.NotApplied = append(resp.NotApplied, NotAppliedResponse{
+			Index:  n.Index,
+			Reason: response.LocalizeMessage(n.Reason.Error(), middleware.GetLocale(c)),
+		})
+	}
+
+	// 5. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "RV-022")
+	if output.FollowUp == nil {
+		return c.JSON(http.StatusOK, resp)
+	}
+	followUp := toReviewCodeResponse(output.FollowUp)
+	resp.FollowUpReview = &followUp
+	if async {
+		c.Response().Header().Set(echo.HeaderLocation, "/api/v1/reviews/"+output.FollowUp.ID)
+		return c.JSON(http.StatusAccepted, resp)
+	}
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// applyErrorResponse - レビューが見つからない・適用できないレビュー・改善点の指定が不正なエラーのレスポンス
+func applyErrorResponse(err error) (int, response.ErrorResponse, bool) {
+	switch {
+	case errors.Is(err, model.ErrReviewNotFound):
+		return http.StatusNotFound, response.ErrorResponse{
+			Error:   "not_found",
+			Message: model.ErrReviewNotFound.Error(),
+		}, true
+	case errors.Is(err, model.ErrReviewNotCompleted),
+		errors.Is(err, model.ErrApplyDiffReview):
+		return http.StatusConflict, response.ErrorResponse{
+			Error:   "conflict",
+			Message: err.Error(),
+		}, true
+	case errors.Is(err, model.ErrApplyNoImprovements),
+		errors.Is(err, model.ErrApplyImprovementIndex),
+		errors.Is(err, model.ErrImprovementNotApplicable),
+		errors.Is(err, model.ErrImprovementNoCodeAfter),
+		errors.Is(err, model.ErrImprovementsOverlap),
+		errors.Is(err, model.ErrAppliedCodeInvalid):
+		return http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		}, true
+	}
+	return 0, response.ErrorResponse{}, false
+}
@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/middleware"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/response"
+)
+
+// ReviewConversationHandler - レビューについての会話のハンドラー
+type ReviewConversationHandler struct {
+	sendMessageUsecase     *review.ReviewConversationUseCase
+	listMessagesUsecase    *review.ListReviewMessagesUseCase
+	createKnowledgeUsecase *review.CreateKnowledgeFromMessageUseCase
+}
+
+// NewReviewConversationHandler - コンストラクタ
+func NewReviewConversationHandler(
+	sendMessageUsecase *review.ReviewConversationUseCase,
+	listMessagesUsecase *review.ListReviewMessagesUseCase,
+	createKnowledgeUsecase *review.CreateKnowledgeFromMessageUseCase,
+) *ReviewConversationHandler {
+	return &ReviewConversationHandler{
+		sendMessageUsecase:     sendMessageUsecase,
+		listMessagesUsecase:    listMessagesUsecase,
+		createKnowledgeUsecase: createKnowledgeUsecase,
+	}
+}
+
+// SendReviewMessageRequest - リクエスト
+type SendReviewMessageRequest struct {
+	Content string `json:"content"`
+	Locale  string `json:"locale"` // ja / en（省略時はレビューの言語）
+}
+
+// SendReviewMessageResponse - 送信したメッセージとLLMの返答
+type SendReviewMessageResponse struct {
+	Question *model.ReviewMessage `json:"question"`
+	Reply    *model.ReviewMessage `json:"reply"`
+}
+
+// ListReviewMessagesResponse - レビューの会話（古い順）
+type ListReviewMessagesResponse struct {
+	Items []*model.ReviewMessage `json:"items"`
+}
+
+// CreateKnowledgeFromMessageRequest - リクエスト（省略した項目はLLMの提案を使う）
+type CreateKnowledgeFromMessageRequest struct {
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+	Category string `json:"category"`
+	Priority int    `json:"priority"`
+}
+
+// SendReviewMessage - POST /api/v1/reviews/:id/messages
+func (h *ReviewConversationHandler) SendReviewMessage(c echo.Context) error {
+	// 1. リクエストボディを取得
+	var req SendReviewMessageRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request body",
+		})
+	}
+
+	// 2. ロケールのバリデーション（メッセージのバリデーションはUseCaseで行う）
+	middleware.SetLocale(c, req.Locale)
+	if req.Locale != "" && model.NormalizeLocale(req.Locale) == "" {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: model.ErrLocaleInvalid.Error(),
+		})
+	}
+
+	// 3. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 4. UseCase実行
+	output, err := h.sendMessageUsecase.Execute(c.Request().Context(), review.SendReviewMessageInput{
+		ReviewID: c.Param("id"),
+		UserID:   userID,
+		Content:  req.Content,
+		Locale:   model.NormalizeLocale(req.Locale),
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrReviewMessageRequired) ||
+			errors.Is(err, model.ErrReviewMessageTooLong) ||
+			errors.Is(err, model.ErrReviewMessageLimit) {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+			})
+		}
+		var quotaErr *model.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceeded(c, quotaErr)
+		}
+		if status, errResp, ok := conversationErrorResponse(err); ok {
+			return c.JSON(status, errResp)
+		}
+		c.Logger().Errorf("SendReviewMessage failed: %v", err)
+		status, errResp := reviewErrorResponse(err)
+		return c.JSON(status, errResp)
+	}
+
+	// 5. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "RV-012")
+	return c.JSON(http.StatusCreated, SendReviewMessageResponse{
+		Question: output.Question,
+		Reply:    output.Reply,
+	})
+}
+
+// ListReviewMessages - GET /api/v1/reviews/:id/messages
+func (h *ReviewConversationHandler) ListReviewMessages(c echo.Context) error {
+	// 1. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 2. UseCase実行（他のユーザーのレビューは見つからない扱い）
+	messages, err := h.listMessagesUsecase.Execute(c.Request().Context(), review.ListReviewMessagesInput{
+		ReviewID: c.Param("id"),
+		UserID:   userID,
+	})
+	if err != nil {
+		if status, errResp, ok := conversationErrorResponse(err); ok {
+			return c.JSON(status, errResp)
+		}
+		c.Logger().Errorf("ListReviewMessages failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "internal_error",
+			Message: "サーバーエラーが発生しました",
+		})
+	}
+
+	// 3. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "RV-013")
+	return c.JSON(http.StatusOK, ListReviewMessagesResponse{Items: messages})
+}
+
+// CreateKnowledgeFromMessage - POST /api/v1/reviews/:id/messages/:message_id/knowledge
+func (h *ReviewConversationHandler) CreateKnowledgeFromMessage(c echo.Context) error {
+	// 1. リクエストボディを取得（ボディなしはLLMの提案をそのまま使う）
+	var req CreateKnowledgeFromMessageRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request body",
+		})
+	}
+
+	// 2. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 3. UseCase実行
+	knowledge, err := h.createKnowledgeUsecase.Execute(c.Request().Context(), review.CreateKnowledgeFromMessageInput{
+		ReviewID:  c.Param("id"),
+		MessageID: c.Param("message_id"),
+		UserID:    userID,
+		Title:     req.Title,
+		Content:   req.Content,
+		Category:  req.Category,
+		Priority:  req.Priority,
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrTitleRequired) ||
+			errors.Is(err, model.ErrTitleTooLong) ||
+			errors.Is(err, model.ErrContentRequired) ||
+			errors.Is(err, model.ErrCategoryRequired) ||
+			errors.Is(err, model.ErrCategoryInvalid) ||
+			errors.Is(err, model.ErrPriorityOutOfRange) {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+			})
+		}
+		if errors.Is(err, model.ErrReviewMessageKnowledgeExists) {
+			return c.JSON(http.StatusConflict, response.ErrorResponse{
+				Error:   "conflict",
+				Message: err.Error(),
+			})
+		}
+		if status, errResp, ok := conversationErrorResponse(err); ok {
+			return c.JSON(status, errResp)
+		}
+		c.Logger().Errorf("CreateKnowledgeFromMessage failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "internal_error",
+			Message: "サーバーエラーが発生しました",
+		})
+	}
+
+	// 4. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "RV-014")
+	return c.JSON(http.StatusCreated, knowledge)
+}
+
+// conversationErrorResponse - 会話の無効化・レビューとメッセージが見つからない・レビューが完了していないエラーのレスポンス
+func conversationErrorResponse(err error) (int, response.ErrorResponse, bool) {
+	switch {
+	case errors.Is(err, model.ErrConversationDisabled):
+		return http.StatusForbidden, response.ErrorResponse{
+			Error:   "forbidden",
+			Message: model.ErrConversationDisabled.Error(),
+		}, true
+	case errors.Is(err, model.ErrReviewNotFound):
+		return http.StatusNotFound, response.ErrorResponse{
+			Error:   "not_found",
+			Message: model.ErrReviewNotFound.Error(),
+		}, true
+	case errors.Is(err, model.ErrReviewMessageNotFound):
+		return http.StatusNotFound, response.ErrorResponse{
+			Error:   "not_found",
+			Message: model.ErrReviewMessageNotFound.Error(),
+		}, true
+	case errors.Is(err, model.ErrReviewNotCompleted):
+		return http.StatusConflict, response.ErrorResponse{
+			Error:   "conflict",
+			Message: model.ErrReviewNotCompleted.Error(),
+		}, true
+	}
+	return 0, response.ErrorResponse{}, false
+}
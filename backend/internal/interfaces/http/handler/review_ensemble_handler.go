@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/middleware"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/response"
+)
+
+// ReviewEnsembleHandler - 複数モデルによるレビューのハンドラー
+type ReviewEnsembleHandler struct {
+	ensembleReviewUsecase    *review.EnsembleReviewUseCase
+	getEnsembleReviewUsecase *review.GetEnsembleReviewUseCase
+}
+
+// NewReviewEnsembleHandler - コンストラクタ
+func NewReviewEnsembleHandler(
+	ensembleReviewUsecase *review.EnsembleReviewUseCase,
+	getEnsembleReviewUsecase *review.GetEnsembleReviewUseCase,
+) *ReviewEnsembleHandler {
+	return &ReviewEnsembleHandler{
+		ensembleReviewUsecase:    ensembleReviewUsecase,
+		getEnsembleReviewUsecase: getEnsembleReviewUsecase,
+	}
+}
+
+// EnsembleReviewRequest - リクエスト
+type EnsembleReviewRequest struct {
+	Code     string   `json:"code"`
+	Language string   `json:"language"`
+	Context  string   `json:"context"`
+	Models   []string `json:"models"` // "provider:model"（省略時は設定されたすべてのモデル）
+	Mode     string   `json:"mode"`   // compare / merge（省略時は compare）
+	Locale   string   `json:"locale"` // ja / en（省略時はユーザー設定の出力言語、Accept-Language の順）
+}
+
+// EnsembleReviewResponse - レスポンス
+type EnsembleReviewResponse struct {
+	EnsembleID string                    `json:"ensemble_id"`
+	Mode       string                    `json:"mode"`
+	Reviews    []ReviewCodeResponse      `json:"reviews"`
+	Merged     *ReviewCodeResponse       `json:"merged,omitempty"`
+	Failures   []EnsembleFailureResponse `json:"failures"`
+}
+
+// EnsembleFailureResponse - レビューに失敗したモデル
+type EnsembleFailureResponse struct {
+	Model string `json:"model"`
+	Error string `json:"error"`
+}
+
+// EnsembleModelsResponse - 比較に使えるモデル一覧
+type EnsembleModelsResponse struct {
+	Models []string `json:"models"`
+}
+
+// ReviewEnsemble - POST /api/v1/reviews/ensemble
+func (h *ReviewEnsembleHandler) ReviewEnsemble(c echo.Context) error {
+	// 1. リクエストボディを取得
+	var req EnsembleReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request body",
+		})
+	}
+
+	// 2. バリデーション
+	middleware.SetLocale(c, req.Locale)
+	if err := validateReviewCodeRequest(&ReviewCodeRequest{Code: req.Code, Language: req.Language, Locale: req.Locale}); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+	}
+
+	// 3. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 4. UseCase実行
+	output, err := h.ensembleReviewUsecase.Execute(c.Request().Context(), review.EnsembleReviewInput{
+		ReviewCodeInput: review.ReviewCodeInput{
+			UserID:   userID,
+			Code:     req.Code,
+			Language: req.Language,
+			Context:  req.Context,
+			Locale:   middleware.GetLocale(c),
+		},
+		Models: req.Models,
+		Mode:   req.Mode,
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrEnsembleModelNotFound) ||
+			errors.Is(err, model.ErrEnsembleTooFewModels) ||
+			errors.Is(err, model.ErrEnsembleModeInvalid) {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+			})
+		}
+		var quotaErr *model.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceeded(c, quotaErr)
+		}
+		c.Logger().Errorf("ReviewEnsemble failed: %v", err)
+		status, errResp := reviewErrorResponse(err)
+		return c.JSON(status, errResp)
+	}
+
+	// 5. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "RV-006")
+	return c.JSON(http.StatusCreated, toEnsembleReviewResponse(output))
+}
+
+// GetEnsemble - GET /api/v1/reviews/ensembles/:id
+func (h *ReviewEnsembleHandler) GetEnsemble(c echo.Context) error {
+	// 1. パスパラメータから ensemble_id を取得
+	ensembleID := c.Param("id")
+	if ensembleID == "" {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: "ensemble_id は必須です",
+		})
+	}
+
+	// 2. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 3. UseCase実行（他のユーザーのレビューは見つからない扱い）
+	output, err := h.getEnsembleReviewUsecase.Execute(c.Request().Context(), review.GetEnsembleReviewInput{
+		EnsembleID: ensembleID,
+		UserID:     userID,
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrEnsembleNotFound) {
+			return c.JSON(http.StatusNotFound, response.ErrorResponse{
+				Error:   "not_found",
+				Message: err.Error(),
+			})
+		}
+		c.Logger().Errorf("GetEnsemble failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "internal_error",
+			Message: "サーバーエラーが発生しました",
+		})
+	}
+
+	// 4. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "RV-007")
+	return c.JSON(http.StatusOK, toEnsembleReviewResponse(output))
+}
+
+// ListEnsembleModels - GET /api/v1/reviews/ensemble/models
+func (h *ReviewEnsembleHandler) ListEnsembleModels(c echo.Context) error {
+	c.Response().Header().Set("X-API-Code", "RV-008")
+	return c.JSON(http.StatusOK, EnsembleModelsResponse{
+		Models: h.ensembleReviewUsecase.Models(),
+	})
+}
+
+// toEnsembleReviewResponse - 複数モデルのレビューをレスポンス形式に変換
+func toEnsembleReviewResponse(output *review.EnsembleReviewOutput) EnsembleReviewResponse {
+	resp := EnsembleReviewResponse{
+		EnsembleID: output.EnsembleID,
+		Mode:       output.Mode,
+		Reviews:    make([]ReviewCodeResponse, len(output.Reviews)),
+		Failures:   make([]EnsembleFailureResponse, len(output.Failures)),
+	}
+	for i, rev := range output.Reviews {
+		resp.Reviews[i] = toReviewCodeResponse(rev)
+	}
+	if output.Merged != nil {
+		merged := toReviewCodeResponse(output.Merged)
+		resp.Merged = &merged
+	}
+	for i, f := range output.Failures {
+		resp.Failures[i] = EnsembleFailureResponse{Model: f.Model, Error: f.Error}
+	}
+	return resp
+}
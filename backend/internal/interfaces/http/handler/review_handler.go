@@ -1,11 +1,18 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
 	"github.com/s7r8/reviewapp/internal/interfaces/http/middleware"
 	"github.com/s7r8/reviewapp/internal/interfaces/http/response"
 )
@@ -16,6 +23,7 @@ type ReviewHandler struct {
 	updateFeedbackUsecase *review.UpdateFeedbackUseCase
 	listReviewsUsecase    *review.ListReviewsUseCase
 	getReviewUsecase      *review.GetReviewUseCase // ★ 追加
+	reviewJobUsecase      *review.ReviewJobUseCase
 }
 
 // NewReviewHandler - コンストラクタ
@@ -24,16 +32,19 @@ func NewReviewHandler(
 	updateFeedbackUsecase *review.UpdateFeedbackUseCase,
 	listReviewsUsecase *review.ListReviewsUseCase,
 	getReviewUsecase *review.GetReviewUseCase, // ★ 追加
+	reviewJobUsecase *review.ReviewJobUseCase,
 ) *ReviewHandler {
 	return &ReviewHandler{
 		reviewCodeUsecase:     reviewCodeUsecase,
 		updateFeedbackUsecase: updateFeedbackUsecase,
 		listReviewsUsecase:    listReviewsUsecase,
 		getReviewUsecase:      getReviewUsecase, // ★ 追加
+		reviewJobUsecase:      reviewJobUsecase,
 	}
 }
 
 // ReviewCode - POST /api/v1/reviews
+// ?async=true の場合はレビューを実行待ちで受け付けて 202 を返す（結果は GET /api/v1/reviews/:id で取得する）
 func (h *ReviewHandler) ReviewCode(c echo.Context) error {
 	var req ReviewCodeRequest
 	if err := c.Bind(&req); err != nil {
@@ -43,7 +54,8 @@ func (h *ReviewHandler) ReviewCode(c echo.Context) error {
 		})
 	}
 
-	// 2. バリデーション
+	// 2. バリデーション（リクエストで指定したロケールはエラーメッセージにも使う）
+	middleware.SetLocale(c, req.Locale)
 	if err := validateReviewCodeRequest(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
 			Error:   "validation_error",
@@ -62,70 +74,346 @@ func (h *ReviewHandler) ReviewCode(c echo.Context) error {
 
 	// Usecaseを実行
 	input := review.ReviewCodeInput{
-		UserID:   userID,
-		Code:     req.Code,
-		Language: req.Language,
-		Context:  req.Context,
+		UserID:       userID,
+		Code:         req.Code,
+		Language:     req.Language,
+		Context:      req.Context,
+		ForceRefresh: req.ForceRefresh,
+		Locale:       middleware.GetLocale(c),
+		FilePath:     req.FilePath,
+		Diff:         req.Diff,
+		ContextFiles: toContextFiles(req.ContextFiles),
+	}
+
+	if c.QueryParam("async") == "true" {
+		return h.submitReview(c, input)
 	}
 
 	output, err := h.reviewCodeUsecase.Execute(c.Request().Context(), input)
 	if err != nil {
+		var quotaErr *model.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceeded(c, quotaErr)
+		}
 		// エラーの詳細をログに出力
 		c.Logger().Errorf("ReviewCode failed: %v", err)
+		status, errResp := reviewErrorResponse(err)
+		return c.JSON(status, errResp)
+	}
+
+	// 構造化データを含めてレスポンス
+	responseData := toReviewCodeResponse(output.Review)
+
+	// ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "RV-001")
+	return c.JSON(http.StatusCreated, responseData)
+}
+
+// submitReview - レビューを非同期のジョブとして受け付ける
+func (h *ReviewHandler) submitReview(c echo.Context, input review.ReviewCodeInput) error {
+	job, err := h.reviewJobUsecase.Submit(c.Request().Context(), input)
+	if err != nil {
+		var quotaErr *model.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceeded(c, quotaErr)
+		}
+		c.Logger().Errorf("ReviewCode (async) failed: %v", err)
+		status, errResp := reviewErrorResponse(err)
+		return c.JSON(status, errResp)
+	}
+
+	// 結果を取得するURLを返す
+	c.Response().Header().Set(echo.HeaderLocation, "/api/v1/reviews/"+job.ID)
+	c.Response().Header().Set("X-API-Code", "RV-001")
+	return c.JSON(http.StatusAccepted, toReviewCodeResponse(job))
+}
+
+// RetryReview - POST /api/v1/reviews/:id/retry
+// 失敗した非同期のレビューを実行待ちに戻して 202 を返す
+func (h *ReviewHandler) RetryReview(c echo.Context) error {
+	// 1. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 2. UseCase実行（他のユーザーのレビューは見つからない扱い）
+	job, err := h.reviewJobUsecase.Retry(c.Request().Context(), review.RetryReviewInput{
+		ReviewID: c.Param("id"),
+		UserID:   userID,
+	})
+	if err != nil {
+		var quotaErr *model.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceeded(c, quotaErr)
+		}
+		if errors.Is(err, model.ErrReviewNotFound) {
+			return c.JSON(http.StatusNotFound, response.ErrorResponse{
+				Error:   "not_found",
+				Message: model.ErrReviewNotFound.Error(),
+			})
+		}
+		if errors.Is(err, model.ErrReviewJobNotRetryable) {
+			return c.JSON(http.StatusConflict, response.ErrorResponse{
+				Error:   "conflict",
+				Message: model.ErrReviewJobNotRetryable.Error(),
+			})
+		}
+		c.Logger().Errorf("RetryReview failed: %v", err)
 		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
 			Error:   "internal_error",
 			Message: "サーバーエラーが発生しました",
 		})
 	}
 
-	// 構造化データを含めてレスポンス
+	// 3. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set(echo.HeaderLocation, "/api/v1/reviews/"+job.ID)
+	c.Response().Header().Set("X-API-Code", "RV-015")
+	return c.JSON(http.StatusAccepted, toReviewCodeResponse(job))
+}
+
+// ReviewCodeStream - POST /api/v1/reviews/stream
+// 生成中のテキストをServer-Sent Eventsで逐次返し、保存済みレビューを最後のイベントで返す
+func (h *ReviewHandler) ReviewCodeStream(c echo.Context) error {
+	var req ReviewCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request body",
+		})
+	}
+
+	// 2. バリデーション（リクエストで指定したロケールはエラーメッセージにも使う）
+	middleware.SetLocale(c, req.Locale)
+	if err := validateReviewCodeRequest(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+	}
+
+	// 3. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 4. SSEを開始する前に利用上限を確認（上限超過は429で返す）
+	if err := h.reviewCodeUsecase.CheckQuota(c.Request().Context(), userID); err != nil {
+		var quotaErr *model.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceeded(c, quotaErr)
+		}
+		c.Logger().Errorf("ReviewCodeStream quota check failed: %v", err)
+		status, errResp := reviewErrorResponse(err)
+		return c.JSON(status, errResp)
+	}
+
+	// 5. SSEヘッダーを送信
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no") // nginx等のバッファリングを無効化
+	res.Header().Set("X-API-Code", "RV-005")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	// クライアントが途中で切断してもレビューを最後まで生成・保存するため、
+	// リクエストのキャンセルを引き継がないコンテキストでUsecaseを実行する
+	clientCtx := c.Request().Context()
+	stream := &sseWriter{res: res, done: clientCtx.Done()}
+
+	input := review.ReviewCodeInput{
+		UserID:       userID,
+		Code:         req.Code,
+		Language:     req.Language,
+		Context:      req.Context,
+		ForceRefresh: req.ForceRefresh,
+		Locale:       middleware.GetLocale(c),
+		FilePath:     req.FilePath,
+		Diff:         req.Diff,
+		ContextFiles: toContextFiles(req.ContextFiles),
+	}
+
+	output, err := h.reviewCodeUsecase.ExecuteStream(context.WithoutCancel(clientCtx), input, func(text string) {
+		stream.send("token", map[string]string{"text": text})
+	})
+	if err != nil {
+		c.Logger().Errorf("ReviewCodeStream failed: %v", err)
+		_, errResp := reviewErrorResponse(err)
+		// SSEのイベントはJSONシリアライザを通らないため、ここでロケールの言語にする
+		errResp.Message = response.LocalizeMessage(errResp.Message, middleware.GetLocale(c))
+		stream.send("error", errResp)
+		return nil
+	}
+
+	// 6. 保存済みレビューを最後のイベントとして送信
+	stream.send("review", toReviewCodeResponse(output.Review))
+	return nil
+}
+
+// reviewErrorResponse - レビュー生成エラーをHTTPステータスとレスポンスに変換
+func reviewErrorResponse(err error) (int, response.ErrorResponse) {
+	// 利用上限超過（ストリーミングでSSE開始後に上限に達した場合）
+	if errors.Is(err, model.ErrQuotaExceeded) {
+		return http.StatusTooManyRequests, response.ErrorResponse{
+			Error:   "quota_exceeded",
+			Message: err.Error(),
+		}
+	}
+	// 差分の形式・内容が不正（差分の解析はUsecaseで行う）
+	if errors.Is(err, model.ErrDiffInvalid) ||
+		errors.Is(err, model.ErrDiffNoChanges) ||
+		errors.Is(err, model.ErrDiffTooManyHunks) ||
+		errors.Is(err, model.ErrDiffWithCode) ||
+		errors.Is(err, model.ErrDiffLanguage) {
+		return http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		}
+	}
+	// LLMが一時的に利用できない（サーキット遮断中・リトライ後も429 / 5xx）
+	if external.IsUnavailable(err) {
+		return http.StatusServiceUnavailable, response.ErrorResponse{
+			Error:   "llm_api_error",
+			Message: "AI APIが一時的に利用できません",
+		}
+	}
+	return http.StatusInternalServerError, response.ErrorResponse{
+		Error:   "internal_error",
+		Message: "サーバーエラーが発生しました",
+	}
+}
+
+// QuotaExceededResponse - 利用上限超過のエラーレスポンス（429）
+type QuotaExceededResponse struct {
+	Error   string                    `json:"error"`
+	Message string                    `json:"message"`
+	Quota   *model.QuotaExceededError `json:"quota"`
+}
+
+// quotaExceeded - 利用上限超過を429で返す（Retry-After は上限がリセットされるまでの秒数）
+func quotaExceeded(c echo.Context, err *model.QuotaExceededError) error {
+	if wait := time.Until(err.ResetAt); wait > 0 {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+	}
+	return c.JSON(http.StatusTooManyRequests, QuotaExceededResponse{
+		Error:   "quota_exceeded",
+		Message: response.LocalizeMessage(err.Error(), middleware.GetLocale(c)),
+		Quota:   err,
+	})
+}
+
+// sseWriter - Server-Sent Eventsの書き込み
+// クライアント切断後は書き込みをスキップする
+type sseWriter struct {
+	res  *echo.Response
+	done <-chan struct{}
+}
+
+// send - イベントを1件送信
+func (w *sseWriter) send(event string, data interface{}) {
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w.res, "event: %s\ndata: %s\n\n", event, payload)
+	w.res.Flush()
+}
+
+// toReviewCodeResponse - レビューをレスポンス形式に変換
+func toReviewCodeResponse(rev *model.Review) ReviewCodeResponse {
 	var structuredResult *StructuredReviewResult
-	if output.Review.StructuredResult != nil {
+	if rev.StructuredResult != nil {
+		improvements := make([]Improvement, len(rev.StructuredResult.Improvements))
+		for i, imp := range rev.StructuredResult.Improvements {
+			improvements[i] = Improvement{
+				Title:       imp.Title,
+				Description: imp.Description,
+				CodeBefore:  imp.CodeBefore,
+				CodeAfter:   imp.CodeAfter,
+				Severity:    imp.Severity,
+				AgreedBy:    imp.AgreedBy,
+				Consensus:   imp.Consensus,
+				FilePath:    imp.FilePath,
+				StartLine:   imp.StartLine,
+				EndLine:     imp.EndLine,
+			}
+		}
 		structuredResult = &StructuredReviewResult{
-			Summary:    output.Review.StructuredResult.Summary,
-			GoodPoints: output.Review.StructuredResult.GoodPoints,
-			Improvements: func() []Improvement {
-				improvements := make([]Improvement, len(output.Review.StructuredResult.Improvements))
-				for i, imp := range output.Review.StructuredResult.Improvements {
-					improvements[i] = Improvement{
-						Title:       imp.Title,
-						Description: imp.Description,
-						CodeAfter:   imp.CodeAfter,
-						Severity:    imp.Severity,
-					}
-				}
-				return improvements
-			}(),
+			Summary:      rev.StructuredResult.Summary,
+			GoodPoints:   rev.StructuredResult.GoodPoints,
+			Improvements: improvements,
 		}
 	}
 
-	responseData := ReviewCodeResponse{
-		ID:               output.Review.ID,
-		UserID:           output.Review.UserID,
-		Code:             output.Review.Code,
-		Language:         output.Review.Language,
-		Context:          output.Review.Context,
-		ReviewResult:     output.Review.ReviewResult,
-		StructuredResult: structuredResult,
-		UsedKnowledgeIDs: output.Review.ReferencedKnowledge,
-		LLMProvider:      output.Review.LLMProvider,
-		LLMModel:         output.Review.LLMModel,
-		TokensUsed:       output.Review.TokensUsed,
-		CreatedAt:        output.Review.CreatedAt,
+	return ReviewCodeResponse{
+		ID:                       rev.ID,
+		UserID:                   rev.UserID,
+		Code:                     rev.Code,
+		Language:                 rev.Language,
+		Locale:                   rev.Locale,
+		FileName:                 rev.FileName(),
+		FilePath:                 rev.FilePath,
+		SessionID:                rev.SessionID,
+		RerunOfReviewID:          rev.RerunOfReviewID,
+		FollowUpOfReviewID:       rev.FollowUpOfReviewID,
+		InputFormat:              rev.InputFormat,
+		Context:                  rev.Context,
+		ReviewResult:             rev.ReviewResult,
+		StructuredResult:         structuredResult,
+		ResultSource:             rev.ResultSource,
+		PromptTemplateID:         rev.PromptTemplateID,
+		PromptTemplateVersion:    rev.PromptTemplateVersion,
+		UsedKnowledgeIDs:         rev.ReferencedKnowledge,
+		LLMProvider:              rev.LLMProvider,
+		LLMModel:                 rev.LLMModel,
+		TokensUsed:               rev.TokensUsed,
+		Usage:                    rev.Usage,
+		Cost:                     rev.Cost,
+		CostCurrency:             rev.CostCurrency,
+		CacheHit:                 rev.CacheHit,
+		CachedFromReviewID:       rev.CachedFromReviewID,
+		EnsembleID:               rev.EnsembleID,
+		PromptInjectionSuspected: rev.PromptInjectionSuspected(),
+		PromptInjectionSignals:   rev.PromptInjectionSignals,
+		Analysis:                 rev.StaticAnalysis,
+		Status:                   rev.Status,
+		Async:                    rev.Async,
+		ErrorMessage:             rev.ErrorMessage,
+		Attempts:                 rev.Attempts,
+		StartedAt:                rev.StartedAt,
+		CompletedAt:              rev.CompletedAt,
+		CreatedAt:                rev.CreatedAt,
 	}
-
-	// ヘッダーにAPI Codeを追加
-	c.Response().Header().Set("X-API-Code", "RV-001")
-	return c.JSON(http.StatusCreated, responseData)
 }
 
-// バリデーション
+// バリデーション（差分の場合、言語は省略すると変更したファイルの拡張子から推定する）
 func validateReviewCodeRequest(req *ReviewCodeRequest) error {
-	if req.Code == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "コードは必須です")
+	if req.Code == "" && req.Diff == "" {
+		return errors.New("コードは必須です")
+	}
+	if req.Code != "" && req.Diff != "" {
+		return model.ErrDiffWithCode
+	}
+	if req.Diff == "" && req.Language == "" {
+		return errors.New("プログラミング言語は必須です")
 	}
-	if req.Language == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "プログラミング言語は必須です")
+	if req.Locale != "" && model.NormalizeLocale(req.Locale) == "" {
+		return model.ErrLocaleInvalid
 	}
 	// Context はオプション（空でもOK）
 	return nil
@@ -136,23 +424,79 @@ type ReviewCodeRequest struct {
 	Code     string `json:"code" validate:"required"`
 	Language string `json:"language" validate:"required"`
 	Context  string `json:"context"`
+	// ForceRefresh - trueの場合は同じ入力のレビュー結果があっても再利用せずにレビューし直す
+	ForceRefresh bool `json:"force_refresh"`
+	// Locale - プロンプト・レビュー結果・エラーメッセージの言語（ja / en）
+	// 省略時はユーザー設定の出力言語、Accept-Language の順に決める
+	Locale string `json:"locale"`
+	// FilePath - レビューするファイルのパス（オプショナル。履歴・ダッシュボードにファイル名を表示する）
+	FilePath string `json:"file_path"`
+	// Diff - code の代わりに unified diff を指定すると、追加・変更したハンクのみをレビューする
+	Diff string `json:"diff"`
+	// ContextFiles - 差分のレビューで参照する変更後のファイル全体（オプショナル）
+	ContextFiles []ContextFileRequest `json:"context_files"`
+}
+
+// ContextFileRequest - 差分のレビューで参照するファイル
+type ContextFileRequest struct {
+	Path string `json:"path"`
+	Code string `json:"code"`
+}
+
+// toContextFiles - 差分のレビューで参照するファイルをUsecaseの入力に変換
+func toContextFiles(files []ContextFileRequest) []review.ContextFile {
+	if len(files) == 0 {
+		return nil
+	}
+	result := make([]review.ContextFile, len(files))
+	for i, f := range files {
+		result[i] = review.ContextFile{Path: f.Path, Code: f.Code}
+	}
+	return result
 }
 
 // ReviewCodeResponse - レスポンス
 type ReviewCodeResponse struct {
-	ID               string                  `json:"id"`
-	UserID           string                  `json:"user_id"`
-	Code             string                  `json:"code"`
-	Language         string                  `json:"language"`
-	FileName         string                  `json:"file_name,omitempty"`
-	Context          string                  `json:"context,omitempty"`
-	ReviewResult     string                  `json:"review_result"`
-	StructuredResult *StructuredReviewResult `json:"structured_result,omitempty"`
-	UsedKnowledgeIDs []string                `json:"used_knowledge_ids"`
-	LLMProvider      string                  `json:"llm_provider"`
-	LLMModel         string                  `json:"llm_model"`
-	TokensUsed       int                     `json:"tokens_used"`
-	CreatedAt        time.Time               `json:"created_at"`
+	ID                    string                  `json:"id"`
+	UserID                string                  `json:"user_id"`
+	Code                  string                  `json:"code"`
+	Language              string                  `json:"language"`
+	Locale                string                  `json:"locale"`
+	FileName              string                  `json:"file_name,omitempty"`
+	FilePath              string                  `json:"file_path,omitempty"`
+	SessionID             *string                 `json:"session_id,omitempty"`
+	RerunOfReviewID       *string                 `json:"rerun_of_review_id,omitempty"`     // 再レビューの元のレビュー
+	FollowUpOfReviewID    *string                 `json:"follow_up_of_review_id,omitempty"` // 改善点を適用したコードのレビューの場合、適用元のレビュー
+	InputFormat           string                  `json:"input_format"`
+	Context               string                  `json:"context,omitempty"`
+	ReviewResult          string                  `json:"review_result"`
+	StructuredResult      *StructuredReviewResult `json:"structured_result,omitempty"`
+	ResultSource          string                  `json:"result_source"`
+	PromptTemplateID      *string                 `json:"prompt_template_id"`
+	PromptTemplateVersion int                     `json:"prompt_template_version"`
+	UsedKnowledgeIDs      []string                `json:"used_knowledge_ids"`
+	LLMProvider           string                  `json:"llm_provider"`
+	LLMModel              string                  `json:"llm_model"`
+	TokensUsed            int                     `json:"tokens_used"`
+	Usage                 model.TokenUsage        `json:"usage"`
+	Cost                  float64                 `json:"cost"`
+	CostCurrency          string                  `json:"cost_currency"`
+	CacheHit              bool                    `json:"cache_hit"`
+	CachedFromReviewID    *string                 `json:"cached_from_review_id,omitempty"`
+	EnsembleID            *string                 `json:"ensemble_id,omitempty"`
+	// PromptInjectionSuspected - 入力にプロンプトインジェクションの疑いがある（結果が操作されている可能性がある）
+	PromptInjectionSuspected bool                          `json:"prompt_injection_suspected"`
+	PromptInjectionSignals   []model.PromptInjectionSignal `json:"prompt_injection_signals,omitempty"`
+	// Analysis - LLM を呼び出す前に計算した静的解析の結果（Go のコードのみ。LLM の指摘とは別に返す）
+	Analysis *model.StaticAnalysis `json:"analysis,omitempty"`
+	// Status - pending / running / completed / failed（同期的なレビューは常に completed）
+	Status       string     `json:"status"`
+	Async        bool       `json:"async"`
+	ErrorMessage string     `json:"error_message,omitempty"` // 失敗した理由（failed のみ）
+	Attempts     int        `json:"attempts"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
 }
 
 // StructuredReviewResult - 構造化されたレビュー結果（レスポンス用）
@@ -166,8 +510,17 @@ type StructuredReviewResult struct {
 type Improvement struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
+	CodeBefore  string `json:"code_before,omitempty"`
 	CodeAfter   string `json:"code_after,omitempty"`
 	Severity    string `json:"severity"`
+	// 対象の行範囲（1始まり、終了行を含む。特定できない場合は省略）。差分のレビューは変更後のファイルの行番号
+	StartLine int `json:"start_line,omitempty"`
+	EndLine   int `json:"end_line,omitempty"`
+	// 複数モデルの結果を統合したレビューのみ：指摘したモデルと、複数のモデルが一致したか
+	AgreedBy  []string `json:"agreed_by,omitempty"`
+	Consensus bool     `json:"consensus,omitempty"`
+	// 差分のレビューのみ：対象のファイル
+	FilePath string `json:"file_path,omitempty"`
 }
 
 // UpdateFeedback - PUT /api/v1/reviews/:id/feedback
@@ -226,7 +579,7 @@ func (h *ReviewHandler) UpdateFeedback(c echo.Context) error {
 	if err != nil {
 		// エラーの種類を判定
 		c.Logger().Errorf("UpdateFeedback failed: %v", err)
-		
+
 		// レビューが見つからない
 		if err.Error() == "レビューが見つかりません" {
 			return c.JSON(http.StatusNotFound, response.ErrorResponse{
@@ -234,7 +587,7 @@ func (h *ReviewHandler) UpdateFeedback(c echo.Context) error {
 				Message: "レビューが見つかりません",
 			})
 		}
-		
+
 		// 権限エラー
 		if err.Error() == "このレビューを更新する権限がありません" {
 			return c.JSON(http.StatusForbidden, response.ErrorResponse{
@@ -242,7 +595,7 @@ func (h *ReviewHandler) UpdateFeedback(c echo.Context) error {
 				Message: "このレビューを更新する権限がありません",
 			})
 		}
-		
+
 		// その他のエラー
 		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
 			Error:   "internal_error",
@@ -307,15 +660,18 @@ func (h *ReviewHandler) ListReviews(c echo.Context) error {
 
 	// 4. UseCase実行
 	input := review.ListReviewsInput{
-		UserID:    userID,
-		Page:      query.Page,
-		PageSize:  query.PageSize,
-		Language:  query.Language,
-		Status:    query.Status,
-		SortBy:    query.SortBy,
-		SortOrder: query.SortOrder,
-		DateFrom:  query.DateFrom,
-		DateTo:    query.DateTo,
+		UserID:     userID,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		Language:   query.Language,
+		Status:     query.Status,
+		SessionID:  query.SessionID,
+		RerunOf:    query.RerunOf,
+		FollowUpOf: query.FollowUpOf,
+		SortBy:     query.SortBy,
+		SortOrder:  query.SortOrder,
+		DateFrom:   query.DateFrom,
+		DateTo:     query.DateTo,
 	}
 
 	output, err := h.listReviewsUsecase.Execute(c.Request().Context(), input)
@@ -335,7 +691,13 @@ func (h *ReviewHandler) ListReviews(c echo.Context) error {
 			UserID:              rev.UserID,
 			Code:                rev.Code,
 			Language:            rev.Language,
-			Status:              "success", // TODO: statusフィールドが実装されたら修正
+			FileName:            rev.FileName(),
+			FilePath:            rev.FilePath,
+			SessionID:           rev.SessionID,
+			RerunOfReviewID:     rev.RerunOfReviewID,
+			FollowUpOfReviewID:  rev.FollowUpOfReviewID,
+			Status:              rev.Status,
+			ErrorMessage:        response.LocalizeMessage(rev.ErrorMessage, middleware.GetLocale(c)),
 			ReviewResult:        rev.ReviewResult,
 			KnowledgeReferences: rev.ReferencedKnowledge,
 			CreatedAt:           rev.CreatedAt,
@@ -369,14 +731,17 @@ func validateListReviewsQuery(query *ListReviewsQuery) error {
 
 // ListReviewsQuery - クエリパラメータ
 type ListReviewsQuery struct {
-	Page      int        `query:"page"`
-	PageSize  int        `query:"page_size"`
-	Language  string     `query:"language"`
-	Status    string     `query:"status"`
-	SortBy    string     `query:"sort_by"`
-	SortOrder string     `query:"sort_order"`
-	DateFrom  *time.Time `query:"date_from"`
-	DateTo    *time.Time `query:"date_to"`
+	Page       int        `query:"page"`
+	PageSize   int        `query:"page_size"`
+	Language   string     `query:"language"`
+	Status     string     `query:"status"`
+	SessionID  string     `query:"session_id"`   // 複数ファイルのレビューセッションのレビューのみ
+	RerunOf    string     `query:"rerun_of"`     // 指定したレビューの再レビューのみ
+	FollowUpOf string     `query:"follow_up_of"` // 指定したレビューの改善点を適用したコードのレビューのみ
+	SortBy     string     `query:"sort_by"`
+	SortOrder  string     `query:"sort_order"`
+	DateFrom   *time.Time `query:"date_from"`
+	DateTo     *time.Time `query:"date_to"`
 }
 
 // ListReviewsResponse - レスポンス
@@ -394,7 +759,13 @@ type ReviewListItem struct {
 	UserID              string    `json:"user_id"`
 	Code                string    `json:"code"`
 	Language            string    `json:"language"`
+	FileName            string    `json:"file_name,omitempty"`
+	FilePath            string    `json:"file_path,omitempty"`
+	SessionID           *string   `json:"session_id,omitempty"`
+	RerunOfReviewID     *string   `json:"rerun_of_review_id,omitempty"`
+	FollowUpOfReviewID  *string   `json:"follow_up_of_review_id,omitempty"`
 	Status              string    `json:"status"`
+	ErrorMessage        string    `json:"error_message,omitempty"`
 	ReviewResult        string    `json:"review_result"`
 	KnowledgeReferences []string  `json:"knowledge_references"`
 	CreatedAt           time.Time `json:"created_at"`
@@ -446,41 +817,9 @@ func (h *ReviewHandler) GetReviewByID(c echo.Context) error {
 		})
 	}
 
-	// 4. 構造化データを含めてレスポンス
-	var structuredResult *StructuredReviewResult
-	if output.Review.StructuredResult != nil {
-		structuredResult = &StructuredReviewResult{
-			Summary:    output.Review.StructuredResult.Summary,
-			GoodPoints: output.Review.StructuredResult.GoodPoints,
-			Improvements: func() []Improvement {
-				improvements := make([]Improvement, len(output.Review.StructuredResult.Improvements))
-				for i, imp := range output.Review.StructuredResult.Improvements {
-					improvements[i] = Improvement{
-						Title:       imp.Title,
-						Description: imp.Description,
-						CodeAfter:   imp.CodeAfter,
-						Severity:    imp.Severity,
-					}
-				}
-				return improvements
-			}(),
-		}
-	}
-
-	responseData := ReviewCodeResponse{
-		ID:               output.Review.ID,
-		UserID:           output.Review.UserID,
-		Code:             output.Review.Code,
-		Language:         output.Review.Language,
-		Context:          output.Review.Context,
-		ReviewResult:     output.Review.ReviewResult,
-		StructuredResult: structuredResult,
-		UsedKnowledgeIDs: output.Review.ReferencedKnowledge,
-		LLMProvider:      output.Review.LLMProvider,
-		LLMModel:         output.Review.LLMModel,
-		TokensUsed:       output.Review.TokensUsed,
-		CreatedAt:        output.Review.CreatedAt,
-	}
+	// 4. 構造化データを含めてレスポンス（失敗した理由はJSONシリアライザで翻訳されないため、ここでロケールの言語にする）
+	responseData := toReviewCodeResponse(output.Review)
+	responseData.ErrorMessage = response.LocalizeMessage(responseData.ErrorMessage, middleware.GetLocale(c))
 
 	// 5. ヘッダーにAPI Codeを追加
 	c.Response().Header().Set("X-API-Code", "RV-003")
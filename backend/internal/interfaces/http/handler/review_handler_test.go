@@ -2,10 +2,13 @@ package handler_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,6 +21,7 @@ import (
 	"github.com/s7r8/reviewapp/internal/interfaces/http/middleware"
 	"github.com/s7r8/reviewapp/test/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestReviewHandler_ReviewCode(t *testing.T) {
@@ -87,6 +91,17 @@ func TestReviewHandler_ReviewCode(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedError:  "internal_error",
 		},
+		{
+			name: "LLMのサーキットが開いている",
+			requestBody: map[string]string{
+				"code":     "func main() {}",
+				"language": "go",
+			},
+			setUserID:      true,
+			claudeError:    fmt.Errorf("failed to call Claude API: %w", external.ErrCircuitOpen),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedError:  "llm_api_error",
+		},
 	}
 
 	for _, tt := range tests {
@@ -109,6 +124,13 @@ func TestReviewHandler_ReviewCode(t *testing.T) {
 				reviewService,
 				mockClaudeClient,
 				mockEmbeddingClient,
+				testutil.NewMockPromptTemplateRepository(),
+				service.NewTemplatePromptRenderer(),
+				service.NewPriceTable(service.DefaultPriceCurrency, service.DefaultModelPrices()),
+				nil,
+				review.ChunkingOptions{},
+				review.CacheOptions{},
+				review.PreferenceOptions{},
 			)
 
 			feedbackUseCase := review.NewUpdateFeedbackUseCase(
@@ -124,7 +146,7 @@ func TestReviewHandler_ReviewCode(t *testing.T) {
 			)
 
 			// ハンドラーを初期化
-			h := handler.NewReviewHandler(reviewUseCase, feedbackUseCase, listReviewsUseCase, getReviewUseCase)
+			h := handler.NewReviewHandler(reviewUseCase, feedbackUseCase, listReviewsUseCase, getReviewUseCase, nil)
 
 			// リクエストを準備
 			var reqBody []byte
@@ -206,9 +228,16 @@ func TestReviewHandler_ReviewCode_WithStructuredResult(t *testing.T) {
 		reviewService,
 		mockClaudeClient,
 		mockEmbeddingClient,
+		testutil.NewMockPromptTemplateRepository(),
+		service.NewTemplatePromptRenderer(),
+		service.NewPriceTable(service.DefaultPriceCurrency, service.DefaultModelPrices()),
+		nil,
+		review.ChunkingOptions{},
+		review.CacheOptions{},
+		review.PreferenceOptions{},
 	)
 
-	h := handler.NewReviewHandler(reviewUseCase, nil, nil, nil)
+	h := handler.NewReviewHandler(reviewUseCase, nil, nil, nil, nil)
 
 	reqBody, _ := json.Marshal(map[string]string{
 		"code":     "func test() {}",
@@ -325,7 +354,7 @@ func TestReviewHandler_UpdateFeedback(t *testing.T) {
 			}
 
 			feedbackUseCase := review.NewUpdateFeedbackUseCase(mockReviewRepo)
-			h := handler.NewReviewHandler(nil, feedbackUseCase, nil, nil)
+			h := handler.NewReviewHandler(nil, feedbackUseCase, nil, nil, nil)
 
 			var reqBody []byte
 			var err error
@@ -453,7 +482,7 @@ func TestReviewHandler_ListReviews(t *testing.T) {
 			}
 
 			listReviewsUseCase := review.NewListReviewsUseCase(mockReviewRepo)
-			h := handler.NewReviewHandler(nil, nil, listReviewsUseCase, nil)
+			h := handler.NewReviewHandler(nil, nil, listReviewsUseCase, nil, nil)
 
 			req := httptest.NewRequest(http.MethodGet, "/api/v1/reviews", nil)
 			q := req.URL.Query()
@@ -563,7 +592,7 @@ func TestReviewHandler_GetReviewByID(t *testing.T) {
 			}
 
 			getReviewUseCase := review.NewGetReviewUseCase(mockReviewRepo)
-			h := handler.NewReviewHandler(nil, nil, nil, getReviewUseCase)
+			h := handler.NewReviewHandler(nil, nil, nil, getReviewUseCase, nil)
 
 			req := httptest.NewRequest(http.MethodGet, "/api/v1/reviews/"+tt.reviewID, nil)
 			rec := httptest.NewRecorder()
@@ -593,3 +622,610 @@ func TestReviewHandler_GetReviewByID(t *testing.T) {
 		})
 	}
 }
+
+func TestReviewHandler_ReviewCodeStream(t *testing.T) {
+	newHandler := func(mockReviewRepo *testutil.MockReviewRepository, mockClaudeClient *testutil.MockClaudeClient) *handler.ReviewHandler {
+		reviewUseCase := review.NewReviewCodeUseCase(
+			mockReviewRepo,
+			testutil.NewMockKnowledgeRepository(),
+			service.NewReviewService(),
+			mockClaudeClient,
+			testutil.NewMockEmbeddingClient(),
+			testutil.NewMockPromptTemplateRepository(),
+			service.NewTemplatePromptRenderer(),
+			service.NewPriceTable(service.DefaultPriceCurrency, service.DefaultModelPrices()),
+			nil,
+			review.ChunkingOptions{},
+			review.CacheOptions{},
+			review.PreferenceOptions{},
+		)
+		return handler.NewReviewHandler(reviewUseCase, nil, nil, nil, nil)
+	}
+
+	newRequest := func() *http.Request {
+		reqBody, _ := json.Marshal(map[string]string{
+			"code":     "func test() {}",
+			"language": "go",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews/stream", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	t.Run("トークンと保存済みレビューをSSEで返す", func(t *testing.T) {
+		mockReviewRepo := testutil.NewMockReviewRepository()
+		h := newHandler(mockReviewRepo, testutil.NewMockClaudeClient())
+
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(newRequest(), rec)
+		middleware.SetUserID(c, "test-user-id")
+
+		err := h.ReviewCodeStream(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "RV-005", rec.Header().Get("X-API-Code"))
+
+		body := rec.Body.String()
+		assert.Contains(t, body, "event: token\ndata: {\"text\":\"Mock review result\"}\n\n")
+		assert.Contains(t, body, "event: review\n")
+		assert.Less(t, strings.Index(body, "event: token"), strings.Index(body, "event: review"))
+	})
+
+	t.Run("LLMエラー時はerrorイベントを返す", func(t *testing.T) {
+		mockClaudeClient := testutil.NewMockClaudeClient()
+		mockClaudeClient.SetError(errors.New("API error"))
+		h := newHandler(testutil.NewMockReviewRepository(), mockClaudeClient)
+
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(newRequest(), rec)
+		middleware.SetUserID(c, "test-user-id")
+
+		err := h.ReviewCodeStream(c)
+		assert.NoError(t, err)
+		assert.Contains(t, rec.Body.String(), "event: error\n")
+		assert.NotContains(t, rec.Body.String(), "event: review")
+	})
+
+	t.Run("クライアントが切断してもレビューは保存される", func(t *testing.T) {
+		mockReviewRepo := testutil.NewMockReviewRepository()
+		h := newHandler(mockReviewRepo, testutil.NewMockClaudeClient())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(newRequest().WithContext(ctx), rec)
+		middleware.SetUserID(c, "test-user-id")
+
+		err := h.ReviewCodeStream(c)
+		assert.NoError(t, err)
+		assert.NotContains(t, rec.Body.String(), "event: review")
+
+		saved, err := mockReviewRepo.FindByUserID(context.Background(), "test-user-id", 10)
+		assert.NoError(t, err)
+		assert.Len(t, saved, 1)
+	})
+
+	t.Run("codeが空の場合", func(t *testing.T) {
+		h := newHandler(testutil.NewMockReviewRepository(), testutil.NewMockClaudeClient())
+
+		reqBody, _ := json.Marshal(map[string]string{"code": "", "language": "go"})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews/stream", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		middleware.SetUserID(c, "test-user-id")
+
+		err := h.ReviewCodeStream(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestReviewHandler_ReviewCode_QuotaExceeded(t *testing.T) {
+	counter := testutil.NewMockQuotaCounter()
+	counter.SetUsage("test-user-id", model.QuotaUsage{ReviewsToday: 5})
+
+	reviewUseCase := review.NewReviewCodeUseCase(
+		testutil.NewMockReviewRepository(),
+		testutil.NewMockKnowledgeRepository(),
+		service.NewReviewService(),
+		testutil.NewMockClaudeClient(),
+		testutil.NewMockEmbeddingClient(),
+		testutil.NewMockPromptTemplateRepository(),
+		service.NewTemplatePromptRenderer(),
+		service.NewPriceTable(service.DefaultPriceCurrency, service.DefaultModelPrices()),
+		service.NewQuotaService(testutil.NewMockQuotaRepository(), counter, model.QuotaPolicy{ReviewsPerDay: 5}),
+		review.ChunkingOptions{},
+		review.CacheOptions{},
+		review.PreferenceOptions{},
+	)
+	h := handler.NewReviewHandler(reviewUseCase, nil, nil, nil, nil)
+
+	for _, tt := range []struct {
+		name string
+		call func(h *handler.ReviewHandler, c echo.Context) error
+	}{
+		{name: "RV-001", call: (*handler.ReviewHandler).ReviewCode},
+		{name: "RV-005 はSSEを開始する前に429を返す", call: (*handler.ReviewHandler).ReviewCodeStream},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			reqBody, _ := json.Marshal(map[string]string{"code": "func test() {}", "language": "go"})
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+			middleware.SetUserID(c, "test-user-id")
+
+			err := tt.call(h, c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+			assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+			var resp struct {
+				Error string `json:"error"`
+				Quota struct {
+					Kind   string `json:"kind"`
+					Limit  int    `json:"limit"`
+					Status struct {
+						RemainingReviewsToday *int `json:"remaining_reviews_today"`
+					} `json:"status"`
+				} `json:"quota"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, "quota_exceeded", resp.Error)
+			assert.Equal(t, model.QuotaKindReviewsPerDay, resp.Quota.Kind)
+			assert.Equal(t, 5, resp.Quota.Limit)
+			require.NotNil(t, resp.Quota.Status.RemainingReviewsToday)
+			assert.Equal(t, 0, *resp.Quota.Status.RemainingReviewsToday)
+		})
+	}
+}
+
+func TestReviewHandler_ReviewCode_Locale(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		requestBody    map[string]string
+		expectedStatus int
+		expectedLocale string
+		expectedMsg    string
+	}{
+		{
+			name:           "Accept-Language が英語の場合はエラーメッセージを英語で返す",
+			acceptLanguage: "en-US,en;q=0.9,ja;q=0.8",
+			requestBody:    map[string]string{"code": "", "language": "go"},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "Code is required",
+		},
+		{
+			name:           "指定がない場合は日本語",
+			requestBody:    map[string]string{"code": "", "language": "go"},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "コードは必須です",
+		},
+		{
+			name:           "未対応のロケール",
+			requestBody:    map[string]string{"code": "func main() {}", "language": "go", "locale": "fr"},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "locale は ja または en を指定してください",
+		},
+		{
+			name:           "リクエストの locale は Accept-Language より優先する",
+			acceptLanguage: "ja",
+			requestBody:    map[string]string{"code": "func main() {}", "language": "go", "locale": "en"},
+			expectedStatus: http.StatusCreated,
+			expectedLocale: model.LocaleEnglish,
+		},
+		{
+			name:           "Accept-Language のロケールでレビューする",
+			acceptLanguage: "en",
+			requestBody:    map[string]string{"code": "func main() {}", "language": "go"},
+			expectedStatus: http.StatusCreated,
+			expectedLocale: model.LocaleEnglish,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClaudeClient := testutil.NewMockClaudeClient()
+			reviewUseCase := review.NewReviewCodeUseCase(
+				testutil.NewMockReviewRepository(),
+				testutil.NewMockKnowledgeRepository(),
+				service.NewReviewService(),
+				mockClaudeClient,
+				testutil.NewMockEmbeddingClient(),
+				testutil.NewMockPromptTemplateRepository(),
+				service.NewTemplatePromptRenderer(),
+				service.NewPriceTable(service.DefaultPriceCurrency, service.DefaultModelPrices()),
+				nil,
+				review.ChunkingOptions{},
+				review.CacheOptions{},
+				review.PreferenceOptions{},
+			)
+			h := handler.NewReviewHandler(reviewUseCase, nil, nil, nil, nil)
+
+			e := echo.New()
+			e.JSONSerializer = middleware.LocalizedJSONSerializer{}
+			reqBody, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			middleware.SetUserID(c, "test-user-id")
+
+			err := middleware.Locale(h.ReviewCode)(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			var resp map[string]interface{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			if tt.expectedMsg != "" {
+				assert.Equal(t, tt.expectedMsg, resp["message"])
+				return
+			}
+			assert.Equal(t, tt.expectedLocale, resp["locale"])
+			require.Len(t, mockClaudeClient.Inputs(), 1)
+			assert.Equal(t, tt.expectedLocale, mockClaudeClient.Inputs()[0].Locale)
+		})
+	}
+}
+
+func TestReviewHandler_ReviewCode_Async(t *testing.T) {
+	mockReviewRepo := testutil.NewMockReviewRepository()
+	mockClaudeClient := testutil.NewMockClaudeClient()
+	mockClaudeClient.SetError(errors.New("API error"))
+	reviewUseCase := review.NewReviewCodeUseCase(
+		mockReviewRepo,
+		testutil.NewMockKnowledgeRepository(),
+		service.NewReviewService(),
+		mockClaudeClient,
+		testutil.NewMockEmbeddingClient(),
+		testutil.NewMockPromptTemplateRepository(),
+		service.NewTemplatePromptRenderer(),
+		service.NewPriceTable(service.DefaultPriceCurrency, service.DefaultModelPrices()),
+		nil,
+		review.ChunkingOptions{},
+		review.CacheOptions{},
+		review.PreferenceOptions{},
+	)
+	jobUseCase := review.NewReviewJobUseCase(reviewUseCase, review.ReviewJobUseCaseOptions{PollInterval: 10 * time.Millisecond})
+	h := handler.NewReviewHandler(reviewUseCase, nil, nil, nil, jobUseCase)
+
+	// 1. 実行待ちで受け付けて 202 を返す（ワーカーの起動前はLLMを呼び出さない）
+	reqBody, _ := json.Marshal(map[string]string{"code": "func test() {}", "language": "go"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews?async=true", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	middleware.SetUserID(c, "test-user-id")
+
+	require.NoError(t, h.ReviewCode(c))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	var submitted handler.ReviewCodeResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &submitted))
+	assert.Equal(t, model.ReviewStatusPending, submitted.Status)
+	assert.True(t, submitted.Async)
+	assert.Equal(t, "/api/v1/reviews/"+submitted.ID, rec.Header().Get("Location"))
+	assert.Empty(t, mockClaudeClient.Inputs())
+
+	retry := func(userID string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/api/v1/reviews/"+submitted.ID+"/retry", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(submitted.ID)
+		middleware.SetUserID(c, userID)
+		require.NoError(t, h.RetryReview(c))
+		return rec
+	}
+
+	// 2. 失敗していないレビューは再実行できない
+	assert.Equal(t, http.StatusConflict, retry("test-user-id").Code)
+
+	// 3. 失敗したレビューは再実行できる（他のユーザーのレビューは見つからない扱い）
+	jobUseCase.Start()
+	require.Eventually(t, func() bool {
+		r, err := mockReviewRepo.FindByID(context.Background(), submitted.ID)
+		require.NoError(t, err)
+		return r.Status == model.ReviewStatusFailed
+	}, 2*time.Second, 5*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, jobUseCase.Shutdown(ctx))
+
+	assert.Equal(t, http.StatusNotFound, retry("other-user-id").Code)
+	rec = retry("test-user-id")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, "RV-015", rec.Header().Get("X-API-Code"))
+	var retried handler.ReviewCodeResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &retried))
+	assert.Equal(t, model.ReviewStatusPending, retried.Status)
+	assert.Equal(t, 1, retried.Attempts)
+}
+
+func TestReviewRerunHandler(t *testing.T) {
+	mockReviewRepo := testutil.NewMockReviewRepository()
+	mockClaudeClient := testutil.NewMockClaudeClient()
+	mockClaudeClient.SetResponse(&external.ReviewCodeOutput{
+		ReviewResult: "new result",
+		Structured: &model.StructuredReviewResult{
+			Summary:      "new",
+			Improvements: []model.Improvement{{Title: "エラーを握りつぶしている", Description: "返す", Severity: "high"}},
+		},
+		TokensUsed: 100,
+	})
+	reviewUseCase := review.NewReviewCodeUseCase(
+		mockReviewRepo,
+		testutil.NewMockKnowledgeRepository(),
+		service.NewReviewService(),
+		mockClaudeClient,
+		testutil.NewMockEmbeddingClient(),
+		testutil.NewMockPromptTemplateRepository(),
+		service.NewTemplatePromptRenderer(),
+		service.NewPriceTable(service.DefaultPriceCurrency, service.DefaultModelPrices()),
+		nil,
+		review.ChunkingOptions{},
+		review.CacheOptions{},
+		review.PreferenceOptions{},
+	)
+	jobUseCase := review.NewReviewJobUseCase(reviewUseCase, review.ReviewJobUseCaseOptions{})
+	h := handler.NewReviewRerunHandler(
+		review.NewRerunReviewUseCase(reviewUseCase, jobUseCase),
+		review.NewDiffReviewsUseCase(mockReviewRepo),
+	)
+
+	original := model.NewReview("test-user-id", "func test() {}", "go", "")
+	original.SetReviewResult("old result", &model.StructuredReviewResult{
+		Summary: "old",
+		Improvements: []model.Improvement{
+			{Title: "エラーを握りつぶしている", Description: "返す", Severity: "medium"},
+			{Title: "マジックナンバー", Description: "定数にする", Severity: "low"},
+		},
+	}, []string{}, "mock", "mock-model", 100)
+	require.NoError(t, mockReviewRepo.Create(context.Background(), original))
+
+	rerun := func(userID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews/"+original.ID+"/rerun", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(original.ID)
+		middleware.SetUserID(c, userID)
+		require.NoError(t, h.RerunReview(c))
+		return rec
+	}
+
+	// 1. 同じコードでレビューし直し、元のレビューを記録する
+	rec := rerun("test-user-id", `{}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "RV-016", rec.Header().Get("X-API-Code"))
+	var rerunResp handler.ReviewCodeResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rerunResp))
+	require.NotNil(t, rerunResp.RerunOfReviewID)
+	assert.Equal(t, original.ID, *rerunResp.RerunOfReviewID)
+	assert.Equal(t, "func test() {}", mockClaudeClient.LastInput().Code)
+
+	// 2. 他のユーザーのレビューは見つからない扱い・上書きする設定が不正な場合は 400
+	assert.Equal(t, http.StatusNotFound, rerun("other-user-id", `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, rerun("test-user-id", `{"overrides":{"temperature":2}}`).Code)
+
+	// 3. 元のレビューと再レビューの改善点を比較する
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/reviews/"+original.ID+"/diff/"+rerunResp.ID, nil)
+	rec = httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id", "otherId")
+	c.SetParamValues(original.ID, rerunResp.ID)
+	middleware.SetUserID(c, "test-user-id")
+	require.NoError(t, h.DiffReviews(c))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "RV-017", rec.Header().Get("X-API-Code"))
+	var diffResp handler.ReviewDiffResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &diffResp))
+	assert.Equal(t, original.ID, diffResp.Base.ID)
+	assert.Equal(t, rerunResp.ID, diffResp.Other.ID)
+	assert.Empty(t, diffResp.Improvements.Added)
+	require.Len(t, diffResp.Improvements.Removed, 1)
+	assert.Equal(t, "マジックナンバー", diffResp.Improvements.Removed[0].Title)
+	require.Len(t, diffResp.Improvements.Persisting, 1)
+	assert.True(t, diffResp.Improvements.Persisting[0].SeverityChanged)
+}
+
+func TestReviewTrashHandler(t *testing.T) {
+	mockReviewRepo := testutil.NewMockReviewRepository()
+	h := handler.NewReviewTrashHandler(review.NewReviewTrashUseCase(mockReviewRepo, nil, review.TrashOptions{Retention: 24 * time.Hour}))
+
+	target := model.NewReview("test-user-id", "const secret = \"xxx\"", "Go", "")
+	require.NoError(t, mockReviewRepo.Create(context.Background(), target))
+	other := model.NewReview("test-user-id", "func main() {}", "Python", "")
+	require.NoError(t, mockReviewRepo.Create(context.Background(), other))
+
+	call := func(method, target, id, userID string, fn func(echo.Context) error) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		if id != "" {
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+		}
+		middleware.SetUserID(c, userID)
+		require.NoError(t, fn(c))
+		return rec
+	}
+
+	// 1. 他のユーザーのレビューは削除できない・存在しないレビューは 404
+	assert.Equal(t, http.StatusForbidden, call(http.MethodDelete, "/api/v1/reviews/"+target.ID, target.ID, "other-user-id", h.DeleteReview).Code)
+	assert.Equal(t, http.StatusNotFound, call(http.MethodDelete, "/api/v1/reviews/missing", "missing", "test-user-id", h.DeleteReview).Code)
+
+	// 2. 自分のレビューを削除するとゴミ箱に移動する
+	rec := call(http.MethodDelete, "/api/v1/reviews/"+target.ID, target.ID, "test-user-id", h.DeleteReview)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "RV-018", rec.Header().Get("X-API-Code"))
+
+	rec = call(http.MethodGet, "/api/v1/reviews/trash", "", "test-user-id", h.ListTrash)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "RV-020", rec.Header().Get("X-API-Code"))
+	var trashResp handler.ListTrashResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &trashResp))
+	require.Len(t, trashResp.Items, 1)
+	assert.Equal(t, target.ID, trashResp.Items[0].ID)
+	assert.Equal(t, trashResp.Items[0].DeletedAt.Add(24*time.Hour), trashResp.Items[0].PurgeAt)
+
+	// 3. 復元すると元のレビューを返す
+	rec = call(http.MethodPost, "/api/v1/reviews/"+target.ID+"/restore", target.ID, "test-user-id", h.RestoreReview)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "RV-021", rec.Header().Get("X-API-Code"))
+	var restored handler.ReviewCodeResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &restored))
+	assert.Equal(t, target.ID, restored.ID)
+
+	// 4. 一括削除は条件が必須
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodDelete, "/api/v1/reviews", "", "test-user-id", h.BulkDeleteReviews).Code)
+	rec = call(http.MethodDelete, "/api/v1/reviews?language=Go", "", "test-user-id", h.BulkDeleteReviews)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "RV-019", rec.Header().Get("X-API-Code"))
+	assert.JSONEq(t, `{"deleted_count":1}`, rec.Body.String())
+}
+
+func TestReviewApplyHandler(t *testing.T) {
+	mockReviewRepo := testutil.NewMockReviewRepository()
+	mockClaudeClient := testutil.NewMockClaudeClient()
+	reviewUseCase := review.NewReviewCodeUseCase(
+		mockReviewRepo,
+		testutil.NewMockKnowledgeRepository(),
+		service.NewReviewService(),
+		mockClaudeClient,
+		testutil.NewMockEmbeddingClient(),
+		testutil.NewMockPromptTemplateRepository(),
+		service.NewTemplatePromptRenderer(),
+		service.NewPriceTable(service.DefaultPriceCurrency, service.DefaultModelPrices()),
+		nil,
+		review.ChunkingOptions{},
+		review.CacheOptions{},
+		review.PreferenceOptions{},
+	)
+	h := handler.NewReviewApplyHandler(
+		review.NewApplyImprovementsUseCase(reviewUseCase, review.NewReviewJobUseCase(reviewUseCase, review.ReviewJobUseCaseOptions{})),
+	)
+
+	original := model.NewReview("test-user-id", "func test() {\n\tx, _ := run()\n\tuse(x)\n}\n", "go", "")
+	original.SetReviewResult("result", &model.StructuredReviewResult{
+		Summary: "summary",
+		Improvements: []model.Improvement{
+			{Title: "エラーを握りつぶしている", StartLine: 2, EndLine: 2, CodeBefore: "x, _ := run()", CodeAfter: "x, err := run()\nif err != nil {\n\treturn\n}"},
+			{Title: "修正後のコードがない", StartLine: 3, EndLine: 3},
+		},
+	}, []string{}, "mock", "mock-model", 100)
+	require.NoError(t, mockReviewRepo.Create(context.Background(), original))
+
+	apply := func(userID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews/"+original.ID+"/apply", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(original.ID)
+		middleware.SetUserID(c, userID)
+		require.NoError(t, h.ApplyImprovements(c))
+		return rec
+	}
+
+	// 1. 適用後のコードと unified diff を返す
+	rec := apply("test-user-id", `{"improvements":[0]}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "RV-022", rec.Header().Get("X-API-Code"))
+	var resp handler.ApplyImprovementsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, []int{0}, resp.Applied)
+	assert.Empty(t, resp.NotApplied)
+	assert.True(t, resp.Formatted)
+	assert.Equal(t, "func test() {\n\tx, err := run()\n\tif err != nil {\n\t\treturn\n\t}\n\tuse(x)\n}\n", resp.Code)
+	assert.Contains(t, resp.Diff, "+++ b/code\n")
+	assert.Nil(t, resp.FollowUpReview)
+
+	// 2. 保存する場合は適用後のコードのレビューを返す
+	rec = apply("test-user-id", `{"improvements":[0],"save":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.NotNil(t, resp.FollowUpReview)
+	require.NotNil(t, resp.FollowUpReview.FollowUpOfReviewID)
+	assert.Equal(t, original.ID, *resp.FollowUpReview.FollowUpOfReviewID)
+	assert.Equal(t, resp.Code, mockClaudeClient.LastInput().Code)
+
+	// 3. 適用できない改善点は適用せずに理由を返す
+	rec = apply("test-user-id", `{"improvements":[0,1]}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	resp = handler.ApplyImprovementsResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, []int{0}, resp.Applied)
+	assert.Equal(t, []handler.NotAppliedResponse{{Index: 1, Reason: model.ErrImprovementNoCodeAfter.Error()}}, resp.NotApplied)
+
+	// 4. 他のユーザーのレビューは見つからない扱い・すべて適用できない改善点は 400
+	assert.Equal(t, http.StatusNotFound, apply("other-user-id", `{"improvements":[0]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, apply("test-user-id", `{"improvements":[1]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, apply("test-user-id", `{}`).Code)
+}
+
+func TestReviewHandler_ReviewCode_StaticAnalysis(t *testing.T) {
+	reviewUseCase := review.NewReviewCodeUseCase(
+		testutil.NewMockReviewRepository(),
+		testutil.NewMockKnowledgeRepository(),
+		service.NewReviewService(),
+		testutil.NewMockClaudeClient(),
+		testutil.NewMockEmbeddingClient(),
+		testutil.NewMockPromptTemplateRepository(),
+		service.NewTemplatePromptRenderer(),
+		service.NewPriceTable(service.DefaultPriceCurrency, service.DefaultModelPrices()),
+		nil,
+		review.ChunkingOptions{},
+		review.CacheOptions{},
+		review.PreferenceOptions{},
+	)
+	h := handler.NewReviewHandler(reviewUseCase, nil, nil, nil, nil)
+
+	tests := []struct {
+		name         string
+		code         string
+		language     string
+		wantAnalysis bool
+	}{
+		{name: "Go のコードは analysis を返す", code: "func save(f *os.File) {\n\tf.Close()\n}", language: "go", wantAnalysis: true},
+		{name: "Go 以外のコードは analysis を返さない", code: "def save(f):\n    f.close()", language: "python"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqBody, _ := json.Marshal(map[string]string{"code": tt.code, "language": tt.language})
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/review", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+			middleware.SetUserID(c, "test-user-id")
+
+			require.NoError(t, h.ReviewCode(c))
+			assert.Equal(t, http.StatusCreated, rec.Code)
+
+			var resp map[string]interface{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			if !tt.wantAnalysis {
+				assert.NotContains(t, resp, "analysis")
+				return
+			}
+			var body handler.ReviewCodeResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			require.NotNil(t, body.Analysis)
+			require.Len(t, body.Analysis.Findings, 1)
+			assert.Equal(t, model.AnalysisRuleIgnoredError, body.Analysis.Findings[0].Rule)
+			assert.Equal(t, "f.Close", body.Analysis.Findings[0].Call)
+			require.Len(t, body.Analysis.Functions, 1)
+			assert.Equal(t, "save", body.Analysis.Functions[0].Name)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/middleware"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/response"
+)
+
+// ReviewRerunHandler - レビューの再実行と、レビュー結果の比較のハンドラー
+type ReviewRerunHandler struct {
+	rerunUsecase *review.RerunReviewUseCase
+	diffUsecase  *review.DiffReviewsUseCase
+}
+
+// NewReviewRerunHandler - コンストラクタ
+func NewReviewRerunHandler(
+	rerunUsecase *review.RerunReviewUseCase,
+	diffUsecase *review.DiffReviewsUseCase,
+) *ReviewRerunHandler {
+	return &ReviewRerunHandler{
+		rerunUsecase: rerunUsecase,
+		diffUsecase:  diffUsecase,
+	}
+}
+
+// RerunReviewRequest - リクエスト（ボディなしは元のレビューと同じ入力・ユーザー設定でレビューし直す）
+type RerunReviewRequest struct {
+	Context   string                 `json:"context"`   // 省略時は元のレビューの説明
+	Locale    string                 `json:"locale"`    // ja / en（省略時は元のレビューの言語）
+	Overrides *model.UserPreferences `json:"overrides"` // ユーザー設定の代わりに使う設定（US-003 と同じ項目）
+}
+
+// ReviewDiffResponse - 2つのレビューの比較
+type ReviewDiffResponse struct {
+	Base             ReviewDiffSide          `json:"base"`
+	Other            ReviewDiffSide          `json:"other"`
+	KnowledgeAdded   []string                `json:"knowledge_added"`
+	KnowledgeRemoved []string                `json:"knowledge_removed"`
+	Improvements     *model.ReviewResultDiff `json:"improvements"`
+}
+
+// ReviewDiffSide - 比較したレビューの、結果に影響する条件
+type ReviewDiffSide struct {
+	ID                    string    `json:"id"`
+	RerunOfReviewID       *string   `json:"rerun_of_review_id,omitempty"`
+	LLMProvider           string    `json:"llm_provider"`
+	LLMModel              string    `json:"llm_model"`
+	PromptTemplateID      *string   `json:"prompt_template_id"`
+	PromptTemplateVersion int       `json:"prompt_template_version"`
+	UsedKnowledgeIDs      []string  `json:"used_knowledge_ids"`
+	Summary               string    `json:"summary"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+// RerunReview - POST /api/v1/reviews/:id/rerun
+// ?async=true の場合は再レビューを実行待ちで受け付けて 202 を返す
+func (h *ReviewRerunHandler) RerunReview(c echo.Context) error {
+	// 1. リクエストボディを取得
+	var req RerunReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request body",
+		})
+	}
+
+	// 2. ロケールのバリデーション（設定のバリデーションはUseCaseで行う）
+	middleware.SetLocale(c, req.Locale)
+	if req.Locale != "" && model.NormalizeLocale(req.Locale) == "" {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: model.ErrLocaleInvalid.Error(),
+		})
+	}
+
+	// 3. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 4. UseCase実行（他のユーザーのレビューは見つからない扱い）
+	async := c.QueryParam("async") == "true"
+	rerun, err := h.rerunUsecase.Execute(c.Request().Context(), review.RerunReviewInput{
+		ReviewID:  c.Param("id"),
+		UserID:    userID,
+		Context:   req.Context,
+		Locale:    model.NormalizeLocale(req.Locale),
+		Overrides: req.Overrides,
+		Async:     async,
+	})
+	if err != nil {
+		var quotaErr *model.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceeded(c, quotaErr)
+		}
+		if status, errResp, ok := rerunErrorResponse(err); ok {
+			return c.JSON(status, errResp)
+		}
+		c.Logger().Errorf("RerunReview failed: %v", err)
+		status, errResp := reviewErrorResponse(err)
+		return c.JSON(status, errResp)
+	}
+
+	// 5. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "RV-016")
+	if async {
+		c.Response().Header().Set(echo.HeaderLocation, "/api/v1/reviews/"+rerun.ID)
+		return c.JSON(http.StatusAccepted, toReviewCodeResponse(rerun))
+	}
+	return c.JSON(http.StatusCreated, toReviewCodeResponse(rerun))
+}
+
+// DiffReviews - GET /api/v1/reviews/:id/diff/:otherId
+func (h *ReviewRerunHandler) DiffReviews(c echo.Context) error {
+	// 1. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 2. UseCase実行（他のユーザーのレビューは見つからない扱い）
+	output, err := h.diffUsecase.Execute(c.Request().Context(), review.DiffReviewsInput{
+		ReviewID:      c.Param("id"),
+		OtherReviewID: c.Param("otherId"),
+		UserID:        userID,
+	})
+	if err != nil {
+		if status, errResp, ok := rerunErrorResponse(err); ok {
+			return c.JSON(status, errResp)
+		}
+		c.Logger().Errorf("DiffReviews failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "internal_error",
+			Message: "サーバーエラーが発生しました",
+		})
+	}
+
+	// 3. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "RV-017")
+	return c.JSON(http.StatusOK, ReviewDiffResponse{
+		Base:             toReviewDiffSide(output.Base),
+		Other:            toReviewDiffSide(output.Other),
+		KnowledgeAdded:   output.KnowledgeAdded,
+		KnowledgeRemoved: output.KnowledgeRemoved,
+		Improvements:     output.Improvements,
+	})
+}
+
+// toReviewDiffSide - 比較したレビューをレスポンス形式に変換
+func toReviewDiffSide(rev *model.Review) ReviewDiffSide {
+	side := ReviewDiffSide{
+		ID:                    rev.ID,
+		RerunOfReviewID:       rev.RerunOfReviewID,
+		LLMProvider:           rev.LLMProvider,
+		LLMModel:              rev.LLMModel,
+		PromptTemplateID:      rev.PromptTemplateID,
+		PromptTemplateVersion: rev.PromptTemplateVersion,
+		UsedKnowledgeIDs:      rev.ReferencedKnowledge,
+		CreatedAt:             rev.CreatedAt,
+	}
+	if side.UsedKnowledgeIDs == nil {
+		side.UsedKnowledgeIDs = []string{}
+	}
+	if rev.StructuredResult != nil {
+		side.Summary = rev.StructuredResult.Summary
+	}
+	return side
+}
+
+// rerunErrorResponse - 設定の不正・レビューが見つからない・比較できないレビューのエラーのレスポンス
+func rerunErrorResponse(err error) (int, response.ErrorResponse, bool) {
+	switch {
+	case errors.Is(err, model.ErrPreferenceTemperatureInvalid),
+		errors.Is(err, model.ErrPreferenceMaxTokensInvalid),
+		errors.Is(err, model.ErrPreferenceOutputLanguageInvalid),
+		errors.Is(err, model.ErrPreferenceReviewFocusInvalid),
+		errors.Is(err, model.ErrPreferenceProviderUnavailable):
+		return http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		}, true
+	case errors.Is(err, model.ErrReviewNotFound):
+		return http.StatusNotFound, response.ErrorResponse{
+			Error:   "not_found",
+			Message: model.ErrReviewNotFound.Error(),
+		}, true
+	case errors.Is(err, model.ErrReviewNotCompleted):
+		return http.StatusConflict, response.ErrorResponse{
+			Error:   "conflict",
+			Message: model.ErrReviewNotCompleted.Error(),
+		}, true
+	case errors.Is(err, model.ErrReviewNoStructuredResult):
+		return http.StatusConflict, response.ErrorResponse{
+			Error:   "conflict",
+			Message: model.ErrReviewNoStructuredResult.Error(),
+		}, true
+	}
+	return 0, response.ErrorResponse{}, false
+}
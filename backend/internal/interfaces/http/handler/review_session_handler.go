@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/middleware"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/response"
+)
+
+// ReviewSessionHandler - 複数ファイルのレビューセッションのハンドラー
+type ReviewSessionHandler struct {
+	reviewSessionUsecase      *review.ReviewSessionUseCase
+	getReviewSessionUsecase   *review.GetReviewSessionUseCase
+	listReviewSessionsUsecase *review.ListReviewSessionsUseCase
+}
+
+// NewReviewSessionHandler - コンストラクタ
+func NewReviewSessionHandler(
+	reviewSessionUsecase *review.ReviewSessionUseCase,
+	getReviewSessionUsecase *review.GetReviewSessionUseCase,
+	listReviewSessionsUsecase *review.ListReviewSessionsUseCase,
+) *ReviewSessionHandler {
+	return &ReviewSessionHandler{
+		reviewSessionUsecase:      reviewSessionUsecase,
+		getReviewSessionUsecase:   getReviewSessionUsecase,
+		listReviewSessionsUsecase: listReviewSessionsUsecase,
+	}
+}
+
+// ReviewSessionRequest - リクエスト
+type ReviewSessionRequest struct {
+	Title        string                     `json:"title"` // 省略時はファイルのパスから作る
+	Files        []ReviewSessionFileRequest `json:"files"`
+	Language     string                     `json:"language"` // ファイルごとの指定も拡張子からの推定もできない場合の言語
+	Context      string                     `json:"context"`  // 全ファイル共通のコンテキスト
+	ForceRefresh bool                       `json:"force_refresh"`
+	Locale       string                     `json:"locale"` // ja / en（省略時はユーザー設定の出力言語、Accept-Language の順）
+}
+
+// ReviewSessionFileRequest - レビューするファイル
+type ReviewSessionFileRequest struct {
+	Path     string `json:"path"`
+	Code     string `json:"code"`
+	Language string `json:"language"` // 省略時は拡張子から推定
+}
+
+// ReviewSessionResponse - セッションとファイルごとのレビュー
+type ReviewSessionResponse struct {
+	ReviewSessionSummaryResponse
+	Reviews  []ReviewCodeResponse           `json:"reviews"`
+	Failures []ReviewSessionFailureResponse `json:"failures"`
+}
+
+// ReviewSessionSummaryResponse - セッションの総合評価と改善点の数（履歴一覧の項目）
+type ReviewSessionSummaryResponse struct {
+	ID                string                  `json:"id"`
+	Title             string                  `json:"title"`
+	Locale            string                  `json:"locale"`
+	Summary           string                  `json:"summary"`
+	FileCount         int                     `json:"file_count"`
+	ImprovementsCount int                     `json:"improvements_count"`
+	Improvements      model.ImprovementCounts `json:"improvements"` // 重要度ごとの内訳
+	CreatedAt         time.Time               `json:"created_at"`
+}
+
+// ReviewSessionFailureResponse - レビューに失敗したファイル
+type ReviewSessionFailureResponse struct {
+	FilePath string `json:"file_path"`
+	Error    string `json:"error"`
+}
+
+// ListReviewSessionsQuery - クエリパラメータ
+type ListReviewSessionsQuery struct {
+	Page     int `query:"page"`
+	PageSize int `query:"page_size"`
+}
+
+// ListReviewSessionsResponse - レスポンス
+type ListReviewSessionsResponse struct {
+	Items      []ReviewSessionSummaryResponse `json:"items"`
+	Total      int                            `json:"total"`
+	Page       int                            `json:"page"`
+	PageSize   int                            `json:"page_size"`
+	TotalPages int                            `json:"total_pages"`
+}
+
+// ReviewSession - POST /api/v1/reviews/sessions
+func (h *ReviewSessionHandler) ReviewSession(c echo.Context) error {
+	// 1. リクエストボディを取得
+	var req ReviewSessionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request body",
+		})
+	}
+
+	// 2. ロケールのバリデーション（ファイルのバリデーションはUseCaseで行う）
+	middleware.SetLocale(c, req.Locale)
+	if req.Locale != "" && model.NormalizeLocale(req.Locale) == "" {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: model.ErrLocaleInvalid.Error(),
+		})
+	}
+
+	// 3. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 4. UseCase実行
+	files := make([]review.ReviewSessionFile, len(req.Files))
+	for i, f := range req.Files {
+		files[i] = review.ReviewSessionFile{Path: f.Path, Code: f.Code, Language: f.Language}
+	}
+	output, err := h.reviewSessionUsecase.Execute(c.Request().Context(), review.ReviewSessionInput{
+		UserID:       userID,
+		Title:        req.Title,
+		Files:        files,
+		Language:     req.Language,
+		Context:      req.Context,
+		ForceRefresh: req.ForceRefresh,
+		Locale:       middleware.GetLocale(c),
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrReviewSessionFilesRequired) ||
+			errors.Is(err, model.ErrReviewSessionTooManyFiles) ||
+			errors.Is(err, model.ErrReviewSessionFilePathRequired) ||
+			errors.Is(err, model.ErrReviewSessionFilePathDuplicate) ||
+			errors.Is(err, model.ErrReviewSessionFileCodeRequired) ||
+			errors.Is(err, model.ErrReviewSessionFileLanguageRequired) {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+			})
+		}
+		var quotaErr *model.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceeded(c, quotaErr)
+		}
+		c.Logger().Errorf("ReviewSession failed: %v", err)
+		status, errResp := reviewErrorResponse(err)
+		return c.JSON(status, errResp)
+	}
+
+	// 5. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "RV-009")
+	return c.JSON(http.StatusCreated, toReviewSessionResponse(output))
+}
+
+// GetReviewSession - GET /api/v1/reviews/sessions/:id
+func (h *ReviewSessionHandler) GetReviewSession(c echo.Context) error {
+	// 1. パスパラメータから session_id を取得
+	sessionID := c.Param("id")
+	if sessionID == "" {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: "session_id は必須です",
+		})
+	}
+
+	// 2. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 3. UseCase実行（他のユーザーのセッションは見つからない扱い）
+	output, err := h.getReviewSessionUsecase.Execute(c.Request().Context(), review.GetReviewSessionInput{
+		SessionID: sessionID,
+		UserID:    userID,
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrReviewSessionNotFound) {
+			return c.JSON(http.StatusNotFound, response.ErrorResponse{
+				Error:   "not_found",
+				Message: model.ErrReviewSessionNotFound.Error(),
+			})
+		}
+		c.Logger().Errorf("GetReviewSession failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "internal_error",
+			Message: "サーバーエラーが発生しました",
+		})
+	}
+
+	// 4. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "RV-010")
+	return c.JSON(http.StatusOK, toReviewSessionResponse(output))
+}
+
+// ListReviewSessions - GET /api/v1/reviews/sessions
+func (h *ReviewSessionHandler) ListReviewSessions(c echo.Context) error {
+	// 1. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 2. クエリパラメータをパース
+	var query ListReviewSessionsQuery
+	if err := c.Bind(&query); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: "無効なパラメータです",
+		})
+	}
+	if err := validateListReviewsQuery(&ListReviewsQuery{Page: query.Page, PageSize: query.PageSize}); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+	}
+
+	// 3. UseCase実行
+	output, err := h.listReviewSessionsUsecase.Execute(c.Request().Context(), review.ListReviewSessionsInput{
+		UserID:   userID,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	})
+	if err != nil {
+		c.Logger().Errorf("ListReviewSessions failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "internal_error",
+			Message: "サーバーエラーが発生しました",
+		})
+	}
+
+	// 4. レスポンスを構築
+	items := make([]ReviewSessionSummaryResponse, len(output.Items))
+	for i, session := range output.Items {
+		items[i] = toReviewSessionSummaryResponse(session)
+	}
+
+	c.Response().Header().Set("X-API-Code", "RV-011")
+	return c.JSON(http.StatusOK, ListReviewSessionsResponse{
+		Items:      items,
+		Total:      output.Total,
+		Page:       output.Page,
+		PageSize:   output.PageSize,
+		TotalPages: output.TotalPages,
+	})
+}
+
+// toReviewSessionResponse - セッションとファイルごとのレビューをレスポンス形式に変換
+func toReviewSessionResponse(output *review.ReviewSessionOutput) ReviewSessionResponse {
+	resp := ReviewSessionResponse{
+		ReviewSessionSummaryResponse: toReviewSessionSummaryResponse(output.Session),
+		Reviews:                      make([]ReviewCodeResponse, len(output.Reviews)),
+		Failures:                     make([]ReviewSessionFailureResponse, len(output.Failures)),
+	}
+	for i, rev := range output.Reviews {
+		resp.Reviews[i] = toReviewCodeResponse(rev)
+	}
+	for i, f := range output.Failures {
+		resp.Failures[i] = ReviewSessionFailureResponse{FilePath: f.FilePath, Error: f.Error}
+	}
+	return resp
+}
+
+// toReviewSessionSummaryResponse - セッションをレスポンス形式に変換
+func toReviewSessionSummaryResponse(session *model.ReviewSession) ReviewSessionSummaryResponse {
+	return ReviewSessionSummaryResponse{
+		ID:                session.ID,
+		Title:             session.Title,
+		Locale:            session.Locale,
+		Summary:           session.Summary,
+		FileCount:         session.FileCount,
+		ImprovementsCount: session.Improvements.Total,
+		Improvements:      session.Improvements,
+		CreatedAt:         session.CreatedAt,
+	}
+}
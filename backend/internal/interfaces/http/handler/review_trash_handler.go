@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/s7r8/reviewapp/internal/application/usecase/review"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/middleware"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/response"
+)
+
+// ReviewTrashHandler - レビューの削除・ゴミ箱・復元のハンドラー
+type ReviewTrashHandler struct {
+	trashUsecase *review.ReviewTrashUseCase
+}
+
+// NewReviewTrashHandler - コンストラクタ
+func NewReviewTrashHandler(trashUsecase *review.ReviewTrashUseCase) *ReviewTrashHandler {
+	return &ReviewTrashHandler{
+		trashUsecase: trashUsecase,
+	}
+}
+
+// BulkDeleteReviewsQuery - 一括削除の条件（GET /api/v1/reviews と同じ。1つ以上必須）
+type BulkDeleteReviewsQuery struct {
+	Language   string     `query:"language"`
+	Status     string     `query:"status"`
+	SessionID  string     `query:"session_id"`
+	RerunOf    string     `query:"rerun_of"`
+	FollowUpOf string     `query:"follow_up_of"`
+	DateFrom   *time.Time `query:"date_from"`
+	DateTo     *time.Time `query:"date_to"`
+}
+
+// BulkDeleteReviewsResponse - レスポンス
+type BulkDeleteReviewsResponse struct {
+	DeletedCount int `json:"deleted_count"`
+}
+
+// ListTrashQuery - クエリパラメータ
+type ListTrashQuery struct {
+	Page     int `query:"page"`
+	PageSize int `query:"page_size"`
+}
+
+// ListTrashResponse - レスポンス
+type ListTrashResponse struct {
+	Items      []TrashItem `json:"items"`
+	Total      int         `json:"total"`
+	Page       int         `json:"page"`
+	PageSize   int         `json:"page_size"`
+	TotalPages int         `json:"total_pages"`
+}
+
+// TrashItem - ゴミ箱のレビュー
+type TrashItem struct {
+	ID        string    `json:"id"`
+	Code      string    `json:"code"`
+	Language  string    `json:"language"`
+	FileName  string    `json:"file_name,omitempty"`
+	FilePath  string    `json:"file_path,omitempty"`
+	SessionID *string   `json:"session_id,omitempty"`
+	Status    string    `json:"status"`
+	Summary   string    `json:"summary"`
+	CreatedAt time.Time `json:"created_at"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"` // この日時以降に完全削除する
+}
+
+// DeleteReview - DELETE /api/v1/reviews/:id
+func (h *ReviewTrashHandler) DeleteReview(c echo.Context) error {
+	// 1. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 2. UseCase実行
+	err = h.trashUsecase.Delete(c.Request().Context(), review.DeleteReviewInput{
+		ReviewID: c.Param("id"),
+		UserID:   userID,
+	})
+	if err != nil {
+		if status, errResp, ok := trashErrorResponse(err); ok {
+			return c.JSON(status, errResp)
+		}
+		c.Logger().Errorf("DeleteReview failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "internal_error",
+			Message: "サーバーエラーが発生しました",
+		})
+	}
+
+	// 3. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "RV-018")
+	return c.NoContent(http.StatusNoContent)
+}
+
+// BulkDeleteReviews - DELETE /api/v1/reviews?language=Go&date_to=...
+func (h *ReviewTrashHandler) BulkDeleteReviews(c echo.Context) error {
+	// 1. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 2. クエリパラメータをパース
+	var query BulkDeleteReviewsQuery
+	if err := c.Bind(&query); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: "無効なパラメータです",
+		})
+	}
+
+	// 3. UseCase実行
+	count, err := h.trashUsecase.BulkDelete(c.Request().Context(), review.BulkDeleteReviewsInput{
+		UserID:     userID,
+		Language:   query.Language,
+		Status:     query.Status,
+		SessionID:  query.SessionID,
+		RerunOf:    query.RerunOf,
+		FollowUpOf: query.FollowUpOf,
+		DateFrom:   query.DateFrom,
+		DateTo:     query.DateTo,
+	})
+	if err != nil {
+		if status, errResp, ok := trashErrorResponse(err); ok {
+			return c.JSON(status, errResp)
+		}
+		c.Logger().Errorf("BulkDeleteReviews failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "internal_error",
+			Message: "サーバーエラーが発生しました",
+		})
+	}
+
+	// 4. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "RV-019")
+	return c.JSON(http.StatusOK, BulkDeleteReviewsResponse{DeletedCount: count})
+}
+
+// ListTrash - GET /api/v1/reviews/trash
+func (h *ReviewTrashHandler) ListTrash(c echo.Context) error {
+	// 1. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 2. クエリパラメータをパース
+	var query ListTrashQuery
+	if err := c.Bind(&query); err != nil || query.Page < 0 || query.PageSize < 0 || query.PageSize > 100 {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: "無効なパラメータです",
+		})
+	}
+
+	// 3. UseCase実行
+	output, err := h.trashUsecase.ListTrash(c.Request().Context(), review.ListTrashInput{
+		UserID:   userID,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	})
+	if err != nil {
+		c.Logger().Errorf("ListTrash failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "internal_error",
+			Message: "サーバーエラーが発生しました",
+		})
+	}
+
+	// 4. レスポンスを構築
+	items := make([]TrashItem, 0, len(output.Items))
+	for _, rev := range output.Items {
+		item := TrashItem{
+			ID:        rev.ID,
+			Code:      rev.Code,
+			Language:  rev.Language,
+			FileName:  rev.FileName(),
+			FilePath:  rev.FilePath,
+			SessionID: rev.SessionID,
+			Status:    rev.Status,
+			CreatedAt: rev.CreatedAt,
+		}
+		if rev.StructuredResult != nil {
+			item.Summary = rev.StructuredResult.Summary
+		}
+		if rev.DeletedAt != nil {
+			item.DeletedAt = *rev.DeletedAt
+			item.PurgeAt = *rev.PurgeAt(output.Retention)
+		}
+		items = append(items, item)
+	}
+
+	// 5. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "RV-020")
+	return c.JSON(http.StatusOK, ListTrashResponse{
+		Items:      items,
+		Total:      output.Total,
+		Page:       output.Page,
+		PageSize:   output.PageSize,
+		TotalPages: output.TotalPages,
+	})
+}
+
+// RestoreReview - POST /api/v1/reviews/:id/restore
+func (h *ReviewTrashHandler) RestoreReview(c echo.Context) error {
+	// 1. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 2. UseCase実行
+	restored, err := h.trashUsecase.Restore(c.Request().Context(), review.RestoreReviewInput{
+		ReviewID: c.Param("id"),
+		UserID:   userID,
+	})
+	if err != nil {
+		if status, errResp, ok := trashErrorResponse(err); ok {
+			return c.JSON(status, errResp)
+		}
+		c.Logger().Errorf("RestoreReview failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "internal_error",
+			Message: "サーバーエラーが発生しました",
+		})
+	}
+
+	// 3. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "RV-021")
+	return c.JSON(http.StatusOK, toReviewCodeResponse(restored))
+}
+
+// trashErrorResponse - レビューが見つからない・権限がない・一括削除の条件が不正なエラーのレスポンス
+func trashErrorResponse(err error) (int, response.ErrorResponse, bool) {
+	switch {
+	case errors.Is(err, model.ErrReviewNotFound):
+		return http.StatusNotFound, response.ErrorResponse{
+			Error:   "not_found",
+			Message: model.ErrReviewNotFound.Error(),
+		}, true
+	case errors.Is(err, model.ErrReviewDeleteForbidden),
+		errors.Is(err, model.ErrReviewRestoreForbidden):
+		return http.StatusForbidden, response.ErrorResponse{
+			Error:   "forbidden",
+			Message: err.Error(),
+		}, true
+	case errors.Is(err, model.ErrReviewFilterRequired),
+		errors.Is(err, model.ErrReviewFilterInvalid):
+		return http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		}, true
+	}
+	return 0, response.ErrorResponse{}, false
+}
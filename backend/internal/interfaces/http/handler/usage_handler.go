@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/s7r8/reviewapp/internal/application/usecase/usage"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/response"
+)
+
+// UsageHandler - トークン使用量・コスト集計ハンドラー（管理者用）
+type UsageHandler struct {
+	getUsageUseCase *usage.GetUsageUseCase
+}
+
+// NewUsageHandler - コンストラクタ
+func NewUsageHandler(
+	getUsageUseCase *usage.GetUsageUseCase,
+) *UsageHandler {
+	return &UsageHandler{
+		getUsageUseCase: getUsageUseCase,
+	}
+}
+
+// GetUsage - 使用量・コスト集計エンドポイント
+// GET /api/v1/usage?from=2025-01-01&to=2025-01-31&group_by=day,model,user&user_id=xxx
+func (h *UsageHandler) GetUsage(c echo.Context) error {
+	// 1. クエリパラメータを取得（group_by はカンマ区切り）
+	var groupBy []string
+	if raw := c.QueryParam("group_by"); raw != "" {
+		for _, group := range strings.Split(raw, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				groupBy = append(groupBy, group)
+			}
+		}
+	}
+
+	// 2. UseCase実行
+	output, err := h.getUsageUseCase.Execute(c.Request().Context(), usage.GetUsageInput{
+		From:    c.QueryParam("from"),
+		To:      c.QueryParam("to"),
+		UserID:  c.QueryParam("user_id"),
+		GroupBy: groupBy,
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrUsageGroupInvalid) || errors.Is(err, model.ErrUsageRangeInvalid) {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+			})
+		}
+		c.Logger().Errorf("GetUsage failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "internal_error",
+			Message: "使用量の集計に失敗しました",
+		})
+	}
+
+	// 3. レスポンスヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "UG-001")
+
+	return c.JSON(http.StatusOK, output)
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/s7r8/reviewapp/internal/application/usecase/user"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/middleware"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/response"
+)
+
+// UserPreferencesHandler - ユーザーのレビュー設定ハンドラー
+type UserPreferencesHandler struct {
+	getPreferencesUseCase    *user.GetPreferencesUseCase
+	updatePreferencesUseCase *user.UpdatePreferencesUseCase
+}
+
+// NewUserPreferencesHandler - コンストラクタ
+func NewUserPreferencesHandler(
+	getPreferencesUseCase *user.GetPreferencesUseCase,
+	updatePreferencesUseCase *user.UpdatePreferencesUseCase,
+) *UserPreferencesHandler {
+	return &UserPreferencesHandler{
+		getPreferencesUseCase:    getPreferencesUseCase,
+		updatePreferencesUseCase: updatePreferencesUseCase,
+	}
+}
+
+// UpdatePreferencesRequest - レビュー設定の更新リクエスト
+// 設定全体を置き換える。省略（null）した項目はサーバーの設定を使う
+type UpdatePreferencesRequest struct {
+	LLMProvider    string   `json:"llm_provider"`
+	LLMModel       string   `json:"llm_model"`
+	Temperature    *float64 `json:"temperature"`
+	MaxTokens      *int     `json:"max_tokens"`
+	OutputLanguage string   `json:"output_language"`
+	ReviewFocus    []string `json:"review_focus"`
+}
+
+// GetPreferences - 自分のレビュー設定を取得
+// GET /api/v1/users/me/preferences
+func (h *UserPreferencesHandler) GetPreferences(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	output, err := h.getPreferencesUseCase.Execute(c.Request().Context(), user.GetPreferencesInput{UserID: userID})
+	if err != nil {
+		return preferencesErrorResponse(c, "GetPreferences", err)
+	}
+
+	c.Response().Header().Set("X-API-Code", "US-002")
+	return c.JSON(http.StatusOK, output)
+}
+
+// UpdatePreferences - 自分のレビュー設定を更新
+// PUT /api/v1/users/me/preferences
+func (h *UserPreferencesHandler) UpdatePreferences(c echo.Context) error {
+	// 1. リクエストボディをパース
+	var req UpdatePreferencesRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request body",
+		})
+	}
+
+	// 2. ユーザーIDを取得
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。",
+		})
+	}
+
+	// 3. UseCase実行
+	output, err := h.updatePreferencesUseCase.Execute(c.Request().Context(), user.UpdatePreferencesInput{
+		UserID: userID,
+		Preferences: model.UserPreferences{
+			LLMProvider:    req.LLMProvider,
+			LLMModel:       req.LLMModel,
+			Temperature:    req.Temperature,
+			MaxTokens:      req.MaxTokens,
+			OutputLanguage: req.OutputLanguage,
+			ReviewFocus:    req.ReviewFocus,
+		},
+	})
+	if err != nil {
+		return preferencesErrorResponse(c, "UpdatePreferences", err)
+	}
+
+	// 4. ヘッダーにAPI Codeを追加
+	c.Response().Header().Set("X-API-Code", "US-003")
+	return c.JSON(http.StatusOK, output)
+}
+
+// preferencesErrorResponse - レビュー設定のエラーをレスポンスに変換
+func preferencesErrorResponse(c echo.Context, operation string, err error) error {
+	switch {
+	case errors.Is(err, model.ErrPreferenceTemperatureInvalid),
+		errors.Is(err, model.ErrPreferenceMaxTokensInvalid),
+		errors.Is(err, model.ErrPreferenceOutputLanguageInvalid),
+		errors.Is(err, model.ErrPreferenceReviewFocusInvalid),
+		errors.Is(err, model.ErrPreferenceProviderUnavailable):
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+	case errors.Is(err, model.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error:   "not_found",
+			Message: "ユーザーが見つかりません",
+		})
+	}
+
+	c.Logger().Errorf("%s failed: %v", operation, err)
+	return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+		Error:   "internal_error",
+		Message: "サーバーエラーが発生しました",
+	})
+}
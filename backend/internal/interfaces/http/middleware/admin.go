@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/response"
+)
+
+// AdminMiddleware は管理者APIへのアクセスを制限するミドルウェア
+type AdminMiddleware struct {
+	adminSubs map[string]bool
+}
+
+// NewAdminMiddleware は新しいAdminMiddlewareを作成します
+// adminSubs には管理者のAuth0ユーザーID（sub）を指定します（空の場合は全員拒否）
+func NewAdminMiddleware(adminSubs []string) *AdminMiddleware {
+	subs := make(map[string]bool, len(adminSubs))
+	for _, sub := range adminSubs {
+		subs[sub] = true
+	}
+	return &AdminMiddleware{adminSubs: subs}
+}
+
+// RequireAdmin は管理者以外のリクエストを403で拒否します（Authenticateの後に適用）
+func (m *AdminMiddleware) RequireAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !m.adminSubs[GetAuth0Sub(c)] {
+			return c.JSON(http.StatusForbidden, response.ErrorResponse{
+				Error:   "forbidden",
+				Message: "管理者権限が必要です",
+			})
+		}
+		return next(c)
+	}
+}
@@ -62,6 +62,11 @@ func (m *AuthMiddleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 		if err == nil && user != nil {
 			// ユーザーが見つかった場合、IDをコンテキストに保存
 			c.Set(string(UserIDKey), user.ID)
+
+			// ユーザー設定の出力言語があれば、Accept-Languageより優先してロケールにする
+			if prefs, err := user.GetPreferences(); err == nil {
+				SetLocale(c, prefs.OutputLanguage)
+			}
 		}
 		// エラーの場合は無視（/auth/syncエンドポイントで作成される）
 
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/s7r8/reviewapp/internal/domain/model"
+	"github.com/s7r8/reviewapp/internal/interfaces/http/response"
+)
+
+// LocaleKey はリクエストのロケールを保存するためのキー
+const LocaleKey ContextKey = "locale"
+
+// Locale はAccept-Languageヘッダーからリクエストのロケールを決めるミドルウェア
+// 認証後はユーザー設定の出力言語（Authenticate）、リクエストボディの locale（各ハンドラー）で上書きされます
+func Locale(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if locale := model.ParseAcceptLanguage(c.Request().Header.Get("Accept-Language")); locale != "" {
+			SetLocale(c, locale)
+		}
+		return next(c)
+	}
+}
+
+// SetLocale はコンテキストにロケールを保存します（未対応のロケールは無視します）
+func SetLocale(c echo.Context, locale string) {
+	if locale = model.NormalizeLocale(locale); locale != "" {
+		c.Set(string(LocaleKey), locale)
+	}
+}
+
+// GetLocale はコンテキストからロケールを取得します（保存されていない場合は model.DefaultLocale）
+func GetLocale(c echo.Context) string {
+	if locale, ok := c.Get(string(LocaleKey)).(string); ok && locale != "" {
+		return locale
+	}
+	return model.DefaultLocale
+}
+
+// LocalizedJSONSerializer はエラーレスポンスのメッセージをリクエストのロケールの言語にするJSONシリアライザです
+// response.ErrorResponse と、echoのHTTPErrorHandlerが返す {"message": ...} を対象にします
+type LocalizedJSONSerializer struct {
+	echo.DefaultJSONSerializer
+}
+
+// Serialize はメッセージをロケールの言語にしてからJSONに変換します
+func (s LocalizedJSONSerializer) Serialize(c echo.Context, i interface{}, indent string) error {
+	return s.DefaultJSONSerializer.Serialize(c, localizeErrorBody(i, GetLocale(c)), indent)
+}
+
+// localizeErrorBody はエラーレスポンスのメッセージを置き換えた値を返します（それ以外はそのまま返します）
+func localizeErrorBody(i interface{}, locale string) interface{} {
+	if locale == model.DefaultLocale {
+		return i
+	}
+
+	switch body := i.(type) {
+	case response.ErrorResponse:
+		body.Message = response.LocalizeMessage(body.Message, locale)
+		return body
+	case *response.ErrorResponse:
+		if body == nil {
+			return i
+		}
+		localized := *body
+		localized.Message = response.LocalizeMessage(body.Message, locale)
+		return localized
+	case map[string]string:
+		message, ok := body["message"]
+		if !ok {
+			return i
+		}
+		localized := make(map[string]string, len(body))
+		for k, v := range body {
+			localized[k] = v
+		}
+		localized["message"] = response.LocalizeMessage(message, locale)
+		return localized
+	case echo.Map:
+		return localizeMessageField(body, locale)
+	case map[string]interface{}:
+		return localizeMessageField(body, locale)
+	}
+	return i
+}
+
+// localizeMessageField は "message" が文字列の場合のみ置き換えたコピーを返します
+func localizeMessageField(body map[string]interface{}, locale string) interface{} {
+	message, ok := body["message"].(string)
+	if !ok {
+		return body
+	}
+	localized := make(map[string]interface{}, len(body))
+	for k, v := range body {
+		localized[k] = v
+	}
+	localized["message"] = response.LocalizeMessage(message, locale)
+	return localized
+}
@@ -0,0 +1,186 @@
+package response
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/s7r8/reviewapp/internal/domain/model"
+)
+
+// englishMessages - エラーメッセージ（日本語）と英語の対応
+// メッセージは各層の日本語の文言をそのまま使い、英語のロケールの場合のみレスポンスを返す直前に置き換える
+var englishMessages = map[string]string{
+	// 認証・権限
+	"認証が必要です":        "Authentication is required",
+	"認証情報が見つかりません":   "Authentication information was not found",
+	"ユーザー情報が見つかりません": "User information was not found",
+	"ユーザー情報が見つかりません。/auth/syncを先に呼び出してください。": "User information was not found. Call /auth/sync first.",
+	"ユーザー同期に失敗しました":                          "Failed to sync the user",
+	"ユーザーが見つかりません":                           "User not found",
+	"ユーザーIDは必須です":                            "User ID is required",
+	"管理者権限が必要です":                             "Administrator privileges are required",
+
+	// リクエスト共通
+	"リクエストボディが不正です":                "Invalid request body",
+	"無効なパラメータです":                   "Invalid parameter",
+	"サーバーエラーが発生しました":               "An internal server error occurred",
+	"AI APIが一時的に利用できません":           "The AI API is temporarily unavailable",
+	"locale は ja または en を指定してください": "locale must be ja or en",
+
+	// レビュー
+	"コードは必須です":                    "Code is required",
+	"プログラミング言語は必須です":              "Programming language is required",
+	"レビューIDは必須です":                 "Review ID is required",
+	"レビューが見つかりません":                "Review not found",
+	"このレビューにアクセスする権限がありません":       "You do not have permission to access this review",
+	"このレビューを更新する権限がありません":         "You do not have permission to update this review",
+	"スコアは1-3の整数で指定してください":         "Score must be an integer from 1 to 3",
+	"コメントは500文字以内にしてください":         "Comment must be 500 characters or fewer",
+	"pageは1以上の整数を指定してください":        "page must be an integer of 1 or more",
+	"page_sizeは1〜100の整数を指定してください": "page_size must be an integer from 1 to 100",
+	"サポートされていない言語です":              "Unsupported language",
+	"無効なステータスです":                  "Invalid status",
+	"無効なソート対象です":                  "Invalid sort field",
+	"無効なソート順です":                   "Invalid sort order",
+	"開始日は終了日より前である必要があります":        "The start date must be before the end date",
+	"フィードバックの更新に失敗しました":           "Failed to update the feedback",
+
+	// 複数モデルのレビュー
+	"2つ以上のモデルを指定してください":                  "Specify two or more models",
+	"mode は compare または merge を指定してください": "mode must be compare or merge",
+	"指定されたモデルは設定されていません":                 "The specified model is not configured",
+	"複数モデルのレビューが見つかりません":                 "Ensemble review not found",
+	"ensemble_id は必須です":                  "ensemble_id is required",
+
+	// 複数ファイルのレビュー
+	"ファイルを1つ以上指定してください":                          "Specify one or more files",
+	"ファイル数が上限を超えています":                            "Too many files",
+	"ファイルのパスは必須です":                               "File path is required",
+	"同じパスのファイルが含まれています":                          "Duplicate file path",
+	"ファイルのコードは必須です":                              "File code is required",
+	"ファイルのプログラミング言語を判定できません（language を指定してください）": "Could not detect the programming language of the file (specify language)",
+	"レビューセッションが見つかりません":                          "Review session not found",
+	"session_id は必須です":                           "session_id is required",
+
+	// 差分のレビュー
+	"差分（unified diff）の形式が不正です":                      "Invalid unified diff",
+	"差分にレビューできる追加・変更がありません":                         "The diff has no added or modified lines to review",
+	"差分のハンク数が上限を超えています":                             "Too many hunks in the diff",
+	"code と diff はどちらか一方のみ指定してください":                 "Specify either code or diff, not both",
+	"差分のファイルのプログラミング言語を判定できません（language を指定してください）": "Could not detect the programming language of the diff (specify language)",
+
+	// レビューについての会話
+	"レビューについての会話は無効になっています":     "Conversations about reviews are disabled",
+	"メッセージは必須です":                "Message is required",
+	"メッセージは4000文字以内にしてください":     "Message must be 4000 characters or fewer",
+	"このレビューの会話のメッセージ数が上限に達しました": "Reached the message limit for this review",
+	"メッセージが見つかりません":             "Message not found",
+	"このメッセージからはナレッジを作成済みです":     "Knowledge has already been created from this message",
+
+	// 非同期のレビュー
+	"失敗したレビューのみ再実行できます": "Only failed reviews can be retried",
+	"レビューが完了していません":     "The review has not completed",
+	"レビューがタイムアウトしました":   "The review timed out",
+	"レビューに失敗しました":       "The review failed",
+	"レビューを削除したため中止しました": "The review was cancelled because it was deleted",
+
+	// 再レビューと結果の比較
+	"構造化されたレビュー結果がないため比較できません": "The review cannot be compared because it has no structured result",
+
+	// レビューの削除・ゴミ箱
+	"このレビューを削除する権限がありません":      "You do not have permission to delete this review",
+	"このレビューを復元する権限がありません":      "You do not have permission to restore this review",
+	"削除するレビューの条件を1つ以上指定してください": "Specify at least one condition for the reviews to delete",
+	"削除するレビューの条件が不正です":         "Invalid conditions for the reviews to delete",
+
+	// 改善点の適用
+	"適用する改善点を1つ以上指定してください":                      "Specify at least one improvement to apply",
+	"指定した番号の改善点がありません":                          "No improvement exists at the specified index",
+	"修正前のコードをレビューしたコードから特定できないため、この改善点は適用できません": "This improvement cannot be applied because its original code was not found in the reviewed code",
+	"修正後のコードがないため、この改善点は適用できません":                "This improvement cannot be applied because it has no revised code",
+	"同じ行を書き換える改善点は同時に適用できません":                   "Improvements that change the same lines cannot be applied together",
+	"差分のレビューには改善点を適用できません":                      "Improvements cannot be applied to a diff review",
+	"適用後のコードに構文エラーがあるため保存できません":                 "The code cannot be saved because it has syntax errors after applying the improvements",
+
+	// ナレッジ
+	"タイトルは必須です":           "Title is required",
+	"タイトルは200文字以内にしてください": "Title must be 200 characters or fewer",
+	"内容は必須です":             "Content is required",
+	"カテゴリは必須です":           "Category is required",
+	"無効なカテゴリです":           "Invalid category",
+	"重要度は必須です":            "Priority is required",
+	"重要度は1-5の整数で指定してください": "Priority must be an integer from 1 to 5",
+	"ソースタイプは必須です":         "Source type is required",
+	"無効なソースタイプです":         "Invalid source type",
+	"ナレッジIDが指定されていません":    "Knowledge ID is not specified",
+	"ナレッジが見つかりません":        "Knowledge not found",
+	"ナレッジの削除に失敗しました":      "Failed to delete the knowledge",
+	"このナレッジを削除する権限がありません": "You do not have permission to delete this knowledge",
+
+	// プロンプトテンプレート
+	"テンプレートIDは必須です":             "Template ID is required",
+	"テンプレート本文は必須です":             "Template content is required",
+	"テンプレート本文は20000文字以内にしてください": "Template content must be 20000 characters or fewer",
+	"無効なテンプレート名です":              "Invalid template name",
+	"プロンプトテンプレートが見つかりません":       "Prompt template not found",
+	"プロンプトテンプレートが不正です":          "Invalid prompt template",
+	"描画エラー": "render error",
+	"構文エラー": "syntax error",
+
+	// 使用量・利用上限・統計
+	"使用量の集計に失敗しました":                          "Failed to aggregate usage",
+	"集計期間が不正です（to は from 以降、期間は366日以内）":      "Invalid period (to must not be before from, and the period must be 366 days or fewer)",
+	"group_by は day, model, user から指定してください": "group_by must be one of day, model, user",
+	"from は YYYY-MM-DD 形式で指定してください":          "from must be in YYYY-MM-DD format",
+	"to は YYYY-MM-DD 形式で指定してください":            "to must be in YYYY-MM-DD format",
+	"利用上限に達しました":                             "Quota exceeded",
+	"利用上限の取得に失敗しました":                         "Failed to get the quota",
+	"利用上限の更新に失敗しました":                         "Failed to update the quota",
+	"ユーザー個別の利用上限が設定されていません":                  "No per-user quota is set",
+	"上限は0以上で指定してください（0は無制限）":                 "Limits must be 0 or more (0 means unlimited)",
+	"統計情報の取得に失敗しました":                         "Failed to get statistics",
+
+	// ユーザー設定
+	"temperature は0以上1以下で指定してください":          "temperature must be between 0 and 1",
+	"max_tokens は1以上16384以下で指定してください":       "max_tokens must be between 1 and 16384",
+	"output_language は ja または en を指定してください": "output_language must be ja or en",
+	"review_focus に未対応の観点が含まれています":          "review_focus contains an unsupported focus",
+	"指定されたLLMプロバイダは利用できません":                 "The specified LLM provider is unavailable",
+}
+
+// englishMessagePatterns - 値を含むメッセージ（利用上限など）
+var englishMessagePatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`^1日あたりのレビュー数の上限（(\d+)件）に達しました$`), "Reached the daily review limit ($1 reviews)"},
+	{regexp.MustCompile(`^1か月あたりのトークン数の上限（(\d+)）に達しました$`), "Reached the monthly token limit ($1)"},
+}
+
+// LocalizeMessage - エラーメッセージをロケールの言語にする
+// 日本語（既定）の場合と、対応する訳がないメッセージはそのまま返す
+// "<メッセージ>: <詳細>" の形式（%w でラップしたエラー）は、訳がある部分のみ置き換える
+func LocalizeMessage(message, locale string) string {
+	if locale != model.LocaleEnglish || message == "" {
+		return message
+	}
+	if translated, ok := englishMessages[message]; ok {
+		return translated
+	}
+	for _, p := range englishMessagePatterns {
+		if p.pattern.MatchString(message) {
+			return p.pattern.ReplaceAllString(message, p.replacement)
+		}
+	}
+
+	parts := strings.Split(message, ": ")
+	if len(parts) == 1 {
+		return message
+	}
+	for i, part := range parts {
+		if translated, ok := englishMessages[part]; ok {
+			parts[i] = translated
+		}
+	}
+	return strings.Join(parts, ": ")
+}
@@ -0,0 +1,9 @@
+-- =====================================================
+-- 002: レビュー結果の生成経路
+-- =====================================================
+-- 構造化データをどの経路で得たかを記録する
+--   tool_use    : Claude のツール呼び出し
+--   json_schema : OpenAI互換APIの JSON Schema 出力
+--   markdown    : マークダウンを正規表現でパース（フォールバック）
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS result_source VARCHAR(20) NOT NULL DEFAULT 'markdown';
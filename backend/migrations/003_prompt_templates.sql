@@ -0,0 +1,36 @@
+-- =====================================================
+-- 003: プロンプトテンプレート（バージョン管理）
+-- =====================================================
+-- システムプロンプトのレビュー方針部分を text/template 形式で保存する
+-- 同じ name のテンプレートは version ごとに1行ずつ保存し、有効化できるのは1バージョンのみ
+-- 有効なテンプレートがない場合は組み込みテンプレート（version 0）を使う
+CREATE TABLE IF NOT EXISTS prompt_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(50) NOT NULL,       -- 'review_system'
+    version INTEGER NOT NULL CHECK (version > 0),
+    content TEXT NOT NULL,
+    description TEXT,
+
+    -- 状態
+    is_active BOOLEAN NOT NULL DEFAULT false,
+
+    -- メタデータ
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    activated_at TIMESTAMP WITH TIME ZONE,
+
+    UNIQUE(name, version)
+);
+
+-- 有効なバージョンは name ごとに1つだけ
+CREATE UNIQUE INDEX IF NOT EXISTS idx_prompt_templates_active ON prompt_templates(name) WHERE is_active = true;
+
+-- =====================================================
+-- reviews: 実行時に使用したテンプレート
+-- =====================================================
+-- prompt_template_id が NULL かつ version が 0 の場合は組み込みテンプレート
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS prompt_template_id UUID REFERENCES prompt_templates(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS prompt_template_version INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_reviews_prompt_template ON reviews(prompt_template_id) WHERE prompt_template_id IS NOT NULL;
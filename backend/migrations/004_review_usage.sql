@@ -0,0 +1,21 @@
+-- =====================================================
+-- 004: レビューごとのトークン使用量とコスト
+-- =====================================================
+-- tokens_used（入力 + 出力）の内訳と、料金表から算出したコストを記録する
+--   input_tokens          : 入力（プロンプトキャッシュ分を除く）
+--   output_tokens         : 出力
+--   cache_creation_tokens : プロンプトキャッシュへの書き込み
+--   cache_read_tokens     : プロンプトキャッシュからの読み込み
+--   embedding_tokens      : ナレッジ検索用のEmbedding
+-- 既存のレビューは内訳が不明なため0のまま（tokens_used は残る）
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS input_tokens INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS output_tokens INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS cache_creation_tokens INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS cache_read_tokens INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS embedding_tokens INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS cost NUMERIC(12, 6) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS cost_currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+-- 使用量APIは削除済みのレビューも含めて期間で絞り込む（idx_reviews_created_at は削除済みを含まない）
+CREATE INDEX IF NOT EXISTS idx_reviews_created_at_all ON reviews(created_at);
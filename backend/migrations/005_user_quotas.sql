@@ -0,0 +1,15 @@
+-- =====================================================
+-- 005: ユーザー個別の利用上限
+-- =====================================================
+-- グローバルの上限は環境変数（QUOTA_REVIEWS_PER_DAY / QUOTA_TOKENS_PER_MONTH）で設定し、
+-- このテーブルの行があるユーザーは上書きする（NULL の項目はグローバル設定、0は無制限）
+-- 消費量は reviews テーブルから集計する（QUOTA_USE_REDIS=true の場合はRedisのカウンターを併用）
+CREATE TABLE IF NOT EXISTS user_quotas (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    reviews_per_day INTEGER CHECK (reviews_per_day >= 0),
+    tokens_per_month INTEGER CHECK (tokens_per_month >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 当日・当月の消費量の集計（削除済みのレビューも含む）
+CREATE INDEX IF NOT EXISTS idx_reviews_user_created_at_all ON reviews(user_id, created_at);
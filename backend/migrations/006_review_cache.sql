@@ -0,0 +1,16 @@
+-- =====================================================
+-- 006: レビュー結果のキャッシュ
+-- =====================================================
+-- 同じ入力（コード・言語・コンテキスト・参照ナレッジ・テンプレート・モデル）のレビューは
+-- LLMを呼び出さずに過去の結果を再利用する
+--   cache_key             : 入力のSHA-256（16進数）
+--   cache_hit             : 過去の結果を再利用したレビューか
+--   cached_from_review_id : 再利用した元のレビュー
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS cache_key VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS cache_hit BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS cached_from_review_id UUID REFERENCES reviews(id) ON DELETE SET NULL;
+
+-- キャッシュの検索（ユーザーごと・LLMで生成したレビューのみ）
+CREATE INDEX IF NOT EXISTS idx_reviews_cache_key ON reviews(user_id, cache_key, created_at DESC)
+    WHERE cache_key IS NOT NULL AND cache_hit = false AND deleted_at IS NULL;
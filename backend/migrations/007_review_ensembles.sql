@@ -0,0 +1,11 @@
+-- =====================================================
+-- 007: 複数モデルによるレビュー（アンサンブル）
+-- =====================================================
+-- 同じコードを複数のモデルで同時にレビューした結果をまとめる
+--   ensemble_id : 同時に作成したレビューで共通のID（モデルごとのレビューと統合したレビュー）
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS ensemble_id UUID;
+
+-- アンサンブルのレビュー一覧の取得
+CREATE INDEX IF NOT EXISTS idx_reviews_ensemble_id ON reviews(user_id, ensemble_id, created_at)
+    WHERE ensemble_id IS NOT NULL AND deleted_at IS NULL;
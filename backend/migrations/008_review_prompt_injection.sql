@@ -0,0 +1,7 @@
+-- =====================================================
+-- 008: プロンプトインジェクションの疑い
+-- =====================================================
+-- レビュー対象のコード・コンテキスト・ナレッジから検出した、プロンプトインジェクションの疑いがある箇所
+--   prompt_injection_signals : [{kind, source, source_id, line, excerpt}]（疑いがなければ空配列）
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS prompt_injection_signals JSONB NOT NULL DEFAULT '[]';
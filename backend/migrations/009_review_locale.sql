@@ -0,0 +1,7 @@
+-- =====================================================
+-- 009: レビューのロケール
+-- =====================================================
+-- プロンプト・レビュー結果の見出しの言語
+--   locale : ja / en（既存のレビューは日本語のプロンプトで作成したため ja）
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS locale TEXT NOT NULL DEFAULT 'ja';
@@ -0,0 +1,37 @@
+-- =====================================================
+-- 010: 複数ファイルのレビューセッション
+-- =====================================================
+-- 複数のファイルをまとめてレビューした単位（ファイルごとのレビューは reviews に1件ずつ保存）
+--   summary             : セッション全体の総合評価（ファイルごとの総合評価をまとめたもの）
+--   file_count          : レビューしたファイル数（失敗したファイルは含めない）
+--   improvement_count   : 改善点の合計（*_count は重要度ごとの内訳）
+CREATE TABLE IF NOT EXISTS review_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title TEXT NOT NULL DEFAULT '',
+    locale TEXT NOT NULL DEFAULT 'ja',
+    summary TEXT NOT NULL DEFAULT '',
+    file_count INTEGER NOT NULL DEFAULT 0,
+    improvement_count INTEGER NOT NULL DEFAULT 0,
+    high_count INTEGER NOT NULL DEFAULT 0,
+    medium_count INTEGER NOT NULL DEFAULT 0,
+    low_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+-- セッション履歴の取得
+CREATE INDEX IF NOT EXISTS idx_review_sessions_user_id ON review_sessions(user_id, created_at DESC)
+    WHERE deleted_at IS NULL;
+
+-- ファイルごとのレビュー
+--   session_id : 親のセッション（単体のレビューは NULL）
+--   file_path  : レビューしたファイルのパス（リポジトリのルートからの相対パス。指定がない場合は NULL）
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS session_id UUID REFERENCES review_sessions(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS file_path TEXT;
+
+-- セッションのレビュー一覧の取得
+CREATE INDEX IF NOT EXISTS idx_reviews_session_id ON reviews(user_id, session_id, created_at)
+    WHERE session_id IS NOT NULL AND deleted_at IS NULL;
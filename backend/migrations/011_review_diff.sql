@@ -0,0 +1,8 @@
+-- =====================================================
+-- 011: 差分（unified diff）のレビュー
+-- =====================================================
+-- input_format : code に保存した入力の形式
+--   code : ファイル全体（または一部）のコード
+--   diff : unified diff（追加・変更したハンクのみレビューし、改善点に file_path / start_line / end_line を記録）
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS input_format TEXT NOT NULL DEFAULT 'code';
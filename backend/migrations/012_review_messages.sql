@@ -0,0 +1,30 @@
+-- =====================================================
+-- 012: レビューについての会話
+-- =====================================================
+-- レビューごとのユーザーの質問とLLMの返答（古い順に会話の履歴としてLLMに渡す）
+--   role                 : user / assistant
+--   suggested_knowledge  : LLMが提案したナレッジ {title, content, category, priority}（提案がなければ NULL）
+--   knowledge_id         : このメッセージから作成したナレッジ（knowledge.source_type = 'conversation'）
+--   llm_provider 〜 cost : 返答を生成したLLMと使用量（ユーザーのメッセージは空・0）
+CREATE TABLE IF NOT EXISTS review_messages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('user', 'assistant')),
+    content TEXT NOT NULL,
+    suggested_knowledge JSONB,
+    knowledge_id UUID REFERENCES knowledge(id) ON DELETE SET NULL,
+    llm_provider TEXT NOT NULL DEFAULT '',
+    llm_model TEXT NOT NULL DEFAULT '',
+    tokens_used INTEGER NOT NULL DEFAULT 0,
+    input_tokens INTEGER NOT NULL DEFAULT 0,
+    output_tokens INTEGER NOT NULL DEFAULT 0,
+    cache_creation_tokens INTEGER NOT NULL DEFAULT 0,
+    cache_read_tokens INTEGER NOT NULL DEFAULT 0,
+    cost NUMERIC(12, 6) NOT NULL DEFAULT 0,
+    cost_currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- レビューの会話の取得
+CREATE INDEX IF NOT EXISTS idx_review_messages_review_id ON review_messages(review_id, created_at);
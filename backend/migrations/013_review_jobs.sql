@@ -0,0 +1,31 @@
+-- =====================================================
+-- 013: 非同期のレビュー（ジョブ）
+-- =====================================================
+-- レビューの状態（pending → running → completed / failed）
+--   status        : pending（実行待ち） / running（実行中） / completed（完了） / failed（失敗）
+--   is_async      : POST /api/v1/reviews?async=true で受け付けたレビュー
+--   job_input     : 実行に必要な、レビューの列にない入力 {force_refresh, context_files}
+--   error_message : 失敗した理由（再実行すると NULL に戻す）
+--   attempts      : 実行した回数（再実行・再起動後の再開を含む）
+--   started_at    : 最後に実行を開始した日時
+--   completed_at  : 完了・失敗した日時
+-- 既存のレビューは同期的に生成・保存したものなので completed とする
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'completed'
+        CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    ADD COLUMN IF NOT EXISTS is_async BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS job_input JSONB,
+    ADD COLUMN IF NOT EXISTS error_message TEXT,
+    ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS started_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP;
+
+UPDATE reviews SET completed_at = created_at WHERE completed_at IS NULL AND status = 'completed';
+
+-- ワーカーが実行待ちのジョブを古い順に取得する・止まったジョブを探す
+CREATE INDEX IF NOT EXISTS idx_reviews_jobs ON reviews(status, created_at)
+    WHERE status IN ('pending', 'running') AND deleted_at IS NULL;
+
+-- 履歴の状態での絞り込み
+CREATE INDEX IF NOT EXISTS idx_reviews_user_status ON reviews(user_id, status, created_at DESC)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,11 @@
+-- =====================================================
+-- 014: レビューの再実行（再レビュー）
+-- =====================================================
+-- 同じコードを現在のナレッジ・プロンプト・モデルでレビューし直したレビューに、元のレビューを記録する
+--   rerun_of_review_id : 再レビューの元のレビュー（元のレビューを削除した場合は NULL）
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS rerun_of_review_id UUID REFERENCES reviews(id) ON DELETE SET NULL;
+
+-- 元のレビューの再レビューの一覧
+CREATE INDEX IF NOT EXISTS idx_reviews_rerun_of ON reviews(rerun_of_review_id, created_at DESC)
+    WHERE rerun_of_review_id IS NOT NULL AND deleted_at IS NULL;
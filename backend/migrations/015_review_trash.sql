@@ -0,0 +1,13 @@
+-- =====================================================
+-- 015: レビューのゴミ箱
+-- =====================================================
+-- DELETE /api/v1/reviews/:id などで削除したレビューは deleted_at を設定してゴミ箱に移動し、
+-- 保持期間（REVIEW_TRASH_RETENTION）を過ぎたものを定期的に物理削除する
+
+-- ユーザーのゴミ箱の一覧（削除日時の新しい順）
+CREATE INDEX IF NOT EXISTS idx_reviews_user_deleted ON reviews(user_id, deleted_at DESC)
+    WHERE deleted_at IS NOT NULL;
+
+-- 保持期間を過ぎたレビューの物理削除
+CREATE INDEX IF NOT EXISTS idx_reviews_deleted_at ON reviews(deleted_at)
+    WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,11 @@
+-- =====================================================
+-- 016: 改善点を適用したコードのレビュー（フォローアップ）
+-- =====================================================
+-- POST /api/v1/reviews/:id/apply で改善点を適用したコードをレビューしたレビューに、適用元のレビューを記録する
+--   follow_up_of_review_id : 適用元のレビュー（適用元のレビューを削除した場合は NULL）
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS follow_up_of_review_id UUID REFERENCES reviews(id) ON DELETE SET NULL;
+
+-- 適用元のレビューのフォローアップの一覧
+CREATE INDEX IF NOT EXISTS idx_reviews_follow_up_of ON reviews(follow_up_of_review_id, created_at DESC)
+    WHERE follow_up_of_review_id IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,9 @@
+-- =====================================================
+-- 017: Go のコードの静的解析の結果
+-- =====================================================
+-- LLM を呼び出す前に go/parser・go/ast で計算した、構文エラー・捨てている戻り値・関数の行数・ネストの深さ・循環的複雑度
+--   static_analysis : {findings: [{rule, start_line, end_line, function, call, message, value, threshold}],
+--                      functions: [{name, start_line, end_line, lines, max_nesting, complexity}]}
+--                     （Go 以外・差分のレビューは NULL）
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS static_analysis JSONB;
@@ -0,0 +1,12 @@
+-- =====================================================
+-- 018: 保持期間を過ぎたゴミ箱のレビューの使用量を残す
+-- =====================================================
+-- 利用上限（QT-001）と使用量の集計（UG-001）は reviews の行から数えるため、
+-- 保持期間を過ぎたレビューは行を削除せず、コード・レビュー結果などの内容を消して使用量の列（トークン数・コスト・作成日時など）を残す
+--   purged_at : 内容を消した日時（ゴミ箱の一覧・復元の対象外。NULL は内容が残っている）
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS purged_at TIMESTAMP WITH TIME ZONE;
+
+-- 保持期間を過ぎたレビューの内容の削除（内容を消したレビューは対象外）
+CREATE INDEX IF NOT EXISTS idx_reviews_purge ON reviews(deleted_at)
+    WHERE deleted_at IS NOT NULL AND purged_at IS NULL;
@@ -0,0 +1,9 @@
+-- =====================================================
+-- 019: 非同期のレビューの開始・完了日時をタイムゾーン付きにする
+-- =====================================================
+-- 013 で TIMESTAMP（タイムゾーンなし）で追加したため、DBとアプリケーションのタイムゾーンが異なると
+-- 止まったジョブの判定（started_at とアプリケーションの時刻の比較）がずれる。他の日時の列と同じ TIMESTAMPTZ にする
+-- 既存の値はDBのセッションのタイムゾーン（TimeZone）の日時として変換する（実行中のジョブの started_at は NOW() で設定している）
+ALTER TABLE reviews
+    ALTER COLUMN started_at TYPE TIMESTAMP WITH TIME ZONE,
+    ALTER COLUMN completed_at TYPE TIMESTAMP WITH TIME ZONE;
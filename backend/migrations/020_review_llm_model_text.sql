@@ -0,0 +1,7 @@
+-- =====================================================
+-- 020: reviews.llm_model の長さの制限をなくす
+-- =====================================================
+-- 統合したレビュー（複数モデルのレビューの merge）は llm_model に比較したモデルを "provider:model" のカンマ区切りで保存するため、
+-- 3〜4モデルで VARCHAR(100) を超える
+ALTER TABLE reviews
+    ALTER COLUMN llm_model TYPE TEXT;
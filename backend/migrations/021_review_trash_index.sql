@@ -0,0 +1,16 @@
+-- =====================================================
+-- 021: ゴミ箱の一覧のインデックス
+-- =====================================================
+-- 015 の idx_reviews_deleted_at（WHERE deleted_at IS NOT NULL）は 001 の同名のインデックス
+-- （WHERE deleted_at IS NULL）があるため IF NOT EXISTS で作成されていなかった。
+-- 保持期間を過ぎたレビューの完全削除は 018 の idx_reviews_purge を使うため、ここでは作成しない。
+--
+-- ゴミ箱の一覧・件数（RV-020）は完全削除したレビュー（purged_at）を除くため、
+-- 完全削除したレビューが増えても一覧が遅くならないように、purged_at IS NULL に絞った別名のインデックスにする
+
+-- ユーザーのゴミ箱の一覧（削除日時の新しい順。完全削除したレビューは除く）
+CREATE INDEX IF NOT EXISTS idx_reviews_trash ON reviews(user_id, deleted_at DESC)
+    WHERE deleted_at IS NOT NULL AND purged_at IS NULL;
+
+-- idx_reviews_trash に置き換える
+DROP INDEX IF EXISTS idx_reviews_user_deleted;
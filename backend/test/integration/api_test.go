@@ -2,15 +2,19 @@ package integration_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/s7r8/reviewapp/internal/di"
 	"github.com/s7r8/reviewapp/internal/domain/model"
 	"github.com/s7r8/reviewapp/internal/infrastructure/config"
+	"github.com/s7r8/reviewapp/internal/infrastructure/external"
+	"github.com/s7r8/reviewapp/internal/infrastructure/persistence/postgres"
 	"github.com/s7r8/reviewapp/test/testutil"
 )
 
@@ -32,16 +36,22 @@ func TestReviewAPI_Integration(t *testing.T) {
 			ClaudeAPIKey:    "test-api-key",
 			ClaudeModel:     "claude-3-5-sonnet-20241022",
 			ClaudeMaxTokens: 4096,
+			EmbeddingDim:    1536,
+		},
+		// ネットワークを使わずにナレッジのEmbeddingを保存し、ベクトル検索を使う
+		Embedding: config.EmbeddingConfig{
+			Provider: external.ProviderHashing,
 		},
 	}
 
 	// ハンドラーを初期化（実際のDIを使用）
-	knowledgeHandler, err := di.InitializeKnowledgeHandler(testDB.DB, cfg)
+	breakers := external.NewBreakerRegistry(5, 30*time.Second)
+	knowledgeHandler, err := di.InitializeKnowledgeHandler(testDB.DB, cfg, breakers, nil)
 	if err != nil {
 		t.Fatalf("Failed to initialize knowledge handler: %v", err)
 	}
 
-	reviewHandler, err := di.InitializeReviewHandler(testDB.DB, cfg)
+	reviewHandler, err := di.InitializeReviewHandler(testDB.DB, cfg, breakers, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to initialize review handler: %v", err)
 	}
@@ -130,6 +140,48 @@ func TestReviewAPI_Integration(t *testing.T) {
 	})
 }
 
+func TestKnowledgeSimilarity_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	testDB := testutil.NewTestDatabase(t)
+	defer testDB.Close()
+	defer testDB.Cleanup(t)
+	testDB.SeedTestData(t)
+
+	// ハッシュEmbeddingでナレッジを保存し、pgvectorの類似度検索で取得できることを確認
+	ctx := context.Background()
+	userID := "00000000-0000-0000-0000-000000000001"
+	embeddingClient := external.NewHashingEmbeddingClient(1536)
+	repo := postgres.NewKnowledgeRepository(testDB.DB)
+
+	contents := map[string]string{
+		"エラーのラップ":   "エラーは fmt.Errorf と %w でラップして呼び出し元に返す",
+		"テーブル駆動テスト": "テストはテーブル駆動で書き、t.Run でサブテストに名前を付ける",
+	}
+	for title, content := range contents {
+		k, err := model.NewKnowledge(userID, title, content, model.CategoryOther, 3)
+		if err != nil {
+			t.Fatalf("Failed to build knowledge: %v", err)
+		}
+		embedding, _ := embeddingClient.GenerateEmbedding(ctx, title+"\n"+content)
+		k.SetEmbedding(embedding)
+		if err := repo.Create(ctx, k); err != nil {
+			t.Fatalf("Failed to create knowledge: %v", err)
+		}
+	}
+
+	query, _ := embeddingClient.GenerateEmbedding(ctx, "if err != nil { return fmt.Errorf(\"load config: %w\", err) }")
+	found, err := repo.SearchBySimilarity(ctx, userID, query, 1, 0)
+	if err != nil {
+		t.Fatalf("Failed to search knowledge: %v", err)
+	}
+	if len(found) != 1 || found[0].Title != "エラーのラップ" {
+		t.Errorf("Expected エラーのラップ to be the most similar, got %+v", found)
+	}
+}
+
 func TestDatabase_Integration(t *testing.T) {
 	// データベース接続テスト
 	testDB := testutil.NewTestDatabase(t)
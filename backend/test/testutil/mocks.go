@@ -3,6 +3,9 @@ package testutil
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/s7r8/reviewapp/internal/domain/model"
@@ -139,8 +142,9 @@ func (m *MockKnowledgeRepository) CountByCategory(ctx context.Context, userID st
 	return counts, nil
 }
 
-// MockReviewRepository - レビューリポジトリのモック
+// MockReviewRepository - レビューリポジトリのモック（非同期のレビューのワーカーから呼び出すため排他制御する）
 type MockReviewRepository struct {
+	mu      sync.Mutex
 	reviews []*model.Review
 	err     error
 }
@@ -152,10 +156,14 @@ func NewMockReviewRepository() *MockReviewRepository {
 }
 
 func (m *MockReviewRepository) SetError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.err = err
 }
 
 func (m *MockReviewRepository) Create(ctx context.Context, review *model.Review) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.err != nil {
 		return m.err
 	}
@@ -163,19 +171,35 @@ func (m *MockReviewRepository) Create(ctx context.Context, review *model.Review)
 	return nil
 }
 
+func (m *MockReviewRepository) CreateAll(ctx context.Context, reviews []*model.Review) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.reviews = append(m.reviews, reviews...)
+	return nil
+}
+
 func (m *MockReviewRepository) FindByID(ctx context.Context, id string) (*model.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.err != nil {
 		return nil, m.err
 	}
 	for _, r := range m.reviews {
-		if r.ID == id {
-			return r, nil
+		if r.ID == id && r.DeletedAt == nil {
+			// 呼び出し側の変更（状態の遷移など）が保存前に反映されないようコピーを返す
+			found := *r
+			return &found, nil
 		}
 	}
-	return nil, errors.New("review not found")
+	return nil, fmt.Errorf("%w: %s", model.ErrReviewNotFound, id)
 }
 
 func (m *MockReviewRepository) FindByUserID(ctx context.Context, userID string, limit int) ([]*model.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -192,6 +216,8 @@ func (m *MockReviewRepository) FindByUserID(ctx context.Context, userID string,
 }
 
 func (m *MockReviewRepository) Update(ctx context.Context, review *model.Review) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.err != nil {
 		return m.err
 	}
@@ -199,6 +225,8 @@ func (m *MockReviewRepository) Update(ctx context.Context, review *model.Review)
 }
 
 func (m *MockReviewRepository) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.err != nil {
 		return m.err
 	}
@@ -211,6 +239,179 @@ func (m *MockReviewRepository) Delete(ctx context.Context, id string) error {
 	return errors.New("review not found")
 }
 
+// SoftDelete - 削除日時を設定したコピーで置き換える
+func (m *MockReviewRepository) SoftDelete(ctx context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return false, m.err
+	}
+	for i, r := range m.reviews {
+		if r.ID == id && r.DeletedAt == nil {
+			deleted := *r
+			now := time.Now()
+			deleted.DeletedAt = &now
+			cancelled := cancelJobOnDelete(&deleted)
+			m.reviews[i] = &deleted
+			return cancelled, nil
+		}
+	}
+	return false, fmt.Errorf("%w: %s", model.ErrReviewNotFound, id)
+}
+
+// SoftDeleteWithFilters - 簡易実装：language / status / session_id / rerun_of / follow_up_of のフィルターのみ対応
+func (m *MockReviewRepository) SoftDeleteWithFilters(ctx context.Context, userID string, filters map[string]interface{}) (int, []*model.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return 0, nil, m.err
+	}
+	matches := func(r *model.Review) bool {
+		if language, ok := filters["language"].(string); ok && r.Language != language {
+			return false
+		}
+		if status, ok := filters["status"].(string); ok && r.Status != status {
+			return false
+		}
+		if sessionID, ok := filters["session_id"].(string); ok && (r.SessionID == nil || *r.SessionID != sessionID) {
+			return false
+		}
+		if rerunOf, ok := filters["rerun_of"].(string); ok && (r.RerunOfReviewID == nil || *r.RerunOfReviewID != rerunOf) {
+			return false
+		}
+		if followUpOf, ok := filters["follow_up_of"].(string); ok && (r.FollowUpOfReviewID == nil || *r.FollowUpOfReviewID != followUpOf) {
+			return false
+		}
+		return true
+	}
+	count := 0
+	var cancelledJobs []*model.Review
+	now := time.Now()
+	for i, r := range m.reviews {
+		if r.UserID == userID && r.DeletedAt == nil && matches(r) {
+			deleted := *r
+			deleted.DeletedAt = &now
+			if cancelJobOnDelete(&deleted) {
+				cancelledJobs = append(cancelledJobs, &model.Review{ID: r.ID, UserID: r.UserID, CreatedAt: r.CreatedAt})
+			}
+			m.reviews[i] = &deleted
+			count++
+		}
+	}
+	return count, cancelledJobs, nil
+}
+
+// cancelJobOnDelete - 削除する実行待ち・実行中の非同期のレビューを失敗にする（中止した場合は true）
+func cancelJobOnDelete(r *model.Review) bool {
+	if r.Status == model.ReviewStatusPending || r.Status == model.ReviewStatusRunning {
+		r.Fail(model.ErrReviewJobCancelled.Error())
+		return true
+	}
+	return false
+}
+
+func (m *MockReviewRepository) FindDeletedByID(ctx context.Context, id string) (*model.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, r := range m.reviews {
+		if r.ID == id && r.DeletedAt != nil && r.PurgedAt == nil {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", model.ErrReviewNotFound, id)
+}
+
+// ListDeleted - 簡易実装：削除日時の新しい順（同じ場合は追加した順）に返す
+func (m *MockReviewRepository) ListDeleted(ctx context.Context, userID string, limit, offset int) ([]*model.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	result := []*model.Review{}
+	for _, r := range m.reviews {
+		if r.UserID == userID && r.DeletedAt != nil && r.PurgedAt == nil {
+			result = append(result, r)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DeletedAt.After(*result[j].DeletedAt)
+	})
+	if offset >= len(result) {
+		return []*model.Review{}, nil
+	}
+	end := offset + limit
+	if end > len(result) {
+		end = len(result)
+	}
+	return result[offset:end], nil
+}
+
+func (m *MockReviewRepository) CountDeleted(ctx context.Context, userID string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return 0, m.err
+	}
+	count := 0
+	for _, r := range m.reviews {
+		if r.UserID == userID && r.DeletedAt != nil && r.PurgedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockReviewRepository) Restore(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	for i, r := range m.reviews {
+		if r.ID == id && r.DeletedAt != nil && r.PurgedAt == nil {
+			restored := *r
+			restored.DeletedAt = nil
+			m.reviews[i] = &restored
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", model.ErrReviewNotFound, id)
+}
+
+// PurgeDeleted - 簡易実装：保持期間を過ぎたレビューの内容を消し、使用量の項目を残す
+func (m *MockReviewRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return 0, m.err
+	}
+	count := 0
+	now := time.Now()
+	for i, r := range m.reviews {
+		if r.DeletedAt == nil || r.PurgedAt != nil || !r.DeletedAt.Before(deletedBefore) {
+			continue
+		}
+		purged := *r
+		purged.Code = ""
+		purged.Context = ""
+		purged.FilePath = ""
+		purged.ReviewResult = ""
+		purged.StructuredResult = nil
+		purged.FeedbackComment = ""
+		purged.ReferencedKnowledge = nil
+		purged.StaticAnalysis = nil
+		purged.JobOptions = nil
+		purged.PurgedAt = &now
+		m.reviews[i] = &purged
+		count++
+	}
+	return count, nil
+}
+
 func (m *MockReviewRepository) FindRecentByUserID(ctx context.Context, userID string, limit int) ([]*model.Review, error) {
 	if m.err != nil {
 		return nil, m.err
@@ -220,6 +421,8 @@ func (m *MockReviewRepository) FindRecentByUserID(ctx context.Context, userID st
 }
 
 func (m *MockReviewRepository) UpdateFeedback(ctx context.Context, reviewID string, score int, comment string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.err != nil {
 		return m.err
 	}
@@ -227,13 +430,15 @@ func (m *MockReviewRepository) UpdateFeedback(ctx context.Context, reviewID stri
 }
 
 func (m *MockReviewRepository) ListWithFilters(ctx context.Context, userID string, filters map[string]interface{}, sortBy, sortOrder string, limit, offset int) ([]*model.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.err != nil {
 		return nil, m.err
 	}
-	// 簡易実装：フィルターなしで全件返す
+	// 簡易実装：フィルターなしで削除していない全件を返す
 	var result []*model.Review
 	for _, r := range m.reviews {
-		if r.UserID == userID {
+		if r.UserID == userID && r.DeletedAt == nil {
 			result = append(result, r)
 		}
 	}
@@ -249,13 +454,15 @@ func (m *MockReviewRepository) ListWithFilters(ctx context.Context, userID strin
 }
 
 func (m *MockReviewRepository) CountWithFilters(ctx context.Context, userID string, filters map[string]interface{}) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.err != nil {
 		return 0, m.err
 	}
-	// 簡易実装：フィルターなしで全件カウント
+	// 簡易実装：フィルターなしで削除していない全件をカウント
 	count := 0
 	for _, r := range m.reviews {
-		if r.UserID == userID {
+		if r.UserID == userID && r.DeletedAt == nil {
 			count++
 		}
 	}
@@ -263,6 +470,8 @@ func (m *MockReviewRepository) CountWithFilters(ctx context.Context, userID stri
 }
 
 func (m *MockReviewRepository) CountByUserID(ctx context.Context, userID string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.err != nil {
 		return 0, m.err
 	}
@@ -276,6 +485,8 @@ func (m *MockReviewRepository) CountByUserID(ctx context.Context, userID string)
 }
 
 func (m *MockReviewRepository) CountByUserIDAndDateRange(ctx context.Context, userID string, from, to time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.err != nil {
 		return 0, m.err
 	}
@@ -289,6 +500,8 @@ func (m *MockReviewRepository) CountByUserIDAndDateRange(ctx context.Context, us
 }
 
 func (m *MockReviewRepository) GetAverageFeedbackScore(ctx context.Context, userID string) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.err != nil {
 		return 0.0, m.err
 	}
@@ -307,6 +520,343 @@ func (m *MockReviewRepository) GetAverageFeedbackScore(ctx context.Context, user
 	return total / float64(count), nil
 }
 
+// AggregateUsage - 簡易実装：メモリ上のレビューを集計単位ごとに合算（ユーザー名・メールは空）
+func (m *MockReviewRepository) AggregateUsage(ctx context.Context, filter model.UsageFilter) ([]*model.UsageSummary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	summaries := []*model.UsageSummary{}
+	index := map[string]*model.UsageSummary{}
+	for _, r := range m.reviews {
+		if r.CreatedAt.Before(filter.From) || !r.CreatedAt.Before(filter.To) {
+			continue
+		}
+		if filter.UserID != "" && r.UserID != filter.UserID {
+			continue
+		}
+		if !r.IsCompleted() {
+			continue
+		}
+
+		key := model.UsageSummary{Currency: r.CostCurrency}
+		for _, group := range filter.GroupBy {
+			switch group {
+			case model.UsageGroupDay:
+				key.Day = r.CreatedAt.UTC().Format(time.DateOnly)
+			case model.UsageGroupModel:
+				key.LLMProvider, key.LLMModel = r.LLMProvider, r.LLMModel
+			case model.UsageGroupUser:
+				key.UserID = r.UserID
+			default:
+				return nil, fmt.Errorf("%w: %s", model.ErrUsageGroupInvalid, group)
+			}
+		}
+
+		k := fmt.Sprintf("%s|%s|%s|%s|%s", key.Day, key.LLMProvider, key.LLMModel, key.UserID, key.Currency)
+		summary, ok := index[k]
+		if !ok {
+			summary = &key
+			index[k] = summary
+			summaries = append(summaries, summary)
+		}
+		summary.ReviewCount++
+		summary.Usage = summary.Usage.Add(r.Usage)
+		summary.Cost += r.Cost
+	}
+	return summaries, nil
+}
+
+// FindCachedReview - 簡易実装：キャッシュキーが一致する最新のレビューを返す
+func (m *MockReviewRepository) FindCachedReview(ctx context.Context, userID, cacheKey string, since time.Time) (*model.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	var found *model.Review
+	for _, r := range m.reviews {
+		if r.UserID != userID || r.CacheKey != cacheKey || r.CacheHit || !r.IsCompleted() || r.CreatedAt.Before(since) {
+			continue
+		}
+		if found == nil || !r.CreatedAt.Before(found.CreatedAt) {
+			found = r
+		}
+	}
+	if found == nil {
+		return nil, model.ErrReviewCacheMiss
+	}
+	return found, nil
+}
+
+// FindByEnsembleID - 簡易実装：同じアンサンブルのレビューを作成順に返す
+func (m *MockReviewRepository) FindByEnsembleID(ctx context.Context, userID, ensembleID string) ([]*model.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*model.Review
+	for _, r := range m.reviews {
+		if r.UserID == userID && r.EnsembleID != nil && *r.EnsembleID == ensembleID {
+			result = append(result, r)
+		}
+	}
+	if len(result) == 0 {
+		return nil, model.ErrEnsembleNotFound
+	}
+	return result, nil
+}
+
+// FindBySessionID - 簡易実装：同じセッションのレビューを作成順に返す
+func (m *MockReviewRepository) FindBySessionID(ctx context.Context, userID, sessionID string) ([]*model.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	result := []*model.Review{}
+	for _, r := range m.reviews {
+		if r.UserID == userID && r.SessionID != nil && *r.SessionID == sessionID {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+// ClaimNextJob - 簡易実装：最も古い実行待ちのレビューを実行中にしてコピーを返す
+func (m *MockReviewRepository) ClaimNextJob(ctx context.Context) (*model.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	for i, r := range m.reviews {
+		if r.Status != model.ReviewStatusPending || r.DeletedAt != nil {
+			continue
+		}
+		now := time.Now()
+		claimed := *r
+		claimed.Status = model.ReviewStatusRunning
+		claimed.Attempts++
+		claimed.StartedAt = &now
+		claimed.ErrorMessage = ""
+		claimed.CompletedAt = nil
+		stored := claimed
+		m.reviews[i] = &stored
+		return &claimed, nil
+	}
+	return nil, model.ErrNoPendingReviewJob
+}
+
+// UpdateJobStatus - 簡易実装：状態が from で実行回数が同じ場合のみ、保存しているレビューをコピーで置き換える
+func (m *MockReviewRepository) UpdateJobStatus(ctx context.Context, review *model.Review, from string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	if !m.ownsJob(review, from) {
+		return fmt.Errorf("%w: %s", model.ErrReviewJobLost, review.ID)
+	}
+	return m.replace(review)
+}
+
+// CompleteJob - 簡易実装：実行中で実行回数が同じ場合のみ、保存しているレビューを完了したレビューのコピーで置き換える
+func (m *MockReviewRepository) CompleteJob(ctx context.Context, review *model.Review) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	if !m.ownsJob(review, model.ReviewStatusRunning) {
+		return fmt.Errorf("%w: %s", model.ErrReviewJobLost, review.ID)
+	}
+	completed := *review
+	completed.Status = model.ReviewStatusCompleted
+	completed.ErrorMessage = ""
+	return m.replace(&completed)
+}
+
+// RequeueStaleJobs - 簡易実装：startedBefore より前に開始した実行中のレビューを実行待ちに戻す
+func (m *MockReviewRepository) RequeueStaleJobs(ctx context.Context, startedBefore time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return 0, m.err
+	}
+	count := 0
+	for i, r := range m.reviews {
+		if r.Status == model.ReviewStatusRunning && r.StartedAt != nil && r.StartedAt.Before(startedBefore) {
+			requeued := *r
+			requeued.Status = model.ReviewStatusPending
+			m.reviews[i] = &requeued
+			count++
+		}
+	}
+	return count, nil
+}
+
+// replace - 同じIDのレビューをコピーで置き換える（呼び出し元が後から変更しても影響しないようにする）
+// ownsJob - 保存しているレビューの状態が status で実行回数が同じか（見つからない場合は replace が ErrReviewNotFound を返す）
+func (m *MockReviewRepository) ownsJob(review *model.Review, status string) bool {
+	for _, r := range m.reviews {
+		if r.ID == review.ID {
+			return r.DeletedAt == nil && r.Status == status && r.Attempts == review.Attempts
+		}
+	}
+	return true
+}
+
+func (m *MockReviewRepository) replace(review *model.Review) error {
+	for i, r := range m.reviews {
+		if r.ID == review.ID {
+			stored := *review
+			m.reviews[i] = &stored
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", model.ErrReviewNotFound, review.ID)
+}
+
+// MockReviewSessionRepository - 複数ファイルのレビューセッションリポジトリのモック
+type MockReviewSessionRepository struct {
+	sessions []*model.ReviewSession
+	err      error
+}
+
+func NewMockReviewSessionRepository() *MockReviewSessionRepository {
+	return &MockReviewSessionRepository{
+		sessions: make([]*model.ReviewSession, 0),
+	}
+}
+
+func (m *MockReviewSessionRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockReviewSessionRepository) Create(ctx context.Context, session *model.ReviewSession) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sessions = append(m.sessions, session)
+	return nil
+}
+
+func (m *MockReviewSessionRepository) FindByID(ctx context.Context, userID, sessionID string) (*model.ReviewSession, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, s := range m.sessions {
+		if s.ID == sessionID && s.UserID == userID {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", model.ErrReviewSessionNotFound, sessionID)
+}
+
+// ListByUserID - 簡易実装：後から作成したセッションを先に返す
+func (m *MockReviewSessionRepository) ListByUserID(ctx context.Context, userID string, limit, offset int) ([]*model.ReviewSession, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	result := []*model.ReviewSession{}
+	for i := len(m.sessions) - 1; i >= 0; i-- {
+		if m.sessions[i].UserID == userID {
+			result = append(result, m.sessions[i])
+		}
+	}
+	if offset >= len(result) {
+		return []*model.ReviewSession{}, nil
+	}
+	end := offset + limit
+	if end > len(result) {
+		end = len(result)
+	}
+	return result[offset:end], nil
+}
+
+func (m *MockReviewSessionRepository) CountByUserID(ctx context.Context, userID string) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	count := 0
+	for _, s := range m.sessions {
+		if s.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MockReviewMessageRepository - レビューについての会話のメッセージリポジトリのモック
+type MockReviewMessageRepository struct {
+	messages []*model.ReviewMessage
+	err      error
+}
+
+func NewMockReviewMessageRepository() *MockReviewMessageRepository {
+	return &MockReviewMessageRepository{
+		messages: make([]*model.ReviewMessage, 0),
+	}
+}
+
+func (m *MockReviewMessageRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockReviewMessageRepository) CreateExchange(ctx context.Context, question, reply *model.ReviewMessage) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.messages = append(m.messages, question, reply)
+	return nil
+}
+
+func (m *MockReviewMessageRepository) FindByID(ctx context.Context, userID, reviewID, messageID string) (*model.ReviewMessage, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, msg := range m.messages {
+		if msg.ID == messageID && msg.ReviewID == reviewID && msg.UserID == userID {
+			return msg, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", model.ErrReviewMessageNotFound, messageID)
+}
+
+// ListByReviewID - 簡易実装：保存した順に返す
+func (m *MockReviewMessageRepository) ListByReviewID(ctx context.Context, userID, reviewID string) ([]*model.ReviewMessage, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	result := []*model.ReviewMessage{}
+	for _, msg := range m.messages {
+		if msg.ReviewID == reviewID && msg.UserID == userID {
+			result = append(result, msg)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockReviewMessageRepository) SetKnowledgeID(ctx context.Context, messageID, knowledgeID string) error {
+	if m.err != nil {
+		return m.err
+	}
+	for _, msg := range m.messages {
+		if msg.ID == messageID {
+			if msg.KnowledgeID != nil {
+				return model.ErrReviewMessageKnowledgeExists
+			}
+			msg.KnowledgeID = &knowledgeID
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", model.ErrReviewMessageNotFound, messageID)
+}
+
 // MockUserRepository - ユーザーリポジトリのモック
 type MockUserRepository struct {
 	users map[string]*model.User // key: auth0_user_id
@@ -344,7 +894,7 @@ func (m *MockUserRepository) FindByID(ctx context.Context, id string) (*model.Us
 			return user, nil
 		}
 	}
-	return nil, errors.New("user not found")
+	return nil, model.ErrUserNotFound
 }
 
 func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
@@ -356,7 +906,7 @@ func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*mo
 			return user, nil
 		}
 	}
-	return nil, errors.New("user not found")
+	return nil, model.ErrUserNotFound
 }
 
 func (m *MockUserRepository) FindByAuth0UserID(ctx context.Context, auth0UserID string) (*model.User, error) {
@@ -365,7 +915,7 @@ func (m *MockUserRepository) FindByAuth0UserID(ctx context.Context, auth0UserID
 	}
 	user, ok := m.users[auth0UserID]
 	if !ok {
-		return nil, errors.New("user not found")
+		return nil, model.ErrUserNotFound
 	}
 	return user, nil
 }
@@ -388,13 +938,20 @@ func (m *MockUserRepository) Delete(ctx context.Context, id string) error {
 			return nil
 		}
 	}
-	return errors.New("user not found")
+	return model.ErrUserNotFound
 }
 
-// MockClaudeClient - Claude APIクライアントのモック
+// MockClaudeClient - LLMプロバイダのモック
 type MockClaudeClient struct {
-	response *external.ReviewCodeOutput
-	err      error
+	mu         sync.Mutex
+	response   *external.ReviewCodeOutput
+	err        error
+	reviewFunc func(input external.ReviewCodeInput) (*external.ReviewCodeOutput, error)
+	lastInput  external.ReviewCodeInput
+	inputs     []external.ReviewCodeInput
+
+	converseFunc   func(input external.ConverseInput) (*external.ConverseOutput, error)
+	converseInputs []external.ConverseInput
 }
 
 func NewMockClaudeClient() *MockClaudeClient {
@@ -414,18 +971,85 @@ func (m *MockClaudeClient) SetError(err error) {
 	m.err = err
 }
 
+// SetReviewFunc - 入力ごとに異なる結果を返す場合に設定（SetResponse / SetError より優先）
+func (m *MockClaudeClient) SetReviewFunc(fn func(input external.ReviewCodeInput) (*external.ReviewCodeOutput, error)) {
+	m.reviewFunc = fn
+}
+
+func (m *MockClaudeClient) Name() string {
+	return "mock"
+}
+
+func (m *MockClaudeClient) Model() string {
+	return "mock-model"
+}
+
+// LastInput - 最後に受け取った入力を取得
+func (m *MockClaudeClient) LastInput() external.ReviewCodeInput {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastInput
+}
+
+// Inputs - 受け取った入力をすべて取得（並行呼び出しの場合は順不同）
+func (m *MockClaudeClient) Inputs() []external.ReviewCodeInput {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]external.ReviewCodeInput(nil), m.inputs...)
+}
+
 func (m *MockClaudeClient) ReviewCode(ctx context.Context, input external.ReviewCodeInput) (*external.ReviewCodeOutput, error) {
+	m.mu.Lock()
+	m.lastInput = input
+	m.inputs = append(m.inputs, input)
+	m.mu.Unlock()
+
+	if m.reviewFunc != nil {
+		return m.reviewFunc(input)
+	}
 	if m.err != nil {
 		return nil, m.err
 	}
 	return m.response, nil
 }
 
+// SetConverseFunc - 会話の返答を設定（未設定の場合は固定の返答。SetError のエラーも返す）
+func (m *MockClaudeClient) SetConverseFunc(fn func(input external.ConverseInput) (*external.ConverseOutput, error)) {
+	m.converseFunc = fn
+}
+
+// ConverseInputs - 会話で受け取った入力をすべて取得
+func (m *MockClaudeClient) ConverseInputs() []external.ConverseInput {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]external.ConverseInput(nil), m.converseInputs...)
+}
+
+func (m *MockClaudeClient) Converse(ctx context.Context, input external.ConverseInput) (*external.ConverseOutput, error) {
+	m.mu.Lock()
+	m.converseInputs = append(m.converseInputs, input)
+	m.mu.Unlock()
+
+	if m.converseFunc != nil {
+		return m.converseFunc(input)
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &external.ConverseOutput{
+		Reply:      "Mock reply",
+		TokensUsed: 50,
+		Provider:   "mock",
+		Model:      "mock-model",
+	}, nil
+}
+
 // MockEmbeddingClient - Embedding APIクライアントのモック
 type MockEmbeddingClient struct {
-	embedding  []float32
-	embeddings [][]float32
-	err        error
+	embedding     []float32
+	embeddings    [][]float32
+	err           error
+	tokensPerText int
 }
 
 func NewMockEmbeddingClient() *MockEmbeddingClient {
@@ -451,10 +1075,16 @@ func (m *MockEmbeddingClient) SetError(err error) {
 	m.err = err
 }
 
+// SetTokensPerText - 1テキストあたりの消費トークン数（使用量の記録を確認する場合に設定）
+func (m *MockEmbeddingClient) SetTokensPerText(tokens int) {
+	m.tokensPerText = tokens
+}
+
 func (m *MockEmbeddingClient) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	external.RecordEmbeddingUsage(ctx, "mock-embedding", m.tokensPerText)
 	return m.embedding, nil
 }
 
@@ -462,6 +1092,7 @@ func (m *MockEmbeddingClient) GenerateEmbeddings(ctx context.Context, texts []st
 	if m.err != nil {
 		return nil, m.err
 	}
+	external.RecordEmbeddingUsage(ctx, "mock-embedding", m.tokensPerText*len(texts))
 	if m.embeddings != nil {
 		return m.embeddings, nil
 	}
@@ -472,3 +1103,163 @@ func (m *MockEmbeddingClient) GenerateEmbeddings(ctx context.Context, texts []st
 	}
 	return result, nil
 }
+
+// MockPromptTemplateRepository - プロンプトテンプレートリポジトリのモック
+type MockPromptTemplateRepository struct {
+	templates []*model.PromptTemplate
+	err       error
+}
+
+func NewMockPromptTemplateRepository() *MockPromptTemplateRepository {
+	return &MockPromptTemplateRepository{
+		templates: make([]*model.PromptTemplate, 0),
+	}
+}
+
+func (m *MockPromptTemplateRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockPromptTemplateRepository) Create(ctx context.Context, template *model.PromptTemplate) error {
+	if m.err != nil {
+		return m.err
+	}
+	version := 0
+	for _, t := range m.templates {
+		if t.Name == template.Name && t.Version > version {
+			version = t.Version
+		}
+	}
+	template.Version = version + 1
+	template.IsActive = false
+	m.templates = append(m.templates, template)
+	return nil
+}
+
+func (m *MockPromptTemplateRepository) FindByID(ctx context.Context, id string) (*model.PromptTemplate, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, t := range m.templates {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", model.ErrPromptTemplateNotFound, id)
+}
+
+func (m *MockPromptTemplateRepository) FindActive(ctx context.Context, name string) (*model.PromptTemplate, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, t := range m.templates {
+		if t.Name == name && t.IsActive {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", model.ErrPromptTemplateNotFound, name)
+}
+
+func (m *MockPromptTemplateRepository) ListByName(ctx context.Context, name string) ([]*model.PromptTemplate, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	result := make([]*model.PromptTemplate, 0)
+	for i := len(m.templates) - 1; i >= 0; i-- {
+		if m.templates[i].Name == name {
+			result = append(result, m.templates[i])
+		}
+	}
+	return result, nil
+}
+
+func (m *MockPromptTemplateRepository) Activate(ctx context.Context, id string) (*model.PromptTemplate, error) {
+	target, err := m.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, t := range m.templates {
+		if t.Name == target.Name {
+			t.IsActive = t.ID == id
+		}
+	}
+	target.ActivatedAt = &now
+	return target, nil
+}
+
+// MockQuotaRepository - ユーザー個別の利用上限リポジトリのモック
+type MockQuotaRepository struct {
+	quotas map[string]*model.UserQuota
+	err    error
+}
+
+func NewMockQuotaRepository() *MockQuotaRepository {
+	return &MockQuotaRepository{
+		quotas: make(map[string]*model.UserQuota),
+	}
+}
+
+func (m *MockQuotaRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockQuotaRepository) FindByUserID(ctx context.Context, userID string) (*model.UserQuota, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if quota, ok := m.quotas[userID]; ok {
+		return quota, nil
+	}
+	return nil, fmt.Errorf("%w: %s", model.ErrUserQuotaNotFound, userID)
+}
+
+func (m *MockQuotaRepository) Upsert(ctx context.Context, quota *model.UserQuota) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.quotas[quota.UserID] = quota
+	return nil
+}
+
+func (m *MockQuotaRepository) Delete(ctx context.Context, userID string) error {
+	if m.err != nil {
+		return m.err
+	}
+	delete(m.quotas, userID)
+	return nil
+}
+
+// MockQuotaCounter - 消費量カウンターのモック（期間の切り替わりは考慮しない）
+type MockQuotaCounter struct {
+	mu    sync.Mutex
+	usage map[string]model.QuotaUsage
+}
+
+func NewMockQuotaCounter() *MockQuotaCounter {
+	return &MockQuotaCounter{
+		usage: make(map[string]model.QuotaUsage),
+	}
+}
+
+func (m *MockQuotaCounter) SetUsage(userID string, usage model.QuotaUsage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage[userID] = usage
+}
+
+func (m *MockQuotaCounter) Usage(ctx context.Context, userID string, now time.Time) (model.QuotaUsage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.usage[userID], nil
+}
+
+func (m *MockQuotaCounter) Add(ctx context.Context, userID string, now time.Time, reviews, tokens int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	usage := m.usage[userID]
+	usage.ReviewsToday += reviews
+	usage.TokensThisMonth += tokens
+	m.usage[userID] = usage
+	return nil
+}
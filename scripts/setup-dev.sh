@@ -106,8 +106,11 @@ echo ""
 echo "🗄️  Step 6: Database Migrations"
 if [ -f backend/migrations/001_init.sql ]; then
     print_info "Running migrations..."
-    PGPASSWORD=dev_password psql -h localhost -p 5432 -U dev_user -d reviewapp -f backend/migrations/001_init.sql > /dev/null 2>&1
-    if [ $? -eq 0 ]; then
+    migration_ok=true
+    for f in backend/migrations/*.sql; do
+        PGPASSWORD=dev_password psql -h localhost -p 5432 -U dev_user -d reviewapp -f "$f" > /dev/null 2>&1 || migration_ok=false
+    done
+    if [ "$migration_ok" = true ]; then
         print_success "Migrations completed"
     else
         print_warning "Migrations may have already been applied"